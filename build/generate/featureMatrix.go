@@ -38,7 +38,12 @@ func generateFeatureMatrix() error {
 			{"SRV", "Driver has explicitly implemented SRV record management"},
 			{"SSHFP", "Provider can manage SSHFP records"},
 			{"TLSA", "Provider can manage TLSA records"},
+			{"HTTPS", "Provider can manage HTTPS records"},
+			{"SVCB", "Provider can manage SVCB records"},
+			{"LOC", "Provider can manage LOC records"},
 			{"TXTMulti", "Provider can manage TXT records with multiple strings"},
+			{"URI", "Provider can manage URI records"},
+			{"RFC3597", "Provider can manage records of unknown types (TYPEnnn) in the generic format of RFC 3597"},
 			{"R53_ALIAS", "Provider supports Route 53 limited ALIAS"},
 			{"AZURE_ALIAS", "Provider supports Azure DNS limited ALIAS"},
 			{"DS", "Provider supports adding DS records"},
@@ -48,6 +53,7 @@ func generateFeatureMatrix() error {
 			{"create-domains", "This means the provider can automatically create domains that do not currently exist on your account. The 'dnscontrol create-domains' command will initialize any missing domains"},
 			{"no_purge", "indicates you can use NO_PURGE macro to prevent deleting records not managed by dnscontrol. A few providers that generate the entire zone from scratch have a problem implementing this."},
 			{"get-zones", "indicates the dnscontrol get-zones subcommand is implemented."},
+			{"concurrent corrections", "indicates that push --concurrency runs the corrections of a zone in parallel."},
 		},
 	}
 	for _, p := range providerTypes {
@@ -84,14 +90,21 @@ func generateFeatureMatrix() error {
 		setCap("AZURE_ALIAS", providers.CanUseAzureAlias)
 		setCap("CAA", providers.CanUseCAA)
 		setCap("DS", providers.CanUseDS)
+		setCap("HTTPS", providers.CanUseHTTPS)
+		setCap("LOC", providers.CanUseLOC)
 		setCap("NAPTR", providers.CanUseNAPTR)
 		setCap("PTR", providers.CanUsePTR)
 		setCap("R53_ALIAS", providers.CanUseRoute53Alias)
+		setCap("RFC3597", providers.CanUseRFC3597)
 		setCap("SOA", providers.CanUseSOA)
 		setCap("SRV", providers.CanUseSRV)
 		setCap("SSHFP", providers.CanUseSSHFP)
+		setCap("SVCB", providers.CanUseSVCB)
 		setCap("TLSA", providers.CanUseTLSA)
+		setCap("TXTMulti", providers.CanUseTXTMulti)
+		setCap("URI", providers.CanUseURI)
 		setCap("get-zones", providers.CanGetZones)
+		setCap("concurrent corrections", providers.CanConcurrentlyModify)
 		setDoc("create-domains", providers.DocCreateDomains, true)
 		setDoc("dual host", providers.DocDualHost, false)
 
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/apistats"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/previewcache"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+// --cache-dir lets consecutive previews (for example in the jobs of a
+// CI pipeline) skip downloading the zones that haven't changed. It only
+// applies to the DNS providers that implement providers.ZoneVersioner:
+// the cache key is made of the version of the zone at the provider and
+// of its configuration in dnsconfig.js, so that a change on either side
+// computes the corrections again.
+//
+// push, which changes the zones, clears the cache. So does --no-cache,
+// which then fills it again.
+
+// openCache returns the cache of args, or nil if there is none.
+func openCache(args PreviewArgs, push bool, out printer.CLI) *previewcache.Cache {
+	if args.CacheDir == "" {
+		return nil
+	}
+	c := previewcache.New(args.CacheDir)
+	if push || args.NoCache {
+		if err := c.Clear(); err != nil {
+			out.Warnf("Can't clear the cache: %s\n", err)
+		}
+	}
+	if push {
+		return nil
+	}
+	return c
+}
+
+// getDomainCorrections returns the corrections of dc at provider,
+// from the cache if they were computed for the same version of the
+// zone. Corrections from the cache can only be printed: their F is nil.
+func getDomainCorrections(cache *previewcache.Cache, provider *models.DNSProviderInstance, dc *models.DomainConfig) ([]*models.Correction, error) {
+	versioner, ok := provider.Driver.(providers.ZoneVersioner)
+	if cache == nil || !ok {
+		return provider.Driver.GetDomainCorrections(dc)
+	}
+	zoneVersion, err := versioner.GetZoneVersion(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	// The key must be computed before GetDomainCorrections, which may
+	// change dc.
+	key, err := previewcache.Key(version, provider.Name, provider.ProviderType, zoneVersion, diff2.EnableDiff2, dc)
+	if err != nil {
+		return nil, err
+	}
+	if msgs, ok := cache.Get(provider.Name, dc.Name, key); ok {
+		apistats.CacheHit()
+		corrections := make([]*models.Correction, len(msgs))
+		for i, msg := range msgs {
+			corrections[i] = &models.Correction{Msg: msg}
+		}
+		return corrections, nil
+	}
+
+	corrections, err := provider.Driver.GetDomainCorrections(dc)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]string, len(corrections))
+	for i, c := range corrections {
+		msgs[i] = c.Msg
+	}
+	if err := cache.Put(provider.Name, dc.Name, key, msgs); err != nil {
+		printer.Warnf("Can't save %s at %s in the cache: %s\n", dc.Name, provider.Name, err)
+	}
+	return corrections, nil
+}
@@ -0,0 +1,279 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/urfave/cli/v2"
+)
+
+// check-creds foo bar
+// is the same as
+// get-zones --format=nameonly foo bar all
+//
+// With --probe or --json, each provider is probed instead: see
+// probeCreds.
+var _ = cmd(catUtils, func() *cli.Command {
+	var args CheckCredsArgs
+	return &cli.Command{
+		Name:  "check-creds",
+		Usage: "Do a small operation to verify credentials (stand-alone)",
+		Action: func(ctx *cli.Context) error {
+			if args.Probe || args.JSON {
+				args.CredNames = ctx.Args().Slice()
+				return exit(CheckCreds(args))
+			}
+			var arg0, arg1 string
+			// This takes one or two command-line args.
+			// Starting in v3.16: Using it with 2 args will generate a warning.
+			// Starting in v4.0: Using it with 2 args might be an error.
+			if ctx.NArg() == 1 {
+				arg0 = ctx.Args().Get(0)
+				arg1 = ""
+			} else if ctx.NArg() == 2 {
+				arg0 = ctx.Args().Get(0)
+				arg1 = ctx.Args().Get(1)
+			} else {
+				return cli.Exit("Arguments should be: credskey [providername] (Ex: r53 ROUTE53)", 1)
+			}
+			args.CredName = arg0
+			args.ProviderName = arg1
+			args.ZoneNames = []string{"all"}
+			args.OutputFormat = "nameonly"
+			return exit(GetZone(args.GetZoneArgs))
+		},
+		Flags:     args.flags(),
+		UsageText: "dnscontrol check-creds [command options] credkey provider",
+		Description: `Do a trivia operation to verify credentials.  This is a stand-alone utility.
+
+If successful, a list of zones will be output. If not, hopefully you
+see verbose error messages.
+
+With --probe, each credkey (or every entry of creds.json if none is
+given) is probed without changing anything: the provider is created
+(which checks the credentials with most providers), its zones are
+listed and one zone is read. The latency of each step and the headroom
+under the provider's rate limit are reported. --json outputs the same
+report as JSON, for monitoring.
+
+ARGUMENTS:
+   credkey:  The name used in creds.json (first parameter to NewDnsProvider() in dnsconfig.js)
+   provider: The name of the provider (second parameter to NewDnsProvider() in dnsconfig.js)
+
+EXAMPLES:
+   dnscontrol check-creds myr53 ROUTE53      # Pre v3.16, or pre-v4.0 for backwards-compatibility
+   dnscontrol check-creds myr53
+   dnscontrol check-creds --out=/dev/null myr53 && echo Success
+   dnscontrol check-creds --probe myr53 mycloudflare
+   dnscontrol check-creds --json --out=creds-status.json`,
+	}
+}())
+
+// CheckCredsArgs args required for the check-creds subcommand.
+type CheckCredsArgs struct {
+	GetZoneArgs
+	Probe     bool     // Probe the providers rather than list the zones
+	JSON      bool     // Output the probes as JSON (implies Probe)
+	ProbeZone string   // The zone to read; default is the first one listed
+	CredNames []string // The keys in creds.json to probe; default is all
+}
+
+func (args *CheckCredsArgs) flags() []cli.Flag {
+	flags := args.GetZoneArgs.flags()
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "probe",
+		Destination: &args.Probe,
+		Usage:       `Probe each provider (create it, list its zones, read one) and report the latencies`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "json",
+		Destination: &args.JSON,
+		Usage:       `Output the probes as JSON (implies --probe)`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "zone",
+		Destination: &args.ProbeZone,
+		Usage:       `With --probe, the zone to read (default: the first zone listed)`,
+	})
+	return flags
+}
+
+// credsProbe is the result of probing the provider of one creds.json
+// entry.
+type credsProbe struct {
+	Name      string         `json:"name"`
+	Type      string         `json:"type"`
+	OK        bool           `json:"ok"`
+	Steps     []probeStep    `json:"steps"`
+	RateLimit *rateLimitInfo `json:"rate_limit,omitempty"`
+}
+
+// probeStep is one operation of a probe.
+type probeStep struct {
+	Step      string `json:"step"`   // "auth", "list-zones" or "read-zone"
+	Status    string `json:"status"` // "ok", "failed" or "skipped"
+	LatencyMS int64  `json:"latency_ms"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// rateLimitInfo describes the rate limit of a providers.RateLimiter.
+type rateLimitInfo struct {
+	PerSecond float64 `json:"per_second"`
+	Burst     int     `json:"burst"`
+	Available float64 `json:"available"` // Requests that may be made at once after the probe.
+}
+
+// CheckCreds probes the providers of the creds.json entries, and
+// returns an error if any of them failed.
+func CheckCreds(args CheckCredsArgs) error {
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return fmt.Errorf("failed CheckCreds LoadProviderConfigs(%q): %w", args.CredsFile, err)
+	}
+	names := args.CredNames
+	if len(names) == 0 {
+		for name := range providerConfigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var probes []credsProbe
+	failed := 0
+	for _, name := range names {
+		cfg, ok := providerConfigs[name]
+		var p credsProbe
+		if ok {
+			p = probeCreds(name, cfg, args.ProbeZone)
+		} else {
+			p = credsProbe{Name: name, Steps: []probeStep{{Step: "auth", Status: "failed", Detail: fmt.Sprintf("no entry %q in %s", name, args.CredsFile)}}}
+		}
+		if !p.OK {
+			failed++
+		}
+		probes = append(probes, p)
+	}
+
+	w := os.Stdout
+	if args.OutputFile != "" {
+		w, err = os.Create(args.OutputFile)
+		if err != nil {
+			return fmt.Errorf("failed CheckCreds Create(%q): %w", args.OutputFile, err)
+		}
+		defer w.Close()
+	}
+	if args.JSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(probes); err != nil {
+			return err
+		}
+	} else {
+		printProbes(w, probes)
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d of %d credentials failed", failed, len(probes))
+	}
+	return nil
+}
+
+// probeCreds probes the provider of a creds.json entry without changing
+// anything: it creates the provider, lists its zones and reads one.
+// Entries whose TYPE isn't a DNS provider (registrars) are only
+// reported as skipped.
+func probeCreds(name string, cfg map[string]string, zone string) credsProbe {
+	p := credsProbe{Name: name, Type: cfg["TYPE"]}
+	step := func(s string, f func() (string, error)) bool {
+		start := time.Now()
+		detail, err := f()
+		ps := probeStep{Step: s, Status: "ok", LatencyMS: time.Since(start).Milliseconds(), Detail: detail}
+		if err != nil {
+			ps.Status, ps.Detail = "failed", err.Error()
+		}
+		p.Steps = append(p.Steps, ps)
+		return err == nil
+	}
+	skip := func(s, detail string) {
+		p.Steps = append(p.Steps, probeStep{Step: s, Status: "skipped", Detail: detail})
+	}
+
+	if _, ok := providers.DNSProviderTypes[p.Type]; !ok {
+		p.OK = p.Type != ""
+		if p.OK {
+			skip("auth", p.Type+" is not a DNS provider")
+		} else {
+			p.Steps = append(p.Steps, probeStep{Step: "auth", Status: "failed", Detail: "the entry has no TYPE"})
+		}
+		return p
+	}
+
+	var provider providers.DNSServiceProvider
+	if !step("auth", func() (string, error) {
+		var err error
+		provider, err = providers.CreateDNSProvider(p.Type, cfg, nil)
+		return "", err
+	}) {
+		return p
+	}
+	if rl, ok := provider.(providers.RateLimiter); ok && rl.RateLimit() != nil {
+		defer func() {
+			l := rl.RateLimit()
+			p.RateLimit = &rateLimitInfo{PerSecond: l.PerSecond, Burst: l.Burst, Available: l.Available()}
+		}()
+	}
+
+	p.OK = true
+	if lister, ok := provider.(providers.ZoneLister); ok {
+		p.OK = step("list-zones", func() (string, error) {
+			zones, err := lister.ListZones()
+			if err != nil {
+				return "", err
+			}
+			if zone == "" && len(zones) != 0 {
+				sort.Strings(zones)
+				zone = zones[0]
+			}
+			return fmt.Sprintf("%d zones", len(zones)), nil
+		})
+	} else {
+		skip("list-zones", "not supported by "+p.Type)
+	}
+
+	switch {
+	case !p.OK:
+	case zone == "":
+		skip("read-zone", "no zone to read (see --zone)")
+	default:
+		p.OK = step("read-zone", func() (string, error) {
+			records, err := provider.GetZoneRecords(zone)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", zone, err)
+			}
+			return fmt.Sprintf("%s: %d records", zone, len(records)), nil
+		})
+	}
+	return p
+}
+
+// printProbes writes the probes as text.
+func printProbes(w io.Writer, probes []credsProbe) {
+	for _, p := range probes {
+		status := "OK"
+		if !p.OK {
+			status = "FAILED"
+		}
+		fmt.Fprintf(w, "%s (%s): %s\n", p.Name, p.Type, status)
+		for _, s := range p.Steps {
+			fmt.Fprintf(w, "  %-11s %-8s %6dms  %s\n", s.Step, s.Status, s.LatencyMS, s.Detail)
+		}
+		if rl := p.RateLimit; rl != nil {
+			fmt.Fprintf(w, "  %-11s %g/s, burst %d, %.1f available\n", "rate-limit", rl.PerSecond, rl.Burst, rl.Available)
+		}
+	}
+}
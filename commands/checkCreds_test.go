@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/StackExchange/dnscontrol/v3/providers/bind"
+)
+
+func TestProbeCreds(t *testing.T) {
+	dir := t.TempDir()
+	zone := "$TTL 300\n@ IN A 1.2.3.4\nwww IN CNAME @\n"
+	if err := os.WriteFile(filepath.Join(dir, "example.com.zone"), []byte(zone), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := func(p credsProbe) string {
+		s := ""
+		for _, st := range p.Steps {
+			s += st.Step + "=" + st.Status + ":" + st.Detail + " "
+		}
+		return s
+	}
+	tests := []struct {
+		name string
+		cfg  map[string]string
+		zone string
+		ok   bool
+		want string
+	}{
+		{"bind", map[string]string{"TYPE": "BIND", "directory": dir}, "", true,
+			"auth=ok: list-zones=ok:1 zones read-zone=ok:example.com: 2 records "},
+		{"bind", map[string]string{"TYPE": "BIND", "directory": filepath.Join(dir, "missing")}, "", false,
+			"auth=ok: list-zones=failed:directory \"" + filepath.Join(dir, "missing") + "\" does not exist "},
+		{"reg", map[string]string{"TYPE": "NONE"}, "", true,
+			"auth=skipped:NONE is not a DNS provider "},
+		{"old", map[string]string{"apikey": "x"}, "", false,
+			"auth=failed:the entry has no TYPE "},
+	}
+	for _, tt := range tests {
+		p := probeCreds(tt.name, tt.cfg, tt.zone)
+		if p.OK != tt.ok || steps(p) != tt.want {
+			t.Errorf("probeCreds(%q, %v) = %v %q, want %v %q", tt.name, tt.cfg, p.OK, steps(p), tt.ok, tt.want)
+		}
+	}
+}
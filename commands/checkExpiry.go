@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/urfave/cli/v2"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args CheckExpiryArgs
+	return &cli.Command{
+		Name:  "check-expiry",
+		Usage: "list when the domains in dnsconfig.js expire at their registrars, and warn about those to renew",
+		Action: func(ctx *cli.Context) error {
+			return exit(CheckExpiry(args))
+		},
+		Flags: args.flags(),
+		Description: `Ask the registrar of each domain in dnsconfig.js when the domain
+expires. Only some registrars can tell (HEXONET, HOSTINGDE and
+NAMECHEAP); the domains of the others are listed as unsupported.
+Domains with the NONE registrar are skipped.
+
+The STATUS column is one of:
+   ok           expires in more than --days days
+   renew        expires within --days days
+   expired      the expiration date is past
+   unsupported  the registrar can't report the expiration date
+   error        the registrar failed (the error is printed)
+
+The command fails if any domain must be renewed, has expired, or if a
+registrar failed, so that it can run in a scheduled CI job.
+
+EXAMPLES:
+   dnscontrol check-expiry
+   dnscontrol check-expiry --days 60 --domains example.com,example.net
+   dnscontrol check-expiry --format=json`,
+	}
+}())
+
+// CheckExpiryArgs contains all data/flags needed to run check-expiry, independently of CLI.
+type CheckExpiryArgs struct {
+	GetDNSConfigArgs
+	GetCredentialsArgs
+	Domains      string // Comma separated, or all the domains if empty.
+	Days         int    // Warn about the domains that expire within this many days.
+	OutputFormat string // table or json
+}
+
+func (args *CheckExpiryArgs) flags() []cli.Flag {
+	flags := args.GetDNSConfigArgs.flags()
+	flags = append(flags, args.GetCredentialsArgs.flags()...)
+	flags = append(flags, &cli.StringFlag{
+		Name:        "domains",
+		Destination: &args.Domains,
+		Usage:       `Comma separated list of domain names to include`,
+	})
+	flags = append(flags, &cli.IntFlag{
+		Name:        "days",
+		Destination: &args.Days,
+		Value:       30,
+		Usage:       `Warn about the domains that expire within this many days`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "format",
+		Destination: &args.OutputFormat,
+		Value:       "table",
+		Usage:       `Output format: table json`,
+	})
+	return flags
+}
+
+// The status of a domain in check-expiry.
+const (
+	expiryOK          = "ok"
+	expiryRenew       = "renew"
+	expiryExpired     = "expired"
+	expiryUnsupported = "unsupported"
+	expiryError       = "error"
+)
+
+// domainExpiry is the expiration of a domain at its registrar.
+type domainExpiry struct {
+	Domain    string     `json:"domain"`
+	Registrar string     `json:"registrar"` // The name in creds.json
+	Type      string     `json:"type"`
+	Expires   *time.Time `json:"expires,omitempty"`
+	DaysLeft  *int       `json:"days_left,omitempty"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// CheckExpiry implements the check-expiry subcommand.
+func CheckExpiry(args CheckExpiryArgs) error {
+	if args.OutputFormat != "table" && args.OutputFormat != "json" {
+		return fmt.Errorf("--format must be table or json, not %q", args.OutputFormat)
+	}
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return err
+	}
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return err
+	}
+	msgs, err := populateProviderTypes(cfg, providerConfigs)
+	if len(msgs) != 0 {
+		fmt.Fprintln(os.Stderr, strings.Join(msgs, "\n"))
+	}
+	if err != nil {
+		return err
+	}
+
+	filter := FilterArgs{Domains: args.Domains}
+	registrars := map[string]providers.Registrar{}
+	now := time.Now()
+	var results []domainExpiry
+	seen := map[string]bool{} // A domain is listed once, even with several views.
+	for _, dc := range cfg.Domains {
+		if !filter.shouldRunDomain(dc) || seen[dc.Name] {
+			continue
+		}
+		seen[dc.Name] = true
+		rCfg := cfg.RegistrarsByName[dc.RegistrarName]
+		if rCfg.Type == "NONE" {
+			continue
+		}
+		r := domainExpiry{Domain: dc.Name, Registrar: rCfg.Name, Type: rCfg.Type}
+		registrar, ok := registrars[rCfg.Name]
+		if !ok {
+			registrar, err = providers.CreateRegistrar(rCfg.Type, providerConfigs[rCfg.Name])
+			if err != nil {
+				return fmt.Errorf("%s: %w", rCfg.Name, err)
+			}
+			registrars[rCfg.Name] = registrar
+		}
+		getter, ok := registrar.(providers.RegistrarExpiryGetter)
+		if !ok {
+			r.Status = expiryUnsupported
+			results = append(results, r)
+			continue
+		}
+		expires, err := getter.GetDomainExpiry(dc.Name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: %s at %s: %s\n", dc.Name, rCfg.Name, err)
+			r.Status, r.Error = expiryError, err.Error()
+			results = append(results, r)
+			continue
+		}
+		left, status := expiryStatus(expires, now, args.Days)
+		r.Expires, r.DaysLeft, r.Status = &expires, &left, status
+		results = append(results, r)
+	}
+	sortExpiries(results)
+
+	if err := writeExpiries(os.Stdout, results, args.OutputFormat); err != nil {
+		return err
+	}
+	var renew, failed int
+	for _, r := range results {
+		switch r.Status {
+		case expiryRenew, expiryExpired:
+			renew++
+		case expiryError:
+			failed++
+		}
+	}
+	if renew != 0 {
+		return fmt.Errorf("%d domains expire within %d days", renew, args.Days)
+	}
+	if failed != 0 {
+		return fmt.Errorf("completed with errors")
+	}
+	return nil
+}
+
+// expiryStatus returns the number of whole days until expires and the
+// status of a domain that must be renewed within days.
+func expiryStatus(expires, now time.Time, days int) (left int, status string) {
+	d := expires.Sub(now)
+	left = int(d / (24 * time.Hour))
+	switch {
+	case d <= 0:
+		return left, expiryExpired
+	case left < days:
+		return left, expiryRenew
+	default:
+		return left, expiryOK
+	}
+}
+
+// sortExpiries sorts the domains by expiration date, the domains
+// whose date is unknown last.
+func sortExpiries(results []domainExpiry) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Expires, results[j].Expires
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Before(*b)
+	})
+}
+
+func writeExpiries(w io.Writer, results []domainExpiry, format string) error {
+	if format == "json" {
+		if results == nil {
+			results = []domainExpiry{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "DOMAIN\tREGISTRAR\tTYPE\tEXPIRES\tDAYS\tSTATUS")
+	for _, r := range results {
+		expires, left := "-", "-"
+		if r.Expires != nil {
+			expires = r.Expires.UTC().Format("2006-01-02")
+			left = fmt.Sprint(*r.DaysLeft)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Domain, r.Registrar, r.Type, expires, left, r.Status)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestExpiryStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expires time.Time
+		left    int
+		status  string
+	}{
+		{now.AddDate(1, 0, 0), 366, expiryOK},
+		{now.AddDate(0, 0, 30), 30, expiryOK},
+		{now.AddDate(0, 0, 29).Add(time.Hour), 29, expiryRenew},
+		{now.Add(time.Hour), 0, expiryRenew},
+		{now.Add(-time.Hour), 0, expiryExpired},
+		{now.AddDate(0, 0, -3), -3, expiryExpired},
+	}
+	for _, tt := range tests {
+		left, status := expiryStatus(tt.expires, now, 30)
+		if left != tt.left || status != tt.status {
+			t.Errorf("expiryStatus(%s) = %d, %s; want %d, %s", tt.expires, left, status, tt.left, tt.status)
+		}
+	}
+}
+
+func TestWriteExpiries(t *testing.T) {
+	soon := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	left1, left2 := 19, 517
+	results := []domainExpiry{
+		{Domain: "example.org", Registrar: "r53", Type: "ROUTE53", Status: expiryUnsupported},
+		{Domain: "example.net", Registrar: "hx", Type: "HEXONET", Expires: &later, DaysLeft: &left2, Status: expiryOK},
+		{Domain: "example.com", Registrar: "hx", Type: "HEXONET", Expires: &soon, DaysLeft: &left1, Status: expiryRenew},
+	}
+	sortExpiries(results)
+	var buf bytes.Buffer
+	if err := writeExpiries(&buf, results, "table"); err != nil {
+		t.Fatal(err)
+	}
+	want := `DOMAIN       REGISTRAR  TYPE     EXPIRES     DAYS  STATUS
+example.com  hx         HEXONET  2024-01-20  19    renew
+example.net  hx         HEXONET  2025-06-01  517   ok
+example.org  r53        ROUTE53  -           -     unsupported
+`
+	if buf.String() != want {
+		t.Errorf("table:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -53,17 +53,60 @@ func Run(v string) int {
 			Usage:       "Enable detailed logging",
 			Destination: &printer.DefaultPrinter.Verbose,
 		},
+		&cli.StringSliceFlag{
+			Name:  "vmodule",
+			Usage: `Set the log level of a module, e.g. "providers.cloudflare=debug" (debug, info, warn or error)`,
+		},
+		&cli.StringFlag{
+			Name:  "log-format",
+			Value: "text",
+			Usage: `Format of the log messages: "text" or "json" (one object per line)`,
+		},
 		&cli.BoolFlag{
 			Name:        "allow-fetch",
 			Usage:       "Enable JS fetch(), dangerous on untrusted code!",
 			Destination: &js.EnableFetch,
 		},
+		&cli.StringSliceFlag{
+			Name:  "fetch-allow",
+			Usage: `Enable JS fetch() of these URL prefixes or hosts only, e.g. "https://ipam.example.com/api/"`,
+		},
+		&cli.StringFlag{
+			Name:        "fetch-cache",
+			Usage:       "Keep the responses of JS fetch() in this directory",
+			Destination: &js.FetchCacheDir,
+		},
+		&cli.DurationFlag{
+			Name:        "fetch-cache-ttl",
+			Value:       js.FetchCacheTTL,
+			Usage:       "Use the responses in --fetch-cache for this long before fetching again",
+			Destination: &js.FetchCacheTTL,
+		},
 		&cli.BoolFlag{
 			Name:        "diff2",
 			Usage:       "Enable replacement diff algorithm",
 			Destination: &diff2.EnableDiff2,
 		},
 	}
+	app.Flags = append(app.Flags, telemetryFlags()...)
+	app.Before = func(ctx *cli.Context) error {
+		switch ctx.String("log-format") {
+		case "text":
+		case "json":
+			printer.DefaultPrinter.JSON = true
+		default:
+			return cli.Exit(fmt.Sprintf("unknown --log-format %q (valid: text, json)", ctx.String("log-format")), 1)
+		}
+		if err := printer.SetModuleLevels(ctx.StringSlice("vmodule")); err != nil {
+			return cli.Exit(err, 1)
+		}
+		js.FetchAllow = ctx.StringSlice("fetch-allow")
+		if err := startTelemetry(ctx); err != nil {
+			return cli.Exit(err, 1)
+		}
+		return nil
+	}
+	app.After = stopTelemetry
 	sort.Sort(cli.CommandsByName(commands))
 	app.Commands = commands
 	app.EnableBashCompletion = true
@@ -251,6 +294,17 @@ func (args *GetCredentialsArgs) flags() []cli.Flag {
 type FilterArgs struct {
 	Providers string
 	Domains   string
+	// ProviderType limits the run to the domains that use a provider
+	// of this type, and to those providers.
+	ProviderType string
+	// ModifiedSince is a git revision. Only the domains whose
+	// configuration changed since then are run (see modifiedDomains).
+	ModifiedSince string
+	modified      map[string]bool // nil unless ModifiedSince is set.
+	// Only and Skip are comma separated kinds of corrections (see
+	// models.CorrectionKinds) to make, or not to make.
+	Only string
+	Skip string
 }
 
 func (args *FilterArgs) flags() []cli.Flag {
@@ -267,10 +321,33 @@ func (args *FilterArgs) flags() []cli.Flag {
 			Usage:       `Comma separated list of domain names to include`,
 			Value:       "",
 		},
+		&cli.StringFlag{
+			Name:        "provider",
+			Destination: &args.ProviderType,
+			Usage:       `Only the domains that use a DNS provider or registrar of this type (e.g. CLOUDFLAREAPI), and only those providers`,
+		},
+		&cli.StringFlag{
+			Name:        "modified-since",
+			Destination: &args.ModifiedSince,
+			Usage:       `Only the domains whose configuration changed since this git revision (e.g. origin/main)`,
+		},
+		&cli.StringFlag{
+			Name:        "only",
+			Destination: &args.Only,
+			Usage:       `Only make the corrections of these kinds (comma separated list of info, create, modify, destroy)`,
+		},
+		&cli.StringFlag{
+			Name:        "skip",
+			Destination: &args.Skip,
+			Usage:       `Don't make the corrections of these kinds (comma separated list of info, create, modify, destroy)`,
+		},
 	}
 }
 
 func (args *FilterArgs) shouldRunProvider(name string, dc *models.DomainConfig) bool {
+	if args.ProviderType != "" && instanceType(name, dc) != args.ProviderType {
+		return false
+	}
 	if args.Providers == "all" {
 		return true
 	}
@@ -290,14 +367,112 @@ func (args *FilterArgs) shouldRunProvider(name string, dc *models.DomainConfig)
 	return false
 }
 
-func (args *FilterArgs) shouldRunDomain(d string) bool {
+func (args *FilterArgs) shouldRunDomain(dc *models.DomainConfig) bool {
+	if args.modified != nil && !args.modified[dc.UniqueName] {
+		return false
+	}
+	if args.ProviderType != "" && !usesProviderType(dc, args.ProviderType) {
+		return false
+	}
 	if args.Domains == "" {
 		return true
 	}
 	for _, dom := range strings.Split(args.Domains, ",") {
-		if dom == d {
+		if dom == dc.UniqueName {
 			return true
 		}
 	}
 	return false
 }
+
+// instanceType returns the type of the DNS provider or registrar of dc
+// called name.
+func instanceType(name string, dc *models.DomainConfig) string {
+	for _, pri := range dc.DNSProviderInstances {
+		if pri.Name == name {
+			return pri.ProviderType
+		}
+	}
+	if dc.RegistrarInstance != nil && dc.RegistrarInstance.Name == name {
+		return dc.RegistrarInstance.ProviderType
+	}
+	return ""
+}
+
+// usesProviderType tells whether a DNS provider or the registrar of dc
+// is of type pType.
+func usesProviderType(dc *models.DomainConfig, pType string) bool {
+	for _, pri := range dc.DNSProviderInstances {
+		if pri.ProviderType == pType {
+			return true
+		}
+	}
+	return dc.RegistrarInstance != nil && dc.RegistrarInstance.ProviderType == pType
+}
+
+// checkKinds returns an error if --only or --skip names an unknown
+// kind of correction.
+func (args *FilterArgs) checkKinds() error {
+	if _, err := parseKinds(args.Only); err != nil {
+		return fmt.Errorf("--only: %w", err)
+	}
+	if _, err := parseKinds(args.Skip); err != nil {
+		return fmt.Errorf("--skip: %w", err)
+	}
+	return nil
+}
+
+func parseKinds(list string) (map[models.CorrectionKind]bool, error) {
+	if list == "" {
+		return nil, nil
+	}
+	kinds := map[models.CorrectionKind]bool{}
+	for _, name := range strings.Split(list, ",") {
+		k, err := models.ParseCorrectionKind(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		kinds[k] = true
+	}
+	return kinds, nil
+}
+
+// filterCorrections returns the corrections whose kind --only and
+// --skip allow, and the number of the others. The informational
+// corrections that come before a correction (see models.Correction)
+// describe what it does, so they are kept or skipped together, as a
+// group of the most destructive kind among them.
+func (args *FilterArgs) filterCorrections(corrections []*models.Correction) (kept []*models.Correction, skipped int, err error) {
+	if args.Only == "" && args.Skip == "" {
+		return corrections, 0, nil
+	}
+	only, err := parseKinds(args.Only)
+	if err != nil {
+		return nil, 0, fmt.Errorf("--only: %w", err)
+	}
+	skip, err := parseKinds(args.Skip)
+	if err != nil {
+		return nil, 0, fmt.Errorf("--skip: %w", err)
+	}
+
+	var group []*models.Correction
+	var kind models.CorrectionKind
+	for i, c := range corrections {
+		group = append(group, c)
+		kind = kind.Max(c.GetKind())
+		if c.F == nil && i < len(corrections)-1 {
+			continue
+		}
+		if c.F == nil {
+			// Nothing makes the changes they describe.
+			kind = models.CorrectionInfo
+		}
+		if (only == nil || only[kind]) && !skip[kind] {
+			kept = append(kept, group...)
+		} else {
+			skipped += len(group)
+		}
+		group, kind = nil, 0
+	}
+	return kept, skipped, nil
+}
@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+// providerConcurrencyFieldName is the name of the field in creds.json
+// that specifies how many domains may talk to a provider at the same
+// time when --concurrency is used.
+const providerConcurrencyFieldName = "_concurrency"
+
+// providerLimiter limits the number of goroutines that may use a
+// provider at the same time.  Most providers were written assuming
+// they are called from one goroutine, therefore the default limit is 1.
+// Providers known to be safe may be given a higher limit in creds.json
+// (for example "_concurrency": "4").
+type providerLimiter struct {
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+	limit map[string]int
+}
+
+// newProviderLimiter returns a providerLimiter configured from the
+// provider entries in creds.json.
+func newProviderLimiter(providerConfigs map[string]map[string]string) *providerLimiter {
+	pl := &providerLimiter{
+		slots: map[string]chan struct{}{},
+		limit: map[string]int{},
+	}
+	for name, vals := range providerConfigs {
+		if n, err := strconv.Atoi(vals[providerConcurrencyFieldName]); err == nil && n > 0 {
+			pl.limit[name] = n
+		}
+	}
+	return pl
+}
+
+// allowRateLimited sets the limit of the providers that limit the rate
+// of their own requests (providers.RateLimiter) to n, unless creds.json
+// sets one.
+func (pl *providerLimiter) allowRateLimited(domains []*models.DomainConfig, n int) {
+	for _, d := range domains {
+		for _, p := range d.DNSProviderInstances {
+			pl.allowRateLimitedProvider(p.Name, p.Driver, n)
+		}
+		if d.RegistrarInstance != nil {
+			pl.allowRateLimitedProvider(d.RegistrarInstance.Name, d.RegistrarInstance.Driver, n)
+		}
+	}
+}
+
+// allowRateLimitedProvider is allowRateLimited for one provider.
+func (pl *providerLimiter) allowRateLimitedProvider(name string, driver interface{}, n int) {
+	if _, ok := driver.(providers.RateLimiter); !ok {
+		return
+	}
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if _, ok := pl.limit[name]; !ok {
+		pl.limit[name] = n
+	}
+}
+
+func (pl *providerLimiter) slot(name string) chan struct{} {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	s, ok := pl.slots[name]
+	if !ok {
+		n := pl.limit[name]
+		if n == 0 {
+			n = 1
+		}
+		s = make(chan struct{}, n)
+		pl.slots[name] = s
+	}
+	return s
+}
+
+// acquire waits until the named providers are available and returns a
+// function that releases them.  The names are acquired in sorted order
+// so that two goroutines waiting for the same providers can not
+// deadlock.  A nil providerLimiter does not limit anything.
+func (pl *providerLimiter) acquire(names ...string) (release func()) {
+	if pl == nil {
+		return func() {}
+	}
+
+	names = uniqueStrings(names)
+	sort.Strings(names)
+	var held []chan struct{}
+	for _, name := range names {
+		s := pl.slot(name)
+		s <- struct{}{}
+		held = append(held, s)
+	}
+
+	return func() {
+		for _, s := range held {
+			<-s
+		}
+	}
+}
+
+// runCorrectionsConcurrently is printOrRunCorrections for push (without
+// -i) when the provider declares providers.CanConcurrentlyModify: it runs
+// the corrections at up to n labels at the same time, then prints the
+// corrections and their results in order. The corrections at a label run
+// one after the other, in order, as diff2 orders the changes of a label
+// by their dependencies. If a correction doesn't have a Label, they all
+// run in order. zu is as for printOrRunCorrections.
+func runCorrectionsConcurrently(domain string, provider string, corrections []*models.Correction, out printer.CLI, n int, compact bool, notifier notifications.Notifier, zu *zoneUpdate) (anyErrors bool) {
+	if len(corrections) == 0 {
+		return false
+	}
+	if compact {
+		out.Printf("%s", diff2.CompactReport(corrections, useColor(out)))
+	}
+
+	errs := make([]error, len(corrections))
+	slots := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for _, chain := range correctionChains(corrections) {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(chain []int) {
+			defer wg.Done()
+			for _, i := range chain {
+				errs[i] = corrections[i].F()
+			}
+			<-slots
+		}(chain)
+	}
+	wg.Wait()
+
+	for i, correction := range corrections {
+		err := errs[i]
+		if correction.F != nil && zu.queue(i, correction, err) {
+			continue
+		}
+		if !compact || err != nil {
+			out.PrintCorrection(i, correction)
+		}
+		if correction.F != nil && (!compact || err != nil) {
+			out.EndCorrection(err)
+		}
+		if err != nil {
+			anyErrors = true
+		}
+		notifier.Notify(domain, provider, correction.Msg, err, false)
+	}
+	return anyErrors
+}
+
+// lockedNotifier serializes calls to a Notifier that is shared by
+// many goroutines.
+type lockedNotifier struct {
+	mu sync.Mutex
+	n  notifications.Notifier
+}
+
+// Notify implements notifications.Notifier.
+func (l *lockedNotifier) Notify(domain, provider string, message string, err error, preview bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.n.Notify(domain, provider, message, err, preview)
+}
+
+// Done implements notifications.Notifier.
+func (l *lockedNotifier) Done() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.n.Done()
+}
+
+// correctionChains returns the indexes of the corrections that do
+// something, in order, grouped by Label: one chain per label, or a
+// single chain if a correction has no Label.
+func correctionChains(corrections []*models.Correction) [][]int {
+	var chains [][]int
+	byLabel := map[string]int{} // The index of the chain of a label.
+	var all []int
+	for i, c := range corrections {
+		if c.F == nil {
+			continue
+		}
+		all = append(all, i)
+		if byLabel == nil {
+			continue
+		}
+		if c.Label == "" {
+			byLabel = nil
+			continue
+		}
+		j, ok := byLabel[c.Label]
+		if !ok {
+			j = len(chains)
+			byLabel[c.Label] = j
+			chains = append(chains, nil)
+		}
+		chains[j] = append(chains[j], i)
+	}
+	if byLabel == nil {
+		return [][]int{all}
+	}
+	return chains
+}
@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ratelimit"
+)
+
+func Test_providerLimiter(t *testing.T) {
+	pl := newProviderLimiter(map[string]map[string]string{
+		"one":   {"TYPE": "FOO"},
+		"three": {"TYPE": "BAR", "_concurrency": "3"},
+		"bad":   {"TYPE": "BAR", "_concurrency": "many"},
+	})
+
+	tests := []struct {
+		name string
+		want int32
+	}{
+		{"one", 1},
+		{"three", 3},
+		{"bad", 1},
+		{"missing", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, max int32
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					release := pl.acquire(tt.name)
+					defer release()
+					n := atomic.AddInt32(&running, 1)
+					for {
+						m := atomic.LoadInt32(&max)
+						if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+							break
+						}
+					}
+					time.Sleep(5 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+				}()
+			}
+			wg.Wait()
+			if max != tt.want {
+				t.Errorf("max concurrent = %d, want %d", max, tt.want)
+			}
+		})
+	}
+}
+
+func Test_providerLimiterMany(t *testing.T) {
+	pl := newProviderLimiter(nil)
+
+	// Acquiring overlapping sets of providers in different orders must
+	// not deadlock.
+	done := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				pl.acquire("a", "b", "a")()
+			}()
+			go func() {
+				defer wg.Done()
+				pl.acquire("b", "a")()
+			}()
+		}
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock acquiring providers")
+	}
+}
+
+func Test_providerLimiterNil(t *testing.T) {
+	var pl *providerLimiter
+	pl.acquire("a")()
+}
+
+// rateLimitedDriver is a DNS provider that limits its own rate.
+type rateLimitedDriver struct {
+	models.DNSProvider
+}
+
+func (rateLimitedDriver) RateLimit() *ratelimit.Limiter { return nil }
+
+func Test_providerLimiterRateLimited(t *testing.T) {
+	pl := newProviderLimiter(map[string]map[string]string{
+		"limited": {"TYPE": "FOO"},
+		"pinned":  {"TYPE": "FOO", "_concurrency": "2"},
+		"plain":   {"TYPE": "BAR"},
+	})
+	instance := func(name string, driver models.DNSProvider) *models.DNSProviderInstance {
+		return &models.DNSProviderInstance{ProviderBase: models.ProviderBase{Name: name}, Driver: driver}
+	}
+	pl.allowRateLimited([]*models.DomainConfig{{
+		Name: "example.com",
+		DNSProviderInstances: []*models.DNSProviderInstance{
+			instance("limited", rateLimitedDriver{}),
+			instance("pinned", rateLimitedDriver{}),
+			instance("plain", nil),
+		},
+	}}, 8)
+
+	for name, want := range map[string]int{"limited": 8, "pinned": 2, "plain": 0} {
+		if got := pl.limit[name]; got != want {
+			t.Errorf("limit[%q] = %d, want %d", name, got, want)
+		}
+	}
+}
+
+func Test_runCorrectionsConcurrently(t *testing.T) {
+	var running, max int32
+	var corrections []*models.Correction
+	for i := 0; i < 10; i++ {
+		i := i
+		corrections = append(corrections, &models.Correction{
+			Msg:   fmt.Sprintf("CREATE r%d.example.com A 1.2.3.%d", i, i),
+			Label: fmt.Sprintf("r%d.example.com", i),
+			F: func() error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&max)
+					if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				if i == 7 {
+					return fmt.Errorf("boom")
+				}
+				return nil
+			},
+		})
+	}
+	corrections = append(corrections, &models.Correction{Msg: "Just a message"})
+
+	buf := &bytes.Buffer{}
+	out := &printer.ConsolePrinter{Reader: bufio.NewReader(strings.NewReader("")), Writer: buf}
+	anyErrors := runCorrectionsConcurrently("example.com", "gandi", corrections, out, 3, false, notifications.Init(nil), nil)
+	if !anyErrors || max != 3 {
+		t.Errorf("anyErrors = %v, max concurrent = %d, want true, 3", anyErrors, max)
+	}
+
+	// The output is the same as when the corrections run one at a time.
+	want := &bytes.Buffer{}
+	out.Writer = want
+	printOrRunCorrections("example.com", "gandi", corrections, out, true, nil, false, notifications.Init(nil), nil)
+	if buf.String() != want.String() {
+		t.Errorf("got:\n%s\nwant:\n%s", buf, want)
+	}
+}
+
+func Test_correctionChains(t *testing.T) {
+	f := func() error { return nil }
+	tests := []struct {
+		name        string
+		corrections []*models.Correction
+		want        string
+	}{
+		{
+			name: "by label",
+			corrections: []*models.Correction{
+				{Msg: "DELETE www CNAME", Label: "www.example.com", F: f},
+				{Msg: "CREATE mail A", Label: "mail.example.com", F: f},
+				{Msg: "Just a message"},
+				{Msg: "CREATE www A", Label: "www.example.com", F: f},
+			},
+			want: "[[0 3] [1]]",
+		},
+		{
+			name: "a correction without a label",
+			corrections: []*models.Correction{
+				{Msg: "CREATE mail A", Label: "mail.example.com", F: f},
+				{Msg: "Enable DNSSEC", F: f},
+				{Msg: "CREATE www A", Label: "www.example.com", F: f},
+			},
+			want: "[[0 1 2]]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprint(correctionChains(tt.corrections)); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
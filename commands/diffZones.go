@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/snapshot"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/urfave/cli/v2"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args DiffZonesArgs
+	return &cli.Command{
+		Name:  "diff-zones",
+		Usage: "compare a zone at two providers, or at a provider and in a zone file (stand-alone)",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 3 {
+				return cli.Exit("Arguments should be: source1 source2 zone (Ex: cfmain bind example.com)", 1)
+			}
+			args.From = ctx.Args().Get(0)
+			args.To = ctx.Args().Get(1)
+			args.Zone = ctx.Args().Get(2)
+			return exit(DiffZones(args))
+		},
+		Flags:     args.flags(),
+		UsageText: "dnscontrol diff-zones [command options] source1 source2 zone",
+		Description: `Download a zone from two sources and print the record-level changes
+that would turn the zone at source1 into the zone at source2.  This
+is a stand-alone utility, for example to verify a migration to another
+provider before changing the delegation.
+
+A source is either a key of creds.json (the first parameter to
+NewDnsProvider() in dnsconfig.js) or, if there is no such key, the name
+of a zone file (or of a JSON file written by "push --snapshot-dir").
+
+The SOA records are ignored by default, as they always differ between
+providers. Use --ignore to change the record types that are ignored.
+
+EXAMPLES:
+   dnscontrol diff-zones cfmain newprovider example.com
+   dnscontrol diff-zones r53_prod r53_staging example.com
+   dnscontrol diff-zones --ignore=SOA,NS cfmain zones/example.com.zone example.com
+   dnscontrol diff-zones --expect-no-changes cfmain bind example.com && echo Same`,
+	}
+}())
+
+// DiffZonesArgs contains all data/flags needed to run diff-zones, independently of CLI.
+type DiffZonesArgs struct {
+	GetCredentialsArgs
+	From        string // creds.json key or zone file of the zone as it is
+	To          string // creds.json key or zone file of the zone as it should be
+	Zone        string // The zone to compare
+	Ignore      string // Comma-separated record types to ignore
+	ExpectEqual bool   // Return an error if the zones differ
+}
+
+func (args *DiffZonesArgs) flags() []cli.Flag {
+	flags := args.GetCredentialsArgs.flags()
+	flags = append(flags, &cli.StringFlag{
+		Name:        "ignore",
+		Destination: &args.Ignore,
+		Value:       "SOA",
+		Usage:       `Comma-separated record types to ignore (Ex: "SOA,NS")`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "expect-no-changes",
+		Destination: &args.ExpectEqual,
+		Usage:       `set to true for non-zero return code if the zones differ`,
+	})
+	return flags
+}
+
+// DiffZones prints the differences between the zone at two sources.
+func DiffZones(args DiffZonesArgs) error {
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return fmt.Errorf("failed DiffZones LoadProviderConfigs(%q): %w", args.CredsFile, err)
+	}
+	from, err := zoneRecords(providerConfigs, args.From, args.Zone)
+	if err != nil {
+		return err
+	}
+	to, err := zoneRecords(providerConfigs, args.To, args.Zone)
+	if err != nil {
+		return err
+	}
+
+	msgs, err := diffZones(args.Zone, from, to, args.Ignore)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("******************** Zone %s: %s => %s\n", args.Zone, args.From, args.To)
+	for _, msg := range msgs {
+		fmt.Println(msg)
+	}
+	fmt.Printf("%d differences\n", len(msgs))
+	if args.ExpectEqual && len(msgs) != 0 {
+		return fmt.Errorf("the zones differ")
+	}
+	return nil
+}
+
+// zoneRecords returns the records of zone at source, which is a key
+// of creds.json or else the name of a file.
+func zoneRecords(providerConfigs map[string]map[string]string, source, zone string) (models.Records, error) {
+	cfg, ok := providerConfigs[source]
+	if !ok {
+		if _, err := os.Stat(source); err != nil {
+			return nil, fmt.Errorf("%q is neither a key of creds.json nor a file", source)
+		}
+		return snapshot.Zone{Domain: zone, File: source}.Read()
+	}
+	provider, err := providers.CreateDNSProvider(cfg["TYPE"], cfg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed DiffZones CDP(%q): %w", source, err)
+	}
+	records, err := provider.GetZoneRecords(zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed DiffZones GetZoneRecords(%q, %q): %w", source, zone, err)
+	}
+	return records, nil
+}
+
+// diffZones returns the messages that describe the changes that turn
+// the records from into the records to, leaving the record types in
+// the comma-separated list ignore out.
+func diffZones(zone string, from, to models.Records, ignore string) ([]string, error) {
+	skip := map[string]bool{}
+	for _, t := range strings.Split(ignore, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			skip[strings.ToUpper(t)] = true
+		}
+	}
+	keep := func(recs models.Records) models.Records {
+		var kept models.Records
+		for _, rc := range recs {
+			if !skip[rc.Type] {
+				kept = append(kept, rc)
+			}
+		}
+		return kept
+	}
+
+	dc := &models.DomainConfig{Name: zone, Records: keep(to)}
+	changes, err := diff2.ByRecord(keep(from), dc, nil)
+	if err != nil {
+		return nil, err
+	}
+	var msgs []string
+	for _, c := range changes {
+		msgs = append(msgs, c.Msgs...)
+	}
+	return msgs, nil
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestDiffZones(t *testing.T) {
+	rec := func(label, typ, target string, ttl uint32) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: typ, TTL: ttl}
+		rc.SetLabel(label, "example.com")
+		if err := rc.PopulateFromString(typ, target, "example.com"); err != nil {
+			t.Fatal(err)
+		}
+		return rc
+	}
+	from := models.Records{
+		rec("@", "SOA", "ns1.example.com. hostmaster.example.com. 1 3600 600 604800 300", 300),
+		rec("@", "A", "1.2.3.4", 300),
+		rec("www", "CNAME", "example.com.", 300),
+		rec("old", "A", "1.2.3.5", 300),
+	}
+	to := models.Records{
+		rec("@", "SOA", "ns1.other.net. hostmaster.other.net. 7 3600 600 604800 300", 300),
+		rec("@", "A", "1.2.3.4", 300),
+		rec("www", "CNAME", "example.com.", 600),
+		rec("new", "TXT", "hello", 300),
+	}
+
+	msgs, err := diffZones("example.com", from, to, "soa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(msgs, "\n")
+	for _, want := range []string{"old.example.com", "new.example.com", "www.example.com"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diffZones() = %q, want a change of %s", got, want)
+		}
+	}
+	if len(msgs) != 3 {
+		t.Errorf("diffZones() = %q, want 3 changes", got)
+	}
+
+	msgs, err = diffZones("example.com", from, from, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 0 {
+		t.Errorf("diffZones() of the same zone = %q, want none", msgs)
+	}
+}
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestFormatDslGcloudRouting(t *testing.T) {
+	tests := []struct {
+		meta map[string]string
+		want string
+	}{
+		{map[string]string{"gcloud_geo": "us-east1"}, `A('www', '1.2.3.4', GCLOUD_GEO("us-east1"))`},
+		{map[string]string{"gcloud_wrr": "0.5"}, `A('www', '1.2.3.4', GCLOUD_WRR(0.5))`},
+	}
+	for _, tt := range tests {
+		rec := &models.RecordConfig{Type: "A", TTL: 300, Metadata: tt.meta}
+		rec.SetLabel("www", "domain.tld")
+		rec.SetTarget("1.2.3.4")
+		if g := formatDsl("domain.tld", rec, 300); g != tt.want {
+			t.Errorf("formatDsl failure: got `%s` want `%s`", g, tt.want)
+		}
+	}
+}
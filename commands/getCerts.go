@@ -6,6 +6,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/acme"
@@ -19,7 +20,7 @@ var _ = cmd(catUtils, func() *cli.Command {
 	var args GetCertsArgs
 	return &cli.Command{
 		Name:  "get-certs",
-		Usage: "Issue certificates via Let's Encrypt",
+		Usage: "Issue certificates via Let's Encrypt or another ACME CA",
 		Action: func(c *cli.Context) error {
 			return exit(GetCerts(args))
 		},
@@ -33,6 +34,9 @@ type GetCertsArgs struct {
 	GetCredentialsArgs
 
 	ACMEServer     string
+	EABKeyID       string
+	EABHMACKey     string
+	Settle         string
 	CertsFile      string
 	RenewUnderDays int
 	CertDirectory  string
@@ -56,7 +60,24 @@ func (args *GetCertsArgs) flags() []cli.Flag {
 		Name:        "acme",
 		Destination: &args.ACMEServer,
 		Value:       "live",
-		Usage:       `ACME server to issue against. Give full directory endpoint. Can also use 'staging' or 'live' for standard Let's Encrypt endpoints.`,
+		Usage:       `ACME server to issue against. Give full directory endpoint. Can also use 'staging' or 'live' for standard Let's Encrypt endpoints, 'zerossl', 'buypass' or 'buypass-staging'.`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "eab-kid",
+		Destination: &args.EABKeyID,
+		EnvVars:     []string{"ACME_EAB_KID"},
+		Usage:       `Key ID of the external account binding, to create an account at CAs that need one (e.g. ZeroSSL)`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "eab-hmac",
+		Destination: &args.EABHMACKey,
+		EnvVars:     []string{"ACME_EAB_HMAC_KEY"},
+		Usage:       `HMAC key (base64url) of the external account binding`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "settle",
+		Destination: &args.Settle,
+		Usage:       `How long to wait after the nameservers serve a challenge, e.g. "30s" (default: depends on the provider)`,
 	})
 	flags = append(flags, &cli.IntFlag{
 		Name:        "renew",
@@ -80,12 +101,12 @@ func (args *GetCertsArgs) flags() []cli.Flag {
 		Name:        "email",
 		Destination: &args.Email,
 		Value:       "",
-		Usage:       `Email to register with let's encrypt`,
+		Usage:       `Email to register with the ACME CA`,
 	})
 	flags = append(flags, &cli.BoolFlag{
 		Name:        "agreeTOS",
 		Destination: &args.AgreeTOS,
-		Usage:       `Must provide this to agree to Let's Encrypt terms of service`,
+		Usage:       `Must provide this to agree to the terms of service of the ACME CA`,
 	})
 	flags = append(flags, &cli.BoolFlag{
 		Name:        "vault",
@@ -127,10 +148,10 @@ func GetCerts(args GetCertsArgs) error {
 	fmt.Println(args.JSFile)
 	// check agree flag
 	if !args.AgreeTOS {
-		return fmt.Errorf("you must agree to the Let's Encrypt Terms of Service by using -agreeTOS")
+		return fmt.Errorf("you must agree to the Terms of Service of the ACME CA by using -agreeTOS")
 	}
 	if args.Email == "" {
-		return fmt.Errorf("must provide email to use for Let's Encrypt registration")
+		return fmt.Errorf("must provide email to use for ACME registration")
 	}
 
 	// load dns config
@@ -174,19 +195,26 @@ func GetCerts(args GetCertsArgs) error {
 		return err
 	}
 
-	acmeServer := args.ACMEServer
-	if acmeServer == "live" {
-		acmeServer = acme.LetsEncryptLive
-	} else if acmeServer == "staging" {
-		acmeServer = acme.LetsEncryptStage
+	opts := acme.Options{
+		Email:      args.Email,
+		Server:     args.ACMEServer,
+		EABKeyID:   args.EABKeyID,
+		EABHMACKey: args.EABHMACKey,
+	}
+	if args.Settle != "" {
+		settle, err := time.ParseDuration(args.Settle)
+		if err != nil {
+			return fmt.Errorf("invalid --settle: %w", err)
+		}
+		opts.Settle = &settle
 	}
 
 	var client acme.Client
 
 	if args.Vault {
-		client, err = acme.NewVault(cfg, args.VaultPath, args.Email, acmeServer, notifier)
+		client, err = acme.NewVault(cfg, args.VaultPath, opts, notifier)
 	} else {
-		client, err = acme.New(cfg, args.CertDirectory, args.Email, acmeServer, notifier)
+		client, err = acme.New(cfg, args.CertDirectory, opts, notifier)
 	}
 	if err != nil {
 		return err
@@ -3,13 +3,18 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
 	"github.com/StackExchange/dnscontrol/v3/pkg/prettyzone"
 	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/gobwas/glob"
 	"github.com/urfave/cli/v2"
 )
 
@@ -53,6 +58,8 @@ FORMATS:
    --format=djs       js with disco commas (leading commas)
    --format=zone      BIND zonefile format
    --format=tsv       TAB separated value (useful for AWK)
+   --format=djson     dnscontrol IR (the JSON output by print-ir; use with --ir)
+   --format=terraform Terraform resources (see below)
    --format=nameonly  Just print the zone names
 
 The columns in --format=tsv are:
@@ -63,63 +70,34 @@ The columns in --format=tsv are:
    Target and arguments (quoted like in a zonefile)
    Either empty or a comma-separated list of properties like "cloudflare_proxy=true"
 
+--format=terraform generates aws_route53_record resources for ROUTE53,
+cloudflare_record resources for CLOUDFLAREAPI, and resources of the
+hashicorp/dns provider for everything else. Set the zone_id
+attributes (which are "CHANGEME") before use.
+
 The --ttl flag only applies to zone/js/djs formats.
 
+With "all", --include and --exclude select zones by name, for example
+--include='*.com' --exclude='test*'. Use --concurrency to download many
+zones at once; each provider is still limited to "_concurrency" (default
+1) downloads at a time, set in its creds.json entry.
+
+To download a view of a split horizon domain, give the zone as
+"example.com!inside" (quoted for the shell); the js, djs and djson
+formats then declare D("example.com!inside"). --tag=inside does the
+same for all the zones.
+
 EXAMPLES:
    dnscontrol get-zones myr53 ROUTE53 example.com
    dnscontrol get-zones gmain GANDI_V5 example.com other.com
    dnscontrol get-zones cfmain CLOUDFLAREAPI all
+   dnscontrol get-zones --include='*.com' --concurrency=8 cfmain - all
    dnscontrol get-zones --format=tsv bind BIND example.com
+   dnscontrol get-zones --format=js bind BIND 'example.com!inside'
    dnscontrol get-zones --format=djs --out=draft.js glcoud GCLOUD example.com`,
 	}
 }())
 
-// check-creds foo bar
-// is the same as
-// get-zones --format=nameonly foo bar all
-var _ = cmd(catUtils, func() *cli.Command {
-	var args GetZoneArgs
-	return &cli.Command{
-		Name:  "check-creds",
-		Usage: "Do a small operation to verify credentials (stand-alone)",
-		Action: func(ctx *cli.Context) error {
-			var arg0, arg1 string
-			// This takes one or two command-line args.
-			// Starting in v3.16: Using it with 2 args will generate a warning.
-			// Starting in v4.0: Using it with 2 args might be an error.
-			if ctx.NArg() == 1 {
-				arg0 = ctx.Args().Get(0)
-				arg1 = ""
-			} else if ctx.NArg() == 2 {
-				arg0 = ctx.Args().Get(0)
-				arg1 = ctx.Args().Get(1)
-			} else {
-				return cli.Exit("Arguments should be: credskey [providername] (Ex: r53 ROUTE53)", 1)
-			}
-			args.CredName = arg0
-			args.ProviderName = arg1
-			args.ZoneNames = []string{"all"}
-			args.OutputFormat = "nameonly"
-			return exit(GetZone(args))
-		},
-		Flags:     args.flags(),
-		UsageText: "dnscontrol check-creds [command options] credkey provider",
-		Description: `Do a trivia operation to verify credentials.  This is a stand-alone utility.
-
-If successful, a list of zones will be output. If not, hopefully you
-see verbose error messages.
-
-ARGUMENTS:
-   credkey:  The name used in creds.json (first parameter to NewDnsProvider() in dnsconfig.js)
-   provider: The name of the provider (second parameter to NewDnsProvider() in dnsconfig.js)
-
-EXAMPLES:
-   dnscontrol check-creds myr53 ROUTE53      # Pre v3.16, or pre-v4.0 for backwards-compatibility
-   dnscontrol check-creds myr53
-   dnscontrol check-creds --out=/dev/null myr53 && echo Success`,
-	}
-}())
-
 // GetZoneArgs args required for the create-domain subcommand.
 type GetZoneArgs struct {
 	GetCredentialsArgs          // Args related to creds.json
@@ -129,6 +107,10 @@ type GetZoneArgs struct {
 	OutputFormat       string   // Output format
 	OutputFile         string   // Filename to send output ("" means stdout)
 	DefaultTTL         int      // default TTL for providers where it is unknown
+	Include            string   // Comma-separated globs; only zones that match one are output
+	Exclude            string   // Comma-separated globs; zones that match one are not output
+	Concurrency        int      // Number of zones to download in parallel
+	Tag                string   // Split horizon tag of the zones that are given without one
 }
 
 func (args *GetZoneArgs) flags() []cli.Flag {
@@ -137,7 +119,7 @@ func (args *GetZoneArgs) flags() []cli.Flag {
 		Name:        "format",
 		Destination: &args.OutputFormat,
 		Value:       "zone",
-		Usage:       `Output format: js djs zone tsv nameonly`,
+		Usage:       `Output format: js djs djson zone tsv terraform nameonly`,
 	})
 	flags = append(flags, &cli.StringFlag{
 		Name:        "out",
@@ -149,6 +131,27 @@ func (args *GetZoneArgs) flags() []cli.Flag {
 		Destination: &args.DefaultTTL,
 		Usage:       `Default TTL (0 picks the zone's most common TTL)`,
 	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "include",
+		Destination: &args.Include,
+		Usage:       `Only output zones that match one of these comma-separated globs (Ex: "*.com,example.*")`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "exclude",
+		Destination: &args.Exclude,
+		Usage:       `Do not output zones that match one of these comma-separated globs`,
+	})
+	flags = append(flags, &cli.IntFlag{
+		Name:        "concurrency",
+		Destination: &args.Concurrency,
+		Value:       1,
+		Usage:       `Number of zones to download in parallel (see "_concurrency" in creds.json)`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "tag",
+		Destination: &args.Tag,
+		Usage:       `Output the zones as this view of split horizon domains (D("example.com!tag")), unless the zone has a tag`,
+	})
 	return flags
 }
 
@@ -180,6 +183,23 @@ func GetZone(args GetZoneArgs) error {
 		}
 	}
 
+	zones, err = filterZones(zones, args.Include, args.Exclude)
+	if err != nil {
+		return err
+	}
+
+	// A zone can be given as "domain.tld!tag" to output it as that view
+	// of a split horizon domain. The provider only knows "domain.tld".
+	views := make([]string, len(zones))
+	zones = append([]string(nil), zones...)
+	for i, zone := range zones {
+		name, tag := models.SplitDomainName(zone)
+		if tag == "" {
+			tag = args.Tag
+		}
+		zones[i], views[i] = name, models.UniqueDomainName(name, tag)
+	}
+
 	// first open output stream and print initial header (if applicable)
 	w := os.Stdout
 	if args.OutputFile != "" {
@@ -191,20 +211,41 @@ func GetZone(args GetZoneArgs) error {
 	defer w.Close()
 
 	if args.OutputFormat == "nameonly" {
-		for _, zone := range zones {
-			fmt.Fprintln(w, zone)
+		for _, view := range views {
+			fmt.Fprintln(w, view)
 		}
 		return nil
 	}
 
 	// fetch all of the records
-	zoneRecs := make([]models.Records, len(zones))
-	for i, zone := range zones {
-		recs, err := provider.GetZoneRecords(zone)
-		if err != nil {
-			return fmt.Errorf("failed GetZone gzr: %w", err)
+	limits := newProviderLimiter(providerConfigs)
+	limits.allowRateLimitedProvider(args.CredName, provider, args.Concurrency)
+	zoneRecs, err := fetchZones(provider, args.CredName, zones, args.Concurrency, limits)
+	if err != nil {
+		return err
+	}
+
+	// Zone-level settings, exported as domain metadata.
+	var zoneMeta []map[string]string
+	if getter, ok := provider.(providers.ZoneMetadataGetter); ok {
+		switch args.OutputFormat {
+		case "js", "djs", "djson":
+			for _, zone := range zones {
+				meta, err := getter.GetZoneMetadata(zone)
+				if err != nil {
+					return fmt.Errorf("failed GetZone GetZoneMetadata(%q): %w", zone, err)
+				}
+				zoneMeta = append(zoneMeta, meta)
+			}
 		}
-		zoneRecs[i] = recs
+	}
+
+	// These formats write all the zones as one document.
+	switch args.OutputFormat {
+	case "djson":
+		return writeDJSON(w, args.CredName, providerType(args, providerConfigs), views, zoneRecs, zoneMeta)
+	case "terraform":
+		return writeTerraform(w, providerType(args, providerConfigs), zones, zoneRecs)
 	}
 
 	// Write the heading:
@@ -231,6 +272,9 @@ func GetZone(args GetZoneArgs) error {
 		switch args.OutputFormat {
 
 		case "zone":
+			if views[i] != zoneName {
+				fmt.Fprintf(w, "; %s\n", views[i])
+			}
 			fmt.Fprintf(w, "$ORIGIN %s.\n", zoneName)
 			prettyzone.WriteZoneFileRC(w, z.Records, zoneName, uint32(args.DefaultTTL), nil)
 			fmt.Fprintln(w)
@@ -240,9 +284,12 @@ func GetZone(args GetZoneArgs) error {
 			if args.OutputFormat == "djs" {
 				sep = "\n\t, " // Funky comma mode
 			}
-			fmt.Fprintf(w, `D("%s", REG_CHANGEME%s`, zoneName, sep)
+			fmt.Fprintf(w, `D("%s", REG_CHANGEME%s`, views[i], sep)
 			var o []string
 			o = append(o, fmt.Sprintf("DnsProvider(%s)", dspVariableName))
+			if zoneMeta != nil && len(zoneMeta[i]) > 0 {
+				o = append(o, formatMetadata(zoneMeta[i]))
+			}
 			defaultTTL := uint32(args.DefaultTTL)
 			if defaultTTL == 0 {
 				defaultTTL = prettyzone.MostCommonTTL(recs)
@@ -297,6 +344,155 @@ func GetZone(args GetZoneArgs) error {
 	return nil
 }
 
+// filterZones returns the zones that match at least one of the include
+// globs (or all of them, if there are none) and none of the exclude
+// globs. "*" matches any characters, including dots.
+func filterZones(zones []string, include, exclude string) ([]string, error) {
+	compile := func(list string) ([]glob.Glob, error) {
+		var globs []glob.Glob
+		for _, pattern := range strings.Split(list, ",") {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				continue
+			}
+			g, err := glob.Compile(strings.ToLower(pattern))
+			if err != nil {
+				return nil, fmt.Errorf("invalid zone glob %q: %w", pattern, err)
+			}
+			globs = append(globs, g)
+		}
+		return globs, nil
+	}
+	matchAny := func(globs []glob.Glob, zone string) bool {
+		for _, g := range globs {
+			if g.Match(strings.ToLower(zone)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	inc, err := compile(include)
+	if err != nil {
+		return nil, err
+	}
+	exc, err := compile(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []string
+	for _, zone := range zones {
+		if len(inc) != 0 && !matchAny(inc, zone) {
+			continue
+		}
+		if matchAny(exc, zone) {
+			continue
+		}
+		filtered = append(filtered, zone)
+	}
+	return filtered, nil
+}
+
+// fetchZones downloads the records of the zones, up to concurrency
+// zones at a time, and no more than limits permits for the provider
+// named credName. The records are returned in the same order as zones.
+func fetchZones(provider providers.DNSServiceProvider, credName string, zones []string, concurrency int, limits *providerLimiter) ([]models.Records, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	zoneRecs := make([]models.Records, len(zones))
+	errs := make([]error, len(zones))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				release := limits.acquire(credName)
+				zoneRecs[i], errs[i] = provider.GetZoneRecords(zones[i])
+				release()
+			}
+		}()
+	}
+	for i := range zones {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed GetZone gzr(%q): %w", zones[i], err)
+		}
+	}
+	return zoneRecs, nil
+}
+
+// providerType returns the provider type (ROUTE53, BIND, etc.), which
+// may come from creds.json.
+func providerType(args GetZoneArgs, providerConfigs map[string]map[string]string) string {
+	if args.ProviderName == "" || args.ProviderName == "-" {
+		return providerConfigs[args.CredName]["TYPE"]
+	}
+	return args.ProviderName
+}
+
+// writeDJSON writes the zones, given as "domain.tld" or
+// "domain.tld!tag", as a dnsconfig IR document, the same
+// JSON that "dnscontrol print-ir" outputs. "dnscontrol preview --ir"
+// and "push --ir" can read it.
+func writeDJSON(w io.Writer, credName, pType string, zones []string, zoneRecs []models.Records, zoneMeta []map[string]string) error {
+	cfg := &models.DNSConfig{
+		Registrars:   []*models.RegistrarConfig{{Name: "none", Type: "NONE"}},
+		DNSProviders: []*models.DNSProviderConfig{{Name: credName, Type: pType}},
+		Domains:      []*models.DomainConfig{},
+	}
+	for i, view := range zones {
+		zone, tag := models.SplitDomainName(view)
+		dc := &models.DomainConfig{
+			Name:             zone,
+			Tag:              tag,
+			RegistrarName:    "none",
+			DNSProviderNames: map[string]int{credName: -1},
+			Records:          models.Records{},
+		}
+		if zoneMeta != nil && len(zoneMeta[i]) > 0 {
+			dc.Metadata = zoneMeta[i]
+		}
+		for _, rec := range prettyzone.PrettySort(zoneRecs[i], zone, 0, nil).Records {
+			// Like NAMESERVER() in the js format, the apex NS records
+			// come from the provider.
+			if rec.Type == "SOA" || (rec.Type == "NS" && rec.Name == "@") {
+				continue
+			}
+			dc.Records = append(dc.Records, rec)
+		}
+		cfg.Domains = append(cfg.Domains, dc)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cfg)
+}
+
+// formatMetadata returns the domain metadata as a JS object, with the
+// keys sorted.
+func formatMetadata(meta map[string]string) string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var items []string
+	for _, k := range keys {
+		items = append(items, fmt.Sprintf("%s: %s", jsonQuoted(k), jsonQuoted(meta[k])))
+	}
+	return "{" + strings.Join(items, ", ") + "}"
+}
+
 // jsonQuoted returns a properly escaped JSON string (without quotes).
 func jsonQuoted(i string) string {
 	// https://stackoverflow.com/questions/51691901
@@ -325,9 +521,17 @@ func formatDsl(zonename string, rec *models.RecordConfig, defaultTTL uint32) str
 		}
 	}
 
+	if models.IsRFC3597Type(rec.Type) {
+		return fmt.Sprintf("RFC3597('%s', '%s', '%s'%s)", rec.Name, rec.Type, rec.GetRFC3597Data(), ttlop)
+	}
+
 	switch rec.Type { // #rtype_variations
 	case "CAA":
 		return makeCaa(rec, ttlop)
+	case "LOC":
+		target = makeLoc(target)
+	case "LUA":
+		target = fmt.Sprintf("'%s', %s", rec.LuaRType, jsonQuoted(rec.GetTargetField()))
 	case "MX":
 		target = fmt.Sprintf("%d, '%s'", rec.MxPreference, rec.GetTargetField())
 	case "NAPTR":
@@ -339,6 +543,8 @@ func formatDsl(zonename string, rec *models.RecordConfig, defaultTTL uint32) str
 			jsonQuoted(rec.NaptrRegexp),      // regex
 			jsonQuoted(rec.GetTargetField()), // .
 		)
+	case "HTTPS", "SVCB":
+		target = fmt.Sprintf("%d, '%s', '%s'", rec.SvcPriority, rec.GetTargetField(), rec.SvcParams)
 	case "SSHFP":
 		target = fmt.Sprintf("%d, %d, '%s'", rec.SshfpAlgorithm, rec.SshfpFingerprint, rec.GetTargetField())
 	case "SOA":
@@ -348,6 +554,8 @@ func formatDsl(zonename string, rec *models.RecordConfig, defaultTTL uint32) str
 		target = fmt.Sprintf("%d, %d, %d, '%s'", rec.SrvPriority, rec.SrvWeight, rec.SrvPort, rec.GetTargetField())
 	case "TLSA":
 		target = fmt.Sprintf("%d, %d, %d, '%s'", rec.TlsaUsage, rec.TlsaSelector, rec.TlsaMatchingType, rec.GetTargetField())
+	case "URI":
+		target = fmt.Sprintf("%d, %d, '%s'", rec.UriPriority, rec.UriWeight, rec.GetTargetField())
 	case "TXT":
 		if len(rec.TxtStrings) == 1 {
 			target = `'` + rec.TxtStrings[0] + `'`
@@ -369,7 +577,15 @@ func formatDsl(zonename string, rec *models.RecordConfig, defaultTTL uint32) str
 		target = "'" + target + "'"
 	}
 
-	return fmt.Sprintf("%s('%s', %s%s%s)", rec.Type, rec.Name, target, cfproxy, ttlop)
+	routing := ""
+	if r := makeR53routing(rec); r != "" {
+		routing += ", " + r
+	}
+	if r := makeGcloudRouting(rec); r != "" {
+		routing += ", " + r
+	}
+
+	return fmt.Sprintf("%s('%s', %s%s%s%s)", rec.Type, rec.Name, target, cfproxy, routing, ttlop)
 }
 
 func makeCaa(rec *models.RecordConfig, ttlop string) string {
@@ -384,6 +600,65 @@ func makeCaa(rec *models.RecordConfig, ttlop string) string {
 	// TODO(tlim): Generate a CAA_BUILDER() instead?
 }
 
+// makeLoc converts the zonefile representation of a LOC record
+// ("52 22 23.000 N 4 53 32.000 E -2.00m 1m 10000m 10m") into the
+// arguments of LOC().
+func makeLoc(target string) string {
+	f := strings.Fields(target)
+	if len(f) != 12 {
+		return "'" + target + "'"
+	}
+	// Remove the leading zeros from degrees and minutes ("04") so they
+	// aren't mistaken for octal.
+	for _, i := range []int{0, 1, 4, 5} {
+		f[i] = strings.TrimLeft(f[i], "0")
+		if f[i] == "" {
+			f[i] = "0"
+		}
+	}
+	for i := 8; i < 12; i++ {
+		f[i] = strings.TrimSuffix(f[i], "m")
+	}
+	f[3] = "'" + f[3] + "'"
+	f[7] = "'" + f[7] + "'"
+	return strings.Join(f, ", ")
+}
+
+// makeR53routing returns the Route 53 routing policy metadata of the
+// record as a record modifier, or "" if it has none.
+func makeR53routing(rec *models.RecordConfig) string {
+	var keys []string
+	for k := range rec.Metadata {
+		if strings.HasPrefix(k, "r53_") {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	items := make([]string, len(keys))
+	for i, k := range keys {
+		items[i] = k + ": " + jsonQuoted(rec.Metadata[k])
+	}
+	return "{" + strings.Join(items, ", ") + "}"
+}
+
+// makeGcloudRouting returns the Google Cloud DNS routing policy of the
+// record as a record modifier, or "" if it has none.
+func makeGcloudRouting(rec *models.RecordConfig) string {
+	if v, ok := rec.Metadata["gcloud_geo"]; ok {
+		return "GCLOUD_GEO(" + jsonQuoted(v) + ")"
+	}
+	if v, ok := rec.Metadata["gcloud_wrr"]; ok {
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return "GCLOUD_WRR(" + v + ")"
+		}
+		return "GCLOUD_WRR(" + jsonQuoted(v) + ")"
+	}
+	return ""
+}
+
 func makeR53alias(rec *models.RecordConfig, ttl uint32) string {
 	items := []string{
 		"'" + rec.Name + "'",
@@ -393,6 +668,9 @@ func makeR53alias(rec *models.RecordConfig, ttl uint32) string {
 	if z, ok := rec.R53Alias["zone_id"]; ok {
 		items = append(items, "R53_ZONE('"+z+"')")
 	}
+	if r := makeR53routing(rec); r != "" {
+		items = append(items, r)
+	}
 	if ttl != 0 {
 		items = append(items, fmt.Sprintf("TTL(%d)", ttl))
 	}
@@ -0,0 +1,271 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/prettyzone"
+)
+
+// writeTerraform writes the zones as Terraform resources.
+//
+// ROUTE53 zones become aws_route53_record resources and CLOUDFLAREAPI
+// zones become cloudflare_record resources. Zones from any other
+// provider become resources of the hashicorp/dns provider (RFC 2136
+// dynamic updates), which supports fewer record types. Records that
+// can't be converted are listed in a comment.
+func writeTerraform(w io.Writer, pType string, zones []string, zoneRecs []models.Records) error {
+	names := map[string]int{}
+	for i, zone := range zones {
+		var recs models.Records
+		for _, rec := range prettyzone.PrettySort(zoneRecs[i], zone, 0, nil).Records {
+			// The SOA and the apex NS records belong to the zone, not
+			// to the records managed in it.
+			if rec.Type == "SOA" || (rec.Type == "NS" && rec.Name == "@") {
+				continue
+			}
+			recs = append(recs, rec)
+		}
+
+		var skipped []string
+		switch pType {
+		case "ROUTE53":
+			skipped = writeTerraformRoute53(w, names, zone, recs)
+		case "CLOUDFLAREAPI":
+			skipped = writeTerraformCloudflare(w, names, zone, recs)
+		default:
+			skipped = writeTerraformRFC2136(w, names, zone, recs)
+		}
+		if len(skipped) != 0 {
+			fmt.Fprintf(w, "# %s: these records could not be converted:\n", zone)
+			for _, s := range skipped {
+				fmt.Fprintf(w, "#   %s\n", s)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
+
+// rrset is the records with the same label and type.
+type rrset struct {
+	Name, Type string
+	TTL        uint32
+	Records    models.Records
+}
+
+// groupRRsets groups the records by label and type, in the order they
+// first appear.
+func groupRRsets(recs models.Records) []*rrset {
+	var sets []*rrset
+	index := map[string]*rrset{}
+	for _, rec := range recs {
+		key := rec.Name + "/" + rec.Type
+		set, ok := index[key]
+		if !ok {
+			set = &rrset{Name: rec.Name, Type: rec.Type, TTL: rec.TTL}
+			index[key] = set
+			sets = append(sets, set)
+		}
+		set.Records = append(set.Records, rec)
+	}
+	return sets
+}
+
+func writeTerraformRFC2136(w io.Writer, names map[string]int, zone string, recs models.Records) (skipped []string) {
+	for _, set := range groupRRsets(recs) {
+		var body []string
+		switch set.Type {
+		case "A", "AAAA":
+			var addrs []string
+			for _, rec := range set.Records {
+				addrs = append(addrs, rec.GetTargetField())
+			}
+			body = append(body, "addresses = "+hclList(addrs))
+		case "CNAME", "PTR":
+			if len(set.Records) != 1 {
+				skipped = append(skipped, describeRRset(set))
+				continue
+			}
+			body = append(body, strings.ToLower(set.Type)+" = "+hclString(set.Records[0].GetTargetField()))
+		case "MX":
+			for _, rec := range set.Records {
+				body = append(body, hclBlock("mx",
+					"preference = "+strconv.Itoa(int(rec.MxPreference)),
+					"exchange = "+hclString(rec.GetTargetField()),
+				))
+			}
+		case "NS":
+			var targets []string
+			for _, rec := range set.Records {
+				targets = append(targets, rec.GetTargetField())
+			}
+			body = append(body, "nameservers = "+hclList(targets))
+		case "SRV":
+			for _, rec := range set.Records {
+				body = append(body, hclBlock("srv",
+					"priority = "+strconv.Itoa(int(rec.SrvPriority)),
+					"weight = "+strconv.Itoa(int(rec.SrvWeight)),
+					"port = "+strconv.Itoa(int(rec.SrvPort)),
+					"target = "+hclString(rec.GetTargetField()),
+				))
+			}
+		case "TXT":
+			var txts []string
+			for _, rec := range set.Records {
+				txts = append(txts, strings.Join(rec.TxtStrings, ""))
+			}
+			body = append(body, "txt = "+hclList(txts))
+		default:
+			skipped = append(skipped, describeRRset(set))
+			continue
+		}
+
+		resource := map[string]string{
+			"A": "dns_a_record_set", "AAAA": "dns_aaaa_record_set",
+			"CNAME": "dns_cname_record", "MX": "dns_mx_record_set",
+			"NS": "dns_ns_record_set", "PTR": "dns_ptr_record",
+			"SRV": "dns_srv_record_set", "TXT": "dns_txt_record_set",
+		}[set.Type]
+		head := []string{"zone = " + hclString(zone+".")}
+		if set.Name != "@" {
+			head = append(head, "name = "+hclString(set.Name))
+		}
+		body = append(head, append(body, "ttl = "+strconv.Itoa(int(set.TTL)))...)
+		writeHCLResource(w, resource, tfName(names, zone, set.Name, set.Type), body)
+	}
+	return skipped
+}
+
+func writeTerraformRoute53(w io.Writer, names map[string]int, zone string, recs models.Records) (skipped []string) {
+	for _, set := range groupRRsets(recs) {
+		fqdn := set.Records[0].GetLabelFQDN()
+		body := []string{
+			`zone_id = "CHANGEME"`,
+			"name = " + hclString(fqdn),
+		}
+
+		if set.Type == "R53_ALIAS" {
+			if len(set.Records) != 1 {
+				skipped = append(skipped, describeRRset(set))
+				continue
+			}
+			rec := set.Records[0]
+			alias := []string{
+				"name = " + hclString(rec.GetTargetField()),
+				"zone_id = " + hclString(rec.R53Alias["zone_id"]),
+				"evaluate_target_health = false",
+			}
+			body = append(body, "type = "+hclString(rec.R53Alias["type"]), hclBlock("alias", alias...))
+			writeHCLResource(w, "aws_route53_record", tfName(names, zone, set.Name, set.Type), body)
+			continue
+		}
+
+		var values []string
+		for _, rec := range set.Records {
+			if rec.Type == "TXT" {
+				// Route 53 wants the strings of a TXT record to be
+				// separated by "" in Terraform.
+				values = append(values, strings.Join(rec.TxtStrings, `""`))
+			} else {
+				values = append(values, rec.GetTargetCombined())
+			}
+		}
+		body = append(body,
+			"type = "+hclString(set.Type),
+			"ttl = "+strconv.Itoa(int(set.TTL)),
+			"records = "+hclList(values),
+		)
+		writeHCLResource(w, "aws_route53_record", tfName(names, zone, set.Name, set.Type), body)
+	}
+	return skipped
+}
+
+func writeTerraformCloudflare(w io.Writer, names map[string]int, zone string, recs models.Records) (skipped []string) {
+	for _, rec := range recs {
+		body := []string{
+			`zone_id = "CHANGEME"`,
+			"name = " + hclString(rec.Name),
+			"type = " + hclString(rec.Type),
+		}
+		switch rec.Type {
+		case "A", "AAAA", "CNAME", "NS", "PTR":
+			body = append(body, "value = "+hclString(strings.TrimSuffix(rec.GetTargetField(), ".")))
+		case "MX":
+			body = append(body,
+				"value = "+hclString(strings.TrimSuffix(rec.GetTargetField(), ".")),
+				"priority = "+strconv.Itoa(int(rec.MxPreference)),
+			)
+		case "TXT":
+			body = append(body, "value = "+hclString(strings.Join(rec.TxtStrings, "")))
+		default:
+			skipped = append(skipped, fmt.Sprintf("%s %s %s", rec.Name, rec.Type, rec.GetTargetCombined()))
+			continue
+		}
+		body = append(body, "ttl = "+strconv.Itoa(int(rec.TTL)))
+		if rec.Metadata["cloudflare_proxy"] == "true" {
+			body = append(body, "proxied = true")
+		}
+		writeHCLResource(w, "cloudflare_record", tfName(names, zone, rec.Name, rec.Type), body)
+	}
+	return skipped
+}
+
+func describeRRset(set *rrset) string {
+	var targets []string
+	for _, rec := range set.Records {
+		targets = append(targets, rec.GetTargetCombined())
+	}
+	return fmt.Sprintf("%s %s %s", set.Name, set.Type, strings.Join(targets, ", "))
+}
+
+var tfNameInvalid = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// tfName returns a unique Terraform resource name for the records
+// with this label and type.
+func tfName(names map[string]int, zone, label, rtype string) string {
+	if label == "@" {
+		label = "apex"
+	}
+	name := tfNameInvalid.ReplaceAllString(strings.ToLower(zone+"_"+label+"_"+rtype), "_")
+	names[name]++
+	if n := names[name]; n > 1 {
+		name = fmt.Sprintf("%s_%d", name, n)
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// hclString quotes s as an HCL string. "${" and "%{" are escaped so
+// that they aren't taken as template sequences.
+func hclString(s string) string {
+	q := strconv.Quote(s)
+	q = strings.ReplaceAll(q, "${", "$${")
+	return strings.ReplaceAll(q, "%{", "%%{")
+}
+
+func hclList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = hclString(s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func hclBlock(name string, attrs ...string) string {
+	return name + " {\n    " + strings.Join(attrs, "\n    ") + "\n  }"
+}
+
+func writeHCLResource(w io.Writer, resource, name string, body []string) {
+	fmt.Fprintf(w, "resource %q %q {\n", resource, name)
+	for _, line := range body {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	fmt.Fprint(w, "}\n\n")
+}
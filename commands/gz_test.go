@@ -4,8 +4,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/providers"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/_all"
 	"github.com/andreyvit/diff"
 )
@@ -16,6 +21,8 @@ func TestFormatTypes(t *testing.T) {
 	  test_data/$DOMAIN.zone   js              test_data/$DOMAIN.zone.js
 	  test_data/$DOMAIN.zone   tsv             test_data/$DOMAIN.zone.tsv
 	  test_data/$DOMAIN.zone   zone            test_data/$DOMAIN.zone.zone
+	  test_data/$DOMAIN.zone   djson           test_data/$DOMAIN.zone.djson
+	  test_data/$DOMAIN.zone   terraform       test_data/$DOMAIN.zone.terraform
 	*/
 
 	for _, domain := range []string{"simple.com", "example.org", "apex.com"} {
@@ -23,6 +30,8 @@ func TestFormatTypes(t *testing.T) {
 		t.Run(domain+"/djs", func(t *testing.T) { testFormat(t, domain, "djs") })
 		t.Run(domain+"/tsv", func(t *testing.T) { testFormat(t, domain, "tsv") })
 		t.Run(domain+"/zone", func(t *testing.T) { testFormat(t, domain, "zone") })
+		t.Run(domain+"/djson", func(t *testing.T) { testFormat(t, domain, "djson") })
+		t.Run(domain+"/terraform", func(t *testing.T) { testFormat(t, domain, "terraform") })
 	}
 }
 
@@ -76,3 +85,153 @@ func testFormat(t *testing.T, domain, format string) {
 		t.Errorf("testFormat mismatch (-got +want):\n%s", diff.LineDiff(g, w))
 	}
 }
+
+func TestHCLString(t *testing.T) {
+	for in, want := range map[string]string{
+		`v=spf1 ~all`:    `"v=spf1 ~all"`,
+		`say "hi"`:       `"say \"hi\""`,
+		`${var.x} %{if}`: `"$${var.x} %%{if}"`,
+	} {
+		if got := hclString(in); got != want {
+			t.Errorf("hclString(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestTFName(t *testing.T) {
+	names := map[string]int{}
+	for _, tst := range []struct{ zone, label, rtype, want string }{
+		{"example.com", "@", "A", "example_com_apex_a"},
+		{"example.com", "_sip._tcp", "SRV", "example_com__sip__tcp_srv"},
+		{"example.com", "@", "A", "example_com_apex_a_2"},
+		{"1.example.com", "*", "CNAME", "_1_example_com___cname"},
+	} {
+		if got := tfName(names, tst.zone, tst.label, tst.rtype); got != tst.want {
+			t.Errorf("tfName(%q, %q, %q) = %q, want %q", tst.zone, tst.label, tst.rtype, got, tst.want)
+		}
+	}
+}
+
+func TestFilterZones(t *testing.T) {
+	zones := []string{"example.com", "www.example.com", "example.net", "test.example.org", "Other.COM"}
+	tests := []struct {
+		include, exclude string
+		want             []string
+	}{
+		{"", "", zones},
+		{"*.com", "", []string{"example.com", "www.example.com", "Other.COM"}},
+		{"example.*", "", []string{"example.com", "example.net"}},
+		{"*.com, *.net", "www.*", []string{"example.com", "example.net", "Other.COM"}},
+		{"", "*.org,other.com", []string{"example.com", "www.example.com", "example.net"}},
+		{"nomatch", "", nil},
+	}
+	for _, tst := range tests {
+		got, err := filterZones(zones, tst.include, tst.exclude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(got, ",") != strings.Join(tst.want, ",") {
+			t.Errorf("filterZones(%q, %q) = %v, want %v", tst.include, tst.exclude, got, tst.want)
+		}
+	}
+	if _, err := filterZones(zones, "[", ""); err == nil {
+		t.Error("expected an error for an invalid glob")
+	}
+}
+
+type fakeZoneProvider struct {
+	providers.None
+	mu            sync.Mutex
+	running, peak int
+}
+
+func (p *fakeZoneProvider) GetZoneRecords(domain string) (models.Records, error) {
+	p.mu.Lock()
+	p.running++
+	if p.running > p.peak {
+		p.peak = p.running
+	}
+	p.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	p.mu.Lock()
+	p.running--
+	p.mu.Unlock()
+	if domain == "bad.com" {
+		return nil, fmt.Errorf("no such zone")
+	}
+	rc := &models.RecordConfig{Type: "A"}
+	rc.SetLabel("@", domain)
+	return models.Records{rc}, nil
+}
+
+func TestFetchZones(t *testing.T) {
+	var zones []string
+	for i := 0; i < 20; i++ {
+		zones = append(zones, fmt.Sprintf("z%d.com", i))
+	}
+
+	for _, tst := range []struct {
+		concurrency, limit, wantPeak int
+	}{
+		{1, 8, 1},
+		{8, 1, 1},
+		{8, 3, 3},
+	} {
+		p := &fakeZoneProvider{}
+		limits := newProviderLimiter(map[string]map[string]string{
+			"p": {providerConcurrencyFieldName: fmt.Sprint(tst.limit)},
+		})
+		recs, err := fetchZones(p, "p", zones, tst.concurrency, limits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, zone := range zones {
+			if recs[i][0].GetLabelFQDN() != zone {
+				t.Errorf("zone %d: got records of %s, want %s", i, recs[i][0].GetLabelFQDN(), zone)
+			}
+		}
+		if p.peak != tst.wantPeak {
+			t.Errorf("concurrency %d, limit %d: %d downloads at once, want %d", tst.concurrency, tst.limit, p.peak, tst.wantPeak)
+		}
+	}
+
+	_, err := fetchZones(&fakeZoneProvider{}, "p", []string{"a.com", "bad.com"}, 2, nil)
+	if err == nil || !strings.Contains(err.Error(), "bad.com") {
+		t.Errorf("expected an error naming bad.com, got %v", err)
+	}
+}
+
+func TestGetZoneTag(t *testing.T) {
+	want, err := os.ReadFile("test_data/simple.com.zone.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tst := range []struct {
+		zone, tag string
+	}{
+		{"simple.com!inside", ""},
+		{"simple.com", "inside"},
+		{"simple.com!inside", "outside"},
+	} {
+		out := t.TempDir() + "/out.js"
+		gzargs := GetZoneArgs{
+			ZoneNames:    []string{tst.zone},
+			OutputFormat: "js",
+			OutputFile:   out,
+			CredName:     "bind",
+			ProviderName: "BIND",
+			Tag:          tst.tag,
+		}
+		gzargs.CredsFile = "test_data/bind-creds.json"
+		if err := GetZone(gzargs); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w := strings.Replace(string(want), `D("simple.com"`, `D("simple.com!inside"`, 1); string(got) != w {
+			t.Errorf("%s with --tag=%q:\n%s", tst.zone, tst.tag, diff.LineDiff(w, string(got)))
+		}
+	}
+}
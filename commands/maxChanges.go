@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/v3/pkg/previewcache"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+)
+
+// --max-changes and --max-changes-per-domain guard against a bad edit of
+// dnsconfig.js (a typo in a variable used by many domains, a D_EXTEND
+// that no longer matches, ...) that would delete most of the records.
+//
+// preview simply fails if the limits are exceeded. push first computes
+// the corrections without running them, as preview does, and only
+// proceeds if they are within the limits. It then runs the corrections
+// of the DNS providers that the dry run computed, rather than
+// downloading the zones again. --force disables the limits.
+
+// limitsEnabled reports whether the corrections must be counted.
+func (args *PreviewArgs) limitsEnabled() bool {
+	return !args.Force && (args.MaxChanges > 0 || args.MaxChangesPerDomain > 0)
+}
+
+// checkMaxChanges returns an error if the number of corrections of each
+// domain (perDomain) exceeds the limits.
+func checkMaxChanges(args PreviewArgs, perDomain map[string]int) error {
+	if !args.limitsEnabled() {
+		return nil
+	}
+	total := 0
+	var over []string
+	for domain, n := range perDomain {
+		total += n
+		if args.MaxChangesPerDomain > 0 && n > args.MaxChangesPerDomain {
+			over = append(over, fmt.Sprintf("%s (%d)", domain, n))
+		}
+	}
+	sort.Strings(over)
+	if args.MaxChanges > 0 && total > args.MaxChanges {
+		return fmt.Errorf("%d corrections exceed --max-changes=%d (use --force to proceed anyway)", total, args.MaxChanges)
+	}
+	if len(over) > 0 {
+		return fmt.Errorf("the corrections of %s exceed --max-changes-per-domain=%d (use --force to proceed anyway)", strings.Join(over, ", "), args.MaxChangesPerDomain)
+	}
+	return nil
+}
+
+// dryRunMaxChanges computes the corrections that push would run for
+// domains, without running them, and returns an error if they exceed
+// the limits. The corrections are then printed, marked as not run.
+// Otherwise, it returns the plan that push takes the corrections of the
+// DNS providers from.
+func dryRunMaxChanges(args PreviewArgs, domains []*models.DomainConfig, providerConfigs map[string]map[string]string, out printer.CLI) (*correctionPlan, error) {
+	plan := &correctionPlan{zones: map[planKey]plannedZone{}}
+	args.plan = plan
+	args.stats = nil
+	// runDomain adds the NS records to the domain, so the dry run uses
+	// copies of the domains.
+	copies := make([]*models.DomainConfig, len(domains))
+	for i, domain := range domains {
+		d := *domain
+		d.Records = append(models.Records(nil), domain.Records...)
+		copies[i] = &d
+	}
+	collector := &correctionCollector{}
+	var buf bytes.Buffer
+	dout := printer.ConsolePrinter{Writer: &buf, Verbose: printer.DefaultPrinter.Verbose}
+	if args.Concurrency > 1 {
+		if _, _, err := runConcurrently(args, copies, false, providerConfigs, dout, collector); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, domain := range copies {
+			if _, _, err := runDomain(args, domain, false, nil, dout, collector, nil); err != nil {
+				return nil, fmt.Errorf("%s: %w", domain.UniqueName, err)
+			}
+		}
+	}
+
+	perDomain := map[string]int{}
+	for _, c := range collector.items {
+		perDomain[c.Domain]++
+	}
+	if err := checkMaxChanges(args, perDomain); err != nil {
+		for _, c := range collector.items {
+			out.Printf("DRY-RUN %s (%s): %s\n", c.Domain, c.Provider, c.Message)
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+// correctionPlan holds the corrections of the DNS providers that the
+// dry run of push computed. The corrections of the registrars aren't
+// kept: they depend on the DS records that the DNS providers publish
+// once their corrections have run.
+type correctionPlan struct {
+	mu    sync.Mutex
+	zones map[planKey]plannedZone
+}
+
+type planKey struct{ domain, provider string }
+
+type plannedZone struct {
+	dc          *models.DomainConfig // As GetDomainCorrections left it.
+	corrections []*models.Correction
+}
+
+// domainCorrections returns getDomainCorrections(cache, provider, dc)
+// and the dc that they were computed from. During the dry run (push is
+// false), it records them; during the push, it returns the ones that
+// the dry run recorded, if any. plan may be nil.
+func (plan *correctionPlan) domainCorrections(push bool, cache *previewcache.Cache, domain string, provider *models.DNSProviderInstance, dc *models.DomainConfig) (*models.DomainConfig, []*models.Correction, error) {
+	if plan == nil {
+		corrections, err := getDomainCorrections(cache, provider, dc)
+		return dc, corrections, err
+	}
+	key := planKey{domain: domain, provider: provider.Name}
+	plan.mu.Lock()
+	planned, ok := plan.zones[key]
+	delete(plan.zones, key)
+	plan.mu.Unlock()
+	if push && ok {
+		return planned.dc, planned.corrections, nil
+	}
+	corrections, err := getDomainCorrections(cache, provider, dc)
+	if !push && err == nil {
+		plan.mu.Lock()
+		plan.zones[key] = plannedZone{dc: dc, corrections: corrections}
+		plan.mu.Unlock()
+	}
+	return dc, corrections, err
+}
+
+// changeCounter is a Notifier that counts the corrections of each
+// domain and passes them on.
+type changeCounter struct {
+	notifications.Notifier
+	mu        sync.Mutex
+	perDomain map[string]int
+}
+
+func (c *changeCounter) Notify(domain, provider, msg string, err error, preview bool) {
+	c.mu.Lock()
+	c.perDomain[domain]++
+	c.mu.Unlock()
+	c.Notifier.Notify(domain, provider, msg, err, preview)
+}
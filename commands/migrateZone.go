@@ -0,0 +1,295 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	"github.com/StackExchange/dnscontrol/v3/pkg/nameservers"
+	"github.com/StackExchange/dnscontrol/v3/pkg/normalize"
+	"github.com/StackExchange/dnscontrol/v3/pkg/prettyzone"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/miekg/dns"
+	"github.com/urfave/cli/v2"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args MigrateZoneArgs
+	return &cli.Command{
+		Name:  "migrate-zone",
+		Usage: "copy a zone from one provider to another (stand-alone)",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 1 || args.From == "" || args.To == "" {
+				return cli.Exit("Arguments should be: --from credkey --to credkey zone (Ex: --from r53 --to cfmain example.com)", 1)
+			}
+			args.Zone = ctx.Args().First()
+			return exit(MigrateZone(args, printer.DefaultPrinter))
+		},
+		Flags:     args.flags(),
+		UsageText: "dnscontrol migrate-zone [command options] --from credkey --to credkey zone",
+		Description: `Download a zone from one provider, rewrite the records that the other
+provider can't serve as they are, and print the dnsconfig.js stanza for
+the zone at the other provider followed by the corrections that would
+copy the zone there. With --push, create the zone at the other provider
+(if it supports create-domains) and run the corrections.
+
+The records are rewritten where possible: ALIAS and ANAME are the same
+record, an R53_ALIAS becomes an ALIAS if the other provider supports
+them, and an ALIAS that isn't at the apex becomes a CNAME if it doesn't.
+The records that can't be rewritten and the provider-specific settings
+of the records (like cloudflare_proxy) are dropped with a warning. The
+SOA and apex NS records are left to the other provider.
+
+The delegation of the zone is not changed.
+
+EXAMPLES:
+   dnscontrol migrate-zone --from r53 --to cfmain example.com
+   dnscontrol migrate-zone --from r53 --to cfmain --out=example.com.js example.com
+   dnscontrol migrate-zone --from r53 --to cfmain --push example.com`,
+	}
+}())
+
+// MigrateZoneArgs contains all data/flags needed to run migrate-zone, independently of CLI.
+type MigrateZoneArgs struct {
+	GetCredentialsArgs
+	From       string // creds.json key of the provider to copy the zone from
+	To         string // creds.json key of the provider to copy the zone to
+	Zone       string // The zone to migrate
+	OutputFile string // Filename to write the stanza to ("" means stdout)
+	Push       bool   // Create the zone and run the corrections
+}
+
+func (args *MigrateZoneArgs) flags() []cli.Flag {
+	flags := args.GetCredentialsArgs.flags()
+	flags = append(flags, &cli.StringFlag{
+		Name:        "from",
+		Destination: &args.From,
+		Usage:       `The key in creds.json of the provider to copy the zone from`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "to",
+		Destination: &args.To,
+		Usage:       `The key in creds.json of the provider to copy the zone to`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "out",
+		Destination: &args.OutputFile,
+		Usage:       `Instead of stdout, write the dnsconfig.js stanza to this file`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "push",
+		Destination: &args.Push,
+		Usage:       `Create the zone and run the corrections instead of only printing them`,
+	})
+	return flags
+}
+
+// MigrateZone copies a zone from one provider to another.
+func MigrateZone(args MigrateZoneArgs, out printer.CLI) error {
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return fmt.Errorf("failed MigrateZone LoadProviderConfigs(%q): %w", args.CredsFile, err)
+	}
+	fromType, toType := providerConfigs[args.From]["TYPE"], providerConfigs[args.To]["TYPE"]
+	if fromType == "" || toType == "" {
+		return fmt.Errorf("%q and %q must be keys of %s with a TYPE", args.From, args.To, args.CredsFile)
+	}
+	from, err := providers.CreateDNSProvider(fromType, providerConfigs[args.From], nil)
+	if err != nil {
+		return fmt.Errorf("failed MigrateZone CDP(%q): %w", args.From, err)
+	}
+	records, err := from.GetZoneRecords(args.Zone)
+	if err != nil {
+		return fmt.Errorf("failed MigrateZone GetZoneRecords(%q): %w", args.Zone, err)
+	}
+
+	records, warnings, err := rewriteRecords(records, fromType, toType)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		out.Warnf("%s\n", w)
+	}
+
+	// The types are taken from creds.json, like the stanza does.
+	cfg := &models.DNSConfig{
+		Registrars:   []*models.RegistrarConfig{{Name: "none", Type: "-"}},
+		DNSProviders: []*models.DNSProviderConfig{{Name: args.To, Type: "-"}},
+		Domains: []*models.DomainConfig{{
+			Name:             args.Zone,
+			RegistrarName:    "none",
+			DNSProviderNames: map[string]int{args.To: -1},
+			Records:          records,
+		}},
+	}
+
+	w := os.Stdout
+	if args.OutputFile != "" {
+		w, err = os.Create(args.OutputFile)
+		if err != nil {
+			return fmt.Errorf("failed MigrateZone Create(%q): %w", args.OutputFile, err)
+		}
+		defer w.Close()
+	}
+	writeStanza(w, args.To, args.Zone, records)
+
+	// The corrections are computed like push would with the stanza.
+	cfg, err = preloadProviders(cfg)
+	if err != nil {
+		return err
+	}
+	notifier, err := InitializeProviders(cfg, providerConfigs, false)
+	if err != nil {
+		return err
+	}
+	defer notifier.Done()
+	errs := normalize.ValidateAndNormalizeConfig(cfg)
+	if PrintValidationErrors(errs) {
+		return fmt.Errorf("exiting due to validation errors")
+	}
+	dc := cfg.Domains[0]
+	provider := dc.DNSProviderInstances[0]
+
+	if args.Push {
+		if creator, ok := provider.Driver.(providers.DomainCreator); ok {
+			out.Printf("Ensuring zone %q exists at %q\n", dc.Name, args.To)
+			if err := creator.EnsureDomainExists(dc.Name); err != nil {
+				return fmt.Errorf("failed MigrateZone EnsureDomainExists(%q): %w", dc.Name, err)
+			}
+		}
+	}
+	if dc.Nameservers, err = nameservers.DetermineNameserversForProviders(dc, dc.DNSProviderInstances); err != nil {
+		return err
+	}
+	out.StartDomain(dc.UniqueName)
+	out.StartDNSProvider(provider.Name, false)
+	corrections, err := provider.Driver.GetDomainCorrections(dc)
+	out.EndProvider(len(corrections), err)
+	if err != nil {
+		return err
+	}
+	anyErrors := printOrRunCorrections(dc.Name, provider.Name, corrections, out, args.Push, nil, false, notifier, nil)
+	if anyErrors {
+		return fmt.Errorf("completed with errors")
+	}
+	return nil
+}
+
+// aliasTypes are the record types that point the apex at a hostname,
+// and the capability a provider needs to serve them.
+var aliasTypes = map[string]providers.Capability{
+	"ALIAS":       providers.CanUseAlias,
+	"AZURE_ALIAS": providers.CanUseAzureAlias,
+	"R53_ALIAS":   providers.CanUseRoute53Alias,
+}
+
+// typeCapabilities are the other record types that not all providers
+// can serve.
+var typeCapabilities = map[string]providers.Capability{
+	"AKAMAICDN": providers.CanUseAKAMAICDN,
+	"CAA":       providers.CanUseCAA,
+	"DS":        providers.CanUseDSForChildren,
+	"HTTPS":     providers.CanUseHTTPS,
+	"LOC":       providers.CanUseLOC,
+	"NAPTR":     providers.CanUseNAPTR,
+	"PTR":       providers.CanUsePTR,
+	"SRV":       providers.CanUseSRV,
+	"SSHFP":     providers.CanUseSSHFP,
+	"SVCB":      providers.CanUseSVCB,
+	"TLSA":      providers.CanUseTLSA,
+	"URI":       providers.CanUseURI,
+}
+
+// rewriteRecords rewrites the records of a zone at a provider of type
+// fromType so that a provider of type toType can serve them. It returns
+// the records, without the SOA and apex NS records, and warnings about
+// what couldn't be kept.
+func rewriteRecords(records models.Records, fromType, toType string) (models.Records, []string, error) {
+	can := func(c providers.Capability) bool { return providers.ProviderHasCapability(toType, c) }
+	var kept models.Records
+	var warnings []string
+	drop := func(rc *models.RecordConfig, why string) {
+		warnings = append(warnings, fmt.Sprintf("%s %s %s dropped: %s", rc.GetLabelFQDN(), rc.Type, rc.GetTargetCombined(), why))
+	}
+
+	for _, rc := range records {
+		if rc.Type == "SOA" || (rc.Type == "NS" && rc.GetLabel() == "@") {
+			continue
+		}
+		rc, err := rc.Copy()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if rc.Type == "ANAME" {
+			rc.Type = "ALIAS"
+		}
+		if rc.Type == "R53_ALIAS" && toType != fromType && can(providers.CanUseAlias) {
+			warnings = append(warnings, fmt.Sprintf("%s R53_ALIAS %s converted to ALIAS", rc.GetLabelFQDN(), rc.GetTargetField()))
+			rc.Type = "ALIAS"
+			rc.R53Alias = nil
+		}
+		if c, ok := aliasTypes[rc.Type]; ok && !can(c) {
+			if rc.Type != "ALIAS" || rc.GetLabel() == "@" {
+				drop(rc, toType+" does not support "+rc.Type)
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf("%s ALIAS %s converted to CNAME", rc.GetLabelFQDN(), rc.GetTargetField()))
+			rc.Type = "CNAME"
+		}
+		if c, ok := typeCapabilities[rc.Type]; ok && !can(c) && !(rc.Type == "DS" && can(providers.CanUseDS)) {
+			drop(rc, toType+" does not support "+rc.Type)
+			continue
+		}
+		if models.IsRFC3597Type(rc.Type) && !can(providers.CanUseRFC3597) {
+			drop(rc, toType+" does not support RFC 3597 records")
+			continue
+		}
+		_, isAlias := aliasTypes[rc.Type]
+		if _, ok := dns.StringToType[rc.Type]; !ok && !isAlias && !models.IsRFC3597Type(rc.Type) && toType != fromType {
+			// CF_REDIRECT and the other pseudo-types of one provider.
+			drop(rc, "it is specific to "+fromType)
+			continue
+		}
+
+		if toType != fromType && len(rc.Metadata) != 0 {
+			var keys []string
+			for k, v := range rc.Metadata {
+				if v != "" && v != "false" && v != "off" {
+					keys = append(keys, k+"="+v)
+				}
+			}
+			sort.Strings(keys)
+			if len(keys) != 0 {
+				warnings = append(warnings, fmt.Sprintf("%s %s: %s dropped: %s-specific", rc.GetLabelFQDN(), rc.Type, strings.Join(keys, ", "), fromType))
+			}
+			rc.Metadata = nil
+		}
+		kept = append(kept, rc)
+	}
+	return kept, warnings, nil
+}
+
+// writeStanza writes the dnsconfig.js stanza of a zone at the provider
+// credName, like get-zones --format=js.
+func writeStanza(w io.Writer, credName, zone string, records models.Records) {
+	dspVariableName := "DSP_" + strings.ToUpper(credName)
+	fmt.Fprintf(w, `var %s = NewDnsProvider("%s");`+"\n", dspVariableName, credName)
+	fmt.Fprintf(w, `var REG_CHANGEME = NewRegistrar("none");`+"\n")
+
+	sep := ",\n\t"
+	o := []string{fmt.Sprintf("DnsProvider(%s)", dspVariableName)}
+	defaultTTL := prettyzone.MostCommonTTL(records)
+	if defaultTTL != models.DefaultTTL && defaultTTL != 0 {
+		o = append(o, fmt.Sprintf("DefaultTTL(%d)", defaultTTL))
+	}
+	for _, rec := range prettyzone.PrettySort(records, zone, 0, nil).Records {
+		o = append(o, formatDsl(zone, rec, defaultTTL))
+	}
+	fmt.Fprintf(w, `D("%s", REG_CHANGEME%s%s`+"\n)\n", zone, sep, strings.Join(o, sep))
+}
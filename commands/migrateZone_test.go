@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	_ "github.com/StackExchange/dnscontrol/v3/providers/bind"
+)
+
+func TestRewriteRecords(t *testing.T) {
+	rec := func(label, typ, target string, meta map[string]string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: typ, TTL: 300, Metadata: meta}
+		rc.SetLabel(label, "example.com")
+		rc.SetTarget(target)
+		return rc
+	}
+	records := models.Records{
+		rec("@", "SOA", "ns1.example.net.", nil),
+		rec("@", "NS", "ns1.example.net.", nil),
+		rec("@", "ALIAS", "lb.example.net.", nil),
+		rec("www", "ANAME", "lb.example.net.", nil),
+		rec("@", "A", "1.2.3.4", map[string]string{"cloudflare_proxy": "true"}),
+		rec("old", "CF_REDIRECT", "https://example.com/", nil),
+		rec("mail", "MX", "mx.example.net.", map[string]string{"cloudflare_proxy": "off"}),
+		rec("exp", "TYPE65534", `\# 1 00`, nil),
+	}
+
+	got, warnings, err := rewriteRecords(records, "CLOUDFLAREAPI", "BIND")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []string
+	for _, rc := range got {
+		kept = append(kept, rc.GetLabel()+" "+rc.Type)
+		if rc.Metadata != nil {
+			t.Errorf("%s %s kept its metadata %v", rc.GetLabel(), rc.Type, rc.Metadata)
+		}
+	}
+	if want := "www CNAME, @ A, mail MX, exp TYPE65534"; strings.Join(kept, ", ") != want {
+		t.Errorf("rewriteRecords() kept %q, want %q", strings.Join(kept, ", "), want)
+	}
+	want := []string{
+		"example.com ALIAS lb.example.net. dropped: BIND does not support ALIAS",
+		"www.example.com ALIAS lb.example.net. converted to CNAME",
+		"example.com A: cloudflare_proxy=true dropped: CLOUDFLAREAPI-specific",
+		"old.example.com CF_REDIRECT https://example.com/ dropped: it is specific to CLOUDFLAREAPI",
+	}
+	if strings.Join(warnings, "\n") != strings.Join(want, "\n") {
+		t.Errorf("rewriteRecords() warnings = %q, want %q", warnings, want)
+	}
+
+	// The original records are left alone.
+	if records[3].Type != "ANAME" || records[4].Metadata == nil {
+		t.Errorf("rewriteRecords() changed its argument")
+	}
+}
@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// --modified-since limits preview and push to the domains whose
+// configuration changed since a git revision, so that CI runs of a
+// large configuration only look at the domains touched by a commit.
+//
+// The configuration is run both as it is and as it was at the
+// revision, and the domains are compared after that. A domain is thus
+// modified whether the change is in its D(), in a macro or a file that
+// it uses, or in the definition of one of its providers.
+
+// modifiedDomains returns the unique names of the domains whose
+// configuration differs from the one at the git revision ref.
+func modifiedDomains(args GetDNSConfigArgs, ref string) (map[string]bool, error) {
+	if args.JSONFile != "" {
+		return nil, fmt.Errorf("can't be used with --ir")
+	}
+	current, err := ExecuteDSL(args.ExecuteDSLArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := filepath.Abs(args.JSFile)
+	if err != nil {
+		return nil, err
+	}
+	if file, err = filepath.EvalSymlinks(file); err != nil {
+		return nil, err
+	}
+	out, err := git(filepath.Dir(file), "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	top := strings.TrimSpace(string(out))
+	rel, err := filepath.Rel(top, file)
+	if err != nil {
+		return nil, err
+	}
+
+	// The whole tree is extracted, for the files that the configuration
+	// loads with require() and the like.
+	dir, err := os.MkdirTemp("", "dnscontrol-modified-since-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	archive, err := git(top, "archive", "--format=tar", ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := extractTar(archive, dir); err != nil {
+		return nil, err
+	}
+
+	var previous *models.DNSConfig
+	oldArgs := args.ExecuteDSLArgs
+	oldArgs.JSFile = filepath.Join(dir, rel)
+	if _, err := os.Stat(oldArgs.JSFile); err == nil {
+		if previous, err = ExecuteDSL(oldArgs); err != nil {
+			return nil, fmt.Errorf("configuration at %s: %w", ref, err)
+		}
+	} else {
+		// The configuration is new: all the domains are.
+		previous = &models.DNSConfig{}
+	}
+
+	before, err := domainsJSON(previous)
+	if err != nil {
+		return nil, err
+	}
+	after, err := domainsJSON(current)
+	if err != nil {
+		return nil, err
+	}
+	modified := map[string]bool{}
+	for name, js := range after {
+		if !bytes.Equal(js, before[name]) {
+			modified[name] = true
+		}
+	}
+	return modified, nil
+}
+
+// domainsJSON returns the JSON of each domain of cfg, with its
+// registrar and DNS providers, by unique name.
+func domainsJSON(cfg *models.DNSConfig) (map[string][]byte, error) {
+	registrars := map[string]*models.RegistrarConfig{}
+	for _, r := range cfg.Registrars {
+		registrars[r.Name] = r
+	}
+	providers := map[string]*models.DNSProviderConfig{}
+	for _, p := range cfg.DNSProviders {
+		providers[p.Name] = p
+	}
+
+	m := map[string][]byte{}
+	for _, dc := range cfg.Domains {
+		v := struct {
+			Domain    *models.DomainConfig
+			Registrar *models.RegistrarConfig
+			Providers []*models.DNSProviderConfig
+		}{Domain: dc, Registrar: registrars[dc.RegistrarName]}
+		var names []string
+		for name := range dc.DNSProviderNames {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v.Providers = append(v.Providers, providers[name])
+		}
+		js, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		m[models.UniqueDomainName(dc.Name, dc.Tag)] = js
+	}
+	return m, nil
+}
+
+// git runs git in dir and returns its output.
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// extractTar extracts the directories and regular files of a tar
+// archive into dir.
+func extractTar(archive []byte, dir string) error {
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(name, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path in the archive: %s", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(name, 0o700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+				return err
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(name, data, 0o600); err != nil {
+				return err
+			}
+		}
+	}
+}
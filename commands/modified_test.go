@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestModifiedDomains(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(args ...string) {
+		if _, err := git(dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("dnsconfig.js", `
+var REG = NewRegistrar("none");
+var DNS = NewDnsProvider("bind");
+require("./mx.js");
+D("a.com", REG, DnsProvider(DNS), A("@", "1.2.3.4"));
+D("b.com", REG, DnsProvider(DNS), MXES);
+D("c.com", REG, DnsProvider(DNS), A("@", "1.2.3.4"));
+`)
+	write("mx.js", `var MXES = MX("@", 10, "mx.example.com.");`)
+	run("init", "-q")
+	run("add", ".")
+	run("commit", "-q", "-m", "first")
+
+	write("dnsconfig.js", `
+var REG = NewRegistrar("none");
+var DNS = NewDnsProvider("bind");
+require("./mx.js");
+D("a.com", REG, DnsProvider(DNS), A("@", "1.2.3.4"));
+D("b.com", REG, DnsProvider(DNS), MXES);
+D("c.com", REG, DnsProvider(DNS), A("@", "1.2.3.5"));
+D("d.com!internal", REG, DnsProvider(DNS));
+`)
+	write("mx.js", `var MXES = MX("@", 20, "mx.example.com.");`)
+
+	args := GetDNSConfigArgs{ExecuteDSLArgs: ExecuteDSLArgs{JSFile: filepath.Join(dir, "dnsconfig.js")}}
+	got, err := modifiedDomains(args, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"b.com": true, "c.com": true, "d.com!internal": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := modifiedDomains(args, "no-such-revision"); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
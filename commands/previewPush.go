@@ -1,18 +1,31 @@
 package commands
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	"github.com/StackExchange/dnscontrol/v3/pkg/delegation"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/dnssec"
+	"github.com/StackExchange/dnscontrol/v3/pkg/dualhost"
+	"github.com/StackExchange/dnscontrol/v3/pkg/glue"
 	"github.com/StackExchange/dnscontrol/v3/pkg/nameservers"
 	"github.com/StackExchange/dnscontrol/v3/pkg/normalize"
 	"github.com/StackExchange/dnscontrol/v3/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/v3/pkg/previewcache"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/registrarmeta"
+	"github.com/StackExchange/dnscontrol/v3/pkg/snapshot"
+	"github.com/StackExchange/dnscontrol/v3/pkg/zonetags"
 	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/mattn/go-isatty"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/exp/slices"
 )
@@ -38,6 +51,44 @@ type PreviewArgs struct {
 	WarnChanges bool
 	NoPopulate  bool
 	Full        bool
+	Concurrency int
+	DiffMode    string
+	// CheckDelegation compares the NS records of the DNS providers with
+	// the delegation in the parent zone.
+	CheckDelegation bool
+	// CheckDualHost compares the records that the DNS providers of each
+	// domain will serve. See pkg/dualhost.
+	CheckDualHost bool
+	// MaxChanges and MaxChangesPerDomain limit the number of
+	// corrections (0 means no limit), unless Force is set.
+	MaxChanges          int
+	MaxChangesPerDomain int
+	Force               bool
+	// SnapshotDir and SnapshotFormat (push only) save the records of
+	// each zone before it is changed. See pkg/snapshot.
+	SnapshotDir    string
+	SnapshotFormat string
+	// CacheDir saves the corrections of the zones that haven't changed
+	// between previews. NoCache clears it first. See cache.go.
+	CacheDir string
+	NoCache  bool
+	// ReportUnmanaged warns about the zones at the DNS providers that
+	// aren't in dnsconfig.js.
+	ReportUnmanaged bool
+	// Report is "stats" to print the time and the API requests of each
+	// domain. See stats.go.
+	Report string
+	// Verify (push only) queries the nameservers of each DNS provider,
+	// after its corrections have run, for up to VerifySample of the
+	// RRsets that changed. See pkg/verify.
+	Verify         bool
+	VerifyTimeout  time.Duration
+	VerifyInterval time.Duration
+	VerifySample   int
+
+	cache *previewcache.Cache // opened by run
+	stats *statsReport        // created by run for --report stats
+	plan  *correctionPlan     // created by push for --max-changes
 }
 
 func (args *PreviewArgs) flags() []cli.Flag {
@@ -64,6 +115,63 @@ func (args *PreviewArgs) flags() []cli.Flag {
 		Destination: &args.Full,
 		Usage:       `Add headings, providers names, notifications of no changes, etc`,
 	})
+	flags = append(flags, &cli.IntFlag{
+		Name:        "concurrency",
+		Destination: &args.Concurrency,
+		Value:       1,
+		Usage:       `Number of domains (and, with providers that allow it, corrections of a zone) to process in parallel. Output is still printed in order`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "diffmode",
+		Destination: &args.DiffMode,
+		Value:       "full",
+		Usage:       `How to report corrections: full (one per line, as the provider words them) or compact (grouped by label, in color on a terminal)`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "check-delegation",
+		Destination: &args.CheckDelegation,
+		Usage:       `Warn if the NS records of the DNS providers don't match the delegation in the parent zone (queries the parent zone's nameservers)`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "check-dual-host",
+		Destination: &args.CheckDualHost,
+		Usage:       `Warn about the records that won't be the same at all the DNS providers of a domain (disables --cache-dir)`,
+	})
+	flags = append(flags, &cli.IntFlag{
+		Name:        "max-changes",
+		Destination: &args.MaxChanges,
+		Usage:       `Fail, without making any changes, if there are more than this many corrections in total`,
+	})
+	flags = append(flags, &cli.IntFlag{
+		Name:        "max-changes-per-domain",
+		Destination: &args.MaxChangesPerDomain,
+		Usage:       `Fail, without making any changes, if a domain has more than this many corrections`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "force",
+		Destination: &args.Force,
+		Usage:       `Ignore --max-changes and --max-changes-per-domain`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "cache-dir",
+		Destination: &args.CacheDir,
+		Usage:       `Reuse the corrections of the zones that haven't changed since the previous preview, saved in this directory (only some providers; push clears it)`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "no-cache",
+		Destination: &args.NoCache,
+		Usage:       `Clear the --cache-dir before running`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "report-unmanaged",
+		Destination: &args.ReportUnmanaged,
+		Usage:       `Warn about the zones that exist at the DNS providers but not in dnsconfig.js (only providers that can list their zones)`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "report",
+		Destination: &args.Report,
+		Usage:       `Print a report at the end. stats: the time, API calls, retries and cache hits of each domain`,
+	})
 	return flags
 }
 
@@ -90,7 +198,41 @@ func (args *PushArgs) flags() []cli.Flag {
 	flags = append(flags, &cli.BoolFlag{
 		Name:        "i",
 		Destination: &args.Interactive,
-		Usage:       "Interactive. Confirm or Exclude each correction before they run",
+		Usage:       "Interactive. Confirm or Exclude each correction before they run: y(es), n(o), a(ll remaining), q(uit)",
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "snapshot-dir",
+		Destination: &args.SnapshotDir,
+		Usage:       `Before changing a zone, save its records in a new timestamped directory in this directory (see "dnscontrol rollback")`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "snapshot-format",
+		Destination: &args.SnapshotFormat,
+		Value:       "json",
+		Usage:       `Format of the snapshots: json (keeps all the record types and metadata) or zone`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "verify",
+		Destination: &args.Verify,
+		Usage:       `After the corrections of a zone, check that the nameservers of its DNS provider serve the changed records; fail if they don't by --verify-timeout`,
+	})
+	flags = append(flags, &cli.DurationFlag{
+		Name:        "verify-timeout",
+		Destination: &args.VerifyTimeout,
+		Value:       2 * time.Minute,
+		Usage:       `How long --verify waits for the nameservers to serve the changes`,
+	})
+	flags = append(flags, &cli.DurationFlag{
+		Name:        "verify-interval",
+		Destination: &args.VerifyInterval,
+		Value:       10 * time.Second,
+		Usage:       `Time between two rounds of --verify queries`,
+	})
+	flags = append(flags, &cli.IntFlag{
+		Name:        "verify-sample",
+		Destination: &args.VerifySample,
+		Value:       10,
+		Usage:       `Number of changed RRsets of each zone that --verify checks (0 for all)`,
 	})
 	return flags
 }
@@ -112,6 +254,45 @@ func run(args PreviewArgs, push bool, interactive bool, out printer.CLI) error {
 	// This is a hack until we have the new printer replacement.
 	printer.SkinnyReport = !args.Full
 
+	if interactive && args.Concurrency > 1 {
+		return fmt.Errorf("-i can not be used with --concurrency")
+	}
+	if push && args.Verify && (interactive || args.Only != "" || args.Skip != "") {
+		// The corrections left out would be reported as not served.
+		return fmt.Errorf("--verify can not be used with -i, --only or --skip")
+	}
+	switch args.DiffMode {
+	case "", "full", "compact":
+	default:
+		return fmt.Errorf("--diffmode must be full or compact, not %q", args.DiffMode)
+	}
+	if err := args.checkKinds(); err != nil {
+		return err
+	}
+	switch args.Report {
+	case "":
+	case "stats":
+		args.stats = newStatsReport()
+	default:
+		return fmt.Errorf("--report must be stats, not %q", args.Report)
+	}
+	if push && args.SnapshotDir != "" {
+		if !slices.Contains(snapshot.Formats, args.SnapshotFormat) {
+			return fmt.Errorf("--snapshot-format must be one of %s, not %q", strings.Join(snapshot.Formats, ", "), args.SnapshotFormat)
+		}
+		args.SnapshotDir = snapshot.NewDir(args.SnapshotDir, time.Now())
+		out.Printf("Saving snapshots in %s\n", args.SnapshotDir)
+	}
+	if args.ModifiedSince != "" {
+		modified, err := modifiedDomains(args.GetDNSConfigArgs, args.ModifiedSince)
+		if err != nil {
+			return fmt.Errorf("--modified-since: %w", err)
+		}
+		args.modified = modified
+		out.Printf("%d domains changed since %s.\n", len(modified), args.ModifiedSince)
+	}
+	args.cache = openCache(args, push, out)
+
 	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
 	if err != nil {
 		return err
@@ -124,107 +305,477 @@ func run(args PreviewArgs, push bool, interactive bool, out printer.CLI) error {
 	if err != nil {
 		return err
 	}
+	// Batched notifiers send their notifications in Done(), which must
+	// run even when a domain fails.
+	defer notifier.Done()
+	var counter *changeCounter
+	if !push && args.limitsEnabled() {
+		counter = &changeCounter{Notifier: notifier, perDomain: map[string]int{}}
+		notifier = counter
+	}
 
 	errs := normalize.ValidateAndNormalizeConfig(cfg)
 	if PrintValidationErrors(errs) {
 		return fmt.Errorf("exiting due to validation errors")
 	}
-	anyErrors := false
-	totalCorrections := 0
-DomainLoop:
+	var domains []*models.DomainConfig
 	for _, domain := range cfg.Domains {
-		if !args.shouldRunDomain(domain.UniqueName) {
-			continue
+		if args.shouldRunDomain(domain) {
+			domains = append(domains, domain)
 		}
-		out.StartDomain(domain.UniqueName)
-		var providersWithExistingZone []*models.DNSProviderInstance
-		for _, provider := range domain.DNSProviderInstances {
-
-			if !args.NoPopulate {
-				// preview run: check if zone is already there, if not print a warning
-				if lister, ok := provider.Driver.(providers.ZoneLister); ok && !push {
-					zones, err := lister.ListZones()
-					if err != nil {
-						return err
-					}
-					if !slices.Contains(zones, domain.Name) {
-						out.Warnf("Domain '%s' does not exist in the '%s' profile and will be added automatically.\n", domain.Name, provider.Name)
-						continue // continue with next provider, as we can not determine corrections without an existing zone
-					}
-				} else if creator, ok := provider.Driver.(providers.DomainCreator); ok && push {
-					// this is the actual push, ensure domain exists at DSP
-					if err := creator.EnsureDomainExists(domain.Name); err != nil {
-						out.Warnf("Error creating domain: %s\n", err)
-						continue // continue with next provider, as we couldn't create this one
-					}
-				}
-			}
-			providersWithExistingZone = append(providersWithExistingZone, provider)
+	}
+
+	if push && args.limitsEnabled() {
+		if args.plan, err = dryRunMaxChanges(args, domains, providerConfigs, out); err != nil {
+			return err
 		}
+	}
+
+	var ask *approval
+	if interactive {
+		ask = &approval{}
+	}
 
-		nsList, err := nameservers.DetermineNameserversForProviders(domain, providersWithExistingZone)
+	var totalCorrections int
+	var anyErrors bool
+	if args.Concurrency > 1 {
+		totalCorrections, anyErrors, err = runConcurrently(args, domains, push, providerConfigs, out, notifier)
 		if err != nil {
 			return err
 		}
-		domain.Nameservers = nsList
-		nameservers.AddNSRecords(domain)
-
-		for _, provider := range providersWithExistingZone {
-			dc, err := domain.Copy()
+	} else {
+		for _, domain := range domains {
+			n, domainErrs, err := runDomain(args, domain, push, ask, out, notifier, nil)
 			if err != nil {
 				return err
 			}
-			shouldrun := args.shouldRunProvider(provider.Name, dc)
-			out.StartDNSProvider(provider.Name, !shouldrun)
-			if !shouldrun {
-				continue
+			totalCorrections += n
+			anyErrors = domainErrs || anyErrors
+			if ask.quitting() {
+				break
 			}
+		}
+	}
+	if args.ReportUnmanaged {
+		anyErrors = reportUnmanagedZones(args, cfg, out) || anyErrors
+	}
+	if os.Getenv("TEAMCITY_VERSION") != "" {
+		fmt.Fprintf(os.Stderr, "##teamcity[buildStatus status='SUCCESS' text='%d corrections']", totalCorrections)
+	}
+	out.Printf("Done. %d corrections.\n", totalCorrections)
+	if args.stats != nil {
+		var names []string
+		for _, domain := range domains {
+			names = append(names, domain.UniqueName)
+		}
+		args.stats.print(names, out)
+	}
+	if anyErrors {
+		return fmt.Errorf("completed with errors")
+	}
+	if counter != nil {
+		if err := checkMaxChanges(args, counter.perDomain); err != nil {
+			return err
+		}
+	}
+	if totalCorrections != 0 && args.WarnChanges {
+		return fmt.Errorf("there are pending changes")
+	}
+	return nil
+}
 
-			/// This is where we should audit?
+// runDomain computes (and if push is true, runs) the corrections for
+// one domain. It returns the number of corrections and whether any
+// errors were reported. err is only set for errors that should stop
+// the entire run.
+//
+// ask is nil unless the user confirms each correction (push -i).
+//
+// limits may be nil. If not, it is used to limit how many goroutines
+// may use each provider at the same time.
+func runDomain(args PreviewArgs, domain *models.DomainConfig, push bool, ask *approval, out printer.CLI, notifier notifications.Notifier, limits *providerLimiter) (totalCorrections int, anyErrors bool, err error) {
+	defer args.stats.track(domain.UniqueName)()
+	out.StartDomain(domain.UniqueName)
+	var providersWithExistingZone []*models.DNSProviderInstance
+	for _, provider := range domain.DNSProviderInstances {
 
-			corrections, err := provider.Driver.GetDomainCorrections(dc)
-			out.EndProvider(len(corrections), err)
-			if err != nil {
-				anyErrors = true
-				continue DomainLoop
+		if !args.NoPopulate {
+			release := limits.acquire(provider.Name)
+			// preview run: check if zone is already there, if not print a warning
+			if lister, ok := provider.Driver.(providers.ZoneLister); ok && !push {
+				zones, err := lister.ListZones()
+				if err != nil {
+					release()
+					return 0, false, err
+				}
+				if !slices.Contains(zones, domain.Name) {
+					release()
+					out.Warnf("Domain '%s' does not exist in the '%s' profile and will be added automatically.\n", domain.Name, provider.Name)
+					continue // continue with next provider, as we can not determine corrections without an existing zone
+				}
+			} else if creator, ok := provider.Driver.(providers.DomainCreator); ok && push {
+				// this is the actual push, ensure domain exists at DSP
+				if err := creator.EnsureDomainExists(domain.Name); err != nil {
+					release()
+					out.Warnf("Error creating domain: %s\n", err)
+					continue // continue with next provider, as we couldn't create this one
+				}
 			}
-			totalCorrections += len(corrections)
-			anyErrors = printOrRunCorrections(domain.Name, provider.Name, corrections, out, push, interactive, notifier) || anyErrors
+			release()
 		}
-		run := args.shouldRunProvider(domain.RegistrarName, domain)
-		out.StartRegistrar(domain.RegistrarName, !run)
-		if !run {
-			continue
+		providersWithExistingZone = append(providersWithExistingZone, provider)
+	}
+
+	var names []string
+	for _, provider := range providersWithExistingZone {
+		names = append(names, provider.Name)
+	}
+	release := limits.acquire(names...)
+	nsList, err := nameservers.DetermineNameserversForProviders(domain, providersWithExistingZone)
+	release()
+	if err != nil {
+		return 0, false, err
+	}
+	domain.Nameservers = nsList
+	nameservers.AddNSRecords(domain)
+
+	// The records that each provider will serve, for --check-dual-host.
+	// Corrections from the cache don't say, so it isn't used then.
+	var zones []dualhost.Zone
+	cache := args.cache
+	if args.CheckDualHost {
+		cache = nil
+	}
+	for _, provider := range providersWithExistingZone {
+		if ask.quitting() {
+			return totalCorrections, anyErrors, nil
+		}
+		dc, err := domain.Copy()
+		if err != nil {
+			return 0, false, err
 		}
-		if len(domain.Nameservers) == 0 && domain.Metadata["no_ns"] != "true" {
-			out.Warnf("No nameservers declared; skipping registrar. Add {no_ns:'true'} to force.\n")
+		shouldrun := args.shouldRunProvider(provider.Name, dc)
+		out.StartDNSProvider(provider.Name, !shouldrun)
+		if !shouldrun {
 			continue
 		}
-		dc, err := domain.Copy()
+
+		/// This is where we should audit?
+
+		release := limits.acquire(provider.Name)
+		dc, corrections, err := args.plan.domainCorrections(push, cache, domain.UniqueName, provider, dc)
+		// The zone tags aren't records: they are set outside of the
+		// zone update below.
+		var tagCorrections []*models.Correction
+		if err == nil {
+			tagCorrections, err = getZoneTagCorrections(provider.Driver, dc)
+		}
+		var skipped, skippedTags int
+		if err == nil {
+			corrections, skipped, err = args.filterCorrections(corrections)
+		}
+		if err == nil {
+			tagCorrections, skippedTags, err = args.filterCorrections(tagCorrections)
+			skipped += skippedTags
+		}
+		out.EndProvider(len(corrections)+len(tagCorrections), err)
 		if err != nil {
-			log.Fatal(err)
+			release()
+			return totalCorrections, true, nil
+		}
+		printSkipped(out, domain.UniqueName, provider.Name, skipped)
+		zones = append(zones, dualhost.Zone{Provider: provider.Name, Records: dc.Records})
+		totalCorrections += len(corrections) + len(tagCorrections)
+		// The zone before the push, for --snapshot-dir and --verify.
+		var before models.Records
+		fetched := push && (args.SnapshotDir != "" || args.Verify) && len(corrections) > 0
+		if fetched {
+			if before, err = takeSnapshot(args, domain.Name, provider); err != nil {
+				release()
+				out.Warnf("Not changing %s at %s: %s\n", domain.UniqueName, provider.Name, err)
+				anyErrors = true
+				continue
+			}
+		}
+		compact := args.DiffMode == "compact"
+		// The record changes of the zone are applied together, or not at
+		// all, if the provider can.
+		var zu *zoneUpdate
+		if tx, ok := provider.Driver.(providers.ZoneTransactioner); ok && push && len(corrections) > 0 {
+			if err := tx.BeginZoneUpdate(dc.Name); err != nil {
+				release()
+				out.Warnf("Not changing %s at %s: %s\n", domain.UniqueName, provider.Name, err)
+				anyErrors = true
+				continue
+			}
+			zu = &zoneUpdate{tx: tx, zone: dc.Name, compact: compact && ask == nil}
+		}
+		var failed bool
+		if push && args.Concurrency > 1 && providers.ProviderHasCapability(provider.ProviderType, providers.CanConcurrentlyModify) {
+			failed = runCorrectionsConcurrently(domain.UniqueName, provider.Name, corrections, out, args.Concurrency, compact, notifier, zu)
+		} else {
+			failed = printOrRunCorrections(domain.UniqueName, provider.Name, corrections, out, push, ask, compact, notifier, zu)
 		}
-		corrections, err := domain.RegistrarInstance.Driver.GetRegistrarCorrections(dc)
-		out.EndProvider(len(corrections), err)
+		if zu != nil {
+			failed = endZoneUpdate(zu, domain.UniqueName, provider.Name, failed, out, notifier)
+		}
+		failed = printOrRunCorrections(domain.UniqueName, provider.Name, tagCorrections, out, push, ask, compact, notifier, nil) || failed
+		anyErrors = failed || anyErrors
+		release()
+		if fetched && args.Verify {
+			anyErrors = verifyZone(args, dc, provider, before, out) || anyErrors
+		}
+	}
+	if args.CheckDualHost {
+		for _, w := range dualhost.Check(domain.UniqueName, zones) {
+			out.Warnf("DUAL HOST: %s\n", w)
+		}
+	}
+	if args.CheckDelegation {
+		warnings, err := delegation.Check(domain, delegation.Lookup)
 		if err != nil {
-			anyErrors = true
+			out.Warnf("%s\n", err)
+		}
+		for _, w := range warnings {
+			out.Warnf("DELEGATION: %s\n", w)
+		}
+	}
+	if ask.quitting() {
+		return totalCorrections, anyErrors, nil
+	}
+	run := args.shouldRunProvider(domain.RegistrarName, domain)
+	out.StartRegistrar(domain.RegistrarName, !run)
+	if !run {
+		return totalCorrections, anyErrors, nil
+	}
+	if len(domain.Nameservers) == 0 && domain.Metadata["no_ns"] != "true" {
+		out.Warnf("No nameservers declared; skipping registrar. Add {no_ns:'true'} to force.\n")
+		return totalCorrections, anyErrors, nil
+	}
+	release = limits.acquire(names...)
+	ds, err := dnssec.DetermineDSRecordsForProviders(domain, providersWithExistingZone)
+	release()
+	if err != nil {
+		out.Warnf("Error getting DS records: %s\n", err)
+		anyErrors = true
+	}
+	domain.DSRecords = ds
+	dc, err := domain.Copy()
+	if err != nil {
+		log.Fatal(err)
+	}
+	release = limits.acquire(domain.RegistrarName)
+	defer release()
+	// The host objects come first: the delegation may use them.
+	corrections, err := getHostCorrections(domain.RegistrarInstance.Driver, dc)
+	if err == nil {
+		var nsCorrections []*models.Correction
+		nsCorrections, err = domain.RegistrarInstance.Driver.GetRegistrarCorrections(dc)
+		corrections = append(corrections, nsCorrections...)
+	}
+	if err == nil {
+		var metaCorrections []*models.Correction
+		metaCorrections, err = getDomainMetaCorrections(domain.RegistrarInstance.Driver, dc)
+		corrections = append(corrections, metaCorrections...)
+	}
+	var skipped int
+	if err == nil {
+		corrections, skipped, err = args.filterCorrections(corrections)
+	}
+	out.EndProvider(len(corrections), err)
+	if err != nil {
+		return totalCorrections, true, nil
+	}
+	printSkipped(out, domain.UniqueName, domain.RegistrarName, skipped)
+	totalCorrections += len(corrections)
+	anyErrors = printOrRunCorrections(domain.UniqueName, domain.RegistrarName, corrections, out, push, ask, args.DiffMode == "compact", notifier, nil) || anyErrors
+	return totalCorrections, anyErrors, nil
+}
+
+// printSkipped reports the corrections that --only or --skip left out.
+func printSkipped(out printer.CLI, domain, provider string, skipped int) {
+	if skipped == 0 {
+		return
+	}
+	plural := "s"
+	if skipped == 1 {
+		plural = ""
+	}
+	out.Printf("%s at %s: %d correction%s skipped by --only/--skip\n", domain, provider, skipped, plural)
+}
+
+// getDomainMetaCorrections returns the corrections of the registrar
+// settings requested by the domain metadata (see package
+// pkg/registrarmeta).
+func getDomainMetaCorrections(reg models.Registrar, dc *models.DomainConfig) ([]*models.Correction, error) {
+	if c, ok := reg.(providers.DomainMetaCorrector); ok {
+		return c.GetDomainMetaCorrections(dc)
+	}
+	desired, err := registrarmeta.Desired(dc)
+	if err != nil {
+		return nil, err
+	}
+	if len(desired) > 0 {
+		return nil, fmt.Errorf("this registrar can't manage the %s metadata", strings.Join(registrarmeta.Keys, "/"))
+	}
+	return nil, nil
+}
+
+// getHostCorrections returns the corrections of the host objects
+// (glue records) of the nameservers of the domain (see package
+// pkg/glue).
+func getHostCorrections(reg models.Registrar, dc *models.DomainConfig) ([]*models.Correction, error) {
+	if c, ok := reg.(providers.HostCorrector); ok {
+		return c.GetHostCorrections(dc)
+	}
+	if hosts := glue.Hosts(dc); len(hosts) > 0 {
+		return nil, fmt.Errorf("this registrar can't manage the glue records of %s", strings.Join(hosts, ", "))
+	}
+	return nil, nil
+}
+
+// getZoneTagCorrections returns the corrections of the tags of the
+// hosted zone requested by the domain metadata (see package
+// pkg/zonetags).
+func getZoneTagCorrections(dsp models.DNSProvider, dc *models.DomainConfig) ([]*models.Correction, error) {
+	if c, ok := dsp.(providers.ZoneTagCorrector); ok {
+		return c.GetZoneTagCorrections(dc)
+	}
+	desired, err := zonetags.Desired(dc)
+	if err != nil {
+		return nil, err
+	}
+	if desired != nil {
+		return nil, fmt.Errorf("this DNS provider can't manage the %s metadata", zonetags.Key)
+	}
+	return nil, nil
+}
+
+// collectedCorrection is a correction found by collectCorrections.
+type collectedCorrection struct {
+	Domain   string `json:"domain"`
+	Provider string `json:"provider"`
+	Message  string `json:"message"`
+	Error    string `json:"error,omitempty"`
+}
+
+// correctionCollector is a Notifier that records the corrections it
+// is given.
+type correctionCollector struct {
+	items []collectedCorrection
+}
+
+func (c *correctionCollector) Notify(domain, provider, msg string, err error, preview bool) {
+	item := collectedCorrection{Domain: domain, Provider: provider, Message: msg}
+	if err != nil {
+		item.Error = err.Error()
+	}
+	c.items = append(c.items, item)
+}
+
+func (c *correctionCollector) Done() {}
+
+// collectCorrections is like run, except that the corrections are
+// returned rather than printed. The output that preview or push would
+// have printed is returned in output. Validation warnings are added to
+// output; validation errors are returned as err.
+func collectCorrections(args PreviewArgs, push bool) (corrections []collectedCorrection, output string, anyErrors bool, err error) {
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return nil, "", false, err
+	}
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if _, err := InitializeProviders(cfg, providerConfigs, false); err != nil {
+		return nil, "", false, err
+	}
+
+	var buf bytes.Buffer
+	var fatal []string
+	for _, err := range normalize.ValidateAndNormalizeConfig(cfg) {
+		if _, ok := err.(normalize.Warning); ok {
+			fmt.Fprintf(&buf, "WARNING: %s\n", err)
+		} else {
+			fatal = append(fatal, err.Error())
+		}
+	}
+	if len(fatal) != 0 {
+		return nil, buf.String(), false, fmt.Errorf("validation errors: %s", strings.Join(fatal, "; "))
+	}
+
+	collector := &correctionCollector{}
+	out := printer.ConsolePrinter{Writer: &buf, Verbose: printer.DefaultPrinter.Verbose}
+	for _, domain := range cfg.Domains {
+		if !args.shouldRunDomain(domain) {
 			continue
 		}
-		totalCorrections += len(corrections)
-		anyErrors = printOrRunCorrections(domain.Name, domain.RegistrarName, corrections, out, push, interactive, notifier) || anyErrors
+		_, domainErrs, err := runDomain(args, domain, push, nil, out, collector, nil)
+		if err != nil {
+			return collector.items, buf.String(), true, fmt.Errorf("%s: %w", domain.UniqueName, err)
+		}
+		anyErrors = domainErrs || anyErrors
 	}
-	if os.Getenv("TEAMCITY_VERSION") != "" {
-		fmt.Fprintf(os.Stderr, "##teamcity[buildStatus status='SUCCESS' text='%d corrections']", totalCorrections)
+	return collector.items, buf.String(), anyErrors, nil
+}
+
+// domainResult holds the buffered output and the results of running
+// runDomain() for one domain.
+type domainResult struct {
+	output           bytes.Buffer
+	totalCorrections int
+	anyErrors        bool
+	err              error
+	done             chan struct{}
+}
+
+// runConcurrently runs runDomain() for up to args.Concurrency domains
+// at a time. The output of each domain is buffered and printed in the
+// order the domains appear in dnsconfig.js, so that it is the same as
+// a serial run.
+func runConcurrently(args PreviewArgs, domains []*models.DomainConfig, push bool, providerConfigs map[string]map[string]string, out printer.CLI, notifier notifications.Notifier) (totalCorrections int, anyErrors bool, err error) {
+	limits := newProviderLimiter(providerConfigs)
+	limits.allowRateLimited(domains, args.Concurrency)
+	notifier = &lockedNotifier{n: notifier}
+
+	results := make([]*domainResult, len(domains))
+	for i := range results {
+		results[i] = &domainResult{done: make(chan struct{})}
 	}
-	notifier.Done()
-	out.Printf("Done. %d corrections.\n", totalCorrections)
-	if anyErrors {
-		return fmt.Errorf("completed with errors")
+
+	jobs := make(chan int)
+	for w := 0; w < args.Concurrency; w++ {
+		go func() {
+			for i := range jobs {
+				r := results[i]
+				dout := printer.ConsolePrinter{
+					Writer:  &r.output,
+					Verbose: printer.DefaultPrinter.Verbose,
+					JSON:    printer.DefaultPrinter.JSON,
+				}
+				r.totalCorrections, r.anyErrors, r.err = runDomain(args, domains[i], push, nil, dout, notifier, limits)
+				close(r.done)
+			}
+		}()
 	}
-	if totalCorrections != 0 && args.WarnChanges {
-		return fmt.Errorf("there are pending changes")
+	go func() {
+		for i := range domains {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	for _, r := range results {
+		<-r.done
+		out.Printf("%s", r.output.String())
+		if r.err != nil && err == nil {
+			err = r.err
+		}
+		totalCorrections += r.totalCorrections
+		anyErrors = r.anyErrors || anyErrors
 	}
-	return nil
+	return totalCorrections, anyErrors, err
 }
 
 // InitializeProviders takes (fully processed) configuration and instantiates all providers and returns them.
@@ -477,20 +1028,49 @@ func refineProviderType(credEntryName string, t string, credFields map[string]st
 
 }
 
-func printOrRunCorrections(domain string, provider string, corrections []*models.Correction, out printer.CLI, push bool, interactive bool, notifier notifications.Notifier) (anyErrors bool) {
+// printOrRunCorrections prints the corrections and, if push is true,
+// runs them. With compact, the corrections are printed as one report
+// (see diff2.CompactReport) and, when pushing, only the corrections
+// that fail (or that -i asks about) are printed individually.
+//
+// ask is nil unless the user confirms each correction (push -i). zu is
+// nil unless the corrections run in a zone update: the corrections that
+// it queues are reported by endZoneUpdate.
+func printOrRunCorrections(domain string, provider string, corrections []*models.Correction, out printer.CLI, push bool, ask *approval, compact bool, notifier notifications.Notifier, zu *zoneUpdate) (anyErrors bool) {
 	anyErrors = false
 	if len(corrections) == 0 {
 		return false
 	}
+	interactive := ask != nil
+	if compact {
+		out.Printf("%s", diff2.CompactReport(corrections, useColor(out)))
+	}
 	for i, correction := range corrections {
-		out.PrintCorrection(i, correction)
+		if ask.quitting() {
+			break
+		}
+		// In a zone update, a correction is printed with its result, as
+		// that may only be known once the update ends.
+		printed := false
+		if (!compact || interactive) && (zu == nil || interactive || correction.F == nil) {
+			out.PrintCorrection(i, correction)
+			printed = true
+		}
 		var err error
-		if push {
-			if interactive && !out.PromptToRun() {
+		if push && correction.F != nil {
+			if interactive && !ask.confirm(out) {
 				continue
 			}
 			err = correction.F()
-			out.EndCorrection(err)
+			if zu.queue(i, correction, err) {
+				continue
+			}
+			if !printed && (!compact || err != nil) {
+				out.PrintCorrection(i, correction)
+			}
+			if !compact || interactive || err != nil {
+				out.EndCorrection(err)
+			}
 			if err != nil {
 				anyErrors = true
 			}
@@ -499,3 +1079,133 @@ func printOrRunCorrections(domain string, provider string, corrections []*models
 	}
 	return anyErrors
 }
+
+// zoneUpdate is an update of a zone at a providers.ZoneTransactioner,
+// between BeginZoneUpdate and endZoneUpdate.
+type zoneUpdate struct {
+	tx      providers.ZoneTransactioner
+	zone    string
+	compact bool // Print only the corrections that fail.
+	queued  []queuedCorrection
+	applied int // The corrections that made their changes at once.
+}
+
+// queuedCorrection is a correction whose F returned providers.ErrQueued.
+type queuedCorrection struct {
+	i int
+	c *models.Correction
+}
+
+// queue records the result err of the F of correction, the i-th of the
+// zone, and reports whether the correction was queued. If so, its
+// result is the outcome of the update. zu may be nil.
+func (zu *zoneUpdate) queue(i int, correction *models.Correction, err error) bool {
+	if zu == nil {
+		return false
+	}
+	switch {
+	case errors.Is(err, providers.ErrQueued):
+		zu.queued = append(zu.queued, queuedCorrection{i: i, c: correction})
+		return true
+	case err == nil:
+		zu.applied++
+	}
+	return false
+}
+
+// errRolledBack is the result of the queued corrections of a zone
+// update that was rolled back.
+var errRolledBack = errors.New("not applied: another correction failed, so the zone update was rolled back")
+
+// endZoneUpdate commits the changes that the corrections of the zone
+// update zu queued, or rolls them back if any of the corrections
+// failed, then reports the result of each queued correction. It
+// reports whether there were errors.
+func endZoneUpdate(zu *zoneUpdate, domain, provider string, failed bool, out printer.CLI, notifier notifications.Notifier) bool {
+	var result error
+	if failed {
+		if err := zu.tx.RollbackZoneUpdate(zu.zone); err != nil {
+			out.Warnf("%s at %s: can't roll back the zone update: %s\n", domain, provider, err)
+			result = fmt.Errorf("can't roll back the zone update: %w", err)
+		} else if len(zu.queued) > 0 {
+			msg := fmt.Sprintf("%s at %s: a correction failed, so the %d queued corrections were rolled back", domain, provider, len(zu.queued))
+			if zu.applied > 0 {
+				msg += fmt.Sprintf("; the %d corrections that ran at once were not undone", zu.applied)
+			}
+			out.Warnf("%s\n", msg)
+			result = errRolledBack
+		}
+	} else if err := zu.tx.CommitZoneUpdate(zu.zone); err != nil {
+		out.Warnf("%s at %s: the %d queued corrections were not applied: %s\n", domain, provider, len(zu.queued), err)
+		result = err
+		failed = true
+	}
+	for _, q := range zu.queued {
+		if !zu.compact || result != nil {
+			out.PrintCorrection(q.i, q.c)
+			out.EndCorrection(result)
+		}
+		notifier.Notify(domain, provider, q.c.Msg, result, false)
+	}
+	return failed
+}
+
+// takeSnapshot returns the records of domain at provider and, with
+// --snapshot-dir, saves them in the snapshot directory.
+func takeSnapshot(args PreviewArgs, domain string, provider *models.DNSProviderInstance) (models.Records, error) {
+	records, err := provider.Driver.GetZoneRecords(domain)
+	if err != nil {
+		return nil, fmt.Errorf("can't read the zone: %w", err)
+	}
+	if args.SnapshotDir == "" {
+		return records, nil
+	}
+	if _, err := snapshot.Write(args.SnapshotDir, domain, provider.Name, records, args.SnapshotFormat); err != nil {
+		return nil, fmt.Errorf("can't take a snapshot: %w", err)
+	}
+	return records, nil
+}
+
+// approval remembers the answers of push -i that apply to all the
+// remaining corrections.
+type approval struct {
+	all  bool // Run the remaining corrections without asking
+	quit bool // Skip the remaining corrections
+}
+
+// confirm asks the user whether to run a correction, unless an earlier
+// answer applies.
+func (a *approval) confirm(out printer.CLI) bool {
+	if a.quit {
+		return false
+	}
+	if a.all {
+		return true
+	}
+	switch out.PromptToRun() {
+	case printer.AnswerYes:
+		return true
+	case printer.AnswerAll:
+		a.all = true
+		return true
+	case printer.AnswerQuit:
+		a.quit = true
+	}
+	return false
+}
+
+// quitting reports whether the user asked to skip the remaining
+// corrections. a may be nil.
+func (a *approval) quitting() bool {
+	return a != nil && a.quit
+}
+
+// useColor reports whether out writes to a terminal that wants color.
+func useColor(out printer.CLI) bool {
+	cp, ok := out.(*printer.ConsolePrinter)
+	if !ok {
+		return false
+	}
+	f, ok := cp.Writer.(*os.File)
+	return ok && isatty.IsTerminal(f.Fd()) && os.Getenv("NO_COLOR") == ""
+}
@@ -1,8 +1,16 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/providers"
 )
 
 func Test_refineProviderType(t *testing.T) {
@@ -56,3 +64,295 @@ func Test_refineProviderType(t *testing.T) {
 		})
 	}
 }
+
+func Test_printOrRunCorrectionsCompact(t *testing.T) {
+	ran := 0
+	corrections := []*models.Correction{
+		{Msg: "CREATE www.example.com A 1.2.3.4", F: func() error { ran++; return nil }},
+		{Msg: "DELETE old.example.com A 5.6.7.8", F: func() error { ran++; return fmt.Errorf("boom") }},
+	}
+	buf := &bytes.Buffer{}
+	out := &printer.ConsolePrinter{Reader: bufio.NewReader(strings.NewReader("")), Writer: buf}
+
+	anyErrors := printOrRunCorrections("example.com", "bind", corrections, out, true, nil, true, notifications.Init(nil), nil)
+	if !anyErrors || ran != 2 {
+		t.Errorf("anyErrors = %v, ran = %d", anyErrors, ran)
+	}
+	want := `old.example.com
+  - A 5.6.7.8
+www.example.com
+  + A 1.2.3.4
+1 to create, 0 to change, 1 to delete
+#2: DELETE old.example.com A 5.6.7.8
+FAILURE! boom
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func Test_printOrRunCorrectionsInteractive(t *testing.T) {
+	var ran []int
+	var corrections []*models.Correction
+	for i := 0; i < 6; i++ {
+		i := i
+		corrections = append(corrections, &models.Correction{
+			Msg: fmt.Sprintf("CREATE r%d.example.com A 1.2.3.%d", i, i),
+			F:   func() error { ran = append(ran, i); return nil },
+		})
+	}
+	buf := &bytes.Buffer{}
+	out := &printer.ConsolePrinter{Reader: bufio.NewReader(strings.NewReader("y\nn\nx\na\n")), Writer: buf}
+	ask := &approval{}
+
+	printOrRunCorrections("example.com", "bind", corrections[:4], out, true, ask, false, notifications.Init(nil), nil)
+	printOrRunCorrections("example.com", "bind", corrections[4:], out, true, ask, false, notifications.Init(nil), nil)
+	if fmt.Sprint(ran) != "[0 3 4 5]" {
+		t.Errorf("ran %v, want [0 3 4 5]", ran)
+	}
+
+	// q skips the remaining corrections, as does the end of the input.
+	for _, input := range []string{"y\nq\ny\n", "y\n"} {
+		ran = nil
+		out.Reader = bufio.NewReader(strings.NewReader(input))
+		ask = &approval{}
+		printOrRunCorrections("example.com", "bind", corrections, out, true, ask, false, notifications.Init(nil), nil)
+		if fmt.Sprint(ran) != "[0]" || !ask.quitting() {
+			t.Errorf("%q: ran %v, want [0] and quitting", input, ran)
+		}
+	}
+}
+
+// fakeZoneTransactioner records how the zone updates end.
+type fakeZoneTransactioner struct {
+	ended     []string
+	commitErr error
+}
+
+func (f *fakeZoneTransactioner) BeginZoneUpdate(domain string) error { return nil }
+func (f *fakeZoneTransactioner) CommitZoneUpdate(domain string) error {
+	f.ended = append(f.ended, "commit")
+	return f.commitErr
+}
+func (f *fakeZoneTransactioner) RollbackZoneUpdate(domain string) error {
+	f.ended = append(f.ended, "rollback")
+	return nil
+}
+
+func Test_endZoneUpdate(t *testing.T) {
+	queued := &models.Correction{Msg: "CREATE www.example.com A 1.2.3.4", F: func() error { return providers.ErrQueued }}
+	atOnce := &models.Correction{Msg: "Enable DNSSEC", F: func() error { return nil }}
+	failing := &models.Correction{Msg: "DELETE old.example.com A 5.6.7.8", F: func() error { return fmt.Errorf("boom") }}
+	tests := []struct {
+		name        string
+		corrections []*models.Correction
+		commitErr   error
+		wantEnded   string
+		want        string
+	}{
+		{
+			name:        "commit",
+			corrections: []*models.Correction{queued, atOnce},
+			wantEnded:   "[commit]",
+			want: `#2: Enable DNSSEC
+SUCCESS!
+#1: CREATE www.example.com A 1.2.3.4
+SUCCESS!
+`,
+		},
+		{
+			name:        "commit fails",
+			corrections: []*models.Correction{queued},
+			commitErr:   fmt.Errorf("conflict"),
+			wantEnded:   "[commit]",
+			want: `WARNING: example.com at pdns: the 1 queued corrections were not applied: conflict
+#1: CREATE www.example.com A 1.2.3.4
+FAILURE! conflict
+`,
+		},
+		{
+			name:        "rollback",
+			corrections: []*models.Correction{queued, atOnce, failing},
+			wantEnded:   "[rollback]",
+			want: `#2: Enable DNSSEC
+SUCCESS!
+#3: DELETE old.example.com A 5.6.7.8
+FAILURE! boom
+WARNING: example.com at pdns: a correction failed, so the 1 queued corrections were rolled back; the 1 corrections that ran at once were not undone
+#1: CREATE www.example.com A 1.2.3.4
+FAILURE! ` + errRolledBack.Error() + `
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			out := &printer.ConsolePrinter{Reader: bufio.NewReader(strings.NewReader("")), Writer: buf}
+			tx := &fakeZoneTransactioner{commitErr: tt.commitErr}
+			zu := &zoneUpdate{tx: tx, zone: "example.com"}
+			failed := printOrRunCorrections("example.com", "pdns", tt.corrections, out, true, nil, false, notifications.Init(nil), zu)
+			failed = endZoneUpdate(zu, "example.com", "pdns", failed, out, notifications.Init(nil))
+			if want := tt.commitErr != nil || tt.wantEnded == "[rollback]"; failed != want {
+				t.Errorf("failed = %v, want %v", failed, want)
+			}
+			if got := fmt.Sprint(tx.ended); got != tt.wantEnded {
+				t.Errorf("ended with %s, want %s", got, tt.wantEnded)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkMaxChanges(t *testing.T) {
+	perDomain := map[string]int{"a.com": 3, "b.com": 10, "c.com": 12}
+	tests := []struct {
+		args PreviewArgs
+		want string
+	}{
+		{PreviewArgs{}, ""},
+		{PreviewArgs{MaxChanges: 25}, ""},
+		{PreviewArgs{MaxChanges: 24}, "25 corrections exceed --max-changes=24 (use --force to proceed anyway)"},
+		{PreviewArgs{MaxChanges: 24, Force: true}, ""},
+		{PreviewArgs{MaxChangesPerDomain: 9}, "the corrections of b.com (10), c.com (12) exceed --max-changes-per-domain=9 (use --force to proceed anyway)"},
+		{PreviewArgs{MaxChangesPerDomain: 12}, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if err := checkMaxChanges(tt.args, perDomain); err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("checkMaxChanges(%+v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+// countingProvider counts the calls to GetDomainCorrections.
+type countingProvider struct {
+	providers.None
+	calls int
+}
+
+func (p *countingProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	p.calls++
+	return []*models.Correction{{Msg: fmt.Sprintf("correction %d of %s", p.calls, dc.Name)}}, nil
+}
+
+func Test_correctionPlan(t *testing.T) {
+	driver := &countingProvider{}
+	provider := &models.DNSProviderInstance{Driver: driver}
+	provider.Name = "bind"
+	plan := &correctionPlan{zones: map[planKey]plannedZone{}}
+
+	dryRun := &models.DomainConfig{Name: "example.com"}
+	if _, _, err := plan.domainCorrections(false, nil, "example.com", provider, dryRun); err != nil {
+		t.Fatal(err)
+	}
+	// The push gets the corrections of the dry run, once.
+	for i, want := range []string{"correction 1 of example.com", "correction 2 of example.com"} {
+		dc, corrections, err := plan.domainCorrections(true, nil, "example.com", provider, &models.DomainConfig{Name: "example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := corrections[0].Msg; got != want {
+			t.Errorf("push %d: got %q, want %q", i, got, want)
+		}
+		if i == 0 && dc != dryRun {
+			t.Errorf("push %d: got another dc than the one of the dry run", i)
+		}
+	}
+	if driver.calls != 2 {
+		t.Errorf("GetDomainCorrections was called %d times, want 2", driver.calls)
+	}
+}
+
+func Test_filterCorrections(t *testing.T) {
+	f := func() error { return nil }
+	corrections := []*models.Correction{
+		{Msg: "CREATE A a.example.com 1.2.3.4", F: f, Kind: models.CorrectionCreate},
+		{Msg: "DELETE A b.example.com 1.2.3.4", F: f, Kind: models.CorrectionDestroy},
+		{Msg: "MODIFY A c.example.com: (1.2.3.4) -> (5.6.7.8)", F: f, Kind: models.CorrectionModify},
+		// One API call, reported as three corrections.
+		{Msg: "DELETE A d.example.com 1.2.3.4", Kind: models.CorrectionDestroy},
+		{Msg: "CREATE A e.example.com 1.2.3.4", Kind: models.CorrectionCreate},
+		{Msg: "CREATE A f.example.com 1.2.3.4", F: f, Kind: models.CorrectionCreate},
+		// Nothing says what this does, so it counts as destroy.
+		{Msg: "CREATE A g.example.com 1.2.3.4", F: f},
+		{Msg: "WARNING: something to know"},
+	}
+	indexes := func(cs []*models.Correction) string {
+		var s []string
+		for _, c := range cs {
+			for i := range corrections {
+				if c == corrections[i] {
+					s = append(s, fmt.Sprint(i))
+				}
+			}
+		}
+		return strings.Join(s, ",")
+	}
+
+	for _, tc := range []struct {
+		only, skip  string
+		want        string
+		wantSkipped int
+	}{
+		{"", "", "0,1,2,3,4,5,6,7", 0},
+		{"", "destroy", "0,2,7", 5},
+		{"destroy", "", "1,3,4,5,6", 3},
+		{"info", "", "7", 7},
+		{"create,modify", "modify", "0", 7},
+	} {
+		args := FilterArgs{Only: tc.only, Skip: tc.skip}
+		got, skipped, err := args.filterCorrections(corrections)
+		if err != nil || indexes(got) != tc.want || skipped != tc.wantSkipped {
+			t.Errorf("--only=%q --skip=%q: got %s (%d skipped), want %s (%d skipped)", tc.only, tc.skip, indexes(got), skipped, tc.want, tc.wantSkipped)
+		}
+	}
+
+	if err := (&FilterArgs{Skip: "delete"}).checkKinds(); err == nil {
+		t.Error("--skip delete should fail")
+	}
+	if _, _, err := (&FilterArgs{Only: "create,delete"}).filterCorrections(corrections); err == nil {
+		t.Error("filterCorrections with --only create,delete should fail")
+	}
+}
+
+func TestShouldRunDomain(t *testing.T) {
+	dc := &models.DomainConfig{
+		UniqueName:        "example.com",
+		RegistrarInstance: &models.RegistrarInstance{ProviderBase: models.ProviderBase{Name: "reg", ProviderType: "NONE"}},
+		DNSProviderInstances: []*models.DNSProviderInstance{
+			{ProviderBase: models.ProviderBase{Name: "cf", ProviderType: "CLOUDFLAREAPI", IsDefault: true}},
+			{ProviderBase: models.ProviderBase{Name: "r53", ProviderType: "ROUTE53", IsDefault: true}},
+		},
+	}
+	for _, tc := range []struct {
+		args      FilterArgs
+		want      bool
+		providers string
+	}{
+		{FilterArgs{}, true, "reg,cf,r53"},
+		{FilterArgs{Domains: "example.net"}, false, "reg,cf,r53"},
+		{FilterArgs{ProviderType: "CLOUDFLAREAPI"}, true, "cf"},
+		{FilterArgs{ProviderType: "NONE"}, true, "reg"},
+		{FilterArgs{ProviderType: "BIND"}, false, ""},
+		{FilterArgs{modified: map[string]bool{"example.com": true}}, true, "reg,cf,r53"},
+		{FilterArgs{modified: map[string]bool{}}, false, "reg,cf,r53"},
+	} {
+		if got := tc.args.shouldRunDomain(dc); got != tc.want {
+			t.Errorf("%+v: shouldRunDomain = %v, want %v", tc.args, got, tc.want)
+		}
+		var providers []string
+		for _, name := range []string{"reg", "cf", "r53"} {
+			if tc.args.shouldRunProvider(name, dc) {
+				providers = append(providers, name)
+			}
+		}
+		if got := strings.Join(providers, ","); got != tc.providers {
+			t.Errorf("%+v: providers %q, want %q", tc.args, got, tc.providers)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/js"
+	"github.com/StackExchange/dnscontrol/v3/pkg/lint"
 	"github.com/StackExchange/dnscontrol/v3/pkg/normalize"
 	"github.com/urfave/cli/v2"
 )
@@ -28,6 +29,30 @@ var _ = cmd(catDebug, func() *cli.Command {
 // CheckArgs encapsulates the flags/arguments for the check command.
 type CheckArgs struct {
 	GetDNSConfigArgs
+	Lint       bool
+	LintConfig string
+	LintFormat string
+}
+
+func (args *CheckArgs) flags() []cli.Flag {
+	return append(args.GetDNSConfigArgs.flags(),
+		&cli.BoolFlag{
+			Name:        "lint",
+			Usage:       "Also look for likely mistakes (duplicate records, missing trailing dots, etc.)",
+			Destination: &args.Lint,
+		},
+		&cli.StringFlag{
+			Name:        "lint-config",
+			Usage:       "JSON file that enables, disables and configures the lint rules",
+			Destination: &args.LintConfig,
+		},
+		&cli.StringFlag{
+			Name:        "lint-format",
+			Usage:       "Output format of --lint: text or json",
+			Value:       "text",
+			Destination: &args.LintFormat,
+		},
+	)
 }
 
 var _ = cmd(catDebug, func() *cli.Command {
@@ -57,6 +82,10 @@ var _ = cmd(catDebug, func() *cli.Command {
 			cli.ErrWriter = os.Stdout
 			log.SetOutput(os.Stdout)
 
+			if args.Lint || args.LintConfig != "" {
+				return exit(Lint(args))
+			}
+
 			err := exit(PrintIR(pargs))
 			if err == nil {
 				fmt.Fprintf(os.Stdout, "No errors.\n")
@@ -67,6 +96,61 @@ var _ = cmd(catDebug, func() *cli.Command {
 	}
 }())
 
+// Lint implements "check --lint". The lint rules run on the
+// configuration before it is validated, and the validation errors are
+// reported as findings of the "validation" rule.
+func Lint(args CheckArgs) error {
+	if args.LintFormat != "text" && args.LintFormat != "json" {
+		return fmt.Errorf("unknown --lint-format %q (use text or json)", args.LintFormat)
+	}
+	lintCfg, err := lint.LoadConfig(args.LintConfig)
+	if err != nil {
+		return err
+	}
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return err
+	}
+
+	findings := lint.Run(cfg, lintCfg)
+	for _, err := range normalize.ValidateAndNormalizeConfig(cfg) {
+		f := lint.Finding{Rule: "validation", Severity: lint.Error, Message: err.Error()}
+		if _, ok := err.(normalize.Warning); ok {
+			f.Severity = lint.Warning
+		}
+		findings = append(findings, f)
+	}
+
+	if args.LintFormat == "json" {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		dat, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(dat))
+	} else {
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+	}
+
+	errors := 0
+	for _, f := range findings {
+		if f.Severity == lint.Error {
+			errors++
+		}
+	}
+	if errors != 0 {
+		return fmt.Errorf("%d lint errors", errors)
+	}
+	if args.LintFormat == "text" {
+		fmt.Println("No errors.")
+	}
+	return nil
+}
+
 // PrintIRArgs encapsulates the flags/arguments for the print-ir command.
 type PrintIRArgs struct {
 	GetDNSConfigArgs
@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/capprobe"
+	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/urfave/cli/v2"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args ProbeCapabilitiesArgs
+	return &cli.Command{
+		Name:  "probe-capabilities",
+		Usage: "test which record types a provider supports and print its DocumentationNotes (stand-alone)",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 2 {
+				return cli.Exit("Arguments should be: credkey zone (Ex: r53 sandbox.example.com)", 1)
+			}
+			args.CredName = ctx.Args().Get(0)
+			args.ZoneName = ctx.Args().Get(1)
+			return exit(ProbeCapabilities(args))
+		},
+		Flags:     args.flags(),
+		UsageText: "dnscontrol probe-capabilities [command options] credkey zone",
+		Description: `Create each record type in a sandbox zone, read it back, and print
+the providers.DocumentationNotes table of the provider with the results.
+This is a stand-alone utility for provider maintainers.
+
+WARNING: Every record in the zone, except the NS records at the apex,
+is deleted. Use a zone that exists only for testing.
+
+ARGUMENTS:
+   credkey:  The name used in creds.json (first parameter to NewDnsProvider() in dnsconfig.js)
+   zone:     The sandbox zone
+
+EXAMPLES:
+   dnscontrol probe-capabilities myr53 sandbox.example.com
+   dnscontrol probe-capabilities --only=CanUseCAA,CanUseLOC myr53 sandbox.example.com
+   dnscontrol probe-capabilities --out=notes.go mybind sandbox.example.com`,
+	}
+}())
+
+// ProbeCapabilitiesArgs args required for the probe-capabilities subcommand.
+type ProbeCapabilitiesArgs struct {
+	GetCredentialsArgs
+	CredName   string // key in creds.json
+	ZoneName   string // The sandbox zone
+	Only       string // Comma-separated capabilities to probe; default is all
+	Meta       string // Provider metadata (JSON), as in NewDnsProvider()
+	OutputFile string // Filename to send output ("" means stdout)
+}
+
+func (args *ProbeCapabilitiesArgs) flags() []cli.Flag {
+	flags := args.GetCredentialsArgs.flags()
+	flags = append(flags, &cli.StringFlag{
+		Name:        "only",
+		Destination: &args.Only,
+		Usage:       `Only probe these comma-separated capabilities (Ex: "CanUseCAA,CanUseLOC")`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "meta",
+		Destination: &args.Meta,
+		Usage:       `Provider metadata as JSON (third parameter to NewDnsProvider() in dnsconfig.js)`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "out",
+		Destination: &args.OutputFile,
+		Usage:       `Instead of stdout, write to this file`,
+	})
+	return flags
+}
+
+// ProbeCapabilities contains all data/flags needed to run probe-capabilities, independently of CLI.
+func ProbeCapabilities(args ProbeCapabilitiesArgs) error {
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return fmt.Errorf("failed ProbeCapabilities LoadProviderConfigs(%q): %w", args.CredsFile, err)
+	}
+	cfg, ok := providerConfigs[args.CredName]
+	if !ok {
+		return fmt.Errorf("no entry %q in %s", args.CredName, args.CredsFile)
+	}
+	pType := cfg["TYPE"]
+	if pType == "" {
+		return fmt.Errorf("creds.json entry %q is missing the TYPE field", args.CredName)
+	}
+
+	var meta json.RawMessage
+	if args.Meta != "" {
+		if !json.Valid([]byte(args.Meta)) {
+			return fmt.Errorf("--meta is not valid JSON")
+		}
+		meta = json.RawMessage(args.Meta)
+	}
+	provider, err := providers.CreateDNSProvider(pType, cfg, meta)
+	if err != nil {
+		return fmt.Errorf("failed ProbeCapabilities CDP: %w", err)
+	}
+
+	probes, err := selectProbes(args.Only)
+	if err != nil {
+		return err
+	}
+	results, err := capprobe.Run(pType, provider, args.ZoneName, probes, printer.Printf)
+	if err != nil {
+		return err
+	}
+
+	w := os.Stdout
+	if args.OutputFile != "" {
+		w, err = os.Create(args.OutputFile)
+		if err != nil {
+			return fmt.Errorf("failed ProbeCapabilities Create(%q): %w", args.OutputFile, err)
+		}
+		defer w.Close()
+	}
+	comment := fmt.Sprintf(`Probed by "dnscontrol probe-capabilities" against %s.`, args.ZoneName)
+	return capprobe.WriteGo(w, capprobe.Notes(pType, results), comment)
+}
+
+// selectProbes returns the probes of the comma-separated capabilities
+// in only, or all of them if only is empty.
+func selectProbes(only string) ([]capprobe.Probe, error) {
+	if only == "" {
+		return capprobe.Probes, nil
+	}
+	var probes []capprobe.Probe
+	for _, name := range strings.Split(only, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, p := range capprobe.Probes {
+			if p.Capability.String() == name {
+				probes = append(probes, p)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no probe for capability %q", name)
+		}
+	}
+	return probes, nil
+}
@@ -68,3 +68,19 @@ func TestR53Test_2ttl(t *testing.T) {
 		t.Errorf("makeR53alias failure: got `%s` want `%s`", g, w)
 	}
 }
+
+func TestR53Test_routing(t *testing.T) {
+	rec := models.RecordConfig{
+		Type:     "R53_ALIAS",
+		Name:     "foo",
+		NameFQDN: "foo.domain.tld",
+		Metadata: map[string]string{"r53_weight": "10", "r53_set_identifier": "us"},
+	}
+	rec.SetTarget("bar")
+	rec.R53Alias = make(map[string]string)
+	rec.R53Alias["type"] = "A"
+	w := `R53_ALIAS('foo', 'A', 'bar', {r53_set_identifier: "us", r53_weight: "10"})`
+	if g := makeR53alias(&rec, 0); g != w {
+		t.Errorf("makeR53alias failure: got `%s` want `%s`", g, w)
+	}
+}
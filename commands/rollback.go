@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	"github.com/StackExchange/dnscontrol/v3/pkg/nameservers"
+	"github.com/StackExchange/dnscontrol/v3/pkg/normalize"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/snapshot"
+	"github.com/urfave/cli/v2"
+)
+
+var _ = cmd(catMain, func() *cli.Command {
+	var args RollbackArgs
+	return &cli.Command{
+		Name:  "rollback",
+		Usage: "restore the zones saved by push --snapshot-dir",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 1 {
+				return cli.Exit("Arguments should be: snapshot (Ex: snapshots/20230102-150405)", 1)
+			}
+			args.Snapshot = ctx.Args().First()
+			return exit(Rollback(args, printer.DefaultPrinter))
+		},
+		Flags:     args.flags(),
+		UsageText: "dnscontrol rollback [command options] snapshot",
+		Description: `Compute the corrections that restore the records of each zone in a
+snapshot taken by "dnscontrol push --snapshot-dir", and print them.
+With --push, run them.
+
+The DNS providers are found by the names used in dnsconfig.js, which
+must still list the domains of the snapshot.
+
+EXAMPLES:
+   dnscontrol push --snapshot-dir=snapshots
+   dnscontrol rollback snapshots/20230102-150405
+   dnscontrol rollback --push snapshots/20230102-150405`,
+	}
+}())
+
+// RollbackArgs contains all data/flags needed to run rollback, independently of CLI.
+type RollbackArgs struct {
+	GetDNSConfigArgs
+	GetCredentialsArgs
+	Snapshot string // The snapshot directory
+	Push     bool   // Run the corrections
+}
+
+func (args *RollbackArgs) flags() []cli.Flag {
+	flags := args.GetDNSConfigArgs.flags()
+	flags = append(flags, args.GetCredentialsArgs.flags()...)
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "push",
+		Destination: &args.Push,
+		Usage:       `Run the corrections instead of only printing them`,
+	})
+	return flags
+}
+
+// Rollback restores the zones of a snapshot.
+func Rollback(args RollbackArgs, out printer.CLI) error {
+	zones, err := snapshot.List(args.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return err
+	}
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return err
+	}
+	notifier, err := InitializeProviders(cfg, providerConfigs, false)
+	if err != nil {
+		return err
+	}
+	defer notifier.Done()
+	errs := normalize.ValidateAndNormalizeConfig(cfg)
+	if PrintValidationErrors(errs) {
+		return fmt.Errorf("exiting due to validation errors")
+	}
+
+	var totalCorrections int
+	var anyErrors bool
+	for _, z := range zones {
+		domain := cfg.FindDomain(z.Domain)
+		if domain == nil {
+			return fmt.Errorf("%s: domain %s is not in dnsconfig.js", z.File, z.Domain)
+		}
+		var provider *models.DNSProviderInstance
+		for _, p := range domain.DNSProviderInstances {
+			if p.Name == z.Provider {
+				provider = p
+			}
+		}
+		if provider == nil {
+			return fmt.Errorf("%s: %s has no DNS provider %q in dnsconfig.js", z.File, z.Domain, z.Provider)
+		}
+		records, err := z.Read()
+		if err != nil {
+			return err
+		}
+
+		// The snapshot has the NS records at the apex, but some providers
+		// also look at dc.Nameservers.
+		dc, err := domain.Copy()
+		if err != nil {
+			return err
+		}
+		if dc.Nameservers, err = nameservers.DetermineNameserversForProviders(domain, domain.DNSProviderInstances); err != nil {
+			return err
+		}
+		dc.Records = records
+
+		out.StartDomain(domain.UniqueName)
+		out.StartDNSProvider(provider.Name, false)
+		corrections, err := provider.Driver.GetDomainCorrections(dc)
+		out.EndProvider(len(corrections), err)
+		if err != nil {
+			anyErrors = true
+			continue
+		}
+		totalCorrections += len(corrections)
+		anyErrors = printOrRunCorrections(domain.Name, provider.Name, corrections, out, args.Push, nil, false, notifier, nil) || anyErrors
+	}
+	out.Printf("Done. %d corrections.\n", totalCorrections)
+	if anyErrors {
+		return fmt.Errorf("completed with errors")
+	}
+	return nil
+}
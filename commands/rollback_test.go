@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	_ "github.com/StackExchange/dnscontrol/v3/providers/bind"
+)
+
+func TestSnapshotRollback(t *testing.T) {
+	dir := t.TempDir()
+	zonesDir := filepath.Join(dir, "zones")
+	zonefile := filepath.Join(zonesDir, "example.com.zone")
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o640); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(dir, "creds.json"), `{"none": {"TYPE": "NONE"}, "bind": {"TYPE": "BIND", "directory": "`+zonesDir+`"}}`)
+	writeFile(filepath.Join(dir, "dnsconfig.js"), `
+D("example.com", NewRegistrar("none"), DnsProvider(NewDnsProvider("bind", {"default_ns": ["ns1.example.net."]})),
+  A("new", "5.6.7.8")
+);`)
+	writeFile(zonefile, `$TTL 300
+@  IN SOA ns1.example.net. hostmaster.example.com. 1 3600 600 604800 1440
+@  IN NS ns1.example.net.
+old IN A 1.2.3.4
+`)
+
+	buf := &bytes.Buffer{}
+	out := &printer.ConsolePrinter{Reader: bufio.NewReader(strings.NewReader("")), Writer: buf}
+	args := PreviewArgs{SnapshotDir: filepath.Join(dir, "snapshots"), SnapshotFormat: "json"}
+	args.JSFile = filepath.Join(dir, "dnsconfig.js")
+	args.CredsFile = filepath.Join(dir, "creds.json")
+	if err := run(args, true, false, out); err != nil {
+		t.Fatalf("push: %s\n%s", err, buf)
+	}
+	if content, _ := os.ReadFile(zonefile); strings.Contains(string(content), "old") {
+		t.Fatalf("push didn't change the zone:\n%s", content)
+	}
+	snapshots, _ := filepath.Glob(filepath.Join(dir, "snapshots", "*"))
+	if len(snapshots) != 1 {
+		t.Fatalf("got snapshots %v, want 1", snapshots)
+	}
+
+	buf.Reset()
+	rargs := RollbackArgs{Snapshot: snapshots[0], Push: true}
+	rargs.JSFile = args.JSFile
+	rargs.CredsFile = args.CredsFile
+	if err := Rollback(rargs, out); err != nil {
+		t.Fatalf("rollback: %s\n%s", err, buf)
+	}
+	content, _ := os.ReadFile(zonefile)
+	if !strings.Contains(string(content), "old") || strings.Contains(string(content), "new") {
+		t.Errorf("rollback didn't restore the zone:\n%s", content)
+	}
+}
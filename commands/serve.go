@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/normalize"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/urfave/cli/v2"
+)
+
+var _ = cmd(catMain, func() *cli.Command {
+	var args ServeArgs
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "run an HTTP server that runs preview (and optionally push) on request",
+		Action: func(ctx *cli.Context) error {
+			return exit(Serve(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// ServeArgs contains all data/flags needed to run serve, independently of CLI
+type ServeArgs struct {
+	GetDNSConfigArgs
+	GetCredentialsArgs
+	Listen    string
+	Token     string
+	AllowPush bool
+}
+
+func (args *ServeArgs) flags() []cli.Flag {
+	flags := args.GetDNSConfigArgs.flags()
+	flags = append(flags, args.GetCredentialsArgs.flags()...)
+	flags = append(flags, &cli.StringFlag{
+		Name:        "listen",
+		Destination: &args.Listen,
+		Value:       "127.0.0.1:8053",
+		Usage:       `Address to listen on`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "token",
+		Destination: &args.Token,
+		EnvVars:     []string{"DNSCONTROL_SERVE_TOKEN"},
+		Usage:       `Token that clients must send as "Authorization: Bearer <token>"`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "allow-push",
+		Destination: &args.AllowPush,
+		Usage:       `Enable the /push endpoint`,
+	})
+	return flags
+}
+
+// Serve implements the serve subcommand.
+func Serve(args ServeArgs) error {
+	if args.Token == "" {
+		return fmt.Errorf("--token (or $DNSCONTROL_SERVE_TOKEN) is required")
+	}
+	srv := &apiServer{
+		args:    args,
+		collect: collectCorrections,
+		config: func() (*models.DNSConfig, error) {
+			return GetDNSConfig(args.GetDNSConfigArgs)
+		},
+	}
+	printer.Printf("Listening on %s\n", args.Listen)
+	return http.ListenAndServe(args.Listen, srv.handler())
+}
+
+// apiServer serves the HTTP API. collect and config are fields so that
+// the tests can replace them.
+type apiServer struct {
+	args    ServeArgs
+	collect func(args PreviewArgs, push bool) ([]collectedCorrection, string, bool, error)
+	config  func() (*models.DNSConfig, error)
+
+	// mu allows only one request to run at a time, since preview and
+	// push use global state (and shouldn't race each other anyway).
+	mu sync.Mutex
+}
+
+// apiRequest is the (optional) body of a /preview or /push request.
+type apiRequest struct {
+	Domains   []string `json:"domains"`
+	Providers []string `json:"providers"`
+}
+
+// apiResponse is the response to a /preview or /push request.
+type apiResponse struct {
+	Corrections []collectedCorrection `json:"corrections"`
+	Errors      bool                  `json:"errors"`
+	Output      string                `json:"output"`
+}
+
+type apiError struct {
+	Error  string `json:"error"`
+	Output string `json:"output,omitempty"`
+}
+
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/preview", s.handleRun(false))
+	mux.HandleFunc("/push", s.handleRun(true))
+	mux.HandleFunc("/zones", s.handleZones)
+	mux.HandleFunc("/zones/", s.handleZones)
+	return s.authorize(mux)
+}
+
+// authorize rejects requests without the token.
+func (s *apiServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		token := strings.TrimPrefix(auth, "Bearer ")
+		if token == auth || subtle.ConstantTimeCompare([]byte(token), []byte(s.args.Token)) != 1 {
+			writeAPIJSON(w, http.StatusUnauthorized, apiError{Error: "missing or invalid token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *apiServer) handleRun(push bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeAPIJSON(w, http.StatusMethodNotAllowed, apiError{Error: "use POST"})
+			return
+		}
+		if push && !s.args.AllowPush {
+			writeAPIJSON(w, http.StatusForbidden, apiError{Error: "push is disabled; start the server with --allow-push"})
+			return
+		}
+
+		var req apiRequest
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			writeAPIJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+			return
+		}
+		if len(strings.TrimSpace(string(body))) != 0 {
+			if err := json.Unmarshal(body, &req); err != nil {
+				writeAPIJSON(w, http.StatusBadRequest, apiError{Error: "invalid request: " + err.Error()})
+				return
+			}
+		}
+
+		args := PreviewArgs{
+			GetDNSConfigArgs:   s.args.GetDNSConfigArgs,
+			GetCredentialsArgs: s.args.GetCredentialsArgs,
+			FilterArgs: FilterArgs{
+				Domains:   strings.Join(req.Domains, ","),
+				Providers: strings.Join(req.Providers, ","),
+			},
+		}
+
+		s.mu.Lock()
+		corrections, output, anyErrors, err := s.collect(args, push)
+		s.mu.Unlock()
+		if err != nil {
+			writeAPIJSON(w, http.StatusInternalServerError, apiError{Error: err.Error(), Output: output})
+			return
+		}
+		if corrections == nil {
+			corrections = []collectedCorrection{}
+		}
+		writeAPIJSON(w, http.StatusOK, apiResponse{Corrections: corrections, Errors: anyErrors, Output: output})
+	}
+}
+
+// handleZones serves /zones, the list of domains in dnsconfig.js, and
+// /zones/DOMAIN, the records that dnsconfig.js says the domain should
+// have.
+func (s *apiServer) handleZones(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeAPIJSON(w, http.StatusMethodNotAllowed, apiError{Error: "use GET"})
+		return
+	}
+
+	s.mu.Lock()
+	cfg, err := s.config()
+	var errs []error
+	if err == nil {
+		errs = normalize.ValidateAndNormalizeConfig(cfg)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		writeAPIJSON(w, http.StatusInternalServerError, apiError{Error: err.Error()})
+		return
+	}
+	for _, err := range errs {
+		if _, ok := err.(normalize.Warning); !ok {
+			writeAPIJSON(w, http.StatusInternalServerError, apiError{Error: "validation errors: " + err.Error()})
+			return
+		}
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/zones"), "/")
+	if name == "" {
+		names := []string{}
+		for _, dc := range cfg.Domains {
+			names = append(names, dc.UniqueName)
+		}
+		writeAPIJSON(w, http.StatusOK, names)
+		return
+	}
+	for _, dc := range cfg.Domains {
+		if dc.UniqueName == name {
+			writeAPIJSON(w, http.StatusOK, dc)
+			return
+		}
+	}
+	writeAPIJSON(w, http.StatusNotFound, apiError{Error: fmt.Sprintf("no such domain: %q", name)})
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
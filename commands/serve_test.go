@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func testAPIServer(allowPush bool) (*apiServer, *[]PreviewArgs) {
+	var calls []PreviewArgs
+	srv := &apiServer{
+		args: ServeArgs{Token: "s3cret", AllowPush: allowPush},
+		collect: func(args PreviewArgs, push bool) ([]collectedCorrection, string, bool, error) {
+			calls = append(calls, args)
+			return []collectedCorrection{{Domain: "example.com", Provider: "bind", Message: "+ CREATE a.example.com A 1.2.3.4"}}, "output", false, nil
+		},
+		config: func() (*models.DNSConfig, error) {
+			return &models.DNSConfig{
+				Domains: []*models.DomainConfig{{Name: "example.com", UniqueName: "example.com", RegistrarName: "none"}},
+			}, nil
+		},
+	}
+	return srv, &calls
+}
+
+func TestServeAuth(t *testing.T) {
+	srv, calls := testAPIServer(false)
+	for _, auth := range []string{"", "s3cret", "Bearer wrong", "Basic s3cret"} {
+		req := httptest.NewRequest(http.MethodPost, "/preview", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		srv.handler().ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: got status %d, want %d", auth, w.Code, http.StatusUnauthorized)
+		}
+	}
+	if len(*calls) != 0 {
+		t.Errorf("preview ran without authorization")
+	}
+}
+
+func TestServeRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowPush  bool
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"preview", false, http.MethodPost, "/preview", "", http.StatusOK, `"message": "+ CREATE a.example.com A 1.2.3.4"`},
+		{"preview filtered", false, http.MethodPost, "/preview", `{"domains": ["example.com"]}`, http.StatusOK, `"corrections"`},
+		{"preview bad body", false, http.MethodPost, "/preview", `{`, http.StatusBadRequest, `"invalid request`},
+		{"preview GET", false, http.MethodGet, "/preview", "", http.StatusMethodNotAllowed, `"use POST"`},
+		{"push disabled", false, http.MethodPost, "/push", "", http.StatusForbidden, `--allow-push`},
+		{"push", true, http.MethodPost, "/push", "", http.StatusOK, `"corrections"`},
+		{"zones", false, http.MethodGet, "/zones", "", http.StatusOK, `"example.com"`},
+		{"zone", false, http.MethodGet, "/zones/example.com", "", http.StatusOK, `"name": "example.com"`},
+		{"no zone", false, http.MethodGet, "/zones/example.net", "", http.StatusNotFound, `no such domain`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := testAPIServer(tt.allowPush)
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer s3cret")
+			w := httptest.NewRecorder()
+			srv.handler().ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body %s does not contain %s", w.Body, tt.wantBody)
+			}
+			if !json.Valid(w.Body.Bytes()) {
+				t.Errorf("body is not JSON: %s", w.Body)
+			}
+			if tt.body != "" && w.Code == http.StatusOK {
+				if got := (*calls)[0].Domains; got != "example.com" {
+					t.Errorf("Domains = %q, want %q", got, "example.com")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/apistats"
+	"github.com/StackExchange/dnscontrol/v3/pkg/httpclient"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+)
+
+// --report stats prints, for each domain, how long it took and how many
+// API requests its providers made, to find the slow providers and zones
+// and tune --concurrency. The requests are counted by pkg/apistats,
+// through the transport of pkg/httpclient.
+
+// statsReport collects the Stats of each domain.
+type statsReport struct {
+	mu    sync.Mutex
+	stats map[string]apistats.Stats
+}
+
+func newStatsReport() *statsReport {
+	apistats.Enable()
+	httpclient.Install()
+	return &statsReport{stats: map[string]apistats.Stats{}}
+}
+
+// track starts counting for a domain, and returns the function that
+// stops. A nil statsReport doesn't count anything.
+func (r *statsReport) track(domain string) (stop func()) {
+	if r == nil {
+		return func() {}
+	}
+	scope := apistats.Start()
+	return func() {
+		s := scope.Stop()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.stats[domain] = s
+	}
+}
+
+// print prints the report, with the domains in the order given (those
+// of dnsconfig.js).
+func (r *statsReport) print(domains []string, out printer.CLI) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DOMAIN\tTIME\tCALLS\tRETRIES\t429s\tCACHE HITS")
+	row := func(name string, s apistats.Stats, elapsed string) {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\n", name, elapsed, s.Calls, s.Retries, s.Throttled, s.CacheHits)
+	}
+	var total apistats.Stats
+	r.mu.Lock()
+	for _, name := range domains {
+		s, ok := r.stats[name]
+		if !ok {
+			continue
+		}
+		row(name, s, s.Elapsed.Round(time.Millisecond).String())
+		total.Add(s)
+	}
+	r.mu.Unlock()
+	if shared := apistats.Shared(); shared != (apistats.Stats{}) {
+		// Requests made while several domains were running.
+		row("(concurrent)", shared, "-")
+		total.Add(shared)
+	}
+	row("TOTAL", total, total.Elapsed.Round(time.Millisecond).String())
+	w.Flush()
+	out.Printf("%s", buf.String())
+}
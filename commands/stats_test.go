@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/apistats"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+)
+
+func TestStatsReport(t *testing.T) {
+	r := &statsReport{stats: map[string]apistats.Stats{
+		"example.net": {Elapsed: 250 * time.Millisecond, Calls: 3, CacheHits: 1},
+		"example.com": {Elapsed: 1500 * time.Millisecond, Calls: 12, Retries: 2, Throttled: 2},
+	}}
+	var buf bytes.Buffer
+	r.print([]string{"example.com", "example.org", "example.net"}, printer.ConsolePrinter{Writer: &buf})
+	want := "" +
+		"DOMAIN       TIME   CALLS  RETRIES  429s  CACHE HITS\n" +
+		"example.com  1.5s   12     2        2     0\n" +
+		"example.net  250ms  3      0        0     1\n" +
+		"TOTAL        1.75s  15     2        2     1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/httpclient"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/urfave/cli/v2"
+)
+
+// The requests that the providers send to their APIs go through
+// pkg/httpclient. These global flags export its traces and metrics.
+
+func telemetryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "otel-traces",
+			Usage: "Write the OpenTelemetry spans of the requests to the providers' APIs to this file (JSON)",
+		},
+		&cli.StringFlag{
+			Name:  "metrics-listen",
+			Usage: `Serve the Prometheus metrics of the requests to the providers' APIs at http://ADDR/metrics (for "watch" and "serve")`,
+		},
+		&cli.StringFlag{
+			Name:  "metrics-push",
+			Usage: "Push the Prometheus metrics of the requests to the providers' APIs to this Pushgateway URL before exiting",
+		},
+	}
+}
+
+// stopTracing writes the remaining spans (nil without --otel-traces).
+var stopTracing func() error
+
+// startTelemetry instruments the HTTP clients of the providers and
+// starts the exporters requested by the flags. Without them, the
+// clients are left alone.
+func startTelemetry(ctx *cli.Context) error {
+	for _, flag := range telemetryFlags() {
+		if ctx.IsSet(flag.Names()[0]) {
+			httpclient.Install()
+			break
+		}
+	}
+
+	if path := ctx.String("otel-traces"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("--otel-traces: %w", err)
+		}
+		shutdown, err := httpclient.EnableTracing(f)
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("--otel-traces: %w", err)
+		}
+		stopTracing = func() error {
+			err := shutdown()
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			return err
+		}
+	}
+
+	if ctx.String("metrics-listen") != "" || ctx.String("metrics-push") != "" {
+		httpclient.EnableMetrics()
+	}
+	if addr := ctx.String("metrics-listen"); addr != "" {
+		if err := httpclient.ServeMetrics(addr); err != nil {
+			return fmt.Errorf("--metrics-listen: %w", err)
+		}
+	}
+	return nil
+}
+
+// stopTelemetry flushes the exporters. Failures are only warnings, as
+// the command has already run.
+func stopTelemetry(ctx *cli.Context) error {
+	if stopTracing != nil {
+		if err := stopTracing(); err != nil {
+			printer.Warnf("--otel-traces: %s\n", err)
+		}
+	}
+	if url := ctx.String("metrics-push"); url != "" {
+		if err := httpclient.PushMetrics(url); err != nil {
+			printer.Warnf("--metrics-push: %s\n", err)
+		}
+	}
+	return nil
+}
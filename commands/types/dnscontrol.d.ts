@@ -43,10 +43,18 @@ type Duration =
  *
  * `FETCH` is not enabled by default. Please read the warnings below.
  *
+ * To restrict `FETCH` to the endpoints you trust, use `--fetch-allow` instead of `--allow-fetch`. It takes a comma-separated list, and can be repeated. An entry is either a URL prefix, such as `https://ipam.example.com/api/`, or a host name, such as `ipam.example.com`, which allows any `https` URL of that host. Any other URL, including the target of a redirect, is rejected.
+ *
+ * Each `GET` request is made at most once per run: fetching the same URL twice gives the same response. With `--fetch-cache=DIR`, successful responses are also kept in `DIR`, and used instead of a new request for `--fetch-cache-ttl` (default: `1h`). An older response is used when the request fails, so that a short outage of the data source doesn't break your builds.
+ *
+ * ```shell
+ * dnscontrol preview --fetch-allow=https://ipam.example.com/api/ --fetch-cache=.fetch-cache
+ * ```
+ *
  * > WARNING:
  * >
  * > 1. Relying on external sources adds a point of failure. If the external source doesn't work, your script won't either. Please make sure you are aware of the consequences.
- * > 2. Make sure DNSControl only uses verified configuration if you want to use `FETCH`. For example, an attacker can send Pull Requests to your config repo, and have your CI test malicious configurations and make arbitrary HTTP requests. Therefore, `FETCH` must be explicitly enabled with flag `--allow-fetch` on DNSControl invocation.
+ * > 2. Make sure DNSControl only uses verified configuration if you want to use `FETCH`. For example, an attacker can send Pull Requests to your config repo, and have your CI test malicious configurations and make arbitrary HTTP requests. Therefore, `FETCH` must be explicitly enabled with flag `--allow-fetch` or `--fetch-allow` on DNSControl invocation.
  *
  * ```js
  * var REG_NONE = NewRegistrar('none');
@@ -148,6 +156,10 @@ declare const CF_PROXY_DEFAULT_ON: DomainModifier;
 declare const CF_UNIVERSALSSL_OFF: DomainModifier;
 /** UniversalSSL on for entire domain */
 declare const CF_UNIVERSALSSL_ON: DomainModifier;
+/** CNAME flattening only at the apex for entire domain */
+declare const CF_CNAME_FLATTEN_AT_ROOT: DomainModifier;
+/** CNAME flattening of all CNAMEs for entire domain */
+declare const CF_CNAME_FLATTEN_ALL: DomainModifier;
 
 /**
  * Set default values for CLI variables. See: https://dnscontrol.org/cli-variables
@@ -221,6 +233,24 @@ declare function A(name: string, address: string | number, ...modifiers: RecordM
  */
 declare function AAAA(name: string, address: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * AAAA_POOL adds an AAAA record for each IPv6 address in the [IP_POOL](https://dnscontrol.org/js#IP_POOL) named `pool`. IPv4 addresses in the pool are ignored. It is an error if the pool has no IPv6 addresses.
+ * 
+ * The name and modifiers are the same as for [AAAA](https://dnscontrol.org/js#AAAA).
+ * 
+ * ```js
+ * IP_POOL("web", ["10.1.1.1", "10.1.1.2", "2001:db8::1", "2001:db8::2"]);
+ * 
+ * D("example.com", REGISTRAR, DnsProvider("R53"),
+ *   AAAA_POOL("@", "web"),
+ *   AAAA_POOL("www", "web", TTL(300))
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#AAAA_POOL
+ */
+declare function AAAA_POOL(name: string, pool: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * AKAMAICDN is a proprietary record type that is used to configure [Zone Apex Mapping](https://blogs.akamai.com/2019/08/fast-dns-zone-apex-mapping-dnssec.html).
  * The AKAMAICDN target must be preconfigured in the Akamai network.
@@ -233,6 +263,9 @@ declare function AKAMAICDN(name: string, target: string, ...modifiers: RecordMod
  * ALIAS is a virtual record type that points a record at another record. It is analogous to a CNAME, but is usually resolved at request-time and served as an A record. Unlike CNAMEs, ALIAS records can be used at the zone apex (`@`)
  * 
  * Different providers handle ALIAS records differently, and many do not support it at all. Attempting to use ALIAS records with a DNS provider type that does not support them will result in an error.
+ * Some providers store ALIAS records as ANAME records, or as CNAME records that they flatten. A provider that
+ * stores an ALIAS record elsewhere than at the apex as a CNAME serves it as a CNAME; DNSControl warns about these
+ * records, and reports an error if the CNAME would share its name with other records.
  * 
  * The name should be the relative label for the domain.
  * 
@@ -289,6 +322,14 @@ declare const AUTODNSSEC_OFF: DomainModifier;
  * If neither `AUTODNSSEC_ON` or `AUTODNSSEC_OFF` is specified for a
  * domain no changes will be requested.
  * 
+ * With `AUTODNSSEC_ON`, the DS records of the signed zone must be
+ * published in the parent zone by the registrar. DNS providers that can
+ * report their DS records (currently `DESEC`) pass them to the registrar,
+ * and registrars that can publish DS records (currently `HEXONET`) update
+ * them automatically. Other registrars leave the DS records alone, and
+ * they must be copied by hand. `AUTODNSSEC_OFF` does not remove the DS
+ * records at the registrar.
+ * 
  * @see https://dnscontrol.org/js#AUTODNSSEC_ON
  */
 declare const AUTODNSSEC_ON: DomainModifier;
@@ -340,6 +381,24 @@ declare const AUTODNSSEC_ON: DomainModifier;
  */
 declare function AZURE_ALIAS(name: string, type: "A" | "AAAA" | "CNAME", target: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * A_POOL adds an A record for each IPv4 address in the [IP_POOL](https://dnscontrol.org/js#IP_POOL) named `pool`. IPv6 addresses in the pool are ignored. It is an error if the pool has no IPv4 addresses.
+ * 
+ * The name and modifiers are the same as for [A](https://dnscontrol.org/js#A).
+ * 
+ * ```js
+ * IP_POOL("web", ["10.1.1.1", "10.1.1.2", "2001:db8::1", "2001:db8::2"]);
+ * 
+ * D("example.com", REGISTRAR, DnsProvider("R53"),
+ *   A_POOL("@", "web"),
+ *   A_POOL("www", "web", TTL(300))
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#A_POOL
+ */
+declare function A_POOL(name: string, pool: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * CAA adds a CAA record to a domain. The name should be the relative label for the record. Use `@` for the domain apex.
  * 
@@ -400,6 +459,85 @@ declare function CAA(name: string, tag: "issue" | "issuewild" | "iodef", value:
  */
 declare function CF_REDIRECT(source: string, destination: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * `CF_RULESET` declares a [WAF custom rule](https://developers.cloudflare.com/waf/custom-rules/)
+ * or a [rate limiting rule](https://developers.cloudflare.com/waf/rate-limiting-rules/)
+ * of the requests for a name of the domain. The provider must have the
+ * `manage_rulesets: true` metadata.
+ * 
+ * The rule applies to the requests whose `Host` is the name, and
+ * that match `expression` if it is set, in the
+ * [rules language](https://developers.cloudflare.com/ruleset-engine/rules-language/).
+ * With `rate_limit`, it is a rate limiting rule: once a client (an IP
+ * address) sends more than `requests` matching requests in `period`
+ * seconds (10, 60, 120, 300, 600 or 3600), the action applies to its
+ * requests for `timeout` seconds.
+ * 
+ * * `action:` What to do with the requests: `block`, `challenge`, `js_challenge`, `managed_challenge` or `log`
+ * * `expression:` Which requests for the name the rule applies to (optional; all of them by default)
+ * * `description:` A description of the rule (optional)
+ * * `rate_limit:` `{requests, period, timeout}` to make it a rate limiting rule (optional)
+ * 
+ * If `manage_rulesets` is set, DNSControl manages _all_ the rules of the
+ * zone whose expression starts with `http.host eq "<name>"`: it deletes
+ * those that aren't declared. The other rules are left alone.
+ * 
+ * WARNING: This interface is not extensively tested. Take precautions such as making
+ * backups and manually verifying `dnscontrol preview` output before running
+ * `dnscontrol push`.
+ * 
+ * ```js
+ * D("foo.com", .... ,
+ *     CF_RULESET("www", {
+ *         action: "managed_challenge",
+ *         expression: 'http.request.uri.path eq "/wp-login.php"',
+ *         description: "No WordPress here",
+ *     }),
+ *     CF_RULESET("api", {
+ *         action: "block",
+ *         rate_limit: { requests: 100, period: 60, timeout: 600 },
+ *     }),
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#CF_RULESET
+ */
+declare function CF_RULESET(name: string, rule: {action: "block" | "challenge" | "js_challenge" | "log" | "managed_challenge", expression?: string, description?: string, rate_limit?: {requests: number, period: number, timeout?: number}}, ...modifiers: RecordModifier[]): DomainModifier;
+
+/**
+ * `CF_SPECTRUM_APP` declares a [Spectrum](https://developers.cloudflare.com/spectrum/)
+ * application: Cloudflare proxies the `port` of `name`, over `protocol`
+ * (`tcp` or `udp`), to `origin`. The origin is `HOST:PORT`, where the host
+ * is an IP address (`[IPv6]:PORT`) or a hostname. The provider must have
+ * the `manage_spectrum: true` metadata.
+ * 
+ * Spectrum applications are part of the configuration of the zone, but
+ * not of its DNS records: they are lost when the zone is deleted and
+ * created again. With `CF_SPECTRUM_APP`, `dnscontrol push` creates them
+ * again.
+ * 
+ * Cloudflare creates the DNS record of `name` itself: don't declare one.
+ * 
+ * If `manage_spectrum` is set, DNSControl manages _all_ the applications
+ * of the zone that proxy one port to one origin: it deletes those that
+ * aren't declared. The others (HTTP applications, port ranges, several
+ * origins...) are left alone.
+ * 
+ * WARNING: This interface is not extensively tested. Take precautions such as making
+ * backups and manually verifying `dnscontrol preview` output before running
+ * `dnscontrol push`.
+ * 
+ * ```js
+ * D("foo.com", .... ,
+ *     CF_SPECTRUM_APP("ssh", "tcp", 22, "192.0.2.1:22"),
+ *     CF_SPECTRUM_APP("game", "udp", 27015, "origin.foo.com:27015"),
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#CF_SPECTRUM_APP
+ */
+declare function CF_SPECTRUM_APP(name: string, protocol: "tcp" | "udp", port: number, origin: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * `CF_TEMP_REDIRECT` uses Cloudflare-specific features ("Forwarding URL" Page
  * Rules) to generate a HTTP 302 temporary redirect.
@@ -459,7 +597,8 @@ declare function CLOUDNS_WR(name: string, target: string, ...modifiers: RecordMo
 
 /**
  * CNAME adds a CNAME record to the domain. The name should be the relative label for the domain.
- * Using `@` or `*` for CNAME records is not recommended, as different providers support them differently.
+ * A CNAME can't be created at `@` (the bare domain), nor share its name with any other record. `dnscontrol check` reports both. Use `ALIAS()` at `@` if the DNS providers support it.
+ * Using `*` for CNAME records is not recommended, as different providers support them differently.
  * 
  * Target should be a string representing the CNAME target. If it is a single label we will assume it is a relative name on the current domain. If it contains *any* dots, it should be a fully qualified domain name, ending with a `.`.
  * 
@@ -475,6 +614,40 @@ declare function CLOUDNS_WR(name: string, target: string, ...modifiers: RecordMo
  */
 declare function CNAME(name: string, target: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * CNAME_CHAINS reports the CNAMEs of the domain that lead to another
+ * CNAME of the domain, which leads to another, and so on. Resolvers
+ * follow each hop of such a chain, which slows down the resolution. The
+ * chains are easily created by accident, for example by macros that add
+ * CNAMEs pointing to names that another macro made CNAMEs too.
+ * 
+ * A CNAME pointing to a name that isn't a CNAME of the domain is one hop.
+ * The chains of more than `max_hops` hops (default: 1) are:
+ * 
+ * * `action: "warn"` (the default): reported as warnings by `preview` and `push`.
+ * * `action: "flatten"`: changed to point directly to the end of the chain.
+ * 
+ * A loop of CNAMEs is an error. Only the CNAMEs of the domain are
+ * followed: a chain through another zone isn't detected.
+ * 
+ * ```js
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   CNAME_CHAINS({ action: "flatten" }),
+ *   CNAME("www", "web"),                  // Becomes CNAME("www", "lb.cdn.example.net.")
+ *   CNAME("web", "lb"),                   // Becomes CNAME("web", "lb.cdn.example.net.")
+ *   CNAME("lb", "lb.cdn.example.net."),
+ *   CNAME("api", "host"),                 // Unchanged: host isn't a CNAME
+ *   A("host", "192.0.2.1")
+ * );
+ * ```
+ * 
+ * Like the other domain modifiers, it can be used in [DEFAULTS](https://dnscontrol.org/js#DEFAULTS)
+ * to apply to all the domains.
+ * 
+ * @see https://dnscontrol.org/js#CNAME_CHAINS
+ */
+declare function CNAME_CHAINS(opts: { max_hops?: number; action?: "warn" | "flatten" }): DomainModifier;
+
 /**
  * DS adds a DS record to the domain.
  * 
@@ -546,6 +719,76 @@ declare function DnsProvider(name: string, nsCount?: number): DomainModifier;
  */
 declare function FRAME(name: string, target: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * HTTPS adds an HTTPS record to a domain. The name should be the relative label for the record. HTTPS records are a special form of the SVCB resource record.
+ * 
+ * Priority is an int; a priority of 0 means AliasMode. Target is a hostname
+ * ending with a dot, or "." to refer to the name of the record itself.
+ * 
+ * Params (SvcParams) is a string of space-separated `key=value` pairs as they
+ * would appear in a zonefile, such as `alpn=h3,h2 port=443`. It may be empty.
+ * 
+ * ```js
+ * D("example.com", REGISTRAR, DnsProvider("BIND"),
+ *   HTTPS("@", 1, ".", "ipv4hint=123.123.123.123 alpn=h3,h2 port=443"),
+ *   HTTPS("@", 123, "test.com.", "")
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#HTTPS
+ */
+declare function HTTPS(name: string, priority: number, target: string, params: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/**
+ * `IGNORE()` makes DNSControl ignore records that match all three of
+ * the patterns. DNSControl will not add, change, or delete these
+ * records. This permits another entity (a dynamic DNS client, Kubernetes
+ * External DNS, a provider's web UI) to "own" them.
+ * 
+ * * `labelPattern` is a glob matched against the label (the short name,
+ *   such as `foo`, not `foo.example.com`). `*` matches one level and `**`
+ *   matches any number of levels, the same as with
+ *   [`IGNORE_NAME`](https://dnscontrol.org/js#IGNORE_NAME). The default (or `"*"`) matches every label.
+ * * `rTypes` is a comma-separated list of record types, such as `"A"` or
+ *   `"A,CNAME"`. The default (or `"*"`) matches every type.
+ * * `targetPattern` is a glob matched against the target of the record.
+ *   The default (or `"*"`) matches every target.
+ * 
+ * `IGNORE()` works the same for all providers, whether they use the old
+ * or the new diff algorithm.
+ * 
+ * ```js
+ * D("example.com", REGISTRAR, DnsProvider("CLOUDFLAREAPI"),
+ *   IGNORE("foo"), // ignore all records at foo
+ *   IGNORE("bar", "A,MX"), // ignore only A and MX records at bar
+ *   IGNORE("*", "CNAME", "*.acm-validations.aws."), // ignore AWS ACM validation CNAMEs
+ *   IGNORE("**", "TXT", "google-site-verification=*"), // ignore Google verification records anywhere
+ *   A("baz", "1.2.3.4")
+ * );
+ * ```
+ * 
+ * # Caveats
+ * 
+ * It is an error to add a record in `dnsconfig.js` that matches an
+ * `IGNORE()` pattern, since DNSControl has promised not to modify it. The
+ * `NS` and `SOA` records at the apex are exempt, as are records with the
+ * `IGNORE_NAME_DISABLE_SAFETY_CHECK` modifier. Add
+ * `DISABLE_UNMANAGED_SAFETY_CHECK` to the `D()` to turn this check off
+ * entirely.
+ * 
+ * Without a `targetPattern`, `IGNORE()` is an alias of `IGNORE_NAME()`:
+ * `IGNORE("foo")` is the same as `IGNORE_NAME("foo")`, and
+ * `IGNORE("foo", "A,MX")` the same as `IGNORE_NAME("foo", "A,MX")`.
+ * Before this version `IGNORE()` only accepted a label and was
+ * deprecated; existing configurations continue to work unchanged.
+ * 
+ * The Cloudflare provider's `ignored_labels` setting has been removed.
+ * Use `IGNORE_NAME()` or `IGNORE()` instead.
+ * 
+ * @see https://dnscontrol.org/js#IGNORE
+ */
+declare function IGNORE(labelPattern?: string, rTypes?: string, targetPattern?: string): DomainModifier;
+
 /**
  * WARNING: The `IGNORE_*` family  of functions is risky to use. The code
  * is brittle and has subtle bugs. Use at your own risk. Do not use these
@@ -680,6 +923,130 @@ declare function IGNORE_TARGET(pattern: string, rType: string): DomainModifier;
  */
 declare function INCLUDE(domain: string): DomainModifier;
 
+/**
+ * `KEEP(types...)` is a finer-grained [`NO_PURGE`](https://dnscontrol.org/js#NO_PURGE):
+ * DNSControl never deletes records of the listed types, even if they are
+ * not in `dnsconfig.js`. Records of other types are deleted as usual.
+ * 
+ * In this example the TXT records that a mail provider's dashboard adds
+ * are left alone, while stray A and CNAME records are deleted.
+ * 
+ * ```js
+ * D("example.com", .... , KEEP("TXT"),
+ *   A("www", "1.2.3.4"),
+ *   TXT("@", "v=spf1 -all")
+ * );
+ * ```
+ * 
+ * TXT records that are listed in `dnsconfig.js` are still updated, and
+ * a label's TXT record set is replaced as a whole when it changes. See
+ * also [`PURGE_ONLY`](https://dnscontrol.org/js#PURGE_ONLY).
+ * 
+ * `KEEP` needs the same support from the provider as `NO_PURGE`.
+ * DNSControl will exit with an error if it is used on a provider that
+ * does not support `NO_PURGE`, such as BIND.
+ * 
+ * @see https://dnscontrol.org/js#KEEP
+ */
+declare function KEEP(...types: string[]): DomainModifier;
+
+/**
+ * LOC adds a LOC record (RFC 1876) to a domain. The name should be the relative label for the record.
+ * 
+ * The latitude is given as degrees (`d1`), minutes (`m1`), seconds (`s1`)
+ * and `"N"` or `"S"`. The longitude is given as degrees (`d2`), minutes
+ * (`m2`), seconds (`s2`) and `"E"` or `"W"`. Seconds may have up to three
+ * decimal places.
+ * 
+ * Altitude (`alt`), size (`siz`), horizontal precision (`hp`) and vertical
+ * precision (`vp`) are in meters. The RFC's defaults are a size of 1m, a
+ * horizontal precision of 10000m and a vertical precision of 10m.
+ * 
+ * To use decimal degrees or a coordinate string instead, see
+ * [`LOC_BUILDER_DD`](https://dnscontrol.org/js#LOC_BUILDER_DD) and [`LOC_BUILDER_DMS_STR`](https://dnscontrol.org/js#LOC_BUILDER_DMS_STR).
+ * 
+ * ```js
+ * D("example.com", REGISTRAR, DnsProvider("BIND"),
+ *   // 52°22′23″N 4°53′32″E, 2m below sea level
+ *   LOC("@", 52, 22, 23, "N", 4, 53, 32, "E", -2, 1, 10000, 10),
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#LOC
+ */
+declare function LOC(name: string, d1: number, m1: number, s1: number, ns: string, d2: number, m2: number, s2: number, ew: string, alt: number, siz: number, hp: number, vp: number, ...modifiers: RecordModifier[]): DomainModifier;
+
+/**
+ * LUA is a PowerDNS specific record type whose answers are computed by a
+ * [Lua script](https://doc.powerdns.com/authoritative/lua-records/index.html)
+ * when it is queried.
+ * 
+ * The type is the type of the records that the script returns, such as
+ * `A`, `AAAA`, `CNAME` or `TXT`. A script that is a single statement
+ * starting with `;` returns its value.
+ * 
+ * LUA records must be enabled in PowerDNS with `enable-lua-records`
+ * (globally or with the `ENABLE-LUA-RECORDS` zone metadata).
+ * Attempting to use LUA on another provider than PowerDNS will result in an error.
+ * 
+ * ```js
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_POWERDNS),
+ *   LUA("www", "A", "ifportup(443, {'192.0.2.1', '192.0.2.2'})"),
+ *   LUA("geo", "CNAME", ";return 'www.' .. country() .. '.example.com.'", TTL(60)),
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#LUA
+ */
+declare function LUA(name: string, type: string, script: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/**
+ * `MANAGED_BY(owner)` lets DNSControl share a zone with other systems,
+ * such as [external-dns](https://github.com/kubernetes-sigs/external-dns),
+ * a dynamic DNS client, manual edits in a provider's web UI, or another
+ * DNSControl configuration. DNSControl only deletes the records that it
+ * created itself.
+ * 
+ * For each record set (label and type) in `dnsconfig.js`, DNSControl adds
+ * a TXT record that says who owns it:
+ * 
+ * ```text
+ * _dnscontrol-owner.www  IN TXT "dnscontrol-owner=prod type=A"
+ * ```
+ * 
+ * When the zone is updated:
+ * 
+ * * Record sets that are in `dnsconfig.js` are managed as usual. Declaring a record set claims it, even if it already existed.
+ * * Record sets that were tagged with the owner, but are no longer in `dnsconfig.js`, are deleted (with their TXT records).
+ * * All other record sets are left alone.
+ * * It is an error to declare a record set that is tagged by a different owner.
+ * 
+ * Unlike [NO_PURGE](NO_PURGE), records that are removed from
+ * `dnsconfig.js` are deleted. Unlike [IGNORE](IGNORE), there is no need
+ * to list what the other systems manage.
+ * 
+ * The owner may contain letters, digits, `.`, `_` and `-`. Use a different
+ * owner for each `dnsconfig.js` that manages the zone. The labels of
+ * wildcard records are written as `_wildcard`, for example
+ * `_dnscontrol-owner._wildcard.foo` for `*.foo`.
+ * 
+ * ```js
+ * D("example.com", REG_NONE, DnsProvider(DSP_MY_PROVIDER),
+ *   MANAGED_BY("dnscontrol-prod"),
+ *   A("www", "10.1.1.1"),
+ *   MX("@", 10, "mx.example.com.")
+ * );
+ * ```
+ * 
+ * Ownership is only recorded in TXT records; provider-specific comments
+ * are not used. `MANAGED_BY` needs the same provider support as
+ * `NO_PURGE`: providers that rewrite the zone from scratch, such as
+ * `BIND`, can't use it.
+ * 
+ * @see https://dnscontrol.org/js#MANAGED_BY
+ */
+declare function MANAGED_BY(owner: string): DomainModifier;
+
 /**
  * MX adds an MX record to the domain.
  * 
@@ -702,7 +1069,7 @@ declare function MX(name: string, priority: number, target: string, ...modifiers
  * `NAMESERVER()` instructs DNSControl to inform the domain's registrar where to find this zone.
  * For some registrars this will also add NS records to the zone itself.
  * 
- * This takes exactly one argument: the name of the nameserver. It must end with
+ * The first argument is the name of the nameserver. It must end with
  * a "." if it is a FQDN, just like all targets.
  * 
  * This is different than the `NS()` function, which inserts NS records
@@ -726,6 +1093,31 @@ declare function MX(name: string, priority: number, target: string, ...modifiers
  * );
  * ```
  * 
+ * # Glue records
+ * 
+ * A nameserver in the domain itself (`ns1.example.com` for `example.com`)
+ * can only be found if the parent zone also publishes its addresses, the
+ * glue records. List them after the name:
+ * 
+ * ```js
+ * D("example.com", REGISTRAR, .... ,
+ *   NAMESERVER("ns1.example.com.", "192.0.2.1", "2001:db8::1"),
+ *   NAMESERVER("ns2.example.com.", "192.0.2.2"),
+ *   A("ns1", "192.0.2.1"),
+ *   AAAA("ns1", "2001:db8::1"),
+ *   A("ns2", "192.0.2.2"),
+ * );
+ * ```
+ * 
+ * The registrar then creates (or updates) a host object for each of these
+ * nameservers at the registry. Host objects that dnsconfig.js doesn't
+ * mention are left alone, since other domains may use them. The glue only
+ * goes to the parent zone: add the `A` and `AAAA` records to the zone as
+ * usual.
+ * 
+ * Only some registrars can manage host objects (HEXONET, for now); the
+ * others report an error when a `NAMESERVER()` has addresses.
+ * 
  * # The difference between NS() and NAMESERVER()
  * 
  * Nameservers are one of the least
@@ -777,7 +1169,7 @@ declare function MX(name: string, priority: number, target: string, ...modifiers
  * 
  * @see https://dnscontrol.org/js#NAMESERVER
  */
-declare function NAMESERVER(name: string, ...modifiers: RecordModifier[]): DomainModifier;
+declare function NAMESERVER(name: string, ...glue: string[]): DomainModifier;
 
 /**
  * TTL sets the TTL on the domain apex NS RRs defined by [NAMESERVER](https://dnscontrol.org/js#NAMESERVER).
@@ -795,6 +1187,34 @@ declare function NAMESERVER(name: string, ...modifiers: RecordModifier[]): Domai
  */
 declare function NAMESERVER_TTL(ttl: Duration): DomainModifier;
 
+/**
+ * NETLIFY is a proprietary record type that points a label at a Netlify site's load balancers.
+ * The target is the site's Netlify hostname, without a trailing dot.
+ * 
+ * Netlify creates these records automatically. DNSControl leaves them alone
+ * unless the domain contains at least one `NETLIFY()` or `NETLIFYv6()` record, in which
+ * case all of them are managed like any other record.
+ * 
+ * ```js
+ * D("example.com", REG_NONE, DnsProvider(DSP_NETLIFY),
+ *   NETLIFY("@", "example.netlify.app"),
+ *   NETLIFYv6("@", "example.netlify.app"),
+ *   NETLIFY("www", "example.netlify.app")
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#NETLIFY
+ */
+declare function NETLIFY(name: string, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/**
+ * NETLIFYv6 is the IPv6 counterpart of [NETLIFY](https://dnscontrol.org/js#NETLIFY).
+ * The target is the site's Netlify hostname, without a trailing dot.
+ * 
+ * @see https://dnscontrol.org/js#NETLIFYv6
+ */
+declare function NETLIFYv6(name: string, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * NO_PURGE indicates that records should not be deleted from a domain.
  * Records will be added and updated, but not removed.
@@ -832,6 +1252,9 @@ declare function NAMESERVER_TTL(ttl: Duration): DomainModifier;
  * There is also `PURGE` command for completeness. `PURGE` is the
  * default, thus this command is a no-op.
  * 
+ * To only protect some record types, use [`PURGE_ONLY`](https://dnscontrol.org/js#PURGE_ONLY)
+ * or [`KEEP`](https://dnscontrol.org/js#KEEP) instead.
+ * 
  * @see https://dnscontrol.org/js#NO_PURGE
  */
 declare const NO_PURGE: DomainModifier;
@@ -944,6 +1367,42 @@ declare function NS1_URLFWD(name: string, target: string, ...modifiers: RecordMo
  */
 declare function PTR(name: string, target: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * `PTR_RANGE` adds a [`PTR`](https://dnscontrol.org/js#PTR) record for every address in a CIDR
+ * block. `hostFn` is called with each address (as a string) and its
+ * position in the block, and returns the target of the record. If it
+ * returns `null` (or any false value) the address is skipped.
+ * 
+ * The records are named by address, therefore DNSControl places them
+ * correctly in the reverse zone in the same way as `PTR("10.1.2.3", ...)`.
+ * The block may be smaller than the zone, or a part of a
+ * [`D_EXTEND`](https://dnscontrol.org/js#D_EXTEND)ed zone. It is an error if an address is not
+ * inside the zone.
+ * 
+ * Blocks larger than 65536 addresses (a /16 for IPv4, a /112 for IPv6) are
+ * not supported. As with [`REV`](https://dnscontrol.org/js#REV), the bits beyond the netmask must be
+ * zero.
+ * 
+ * ```js
+ * D(REV("10.1.2.0/24"), REGISTRAR, DnsProvider(BIND),
+ *   // 10.1.2.1 -> host-10-1-2-1.example.com.
+ *   PTR_RANGE("10.1.2.0/24", function (ip, i) {
+ *     if (i === 0 || i === 255) { return null; } // Skip the network and broadcast addresses.
+ *     return "host-" + ip.replace(/\./g, "-") + ".example.com.";
+ *   })
+ * );
+ * 
+ * D(REV("2001:db8:302::/48"), REGISTRAR, DnsProvider(BIND),
+ *   PTR_RANGE("2001:db8:302::100/120", function (ip, i) {
+ *     return "pool" + i + ".example.com.";
+ *   }, TTL(3600))
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#PTR_RANGE
+ */
+declare function PTR_RANGE(cidr: string, hostFn: (address: string, index: number) => string | null | undefined | false, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * PURGE is the default setting for all domains.  Therefore PURGE is
  * a no-op. It is included for completeness only.
@@ -984,6 +1443,34 @@ declare function PTR(name: string, target: string, ...modifiers: RecordModifier[
  */
 declare const PURGE: DomainModifier;
 
+/**
+ * `PURGE_ONLY(types...)` is a finer-grained [`NO_PURGE`](https://dnscontrol.org/js#NO_PURGE):
+ * DNSControl only deletes records of the listed types. Records of other
+ * types that are not in `dnsconfig.js` are left alone.
+ * 
+ * In this example DNSControl manages all the A, AAAA and CNAME records
+ * of the zone. The MX and TXT records, which are maintained by another
+ * system, are not deleted even though they are not listed.
+ * 
+ * ```js
+ * D("example.com", .... , PURGE_ONLY("A", "AAAA", "CNAME"),
+ *   A("www", "1.2.3.4"),
+ *   CNAME("blog", "www")
+ * );
+ * ```
+ * 
+ * Records of any type can still be added and updated. Use
+ * [`KEEP`](https://dnscontrol.org/js#KEEP) to list the types that must never be deleted
+ * instead. If both are used, `KEEP` wins.
+ * 
+ * `PURGE_ONLY` needs the same support from the provider as `NO_PURGE`.
+ * DNSControl will exit with an error if it is used on a provider that
+ * does not support `NO_PURGE`, such as BIND.
+ * 
+ * @see https://dnscontrol.org/js#PURGE_ONLY
+ */
+declare function PURGE_ONLY(...types: string[]): DomainModifier;
+
 /**
  * R53_ALIAS is a Route53 specific virtual record type that points a record at either another record or an AWS entity (like a Cloudfront distribution, an ELB, etc...). It is analogous to a CNAME, but is usually resolved at request-time and served as an A record. Unlike CNAMEs, ALIAS records can be used at the zone apex (`@`)
  * 
@@ -1011,6 +1498,8 @@ declare const PURGE: DomainModifier;
  * * _S3 bucket_ (configured as website): specify the hosted zone ID for the region that you created the bucket in. You can find it in [the List of regions and hosted Zone IDs](http://docs.aws.amazon.com/general/latest/gr/rande.html#s3_region)
  * * _Another Route 53 record_: you can either specify the correct zone id or do not specify anything and DNSControl will figure out the right zone id. (Note: Route53 alias can't reference a record in a different zone).
  * 
+ * An alias can also have a routing policy, and can evaluate the health of its target, with the `r53_*` metadata fields described in the [Route 53 provider documentation](https://dnscontrol.org//providers/route53#metadata).
+ * 
  * ```js
  * D('example.com', REGISTRAR, DnsProvider('ROUTE53'),
  *   R53_ALIAS('foo', 'A', 'bar'),                              // record in same zone
@@ -1018,6 +1507,7 @@ declare const PURGE: DomainModifier;
  *   R53_ALIAS('foo', 'A', 'blahblah.elasticloadbalancing.us-west-1.amazonaws.com.', R53_ZONE('Z368ELLRRE2KJ0')),     // a classic ELB in us-west-1
  *   R53_ALIAS('foo', 'A', 'blahblah.elasticbeanstalk.us-west-2.amazonaws.com.', R53_ZONE('Z38NKT9BP95V3O')),     // an Elastic Beanstalk environment in us-west-2
  *   R53_ALIAS('foo', 'A', 'blahblah-bucket.s3-website-us-west-1.amazonaws.com.', R53_ZONE('Z2F56UZL2M1ACD')),     // a website S3 Bucket in us-west-1
+ *   R53_ALIAS('www', 'A', 'lb-eu.example.com.', {r53_set_identifier: 'eu', r53_region: 'eu-west-1', r53_evaluate_target_health: 'true'}),  // latency routing
  * );
  * ```
  * 
@@ -1025,6 +1515,35 @@ declare const PURGE: DomainModifier;
  */
 declare function R53_ALIAS(name: string, target: string, zone_idModifier: DomainModifier & RecordModifier): DomainModifier;
 
+/**
+ * `RFC3597` adds a record of a type that DNSControl doesn't know to a domain,
+ * in the generic format of [RFC 3597](https://datatracker.ietf.org/doc/html/rfc3597).
+ * This lets you deploy new or experimental record types without waiting for
+ * DNSControl to support them.
+ * 
+ * The type is `TYPE` followed by the number of the type (`"TYPE65534"`), or
+ * the number alone (`65534`). Types that DNSControl or the zone file syntax
+ * know by name (`TYPE1` is `A`) are rejected: use their own function instead.
+ * 
+ * The rdata is `\# length hexdata`, where length is the number of bytes of
+ * the data (`"\\# 4 0a000001"`, with the backslash escaped), or the hex data
+ * alone (`"0a000001"`). The data is opaque to DNSControl: it is not checked
+ * beyond its length.
+ * 
+ * Only a few providers can serve these records. See the RFC3597 column of
+ * the [provider page](https://dnscontrol.org//provider-list) matrix.
+ * 
+ * ```js
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   RFC3597("@", "TYPE65534", "\\# 4 0a000001"),
+ *   RFC3597("exp", 65280, "c0ffee"),
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#RFC3597
+ */
+declare function RFC3597(name: string, type: string | number, rdata: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * `SOA` adds an `SOA` record to a domain. The name should be `@`.  ns and mbox are strings. The other fields are unsigned 32-bit ints.
  * 
@@ -1094,6 +1613,26 @@ declare function SRV(name: string, priority: number, weight: number, port: numbe
  */
 declare function SSHFP(name: string, algorithm: 0 | 1 | 2 | 3 | 4, type: 0 | 1 | 2, value: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * SVCB adds an SVCB record to a domain. The name should be the relative label for the record.
+ * 
+ * Priority is an int; a priority of 0 means AliasMode. Target is a hostname
+ * ending with a dot, or "." to refer to the name of the record itself.
+ * 
+ * Params (SvcParams) is a string of space-separated `key=value` pairs as they
+ * would appear in a zonefile, such as `alpn=h3,h2 port=443`. It may be empty.
+ * 
+ * ```js
+ * D("example.com", REGISTRAR, DnsProvider("BIND"),
+ *   SVCB("@", 1, ".", "ipv4hint=123.123.123.123 alpn=h3,h2 port=443"),
+ *   SVCB("_8443._foo.api", 2, "example.com.", "port=8443")
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#SVCB
+ */
+declare function SVCB(name: string, priority: number, target: string, params: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * TLSA adds a TLSA record to a domain. The name should be the relative label for the record.
  * 
@@ -1112,6 +1651,38 @@ declare function SSHFP(name: string, algorithm: 0 | 1 | 2 | 3 | 4, type: 0 | 1 |
  */
 declare function TLSA(name: string, usage: number, selector: number, type: number, certificate: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * TTL_POLICY sets the TTL of the records that do not set one, by type.
+ * The rule for `"*"` applies to the types that don't have their own. It
+ * is most useful in [DEFAULTS](https://dnscontrol.org/js#DEFAULTS), to set the same policy for all
+ * domains.
+ * 
+ * A record's TTL is, in order of precedence: its [TTL](https://dnscontrol.org/js#TTL), the
+ * [DefaultTTL](https://dnscontrol.org/js#DefaultTTL) of the domain, the rule of TTL_POLICY for its
+ * type, the `"*"` rule, and the DNSControl global default of 300 seconds.
+ * The TTLs are in the same format as [TTL](https://dnscontrol.org/js#TTL).
+ * 
+ * ```js
+ * DEFAULTS(
+ *   TTL_POLICY({ MX: "1h", TXT: "5m", "*": "30m" })
+ * );
+ * 
+ * D('example.com', REGISTRAR, DnsProvider('R53'),
+ *   A('@','1.2.3.4'),                  // 30 minutes
+ *   MX('@', 10, 'mail.example.com.'),  // 1 hour
+ *   TXT('@', 'v=spf1 -all'),           // 5 minutes
+ *   A('foo', '2.3.4.5', TTL(600))      // 10 minutes
+ * );
+ * ```
+ * 
+ * Some DNS providers only accept some TTLs (for example, none under 60
+ * seconds). They change the others to the closest TTL they accept, and
+ * `preview` warns about the records whose TTL will be changed.
+ * 
+ * @see https://dnscontrol.org/js#TTL_POLICY
+ */
+declare function TTL_POLICY(rules: { [rtype: string]: Duration }): DomainModifier;
+
 /**
  * TXT adds an TXT record To a domain. The name should be the relative
  * label for the record. Use `@` for the domain apex.
@@ -1214,6 +1785,29 @@ declare function TLSA(name: string, usage: number, selector: number, type: numbe
  */
 declare function TXT(name: string, contents: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * `URI` adds a URI record ([RFC 7553](https://datatracker.ietf.org/doc/html/rfc7553)) to a domain. A URI record maps
+ * a service, named like the label of an SRV record (`_service._proto`), to a URI.
+ * 
+ * Priority and weight are numbers between 0 and 65535 and are used like the
+ * ones of an SRV record: clients use the URIs with the lowest priority first,
+ * and pick among the URIs of the same priority in proportion to their weights.
+ * 
+ * The target is the URI itself. It is case-sensitive and is not changed by
+ * DNSControl: use a full URI, such as `https://www.example.com/`.
+ * 
+ * ```js
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   URI("_http._tcp", 10, 1, "https://www.example.com/"),
+ *   URI("_ftp._tcp", 10, 1, "ftp://ftp.example.com/public"),
+ *   URI("_sip._udp", 20, 5, "sip:help@example.com"),
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#URI
+ */
+declare function URI(name: string, priority: number, weight: number, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
 /**
  * Documentation needed.
  * 
@@ -1228,6 +1822,37 @@ declare function URL(name: string, target: string, ...modifiers: RecordModifier[
  */
 declare function URL301(name: string, ...modifiers: RecordModifier[]): DomainModifier;
 
+/**
+ * ZONE_TAGS sets the tags (labels) of the hosted zone at the DNS
+ * providers, for example to enforce cost-allocation tagging from
+ * `dnsconfig.js`. The tags of several `ZONE_TAGS` are merged, so that
+ * [DEFAULTS](https://dnscontrol.org/js#DEFAULTS) can set the tags that all the zones share.
+ * 
+ * When `ZONE_TAGS` is used, `push` makes the tags of the zone exactly the
+ * ones declared: it adds the missing tags, changes the values that
+ * differ and removes the other tags. Tags that belong to the provider
+ * itself are left alone. Without `ZONE_TAGS`, the tags are not touched.
+ * 
+ * ```js
+ * DEFAULTS(ZONE_TAGS({ costcenter: "1234" }));
+ * 
+ * D("example.com", REG_MY_PROVIDER, DnsProvider(DSP_MY_PROVIDER),
+ *   ZONE_TAGS({ team: "dns", env: "prod" }),
+ *   A("@", "1.2.3.4")
+ * );
+ * ```
+ * 
+ * The tags are stored as a JSON object in the `zone_tags` domain
+ * metadata, which can also be set directly:
+ * `{zone_tags: '{"team":"dns"}'}`.
+ * 
+ * Zone tags are supported by `AZURE_DNS` (resource tags) and `ROUTE53`
+ * (hosted zone tags). Using them with another DNS provider is an error.
+ * 
+ * @see https://dnscontrol.org/js#ZONE_TAGS
+ */
+declare function ZONE_TAGS(tags: { [key: string]: string }): DomainModifier;
+
 /**
  * `D` adds a new Domain for DNSControl to manage. The first two arguments are required: the domain name (fully qualified `example.com` without a trailing dot), and the
  * name of the registrar (as previously declared with [NewRegistrar](https://dnscontrol.org/js#NewRegistrar)). Any number of additional arguments may be included to add DNS Providers with [DNSProvider](https://dnscontrol.org/js#DNSProvider),
@@ -1314,6 +1939,19 @@ declare function URL301(name: string, ...modifiers: RecordModifier[]): DomainMod
  * character, which is probably does.  If you see an error that mentions
  * `event not found` you probably forgot the quotes.
  * 
+ * `preview` and `push` print the name with its tag (`example.com!inside`)
+ * in the headers and messages of each view, and so does `print-ir`, so
+ * that its output can be read back with `--ir`.
+ * 
+ * Only one view of a domain can use a registrar other than `NONE`: the
+ * registrar delegates the domain to the nameservers of one view, usually
+ * the public one. Use a registrar of type `NONE`, e.g.
+ * `NewRegistrar("none", "NONE")`, for the other views.
+ * 
+ * To start the configuration of a view from an existing zone, give
+ * `get-zones` the name with the tag, e.g. `dnscontrol get-zones
+ * --format=js bind BIND 'example.com!inside'`, or use `--tag=inside`.
+ * 
  * @see https://dnscontrol.org/js#D
  */
 declare function D(name: string, registrar: string, ...modifiers: DomainModifier[]): void;
@@ -1427,9 +2065,12 @@ declare function DOMAIN_ELSEWHERE_AUTO(domain: string, registrar: string, dnsPro
  * `D_EXTEND('sub.sub.domain.tld', ...)` would match `sub.domain.tld`,
  * not `domain.tld`.
  * 
- * Some operators only act on an apex domain (e.g.
- * `CF_REDIRECT` and `CF_TEMP_REDIRECT`). Using them
- * in a `D_EXTEND` subdomain may not be what you expect.
+ * Some record types apply to the whole zone rather than to a label
+ * (`CF_REDIRECT`, `CF_TEMP_REDIRECT` and `CF_WORKER_ROUTE`). They can be
+ * used in any `D_EXTEND()`, including a subdomain one, but the subdomain
+ * isn't added to them: their patterns already name the hosts they match.
+ * This lets each team keep the page rules and worker routes of its
+ * subdomain in its own file.
  * 
  * ```js
  * D("domain.tld", REG, DnsProvider(DNS),
@@ -1489,6 +2130,66 @@ declare function DOMAIN_ELSEWHERE_AUTO(domain: string, registrar: string, dnsPro
  */
 declare function D_EXTEND(name: string, ...modifiers: DomainModifier[]): void;
 
+/**
+ * `IMPORT(...)` loads a JavaScript or JSON file as a module, so that a
+ * large configuration can be split into several files. For example, each
+ * team can maintain the zones it owns in its own file while DNSControl is
+ * still run once.
+ * 
+ * `IMPORT()` differs from [`require()`](https://dnscontrol.org/js#require) as follows:
+ * 
+ * * A relative path is always relative to the file that contains the
+ *   `IMPORT()`, no matter if it starts with `.` or not.
+ * * A `.js` file runs in its own scope, like a Node.js module. Variables
+ *   and functions it declares are not visible to other files unless they
+ *   are added to `module.exports`. `IMPORT()` returns `module.exports`.
+ *   Functions such as `D()` and `A()` work as usual.
+ * * Each file runs only once. Later `IMPORT()`s of the same file return
+ *   the same `module.exports`.
+ * * A file that (directly or indirectly) imports itself is an error.
+ * 
+ * If the path ends with `.json`, `IMPORT()` returns the `JSON.parse()` of
+ * the file's contents.
+ * 
+ * ```js
+ * // dnsconfig.js
+ * var common = IMPORT("common.js");
+ * 
+ * D("example.com", REG, DnsProvider(DSP),
+ *   common.webServers(),
+ *   A("mail", "10.2.3.5")
+ * );
+ * 
+ * IMPORT("teams/marketing.js");
+ * ```
+ * 
+ * ```js
+ * // common.js
+ * var settings = IMPORT("settings.json");
+ * 
+ * module.exports = {
+ *   webServers: function () {
+ *     return [
+ *       A("@", settings.web),
+ *       A("www", settings.web),
+ *     ];
+ *   },
+ * };
+ * ```
+ * 
+ * ```js
+ * // teams/marketing.js
+ * var common = IMPORT("../common.js"); // Relative to this file.
+ * 
+ * D("example-promo.com", REG, DnsProvider(DSP),
+ *   common.webServers()
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#IMPORT
+ */
+declare function IMPORT(path: string): any;
+
 /**
  * Converts an IPv4 address from string to an integer. This allows performing mathematical operations with the IP address.
  * 
@@ -1513,6 +2214,30 @@ declare function D_EXTEND(name: string, ...modifiers: DomainModifier[]): void;
  */
 declare function IP(ip: string): number;
 
+/**
+ * `IP_POOL` defines a named list of IP addresses, such as the members of
+ * a round-robin. Use [`A_POOL`](https://dnscontrol.org/js#A_POOL) and [`AAAA_POOL`](https://dnscontrol.org/js#AAAA_POOL) to
+ * add a record for each address.
+ * 
+ * The addresses may be IPv4 or IPv6 addresses, or numeric values obtained
+ * via [IP](https://dnscontrol.org/js#IP). They are checked when `dnsconfig.js` is run: it is an
+ * error if an address is invalid, is listed twice, or if a pool is
+ * declared more than once. A pool must be declared before it is used.
+ * 
+ * ```js
+ * IP_POOL("web", ["10.1.1.1", "10.1.1.2", "10.1.1.3", "2001:db8::80"]);
+ * 
+ * D("example.com", REGISTRAR, DnsProvider("R53"),
+ *   A_POOL("@", "web"),
+ *   A_POOL("www", "web", TTL(300)),
+ *   AAAA_POOL("www", "web")
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#IP_POOL
+ */
+declare function IP_POOL(name: string, addresses: (string | number)[]): void;
+
 /**
  * NewDnsProvider activates a DNS Service Provider (DSP) specified in `creds.json`.
  * A DSP stores a DNS zone's records and provides DNS service for the zone (i.e.
@@ -1523,6 +2248,7 @@ declare function IP(ip: string): number;
  *   * Starting with v3.16, the type is optional. If it is absent, the `TYPE` field in `creds.json` is used instead. You can leave it out. (Thanks to JavaScript magic, you can leave it out even when there are more fields).
  *   * Starting with v4.0, specifying the type may be an error. Please add the `TYPE` field to `creds.json` and remove this parameter from `dnsconfig.js` to prepare.
  * * `meta` is a way to send additional parameters to the provider.  It is optional and only certain providers use it.  See the [individual provider docs](https://dnscontrol.org//provider-list) for details.
+ *   * Providers that declare their metadata (BIND, CLOUDFLAREAPI, POWERDNS, ...) reject unknown keys and values of the wrong type, e.g. `unknown key "manage_redirect" (did you mean "manage_redirects"?)`.
  * 
  * This function will return an opaque string that should be assigned to a variable name for use in [D](https://dnscontrol.org/js#D) directives.
  * 
@@ -1653,6 +2379,31 @@ declare function PANIC(message: string): never;
  */
 declare function REV(address: string): string;
 
+/**
+ * `TRANSFORM_IP6` returns a rule of a transform table, as used by
+ * [`IMPORT_TRANSFORM`](https://dnscontrol.org/js#IMPORT_TRANSFORM) and Cloudflare's
+ * `ip_conversions`, that rebases the IPv6 addresses in `cidr` on
+ * `newBase`. The part of the address after the prefix is kept.
+ * 
+ * If `newBase` is a list of addresses, each address is turned into one
+ * address per item in the list.
+ * 
+ * ```js
+ * var TRANSFORM_INT = [
+ *     { low: "10.1.0.0", high: "10.1.255.255", newBase: "10.2.0.0" },
+ *     // 2001:db8::11 becomes fd00:1::11 and fd00:2::11
+ *     TRANSFORM_IP6("2001:db8::/64", ["fd00:1::", "fd00:2::"]),
+ * ];
+ * 
+ * D("internal.example.com", REG_NONE, DnsProvider(DNS_BIND),
+ *   IMPORT_TRANSFORM(TRANSFORM_INT, "example.com", 60)
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#TRANSFORM_IP6
+ */
+declare function TRANSFORM_IP6(cidr: string, newBase: string | string[]): { cidr: string; newBase: string | string[] };
+
 /**
  * `getConfiguredDomains` getConfiguredDomains is a helper function that returns the domain names
  * configured at the time the function is called. Calling this function early or later in
@@ -1714,6 +2465,48 @@ declare function REV(address: string): string;
  */
 declare function getConfiguredDomains(): string[];
 
+/**
+ * `require_csv(...)` reads a CSV file and returns its rows, so that data
+ * such as an inventory of hosts can be kept in a spreadsheet-friendly file
+ * instead of in the JavaScript code. Like with
+ * [`require_glob()`](https://dnscontrol.org/js#require_glob), a relative path is relative to the
+ * file that calls `require_csv()`.
+ * 
+ * By default, the first row holds the names of the columns, and each of
+ * the other rows is returned as an object whose keys are these names. All
+ * the values are strings. Lines that start with `#` are comments, and the
+ * spaces after a separator are ignored. A row with more or fewer fields
+ * than the first row is an error.
+ * 
+ * The options are:
+ * 
+ * - `header`: if `false`, the first row is data too, and each row is returned as an array of strings. Default is `true`.
+ * - `separator`: the character between the fields. Default is `,`.
+ * 
+ * `dataFiles/hosts.csv`:
+ * 
+ * ```text
+ * name,ip
+ * db1, 10.2.4.1
+ * db2, 10.2.4.2
+ * ```
+ * 
+ * ```js
+ * var db = require_csv("dataFiles/hosts.csv");
+ * 
+ * D("example.com", REG, DnsProvider(DNS),
+ *   db.map(function(h) { return A(h.name, h.ip); })
+ * );
+ * 
+ * // Without a header row:
+ * var rows = require_csv("dataFiles/hosts.csv", {header: false});
+ * // rows[1] is ["db1", "10.2.4.1"]
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#require_csv
+ */
+declare function require_csv(path: string, options?: { header?: boolean, separator?: string }): any[];
+
 /**
  * `require_glob()` can recursively load `.js` files, optionally non-recursive as well.
  * 
@@ -1756,6 +2549,47 @@ declare function getConfiguredDomains(): string[];
  */
 declare function require_glob(path: string, recursive: boolean): void;
 
+/**
+ * `require_yaml(...)` reads a YAML file and returns its contents, so that
+ * data such as lists of hosts can be kept in a data file instead of in
+ * the JavaScript code. Like with [`require_glob()`](https://dnscontrol.org/js#require_glob), a
+ * relative path is relative to the file that calls `require_yaml()`.
+ * 
+ * Mappings become objects, sequences become arrays, and scalars become
+ * strings, numbers, booleans or `null`. Only the first document of the
+ * file is read.
+ * 
+ * `dataFiles/hosts.yaml`:
+ * 
+ * ```yaml
+ * ttl: 600
+ * hosts:
+ *   - name: web1
+ *     ip: 10.2.3.1
+ *   - name: web2
+ *     ip: 10.2.3.2
+ * ```
+ * 
+ * ```js
+ * var web = require_yaml("dataFiles/hosts.yaml");
+ * 
+ * D("example.com", REG, DnsProvider(DNS),
+ *   web.hosts.map(function(h) {
+ *     return A(h.name, h.ip, TTL(web.ttl));
+ *   })
+ * );
+ * 
+ * D(REV("10.2.3.0/24"), REG, DnsProvider(DNS),
+ *   web.hosts.map(function(h) {
+ *     return PTR(h.ip, h.name + ".example.com.");
+ *   })
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#require_yaml
+ */
+declare function require_yaml(path: string): any;
+
 /**
  * DNSControl contains a `CAA_BUILDER` which can be used to simply create
  * CAA records for your domains. Instead of creating each CAA record
@@ -1782,10 +2616,18 @@ declare function require_glob(path: string, recursive: boolean): void;
  * The parameters are:
  * 
  * * `label:` The label of the CAA record. (Optional. Default: `"@"`)
- * * `iodef:` Report all violation to configured mail address.
+ * * `iodef:` Report all violation to configured mail address. An array of `mailto:`, `http:` or `https:` URLs creates one record for each. (Optional)
  * * `iodef_critical:` This can be `true` or `false`. If enabled and CA does not support this record, then certificate issue will be refused. (Optional. Default: `false`)
  * * `issue:` An array of CAs which are allowed to issue certificates. (Use `"none"` to refuse all CAs)
+ * * `issue_critical:` This can be `true` or `false`. Sets the critical flag on the `issue` records. (Optional. Default: `false`)
+ * * `issue_profile:` CAs of the catalog below, joined by `+`, to add to `issue`. (Optional)
  * * `issuewild:` An array of CAs which are allowed to issue wildcard certificates. (Can be simply `"none"` to refuse issuing wildcard certificates for all CAs)
+ * * `issuewild_critical:` Like `issue_critical`, for the `issuewild` records. (Optional. Default: `false`)
+ * * `issuewild_profile:` Like `issue_profile`, for `issuewild`. (Optional)
+ * 
+ * At least one of `issue`, `issue_profile`, `issuewild` and `issuewild_profile` is required.
+ * Without `issue` or `issue_profile`, only the wildcard certificates are restricted:
+ * any CA may issue the others.
  * 
  * `CAA_BUILDER()` returns multiple records (when configured as example above):
  * 
@@ -1794,9 +2636,42 @@ declare function require_glob(path: string, recursive: boolean): void;
  *   * `CAA("@", "issue", "comodoca.com")`
  *   * `CAA("@", "issuewild", ";")`
  * 
+ * ## Profiles
+ * 
+ * The profiles are names of CAs that stand for their issuer domain names:
+ * 
+ * | Profile       | Issuer domain names                                          |
+ * |---------------|--------------------------------------------------------------|
+ * | `amazon`      | `amazon.com`, `amazontrust.com`, `awstrust.com`, `amazonaws.com` |
+ * | `buypass`     | `buypass.com`                                                |
+ * | `digicert`    | `digicert.com`                                               |
+ * | `globalsign`  | `globalsign.com`                                             |
+ * | `google`      | `pki.goog`                                                   |
+ * | `letsencrypt` | `letsencrypt.org`                                            |
+ * | `sectigo`     | `sectigo.com`                                                |
+ * | `zerossl`     | `sectigo.com`                                                |
+ * 
+ * ```js
+ * CAA_BUILDER({
+ *   iodef: ["mailto:security@example.com", "https://example.com/caa-report"],
+ *   issue_profile: "letsencrypt+amazon+google",
+ *   issuewild: "none",
+ * })
+ * ```
+ * 
+ * ## Validation
+ * 
+ * These CAA records, whether they come from `CAA_BUILDER` or `CAA`, are rejected when `dnsconfig.js` is checked:
+ * 
+ * * flags other than `0` and `128` (`CAA_CRITICAL`),
+ * * `iodef` values that aren't `mailto:`, `http:` or `https:` URLs,
+ * * a label with both `issue ";"` (no CA) and an `issue` for a CA (or the same for `issuewild`).
+ * 
+ * A label with only `iodef` records gets a warning, as they don't restrict any CA.
+ * 
  * @see https://dnscontrol.org/js#CAA_BUILDER
  */
-declare function CAA_BUILDER(opts: { label?: string; iodef: string; iodef_critical?: boolean; issue: string[]; issuewild: string }): RecordModifier;
+declare function CAA_BUILDER(opts: { label?: string; iodef?: string | string[]; iodef_critical?: boolean; issue?: string | string[]; issue_critical?: boolean; issue_profile?: string; issuewild?: string | string[]; issuewild_critical?: boolean; issuewild_profile?: string }): RecordModifier;
 
 /**
  * DNSControl contains a `DMARC_BUILDER` which can be used to simply create
@@ -1880,16 +2755,167 @@ declare function CAA_BUILDER(opts: { label?: string; iodef: string; iodef_critic
  * ### Caveats
  * 
  * * TXT records are automatically split using `AUTOSPLIT`.
- * * URIs in the `rua` and `ruf` arrays are passed raw. You must percent-encode all commas and exclamation points in the URI itself.
+ * * URIs in the `rua` and `ruf` arrays must be `mailto:` addresses or `http(s):` URLs, optionally followed by a size limit such as `!10m`. They are otherwise passed raw, so you must percent-encode all commas and exclamation points in the URI itself.
+ * * The generated record is validated when `dnscontrol check`, `preview` or `push` runs. An invalid record (for example, a `pct` outside 0 to 100 or an unknown `rf` format) is an error.
  * 
  * @see https://dnscontrol.org/js#DMARC_BUILDER
  */
 declare function DMARC_BUILDER(opts: { label?: string; version?: string; policy: 'none' | 'quarantine' | 'reject'; subdomainPolicy?: 'none' | 'quarantine' | 'reject'; alignmentSPF?: 'strict' | 's' | 'relaxed' | 'r'; alignmentDKIM?: 'strict' | 's' | 'relaxed' | 'r'; percent?: number; rua?: string[]; ruf?: string[]; failureOptions?: { SPF: boolean, DKIM: boolean } | string; failureFormat?: string; reportInterval?: Duration; ttl?: Duration }): RecordModifier;
 
+/**
+ * GCLOUD_GEO adds a record to the item of a geolocation routing policy
+ * that is served to clients near the Google Cloud region `location` (for
+ * example `us-east1`).  The records with the same name, type and location
+ * are one item of the policy.
+ * 
+ * All the records with the same name and type must have a GCLOUD_GEO()
+ * modifier.  See the [Google Cloud DNS provider documentation](https://dnscontrol.org//providers/gcloud#metadata)
+ * for details.
+ * 
+ * ```js
+ * D("example.com", REG_NONE, DnsProvider(DSP_GCLOUD),
+ *   A("www", "192.0.2.1", GCLOUD_GEO("us-east1")),
+ *   A("www", "192.0.2.2", GCLOUD_GEO("us-east1")),
+ *   A("www", "192.0.2.3", GCLOUD_GEO("europe-west1"))
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#GCLOUD_GEO
+ */
+declare function GCLOUD_GEO(location: string): RecordModifier;
+
+/**
+ * GCLOUD_WRR adds a record to the item of a weighted round robin routing
+ * policy with the weight `weight`.  Each item is served in proportion to
+ * its weight.  The records with the same name, type and weight are one
+ * item of the policy.
+ * 
+ * All the records with the same name and type must have a GCLOUD_WRR()
+ * modifier.  See the [Google Cloud DNS provider documentation](https://dnscontrol.org//providers/gcloud#metadata)
+ * for details.
+ * 
+ * ```js
+ * D("example.com", REG_NONE, DnsProvider(DSP_GCLOUD),
+ *   A("api", "192.0.2.1", GCLOUD_WRR(0.8)),
+ *   A("api", "192.0.2.2", GCLOUD_WRR(0.2))
+ * );
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#GCLOUD_WRR
+ */
+declare function GCLOUD_WRR(weight: number): RecordModifier;
+
+/**
+ * `LOC_BUILDER_DD` creates a [`LOC`](https://dnscontrol.org/js#LOC) record from a latitude and
+ * longitude in decimal degrees. Negative latitudes are south of the
+ * equator; negative longitudes are west of the prime meridian.
+ * 
+ * Altitude (`alt`, default 0) and size/precision (`siz`, `hp`, `vp`,
+ * defaults 1, 10000 and 10) are in meters. `label` defaults to `@`.
+ * 
+ * ## Example
+ * 
+ * ```js
+ * D("example.com", REGISTRAR, DnsProvider("BIND"),
+ *   LOC_BUILDER_DD({
+ *     label: "office",
+ *     lat: 51.5007,
+ *     lon: -0.1246,
+ *     alt: 10,
+ *   }),
+ * );
+ * ```
+ * 
+ * This generates the record:
+ * 
+ * ```text
+ * office IN LOC 51 30 2.520 N 0 7 28.560 W 10m 1m 10000m 10m
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#LOC_BUILDER_DD
+ */
+declare function LOC_BUILDER_DD(opts: { label?: string; lat: number; lon: number; alt?: number; siz?: number; hp?: number; vp?: number; ttl?: Duration }): RecordModifier;
+
+/**
+ * `LOC_BUILDER_DMS_STR` creates a [`LOC`](https://dnscontrol.org/js#LOC) record from a string of
+ * coordinates in degrees, minutes and seconds. Both the symbols commonly
+ * used on maps (`33°51′31″S 151°12′51″E`) and the zonefile format
+ * (`33 51 31 S 151 12 51 E`) are accepted.
+ * 
+ * Altitude (`alt`, default 0) and size/precision (`siz`, `hp`, `vp`,
+ * defaults 1, 10000 and 10) are in meters. `label` defaults to `@`.
+ * 
+ * ## Example
+ * 
+ * ```js
+ * D("example.com", REGISTRAR, DnsProvider("BIND"),
+ *   LOC_BUILDER_DMS_STR({
+ *     label: "sydney",
+ *     str: "33°51′31″S 151°12′51″E",
+ *   }),
+ * );
+ * ```
+ * 
+ * This generates the record:
+ * 
+ * ```text
+ * sydney IN LOC 33 51 31.000 S 151 12 51.000 E 0m 1m 10000m 10m
+ * ```
+ * 
+ * @see https://dnscontrol.org/js#LOC_BUILDER_DMS_STR
+ */
+declare function LOC_BUILDER_DMS_STR(opts: { label?: string; str: string; alt?: number; siz?: number; hp?: number; vp?: number; ttl?: Duration }): RecordModifier;
+
+/**
+ * DNSControl contains an `MTA_STS_BUILDER` which can be used to create
+ * the DNS records needed for [MTA-STS](https://www.rfc-editor.org/rfc/rfc8461)
+ * and, optionally, [SMTP TLS reporting](https://www.rfc-editor.org/rfc/rfc8460).
+ * 
+ * The policy itself is a file served over HTTPS from
+ * `https://mta-sts.<domain>/.well-known/mta-sts.txt`. DNSControl does
+ * not create that file. Whenever you change it, change `id` too so
+ * that senders fetch the new policy.
+ * 
+ * ## Example
+ * 
+ * ```js
+ * MTA_STS_BUILDER({
+ *   id: "20230101T000000",
+ *   policyHost: "mta-sts.hosting.example.net.",
+ *   tlsrpt: [
+ *     "mailto:tlsrpt@example.com",
+ *   ],
+ * })
+ * ```
+ * 
+ * This yields the following records:
+ * 
+ * ```text
+ * _mta-sts    IN  TXT   "v=STSv1; id=20230101T000000"
+ * mta-sts     IN  CNAME mta-sts.hosting.example.net.
+ * _smtp._tls  IN  TXT   "v=TLSRPTv1; rua=mailto:tlsrpt@example.com"
+ * ```
+ * 
+ * ### Parameters
+ * 
+ * * `label:` The DNS label of the mail domain (the `_mta-sts` and `mta-sts` prefixes are added, default: `'@'`)
+ * * `id:` The id of the current policy, 1 to 32 letters and digits
+ * * `policyHost:` The host that serves the policy file. `mta-sts` is made a CNAME to it. If not set, no CNAME is created and you must create the `mta-sts` records yourself. (optional)
+ * * `tlsrpt:` Array of SMTP TLS report targets, `mailto:` or `https:` URIs. If set, a TLSRPT record is created at `_smtp._tls`. (optional)
+ * * `ttl:` Input for `TTL` method (optional)
+ * 
+ * ### Caveats
+ * 
+ * * The TXT records are validated when `dnscontrol check`, `preview` or `push` runs. An invalid record is an error.
+ * 
+ * @see https://dnscontrol.org/js#MTA_STS_BUILDER
+ */
+declare function MTA_STS_BUILDER(opts: { label?: string; id: string | number; policyHost?: string; tlsrpt?: string[]; ttl?: Duration }): RecordModifier;
+
 /**
  * R53_ZONE lets you specify the AWS Zone ID for an entire domain (D()) or a specific R53_ALIAS() record.
  * 
- * When used with D(), it sets the zone id of the domain. This can be used to differentiate between split horizon domains in public and private zones.
+ * When used with D(), it sets the zone id of the domain. This can be used to differentiate between split horizon domains in public and private zones. If there is only one private zone with the domain's name, it is used without the zone id when there is no public zone, or with the `private` domain metadata when there is one; see the [Route 53 provider documentation](https://dnscontrol.org//providers/route53#private-zones).
  * 
  * When used with R53_ALIAS() it sets the required Route53 hosted zone id in a R53_ALIAS record. See [R53_ALIAS's documentation](https://stackexchange.github.io/dnscontrol/js#R53_ALIAS) for details.
  * 
@@ -2002,6 +3028,8 @@ declare function R53_ZONE(zone_id: string): DomainModifier & RecordModifier;
  * * `txtMaxSize` The maximum size for each TXT record. Values over 255 will result in [multiple strings][multi-string]. General recommendation is to [not go higher than 450][record-size] so that DNS responses will still fit in a UDP packet. (Optional. Default: `"255"`)
  * * `parts:` The individual parts of the SPF settings.
  * * `flatten:` Which includes should be inlined. For safety purposes the flattening is done on an opt-in basis. If `"*"` is listed, all includes will be flattened... this might create more problems than is solves due to length limitations.
+ * * `cacheFile:` The file that caches the DNS lookups done while flattening. Use a different file for each domain (or group of domains) to keep their caches apart. (Optional. Default: `"spfcache.json"`)
+ * * `lookupLimit:` The maximum number of DNS lookups the published SPF record may require, counting the includes that chain split records together. If the record needs more, validation fails. (Optional. Default: no limit)
  * 
  * [multi-string]: https://tools.ietf.org/html/rfc4408#section-3.1.3
  * [record-size]: https://tools.ietf.org/html/rfc4408#section-3.1.4
@@ -2093,8 +3121,20 @@ declare function R53_ZONE(zone_id: string): DomainModifier & RecordModifier;
  * Needing to do this kind of update is considered a validation error
  * and will block `dnscontrol push` from running.
  * 
- * Note: The instructions are hardcoded strings. The filenames will
- * not change.
+ * If `cacheFile` is set, that file is used instead of `spfcache.json`
+ * and the updated data is written next to it: `spfcache-example.json`
+ * becomes `spfcache-example.updated.json`. For example, to keep a
+ * cache per domain:
+ * 
+ * ```js
+ * D("example.tld", REG, DSP,
+ *   SPF_BUILDER({
+ *     cacheFile: "spfcache-example.tld.json",
+ *     parts: [ ... ],
+ *     flatten: [ ... ]
+ *   }),
+ * );
+ * ```
  * 
  * Note: The instructions assume you use git. If you use something
  * else, please do the appropriate equivalent command.
@@ -2116,10 +3156,18 @@ declare function R53_ZONE(zone_id: string): DomainModifier & RecordModifier;
  * domain ownership), the total packet size of all the TXT records
  * could exceed 512 bytes, and will require EDNS or a TCP request.
  * 
- * 3. Dnscontrol does not warn if the number of lookups exceeds 10.
- * We hope to implement this some day.
+ * 3. Dnscontrol does not check the number of lookups unless `lookupLimit`
+ * is set. Set it to `10` to enforce the limit in the RFC.
  * 
- * 4. The `redirect=` directive is only partially implemented.  We only
+ * 4. DNSControl asks each of the domain's DNS providers whether it
+ * can store the generated records. If a provider can't store a TXT
+ * record with several strings, the records are split again with a
+ * `txtMaxSize` of 255. If a provider can't store the flattened record
+ * at all (for example, it is longer than 255 bytes and `overflow` is
+ * not set), a warning is printed and the unflattened record is used
+ * instead.
+ * 
+ * 5. The `redirect=` directive is only partially implemented.  We only
  * handle the case where redirect is the last item in the SPF record.
  * In which case, it is equivalent to `include:`.
  * 
@@ -2182,7 +3230,79 @@ declare function R53_ZONE(zone_id: string): DomainModifier & RecordModifier;
  * 
  * @see https://dnscontrol.org/js#SPF_BUILDER
  */
-declare function SPF_BUILDER(opts: { label?: string; overflow?: string; overhead1?: string; raw?: string; ttl?: Duration; txtMaxSize: string[]; parts?: number; flatten?: string[] }): RecordModifier;
+declare function SPF_BUILDER(opts: { label?: string; overflow?: string; overhead1?: string; raw?: string; ttl?: Duration; txtMaxSize: string[]; parts?: number; flatten?: string[]; cacheFile?: string; lookupLimit?: number }): RecordModifier;
+
+/**
+ * DNSControl contains an `SRV_BUILDER` which creates the [SRV](SRV.md)
+ * records of a service: their `_service._proto` names, and the ports of
+ * the well-known services, are filled in for you.
+ * 
+ * ## Example
+ * 
+ * ```js
+ * SRV_BUILDER({
+ *   service: "xmpp",
+ *   targets: [
+ *     "xmpp1.example.com.",
+ *     { target: "xmpp2.example.com.", priority: 20 },
+ *   ],
+ * })
+ * ```
+ * 
+ * This yields the following records:
+ * 
+ * ```text
+ * _xmpp-client._tcp  IN  SRV  10 10 5222 xmpp1.example.com.
+ * _xmpp-client._tcp  IN  SRV  20 10 5222 xmpp2.example.com.
+ * _xmpp-server._tcp  IN  SRV  10 10 5269 xmpp1.example.com.
+ * _xmpp-server._tcp  IN  SRV  20 10 5269 xmpp2.example.com.
+ * ```
+ * 
+ * A service that is not available in the domain is announced with
+ * `targets: "none"`, which creates a single `0 0 0 .` record
+ * ([RFC 2782](https://www.rfc-editor.org/rfc/rfc2782)):
+ * 
+ * ```js
+ * SRV_BUILDER({
+ *   service: "submission",
+ *   targets: "none",
+ * })
+ * ```
+ * 
+ * ### Known services
+ * 
+ * | `service`          | Records                                                                                                    |
+ * |--------------------|------------------------------------------------------------------------------------------------------------|
+ * | `active-directory` | `_ldap._tcp` and `_ldap._tcp.dc._msdcs` (389), `_kerberos._tcp/_udp` and `_kerberos._tcp.dc._msdcs` (88), `_kpasswd._tcp/_udp` (464), `_gc._tcp` (3268) |
+ * | `autodiscover`     | `_autodiscover._tcp` (443)                                                                                 |
+ * | `caldavs`          | `_caldavs._tcp` (443)                                                                                      |
+ * | `carddavs`         | `_carddavs._tcp` (443)                                                                                     |
+ * | `imaps`            | `_imaps._tcp` (993)                                                                                        |
+ * | `minecraft`        | `_minecraft._tcp` (25565)                                                                                  |
+ * | `sip`              | `_sip._udp`, `_sip._tcp` (5060), `_sips._tcp` (5061)                                                       |
+ * | `submission`       | `_submission._tcp` (587)                                                                                   |
+ * | `xmpp`             | `_xmpp-client._tcp` (5222), `_xmpp-server._tcp` (5269)                                                     |
+ * 
+ * Any other service can be used with both `proto` and `port`.
+ * 
+ * ### Parameters
+ * 
+ * * `label:` The DNS label under which the service is published (the `_service._proto` prefix is added, default: `'@'`)
+ * * `service:` The service: one of the known services above, or any other service name
+ * * `proto:` The protocol (`tcp`, `udp`, `tls`...). With a known service, only the record of this protocol is created. Required for the other services. (optional)
+ * * `port:` The port of the service, for all its records. Required for the services that aren't known. (optional)
+ * * `targets:` The hosts that provide the service: a hostname, or an array of hostnames or of `{target, priority, weight, port}` objects, whose fields override the others. `"none"` if the service isn't available.
+ * * `priority:` The priority of the targets (default: `10`)
+ * * `weight:` The weight of the targets (default: `10`)
+ * * `ttl:` Input for `TTL` method (optional)
+ * 
+ * ### Caveats
+ * 
+ * * The records are validated when `dnscontrol check`, `preview` or `push` runs. A `"."` target with a port other than 0 is an error.
+ * 
+ * @see https://dnscontrol.org/js#SRV_BUILDER
+ */
+declare function SRV_BUILDER(opts: { label?: string; service: string; proto?: string; port?: number; targets: string | (string | {target: string, priority?: number, weight?: number, port?: number})[]; priority?: number; weight?: number; ttl?: Duration }): RecordModifier;
 
 /**
  * TTL sets the TTL for a single record only. This will take precedence
@@ -7,10 +7,18 @@
  *
  * `FETCH` is not enabled by default. Please read the warnings below.
  *
+ * To restrict `FETCH` to the endpoints you trust, use `--fetch-allow` instead of `--allow-fetch`. It takes a comma-separated list, and can be repeated. An entry is either a URL prefix, such as `https://ipam.example.com/api/`, or a host name, such as `ipam.example.com`, which allows any `https` URL of that host. Any other URL, including the target of a redirect, is rejected.
+ *
+ * Each `GET` request is made at most once per run: fetching the same URL twice gives the same response. With `--fetch-cache=DIR`, successful responses are also kept in `DIR`, and used instead of a new request for `--fetch-cache-ttl` (default: `1h`). An older response is used when the request fails, so that a short outage of the data source doesn't break your builds.
+ *
+ * ```shell
+ * dnscontrol preview --fetch-allow=https://ipam.example.com/api/ --fetch-cache=.fetch-cache
+ * ```
+ *
  * > WARNING:
  * >
  * > 1. Relying on external sources adds a point of failure. If the external source doesn't work, your script won't either. Please make sure you are aware of the consequences.
- * > 2. Make sure DNSControl only uses verified configuration if you want to use `FETCH`. For example, an attacker can send Pull Requests to your config repo, and have your CI test malicious configurations and make arbitrary HTTP requests. Therefore, `FETCH` must be explicitly enabled with flag `--allow-fetch` on DNSControl invocation.
+ * > 2. Make sure DNSControl only uses verified configuration if you want to use `FETCH`. For example, an attacker can send Pull Requests to your config repo, and have your CI test malicious configurations and make arbitrary HTTP requests. Therefore, `FETCH` must be explicitly enabled with flag `--allow-fetch` or `--fetch-allow` on DNSControl invocation.
  *
  * ```js
  * var REG_NONE = NewRegistrar('none');
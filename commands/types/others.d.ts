@@ -34,6 +34,10 @@ declare const CF_PROXY_DEFAULT_ON: DomainModifier;
 declare const CF_UNIVERSALSSL_OFF: DomainModifier;
 /** UniversalSSL on for entire domain */
 declare const CF_UNIVERSALSSL_ON: DomainModifier;
+/** CNAME flattening only at the apex for entire domain */
+declare const CF_CNAME_FLATTEN_AT_ROOT: DomainModifier;
+/** CNAME flattening of all CNAMEs for entire domain */
+declare const CF_CNAME_FLATTEN_ALL: DomainModifier;
 
 /**
  * Set default values for CLI variables. See: https://dnscontrol.org/cli-variables
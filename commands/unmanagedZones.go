@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+// reportUnmanagedZones warns about the zones that exist at the DNS
+// providers used in dnsconfig.js, but that dnsconfig.js doesn't have
+// (with that provider). It returns whether a provider failed to list
+// its zones. See --report-unmanaged.
+func reportUnmanagedZones(args PreviewArgs, cfg *models.DNSConfig, out printer.CLI) (anyErrors bool) {
+	instances := map[string]*models.DNSProviderInstance{}
+	for _, dc := range cfg.Domains {
+		for _, p := range dc.DNSProviderInstances {
+			instances[p.Name] = p
+		}
+	}
+	var names []string
+	for name := range instances {
+		if args.Providers == "" || args.Providers == "all" || providerListed(args.Providers, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		lister, ok := instances[name].Driver.(providers.ZoneLister)
+		if !ok {
+			out.Debugf("%s can't list its zones; not checking for unmanaged zones\n", name)
+			continue
+		}
+		zones, err := lister.ListZones()
+		if err != nil {
+			out.Warnf("Can't list the zones of %s: %s\n", name, err)
+			anyErrors = true
+			continue
+		}
+		sort.Strings(zones)
+		for _, zone := range zones {
+			switch classifyZone(cfg, name, zone).Status {
+			case zoneUnmanaged:
+				out.Warnf("UNMANAGED ZONE: %s exists at %s but not in dnsconfig.js\n", zone, name)
+			case zoneOtherProvider:
+				out.Warnf("UNMANAGED ZONE: %s exists at %s but dnsconfig.js uses other DNS providers for it\n", zone, name)
+			}
+		}
+	}
+	return anyErrors
+}
+
+// providerListed reports whether name is in list, a comma-separated
+// list of provider names (--providers).
+func providerListed(list, name string) bool {
+	for _, p := range strings.Split(list, ",") {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
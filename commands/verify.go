@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/verify"
+)
+
+// verifyZone implements push --verify: it checks that the nameservers
+// of provider serve the records of dc that changed since before. It
+// returns true if they don't.
+func verifyZone(args PreviewArgs, dc *models.DomainConfig, provider *models.DNSProviderInstance, before models.Records, out printer.CLI) (anyErrors bool) {
+	// With IGNORE or NO_PURGE, the records missing from dnsconfig.js
+	// are kept on purpose.
+	removals := len(dc.Unmanaged) == 0 && !dc.KeepUnknown
+	checks := verify.Sample(verify.Changes(before, dc.Records, removals), args.VerifySample)
+	if len(checks) == 0 {
+		return false
+	}
+	nss, err := provider.Driver.GetNameservers(dc.Name)
+	if err != nil {
+		out.Warnf("VERIFY: %s at %s: can't get the nameservers: %s\n", dc.Name, provider.Name, err)
+		return true
+	}
+	var servers []string
+	for _, ns := range nss {
+		servers = append(servers, strings.TrimSuffix(ns.Name, "."))
+	}
+	if len(servers) == 0 {
+		out.Warnf("VERIFY: %s at %s: the provider doesn't say which nameservers serve the zone; not verified\n", dc.Name, provider.Name)
+		return false
+	}
+
+	plural := "s"
+	if len(checks) == 1 {
+		plural = ""
+	}
+	out.Printf("Verifying %d RRset%s of %s on %s\n", len(checks), plural, dc.Name, strings.Join(servers, ", "))
+	failures := verify.Run(servers, checks, verify.Options{
+		Timeout:  args.VerifyTimeout,
+		Interval: args.VerifyInterval,
+	})
+	for _, f := range failures {
+		out.Warnf("VERIFY: %s\n", f)
+	}
+	return len(failures) != 0
+}
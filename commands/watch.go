@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	"github.com/StackExchange/dnscontrol/v3/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/urfave/cli/v2"
+)
+
+var _ = cmd(catMain, func() *cli.Command {
+	var args WatchArgs
+	return &cli.Command{
+		Name:  "watch",
+		Usage: "run preview repeatedly and report drift between dnsconfig.js and the providers",
+		Action: func(ctx *cli.Context) error {
+			return exit(Watch(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// WatchArgs contains all data/flags needed to run watch, independently of CLI
+type WatchArgs struct {
+	PreviewArgs
+	Interval    time.Duration
+	WatchConfig bool
+	DriftFile   string
+}
+
+func (args *WatchArgs) flags() []cli.Flag {
+	flags := args.PreviewArgs.flags()
+	flags = append(flags, &cli.DurationFlag{
+		Name:        "interval",
+		Destination: &args.Interval,
+		Value:       5 * time.Minute,
+		Usage:       `Time between previews`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "watch-config",
+		Destination: &args.WatchConfig,
+		Usage:       `Also run a preview as soon as the config file changes`,
+	})
+	flags = append(flags, &cli.StringFlag{
+		Name:        "drift-file",
+		Destination: &args.DriftFile,
+		Usage:       `Write the result of each preview to this file as JSON`,
+	})
+	return flags
+}
+
+// configPollInterval is how often --watch-config checks the config file.
+const configPollInterval = 5 * time.Second
+
+// driftReport is written to the --drift-file after each preview.
+type driftReport struct {
+	Time  time.Time             `json:"time"`
+	Drift []collectedCorrection `json:"drift"`
+	New   int                   `json:"new"`
+	Error string                `json:"error,omitempty"`
+}
+
+// newDrift returns the items of cur that are not in prev.
+func newDrift(prev, cur []collectedCorrection) []collectedCorrection {
+	seen := make(map[collectedCorrection]bool, len(prev))
+	for _, item := range prev {
+		seen[item] = true
+	}
+	var added []collectedCorrection
+	for _, item := range cur {
+		if !seen[item] {
+			added = append(added, item)
+		}
+	}
+	return added
+}
+
+// Watch implements the watch subcommand. It runs a preview every
+// args.Interval (and, with --watch-config, whenever the config file
+// changes) until it is interrupted. Corrections that weren't pending
+// in the previous preview are sent to the notifiers, so that
+// out-of-band changes are reported once rather than on every run.
+func Watch(args WatchArgs) error {
+	if args.Interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	printer.SkinnyReport = !args.Full
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var prev []collectedCorrection
+	lastMod := configModTime(args.JSFile)
+	poll := time.NewTicker(configPollInterval)
+	defer poll.Stop()
+	for {
+		drift, err := watchOnce(args, prev)
+		if err != nil {
+			printer.Printf("%s: preview failed: %s\n", time.Now().Format(time.RFC3339), err)
+		} else {
+			prev = drift
+		}
+
+		next := time.NewTimer(args.Interval)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				next.Stop()
+				return nil
+			case <-next.C:
+				break wait
+			case <-poll.C:
+				if !args.WatchConfig {
+					continue
+				}
+				if mod := configModTime(args.JSFile); !mod.Equal(lastMod) {
+					lastMod = mod
+					printer.Printf("%s: %s changed\n", time.Now().Format(time.RFC3339), args.JSFile)
+					next.Stop()
+					break wait
+				}
+			}
+		}
+	}
+}
+
+// watchOnce runs one preview and reports the drift that wasn't in
+// prev.
+func watchOnce(args WatchArgs, prev []collectedCorrection) (drift []collectedCorrection, err error) {
+	now := time.Now()
+	defer func() {
+		if args.DriftFile == "" {
+			return
+		}
+		report := driftReport{Time: now.UTC(), Drift: drift, New: len(newDrift(prev, drift))}
+		if err != nil {
+			report.Error = err.Error()
+		}
+		if report.Drift == nil {
+			report.Drift = []collectedCorrection{}
+		}
+		if werr := writeDriftFile(args.DriftFile, report); werr != nil && err == nil {
+			err = werr
+		}
+	}()
+
+	drift, err = previewDrift(args.PreviewArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	added := newDrift(prev, drift)
+	printer.Printf("%s: %d corrections pending, %d new\n", now.Format(time.RFC3339), len(drift), len(added))
+	if len(added) == 0 {
+		return drift, nil
+	}
+
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return nil, err
+	}
+	var notifier notifications.Notifier = notifications.Init(nil)
+	if args.Notify {
+		notifier = notifications.Init(providerConfigs["notifications"])
+	}
+	for _, item := range added {
+		printer.Printf("    %s %s: %s\n", item.Domain, item.Provider, item.Message)
+		notifier.Notify(item.Domain, item.Provider, item.Message, nil, true)
+	}
+	notifier.Done()
+	return drift, nil
+}
+
+// previewDrift returns the corrections that a preview would print.
+func previewDrift(args PreviewArgs) ([]collectedCorrection, error) {
+	drift, output, anyErrors, err := collectCorrections(args, false)
+	// The usual preview output is only shown if something went wrong.
+	if err != nil || anyErrors || args.Full {
+		printer.Printf("%s", output)
+	}
+	return drift, err
+}
+
+func configModTime(filename string) time.Time {
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+// writeDriftFile writes the report atomically, so that a reader never
+// sees a partial file.
+func writeDriftFile(filename string, report driftReport) error {
+	dat, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, append(dat, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewDrift(t *testing.T) {
+	a := collectedCorrection{Domain: "example.com", Provider: "bind", Message: "+ CREATE a.example.com A 1.2.3.4"}
+	b := collectedCorrection{Domain: "example.com", Provider: "bind", Message: "- DELETE b.example.com A 1.2.3.4"}
+	c := collectedCorrection{Domain: "example.net", Provider: "bind", Message: "+ CREATE a.example.net A 1.2.3.4"}
+
+	tests := []struct {
+		name      string
+		prev, cur []collectedCorrection
+		want      []collectedCorrection
+	}{
+		{"first", nil, []collectedCorrection{a, b}, []collectedCorrection{a, b}},
+		{"unchanged", []collectedCorrection{a, b}, []collectedCorrection{a, b}, nil},
+		{"resolved", []collectedCorrection{a, b}, []collectedCorrection{b}, nil},
+		{"added", []collectedCorrection{a}, []collectedCorrection{a, c}, []collectedCorrection{c}},
+		{"none", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newDrift(tt.prev, tt.cur); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newDrift() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteDriftFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "drift.json")
+	report := driftReport{
+		Time:  time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Drift: []collectedCorrection{{Domain: "example.com", Provider: "bind", Message: "+ CREATE a.example.com A 1.2.3.4"}},
+		New:   1,
+	}
+	if err := writeDriftFile(filename, report); err != nil {
+		t.Fatal(err)
+	}
+	dat, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got driftReport
+	if err := json.Unmarshal(dat, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, report) {
+		t.Errorf("got %+v, want %+v", got, report)
+	}
+	if _, err := os.Stat(filename + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file was left behind")
+	}
+}
@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/net/idna"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args ZonesListArgs
+	return &cli.Command{
+		Name:  "zones",
+		Usage: "commands that act on the zones of all the DNS providers",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "list the zones of all the DNS providers in creds.json, and whether dnsconfig.js manages them",
+				Action: func(ctx *cli.Context) error {
+					return exit(ZonesList(args))
+				},
+				Flags: args.flags(),
+				Description: `List the zones of each DNS provider in creds.json that can list
+its zones, and compare them with the domains in dnsconfig.js.
+
+The STATUS column is one of:
+   managed         dnsconfig.js has the domain, with this DNS provider
+   other-provider  dnsconfig.js has the domain, but not with this DNS provider
+   unmanaged       dnsconfig.js doesn't have the domain
+
+The OWNER column is the MANAGED_BY() owner of the domain, if any.
+
+EXAMPLES:
+   dnscontrol zones list
+   dnscontrol zones list --unmanaged
+   dnscontrol zones list --format=json`,
+			},
+		},
+	}
+}())
+
+// ZonesListArgs contains all data/flags needed to run zones list, independently of CLI.
+type ZonesListArgs struct {
+	GetDNSConfigArgs
+	GetCredentialsArgs
+	OutputFormat  string // table or json
+	UnmanagedOnly bool   // Only list the unmanaged zones
+}
+
+func (args *ZonesListArgs) flags() []cli.Flag {
+	flags := args.GetDNSConfigArgs.flags()
+	flags = append(flags, args.GetCredentialsArgs.flags()...)
+	flags = append(flags, &cli.StringFlag{
+		Name:        "format",
+		Destination: &args.OutputFormat,
+		Value:       "table",
+		Usage:       `Output format: table json`,
+	})
+	flags = append(flags, &cli.BoolFlag{
+		Name:        "unmanaged",
+		Destination: &args.UnmanagedOnly,
+		Usage:       `Only list the zones that dnsconfig.js doesn't have`,
+	})
+	return flags
+}
+
+// The status of a zone at a DNS provider, compared with dnsconfig.js.
+const (
+	zoneManaged       = "managed"
+	zoneOtherProvider = "other-provider"
+	zoneUnmanaged     = "unmanaged"
+)
+
+// providerZone is a zone at a DNS provider.
+type providerZone struct {
+	Provider string `json:"provider"` // The name in creds.json
+	Type     string `json:"type"`
+	Zone     string `json:"zone"`
+	Status   string `json:"status"`
+	Owner    string `json:"owner,omitempty"` // MANAGED_BY()
+}
+
+// ZonesList implements the zones list subcommand.
+func ZonesList(args ZonesListArgs) error {
+	if args.OutputFormat != "table" && args.OutputFormat != "json" {
+		return fmt.Errorf("--format must be table or json, not %q", args.OutputFormat)
+	}
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return err
+	}
+	providerConfigs, err := credsfile.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range providerConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var zones []providerZone
+	var anyErrors bool
+	for _, name := range names {
+		pType := providerConfigs[name][providerTypeFieldName]
+		if _, ok := providers.DNSProviderTypes[pType]; !ok {
+			continue // A registrar, or no TYPE.
+		}
+		provider, err := providers.CreateDNSProvider(pType, providerConfigs[name], nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: %s: %s\n", name, err)
+			anyErrors = true
+			continue
+		}
+		lister, ok := provider.(providers.ZoneLister)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "WARNING: %s: provider type %s cannot list zones\n", name, pType)
+			continue
+		}
+		found, err := lister.ListZones()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING: %s: %s\n", name, err)
+			anyErrors = true
+			continue
+		}
+		sort.Strings(found)
+		for _, zone := range found {
+			z := classifyZone(cfg, name, zone)
+			z.Type = pType
+			if args.UnmanagedOnly && z.Status != zoneUnmanaged {
+				continue
+			}
+			zones = append(zones, z)
+		}
+	}
+
+	if err := writeZonesList(os.Stdout, zones, args.OutputFormat); err != nil {
+		return err
+	}
+	if anyErrors {
+		return fmt.Errorf("completed with errors")
+	}
+	return nil
+}
+
+// classifyZone returns how dnsconfig.js manages zone at provider (the
+// name in creds.json).
+func classifyZone(cfg *models.DNSConfig, provider, zone string) providerZone {
+	z := providerZone{Provider: provider, Zone: zone, Status: zoneUnmanaged}
+	name := canonicalZoneName(zone)
+	for _, dc := range cfg.Domains {
+		if canonicalZoneName(dc.Name) != name {
+			continue
+		}
+		if _, ok := dc.DNSProviderNames[provider]; ok {
+			z.Status = zoneManaged
+			z.Owner = dc.ManagedBy
+			return z
+		}
+		z.Status = zoneOtherProvider
+		z.Owner = dc.ManagedBy
+	}
+	return z
+}
+
+// canonicalZoneName returns name in the form used to compare the zone
+// names of providers with the domains in dnsconfig.js: in lowercase
+// ASCII, without the trailing dot.
+func canonicalZoneName(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if ascii, err := idna.ToASCII(name); err == nil {
+		return ascii
+	}
+	return name
+}
+
+func writeZonesList(w io.Writer, zones []providerZone, format string) error {
+	if format == "json" {
+		if zones == nil {
+			zones = []providerZone{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(zones)
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tTYPE\tZONE\tSTATUS\tOWNER")
+	for _, z := range zones {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", z.Provider, z.Type, z.Zone, z.Status, z.Owner)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	_ "github.com/StackExchange/dnscontrol/v3/providers/bind"
+)
+
+func TestClassifyZone(t *testing.T) {
+	cfg := &models.DNSConfig{Domains: []*models.DomainConfig{
+		{Name: "example.com", DNSProviderNames: map[string]int{"bind": -1}, ManagedBy: "prod"},
+		{Name: "example.net", DNSProviderNames: map[string]int{"r53": -1}},
+		{Name: "xn--bcher-kva.example", DNSProviderNames: map[string]int{"bind": -1}},
+	}}
+	tests := []struct {
+		provider, zone string
+		status, owner  string
+	}{
+		{"bind", "example.com", zoneManaged, "prod"},
+		{"bind", "EXAMPLE.com.", zoneManaged, "prod"},
+		{"bind", "example.net", zoneOtherProvider, ""},
+		{"bind", "bücher.example", zoneManaged, ""},
+		{"bind", "orphan.org", zoneUnmanaged, ""},
+	}
+	for _, tt := range tests {
+		z := classifyZone(cfg, tt.provider, tt.zone)
+		if z.Status != tt.status || z.Owner != tt.owner || z.Zone != tt.zone {
+			t.Errorf("classifyZone(%q, %q) = %+v, want status %q owner %q", tt.provider, tt.zone, z, tt.status, tt.owner)
+		}
+	}
+}
+
+func TestWriteZonesList(t *testing.T) {
+	zones := []providerZone{
+		{Provider: "bind", Type: "BIND", Zone: "example.com", Status: zoneManaged, Owner: "prod"},
+		{Provider: "bind", Type: "BIND", Zone: "orphan.org", Status: zoneUnmanaged},
+	}
+	var buf bytes.Buffer
+	if err := writeZonesList(&buf, zones, "table"); err != nil {
+		t.Fatal(err)
+	}
+	want := `PROVIDER  TYPE  ZONE         STATUS     OWNER
+bind      BIND  example.com  managed    prod
+bind      BIND  orphan.org   unmanaged  
+`
+	if buf.String() != want {
+		t.Errorf("table:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := writeZonesList(&buf, nil, "json"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("json of no zones = %q, want []", buf.String())
+	}
+}
+
+func TestReportUnmanaged(t *testing.T) {
+	dir := t.TempDir()
+	zonesDir := filepath.Join(dir, "zones")
+	if err := os.MkdirAll(zonesDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"creds.json":                `{"none": {"TYPE": "NONE"}, "bind": {"TYPE": "BIND", "directory": "` + zonesDir + `"}}`,
+		"dnsconfig.js":              `D("example.com", NewRegistrar("none"), DnsProvider(NewDnsProvider("bind")));`,
+		"zones/example.com.zone":    "$TTL 300\n@ IN SOA ns1.example.net. hostmaster.example.com. 1 3600 600 604800 1440\n",
+		"zones/orphan.example.zone": "$TTL 300\n@ IN SOA ns1.example.net. hostmaster.example.com. 1 3600 600 604800 1440\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o640); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	out := &printer.ConsolePrinter{Reader: bufio.NewReader(strings.NewReader("")), Writer: buf}
+	args := PreviewArgs{ReportUnmanaged: true}
+	args.JSFile = filepath.Join(dir, "dnsconfig.js")
+	args.CredsFile = filepath.Join(dir, "creds.json")
+	if err := run(args, false, false, out); err != nil {
+		t.Fatalf("preview: %s\n%s", err, buf)
+	}
+	if !strings.Contains(buf.String(), "UNMANAGED ZONE: orphan.example exists at bind but not in dnsconfig.js") {
+		t.Errorf("orphan.example wasn't reported:\n%s", buf)
+	}
+	if strings.Contains(buf.String(), "UNMANAGED ZONE: example.com") {
+		t.Errorf("example.com was reported:\n%s", buf)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -13,6 +14,7 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/mockapi"
 	"github.com/StackExchange/dnscontrol/v3/pkg/nameservers"
 	"github.com/StackExchange/dnscontrol/v3/pkg/normalize"
 	"github.com/StackExchange/dnscontrol/v3/providers"
@@ -27,6 +29,7 @@ var endIdx = flag.Int("end", 0, "Test index to stop after")
 var verbose = flag.Bool("verbose", false, "Print corrections as you run them")
 var printElapsed = flag.Bool("elapsed", false, "Print elapsed time for each testgroup")
 var enableCFWorkers = flag.Bool("cfworkers", true, "Set false to disable CF worker tests")
+var useMock = flag.Bool("mock", false, "Run against a mock of the provider's API (see pkg/mockapi)")
 
 func init() {
 	testing.Init()
@@ -50,6 +53,10 @@ func getProvider(t *testing.T) (providers.DNSServiceProvider, string, map[int]bo
 			continue
 		}
 
+		if *useMock {
+			cfg = mockCreds(t, name, cfg)
+		}
+
 		var metadata json.RawMessage
 		// CLOUDFLAREAPI tests related to CF_REDIRECT/CF_TEMP_REDIRECT
 		// requires metadata to enable this feature.
@@ -58,9 +65,9 @@ func getProvider(t *testing.T) (providers.DNSServiceProvider, string, map[int]bo
 		// feature at the time?
 		if name == "CLOUDFLAREAPI" {
 			if *enableCFWorkers {
-				metadata = []byte(`{ "manage_redirects": true, "manage_workers": true }`)
+				metadata = []byte(`{ "manage_redirects": true, "manage_workers": true, "manage_rulesets": true }`)
 			} else {
-				metadata = []byte(`{ "manage_redirects": true }`)
+				metadata = []byte(`{ "manage_redirects": true, "manage_rulesets": true }`)
 			}
 		}
 
@@ -92,6 +99,28 @@ func getProvider(t *testing.T) (providers.DNSServiceProvider, string, map[int]bo
 	return nil, "", nil, nil
 }
 
+// mockCreds starts a mock of the API of the provider, with the test
+// domain (example.com unless the domain is set), and returns cfg with
+// the creds that use the mock.
+func mockCreds(t *testing.T, name string, cfg map[string]string) map[string]string {
+	server, err := mockapi.Start(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	creds := map[string]string{"domain": "example.com"}
+	for k, v := range cfg {
+		if v != "" {
+			creds[k] = v
+		}
+	}
+	for k, v := range server.Creds() {
+		creds[k] = v
+	}
+	server.Mock.AddZone(creds["domain"])
+	return creds
+}
+
 func TestDNSProviders(t *testing.T) {
 	provider, domain, fails, cfg := getProvider(t)
 	if provider == nil {
@@ -144,12 +173,20 @@ func testPermitted(t *testing.T, p string, f TestGroup) error {
 	// If there are any required capabilities, make sure they all exist.
 	if len(f.required) != 0 {
 		for _, c := range f.required {
-			if !providers.ProviderHasCapability(*providerToRun, c) {
+			if !providers.ProviderHasCapability(p, c) {
 				return fmt.Errorf("%s not supported", c)
 			}
 		}
 	}
 
+	// The provider must also be able to use every type of record in the
+	// tests, so a group doesn't need requires() for the types it uses.
+	for _, typ := range f.recordTypes() {
+		if caps := normalize.CapabilitiesOfType(typ); caps != nil && providerCapabilities(p, caps) == nil {
+			return fmt.Errorf("%s records not supported", typ)
+		}
+	}
+
 	// If there are any "only" items, you must be one of them.
 	if len(f.only) != 0 {
 		for _, provider := range f.only {
@@ -173,6 +210,67 @@ func testPermitted(t *testing.T, p string, f TestGroup) error {
 	return nil
 }
 
+// providerCapabilities returns the capabilities in caps that provider
+// p has.
+func providerCapabilities(p string, caps []providers.Capability) []providers.Capability {
+	var has []providers.Capability
+	for _, c := range caps {
+		if providers.ProviderHasCapability(p, c) {
+			has = append(has, c)
+		}
+	}
+	return has
+}
+
+// untestedCapabilities are the capabilities that a provider claims but
+// that can't be tested on the domain of the integration tests.
+var untestedCapabilities = map[string][]providers.Capability{
+	// ClouDNS only allows PTR records in a reverse zone.
+	"CLOUDNS": {providers.CanUsePTR},
+}
+
+// TestCapabilitiesAreTested fails if a provider claims a capability for
+// a type of record, but no test that the provider runs uses that type.
+// It checks the -provider, or every provider if there is none.
+func TestCapabilitiesAreTested(t *testing.T) {
+	pTypes := []string{*providerToRun}
+	if *providerToRun == "" {
+		pTypes = nil
+		for pType := range providers.Notes {
+			pTypes = append(pTypes, pType)
+		}
+		sort.Strings(pTypes)
+	}
+	groups := makeTests(t)
+
+	for _, pType := range pTypes {
+		tested := map[providers.Capability]bool{}
+		for _, c := range untestedCapabilities[pType] {
+			tested[c] = true
+		}
+		for _, group := range groups {
+			if testPermitted(t, pType, *group) != nil {
+				continue
+			}
+			for _, c := range group.required {
+				tested[c] = true
+			}
+			for _, typ := range group.recordTypes() {
+				for _, c := range providerCapabilities(pType, normalize.CapabilitiesOfType(typ)) {
+					tested[c] = true
+				}
+			}
+		}
+
+		for c, note := range providers.Notes[pType] {
+			if !note.HasFeature || tested[c] || normalize.TypesOfCapability(c) == nil {
+				continue
+			}
+			t.Errorf("%s claims %s, but no test uses %s records", pType, c, strings.Join(normalize.TypesOfCapability(c), "/"))
+		}
+	}
+}
+
 // makeChanges runs one set of DNS record tests. Returns true on success.
 func makeChanges(t *testing.T, prv providers.DNSServiceProvider, dc *models.DomainConfig, tst *TestCase, desc string, expectChanges bool, origConfig map[string]string) bool {
 	domainName := dc.Name
@@ -222,6 +320,9 @@ func makeChanges(t *testing.T, prv providers.DNSServiceProvider, dc *models.Doma
 			if *verbose {
 				t.Log(c.Msg)
 			}
+			if c.F == nil {
+				continue
+			}
 			err = c.F()
 			if err != nil {
 				t.Fatal(err)
@@ -324,6 +425,9 @@ func TestDualProviders(t *testing.T) {
 		}
 		for i, c := range cs {
 			t.Logf("#%d: %s", i+1, c.Msg)
+			if c.F == nil {
+				continue
+			}
 			if err = c.F(); err != nil {
 				t.Fatal(err)
 			}
@@ -362,6 +466,22 @@ type TestGroup struct {
 	tests     []*TestCase
 }
 
+// recordTypes returns the types of the records in the tests of the
+// group.
+func (g *TestGroup) recordTypes() []string {
+	var types []string
+	seen := map[string]bool{}
+	for _, tst := range g.tests {
+		for _, r := range tst.Records {
+			if !seen[r.Type] {
+				seen[r.Type] = true
+				types = append(types, r.Type)
+			}
+		}
+	}
+	return types
+}
+
 type TestCase struct {
 	Desc           string
 	Records        []*models.RecordConfig
@@ -402,6 +522,10 @@ func azureAlias(name, aliasType, target string) *models.RecordConfig {
 	return r
 }
 
+func akamaiCDN(name, target string) *models.RecordConfig {
+	return makeRec(name, target, "AKAMAICDN")
+}
+
 func cfRedir(pattern, target string) *models.RecordConfig {
 	t := fmt.Sprintf("%s,%s", pattern, target)
 	r := makeRec("@", t, "CF_REDIRECT")
@@ -428,6 +552,10 @@ func cfProxyCNAME(name, target, status string) *models.RecordConfig {
 	return r
 }
 
+func cfRuleset(name, rule string) *models.RecordConfig {
+	return makeRec(name, rule, "CF_RULESET")
+}
+
 func cfWorkerRoute(pattern, target string) *models.RecordConfig {
 	t := fmt.Sprintf("%s,%s", pattern, target)
 	r := makeRec("@", t, "CF_WORKER_ROUTE")
@@ -478,6 +606,18 @@ func sshfp(name string, algorithm uint8, fingerprint uint8, target string) *mode
 	return r
 }
 
+func uri(name string, priority, weight uint16, target string) *models.RecordConfig {
+	r := makeRec(name, target, "URI")
+	r.SetTargetURI(priority, weight, target)
+	return r
+}
+
+func rfc3597(name, rtype, rdata string) *models.RecordConfig {
+	r := makeRec(name, "", rtype)
+	r.SetTargetRFC3597(rtype, rdata)
+	return r
+}
+
 func txt(name, target string) *models.RecordConfig {
 	r := makeRec(name, "", "TXT")
 	r.SetTargetTXT(target)
@@ -496,6 +636,27 @@ func caa(name string, tag string, flag uint8, target string) *models.RecordConfi
 	return r
 }
 
+func https(name string, priority uint16, target string, params string) *models.RecordConfig {
+	r := makeRec(name, target, "HTTPS")
+	r.SvcPriority = priority
+	r.SvcParams = params
+	return r
+}
+
+func loc(name string, d1 uint8, m1 uint8, s1 float32, ns string,
+	d2 uint8, m2 uint8, s2 float32, ew string, alt float32, siz float32, hp float32, vp float32) *models.RecordConfig {
+	r := makeRec(name, "", "LOC")
+	r.SetLOCParams(d1, m1, s1, ns, d2, m2, s2, ew, alt, siz, hp, vp)
+	return r
+}
+
+func svcb(name string, priority uint16, target string, params string) *models.RecordConfig {
+	r := makeRec(name, target, "SVCB")
+	r.SvcPriority = priority
+	r.SvcParams = params
+	return r
+}
+
 func tlsa(name string, usage, selector, matchingtype uint8, target string) *models.RecordConfig {
 	r := makeRec(name, target, "TLSA")
 	r.SetTargetTLSA(usage, selector, matchingtype, target)
@@ -655,8 +816,8 @@ func makeTests(t *testing.T) []*TestGroup {
 
 	// Filters:
 
-	// Only apply to providers that CanUseAlias.
-	//      requires(providers.CanUseAlias),
+	// Only apply to providers that CanUseDS (not only for children).
+	//      requires(providers.CanUseDS),
 	// Only apply to ROUTE53 + GANDI_V5:
 	//      only("ROUTE53", "GANDI_V5")
 	// Only apply to all providers except ROUTE53 + GANDI_V5:
@@ -666,6 +827,11 @@ func makeTests(t *testing.T) []*TestGroup {
 	// NOTE: You can't mix not() and only()
 	//     reset(not("ROUTE53"), only("GCLOUD")),  // ERROR!
 	// NOTE: All requires()/not()/only() must appear before any tc().
+	// NOTE: A group is skipped if the provider can't use one of the types
+	// of records in it (see normalize.CapabilitiesOfType), so requires()
+	// is only needed for capabilities that aren't a type of record.
+	// TestCapabilitiesAreTested fails if a provider claims a type that
+	// no group it runs uses.
 
 	// tc()
 	// Each tc() indicates a set of records.  The testgroup tries to
@@ -923,7 +1089,6 @@ func makeTests(t *testing.T) []*TestGroup {
 		//
 
 		testgroup("CAA",
-			requires(providers.CanUseCAA),
 			tc("CAA record", caa("@", "issue", 0, "letsencrypt.org")),
 			tc("CAA change tag", caa("@", "issuewild", 0, "letsencrypt.org")),
 			tc("CAA change target", caa("@", "issuewild", 0, "example.com")),
@@ -935,7 +1100,6 @@ func makeTests(t *testing.T) []*TestGroup {
 		),
 
 		testgroup("NAPTR",
-			requires(providers.CanUseNAPTR),
 			tc("NAPTR record", naptr("test", 100, 10, "U", "E2U+sip", "!^.*$!sip:customer-service@example.com!", "example.foo.com.")),
 			tc("NAPTR second record", naptr("test", 102, 10, "U", "E2U+email", "!^.*$!mailto:information@example.com!", "example.foo.com.")),
 			tc("NAPTR delete record", naptr("test", 100, 10, "U", "E2U+email", "!^.*$!mailto:information@example.com!", "example.foo.com.")),
@@ -948,13 +1112,13 @@ func makeTests(t *testing.T) []*TestGroup {
 		),
 
 		// ClouDNS provider can work with PTR records, but you need to create special type of zone
-		testgroup("PTR", requires(providers.CanUsePTR), not("CLOUDNS"),
+		testgroup("PTR", not("CLOUDNS"),
 			tc("Create PTR record", ptr("4", "foo.com.")),
 			tc("Modify PTR record", ptr("4", "bar.com.")),
 		),
 
 		// SOA
-		testgroup("SOA", requires(providers.CanUseSOA),
+		testgroup("SOA",
 			clear(), // Extra clear required or only the first run passes.
 			tc("Create SOA record", soa("@", "kim.ns.cloudflare.com.", "dns.cloudflare.com.", 2037190000, 10000, 2400, 604800, 3600)),
 			tc("Modify SOA ns    ", soa("@", "mmm.ns.cloudflare.com.", "dns.cloudflare.com.", 2037190000, 10000, 2400, 604800, 3600)),
@@ -965,7 +1129,7 @@ func makeTests(t *testing.T) []*TestGroup {
 			tc("Modify SOA minttl", soa("@", "mmm.ns.cloudflare.com.", "eee.cloudflare.com.", 2037190000, 10001, 2401, 604801, 3601)),
 		),
 
-		testgroup("SRV", requires(providers.CanUseSRV),
+		testgroup("SRV",
 			tc("SRV record", srv("_sip._tcp", 5, 6, 7, "foo.com.")),
 			tc("Second SRV record, same prio", srv("_sip._tcp", 5, 6, 7, "foo.com."), srv("_sip._tcp", 5, 60, 70, "foo2.com.")),
 			tc("3 SRV", srv("_sip._tcp", 5, 6, 7, "foo.com."), srv("_sip._tcp", 5, 60, 70, "foo2.com."), srv("_sip._tcp", 15, 65, 75, "foo3.com.")),
@@ -979,7 +1143,6 @@ func makeTests(t *testing.T) []*TestGroup {
 		),
 
 		testgroup("SSHFP",
-			requires(providers.CanUseSSHFP),
 			tc("SSHFP record",
 				sshfp("@", 1, 1, "66c7d5540b7d75a1fb4c84febfa178ad99bdd67c")),
 			tc("SSHFP change algorithm",
@@ -989,7 +1152,6 @@ func makeTests(t *testing.T) []*TestGroup {
 		),
 
 		testgroup("TLSA",
-			requires(providers.CanUseTLSA),
 			tc("TLSA record", tlsa("_443._tcp", 3, 1, 1, sha256hash)),
 			tc("TLSA change usage", tlsa("_443._tcp", 2, 1, 1, sha256hash)),
 			tc("TLSA change selector", tlsa("_443._tcp", 2, 0, 1, sha256hash)),
@@ -997,6 +1159,47 @@ func makeTests(t *testing.T) []*TestGroup {
 			tc("TLSA change certificate", tlsa("_443._tcp", 2, 0, 2, reversedSha512)),
 		),
 
+		testgroup("URI",
+			tc("URI record", uri("_http._tcp", 10, 1, "https://www.example.com/")),
+			tc("URI change priority", uri("_http._tcp", 20, 1, "https://www.example.com/")),
+			tc("URI change weight", uri("_http._tcp", 20, 5, "https://www.example.com/")),
+			tc("URI change target", uri("_http._tcp", 20, 5, "https://www.example.com/Other")),
+			tc("URI add another", uri("_http._tcp", 20, 5, "https://www.example.com/Other"), uri("_http._tcp", 30, 1, "ftp://ftp.example.com/")),
+		),
+
+		testgroup("RFC3597",
+			tc("RFC3597 record", rfc3597("exp", "TYPE65534", "0a000001")),
+			tc("RFC3597 change rdata", rfc3597("exp", "TYPE65534", "0a000002")),
+			tc("RFC3597 add another", rfc3597("exp", "TYPE65534", "0a000002"), rfc3597("exp", "TYPE65534", "c0ffee")),
+			tc("RFC3597 another type", rfc3597("exp", "TYPE65534", "0a000002"), rfc3597("exp", "TYPE65280", "")),
+		),
+
+		testgroup("HTTPS",
+			tc("Create a HTTPS record", https("@", 1, "test.com.", "port=80")),
+			tc("Change HTTPS priority", https("@", 2, "test.com.", "port=80")),
+			tc("Change HTTPS target", https("@", 2, ".", "port=80")),
+			tc("Change HTTPS params", https("@", 2, ".", "port=99")),
+			tc("Change HTTPS params-empty", https("@", 2, ".", "")),
+			tc("Change HTTPS multiple params", https("@", 2, ".", "alpn=h2,h3 port=99")),
+		),
+
+		testgroup("SVCB",
+			tc("Create a SVCB record", svcb("@", 1, "test.com.", "port=80")),
+			tc("Change SVCB priority", svcb("@", 2, "test.com.", "port=80")),
+			tc("Change SVCB target", svcb("@", 2, ".", "port=80")),
+			tc("Change SVCB params", svcb("@", 2, ".", "port=99")),
+			tc("Change SVCB params-empty", svcb("@", 2, ".", "")),
+			tc("Change SVCB multiple params", svcb("@", 2, ".", "alpn=h2,h3 port=99")),
+		),
+
+		testgroup("LOC",
+			tc("Create a LOC record", loc("@", 52, 22, 23, "N", 4, 53, 32, "E", -2, 1, 10000, 10)),
+			tc("Change LOC latitude", loc("@", 52, 22, 24, "N", 4, 53, 32, "E", -2, 1, 10000, 10)),
+			tc("Change LOC longitude", loc("@", 52, 22, 24, "N", 4, 53, 32, "W", -2, 1, 10000, 10)),
+			tc("Change LOC altitude", loc("@", 52, 22, 24, "N", 4, 53, 32, "W", 100, 1, 10000, 10)),
+			tc("Change LOC precision", loc("@", 52, 22, 24, "N", 4, 53, 32, "W", 100, 2, 100, 5)),
+		),
+
 		testgroup("DS",
 			requires(providers.CanUseDS),
 			// Use a valid digest value here.  Some providers verify that a valid digest is in use.  See RFC 4034 and
@@ -1092,17 +1295,23 @@ func makeTests(t *testing.T) []*TestGroup {
 		//
 
 		testgroup("ALIAS",
-			requires(providers.CanUseAlias),
 			tc("ALIAS at root", alias("@", "foo.com.")),
 			tc("change it", alias("@", "foo2.com.")),
 			tc("ALIAS at subdomain", alias("test", "foo.com.")),
 			tc("change it", alias("test", "foo2.com.")),
 		),
 
+		// AKAMAIEDGEDNS features
+
+		// The targets must be edge hostnames of the Akamai account.
+		testgroup("AKAMAICDN",
+			tc("Create an AKAMAICDN", akamaiCDN("@", "www.**current-domain-no-trailing**.edgekey.net")),
+			tc("Change it", akamaiCDN("@", "m.**current-domain-no-trailing**.edgekey.net")),
+		),
+
 		// AZURE features
 
 		testgroup("AZURE_ALIAS",
-			requires(providers.CanUseAzureAlias),
 			tc("create dependent A records",
 				a("foo.a", "1.2.3.4"),
 				a("quux.a", "2.3.4.5"),
@@ -1136,7 +1345,6 @@ func makeTests(t *testing.T) []*TestGroup {
 		// ROUTE43 features
 
 		testgroup("R53_ALIAS2",
-			requires(providers.CanUseRoute53Alias),
 			tc("create dependent records",
 				a("kyle", "1.2.3.4"),
 				a("cartman", "2.3.4.5"),
@@ -1154,7 +1362,6 @@ func makeTests(t *testing.T) []*TestGroup {
 		),
 
 		testgroup("R53_ALIAS_ORDER",
-			requires(providers.CanUseRoute53Alias),
 			tc("create target cnames",
 				cname("dev-system18", "ec2-54-91-33-155.compute-1.amazonaws.com."),
 				cname("dev-system19", "ec2-54-91-99-999.compute-1.amazonaws.com."),
@@ -1301,6 +1508,18 @@ func makeTests(t *testing.T) []*TestGroup {
 			),
 		),
 
+		testgroup("CF_RULESET",
+			only("CLOUDFLAREAPI"),
+			tc("custom", cfRuleset("www", `{"action":"block","expression":"http.request.uri.path eq \"/login\""}`)),
+			tc("changeAction", cfRuleset("www", `{"action":"managed_challenge","expression":"http.request.uri.path eq \"/login\""}`)),
+			tc("rateLimit", cfRuleset("www", `{"action":"block","rate_limit":{"requests":100,"period":60,"timeout":60}}`)),
+			tc("addOne",
+				cfRuleset("www", `{"action":"block","rate_limit":{"requests":100,"period":60,"timeout":60}}`),
+				cfRuleset("api", `{"action":"log"}`),
+			),
+			tc("deleteOne", cfRuleset("api", `{"action":"log"}`)),
+		),
+
 		// IGNORE* features
 
 		testgroup("IGNORE_NAME function",
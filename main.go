@@ -7,6 +7,8 @@ import (
 	"runtime/debug"
 
 	"github.com/StackExchange/dnscontrol/v3/commands"
+	_ "github.com/StackExchange/dnscontrol/v3/pkg/credsfile/awssm"
+	_ "github.com/StackExchange/dnscontrol/v3/pkg/credsfile/vault"
 	"github.com/StackExchange/dnscontrol/v3/pkg/version"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/_all"
 )
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CorrectionKind is what a correction does, so that the user can make
+// only some kinds of corrections (see push --only and --skip). The
+// kinds are ordered from the least to the most destructive.
+type CorrectionKind int
+
+const (
+	_                 CorrectionKind = iota // Unset; see Correction.GetKind.
+	CorrectionInfo                          // Only reports something.
+	CorrectionCreate                        // Adds records or settings.
+	CorrectionModify                        // Changes records or settings.
+	CorrectionDestroy                       // Removes records or settings.
+)
+
+var correctionKindNames = []string{"", "info", "create", "modify", "destroy"}
+
+// CorrectionKinds are the names of the kinds of corrections.
+var CorrectionKinds = correctionKindNames[1:]
+
+func (k CorrectionKind) String() string {
+	if k < 0 || int(k) >= len(correctionKindNames) {
+		return fmt.Sprintf("CorrectionKind(%d)", int(k))
+	}
+	return correctionKindNames[k]
+}
+
+// ParseCorrectionKind returns the kind named s (see CorrectionKinds).
+func ParseCorrectionKind(s string) (CorrectionKind, error) {
+	for i, name := range CorrectionKinds {
+		if s == name {
+			return CorrectionKind(i + 1), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown kind of correction %q (kinds: %s)", s, strings.Join(CorrectionKinds, ", "))
+}
+
+// Max returns the more destructive of k and o.
+func (k CorrectionKind) Max(o CorrectionKind) CorrectionKind {
+	if o > k {
+		return o
+	}
+	return k
+}
+
+// GetKind returns the kind of the correction: Kind if it is set,
+// CorrectionInfo if F is nil, and otherwise CorrectionDestroy, since
+// nothing says that the correction is any less destructive.
+func (c *Correction) GetKind() CorrectionKind {
+	if c.Kind != 0 {
+		return c.Kind
+	}
+	if c.F == nil {
+		return CorrectionInfo
+	}
+	return CorrectionDestroy
+}
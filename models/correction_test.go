@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestCorrectionGetKind(t *testing.T) {
+	f := func() error { return nil }
+	for _, tc := range []struct {
+		c    Correction
+		want CorrectionKind
+	}{
+		{Correction{Msg: "CREATE A www.example.com 1.2.3.4", F: f, Kind: CorrectionCreate}, CorrectionCreate},
+		{Correction{Msg: "GENERATE_ZONEFILE: 'example.com'", F: f, Kind: CorrectionModify}, CorrectionModify},
+		{Correction{Msg: "CREATE A www.example.com 1.2.3.4", F: f}, CorrectionDestroy},
+		{Correction{Msg: "Deploy zone example.com", F: f}, CorrectionDestroy},
+		{Correction{Msg: "DELETE A www.example.com 1.2.3.4"}, CorrectionInfo},
+		{Correction{Msg: "DELETE A www.example.com 1.2.3.4", Kind: CorrectionDestroy}, CorrectionDestroy},
+	} {
+		if got := tc.c.GetKind(); got != tc.want {
+			t.Errorf("GetKind(%q) = %v, want %v", tc.c.Msg, got, tc.want)
+		}
+	}
+}
+
+func TestCorrectionKindMax(t *testing.T) {
+	if got := CorrectionCreate.Max(CorrectionDestroy); got != CorrectionDestroy {
+		t.Errorf("create.Max(destroy) = %v", got)
+	}
+	if got := CorrectionModify.Max(0); got != CorrectionModify {
+		t.Errorf("modify.Max(0) = %v", got)
+	}
+}
+
+func TestParseCorrectionKind(t *testing.T) {
+	for _, name := range CorrectionKinds {
+		k, err := ParseCorrectionKind(name)
+		if err != nil || k.String() != name {
+			t.Errorf("ParseCorrectionKind(%q) = %v, %v", name, k, err)
+		}
+	}
+	if _, err := ParseCorrectionKind("delete"); err == nil {
+		t.Error("ParseCorrectionKind(delete) should fail")
+	}
+}
@@ -47,6 +47,11 @@ type DNSProviderConfig struct {
 type Nameserver struct {
 	Name string `json:"name"` // Normalized to a FQDN with NO trailing "."
 	// NB(tlim): DomainConfig.Nameservers are stored WITH a trailing "." (Sorry!)
+
+	// Glue lists the addresses of a nameserver in the domain itself
+	// (ns1.example.com for example.com), which the registrar publishes
+	// in the parent zone. See pkg/glue.
+	Glue []string `json:"glue,omitempty"`
 }
 
 // FIXME(tal): In hindsight the Nameserver struct is overkill. We
@@ -99,9 +104,26 @@ func NameserversToStrings(nss []*Nameserver) (s []string) {
 }
 
 // Correction is anything that can be run. Implementation is up to the specific provider.
+//
+// F may be nil if the correction is informational only; for example,
+// when a provider applies a group of changes in one API call but wants
+// each change reported individually. The informational corrections
+// must then come before the correction that makes the changes.
+//
+// Kind says what the correction does; for a correction that makes
+// several changes, it is the most destructive of them (see
+// CorrectionKind.Max). A correction that leaves it unset counts as
+// CorrectionDestroy (see GetKind).
+//
+// Label is the NameFQDN of the records that the correction changes, if
+// they are all at one label. With push --concurrency, corrections at
+// different labels may run at the same time (see
+// providers.CanConcurrentlyModify).
 type Correction struct {
-	F   func() error `json:"-"`
-	Msg string
+	F     func() error `json:"-"`
+	Msg   string
+	Kind  CorrectionKind `json:",omitempty"`
+	Label string         `json:",omitempty"`
 }
 
 // DomainContainingFQDN finds the best domain from the dns config for the given record fqdn.
@@ -47,6 +47,21 @@ func TestRR(t *testing.T) {
 	if found != expected {
 		t.Errorf("RR expected (%#v) got (%#v)\n", expected, found)
 	}
+
+	experiment = RecordConfig{
+		Type:        "HTTPS",
+		Name:        "@",
+		NameFQDN:    "example.com",
+		target:      ".",
+		TTL:         300,
+		SvcPriority: 1,
+		SvcParams:   "alpn=h3,h2 port=443",
+	}
+	expected = "example.com.\t300\tIN\tHTTPS\t1 . alpn=\"h3,h2\" port=\"443\""
+	found = experiment.ToRR().String()
+	if found != expected {
+		t.Errorf("RR expected (%#v) got (%#v)\n", expected, found)
+	}
 }
 
 func TestDowncase(t *testing.T) {
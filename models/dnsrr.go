@@ -76,6 +76,10 @@ func RRtoRC(rr dns.RR, origin string) (RecordConfig, error) {
 		err = rc.SetTarget(v.Target)
 	case *dns.DS:
 		err = rc.SetTargetDS(v.KeyTag, v.Algorithm, v.DigestType, v.Digest)
+	case *dns.HTTPS:
+		err = rc.SetTargetHTTPS(v.Priority, v.Target, v.Value)
+	case *dns.LOC:
+		err = rc.SetTargetLOC(v.Version, v.Latitude, v.Longitude, v.Altitude, v.Size, v.HorizPre, v.VertPre)
 	case *dns.MX:
 		err = rc.SetTargetMX(v.Preference, v.Mx)
 	case *dns.NS:
@@ -90,10 +94,17 @@ func RRtoRC(rr dns.RR, origin string) (RecordConfig, error) {
 		err = rc.SetTargetSRV(v.Priority, v.Weight, v.Port, v.Target)
 	case *dns.SSHFP:
 		err = rc.SetTargetSSHFP(v.Algorithm, v.Type, v.FingerPrint)
+	case *dns.SVCB:
+		err = rc.SetTargetSVCB(v.Priority, v.Target, v.Value)
 	case *dns.TLSA:
 		err = rc.SetTargetTLSA(v.Usage, v.Selector, v.MatchingType, v.Certificate)
 	case *dns.TXT:
 		err = rc.SetTargetTXTs(v.Txt)
+	case *dns.URI:
+		err = rc.SetTargetURI(v.Priority, v.Weight, v.Target)
+	case *dns.RFC3597:
+		rc.Type = fmt.Sprintf("TYPE%d", v.Hdr.Rrtype)
+		err = rc.SetTargetRFC3597(rc.Type, v.Rdata)
 	default:
 		return *rc, fmt.Errorf("rrToRecord: Unimplemented zone record type=%s (%v)", rc.Type, rr)
 	}
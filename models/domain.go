@@ -2,32 +2,53 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/gobwas/glob"
 	"github.com/qdm12/reprint"
 	"golang.org/x/net/idna"
 )
 
+// CNAMEChains is set by CNAME_CHAINS(). The CNAMEs that lead to
+// another CNAME of the zone, and so on, more than MaxHops times are
+// reported (Action "warn") or changed to point to the end of the chain
+// (Action "flatten"). See pkg/normalize.
+type CNAMEChains struct {
+	MaxHops int    `json:"max_hops"`
+	Action  string `json:"action"`
+}
+
 // DomainConfig describes a DNS domain (tecnically a  DNS zone).
 type DomainConfig struct {
-	Name             string         `json:"name"` // NO trailing "."
-	Tag              string         `json:"-"`    // split horizon tag
-	UniqueName       string         `json:"-"`    // .Name + "!" + .Tag
+	Name             string         `json:"name"`          // NO trailing "."
+	Tag              string         `json:"tag,omitempty"` // split horizon tag
+	UniqueName       string         `json:"-"`             // .Name + "!" + .Tag
 	RegistrarName    string         `json:"registrar"`
 	DNSProviderNames map[string]int `json:"dnsProviders"`
 
 	Metadata    map[string]string `json:"meta,omitempty"`
+	TTLPolicy   map[string]uint32 `json:"ttl_policy,omitempty"`   // TTL_POLICY(): default TTL by type, "*" for the others
+	CNAMEChains *CNAMEChains      `json:"cname_chains,omitempty"` // CNAME_CHAINS(): what to do with the chains of CNAMEs
 	Records     Records           `json:"records"`
 	Nameservers []*Nameserver     `json:"nameservers,omitempty"`
 
 	KeepUnknown     bool               `json:"keepunknown,omitempty"`
+	PurgeOnly       []string           `json:"purge_only,omitempty"` // PURGE_ONLY(): the only types that may be deleted
+	Keep            []string           `json:"keep,omitempty"`       // KEEP(): types that are never deleted
 	IgnoredNames    []*IgnoreName      `json:"ignored_names,omitempty"`
 	IgnoredTargets  []*IgnoreTarget    `json:"ignored_targets,omitempty"`
 	Unmanaged       []*UnmanagedConfig `json:"unmanaged,omitempty"`
 	UnmanagedUnsafe bool               `json:"unmanaged_disable_safety_check,omitempty"`
+	ManagedBy       string             `json:"managed_by,omitempty"` // MANAGED_BY() owner; see pkg/ownership
 
 	AutoDNSSEC string `json:"auto_dnssec,omitempty"` // "", "on", "off"
 	//DNSSEC        bool              `json:"dnssec,omitempty"`
 
+	// DSRecords are the DS records that the registrar should publish,
+	// as reported by the DNS providers (see pkg/dnssec). nil means that
+	// the DS records at the registrar are left alone.
+	DSRecords Records `json:"-"`
+
 	// These fields contain instantiated provider instances once everything is linked up.
 	// This linking is in two phases:
 	// 1. Metadata (name/type) is available just from the dnsconfig. Validation can use that.
@@ -36,12 +57,118 @@ type DomainConfig struct {
 	DNSProviderInstances []*DNSProviderInstance `json:"-"`
 }
 
-// UnmanagedConfig describes an UNMANAGED() rule.
+// SplitDomainName splits the name given to D(), "domain.tld" or
+// "domain.tld!tag", into the name of the zone and the split horizon
+// tag.
+func SplitDomainName(s string) (name, tag string) {
+	name, tag, _ = strings.Cut(s, "!")
+	return name, tag
+}
+
+// UniqueDomainName is the name of the domain name with the split
+// horizon tag, as given to D().
+func UniqueDomainName(name, tag string) string {
+	if tag == "" {
+		return name
+	}
+	return name + "!" + tag
+}
+
+// UnmanagedConfig describes an IGNORE() or UNMANAGED() rule.
 type UnmanagedConfig struct {
 	Label   string          `json:"label_pattern"` // Glob pattern for matching labels.
 	RType   string          `json:"rType_pattern"` // Comma-separated list of DNS Resource Types.
 	typeMap map[string]bool // map of RTypes or len()=0 for all
 	Target  string          `json:"target_pattern"` // Glob pattern for matching targets.
+
+	labelGlob  glob.Glob
+	targetGlob glob.Glob
+}
+
+// Compile compiles the patterns of the rule. It is called
+// automatically by Match(), but calling it earlier permits errors to
+// be reported to the user.
+//
+// The label pattern is matched against the short name of the record
+// (i.e. "foo" not "foo.example.com"), using "." as a separator, the
+// same as IGNORE_NAME(). A pattern of "" or "*" for the label, rtype or
+// target matches everything.
+func (uc *UnmanagedConfig) Compile() error {
+	if uc.labelGlob != nil {
+		return nil
+	}
+
+	lab := uc.Label
+	if lab == "" || lab == "*" {
+		lab = "**"
+	}
+	labelGlob, err := glob.Compile(lab, '.')
+	if err != nil {
+		return fmt.Errorf("invalid label pattern %q: %w", uc.Label, err)
+	}
+
+	targ := uc.Target
+	if targ == "" {
+		targ = "*"
+	}
+	targetGlob, err := glob.Compile(targ)
+	if err != nil {
+		return fmt.Errorf("invalid target pattern %q: %w", uc.Target, err)
+	}
+
+	uc.typeMap = map[string]bool{}
+	for _, t := range strings.Split(uc.RType, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" {
+			// Match all types.
+			uc.typeMap = map[string]bool{}
+			break
+		}
+		if t != "" {
+			uc.typeMap[t] = true
+		}
+	}
+
+	uc.labelGlob, uc.targetGlob = labelGlob, targetGlob
+	return nil
+}
+
+// Match returns true if rc is matched by the rule. Rules with invalid
+// patterns match nothing.
+func (uc *UnmanagedConfig) Match(rc *RecordConfig) bool {
+	if err := uc.Compile(); err != nil {
+		return false
+	}
+	if !uc.labelGlob.Match(rc.GetLabel()) {
+		return false
+	}
+	if len(uc.typeMap) != 0 && !uc.typeMap[rc.Type] {
+		return false
+	}
+	return uc.targetGlob.Match(rc.GetTargetField())
+}
+
+// Purgeable reports whether existing records of type rtype that aren't
+// in the configuration may be deleted, according to NO_PURGE,
+// PURGE_ONLY() and KEEP().
+func (dc *DomainConfig) Purgeable(rtype string) bool {
+	if dc.KeepUnknown {
+		return false
+	}
+	for _, t := range dc.Keep {
+		if strings.EqualFold(t, rtype) {
+			return false
+		}
+	}
+	if len(dc.PurgeOnly) == 0 {
+		return true
+	}
+	for _, t := range dc.PurgeOnly {
+		if strings.EqualFold(t, rtype) {
+			return true
+		}
+	}
+	return false
 }
 
 // Copy returns a deep copy of the DomainConfig.
@@ -89,18 +216,21 @@ func (dc *DomainConfig) Punycode() error {
 
 		// Set the target:
 		switch rec.Type { // #rtype_variations
-		case "ALIAS", "MX", "NS", "CNAME", "PTR", "SRV", "URL", "URL301", "FRAME", "R53_ALIAS", "NS1_URLFWD", "AKAMAICDN", "CLOUDNS_WR":
+		case "ALIAS", "MX", "NS", "CNAME", "PTR", "SRV", "URL", "URL301", "FRAME", "R53_ALIAS", "NS1_URLFWD", "AKAMAICDN", "CLOUDNS_WR", "NETLIFY", "NETLIFYv6", "HTTPS", "SVCB":
 			// These rtypes are hostnames, therefore need to be converted (unlike, for example, an AAAA record)
 			t, err := idna.ToASCII(rec.GetTargetField())
 			if err != nil {
 				return err
 			}
 			rec.SetTarget(t)
-		case "CF_REDIRECT", "CF_TEMP_REDIRECT", "CF_WORKER_ROUTE":
+		case "CF_REDIRECT", "CF_TEMP_REDIRECT", "CF_WORKER_ROUTE", "CF_RULESET", "CF_SPECTRUM_APP":
 			rec.SetTarget(rec.GetTargetField())
-		case "A", "AAAA", "CAA", "DS", "NAPTR", "SOA", "SSHFP", "TXT", "TLSA", "AZURE_ALIAS":
+		case "A", "AAAA", "CAA", "DS", "LOC", "LUA", "NAPTR", "SOA", "SSHFP", "TXT", "TLSA", "URI", "AZURE_ALIAS":
 			// Nothing to do.
 		default:
+			if IsRFC3597Type(rec.Type) {
+				break // Opaque rdata.
+			}
 			return fmt.Errorf("Punycode rtype %v unimplemented", rec.Type)
 		}
 	}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Providers often store attributes of a record that DNSControl doesn't
+// model: Cloudflare's comments and tags, for example. Updating such a
+// record with only the fields that DNSControl knows about would silently
+// wipe them. To prevent that, a provider saves them in
+// RecordConfig.ProviderFields when it reads the zone (nativeToRecord)
+// and re-applies them when it updates the record:
+//
+//	// nativeToRecord():
+//	rc.ProviderFields, err = models.ProviderFieldsFromNative(native, "id", "type", "name", "content", "ttl")
+//
+//	// When updating the record:
+//	err := models.ApplyProviderFields(&native, existing.ProviderFields)
+//
+// ProviderFields are not compared by the differ and are not part of the
+// configuration, thus they never cause a correction by themselves.
+
+// ProviderFieldsFromNative returns the fields of native, a provider's
+// record struct, as they are encoded in JSON, except for the fields
+// listed in modeled (the JSON names of the fields that DNSControl
+// manages). Empty fields are omitted. It returns nil if no fields are
+// left.
+func ProviderFieldsFromNative(native interface{}, modeled ...string) (map[string]interface{}, error) {
+	j, err := json.Marshal(native)
+	if err != nil {
+		return nil, fmt.Errorf("ProviderFieldsFromNative: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(j, &fields); err != nil {
+		return nil, fmt.Errorf("ProviderFieldsFromNative: %w", err)
+	}
+	for _, k := range modeled {
+		delete(fields, k)
+	}
+	for k, v := range fields {
+		if v == nil {
+			delete(fields, k)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// ApplyProviderFields sets the fields of native, a pointer to a
+// provider's record struct, from fields (as returned by
+// ProviderFieldsFromNative). Fields that are already set in native are
+// left alone, thus the values from dnsconfig.js win.
+func ApplyProviderFields(native interface{}, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	j, err := json.Marshal(native)
+	if err != nil {
+		return fmt.Errorf("ApplyProviderFields: %w", err)
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal(j, &merged); err != nil {
+		return fmt.Errorf("ApplyProviderFields: %w", err)
+	}
+	for k, v := range fields {
+		if cur, ok := merged[k]; !ok || cur == nil {
+			merged[k] = v
+		}
+	}
+	if j, err = json.Marshal(merged); err != nil {
+		return fmt.Errorf("ApplyProviderFields: %w", err)
+	}
+	if err := json.Unmarshal(j, native); err != nil {
+		return fmt.Errorf("ApplyProviderFields: %w", err)
+	}
+	return nil
+}
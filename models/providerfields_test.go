@@ -0,0 +1,41 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+type nativeRecord struct {
+	ID      string   `json:"id,omitempty"`
+	Content string   `json:"content,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Data    *struct {
+		Weight int `json:"weight"`
+	} `json:"data,omitempty"`
+}
+
+func TestProviderFields(t *testing.T) {
+	existing := nativeRecord{ID: "1", Content: "1.2.3.4", Comment: "do not touch", Tags: []string{"a"}}
+	fields, err := ProviderFieldsFromNative(existing, "id", "content", "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"comment": "do not touch", "tags": []interface{}{"a"}}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ProviderFieldsFromNative() = %v, want %v", fields, want)
+	}
+
+	update := nativeRecord{ID: "1", Content: "5.6.7.8", Tags: []string{"b"}}
+	if err := ApplyProviderFields(&update, fields); err != nil {
+		t.Fatal(err)
+	}
+	wantUpdate := nativeRecord{ID: "1", Content: "5.6.7.8", Comment: "do not touch", Tags: []string{"b"}}
+	if !reflect.DeepEqual(update, wantUpdate) {
+		t.Errorf("ApplyProviderFields() = %+v, want %+v", update, wantUpdate)
+	}
+
+	if fields, err := ProviderFieldsFromNative(nativeRecord{ID: "2"}, "id"); err != nil || fields != nil {
+		t.Errorf("ProviderFieldsFromNative() = %v, %v; want nil, nil", fields, err)
+	}
+}
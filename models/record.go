@@ -22,6 +22,8 @@ import (
 //	  ANAME  // Technically not an official rtype yet.
 //	  CAA
 //	  CNAME
+//	  HTTPS
+//	  LOC
 //	  MX
 //	  NAPTR
 //	  NS
@@ -29,21 +31,31 @@ import (
 //	  SOA
 //	  SRV
 //	  SSHFP
+//	  SVCB
 //	  TLSA
 //	  TXT
+//	  TYPEnnn  // Any other type, in the generic format of RFC 3597.
+//	  URI
 //	Pseudo-Types: (alphabetical)
 //	  ALIAS
 //	  CF_REDIRECT
+//	  CF_RULESET
+//	  CF_SPECTRUM_APP
 //	  CF_TEMP_REDIRECT
 //	  CF_WORKER_ROUTE
 //	  CLOUDNS_WR
 //	  FRAME
 //	  IMPORT_TRANSFORM
+//	  LUA
 //	  NAMESERVER
+//	  NETLIFY
+//	  NETLIFYv6
 //	  NO_PURGE
 //	  NS1_URLFWD
 //	  PAGE_RULE
 //	  PURGE
+//	  RULESET
+//	  SPECTRUM_APP
 //	  URL
 //	  URL301
 //	  WORKER_ROUTE
@@ -93,6 +105,11 @@ type RecordConfig struct {
 	Metadata  map[string]string `json:"meta,omitempty"`
 	Original  interface{}       `json:"-"` // Store pointer to provider-specific record object. Used in diffing.
 
+	// ProviderFields stores the attributes of an existing record that
+	// DNSControl doesn't model, so that the provider can re-apply them
+	// when it updates the record. See providerfields.go.
+	ProviderFields map[string]interface{} `json:"-"`
+
 	// If you add a field to this struct, also add it to the list on MarshalJSON.
 	MxPreference     uint16            `json:"mxpreference,omitempty"`
 	SrvPriority      uint16            `json:"srvpriority,omitempty"`
@@ -104,6 +121,13 @@ type RecordConfig struct {
 	DsAlgorithm      uint8             `json:"dsalgorithm,omitempty"`
 	DsDigestType     uint8             `json:"dsdigesttype,omitempty"`
 	DsDigest         string            `json:"dsdigest,omitempty"`
+	LocVersion       uint8             `json:"locversion,omitempty"`
+	LocSize          uint8             `json:"locsize,omitempty"`
+	LocHorizPre      uint8             `json:"lochorizpre,omitempty"`
+	LocVertPre       uint8             `json:"locvertpre,omitempty"`
+	LocLatitude      uint32            `json:"loclatitude,omitempty"`
+	LocLongitude     uint32            `json:"loclongitude,omitempty"`
+	LocAltitude      uint32            `json:"localtitude,omitempty"`
 	NaptrOrder       uint16            `json:"naptrorder,omitempty"`
 	NaptrPreference  uint16            `json:"naptrpreference,omitempty"`
 	NaptrFlags       string            `json:"naptrflags,omitempty"`
@@ -117,10 +141,15 @@ type RecordConfig struct {
 	SoaRetry         uint32            `json:"soaretry,omitempty"`
 	SoaExpire        uint32            `json:"soaexpire,omitempty"`
 	SoaMinttl        uint32            `json:"soaminttl,omitempty"`
+	SvcPriority      uint16            `json:"svcpriority,omitempty"`
+	SvcParams        string            `json:"svcparams,omitempty"`
 	TlsaUsage        uint8             `json:"tlsausage,omitempty"`
 	TlsaSelector     uint8             `json:"tlsaselector,omitempty"`
 	TlsaMatchingType uint8             `json:"tlsamatchingtype,omitempty"`
 	TxtStrings       []string          `json:"txtstrings,omitempty"` // TxtStrings stores all strings (including the first). Target stores all the strings joined.
+	UriPriority      uint16            `json:"uripriority,omitempty"`
+	UriWeight        uint16            `json:"uriweight,omitempty"`
+	LuaRType         string            `json:"luartype,omitempty"`
 	R53Alias         map[string]string `json:"r53_alias,omitempty"`
 	AzureAlias       map[string]string `json:"azure_alias,omitempty"`
 }
@@ -165,6 +194,13 @@ func (rc *RecordConfig) UnmarshalJSON(b []byte) error {
 		DsAlgorithm      uint8             `json:"dsalgorithm,omitempty"`
 		DsDigestType     uint8             `json:"dsdigesttype,omitempty"`
 		DsDigest         string            `json:"dsdigest,omitempty"`
+		LocVersion       uint8             `json:"locversion,omitempty"`
+		LocSize          uint8             `json:"locsize,omitempty"`
+		LocHorizPre      uint8             `json:"lochorizpre,omitempty"`
+		LocVertPre       uint8             `json:"locvertpre,omitempty"`
+		LocLatitude      uint32            `json:"loclatitude,omitempty"`
+		LocLongitude     uint32            `json:"loclongitude,omitempty"`
+		LocAltitude      uint32            `json:"localtitude,omitempty"`
 		NaptrOrder       uint16            `json:"naptrorder,omitempty"`
 		NaptrPreference  uint16            `json:"naptrpreference,omitempty"`
 		NaptrFlags       string            `json:"naptrflags,omitempty"`
@@ -178,10 +214,15 @@ func (rc *RecordConfig) UnmarshalJSON(b []byte) error {
 		SoaRetry         uint32            `json:"soaretry,omitempty"`
 		SoaExpire        uint32            `json:"soaexpire,omitempty"`
 		SoaMinttl        uint32            `json:"soaminttl,omitempty"`
+		SvcPriority      uint16            `json:"svcpriority,omitempty"`
+		SvcParams        string            `json:"svcparams,omitempty"`
 		TlsaUsage        uint8             `json:"tlsausage,omitempty"`
 		TlsaSelector     uint8             `json:"tlsaselector,omitempty"`
 		TlsaMatchingType uint8             `json:"tlsamatchingtype,omitempty"`
 		TxtStrings       []string          `json:"txtstrings,omitempty"` // TxtStrings stores all strings (including the first). Target stores only the first one.
+		UriPriority      uint16            `json:"uripriority,omitempty"`
+		UriWeight        uint16            `json:"uriweight,omitempty"`
+		LuaRType         string            `json:"luartype,omitempty"`
 		R53Alias         map[string]string `json:"r53_alias,omitempty"`
 		AzureAlias       map[string]string `json:"azure_alias,omitempty"`
 		// NB(tlim): If anyone can figure out how to do this without listing all
@@ -337,6 +378,11 @@ func (rc *RecordConfig) ToDiffable(extraMaps ...map[string]string) string {
 // ToRR converts a RecordConfig to a dns.RR.
 func (rc *RecordConfig) ToRR() dns.RR {
 
+	// Types in the generic format of RFC 3597 have no type of their own.
+	if n, err := rfc3597TypeNumber(rc.Type); err == nil {
+		return rc.toRFC3597(n)
+	}
+
 	// Don't call this on fake types.
 	rdtype, ok := dns.StringToType[rc.Type]
 	if !ok {
@@ -377,6 +423,23 @@ func (rc *RecordConfig) ToRR() dns.RR {
 		rr.(*dns.NAPTR).Service = rc.NaptrService
 		rr.(*dns.NAPTR).Regexp = rc.NaptrRegexp
 		rr.(*dns.NAPTR).Replacement = rc.GetTargetField()
+	case dns.TypeHTTPS:
+		rr.(*dns.HTTPS).Priority = rc.SvcPriority
+		rr.(*dns.HTTPS).Target = rc.GetTargetField()
+		value, err := rc.GetSVCBValue()
+		if err != nil {
+			// pkg/normalize checks the params beforehand.
+			log.Fatalf("%s record %s: %s", rc.Type, rc.NameFQDN, err)
+		}
+		rr.(*dns.HTTPS).Value = value
+	case dns.TypeLOC:
+		rr.(*dns.LOC).Version = rc.LocVersion
+		rr.(*dns.LOC).Size = rc.LocSize
+		rr.(*dns.LOC).HorizPre = rc.LocHorizPre
+		rr.(*dns.LOC).VertPre = rc.LocVertPre
+		rr.(*dns.LOC).Latitude = rc.LocLatitude
+		rr.(*dns.LOC).Longitude = rc.LocLongitude
+		rr.(*dns.LOC).Altitude = rc.LocAltitude
 	case dns.TypeMX:
 		rr.(*dns.MX).Preference = rc.MxPreference
 		rr.(*dns.MX).Mx = rc.GetTargetField()
@@ -399,6 +462,15 @@ func (rc *RecordConfig) ToRR() dns.RR {
 		rr.(*dns.SSHFP).Algorithm = rc.SshfpAlgorithm
 		rr.(*dns.SSHFP).Type = rc.SshfpFingerprint
 		rr.(*dns.SSHFP).FingerPrint = rc.GetTargetField()
+	case dns.TypeSVCB:
+		rr.(*dns.SVCB).Priority = rc.SvcPriority
+		rr.(*dns.SVCB).Target = rc.GetTargetField()
+		value, err := rc.GetSVCBValue()
+		if err != nil {
+			// pkg/normalize checks the params beforehand.
+			log.Fatalf("%s record %s: %s", rc.Type, rc.NameFQDN, err)
+		}
+		rr.(*dns.SVCB).Value = value
 	case dns.TypeCAA:
 		rr.(*dns.CAA).Flag = rc.CaaFlag
 		rr.(*dns.CAA).Tag = rc.CaaTag
@@ -412,6 +484,10 @@ func (rc *RecordConfig) ToRR() dns.RR {
 		rr.(*dns.SPF).Txt = rc.TxtStrings
 	case dns.TypeTXT:
 		rr.(*dns.TXT).Txt = rc.TxtStrings
+	case dns.TypeURI:
+		rr.(*dns.URI).Priority = rc.UriPriority
+		rr.(*dns.URI).Weight = rc.UriWeight
+		rr.(*dns.URI).Target = rc.GetTargetField()
 	default:
 		panic(fmt.Sprintf("ToRR: Unimplemented rtype %v", rc.Type))
 		// We panic so that we quickly find any switch statements
@@ -531,10 +607,10 @@ func downcase(recs []*RecordConfig) {
 		r.Name = strings.ToLower(r.Name)
 		r.NameFQDN = strings.ToLower(r.NameFQDN)
 		switch r.Type { // #rtype_variations
-		case "ANAME", "CNAME", "DS", "MX", "NS", "PTR", "NAPTR", "SRV", "TLSA", "AKAMAICDN":
+		case "ANAME", "CNAME", "DS", "HTTPS", "MX", "NS", "PTR", "NAPTR", "SRV", "SVCB", "TLSA", "AKAMAICDN":
 			// These record types have a target that is case insensitive, so we downcase it.
 			r.target = strings.ToLower(r.target)
-		case "A", "AAAA", "ALIAS", "CAA", "IMPORT_TRANSFORM", "TXT", "SSHFP", "CF_REDIRECT", "CF_TEMP_REDIRECT", "CF_WORKER_ROUTE":
+		case "A", "AAAA", "ALIAS", "CAA", "IMPORT_TRANSFORM", "LOC", "LUA", "TXT", "SSHFP", "URI", "CF_REDIRECT", "CF_TEMP_REDIRECT", "CF_WORKER_ROUTE", "CF_RULESET", "CF_SPECTRUM_APP":
 			// These record types have a target that is case sensitive, or is an IP address. We leave them alone.
 			// Do nothing.
 		case "SOA":
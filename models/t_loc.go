@@ -0,0 +1,127 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Constants used when encoding LOC records (RFC 1876).
+const (
+	locEquator  = 1 << 31      // Latitude and longitude are offset from this value.
+	locAltBase  = 100000 * 100 // Altitude is stored in cm above -100000m.
+	locMaxDeg   = 180
+	locMaxLat   = 90
+	locVersion0 = 0
+)
+
+// SetTargetLOC sets the LOC fields from the raw (RFC 1876 encoded) values.
+func (rc *RecordConfig) SetTargetLOC(ver uint8, lat uint32, lon uint32, alt uint32, siz uint8, hzp uint8, vtp uint8) error {
+	rc.LocVersion = ver
+	rc.LocLatitude = lat
+	rc.LocLongitude = lon
+	rc.LocAltitude = alt
+	rc.LocSize = siz
+	rc.LocHorizPre = hzp
+	rc.LocVertPre = vtp
+	if rc.Type == "" {
+		rc.Type = "LOC"
+	}
+	if rc.Type != "LOC" {
+		panic("assertion failed: SetTargetLOC called when .Type is not LOC")
+	}
+	return nil
+}
+
+// SetTargetLOCString is like SetTargetLOC but accepts one big string
+// in zonefile format, such as "52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m".
+func (rc *RecordConfig) SetTargetLOCString(origin string, contents string) error {
+	if rc.Type == "" {
+		rc.Type = "LOC"
+	}
+	if rc.Type != "LOC" {
+		panic("assertion failed: SetTargetLOCString called when .Type is not LOC")
+	}
+	record, err := dns.NewRR(fmt.Sprintf("%s. LOC %s", origin, contents))
+	if err != nil {
+		return fmt.Errorf("could not parse LOC record: %w", err)
+	}
+	r, ok := record.(*dns.LOC)
+	if !ok {
+		return fmt.Errorf("LOC value could not be parsed: (%#v)", contents)
+	}
+	return rc.SetTargetLOC(r.Version, r.Latitude, r.Longitude, r.Altitude, r.Size, r.HorizPre, r.VertPre)
+}
+
+// SetLOCParams is like SetTargetLOC but accepts human-friendly values:
+// degrees, minutes and seconds for latitude and longitude, "N" or "S"
+// and "E" or "W" for the direction, and altitude, size, horizontal
+// and vertical precision in meters.
+func (rc *RecordConfig) SetLOCParams(d1 uint8, m1 uint8, s1 float32, ns string,
+	d2 uint8, m2 uint8, s2 float32, ew string, alt float32, siz float32, hp float32, vp float32) error {
+
+	ns = strings.ToUpper(ns)
+	ew = strings.ToUpper(ew)
+	if ns != "N" && ns != "S" {
+		return fmt.Errorf("LOC latitude direction must be N or S, not %q", ns)
+	}
+	if ew != "E" && ew != "W" {
+		return fmt.Errorf("LOC longitude direction must be E or W, not %q", ew)
+	}
+	if d1 > locMaxLat || m1 > 59 || s1 < 0 || s1 >= 60 {
+		return fmt.Errorf("LOC latitude %d %d %f is out of range", d1, m1, s1)
+	}
+	if d2 > locMaxDeg || m2 > 59 || s2 < 0 || s2 >= 60 {
+		return fmt.Errorf("LOC longitude %d %d %f is out of range", d2, m2, s2)
+	}
+
+	lat := locEncodeDMS(d1, m1, s1, ns == "N")
+	lon := locEncodeDMS(d2, m2, s2, ew == "E")
+
+	if alt < -100000 || alt > 42849672.95 {
+		return fmt.Errorf("LOC altitude %fm is out of range", alt)
+	}
+	altitude := uint32(math.Round(float64(alt)*100)) + locAltBase
+
+	size, err := locEncodeMeters(siz)
+	if err != nil {
+		return fmt.Errorf("LOC size: %w", err)
+	}
+	hzp, err := locEncodeMeters(hp)
+	if err != nil {
+		return fmt.Errorf("LOC horizontal precision: %w", err)
+	}
+	vtp, err := locEncodeMeters(vp)
+	if err != nil {
+		return fmt.Errorf("LOC vertical precision: %w", err)
+	}
+
+	return rc.SetTargetLOC(locVersion0, lat, lon, altitude, size, hzp, vtp)
+}
+
+// locEncodeDMS encodes degrees, minutes and seconds as thousandths of
+// an arcsecond offset from the equator (or prime meridian).
+func locEncodeDMS(d uint8, m uint8, s float32, positive bool) uint32 {
+	v := uint32(d)*3600000 + uint32(m)*60000 + uint32(math.Round(float64(s)*1000))
+	if positive {
+		return locEquator + v
+	}
+	return locEquator - v
+}
+
+// locEncodeMeters encodes a value in meters as the 4-bit mantissa and
+// 4-bit exponent (in centimeters) used by the size and precision fields.
+func locEncodeMeters(meters float32) (uint8, error) {
+	if meters < 0 || meters > 90000000 {
+		return 0, fmt.Errorf("%fm is out of range", meters)
+	}
+	cm := uint64(math.Round(float64(meters) * 100))
+	var exp uint8
+	for cm >= 10 && exp < 9 {
+		cm /= 10
+		exp++
+	}
+	return uint8(cm)<<4 | exp, nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import "testing"
+
+func TestSetLOCParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		d1, m1 uint8
+		s1     float32
+		ns     string
+		d2, m2 uint8
+		s2     float32
+		ew     string
+		alt    float32
+		siz    float32
+		hp     float32
+		vp     float32
+		want   string
+	}{
+		{"amsterdam", 52, 22, 23, "N", 4, 53, 32, "E", -2, 1, 10000, 10, "52 22 23.000 N 4 53 32.000 E -2m 1m 10000m 10m"},
+		{"sydney", 33, 51, 31, "S", 151, 12, 51, "E", 0, 1, 10000, 10, "33 51 31.000 S 151 12 51.000 E 0m 1m 10000m 10m"},
+		{"london", 51, 30, 2.52, "N", 0, 7, 28.56, "W", 10, 2, 100, 5, "51 30 2.520 N 0 7 28.560 W 10m 2m 100m 5m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &RecordConfig{Type: "LOC"}
+			if err := got.SetLOCParams(tt.d1, tt.m1, tt.s1, tt.ns, tt.d2, tt.m2, tt.s2, tt.ew, tt.alt, tt.siz, tt.hp, tt.vp); err != nil {
+				t.Fatal(err)
+			}
+			want := &RecordConfig{Type: "LOC"}
+			if err := want.SetTargetLOCString("example.com", tt.want); err != nil {
+				t.Fatal(err)
+			}
+			if got.LocLatitude != want.LocLatitude || got.LocLongitude != want.LocLongitude ||
+				got.LocAltitude != want.LocAltitude || got.LocSize != want.LocSize ||
+				got.LocHorizPre != want.LocHorizPre || got.LocVertPre != want.LocVertPre {
+				t.Errorf("SetLOCParams() = %s, want %s", got.GetTargetDebug(), want.GetTargetDebug())
+			}
+		})
+	}
+}
+
+func TestSetLOCParamsInvalid(t *testing.T) {
+	rc := &RecordConfig{Type: "LOC"}
+	if err := rc.SetLOCParams(91, 0, 0, "N", 0, 0, 0, "E", 0, 1, 1, 1); err == nil {
+		t.Error("expected error for latitude > 90")
+	}
+	if err := rc.SetLOCParams(0, 0, 0, "X", 0, 0, 0, "E", 0, 1, 1, 1); err == nil {
+		t.Error("expected error for bad direction")
+	}
+}
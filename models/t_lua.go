@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetTargetLUA sets the LUA fields. LUA records are a PowerDNS
+// extension: the script computes records of type rtype when queried.
+func (rc *RecordConfig) SetTargetLUA(rtype, script string) error {
+	rc.LuaRType = strings.ToUpper(rtype)
+	rc.SetTarget(script)
+	if rc.Type == "" {
+		rc.Type = "LUA"
+	}
+	if rc.Type != "LUA" {
+		panic("assertion failed: SetTargetLUA called when .Type is not LUA")
+	}
+	if rc.LuaRType == "" {
+		return fmt.Errorf("LUA record has no type")
+	}
+	return nil
+}
+
+// SetTargetLUAString is like SetTargetLUA but accepts the record
+// content as PowerDNS writes it (`A "ifportup(443, {'192.0.2.1'})"`).
+func (rc *RecordConfig) SetTargetLUAString(s string) error {
+	rtype, script, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return fmt.Errorf("LUA value does not contain a type and a script: (%#v)", s)
+	}
+	script = strings.TrimSpace(script)
+	if !IsQuoted(script) {
+		return rc.SetTargetLUA(rtype, script)
+	}
+	return rc.SetTargetLUA(rtype, unescapeLUA(script[1:len(script)-1]))
+}
+
+// unescapeLUA decodes the \X and \DDD escapes of a quoted string.
+func unescapeLUA(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i+2 < len(s) && isDigit(s[i]) && isDigit(s[i+1]) && isDigit(s[i+2]) {
+			n := int(s[i]-'0')*100 + int(s[i+1]-'0')*10 + int(s[i+2]-'0')
+			if n < 256 {
+				b.WriteByte(byte(n))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// luaContent returns the LUA record content: the type and the quoted
+// script.
+func (rc *RecordConfig) luaContent() string {
+	var b strings.Builder
+	b.WriteString(rc.LuaRType)
+	b.WriteString(` "`)
+	for _, c := range []byte(rc.target) {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < ' ' || c > '~':
+			fmt.Fprintf(&b, "\\%03d", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
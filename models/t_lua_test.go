@@ -0,0 +1,41 @@
+package models
+
+import "testing"
+
+func TestSetTargetLUAString(t *testing.T) {
+	tests := []struct {
+		s          string
+		wantType   string
+		wantScript string
+		wantErr    bool
+	}{
+		{`A "ifportup(443, {'192.0.2.1', '192.0.2.2'})"`, "A", `ifportup(443, {'192.0.2.1', '192.0.2.2'})`, false},
+		{`txt "os.date()"`, "TXT", `os.date()`, false},
+		{`CNAME ";return \"www.\" .. country() .. \".example.com.\""`, "CNAME", `;return "www." .. country() .. ".example.com."`, false},
+		{`A "a\010b\\c"`, "A", "a\nb\\c", false},
+		{`A`, "", "", true},
+	}
+	for _, tt := range tests {
+		rc := &RecordConfig{Type: "LUA"}
+		err := rc.SetTargetLUAString(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SetTargetLUAString(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if rc.LuaRType != tt.wantType || rc.GetTargetField() != tt.wantScript {
+			t.Errorf("SetTargetLUAString(%q) = %q %q, want %q %q", tt.s, rc.LuaRType, rc.GetTargetField(), tt.wantType, tt.wantScript)
+		}
+
+		// The content survives a round trip.
+		back := &RecordConfig{Type: "LUA"}
+		if err := back.SetTargetLUAString(rc.GetTargetCombined()); err != nil {
+			t.Fatal(err)
+		}
+		if back.GetTargetDebug() != rc.GetTargetDebug() {
+			t.Errorf("round trip of %q = %q, want %q", rc.GetTargetCombined(), back.GetTargetDebug(), rc.GetTargetDebug())
+		}
+	}
+}
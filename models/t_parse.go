@@ -55,6 +55,10 @@ func (rc *RecordConfig) PopulateFromString(rtype, contents, origin string) error
 		return rc.SetTargetCAAString(contents)
 	case "DS":
 		return rc.SetTargetDSString(contents)
+	case "HTTPS", "SVCB":
+		return rc.SetTargetSVCBString(origin, contents)
+	case "LOC":
+		return rc.SetTargetLOCString(origin, contents)
 	case "MX":
 		return rc.SetTargetMXString(contents)
 	case "NAPTR":
@@ -69,7 +73,12 @@ func (rc *RecordConfig) PopulateFromString(rtype, contents, origin string) error
 		return rc.SetTargetSSHFPString(contents)
 	case "TLSA":
 		return rc.SetTargetTLSAString(contents)
+	case "URI":
+		return rc.SetTargetURIString(contents)
 	default:
+		if IsRFC3597Type(rtype) {
+			return rc.SetTargetRFC3597String(rtype, contents)
+		}
 		return fmt.Errorf("unknown rtype (%s) when parsing (%s) domain=(%s)",
 			rtype, contents, origin)
 	}
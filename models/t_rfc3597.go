@@ -0,0 +1,99 @@
+package models
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Records of types that DNSControl doesn't know can be given in the
+// generic format of RFC 3597: the type is "TYPE" followed by its number
+// (TYPE65534) and the target is `\# length hexdata` (`\# 4 0a000001`).
+// The target is stored in that format, with the hex data in lowercase
+// and in one piece.
+
+// IsRFC3597Type returns true if t is a type in the generic format of
+// RFC 3597 whose number isn't of a type that DNSControl or the dns
+// package know.
+func IsRFC3597Type(t string) bool {
+	_, err := rfc3597TypeNumber(t)
+	return err == nil
+}
+
+func rfc3597TypeNumber(t string) (uint16, error) {
+	if !strings.HasPrefix(t, "TYPE") {
+		return 0, fmt.Errorf("type %q is not of the form TYPEnnn", t)
+	}
+	n, err := strconv.ParseUint(t[len("TYPE"):], 10, 16)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("type %q is not of the form TYPEnnn with nnn between 1 and 65535", t)
+	}
+	if name, ok := dns.TypeToString[uint16(n)]; ok {
+		return 0, fmt.Errorf("type %s is %s: use %s() instead", t, name, name)
+	}
+	return uint16(n), nil
+}
+
+// SetTargetRFC3597 sets the type and the target of a record of a type
+// in the generic format of RFC 3597. rdata is the hex data, without
+// `\#` and the length.
+func (rc *RecordConfig) SetTargetRFC3597(rtype string, rdata string) error {
+	if _, err := rfc3597TypeNumber(rtype); err != nil {
+		return err
+	}
+	data, err := hex.DecodeString(rdata)
+	if err != nil {
+		return fmt.Errorf("%s rdata is not hexadecimal: %w", rtype, err)
+	}
+	rc.Type = rtype
+	rc.SetTarget(fmt.Sprintf(`\# %d %x`, len(data), data))
+	if len(data) == 0 {
+		rc.SetTarget(`\# 0`)
+	}
+	return nil
+}
+
+// SetTargetRFC3597String is like SetTargetRFC3597 but accepts the
+// target in the format of RFC 3597 (`\# 4 0a000001`, where the hex data
+// may be split by spaces). The hex data alone is accepted too.
+func (rc *RecordConfig) SetTargetRFC3597String(rtype, s string) error {
+	part := strings.Fields(s)
+	if len(part) == 0 || part[0] != `\#` {
+		return rc.SetTargetRFC3597(rtype, strings.Join(part, ""))
+	}
+	if len(part) < 2 {
+		return fmt.Errorf("%s rdata (%q) has no length", rtype, s)
+	}
+	length, err := strconv.ParseUint(part[1], 10, 16)
+	if err != nil {
+		return fmt.Errorf("%s rdata (%q) has an invalid length: %w", rtype, s, err)
+	}
+	rdata := strings.Join(part[2:], "")
+	if uint64(len(rdata)) != 2*length {
+		return fmt.Errorf("%s rdata (%q) is not %d bytes long", rtype, s, length)
+	}
+	return rc.SetTargetRFC3597(rtype, rdata)
+}
+
+// GetRFC3597Data returns the hex data of a record of a type in the
+// generic format of RFC 3597.
+func (rc *RecordConfig) GetRFC3597Data() string {
+	part := strings.Fields(rc.target)
+	if len(part) < 3 {
+		return ""
+	}
+	return part[2]
+}
+
+// toRFC3597 converts the record to a dns.RFC3597 of type rdtype.
+func (rc *RecordConfig) toRFC3597(rdtype uint16) dns.RR {
+	rr := &dns.RFC3597{Rdata: rc.GetRFC3597Data()}
+	rr.Hdr = dns.RR_Header{Name: rc.NameFQDN + ".", Rrtype: rdtype, Class: dns.ClassINET, Ttl: rc.TTL}
+	if rc.TTL == 0 {
+		rr.Hdr.Ttl = DefaultTTL
+	}
+	return rr
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestIsRFC3597Type(t *testing.T) {
+	for typ, want := range map[string]bool{
+		"TYPE65534": true,
+		"TYPE1":     false, // A
+		"TYPE256":   false, // URI
+		"TYPE0":     false,
+		"TYPE65536": false,
+		"TYPEA":     false,
+		"A":         false,
+	} {
+		if got := IsRFC3597Type(typ); got != want {
+			t.Errorf("IsRFC3597Type(%q) = %v, want %v", typ, got, want)
+		}
+	}
+}
+
+func TestSetTargetRFC3597String(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{`\# 4 0A000001`, `\# 4 0a000001`, false},
+		{`\# 4 0a00 0001`, `\# 4 0a000001`, false},
+		{`0a000001`, `\# 4 0a000001`, false},
+		{`\# 0`, `\# 0`, false},
+		{`\# 3 0a000001`, "", true},
+		{`\# 4 0a0000zz`, "", true},
+		{`\#`, "", true},
+	}
+	for _, tt := range tests {
+		rc := &RecordConfig{TTL: 300}
+		rc.SetLabel("exp", "example.com")
+		err := rc.SetTargetRFC3597String("TYPE65534", tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SetTargetRFC3597String(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := rc.GetTargetCombined(); got != tt.want {
+			t.Errorf("SetTargetRFC3597String(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+
+		// The record survives a round trip through a zone file.
+		rr, err := dns.NewRR(rc.ToRR().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := RRtoRC(rr, "example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back.Type != rc.Type || back.GetTargetDebug() != rc.GetTargetDebug() {
+			t.Errorf("RRtoRC() = %s %q, want %s %q", back.Type, back.GetTargetDebug(), rc.Type, rc.GetTargetDebug())
+		}
+	}
+
+	rc := &RecordConfig{}
+	if err := rc.SetTargetRFC3597String("TYPE1", `\# 4 0a000001`); err == nil {
+		t.Errorf("SetTargetRFC3597String(TYPE1) accepted a type that has a name")
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// SetTargetSVCB sets the SVCB fields.
+func (rc *RecordConfig) SetTargetSVCB(priority uint16, target string, params []dns.SVCBKeyValue) error {
+	rc.SvcPriority = priority
+	rc.SetTarget(target)
+	paramsStr := []string{}
+	for _, kv := range params {
+		paramsStr = append(paramsStr, fmt.Sprintf("%s=%s", kv.Key(), kv.String()))
+	}
+	rc.SvcParams = strings.Join(paramsStr, " ")
+	if rc.Type == "" {
+		rc.Type = "SVCB"
+	}
+	if rc.Type != "SVCB" && rc.Type != "HTTPS" {
+		panic("assertion failed: SetTargetSVCB called when .Type is not SVCB or HTTPS")
+	}
+	return nil
+}
+
+// SetTargetHTTPS is like SetTargetSVCB but for HTTPS records.
+func (rc *RecordConfig) SetTargetHTTPS(priority uint16, target string, params []dns.SVCBKeyValue) error {
+	if rc.Type == "" {
+		rc.Type = "HTTPS"
+	}
+	return rc.SetTargetSVCB(priority, target, params)
+}
+
+// SetTargetSVCBString is like SetTargetSVCB but accepts one big string.
+// The parsing is done by miekg/dns, therefore the params are normalized
+// (for example, "alpn=h3,h2" stays as-is but keys are lowercased).
+// It is used for both SVCB and HTTPS records; if .Type is not set, SVCB is assumed.
+func (rc *RecordConfig) SetTargetSVCBString(origin, contents string) error {
+	if rc.Type == "" {
+		rc.Type = "SVCB"
+	}
+	record, err := dns.NewRR(fmt.Sprintf("%s. %s %s", origin, rc.Type, contents))
+	if err != nil {
+		return fmt.Errorf("could not parse %s record: %w", rc.Type, err)
+	}
+	switch r := record.(type) {
+	case *dns.HTTPS:
+		return rc.SetTargetSVCB(r.Priority, r.Target, r.Value)
+	case *dns.SVCB:
+		return rc.SetTargetSVCB(r.Priority, r.Target, r.Value)
+	}
+	return fmt.Errorf("%s value could not be parsed: (%#v)", rc.Type, contents)
+}
+
+// GetSVCBValue returns the SvcParams of an SVCB or HTTPS record in the
+// format used by miekg/dns, or an error if they can't be parsed.
+func (rc *RecordConfig) GetSVCBValue() ([]dns.SVCBKeyValue, error) {
+	// Only the params are needed: the name and the target, which may not
+	// be fully qualified yet, are left out.
+	record, err := dns.NewRR(fmt.Sprintf(". %s %d . %s", rc.Type, rc.SvcPriority, rc.SvcParams))
+	if err != nil {
+		return nil, fmt.Errorf("invalid params %q: %w", rc.SvcParams, err)
+	}
+	switch r := record.(type) {
+	case *dns.HTTPS:
+		return r.Value, nil
+	case *dns.SVCB:
+		return r.Value, nil
+	}
+	return nil, fmt.Errorf("GetSVCBValue called on a %s record", rc.Type)
+}
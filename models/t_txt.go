@@ -75,12 +75,15 @@ Most providers fall into one of these categories:
 	  RecordConfig.GetTargetRFC1035Quoted() and send that string.
 
 Note: If the API expects many strings, each 255-octets or smaller, the
-provider code must split the longer strings into smaller strings.  The
-helper function txtutil.SplitSingleLongTxt(dc.Records) will iterate
-over all TXT records and split out any strings longer than 255 octets.
-Call this once in GetDomainCorrections().  (Yes, this violates
+provider code must split the longer strings into smaller strings. If
+the API expects one string, the provider code must join them. The
+provider declares this with a txtutil.Policy (usually txtutil.SplitLong)
+passed to RegisterDomainServiceProviderType(), and calls the policy's
+Apply(dc.Records) once in GetDomainCorrections().  (Yes, this violates
 Principle 1, but we decided it is best to do it once, than provide a
-getter that would re-split the strings on every call.)
+getter that would re-split the strings on every call.)  The policy also
+lets validation reject the TXT records that the provider can't store,
+and sets the CanUseTXTMulti capability.
 
 Principle 4. Providers can communicate back to DNSControl strings they can't handle.
 
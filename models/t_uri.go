@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SetTargetURI sets the URI fields.
+func (rc *RecordConfig) SetTargetURI(priority, weight uint16, target string) error {
+	rc.UriPriority = priority
+	rc.UriWeight = weight
+	rc.SetTarget(target)
+	if rc.Type == "" {
+		rc.Type = "URI"
+	}
+	if rc.Type != "URI" {
+		panic("assertion failed: SetTargetURI called when .Type is not URI")
+	}
+	return nil
+}
+
+// SetTargetURIStrings is like SetTargetURI but accepts all parameters as strings.
+func (rc *RecordConfig) SetTargetURIStrings(priority, weight, target string) (err error) {
+	var i64priority, i64weight uint64
+	if i64priority, err = strconv.ParseUint(priority, 10, 16); err == nil {
+		if i64weight, err = strconv.ParseUint(weight, 10, 16); err == nil {
+			return rc.SetTargetURI(uint16(i64priority), uint16(i64weight), target)
+		}
+	}
+	return fmt.Errorf("URI value too big for uint16: %w", err)
+}
+
+// SetTargetURIString is like SetTargetURI but accepts one big string to be
+// parsed (`10 1 "https://example.com/"`). The quotes around the target are
+// optional.
+func (rc *RecordConfig) SetTargetURIString(s string) error {
+	part := strings.Fields(s)
+	if len(part) != 3 {
+		return fmt.Errorf("URI value does not contain 3 fields: (%#v)", s)
+	}
+	return rc.SetTargetURIStrings(part[0], part[1], StripQuotes(part[2]))
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestSetTargetURIString(t *testing.T) {
+	tests := []struct {
+		s       string
+		wantErr bool
+	}{
+		{`10 1 "https://www.example.com/Path"`, false},
+		{`10 1 https://www.example.com/Path`, false},
+		{`10 https://www.example.com/Path`, true},
+		{`10 65536 "https://www.example.com/Path"`, true},
+	}
+	for _, tt := range tests {
+		rc := &RecordConfig{Type: "URI", TTL: 300}
+		rc.SetLabel("_http._tcp", "example.com")
+		err := rc.SetTargetURIString(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SetTargetURIString(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if rc.UriPriority != 10 || rc.UriWeight != 1 || rc.GetTargetField() != "https://www.example.com/Path" {
+			t.Errorf("SetTargetURIString(%q) = %d %d %q", tt.s, rc.UriPriority, rc.UriWeight, rc.GetTargetField())
+		}
+
+		// The record survives a round trip through a zone file.
+		if got, want := rc.GetTargetCombined(), `10 1 "https://www.example.com/Path"`; got != want {
+			t.Errorf("GetTargetCombined() = %q, want %q", got, want)
+		}
+		rr, err := dns.NewRR("_http._tcp.example.com. 300 IN URI " + rc.GetTargetCombined())
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := RRtoRC(rr, "example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back.GetTargetDebug() != rc.GetTargetDebug() {
+			t.Errorf("RRtoRC() = %q, want %q", back.GetTargetDebug(), rc.GetTargetDebug())
+		}
+	}
+}
@@ -52,6 +52,8 @@ func (rc *RecordConfig) GetTargetCombined() string {
 		case "AZURE_ALIAS":
 			// Differentiate between multiple AZURE_ALIASs on the same label.
 			return fmt.Sprintf("%s atype=%s", rc.target, rc.AzureAlias["type"])
+		case "LUA":
+			return rc.luaContent()
 		default:
 			// Just return the target.
 			return rc.target
@@ -107,6 +109,10 @@ func (rc *RecordConfig) GetTargetDebug() string {
 		content += fmt.Sprintf(" caatag=%s caaflag=%d", rc.CaaTag, rc.CaaFlag)
 	case "DS":
 		content += fmt.Sprintf(" ds_algorithm=%d ds_keytag=%d ds_digesttype=%d ds_digest=%s", rc.DsAlgorithm, rc.DsKeyTag, rc.DsDigestType, rc.DsDigest)
+	case "LOC":
+		content += fmt.Sprintf(" lat=%d lon=%d alt=%d size=%d horiz=%d vert=%d", rc.LocLatitude, rc.LocLongitude, rc.LocAltitude, rc.LocSize, rc.LocHorizPre, rc.LocVertPre)
+	case "LUA":
+		content += fmt.Sprintf(" luartype=%s", rc.LuaRType)
 	case "MX":
 		content += fmt.Sprintf(" pref=%d", rc.MxPreference)
 	case "NAPTR":
@@ -117,11 +123,18 @@ func (rc *RecordConfig) GetTargetDebug() string {
 		content = fmt.Sprintf("%s ns=%v mbox=%v serial=%v refresh=%v retry=%v expire=%v minttl=%v", rc.Type, rc.target, rc.SoaMbox, rc.SoaSerial, rc.SoaRefresh, rc.SoaRetry, rc.SoaExpire, rc.SoaMinttl)
 	case "SRV":
 		content += fmt.Sprintf(" srvpriority=%d srvweight=%d srvport=%d", rc.SrvPriority, rc.SrvWeight, rc.SrvPort)
+	case "HTTPS", "SVCB":
+		content += fmt.Sprintf(" svcpriority=%d svcparams=%s", rc.SvcPriority, rc.SvcParams)
 	case "SSHFP":
 		content += fmt.Sprintf(" sshfpalgorithm=%d sshfpfingerprint=%d", rc.SshfpAlgorithm, rc.SshfpFingerprint)
 	case "TLSA":
 		content += fmt.Sprintf(" tlsausage=%d tlsaselector=%d tlsamatchingtype=%d", rc.TlsaUsage, rc.TlsaSelector, rc.TlsaMatchingType)
+	case "URI":
+		content += fmt.Sprintf(" uripriority=%d uriweight=%d", rc.UriPriority, rc.UriWeight)
 	default:
+		if IsRFC3597Type(rc.Type) {
+			break // The target is the whole rdata.
+		}
 		panic(fmt.Errorf("rc.String rtype %v unimplemented", rc.Type))
 		// We panic so that we quickly find any switch statements
 		// that have not been updated for a new RR type.
@@ -40,6 +40,8 @@ type certManager struct {
 	email         string
 	acmeDirectory string
 	acmeHost      string
+	eabKeyID      string
+	eabHMACKey    string
 
 	storage         Storage
 	cfg             *models.DNSConfig
@@ -48,8 +50,27 @@ type certManager struct {
 
 	notifier notifications.Notifier
 
-	account    *Account
-	waitedOnce bool
+	account *Account
+
+	settle      *time.Duration
+	nameservers map[string][]string // The nameservers of each provider and domain.
+	settled     map[string]bool     // The providers that we waited for.
+}
+
+// Options are the options of the ACME clients.
+type Options struct {
+	// Email is the address of the ACME account.
+	Email string
+	// Server is the directory URL of the ACME server, or one of the
+	// names in Servers.
+	Server string
+	// EABKeyID and EABHMACKey are the credentials of the external
+	// account binding, which some CAs need to create an account.
+	EABKeyID   string
+	EABHMACKey string
+	// Settle, if set, replaces the Settle of the Propagation of every
+	// provider.
+	Settle *time.Duration
 }
 
 const (
@@ -57,26 +78,61 @@ const (
 	LetsEncryptLive = "https://acme-v02.api.letsencrypt.org/directory"
 	// LetsEncryptStage is the endpoint for the staging area.
 	LetsEncryptStage = "https://acme-staging-v02.api.letsencrypt.org/directory"
+	// ZeroSSL is the endpoint of ZeroSSL. It needs an external account
+	// binding.
+	ZeroSSL = "https://acme.zerossl.com/v2/DV90"
+	// BuypassLive is the endpoint of Buypass Go SSL (production).
+	BuypassLive = "https://api.buypass.com/acme/directory"
+	// BuypassStage is the endpoint of the Buypass test environment.
+	BuypassStage = "https://api.test4.buypass.no/acme/directory"
 )
 
+// Servers are the names of the ACME servers that can be used instead
+// of their directory URL.
+var Servers = map[string]string{
+	"live":            LetsEncryptLive,
+	"staging":         LetsEncryptStage,
+	"zerossl":         ZeroSSL,
+	"buypass":         BuypassLive,
+	"buypass-staging": BuypassStage,
+}
+
+// needsEAB lists the servers that only create accounts with an
+// external account binding.
+var needsEAB = map[string]bool{
+	ZeroSSL: true,
+}
+
 // New is a factory for acme clients.
-func New(cfg *models.DNSConfig, directory string, email string, server string, notify notifications.Notifier) (Client, error) {
-	return commonNew(cfg, directoryStorage(directory), email, server, notify)
+func New(cfg *models.DNSConfig, directory string, opts Options, notify notifications.Notifier) (Client, error) {
+	return commonNew(cfg, directoryStorage(directory), opts, notify)
 }
 
-func commonNew(cfg *models.DNSConfig, storage Storage, email string, server string, notify notifications.Notifier) (Client, error) {
+func commonNew(cfg *models.DNSConfig, storage Storage, opts Options, notify notifications.Notifier) (Client, error) {
+	server := opts.Server
+	if s, ok := Servers[server]; ok {
+		server = s
+	}
 	u, err := url.Parse(server)
 	if err != nil || u.Host == "" {
 		return nil, fmt.Errorf("ACME directory '%s' is not a valid URL", server)
 	}
+	if (opts.EABKeyID == "") != (opts.EABHMACKey == "") {
+		return nil, fmt.Errorf("the external account binding needs both a key ID and a HMAC key")
+	}
 	c := &certManager{
 		storage:       storage,
-		email:         email,
+		email:         opts.Email,
 		acmeDirectory: server,
 		acmeHost:      u.Host,
+		eabKeyID:      opts.EABKeyID,
+		eabHMACKey:    opts.EABHMACKey,
 		cfg:           cfg,
 		domains:       map[string]*models.DomainConfig{},
 		notifier:      notify,
+		settle:        opts.Settle,
+		nameservers:   map[string][]string{},
+		settled:       map[string]bool{},
 	}
 
 	acct, err := c.getOrCreateAccount()
@@ -88,12 +144,12 @@ func commonNew(cfg *models.DNSConfig, storage Storage, email string, server stri
 }
 
 // NewVault is a factory for new vaunt clients.
-func NewVault(cfg *models.DNSConfig, vaultPath string, email string, server string, notify notifications.Notifier) (Client, error) {
+func NewVault(cfg *models.DNSConfig, vaultPath string, opts Options, notify notifications.Notifier) (Client, error) {
 	storage, err := makeVaultStorage(vaultPath)
 	if err != nil {
 		return nil, err
 	}
-	return commonNew(cfg, storage, email, server, notify)
+	return commonNew(cfg, storage, opts, notify)
 }
 
 // IssueOrRenewCert will obtain a certificate with the given name if it does not exist,
@@ -295,6 +351,9 @@ func (c *certManager) getAndRunCorrections(d *models.DomainConfig) error {
 	fmt.Printf("%d corrections\n", len(cs))
 	for _, corr := range cs {
 		fmt.Printf("Running [%s]\n", corr.Msg)
+		if corr.F == nil {
+			continue
+		}
 		err = corr.F()
 		c.notifier.Notify(d.Name, "certs", corr.Msg, err, false)
 		if err != nil {
@@ -4,28 +4,99 @@ import (
 	"log"
 	"time"
 
+	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/go-acme/lego/challenge/dns01"
 )
 
+// preCheckDNS reports whether the challenge record has reached the
+// nameservers of each DNS provider of the domain. Each provider is
+// checked the way its Propagation says, and the first time it is ready
+// we wait another Settle for the record to reach the rest of its
+// network, because the validation sometimes fails anyway otherwise.
 func (c *certManager) preCheckDNS(domain, fqdn, value string, native dns01.PreCheckFunc) (bool, error) {
-	// default record verification in the client library makes sure the authoritative nameservers
-	// have the expected records.
-	// Sometimes the Let's Encrypt verification fails anyway because records have not propagated the provider's network fully.
-	// So we add an additional 60 second sleep just for safety.
-	v, err := native(fqdn, value)
-	if err != nil {
-		return v, err
+	d := c.cfg.DomainContainingFQDN(domain)
+	if d == nil {
+		return native(fqdn, value)
+	}
+	var settle time.Duration
+	var unsettled []string
+	checkedNative := false
+	for _, p := range d.DNSProviderInstances {
+		if IgnoredProviders[p.Name] {
+			continue
+		}
+		prop := propagationOf(p.ProviderType)
+		nss, err := c.providerNameservers(p, d.Name)
+		if err != nil {
+			return false, err
+		}
+		var ok bool
+		if len(nss) == 0 {
+			// We don't know the nameservers. Check the ones that the
+			// zone is delegated to instead.
+			if checkedNative {
+				continue
+			}
+			ok, err = native(fqdn, value)
+			checkedNative = true
+		} else {
+			ok, err = prop.Check(fqdn, value, nss)
+		}
+		if !ok || err != nil {
+			return ok, err
+		}
+		if !c.settled[p.Name] {
+			unsettled = append(unsettled, p.Name)
+			if prop.Settle > settle {
+				settle = prop.Settle
+			}
+		}
+	}
+	if c.settle != nil {
+		settle = *c.settle
 	}
-	if !c.waitedOnce {
-		log.Printf("DNS ok. Waiting another 60s to ensure stability.")
-		time.Sleep(60 * time.Second)
-		c.waitedOnce = true
+	if len(unsettled) != 0 && settle > 0 {
+		log.Printf("DNS ok. Waiting another %s to ensure stability.", settle)
+		time.Sleep(settle)
+	}
+	for _, name := range unsettled {
+		c.settled[name] = true
 	}
 	log.Printf("DNS records seem to exist. Proceeding to request validation")
-	return v, err
+	return true, nil
+}
+
+// providerNameservers returns the addresses of the nameservers of the
+// provider p for the domain.
+func (c *certManager) providerNameservers(p *models.DNSProviderInstance, domain string) ([]string, error) {
+	key := p.Name + " " + domain
+	if nss, ok := c.nameservers[key]; ok {
+		return nss, nil
+	}
+	list, err := p.Driver.GetNameservers(domain)
+	if err != nil {
+		return nil, err
+	}
+	nss := []string{}
+	for _, ns := range list {
+		nss = append(nss, nameserverAddr(ns.Name))
+	}
+	c.nameservers[key] = nss
+	return nss, nil
 }
 
-// Timeout increases the client-side polling check time to five minutes with one second waits in-between.
+// Timeout is how long to wait for the challenge records: the longest
+// Timeout of the providers, polling every second.
 func (c *certManager) Timeout() (timeout, interval time.Duration) {
-	return 5 * time.Minute, time.Second
+	for _, d := range c.cfg.Domains {
+		for _, p := range d.DNSProviderInstances {
+			if t := propagationOf(p.ProviderType).Timeout; t > timeout && !IgnoredProviders[p.Name] {
+				timeout = t
+			}
+		}
+	}
+	if timeout == 0 {
+		timeout = DefaultPropagation.Timeout
+	}
+	return timeout, time.Second
 }
@@ -0,0 +1,109 @@
+package acme
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Propagation describes how to wait until the challenge records that
+// are created at a DNS provider are served by all its nameservers.
+type Propagation struct {
+	// Timeout is how long to wait for the nameservers to serve the
+	// record.
+	Timeout time.Duration
+	// Settle is how long to wait once they do, for the servers that
+	// don't answer at the addresses of the nameservers (e.g. the other
+	// servers of an anycast network) to catch up.
+	Settle time.Duration
+	// Check reports whether the nameservers serve the record. If nil,
+	// CheckNameservers is used.
+	Check PropagationCheck
+}
+
+// PropagationCheck reports whether all the nameservers, given as
+// "host:port", serve the TXT record fqdn with the value.
+type PropagationCheck func(fqdn, value string, nameservers []string) (bool, error)
+
+// DefaultPropagation is the Propagation of the providers that haven't
+// registered their own.
+var DefaultPropagation = Propagation{
+	Timeout: 5 * time.Minute,
+	Settle:  60 * time.Second,
+	Check:   CheckNameservers,
+}
+
+var propagations = map[string]Propagation{
+	// The changes reach the whole anycast network within seconds.
+	"CLOUDFLAREAPI": {Settle: 10 * time.Second},
+	"NS1":           {Settle: 10 * time.Second},
+}
+
+// RegisterPropagation sets how to wait for the challenge records at
+// the providers of type providerType. The fields that are not set are
+// taken from DefaultPropagation.
+func RegisterPropagation(providerType string, p Propagation) {
+	propagations[providerType] = p
+}
+
+// propagationOf returns the Propagation of the providers of type
+// providerType.
+func propagationOf(providerType string) Propagation {
+	p := propagations[providerType]
+	if p.Timeout == 0 {
+		p.Timeout = DefaultPropagation.Timeout
+	}
+	if p.Settle == 0 {
+		p.Settle = DefaultPropagation.Settle
+	}
+	if p.Check == nil {
+		p.Check = DefaultPropagation.Check
+	}
+	return p
+}
+
+// CheckNameservers queries each of the nameservers for the TXT record
+// fqdn, without recursion, and reports whether they all return value.
+func CheckNameservers(fqdn, value string, nameservers []string) (bool, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(fqdn), dns.TypeTXT)
+	m.RecursionDesired = false
+	udp := &dns.Client{Timeout: 10 * time.Second}
+	tcp := &dns.Client{Net: "tcp", Timeout: 10 * time.Second}
+	for _, ns := range nameservers {
+		r, _, err := udp.Exchange(m, ns)
+		if err == nil && r.Truncated {
+			r, _, err = tcp.Exchange(m, ns)
+		}
+		if err != nil {
+			return false, fmt.Errorf("querying %s for %s: %w", ns, fqdn, err)
+		}
+		if r.Rcode != dns.RcodeSuccess && r.Rcode != dns.RcodeNameError {
+			return false, fmt.Errorf("querying %s for %s: %s", ns, fqdn, dns.RcodeToString[r.Rcode])
+		}
+		if !hasTXT(r.Answer, value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func hasTXT(rrs []dns.RR, value string) bool {
+	for _, rr := range rrs {
+		if txt, ok := rr.(*dns.TXT); ok && strings.Join(txt.Txt, "") == value {
+			return true
+		}
+	}
+	return false
+}
+
+// nameserverAddr returns the address to query the nameserver ns at.
+func nameserverAddr(ns string) string {
+	if _, _, err := net.SplitHostPort(ns); err == nil {
+		return ns
+	}
+	return net.JoinHostPort(strings.TrimSuffix(ns, "."), "53")
+}
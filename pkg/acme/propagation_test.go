@@ -0,0 +1,67 @@
+package acme
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// serveTXT starts a nameserver that answers with the TXT record
+// _acme-challenge.example.com with the value.
+func serveTXT(t *testing.T, value string) string {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		if r.Question[0].Name == "_acme-challenge.example.com." && value != "" {
+			rr, _ := dns.NewRR(`_acme-challenge.example.com. 60 IN TXT "` + value + `"`)
+			m.Answer = append(m.Answer, rr)
+		}
+		w.WriteMsg(m)
+	})}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+	return pc.LocalAddr().String()
+}
+
+func TestCheckNameservers(t *testing.T) {
+	ns1 := serveTXT(t, "token")
+	ns2 := serveTXT(t, "old-token")
+	for _, tst := range []struct {
+		nss  []string
+		want bool
+	}{
+		{[]string{ns1}, true},
+		{[]string{ns1, ns2}, false},
+		{[]string{ns2}, false},
+	} {
+		got, err := CheckNameservers("_acme-challenge.example.com", "token", tst.nss)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tst.want {
+			t.Errorf("CheckNameservers(%v) = %v, want %v", tst.nss, got, tst.want)
+		}
+	}
+}
+
+func TestPropagationOf(t *testing.T) {
+	RegisterPropagation("SLOWDNS", Propagation{Timeout: time.Hour})
+	defer delete(propagations, "SLOWDNS")
+
+	p := propagationOf("SLOWDNS")
+	if p.Timeout != time.Hour || p.Settle != DefaultPropagation.Settle || p.Check == nil {
+		t.Errorf("got %+v, want the Timeout of SLOWDNS and the defaults", p)
+	}
+	if p := propagationOf("CLOUDFLAREAPI"); p.Settle != 10*time.Second || p.Timeout != DefaultPropagation.Timeout {
+		t.Errorf("got %+v for CLOUDFLAREAPI", p)
+	}
+	if got := nameserverAddr("ns1.example.com."); got != "ns1.example.com:53" {
+		t.Errorf("nameserverAddr = %q", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"fmt"
 
 	"github.com/go-acme/lego/certcrypto"
 	"github.com/go-acme/lego/lego"
@@ -29,6 +30,9 @@ func (c *certManager) getOrCreateAccount() (*Account, error) {
 }
 
 func (c *certManager) createAccount(email string) (*Account, error) {
+	if c.eabKeyID == "" && needsEAB[c.acmeDirectory] {
+		return nil, fmt.Errorf("%s needs an external account binding to create an account (--eab-kid and --eab-hmac)", c.acmeHost)
+	}
 	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
 	if err != nil {
 		return nil, err
@@ -44,7 +48,16 @@ func (c *certManager) createAccount(email string) (*Account, error) {
 	if err != nil {
 		return nil, err
 	}
-	reg, err := client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	var reg *registration.Resource
+	if c.eabKeyID != "" {
+		reg, err = client.Registration.RegisterWithExternalAccountBinding(registration.RegisterEABOptions{
+			TermsOfServiceAgreed: true,
+			Kid:                  c.eabKeyID,
+			HmacEncoded:          c.eabHMACKey,
+		})
+	} else {
+		reg, err = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	}
 	if err != nil {
 		return nil, err
 	}
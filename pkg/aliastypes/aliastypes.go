@@ -0,0 +1,72 @@
+// Package aliastypes describes how the API of a DNS provider serves
+// ALIAS records, i.e. names that resolve like a CNAME but can be at
+// the apex (ALIAS, ANAME, CNAME flattening).
+package aliastypes
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Policy is the record type that a provider turns ALIAS records into.
+// Registering one sets providers.CanUseAlias.
+//
+// Providers without a policy that can use ALIAS store the records as
+// they are.
+type Policy struct {
+	// Type is the record type that the API stores ALIAS records as
+	// ("ALIAS" or "ANAME"). "" means the API has no such type: ALIAS
+	// records are stored as CNAME records, which the provider
+	// flattens (at least at the apex).
+	Type string
+	// ApexOnly is true if Type is only accepted at the apex. ALIAS
+	// records elsewhere are stored as CNAME records.
+	ApexOnly bool
+}
+
+var (
+	// Native is the policy of the providers with an ALIAS type.
+	Native = Policy{Type: "ALIAS"}
+	// ANAME is the policy of the providers that call ALIAS ANAME.
+	ANAME = Policy{Type: "ANAME"}
+	// FlattenedCNAME is the policy of the providers that serve ALIAS
+	// records by flattening CNAME records.
+	FlattenedCNAME = Policy{}
+)
+
+// TypeFor returns the record type that the API stores the ALIAS
+// record rc as.
+func (p Policy) TypeFor(rc *models.RecordConfig) string {
+	if p.Type == "" || (p.ApexOnly && rc.GetLabel() != "@") {
+		return "CNAME"
+	}
+	return p.Type
+}
+
+// Apply rewrites the ALIAS records the way the API stores them.
+func (p Policy) Apply(records []*models.RecordConfig) {
+	for _, rc := range records {
+		if rc.Type == "ALIAS" {
+			rc.Type = p.TypeFor(rc)
+		}
+	}
+}
+
+// Check returns an error if the API can't store the ALIAS record rc of
+// a zone with the given records, and a warning if it stores it with
+// different semantics. An ALIAS record that becomes a CNAME record
+// elsewhere than at the apex is served as a CNAME (the resolvers
+// follow it, instead of getting the addresses), and can't share its
+// name with other records.
+func (p Policy) Check(rc *models.RecordConfig, records []*models.RecordConfig) (warning, err error) {
+	if rc.Type != "ALIAS" || p.TypeFor(rc) != "CNAME" || rc.GetLabel() == "@" {
+		return nil, nil
+	}
+	for _, other := range records {
+		if other != rc && other.GetLabel() == rc.GetLabel() {
+			return nil, fmt.Errorf("ALIAS %s is stored as a CNAME, which can't have the same name as the %s record", rc.GetLabelFQDN(), other.Type)
+		}
+	}
+	return fmt.Errorf("ALIAS %s is stored as a CNAME", rc.GetLabelFQDN()), nil
+}
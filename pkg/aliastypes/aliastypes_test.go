@@ -0,0 +1,69 @@
+package aliastypes
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func makeRC(label, rtype string) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: rtype}
+	rc.SetLabel(label, "example.com")
+	return rc
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		policy   Policy
+		wantApex string
+		wantWWW  string
+	}{
+		{Native, "ALIAS", "ALIAS"},
+		{ANAME, "ANAME", "ANAME"},
+		{FlattenedCNAME, "CNAME", "CNAME"},
+		{Policy{Type: "ALIAS", ApexOnly: true}, "ALIAS", "CNAME"},
+	}
+	for _, tt := range tests {
+		apex, www, a := makeRC("@", "ALIAS"), makeRC("www", "ALIAS"), makeRC("@", "A")
+		tt.policy.Apply([]*models.RecordConfig{apex, www, a})
+		if apex.Type != tt.wantApex || www.Type != tt.wantWWW || a.Type != "A" {
+			t.Errorf("%+v: got %s %s %s, want %s %s A", tt.policy, apex.Type, www.Type, a.Type, tt.wantApex, tt.wantWWW)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	apex, www, shop, shopTXT := makeRC("@", "ALIAS"), makeRC("www", "ALIAS"), makeRC("shop", "ALIAS"), makeRC("shop", "TXT")
+	records := []*models.RecordConfig{apex, makeRC("@", "MX"), www, shop, shopTXT}
+
+	tests := []struct {
+		policy  Policy
+		rc      *models.RecordConfig
+		warning string
+		err     string
+	}{
+		{Native, www, "", ""},
+		{ANAME, shop, "", ""},
+		{FlattenedCNAME, apex, "", ""},
+		{FlattenedCNAME, www, "ALIAS www.example.com is stored as a CNAME", ""},
+		{FlattenedCNAME, shop, "", "ALIAS shop.example.com is stored as a CNAME, which can't have the same name as the TXT record"},
+		{Policy{Type: "ALIAS", ApexOnly: true}, www, "ALIAS www.example.com is stored as a CNAME", ""},
+		{FlattenedCNAME, shopTXT, "", ""},
+	}
+	for _, tt := range tests {
+		warning, err := tt.policy.Check(tt.rc, records)
+		if got := errString(warning); got != tt.warning {
+			t.Errorf("%+v Check(%s %s) warning = %q, want %q", tt.policy, tt.rc.Type, tt.rc.GetLabel(), got, tt.warning)
+		}
+		if got := errString(err); got != tt.err {
+			t.Errorf("%+v Check(%s %s) error = %q, want %q", tt.policy, tt.rc.Type, tt.rc.GetLabel(), got, tt.err)
+		}
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
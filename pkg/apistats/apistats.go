@@ -0,0 +1,148 @@
+// Package apistats counts the requests that the providers make to their
+// APIs, for `dnscontrol preview --report stats`.
+//
+// The requests are counted by Transport, which pkg/httpclient puts in
+// front of the HTTP clients of the providers, in the Scope of the
+// domain being processed (see Start). The caches of the providers
+// (providers.Memo) and of preview (--cache-dir) call CacheHit().
+// Nothing is counted until Enable() is called.
+package apistats
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Stats are the numbers reported for a domain.
+type Stats struct {
+	Elapsed time.Duration
+	// Calls is the number of HTTP requests sent to the APIs.
+	Calls int
+	// Retries is the number of requests that repeat a request that
+	// failed or was throttled.
+	Retries int
+	// Throttled is the number of responses with status 429 (Too Many
+	// Requests).
+	Throttled int
+	// CacheHits is the number of API results taken from a cache
+	// instead.
+	CacheHits int
+}
+
+// Add adds the counts of o to s.
+func (s *Stats) Add(o Stats) {
+	s.Elapsed += o.Elapsed
+	s.Calls += o.Calls
+	s.Retries += o.Retries
+	s.Throttled += o.Throttled
+	s.CacheHits += o.CacheHits
+}
+
+// Scope collects the Stats of one domain. Requests made while several
+// scopes are open (preview --concurrency) can't be attributed to any
+// of them; they are counted in Shared().
+type Scope struct {
+	start time.Time
+	stats Stats
+}
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	active  = map[*Scope]bool{}
+	shared  Stats
+	// failed records the requests (method and URL) whose last attempt
+	// failed, so that their next attempt is counted as a retry.
+	failed = map[string]bool{}
+)
+
+// Start opens a Scope.
+func Start() *Scope {
+	s := &Scope{start: time.Now()}
+	mu.Lock()
+	defer mu.Unlock()
+	active[s] = true
+	return s
+}
+
+// Stop closes the Scope and returns its Stats.
+func (s *Scope) Stop() Stats {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(active, s)
+	s.stats.Elapsed = time.Since(s.start)
+	return s.stats
+}
+
+// Shared returns the counts that could not be attributed to a Scope.
+func Shared() Stats {
+	mu.Lock()
+	defer mu.Unlock()
+	return shared
+}
+
+// count calls f with the Stats to update: those of the only open
+// Scope, or the shared ones. The caller holds mu.
+func count(f func(*Stats)) {
+	if len(active) == 1 {
+		for s := range active {
+			f(&s.stats)
+		}
+		return
+	}
+	f(&shared)
+}
+
+// CacheHit counts an API result taken from a cache.
+func CacheHit() {
+	mu.Lock()
+	defer mu.Unlock()
+	if enabled {
+		count(func(s *Stats) { s.CacheHits++ })
+	}
+}
+
+// Transport is an http.RoundTripper that counts the requests it sends
+// with Base (http.DefaultTransport if nil).
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+
+	key := req.Method + " " + req.URL.String()
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return resp, err
+	}
+	count(func(s *Stats) {
+		s.Calls++
+		if failed[key] {
+			s.Retries++
+		}
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			s.Throttled++
+		}
+	})
+	if err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		failed[key] = true
+	} else {
+		delete(failed, key)
+	}
+	return resp, err
+}
+
+// Enable starts counting.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+}
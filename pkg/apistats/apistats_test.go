@@ -0,0 +1,54 @@
+package apistats
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTransport(t *testing.T) {
+	// The first request to /flaky is throttled, the second succeeds.
+	throttled := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/flaky" && !throttled {
+			throttled = true
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: &Transport{}}
+	get := func(path string) {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	enabled = true
+	defer func() { enabled = false }()
+
+	s := Start()
+	get("/zones")
+	get("/flaky")
+	get("/flaky")
+	get("/zones")
+	CacheHit()
+	got := s.Stop()
+	got.Elapsed = 0
+	want := Stats{Calls: 4, Retries: 1, Throttled: 1, CacheHits: 1}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// With two scopes open, the requests are shared.
+	a, b := Start(), Start()
+	get("/zones")
+	if got := a.Stop(); got.Calls != 0 {
+		t.Errorf("got %d calls in an overlapping scope, want 0", got.Calls)
+	}
+	b.Stop()
+	if got := Shared(); got.Calls != 1 {
+		t.Errorf("got %d shared calls, want 1", got.Calls)
+	}
+}
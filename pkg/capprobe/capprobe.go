@@ -0,0 +1,200 @@
+// Package capprobe checks which record types a DNS provider really
+// supports. Each probe creates records of one type in a sandbox zone,
+// reads them back and verifies that a second push is a no-op. The
+// results are emitted as the provider's providers.DocumentationNotes
+// table, so that the feature matrix doesn't drift from reality.
+//
+// The sandbox zone is wiped: every record except the NS records at
+// the apex is deleted. Never run a probe against a zone that is in use.
+package capprobe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/nameservers"
+	"github.com/StackExchange/dnscontrol/v3/pkg/normalize"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+// Probe describes the check of one capability.
+type Probe struct {
+	Capability providers.Capability
+	// Records are the records to create, as "label type contents"
+	// in zone file syntax (see models.RecordConfig.PopulateFromString).
+	Records []string
+}
+
+// Probes are the probes that Run runs, in order.
+var Probes = []Probe{
+	{providers.CanUseAlias, []string{"@ ALIAS example.net."}},
+	{providers.CanUseCAA, []string{
+		`probe-caa CAA 0 issue "letsencrypt.org"`,
+		`probe-caa CAA 128 iodef "mailto:hostmaster@example.net"`,
+	}},
+	{providers.CanUseDS, []string{"@ DS 12345 13 2 4D6F2A8A19D16C4B3A8C1E1E3B2B6A1CB4A4E54F7E3F5F4D1E3D2B1A0F9E8D7C"}},
+	{providers.CanUseDSForChildren, []string{
+		"probe-ds NS ns1.example.net.",
+		"probe-ds DS 12345 13 2 4D6F2A8A19D16C4B3A8C1E1E3B2B6A1CB4A4E54F7E3F5F4D1E3D2B1A0F9E8D7C",
+	}},
+	{providers.CanUseHTTPS, []string{`probe-https HTTPS 1 . alpn="h2,h3"`}},
+	{providers.CanUseLOC, []string{"probe-loc LOC 52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m"}},
+	{providers.CanUseNAPTR, []string{`probe-naptr NAPTR 100 10 "U" "E2U+sip" "!^.*$!sip:info@example.net!" .`}},
+	{providers.CanUsePTR, []string{"probe-ptr PTR host.example.net."}},
+	{providers.CanUseRFC3597, []string{`probe-rfc3597 TYPE65534 \# 4 0a000001`}},
+	{providers.CanUseSRV, []string{"_sip._tcp.probe-srv SRV 10 60 5060 sip.example.net."}},
+	{providers.CanUseSSHFP, []string{"probe-sshfp SSHFP 4 2 123456789abcdef67890123456789abcdef67890123456789abcdef123456789"}},
+	{providers.CanUseSVCB, []string{"_8443._foo.probe-svcb SVCB 1 svc.example.net. port=8443"}},
+	{providers.CanUseTLSA, []string{"_443._tcp.probe-tlsa TLSA 3 1 1 abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789"}},
+	{providers.CanUseURI, []string{`_http._tcp.probe-uri URI 10 1 "https://www.example.net/"`}},
+}
+
+// Result is the outcome of one probe.
+type Result struct {
+	Capability providers.Capability
+	OK         bool
+	Reason     string // Why the probe failed
+}
+
+// Note returns the documentation note for the result.
+func (r Result) Note() *providers.DocumentationNote {
+	if r.OK {
+		return providers.Can()
+	}
+	return providers.Cannot(r.Reason)
+}
+
+// Run runs the probes against a sandbox zone of the provider. pType
+// is the provider type (BIND, ROUTE53, etc.), which is needed to
+// audit the records. logf, if not nil, receives progress messages.
+//
+// A probe that fails doesn't stop the others; Run only returns an
+// error if the zone can't be cleaned up.
+func Run(pType string, dsp providers.DNSServiceProvider, domain string, probes []Probe, logf func(format string, args ...interface{})) ([]Result, error) {
+	if logf == nil {
+		logf = func(string, ...interface{}) {}
+	}
+	ns, err := dsp.GetNameservers(domain)
+	if err != nil {
+		return nil, fmt.Errorf("getting the nameservers of %s: %w", domain, err)
+	}
+	base := &models.DomainConfig{Name: domain, Nameservers: ns}
+	normalize.UpdateNameSplitHorizon(base)
+	nameservers.AddNSRecords(base)
+
+	logf("Cleaning up %s\n", domain)
+	if err := push(dsp, base); err != nil {
+		return nil, fmt.Errorf("cleaning up %s: %w", domain, err)
+	}
+
+	var results []Result
+	for _, p := range probes {
+		logf("Probing %s\n", p.Capability)
+		r := Result{Capability: p.Capability, OK: true}
+		if err := runProbe(pType, dsp, base, p); err != nil {
+			r.OK, r.Reason = false, err.Error()
+			logf("  %s\n", r.Reason)
+		}
+		results = append(results, r)
+		if err := push(dsp, base); err != nil {
+			return results, fmt.Errorf("cleaning up %s after probing %s: %w", domain, p.Capability, err)
+		}
+	}
+	return results, nil
+}
+
+func runProbe(pType string, dsp providers.DNSServiceProvider, base *models.DomainConfig, p Probe) error {
+	dc, err := base.Copy()
+	if err != nil {
+		return err
+	}
+	var added models.Records
+	for _, line := range p.Records {
+		rc, err := parseRecord(line, dc.Name)
+		if err != nil {
+			return fmt.Errorf("invalid probe %q: %w", line, err)
+		}
+		added = append(added, rc)
+	}
+	dc.Records = append(dc.Records, added...)
+	models.PostProcessRecords(dc.Records)
+
+	if errs := providers.AuditRecords(pType, added); len(errs) > 0 {
+		return fmt.Errorf("rejected by the provider's record audit: %s", errs[0])
+	}
+
+	want, err := dc.Copy()
+	if err != nil {
+		return err
+	}
+	if err := push(dsp, dc); err != nil {
+		return fmt.Errorf("create failed: %w", err)
+	}
+
+	existing, err := dsp.GetZoneRecords(want.Name)
+	if err != nil {
+		return fmt.Errorf("read back failed: %w", err)
+	}
+	for _, rc := range added {
+		if !contains(existing, rc) {
+			return fmt.Errorf("%s %s was not returned by the provider", rc.GetLabel(), rc.Type)
+		}
+	}
+
+	corrections, err := dsp.GetDomainCorrections(want)
+	if err != nil {
+		return fmt.Errorf("second pass failed: %w", err)
+	}
+	if len(corrections) > 0 {
+		return fmt.Errorf("not stable, the second pass wants to %s", strings.TrimSpace(corrections[0].Msg))
+	}
+	return nil
+}
+
+// parseRecord parses a "label type contents" line.
+func parseRecord(line, origin string) (*models.RecordConfig, error) {
+	parts := strings.SplitN(line, " ", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("expected label, type and contents")
+	}
+	rc := &models.RecordConfig{TTL: 300}
+	rc.SetLabel(parts[0], origin)
+	if err := rc.PopulateFromString(parts[1], parts[2], origin); err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
+// contains reports whether recs has a record with the same label,
+// type and target as rc.
+func contains(recs models.Records, rc *models.RecordConfig) bool {
+	for _, r := range recs {
+		if r.Type == rc.Type && r.GetLabel() == rc.GetLabel() &&
+			strings.EqualFold(r.GetTargetCombined(), rc.GetTargetCombined()) {
+			return true
+		}
+	}
+	return false
+}
+
+// push runs the corrections that make the zone match dc.
+func push(dsp providers.DNSServiceProvider, dc *models.DomainConfig) error {
+	dc, err := dc.Copy()
+	if err != nil {
+		return err
+	}
+	corrections, err := dsp.GetDomainCorrections(dc)
+	if err != nil {
+		return err
+	}
+	for _, c := range corrections {
+		if c.F == nil {
+			continue
+		}
+		if err := c.F(); err != nil {
+			return fmt.Errorf("%s: %w", c.Msg, err)
+		}
+	}
+	return nil
+}
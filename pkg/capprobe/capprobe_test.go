@@ -0,0 +1,64 @@
+package capprobe
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/providers"
+	_ "github.com/StackExchange/dnscontrol/v3/providers/bind"
+)
+
+func TestRunBind(t *testing.T) {
+	dsp, err := providers.CreateDNSProvider("BIND", map[string]string{"directory": t.TempDir()},
+		[]byte(`{"default_ns": ["ns1.example.net."]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := Run("BIND", dsp, "example.com", Probes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(Probes) {
+		t.Fatalf("got %d results, want %d", len(results), len(Probes))
+	}
+	for _, r := range results {
+		want := providers.ProviderHasCapability("BIND", r.Capability)
+		if r.Capability == providers.CanUseDSForChildren {
+			// CanUseDS implies CanUseDSForChildren.
+			want = providers.ProviderHasCapability("BIND", providers.CanUseDS)
+		}
+		if r.OK != want {
+			t.Errorf("%s: got %v (%s), want %v", r.Capability, r.OK, r.Reason, want)
+		}
+	}
+}
+
+func TestNotes(t *testing.T) {
+	notes := Notes("BIND", []Result{
+		{Capability: providers.CanUseCAA, OK: true},
+		{Capability: providers.CanUseLOC, OK: false, Reason: "LOC was not returned"},
+		{Capability: providers.CanUseAzureAlias, OK: false, Reason: "rejected"},
+		{Capability: providers.CanUseDSForChildren, OK: true},
+	})
+	buf := &bytes.Buffer{}
+	if err := WriteGo(buf, notes, "Probed against example.com."); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if strings.Contains(got, "CanUseDSForChildren") {
+		t.Errorf("CanUseDSForChildren should be implied by CanUseDS:\n%s", got)
+	}
+	for _, want := range []string{
+		"// Probed against example.com.\nvar features = providers.DocumentationNotes{\n",
+		"\tproviders.CanAutoDNSSEC:          providers.Can(\"Just writes out a comment indicating DNSSEC was requested\"),\n",
+		"\tproviders.CanUseAzureAlias:       providers.Cannot(\"rejected\"),\n",
+		"\tproviders.CanUseCAA:              providers.Can(),\n",
+		"\tproviders.CanUseLOC:              providers.Cannot(\"LOC was not returned\"),\n",
+		"\tproviders.CantUseNOPURGE:         providers.Cannot(),\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package capprobe
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+// lastCapability is the last Capability constant.
+const lastCapability = providers.DocOfficiallySupported
+
+// Notes returns the documentation notes of the provider type pType,
+// updated with the results of the probes. The notes of capabilities
+// that weren't probed are kept. So are the notes (and their comments)
+// that agree with the results. CanUseDS implies CanUseDSForChildren,
+// so the latter is only added when it's needed.
+func Notes(pType string, results []Result) providers.DocumentationNotes {
+	notes := providers.DocumentationNotes{}
+	for c := providers.Capability(0); c <= lastCapability; c++ {
+		if n := providers.Notes[pType][c]; n != nil {
+			notes[c] = n
+		} else if providers.ProviderHasCapability(pType, c) {
+			notes[c] = providers.Can()
+		}
+	}
+	for _, r := range results {
+		if n := notes[r.Capability]; n != nil && n.HasFeature == r.OK {
+			continue
+		}
+		if r.Capability == providers.CanUseDSForChildren && r.OK && notes[providers.CanUseDS] != nil && notes[providers.CanUseDS].HasFeature {
+			continue
+		}
+		notes[r.Capability] = r.Note()
+	}
+	return notes
+}
+
+// WriteGo writes notes as the Go source of the "features" table that
+// providers pass to providers.RegisterDomainServiceProviderType.
+func WriteGo(w io.Writer, notes providers.DocumentationNotes, comment string) error {
+	caps := make([]providers.Capability, 0, len(notes))
+	for c := range notes {
+		caps = append(caps, c)
+	}
+	sort.Slice(caps, func(i, j int) bool { return caps[i].String() < caps[j].String() })
+
+	buf := &bytes.Buffer{}
+	if comment != "" {
+		fmt.Fprintf(buf, "// %s\n", comment)
+	}
+	fmt.Fprintln(buf, "var features = providers.DocumentationNotes{")
+	for _, c := range caps {
+		fmt.Fprintf(buf, "providers.%s: %s,\n", c, noteSource(notes[c]))
+	}
+	fmt.Fprintln(buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting the notes: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// noteSource returns the Go expression that creates n.
+func noteSource(n *providers.DocumentationNote) string {
+	fn := "Cannot"
+	if n.HasFeature {
+		fn = "Can"
+	} else if n.Unimplemented {
+		fn = "Unimplemented"
+	}
+	var args string
+	switch {
+	case n.Link != "":
+		args = strconv.Quote(n.Comment) + ", " + strconv.Quote(n.Link)
+	case n.Comment != "":
+		args = strconv.Quote(n.Comment)
+	}
+	return fmt.Sprintf("providers.%s(%s)", fn, args)
+}
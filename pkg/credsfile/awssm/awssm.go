@@ -0,0 +1,126 @@
+// Package awssm lets creds.json refer to secrets kept in AWS Secrets
+// Manager:
+//
+//	"r53": "awssm://dnscontrol/route53"
+//	"apitoken": "awssm://dnscontrol/cloudflare#apitoken"
+//
+// The path is the name or ARN of the secret. A secret whose value is
+// a JSON object has one field per member; any other secret has a
+// single field "". Credentials and the region are found the usual way
+// (AWS_PROFILE, AWS_REGION, etc.) except that the region in an ARN
+// takes precedence.
+package awssm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+func init() {
+	credsfile.RegisterSecretBackend("awssm", &backend{})
+}
+
+type backend struct{}
+
+// client makes the requests to Secrets Manager. Without a timeout, an
+// unreachable endpoint would hang dnscontrol while it reads creds.json.
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Lookup implements credsfile.SecretBackend.
+func (b *backend) Lookup(path string) (map[string]string, error) {
+	ctx := context.Background()
+	awsCfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	region := awsCfg.Region
+	if strings.HasPrefix(path, "arn:") {
+		// arn:aws:secretsmanager:REGION:ACCOUNT:secret:NAME
+		if parts := strings.Split(path, ":"); len(parts) > 3 && parts[3] != "" {
+			region = parts[3]
+		}
+	}
+	if region == "" {
+		return nil, fmt.Errorf("no AWS region; set AWS_REGION or use the ARN of the secret")
+	}
+
+	creds, err := awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := json.Marshal(struct {
+		SecretID string `json:"SecretId"`
+	}{path})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", region), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	hash := sha256.Sum256(body)
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", region, time.Now()); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(respBody, &apiErr)
+		return nil, fmt.Errorf("GetSecretValue: %s: %s %s", resp.Status, apiErr.Type, apiErr.Message)
+	}
+
+	var out struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return nil, err
+	}
+	if out.SecretString == nil {
+		return nil, fmt.Errorf("binary secrets are not supported")
+	}
+	return parseSecretString(*out.SecretString), nil
+}
+
+// parseSecretString returns the members of a JSON object, or the
+// whole string as the field "" if it is not one.
+func parseSecretString(s string) map[string]string {
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &obj); err != nil {
+		return map[string]string{"": s}
+	}
+	fields := make(map[string]string, len(obj))
+	for k, v := range obj {
+		if str, ok := v.(string); ok {
+			fields[k] = str
+		} else {
+			fields[k] = fmt.Sprint(v)
+		}
+	}
+	return fields
+}
@@ -1,8 +1,14 @@
 // Package credsfile provides functions for reading and parsing the provider credentials json file.
 // It cleans nonstandard json features (comments and trailing commas), as well as replaces environment variable placeholders with
-// their environment variable equivalents. To reference an environment variable in your json file, simply use values in this format:
+// their environment variable equivalents. To reference an environment variable in your json file, simply use values in one of these formats:
 //
-//	"key"="$ENV_VAR_NAME"
+//	"key": "$ENV_VAR_NAME"
+//	"key": "$ENV:ENV_VAR_NAME"
+//	"key": {"env": "ENV_VAR_NAME"}
+//
+// The first format is replaced by the empty string if the variable is not set. The others are an error.
+//
+// Values may also refer to secrets kept in a secret store (see SecretBackend).
 package credsfile
 
 import (
@@ -38,7 +44,7 @@ func keysWithColons(list []string) []string {
 
 // LoadProviderConfigs will open or execute the specified file name, and parse its contents. It will replace environment variables it finds if any value matches $[A-Za-z_-0-9]+
 func LoadProviderConfigs(fname string) (map[string]map[string]string, error) {
-	var results = map[string]map[string]string{}
+	var raw = map[string]json.RawMessage{}
 
 	var dat []byte
 	var err error
@@ -60,12 +66,13 @@ func LoadProviderConfigs(fname string) (map[string]map[string]string, error) {
 
 	s := string(dat)
 	r := JsonConfigReader.New(strings.NewReader(s))
-	err = json.NewDecoder(r).Decode(&results)
+	err = json.NewDecoder(r).Decode(&raw)
 	if err != nil {
 		return nil, fmt.Errorf("failed parsing provider credentials file %v: %v", fname, err)
 	}
-	if err = replaceEnvVars(results); err != nil {
-		return nil, err
+	results, err := replaceEnvVars(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing provider credentials file %v: %v", fname, err)
 	}
 
 	// For backwards compatibility, insert NONE and BIND entries if
@@ -123,15 +130,80 @@ func fileExists(filename string) bool {
 	return !errors.Is(err, os.ErrNotExist)
 }
 
-func replaceEnvVars(m map[string]map[string]string) error {
-	for _, keys := range m {
+// envPrefix marks a value as a reference to an environment variable
+// that must be set.
+const envPrefix = "$ENV:"
+
+// replaceEnvVars converts the values found in the creds file to
+// strings, replacing references to environment variables and secret
+// backends with their values.
+func replaceEnvVars(raw map[string]json.RawMessage) (map[string]map[string]string, error) {
+	m := make(map[string]map[string]string, len(raw))
+	for name, entry := range raw {
+		// An entire entry may be a reference to a secret.
+		var ref string
+		if err := json.Unmarshal(entry, &ref); err == nil {
+			scheme, path, field, ok := splitSecretRef(ref)
+			if !ok {
+				return nil, fmt.Errorf("%q: entry must be an object or a reference to a secret, got %q", name, ref)
+			}
+			fields, err := resolveSecretEntry(scheme, path, field)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", name, err)
+			}
+			m[name] = fields
+			continue
+		}
+
+		var keys map[string]json.RawMessage
+		if err := json.Unmarshal(entry, &keys); err != nil {
+			return nil, fmt.Errorf("%q: %w", name, err)
+		}
+		m[name] = make(map[string]string, len(keys))
 		for k, v := range keys {
-			if strings.HasPrefix(v, "$") {
-				env := v[1:]
-				newVal := os.Getenv(env)
-				keys[k] = newVal
+			newVal, err := resolveValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %q: %w", name, k, err)
 			}
+			m[name][k] = newVal
+		}
+	}
+	return m, nil
+}
+
+// resolveValue returns the string value of v, which is either a JSON
+// string or an object of the form {"env": "NAME"}.
+func resolveValue(v json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(v, &s); err == nil {
+		switch {
+		case strings.HasPrefix(s, envPrefix):
+			return lookupEnv(s[len(envPrefix):])
+		case strings.HasPrefix(s, "$"):
+			return os.Getenv(s[1:]), nil
 		}
+		if scheme, path, field, ok := splitSecretRef(s); ok {
+			return resolveSecretValue(scheme, path, field)
+		}
+		return s, nil
+	}
+
+	var ref struct {
+		Env *string `json:"env"`
+	}
+	if err := json.Unmarshal(v, &ref); err != nil || ref.Env == nil {
+		return "", fmt.Errorf(`value must be a string or {"env": "VARIABLE"}, got %s`, v)
+	}
+	return lookupEnv(*ref.Env)
+}
+
+func lookupEnv(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("missing environment variable name")
+	}
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", name)
 	}
-	return nil
+	return val, nil
 }
@@ -1,7 +1,11 @@
 package credsfile
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -49,3 +53,75 @@ func Test_quotedList(t *testing.T) {
 		})
 	}
 }
+
+func Test_resolveValue(t *testing.T) {
+	t.Setenv("CREDSFILE_TEST_TOKEN", "tok")
+	t.Setenv("CREDSFILE_TEST_EMPTY", "")
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"plain", `"value"`, "value", false},
+		{"empty", `""`, "", false},
+		{"dollar", `"$CREDSFILE_TEST_TOKEN"`, "tok", false},
+		{"dollarMissing", `"$CREDSFILE_TEST_MISSING"`, "", false},
+		{"envPrefix", `"$ENV:CREDSFILE_TEST_TOKEN"`, "tok", false},
+		{"envPrefixEmpty", `"$ENV:CREDSFILE_TEST_EMPTY"`, "", false},
+		{"envPrefixMissing", `"$ENV:CREDSFILE_TEST_MISSING"`, "", true},
+		{"envPrefixNoName", `"$ENV:"`, "", true},
+		{"object", `{"env": "CREDSFILE_TEST_TOKEN"}`, "tok", false},
+		{"objectMissing", `{"env": "CREDSFILE_TEST_MISSING"}`, "", true},
+		{"objectNoEnv", `{"file": "x"}`, "", true},
+		{"number", `42`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveValue(json.RawMessage(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadProviderConfigs(t *testing.T) {
+	t.Setenv("CREDSFILE_TEST_TOKEN", "tok")
+
+	fname := filepath.Join(t.TempDir(), "creds.json")
+	err := os.WriteFile(fname, []byte(`{
+  "cloudflare": {
+    "TYPE": "CLOUDFLAREAPI",
+    "apitoken": {"env": "CREDSFILE_TEST_TOKEN"}, // comments are permitted
+    "accountid": "$ENV:CREDSFILE_TEST_TOKEN",
+  },
+}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadProviderConfigs(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"TYPE": "CLOUDFLAREAPI", "apitoken": "tok", "accountid": "tok"}
+	if !reflect.DeepEqual(got["cloudflare"], want) {
+		t.Errorf("cloudflare = %v, want %v", got["cloudflare"], want)
+	}
+	if got["none"]["TYPE"] != "NONE" {
+		t.Errorf("none entry missing: %v", got)
+	}
+
+	err = os.WriteFile(fname, []byte(`{"cf": {"apitoken": "$ENV:CREDSFILE_TEST_MISSING"}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProviderConfigs(fname); err == nil || !strings.Contains(err.Error(), "CREDSFILE_TEST_MISSING") {
+		t.Errorf("expected error naming the missing variable, got %v", err)
+	}
+}
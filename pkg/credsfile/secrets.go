@@ -0,0 +1,127 @@
+package credsfile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SecretBackend fetches secrets from an external secret store such as
+// HashiCorp Vault or AWS Secrets Manager.
+//
+// A value in creds.json that starts with "scheme://" (for a registered
+// scheme) is a reference to a secret:
+//
+//	"apitoken": "vault://secret/dnscontrol/cloudflare#apitoken"
+//
+// The part after "#" selects one field of the secret. An entire
+// creds.json entry may also be a reference, in which case all the
+// fields of the secret become the fields of the entry:
+//
+//	"r53": "awssm://dnscontrol/route53"
+type SecretBackend interface {
+	// Lookup returns the fields of the secret at path (the part of the
+	// reference between "scheme://" and "#"). A secret that is a
+	// plain string, rather than a set of fields, is returned as the
+	// field "".
+	Lookup(path string) (map[string]string, error)
+}
+
+var (
+	secretBackends   = map[string]SecretBackend{}
+	secretCache      = map[string]map[string]string{}
+	secretBackendsMu sync.Mutex
+)
+
+// RegisterSecretBackend makes a SecretBackend available for references
+// that start with scheme + "://".
+func RegisterSecretBackend(scheme string, b SecretBackend) {
+	secretBackendsMu.Lock()
+	defer secretBackendsMu.Unlock()
+	if _, ok := secretBackends[scheme]; ok {
+		panic(fmt.Sprintf("secret backend %q already registered", scheme))
+	}
+	secretBackends[scheme] = b
+}
+
+// splitSecretRef splits a reference into its scheme, path and field.
+// ok is false if s is not a reference to a registered backend.
+func splitSecretRef(s string) (scheme, path, field string, ok bool) {
+	scheme, rest, found := strings.Cut(s, "://")
+	if !found {
+		return "", "", "", false
+	}
+	secretBackendsMu.Lock()
+	_, ok = secretBackends[scheme]
+	secretBackendsMu.Unlock()
+	if !ok {
+		return "", "", "", false
+	}
+	path, field, _ = strings.Cut(rest, "#")
+	return scheme, path, field, true
+}
+
+// lookupSecret returns the fields of the secret. Each secret is only
+// fetched once per run, no matter how many values refer to it.
+func lookupSecret(scheme, path string) (map[string]string, error) {
+	secretBackendsMu.Lock()
+	defer secretBackendsMu.Unlock()
+
+	key := scheme + "://" + path
+	if fields, ok := secretCache[key]; ok {
+		return fields, nil
+	}
+	fields, err := secretBackends[scheme].Lookup(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	secretCache[key] = fields
+	return fields, nil
+}
+
+// resolveSecretValue returns the value of the field named in the
+// reference.
+func resolveSecretValue(scheme, path, field string) (string, error) {
+	fields, err := lookupSecret(scheme, path)
+	if err != nil {
+		return "", err
+	}
+	val, ok := fields[field]
+	if !ok {
+		if field == "" {
+			return "", fmt.Errorf("%s://%s: secret has several fields; add #field to select one (%s)", scheme, path, fieldNames(fields))
+		}
+		return "", fmt.Errorf("%s://%s: secret has no field %q (%s)", scheme, path, field, fieldNames(fields))
+	}
+	return val, nil
+}
+
+// resolveSecretEntry returns the fields of the secret, for use as an
+// entire creds.json entry.
+func resolveSecretEntry(scheme, path, field string) (map[string]string, error) {
+	if field != "" {
+		return nil, fmt.Errorf("%s://%s#%s: an entry must refer to a whole secret, not a field", scheme, path, field)
+	}
+	fields, err := lookupSecret(scheme, path)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fields[""]; ok {
+		return nil, fmt.Errorf("%s://%s: secret must be a set of fields to be used as an entry", scheme, path)
+	}
+	m := make(map[string]string, len(fields))
+	for k, v := range fields {
+		m[k] = v
+	}
+	return m, nil
+}
+
+func fieldNames(fields map[string]string) string {
+	var names []string
+	for k := range fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return "fields: " + quotedList(names)
+}
@@ -0,0 +1,97 @@
+package credsfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type fakeBackend struct {
+	secrets map[string]map[string]string
+	calls   map[string]int
+}
+
+func (f *fakeBackend) Lookup(path string) (map[string]string, error) {
+	f.calls[path]++
+	s, ok := f.secrets[path]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return s, nil
+}
+
+var fake = &fakeBackend{
+	secrets: map[string]map[string]string{
+		"dns/cloudflare": {"apitoken": "cf-token", "accountid": "cf-account"},
+		"dns/route53":    {"TYPE": "ROUTE53", "KeyId": "key", "SecretKey": "secret"},
+		"dns/plain":      {"": "plain-value"},
+	},
+	calls: map[string]int{},
+}
+
+func init() {
+	RegisterSecretBackend("fake", fake)
+}
+
+func TestSecretBackends(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "creds.json")
+	err := os.WriteFile(fname, []byte(`{
+  "cloudflare": {
+    "TYPE": "CLOUDFLAREAPI",
+    "apitoken": "fake://dns/cloudflare#apitoken",
+    "accountid": "fake://dns/cloudflare#accountid",
+    "other": "plain://not/a/backend"
+  },
+  "r53": "fake://dns/route53",
+  "plain": {"value": "fake://dns/plain"}
+}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadProviderConfigs(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]string{
+		"cloudflare": {"TYPE": "CLOUDFLAREAPI", "apitoken": "cf-token", "accountid": "cf-account", "other": "plain://not/a/backend"},
+		"r53":        {"TYPE": "ROUTE53", "KeyId": "key", "SecretKey": "secret"},
+		"plain":      {"value": "plain-value"},
+	}
+	for name, w := range want {
+		if !reflect.DeepEqual(got[name], w) {
+			t.Errorf("%s = %v, want %v", name, got[name], w)
+		}
+	}
+	if n := fake.calls["dns/cloudflare"]; n != 1 {
+		t.Errorf("secret fetched %d times, want 1", n)
+	}
+}
+
+func TestSecretBackendsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		creds string
+	}{
+		{"missingSecret", `{"x": {"a": "fake://dns/nope#a"}}`},
+		{"missingField", `{"x": {"a": "fake://dns/cloudflare#nope"}}`},
+		{"noFieldSelected", `{"x": {"a": "fake://dns/cloudflare"}}`},
+		{"entryWithField", `{"x": "fake://dns/route53#KeyId"}`},
+		{"entryPlain", `{"x": "fake://dns/plain"}`},
+		{"entryNotRef", `{"x": "hello"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fname := filepath.Join(t.TempDir(), "creds.json")
+			if err := os.WriteFile(fname, []byte(tt.creds), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadProviderConfigs(fname); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
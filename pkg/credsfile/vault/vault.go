@@ -0,0 +1,64 @@
+// Package vault lets creds.json refer to secrets kept in HashiCorp
+// Vault:
+//
+//	"apitoken": "vault://secret/dnscontrol/cloudflare#apitoken"
+//
+// The first element of the path is the mount point of a KV secrets
+// engine. Version 2 of the KV engine is tried first, then version 1.
+// The client is configured from the usual VAULT_ADDR, VAULT_TOKEN,
+// etc. environment variables.
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/credsfile"
+	"github.com/hashicorp/vault/api"
+)
+
+func init() {
+	credsfile.RegisterSecretBackend("vault", &backend{})
+}
+
+type backend struct {
+	once   sync.Once
+	client *api.Client
+	err    error
+}
+
+// Lookup implements credsfile.SecretBackend.
+func (b *backend) Lookup(path string) (map[string]string, error) {
+	b.once.Do(func() {
+		b.client, b.err = api.NewClient(api.DefaultConfig())
+	})
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	mount, secretPath, ok := strings.Cut(strings.Trim(path, "/"), "/")
+	if !ok || secretPath == "" {
+		return nil, fmt.Errorf("path must be <mount>/<secret>")
+	}
+
+	var data map[string]interface{}
+	if s, err := b.client.KVv2(mount).Get(context.Background(), secretPath); err == nil {
+		data = s.Data
+	} else if s, err1 := b.client.Logical().Read(mount + "/" + secretPath); err1 == nil && s != nil {
+		data = s.Data
+	} else {
+		return nil, err
+	}
+
+	fields := make(map[string]string, len(data))
+	for k, v := range data {
+		if s, ok := v.(string); ok {
+			fields[k] = s
+		} else {
+			fields[k] = fmt.Sprint(v)
+		}
+	}
+	return fields, nil
+}
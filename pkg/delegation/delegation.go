@@ -0,0 +1,140 @@
+// Package delegation checks that a domain is delegated to the
+// nameservers that its DNS providers serve.
+//
+// The NS records at the apex of a zone (what the DNS providers serve)
+// and the NS records in the parent zone (the delegation, which is set at
+// the registrar) must list the same nameservers. When they don't,
+// resolution works or fails depending on which nameserver a resolver
+// asks, which is usually only noticed when it fails.
+package delegation
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/miekg/dns"
+)
+
+// LookupFunc returns the nameservers that the parent zone delegates
+// domain to. It returns an empty list if domain isn't delegated.
+type LookupFunc func(domain string) ([]string, error)
+
+// Check compares the NS records at the apex of dc.Records with the
+// delegation returned by lookup and returns a warning for each
+// mismatch. dc.Records must include the NS records of the DNS providers
+// (see nameservers.AddNSRecords).
+func Check(dc *models.DomainConfig, lookup LookupFunc) ([]string, error) {
+	served := map[string]bool{}
+	for _, rc := range dc.Records {
+		if rc.Type == "NS" && rc.GetLabel() == "@" {
+			served[canonical(rc.GetTargetField())] = true
+		}
+	}
+	if len(served) == 0 {
+		return nil, nil
+	}
+
+	found, err := lookup(dc.Name)
+	if err != nil {
+		return nil, fmt.Errorf("checking the delegation of %s: %w", dc.Name, err)
+	}
+	if len(found) == 0 {
+		return []string{fmt.Sprintf("%s is not delegated; the parent zone has no NS records for it", dc.Name)}, nil
+	}
+	delegated := map[string]bool{}
+	for _, ns := range found {
+		delegated[canonical(ns)] = true
+	}
+
+	var warnings []string
+	for _, ns := range sortedKeys(delegated) {
+		if !served[ns] {
+			warnings = append(warnings, fmt.Sprintf("%s is delegated to %s, which is not in the NS records of the DNS providers", dc.Name, ns))
+		}
+	}
+	for _, ns := range sortedKeys(served) {
+		if !delegated[ns] {
+			warnings = append(warnings, fmt.Sprintf("%s has an NS record for %s, which is not in the delegation at the registrar", dc.Name, ns))
+		}
+	}
+	return warnings, nil
+}
+
+func canonical(ns string) string {
+	return strings.ToLower(strings.TrimSuffix(ns, "."))
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dnsTimeout is the timeout of each query to a nameserver of the parent
+// zone.
+const dnsTimeout = 5 * time.Second
+
+// Lookup is a LookupFunc that asks the nameservers of the parent zone,
+// without recursion, for the delegation of domain.
+func Lookup(domain string) ([]string, error) {
+	servers, err := parentServers(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(domain), dns.TypeNS)
+	msg.RecursionDesired = false
+	client := &dns.Client{Timeout: dnsTimeout}
+	var lastErr error
+	for _, server := range servers {
+		resp, _, err := client.Exchange(msg, net.JoinHostPort(server, "53"))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.Rcode == dns.RcodeNameError {
+			return nil, nil
+		}
+		if resp.Rcode != dns.RcodeSuccess {
+			lastErr = fmt.Errorf("%s answered %s", server, dns.RcodeToString[resp.Rcode])
+			continue
+		}
+		// The delegation is in the authority section of a referral, or in
+		// the answer if the server also serves domain.
+		var ns []string
+		for _, rr := range append(resp.Answer, resp.Ns...) {
+			if rr, ok := rr.(*dns.NS); ok && strings.EqualFold(rr.Hdr.Name, dns.Fqdn(domain)) {
+				ns = append(ns, rr.Ns)
+			}
+		}
+		return ns, nil
+	}
+	return nil, fmt.Errorf("no nameserver of the parent zone of %s answered: %w", domain, lastErr)
+}
+
+// parentServers returns the nameservers of the closest enclosing zone
+// of domain.
+func parentServers(domain string) ([]string, error) {
+	labels := dns.SplitDomainName(domain)
+	for i := 1; i < len(labels); i++ {
+		parent := strings.Join(labels[i:], ".")
+		nss, err := net.LookupNS(parent)
+		if err != nil || len(nss) == 0 {
+			continue
+		}
+		var servers []string
+		for _, ns := range nss {
+			servers = append(servers, strings.TrimSuffix(ns.Host, "."))
+		}
+		return servers, nil
+	}
+	return nil, fmt.Errorf("could not find the nameservers of the parent zone of %s", domain)
+}
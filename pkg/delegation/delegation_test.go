@@ -0,0 +1,73 @@
+package delegation
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func makeNS(targets ...string) *models.DomainConfig {
+	dc := &models.DomainConfig{Name: "example.com"}
+	for _, t := range targets {
+		rc := &models.RecordConfig{Type: "NS"}
+		rc.SetLabel("@", dc.Name)
+		rc.SetTarget(t)
+		dc.Records = append(dc.Records, rc)
+	}
+	return dc
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		served    []string
+		delegated []string
+		want      []string
+	}{
+		{
+			name:      "match",
+			served:    []string{"ns1.example.net.", "ns2.example.net."},
+			delegated: []string{"NS2.example.net.", "ns1.example.net"},
+		},
+		{
+			name:      "mismatch",
+			served:    []string{"ns1.example.net.", "ns2.example.net."},
+			delegated: []string{"ns1.example.net.", "ns3.example.net."},
+			want: []string{
+				"example.com is delegated to ns3.example.net, which is not in the NS records of the DNS providers",
+				"example.com has an NS record for ns2.example.net, which is not in the delegation at the registrar",
+			},
+		},
+		{
+			name:   "not delegated",
+			served: []string{"ns1.example.net."},
+			want:   []string{"example.com is not delegated; the parent zone has no NS records for it"},
+		},
+		{
+			name:      "no NS records",
+			delegated: []string{"ns1.example.net."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Check(makeNS(tt.served...), func(domain string) ([]string, error) {
+				return tt.delegated, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	_, err := Check(makeNS("ns1.example.net."), func(domain string) ([]string, error) {
+		return nil, fmt.Errorf("timeout")
+	})
+	if err == nil {
+		t.Error("expected an error, got none")
+	}
+}
@@ -6,10 +6,14 @@ import (
 	"sort"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ownership"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
 	"github.com/gobwas/glob"
 )
 
+var logger = printer.Module("pkg.diff")
+
 // Correlation stores a difference between two records.
 type Correlation struct {
 	d        *differ
@@ -104,15 +108,29 @@ func (d *differ) IncrementalDiff(existing []*models.RecordConfig) (unchanged, cr
 
 	//fmt.Printf("********** DEBUG: existing list %+v\n", existing)
 
+	// Records that MANAGED_BY() leaves alone.
+	foreign, err := ownership.Foreign(existing, d.dc)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	isForeign := make(map[*models.RecordConfig]bool, len(foreign))
+	for _, rc := range foreign {
+		isForeign[rc] = true
+	}
+
 	// Gather the existing records. Skip over any that should be ignored.
 	for _, e := range existing {
 		//fmt.Printf("********** DEBUG: existing %v %v %v\n", e.GetLabel(), e.Type, e.GetTargetCombined())
 		if d.matchIgnoredName(e.GetLabel(), e.Type) {
 			//fmt.Printf("Ignoring record %s %s due to IGNORE_NAME\n", e.GetLabel(), e.Type)
-			printer.Debugf("Ignoring record %s %s due to IGNORE_NAME\n", e.GetLabel(), e.Type)
+			logger.Debugf("Ignoring record %s %s due to IGNORE_NAME\n", e.GetLabel(), e.Type)
 		} else if d.matchIgnoredTarget(e.GetTargetField(), e.Type) {
 			//fmt.Printf("Ignoring record %s %s due to IGNORE_TARGET\n", e.GetLabel(), e.Type)
-			printer.Debugf("Ignoring record %s %s due to IGNORE_TARGET\n", e.GetLabel(), e.Type)
+			logger.Debugf("Ignoring record %s %s due to IGNORE_TARGET\n", e.GetLabel(), e.Type)
+		} else if d.matchUnmanaged(e) {
+			logger.Debugf("Ignoring record %s %s due to IGNORE\n", e.GetLabel(), e.Type)
+		} else if isForeign[e] {
+			logger.Debugf("Ignoring record %s %s due to MANAGED_BY\n", e.GetLabel(), e.Type)
 		} else {
 			k := e.Key()
 			existingByNameAndType[k] = append(existingByNameAndType[k], e)
@@ -132,18 +150,21 @@ func (d *differ) IncrementalDiff(existing []*models.RecordConfig) (unchanged, cr
 			}
 		} else if d.matchIgnoredTarget(dr.GetTargetField(), dr.Type) {
 			return nil, nil, nil, nil, fmt.Errorf("trying to update/add IGNORE_TARGETd record: %s %s", dr.GetLabel(), dr.Type)
+		} else if d.matchUnmanaged(dr) && !d.dc.UnmanagedUnsafe {
+			if (!ignoreNameException(dr)) && (!apexException(dr)) {
+				return nil, nil, nil, nil, fmt.Errorf("trying to update/add IGNOREd record: %s %s", dr.GetLabel(), dr.Type)
+			}
 		} else {
 			k := dr.Key()
 			desiredByNameAndType[k] = append(desiredByNameAndType[k], dr)
 		}
 	}
 	// if NO_PURGE is set, just remove anything that is only in existing.
-	if d.dc.KeepUnknown {
-		for k := range existingByNameAndType {
-			if _, ok := desiredByNameAndType[k]; !ok {
-				printer.Debugf("Ignoring record set %s %s due to NO_PURGE\n", k.Type, k.NameFQDN)
-				delete(existingByNameAndType, k)
-			}
+	// PURGE_ONLY() and KEEP() do the same for some types.
+	for k, recs := range existingByNameAndType {
+		if _, ok := desiredByNameAndType[k]; !ok && !d.dc.Purgeable(recs[0].Type) {
+			logger.Debugf("Ignoring record set %s %s due to NO_PURGE, PURGE_ONLY or KEEP\n", k.Type, k.NameFQDN)
+			delete(existingByNameAndType, k)
 		}
 	}
 	// Look through existing records. This will give us changes and deletions and some additions.
@@ -285,6 +306,99 @@ func ChangesetLess(c Changeset, i, j int) bool {
 	// elements, and sort on the result.
 }
 
+// Order returns the changes in the order in which to make them: the
+// deletions, then the creations, then the modifications, each sorted by
+// label, type and content, except that a change is moved after the
+// changes it depends on (see diff2.DefaultDependencies). For example,
+// the DS records of a label are deleted before its NS records.
+func Order(create, toDelete, modify Changeset) Changeset {
+	var changes Changeset
+	var steps []diff2.Step
+	for _, cs := range []Changeset{toDelete, create, modify} {
+		cs = append(Changeset(nil), cs...)
+		sort.SliceStable(cs, func(i, j int) bool {
+			a, b := cs[i].record(), cs[j].record()
+			if a.NameFQDN != b.NameFQDN {
+				return a.NameFQDN < b.NameFQDN
+			}
+			if a.Type != b.Type {
+				return a.Type < b.Type
+			}
+			return cs[i].String() < cs[j].String()
+		})
+		for _, c := range cs {
+			changes = append(changes, c)
+			steps = append(steps, c.step())
+		}
+	}
+
+	ordered := make(Changeset, 0, len(changes))
+	for _, i := range diff2.Order(steps, diff2.DefaultDependencies) {
+		ordered = append(ordered, changes[i])
+	}
+	return ordered
+}
+
+// record returns the desired record, or the existing record of a
+// deletion.
+func (c Correlation) record() *models.RecordConfig {
+	if c.Desired != nil {
+		return c.Desired
+	}
+	return c.Existing
+}
+
+func (c Correlation) step() diff2.Step {
+	verb := diff2.CHANGE
+	if c.Existing == nil {
+		verb = diff2.CREATE
+	} else if c.Desired == nil {
+		verb = diff2.DELETE
+	}
+	return diff2.Step{Verb: verb, Label: c.record().NameFQDN, Type: c.record().Type}
+}
+
+// Kind returns what making the change does, for the Kind of the
+// correction that makes it.
+func (c Correlation) Kind() models.CorrectionKind {
+	if c.Existing == nil {
+		return models.CorrectionCreate
+	} else if c.Desired == nil {
+		return models.CorrectionDestroy
+	}
+	return models.CorrectionModify
+}
+
+// Kind returns the most destructive Kind of the changes, or 0 if
+// there are none.
+func (c Changeset) Kind() models.CorrectionKind {
+	var kind models.CorrectionKind
+	for _, cor := range c {
+		kind = kind.Max(cor.Kind())
+	}
+	return kind
+}
+
+// Kind returns the Kind of a correction that makes all the changes,
+// or 0 if there are none.
+func Kind(create, toDelete, modify Changeset) models.CorrectionKind {
+	return create.Kind().Max(toDelete.Kind()).Max(modify.Kind())
+}
+
+// ReplaceKind returns the Kind of a correction that replaces the
+// records existing with desired, such as the records of a recordset.
+// Replacing records with fewer records removes one, so it is a
+// CorrectionDestroy.
+func ReplaceKind(existing, desired models.Records) models.CorrectionKind {
+	switch {
+	case len(existing) == 0:
+		return models.CorrectionCreate
+	case len(desired) < len(existing):
+		return models.CorrectionDestroy
+	}
+	return models.CorrectionModify
+}
+
 // CorrectionLess returns true when comparing corrections.
 func CorrectionLess(c []*models.Correction, i, j int) bool {
 	return c[i].Msg < c[j].Msg
@@ -425,3 +539,13 @@ func (d *differ) matchIgnoredTarget(target string, rType string) bool {
 
 	return false
 }
+
+// matchUnmanaged returns true if rec is matched by an IGNORE() rule.
+func (d *differ) matchUnmanaged(rec *models.RecordConfig) bool {
+	for _, uc := range d.dc.Unmanaged {
+		if uc.Match(rec) {
+			return true
+		}
+	}
+	return false
+}
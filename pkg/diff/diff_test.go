@@ -204,6 +204,70 @@ func TestNoPurge(t *testing.T) {
 	checkLengthsWithKeepUnknown(t, existing, desired, 1, 0, 1, 0, true)
 }
 
+func TestManagedBy(t *testing.T) {
+	tag := func(label, rtype string) *models.RecordConfig {
+		r := &models.RecordConfig{Type: "TXT", TTL: 1, Metadata: map[string]string{}}
+		r.SetLabel("_dnscontrol-owner."+label, "example.com")
+		r.SetTargetTXT("dnscontrol-owner=prod type=" + rtype)
+		return r
+	}
+	existing := []*models.RecordConfig{
+		myRecord("www A 1 1.1.1.1"),
+		myRecord("old A 1 2.2.2.2"),
+		myRecord("manual A 1 3.3.3.3"),
+		tag("www", "A"),
+		tag("old", "A"),
+	}
+	desired := []*models.RecordConfig{
+		myRecord("www A 1 1.2.3.4"),
+		tag("www", "A"),
+	}
+	dc := &models.DomainConfig{Name: "example.com", Records: desired, ManagedBy: "prod"}
+	_, _, del, mod, err := New(dc).IncrementalDiff(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "old" and its tag are deleted, "manual" is left alone.
+	if len(del) != 2 || len(mod) != 1 {
+		t.Errorf("got %d deletions and %d modifications, want 2 and 1", len(del), len(mod))
+	}
+	for _, c := range del {
+		if c.Existing.GetLabel() == "manual" {
+			t.Errorf("deleted a record that isn't MANAGED_BY the owner")
+		}
+	}
+}
+
+func TestPurgeOnlyKeep(t *testing.T) {
+	existing := []*models.RecordConfig{
+		myRecord("www A 1 1.1.1.1"),
+		myRecord("old A 1 2.2.2.2"),
+		myRecord("old TXT 1 foo"),
+		myRecord("@ MX 1 1.1.1.1"),
+	}
+	desired := []*models.RecordConfig{
+		myRecord("www A 1 1.2.3.4"),
+	}
+	for _, tst := range []struct {
+		purgeOnly, keep []string
+		del             int
+	}{
+		{nil, nil, 3},
+		{[]string{"A"}, nil, 1},
+		{[]string{"A", "TXT"}, []string{"TXT"}, 1},
+		{nil, []string{"MX"}, 2},
+	} {
+		dc := &models.DomainConfig{Name: "example.com", Records: desired, PurgeOnly: tst.purgeOnly, Keep: tst.keep}
+		_, _, del, mod, err := New(dc).IncrementalDiff(existing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(del) != tst.del || len(mod) != 1 {
+			t.Errorf("PURGE_ONLY(%v) KEEP(%v): got %d deletions and %d modifications, want %d and 1", tst.purgeOnly, tst.keep, len(del), len(mod), tst.del)
+		}
+	}
+}
+
 func TestIgnoredRecords(t *testing.T) {
 	existing := []*models.RecordConfig{
 		myRecord("www1 A 1 1.1.1.1"),
@@ -372,3 +436,67 @@ func TestCaas(t *testing.T) {
 
 	checkLengthsFull(t, existing, desired, 3, 0, 0, 0, false, nil, nil)
 }
+
+func TestOrder(t *testing.T) {
+	existing := []*models.RecordConfig{
+		myRecord("child NS 300 ns1.example.net."),
+		myRecord("child DS 300 1"),
+		myRecord("www CNAME 300 foo.example.net."),
+		myRecord("mail A 300 1.2.3.4"),
+	}
+	desired := []*models.RecordConfig{
+		myRecord("www A 300 1.2.3.4"),
+		myRecord("other DS 300 1"),
+		myRecord("other NS 300 ns1.example.net."),
+		myRecord("mail A 600 1.2.3.4"),
+	}
+	_, cre, del, mod := checkLengths(t, existing, desired, 0, 3, 3, 1)
+
+	var got []string
+	for _, c := range Order(cre, del, mod) {
+		got = append(got, c.step().Verb.String()+" "+c.record().GetLabel()+" "+c.record().Type)
+	}
+	want := "DELETE child DS,DELETE child NS,DELETE www CNAME,CREATE other NS,CREATE other DS,CREATE www A,CHANGE mail A"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestKind(t *testing.T) {
+	existing := []*models.RecordConfig{
+		myRecord("www A 300 1.2.3.4"),
+		myRecord("mail A 300 1.2.3.4"),
+	}
+	desired := []*models.RecordConfig{
+		myRecord("www A 600 1.2.3.4"),
+		myRecord("new A 300 1.2.3.4"),
+	}
+	_, cre, del, mod := checkLengths(t, existing, desired, 0, 1, 1, 1)
+	if cre.Kind() != models.CorrectionCreate || del.Kind() != models.CorrectionDestroy || mod.Kind() != models.CorrectionModify {
+		t.Errorf("got kinds %v, %v, %v", cre.Kind(), del.Kind(), mod.Kind())
+	}
+	if k := Kind(cre, nil, mod); k != models.CorrectionModify {
+		t.Errorf("Kind(create, nil, modify) = %v", k)
+	}
+	if k := Kind(cre, del, mod); k != models.CorrectionDestroy {
+		t.Errorf("Kind(create, delete, modify) = %v", k)
+	}
+	if k := Kind(nil, nil, nil); k != 0 {
+		t.Errorf("Kind() = %v, want 0", k)
+	}
+
+	for _, tc := range []struct {
+		existing, desired int
+		want              models.CorrectionKind
+	}{
+		{0, 1, models.CorrectionCreate},
+		{1, 1, models.CorrectionModify},
+		{1, 2, models.CorrectionModify},
+		{2, 1, models.CorrectionDestroy},
+		{1, 0, models.CorrectionDestroy},
+	} {
+		if k := ReplaceKind(make(models.Records, tc.existing), make(models.Records, tc.desired)); k != tc.want {
+			t.Errorf("ReplaceKind(%d records, %d records) = %v, want %v", tc.existing, tc.desired, k, tc.want)
+		}
+	}
+}
@@ -17,7 +17,8 @@ func analyzeByRecordSet(cc *CompareConfig) ChangeList {
 			// ...if there are changes generate an instruction.
 			ets := rt.existingTargets
 			dts := rt.desiredTargets
-			msgs := genmsgs(ets, dts)
+			cl := diffTargets(ets, dts)
+			msgs := justMsgs(cl)
 			if len(msgs) == 0 { // No differences?
 				//fmt.Printf("DEBUG: done. Records are the same\n")
 				// The records at this rset are the same. No work to be done.
@@ -31,7 +32,9 @@ func analyzeByRecordSet(cc *CompareConfig) ChangeList {
 				instructions = append(instructions, mkDelete(lc.label, rt.rType, rt.existingRecs, msgs))
 			} else { // Change the records at that label
 				//fmt.Printf("DEBUG: change\n")
-				instructions = append(instructions, mkChange(lc.label, rt.rType, msgs, rt.existingRecs, rt.desiredRecs))
+				c := mkChange(lc.label, rt.rType, msgs, rt.existingRecs, rt.desiredRecs)
+				c.kind = cl.Kind()
+				instructions = append(instructions, c)
 			}
 		}
 	}
@@ -46,6 +49,7 @@ func analyzeByLabel(cc *CompareConfig) ChangeList {
 		//fmt.Printf("DEBUG: START LABEL = %q\n", lc.label)
 		label := lc.label
 		var accMsgs []string
+		var accKind models.CorrectionKind
 		var accExisting models.Records
 		var accDesired models.Records
 		msgsByKey := map[models.RecordKey][]string{}
@@ -53,8 +57,10 @@ func analyzeByLabel(cc *CompareConfig) ChangeList {
 			//fmt.Printf("DEBUG: START RTYPE = %q\n", rt.rType)
 			ets := rt.existingTargets
 			dts := rt.desiredTargets
-			msgs := genmsgs(ets, dts)
+			cl := diffTargets(ets, dts)
+			msgs := justMsgs(cl)
 			msgsByKey[models.RecordKey{NameFQDN: label, Type: rt.rType}] = msgs
+			accKind = accKind.Max(cl.Kind())
 			//fmt.Printf("DEBUG:    appending msgs=%v\n", msgs)
 			accMsgs = append(accMsgs, msgs...)                    // Accumulate the messages
 			accExisting = append(accExisting, rt.existingRecs...) // Accumulate records existing at this label.
@@ -83,7 +89,9 @@ func analyzeByLabel(cc *CompareConfig) ChangeList {
 			// 	accMsgs,
 			// )
 			//fmt.Printf("DEBUG: analyzeByLabel mkchange msgs=%d\n", len(accMsgs))
-			instructions = append(instructions, mkChangeLabel(label, "", accMsgs, accExisting, accDesired, msgsByKey))
+			c := mkChangeLabel(label, "", accMsgs, accExisting, accDesired, msgsByKey)
+			c.kind = accKind
+			instructions = append(instructions, c)
 		}
 	}
 
@@ -353,11 +361,6 @@ func diffTargets(existing, desired []targetConfig) ChangeList {
 	return instructions
 }
 
-func genmsgs(existing, desired []targetConfig) []string {
-	cl := diffTargets(existing, desired)
-	return justMsgs(cl)
-}
-
 func justMsgs(cl ChangeList) []string {
 	var msgs []string
 	for _, c := range cl {
@@ -0,0 +1,194 @@
+package diff2
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// This file renders corrections in the "compact" report mode
+// (--diffmode compact): the changes are grouped by label, marked with
+// +, - and ~ (optionally in color), and changes that only touch the
+// TTL are collapsed into one summary line.
+//
+// Corrections only carry their messages, so the messages are parsed.
+// Both the diff2 format ("CREATE www.example.com A 1.2.3.4 ttl=300")
+// and the old diff format ("CREATE A www.example.com 1.2.3.4 ttl=300")
+// are understood. Anything else is listed verbatim at the end.
+
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+)
+
+// compactLine is one line of a correction message.
+type compactLine struct {
+	mark  string // "+", "-" or "~"
+	label string
+	rtype string
+	rest  string // The target(s), or how they change
+}
+
+var (
+	// "1.2.3.4 (ttl 300->3600)", as produced by humanDiff.
+	ttlOnlyDiff2 = regexp.MustCompile(`^(.*) \(ttl (\d+)->(\d+)\)$`)
+	// "(1.2.3.4 ttl=300) -> (1.2.3.4 ttl=3600)", as produced by pkg/diff.
+	ttlOnlyDiff = regexp.MustCompile(`^\((.*) ttl=(\d+)(.*)\) -> \((.*) ttl=(\d+)(.*)\)$`)
+)
+
+// CompactReport renders corrections for humans: grouped by label, with
+// +/-/~ markers (in ANSI colors if color is true), and with the
+// changes that only touch the TTL collapsed into one line.
+func CompactReport(corrections []*models.Correction, color bool) string {
+	paint := func(c, s string) string {
+		if !color {
+			return s
+		}
+		return c + s + colorReset
+	}
+
+	byLabel := map[string][]compactLine{}
+	var labels, others []string
+	ttlOnly := map[string]int{} // "300 -> 3600" -> count
+	var creates, changes, deletes, ttlChanges int
+	for _, c := range corrections {
+		for _, text := range strings.Split(c.Msg, "\n") {
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			l, ok := parseCompactLine(text)
+			if !ok {
+				others = append(others, strings.TrimSpace(text))
+				continue
+			}
+			if l.mark == "~" {
+				if from, to, ok := ttlOnlyChange(l.rest); ok {
+					ttlOnly[from+" -> "+to]++
+					ttlChanges++
+					continue
+				}
+			}
+			switch l.mark {
+			case "+":
+				creates++
+			case "-":
+				deletes++
+			default:
+				changes++
+			}
+			if _, ok := byLabel[l.label]; !ok {
+				labels = append(labels, l.label)
+			}
+			byLabel[l.label] = append(byLabel[l.label], l)
+		}
+	}
+	sort.Strings(labels)
+
+	b := &strings.Builder{}
+	for _, label := range labels {
+		lines := byLabel[label]
+		width := 0
+		for _, l := range lines {
+			if len(l.rtype) > width {
+				width = len(l.rtype)
+			}
+		}
+		fmt.Fprintln(b, paint(colorBold, label))
+		for _, l := range lines {
+			c := colorYellow
+			switch l.mark {
+			case "+":
+				c = colorGreen
+			case "-":
+				c = colorRed
+			}
+			fmt.Fprintf(b, "  %s\n", paint(c, fmt.Sprintf("%s %-*s %s", l.mark, width, l.rtype, l.rest)))
+		}
+	}
+	if len(ttlOnly) > 0 {
+		var ttls []string
+		for k, v := range ttlOnly {
+			ttls = append(ttls, fmt.Sprintf("%s (%d)", k, v))
+		}
+		sort.Strings(ttls)
+		fmt.Fprintln(b, paint(colorYellow, fmt.Sprintf("~ %d TTL-only change%s: ttl %s", ttlChanges, plural(ttlChanges), strings.Join(ttls, ", "))))
+	}
+	for _, o := range others {
+		fmt.Fprintln(b, o)
+	}
+	fmt.Fprintf(b, "%s, %s, %s\n",
+		paint(colorGreen, fmt.Sprintf("%d to create", creates)),
+		paint(colorYellow, fmt.Sprintf("%d to change", changes+ttlChanges)),
+		paint(colorRed, fmt.Sprintf("%d to delete", deletes)),
+	)
+	return b.String()
+}
+
+// parseCompactLine parses a line of a correction message. ok is false
+// if the line isn't a record change.
+func parseCompactLine(text string) (l compactLine, ok bool) {
+	text = strings.TrimLeft(text, "+-±~ ")
+	fields := strings.SplitN(text, " ", 4)
+	if len(fields) < 3 {
+		return l, false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "CREATE":
+		l.mark = "+"
+	case "DELETE":
+		l.mark = "-"
+	case "CHANGE", "MODIFY":
+		l.mark = "~"
+	default:
+		return l, false
+	}
+	a, b := fields[1], strings.TrimSuffix(fields[2], ":")
+	if isRType(a) && !isRType(b) {
+		// The old diff puts the type first.
+		a, b = b, a
+	}
+	l.label, l.rtype = a, b
+	if len(fields) == 4 {
+		l.rest = fields[3]
+	}
+	return l, true
+}
+
+// isRType reports whether s looks like a record type (A, MX,
+// R53_ALIAS, ...) rather than a name.
+func isRType(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// ttlOnlyChange returns the old and new TTL if rest describes a change
+// that only touches the TTL.
+func ttlOnlyChange(rest string) (from, to string, ok bool) {
+	if m := ttlOnlyDiff2.FindStringSubmatch(rest); m != nil && !strings.Contains(m[1], ") -> (") {
+		return m[2], m[3], true
+	}
+	if m := ttlOnlyDiff.FindStringSubmatch(rest); m != nil && m[1] == m[4] && m[3] == m[6] {
+		return m[2], m[5], true
+	}
+	return "", "", false
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
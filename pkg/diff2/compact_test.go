@@ -0,0 +1,61 @@
+package diff2
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestCompactReport(t *testing.T) {
+	corrections := []*models.Correction{
+		{Msg: "CREATE www.example.com A 1.2.3.4"},
+		{Msg: "CHANGE mail.example.com MX (10 mx1.example.com.) -> (20 mx1.example.com.)\nDELETE mail.example.com A 5.6.7.8"},
+		{Msg: "CHANGE www.example.com AAAA ::1 (ttl 300->3600)"},
+		{Msg: "CHANGE example.com TXT \"v=spf1 -all\" (ttl 300->3600)"},
+		{Msg: "MODIFY A old.example.com: (1.1.1.1 ttl=60) -> (1.1.1.1 ttl=300)"},
+		{Msg: "MODIFY A old.example.com: (1.1.1.1 ttl=60) -> (2.2.2.2 ttl=300)"},
+		{Msg: "Update nameservers a -> b"},
+	}
+	want := `mail.example.com
+  ~ MX (10 mx1.example.com.) -> (20 mx1.example.com.)
+  - A  5.6.7.8
+old.example.com
+  ~ A (1.1.1.1 ttl=60) -> (2.2.2.2 ttl=300)
+www.example.com
+  + A 1.2.3.4
+~ 3 TTL-only changes: ttl 300 -> 3600 (2), 60 -> 300 (1)
+Update nameservers a -> b
+1 to create, 5 to change, 1 to delete
+`
+	if got := CompactReport(corrections, false); got != want {
+		t.Errorf("CompactReport() =\n%s\nwant\n%s", got, want)
+	}
+
+	got := CompactReport(corrections[:1], true)
+	want = "\x1b[1mwww.example.com\x1b[0m\n  \x1b[32m+ A 1.2.3.4\x1b[0m\n" +
+		"\x1b[32m1 to create\x1b[0m, \x1b[33m0 to change\x1b[0m, \x1b[31m0 to delete\x1b[0m\n"
+	if got != want {
+		t.Errorf("CompactReport(color) = %q, want %q", got, want)
+	}
+}
+
+func Test_parseCompactLine(t *testing.T) {
+	tests := []struct {
+		text string
+		want compactLine
+		ok   bool
+	}{
+		{"CREATE www.example.com A 1.2.3.4", compactLine{"+", "www.example.com", "A", "1.2.3.4"}, true},
+		{"+ CREATE www.example.com A 1.2.3.4", compactLine{"+", "www.example.com", "A", "1.2.3.4"}, true},
+		{"DELETE TXT example.com \"x\" ttl=300", compactLine{"-", "example.com", "TXT", "\"x\" ttl=300"}, true},
+		{"± MODIFY A foo.example.com: (1.1.1.1) -> (2.2.2.2)", compactLine{"~", "foo.example.com", "A", "(1.1.1.1) -> (2.2.2.2)"}, true},
+		{"Update nameservers a -> b", compactLine{}, false},
+		{"CREATE zone", compactLine{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseCompactLine(tt.text)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("parseCompactLine(%q) = %+v, %v; want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -56,6 +56,11 @@ type CompareConfig struct {
 	labelMap map[string]bool           // Which labels exist?
 	keyMap   map[models.RecordKey]bool // Which RecordKey exists?
 	//
+	// Indexes of ldata, so that large zones aren't scanned for each
+	// record.
+	labelIndex map[string]*labelConfig
+	typeIndex  map[models.RecordKey]*rTypeConfig // By label and rType.
+	//
 	// A function that generates a string used to compare two
 	// RecordConfigs for equality.  This is normally nil. If it is not
 	// nil, the function is called and the resulting string is joined to
@@ -64,6 +69,9 @@ type CompareConfig struct {
 	// comparison string.
 	compareableFunc ComparableFunc
 	//
+	// Provider-specific comparison rules. compareableFunc is a copy of
+	// comparer.Comparable, kept for debugging output.
+	comparer *Comparer
 }
 
 type labelConfig struct {
@@ -87,15 +95,23 @@ type targetConfig struct {
 }
 
 func NewCompareConfig(origin string, existing, desired models.Records, compFn ComparableFunc) *CompareConfig {
+	return newCompareConfig(origin, existing, desired, &Comparer{Comparable: compFn})
+}
+
+func newCompareConfig(origin string, existing, desired models.Records, comparer *Comparer) *CompareConfig {
 	cc := &CompareConfig{
 		existing: existing,
 		desired:  desired,
 		//
 		origin:          origin,
-		compareableFunc: compFn,
+		compareableFunc: comparer.Comparable,
+		comparer:        comparer,
 		//
 		labelMap: map[string]bool{},
 		keyMap:   map[models.RecordKey]bool{},
+		//
+		labelIndex: map[string]*labelConfig{},
+		typeIndex:  map[models.RecordKey]*rTypeConfig{},
 	}
 	cc.addRecords(existing, true) // Must be called first so that CNAME manipulations happen in the correct order.
 	cc.addRecords(desired, false)
@@ -207,61 +223,60 @@ func (cc *CompareConfig) addRecords(recs models.Records, storeInExisting bool) {
 	// of the same label+rtype are grouped. We use PrettySort because it works,
 	// has been extensively tested, and assures that the ChangeList will
 	// be in an order that is pretty to look at.
-	z := prettyzone.PrettySort(recs, cc.origin, 0, nil)
+	//
+	// Only the order of the records within a label matters, as cc.ldata
+	// is sorted by label later. Thus the records are grouped by label
+	// and each label is sorted on its own, which is much faster than
+	// sorting a large zone at once.
+	var labels []string
+	byLabel := map[string]models.Records{}
+	for _, rec := range recs {
+		if _, ok := byLabel[rec.NameFQDN]; !ok {
+			labels = append(labels, rec.NameFQDN)
+		}
+		byLabel[rec.NameFQDN] = append(byLabel[rec.NameFQDN], rec)
+	}
+	var sorted models.Records
+	for _, label := range labels {
+		sorted = append(sorted, prettyzone.PrettySort(byLabel[label], cc.origin, 0, nil).Records...)
+	}
 
-	for _, rec := range z.Records {
+	for _, rec := range sorted {
 
 		label := rec.NameFQDN
 		rtype := rec.Type
-		comp := comparable(rec, cc.compareableFunc)
+		comp := cc.comparer.comparable(rec)
 
 		// Are we seeing this label for the first time?
-		var labelIdx int
-		if _, ok := cc.labelMap[label]; !ok {
+		lc, ok := cc.labelIndex[label]
+		if !ok {
 			//fmt.Printf("DEBUG: I haven't see label=%v before. Adding.\n", label)
 			cc.labelMap[label] = true
-			cc.ldata = append(cc.ldata, &labelConfig{label: label})
-			labelIdx = highest(cc.ldata)
-		} else {
-			// find label in cc.ldata:
-			for k, v := range cc.ldata {
-				if v.label == label {
-					labelIdx = k
-					break
-				}
-			}
+			lc = &labelConfig{label: label}
+			cc.labelIndex[label] = lc
+			cc.ldata = append(cc.ldata, lc)
 		}
 
 		// Are we seeing this label+rtype for the first time?
-		key := rec.Key()
-		if _, ok := cc.keyMap[key]; !ok {
-			//fmt.Printf("DEBUG: I haven't see key=%v before. Adding.\n", key)
-			cc.keyMap[key] = true
-			x := cc.ldata[labelIdx]
+		cc.keyMap[rec.Key()] = true
+		typeKey := models.RecordKey{NameFQDN: label, Type: rtype}
+		td, ok := cc.typeIndex[typeKey]
+		if !ok {
 			//fmt.Printf("DEBUG: appending rtype=%v\n", rtype)
-			x.tdata = append(x.tdata, &rTypeConfig{rType: rtype})
-		}
-		var rtIdx int
-		// find rtype in tdata:
-		for k, v := range cc.ldata[labelIdx].tdata {
-			if v.rType == rtype {
-				rtIdx = k
-				break
-			}
+			td = &rTypeConfig{rType: rtype}
+			cc.typeIndex[typeKey] = td
+			lc.tdata = append(lc.tdata, td)
 		}
-		//fmt.Printf("DEBUG: found rtype=%v at index %d\n", rtype, rtIdx)
 
 		// Now it is safe to add/modify the records.
 
 		//fmt.Printf("BEFORE E/D: %v/%v\n", len(td.existingRecs), len(td.desiredRecs))
 		if storeInExisting {
-			cc.ldata[labelIdx].tdata[rtIdx].existingRecs = append(cc.ldata[labelIdx].tdata[rtIdx].existingRecs, rec)
-			cc.ldata[labelIdx].tdata[rtIdx].existingTargets = append(cc.ldata[labelIdx].tdata[rtIdx].existingTargets,
-				targetConfig{compareable: comp, rec: rec})
+			td.existingRecs = append(td.existingRecs, rec)
+			td.existingTargets = append(td.existingTargets, targetConfig{compareable: comp, rec: rec})
 		} else {
-			cc.ldata[labelIdx].tdata[rtIdx].desiredRecs = append(cc.ldata[labelIdx].tdata[rtIdx].desiredRecs, rec)
-			cc.ldata[labelIdx].tdata[rtIdx].desiredTargets = append(cc.ldata[labelIdx].tdata[rtIdx].desiredTargets,
-				targetConfig{compareable: comp, rec: rec})
+			td.desiredRecs = append(td.desiredRecs, rec)
+			td.desiredTargets = append(td.desiredTargets, targetConfig{compareable: comp, rec: rec})
 		}
 		//fmt.Printf("AFTER  L: %v\n", len(cc.ldata))
 		//fmt.Printf("AFTER  E/D: %v/%v\n", len(td.existingRecs), len(td.desiredRecs))
@@ -0,0 +1,179 @@
+package diff2
+
+// This file implements a way for providers to customize how records
+// are compared, without having to modify the records before (or after)
+// calling the differ.
+
+import (
+	"sync"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ownership"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+)
+
+// Comparer describes provider-specific rules for deciding if an
+// existing record and a desired record are equivalent.
+//
+// For example, Cloudflare reports a TTL of 1 for "automatic" which
+// should be considered equal to a TTL of 0, and whether a record is
+// proxied must be compared even though it is stored as metadata.
+type Comparer struct {
+	// Normalize, if not nil, is called on a copy of each existing and
+	// desired record before the comparison string is generated. Changes
+	// made by Normalize only affect the comparison; the records passed
+	// back in the ChangeList are the originals.  The copy is shallow,
+	// therefore Normalize must replace (not modify) any maps.
+	Normalize func(*models.RecordConfig)
+
+	// Comparable, if not nil, returns additional text that must also
+	// match for two records to be equal (for example, metadata that the
+	// provider cares about).
+	Comparable ComparableFunc
+}
+
+var logger = printer.Module("pkg.diff2")
+
+var (
+	comparersMu sync.RWMutex
+	comparers   = map[string]*Comparer{}
+)
+
+// RegisterComparer registers the comparison rules for a provider type.
+// It is normally called from the provider's init() function.
+func RegisterComparer(providerType string, c *Comparer) {
+	comparersMu.Lock()
+	defer comparersMu.Unlock()
+	comparers[providerType] = c
+}
+
+// GetComparer returns the comparison rules registered for
+// providerType. If none are registered, a Comparer that uses the
+// default rules is returned.
+func GetComparer(providerType string) *Comparer {
+	comparersMu.RLock()
+	defer comparersMu.RUnlock()
+	if c, ok := comparers[providerType]; ok {
+		return c
+	}
+	return &Comparer{}
+}
+
+// ByRecordSet is like the package-level ByRecordSet but uses the rules in c.
+func (c *Comparer) ByRecordSet(existing models.Records, dc *models.DomainConfig) (ChangeList, error) {
+	desired, err := desiredRecords(existing, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	cc := newCompareConfig(dc.Name, existing, desired, c)
+	instructions := analyzeByRecordSet(cc)
+	return orderChanges(processPurge(instructions, !dc.KeepUnknown), DefaultDependencies), nil
+}
+
+// ByLabel is like the package-level ByLabel but uses the rules in c.
+func (c *Comparer) ByLabel(existing models.Records, dc *models.DomainConfig) (ChangeList, error) {
+	desired, err := desiredRecords(existing, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	cc := newCompareConfig(dc.Name, existing, desired, c)
+	instructions := analyzeByLabel(cc)
+	return orderChanges(processPurge(instructions, !dc.KeepUnknown), DefaultDependencies), nil
+}
+
+// ByRecord is like the package-level ByRecord but uses the rules in c.
+func (c *Comparer) ByRecord(existing models.Records, dc *models.DomainConfig) (ChangeList, error) {
+	desired, err := desiredRecords(existing, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	cc := newCompareConfig(dc.Name, existing, desired, c)
+	instructions := analyzeByRecord(cc)
+	return orderChanges(processPurge(instructions, !dc.KeepUnknown), DefaultDependencies), nil
+}
+
+// ByZone is like the package-level ByZone but uses the rules in c.
+func (c *Comparer) ByZone(existing models.Records, dc *models.DomainConfig) ([]string, models.CorrectionKind, error) {
+	if len(existing) == 0 {
+		// Nothing previously existed. No need to output a list of individual changes.
+		return nil, models.CorrectionCreate, nil
+	}
+
+	desired, err := desiredRecords(existing, dc)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cc := newCompareConfig(dc.Name, existing, desired, c)
+	instructions := analyzeByRecord(cc)
+	instructions = processPurge(instructions, !dc.KeepUnknown)
+	return justMsgs(instructions), instructions.Kind(), nil
+}
+
+// desiredRecords returns the records that the zone should have:
+// dc.Records plus the existing records that IGNORE(), MANAGED_BY(),
+// PURGE_ONLY() and KEEP() leave alone.
+func desiredRecords(existing models.Records, dc *models.DomainConfig) (models.Records, error) {
+	foreign, err := ownership.Foreign(existing, dc) // Handle MANAGED_BY()
+	if err != nil {
+		return nil, err
+	}
+	if len(foreign) != 0 {
+		logger.Debugf("Leaving %d records that aren't MANAGED_BY(%q) alone\n", len(foreign), dc.ManagedBy)
+		skip := make(map[*models.RecordConfig]bool, len(foreign))
+		for _, rc := range foreign {
+			skip[rc] = true
+		}
+		var rest models.Records
+		for _, rc := range existing {
+			if !skip[rc] {
+				rest = append(rest, rc)
+			}
+		}
+		existing = rest
+	}
+
+	desired, err := handsoff(existing, dc.Records, dc.Unmanaged, !dc.UnmanagedUnsafe) // Handle IGNORE()
+	if err != nil {
+		return nil, err
+	}
+	desired = append(desired, foreign...)
+	return append(desired, unpurgeable(existing, desired, dc)...), nil
+}
+
+// unpurgeable returns the existing record sets that aren't desired
+// and that PURGE_ONLY() or KEEP() say must not be deleted. (NO_PURGE
+// is handled by processPurge.)
+func unpurgeable(existing, desired models.Records, dc *models.DomainConfig) models.Records {
+	if dc.KeepUnknown || (len(dc.PurgeOnly) == 0 && len(dc.Keep) == 0) {
+		return nil
+	}
+	keys := map[models.RecordKey]bool{}
+	for _, rc := range desired {
+		keys[rc.Key()] = true
+	}
+	var kept models.Records
+	for _, rc := range existing {
+		if !keys[rc.Key()] && !dc.Purgeable(rc.Type) {
+			kept = append(kept, rc)
+		}
+	}
+	return kept
+}
+
+// comparable returns the string used to compare rc to other records
+// for equality.
+func (c *Comparer) comparable(rc *models.RecordConfig) string {
+	if c == nil {
+		return comparable(rc, nil)
+	}
+	if c.Normalize != nil {
+		n := *rc
+		c.Normalize(&n)
+		rc = &n
+	}
+	return comparable(rc, c.Comparable)
+}
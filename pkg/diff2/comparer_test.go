@@ -0,0 +1,115 @@
+package diff2
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestComparerNormalize(t *testing.T) {
+	existing := models.Records{makeRecTTL("laba", "A", "1.2.3.4", 1)}
+	desired := models.Records{makeRecTTL("laba", "A", "1.2.3.4", 0)}
+	dc := &models.DomainConfig{Name: "f.com", Records: desired}
+
+	// Without a comparer, the TTLs differ.
+	cl, err := ByRecord(existing, dc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cl) != 1 {
+		t.Fatalf("expected 1 change without comparer, got %d:\n%s", len(cl), cl)
+	}
+
+	// With a comparer that treats TTL=1 as TTL=0, nothing changes.
+	c := &Comparer{
+		Normalize: func(rc *models.RecordConfig) {
+			if rc.TTL == 1 {
+				rc.TTL = 0
+			}
+		},
+	}
+	cl, err = c.ByRecord(existing, dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cl) != 0 {
+		t.Fatalf("expected no changes with comparer, got %d:\n%s", len(cl), cl)
+	}
+
+	// The records themselves must not be modified.
+	if existing[0].TTL != 1 {
+		t.Errorf("Normalize modified the original record: TTL=%d", existing[0].TTL)
+	}
+}
+
+func TestGetComparer(t *testing.T) {
+	if c := GetComparer("NOSUCHPROVIDER"); c.Normalize != nil || c.Comparable != nil {
+		t.Errorf("expected default comparer, got %+v", c)
+	}
+
+	f := func(rc *models.RecordConfig) string { return "x" }
+	RegisterComparer("TESTPROVIDER", &Comparer{Comparable: f})
+	if c := GetComparer("TESTPROVIDER"); c.Comparable == nil {
+		t.Errorf("expected registered comparer, got %+v", c)
+	}
+}
+
+func TestManagedBy(t *testing.T) {
+	existing := models.Records{
+		makeRec("www", "A", "1.1.1.1"),
+		makeRec("old", "A", "2.2.2.2"),
+		makeRec("manual", "A", "3.3.3.3"),
+		makeRec("_dnscontrol-owner.www", "TXT", `"dnscontrol-owner=prod type=A"`),
+		makeRec("_dnscontrol-owner.old", "TXT", `"dnscontrol-owner=prod type=A"`),
+	}
+	desired := models.Records{
+		makeRec("www", "A", "1.2.3.4"),
+		makeRec("_dnscontrol-owner.www", "TXT", `"dnscontrol-owner=prod type=A"`),
+	}
+	dc := &models.DomainConfig{Name: "f.com", Records: desired, ManagedBy: "prod"}
+
+	cl, err := ByRecord(existing, dc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// www is changed, old and its tag are deleted, manual is left alone.
+	var got []string
+	for _, c := range cl {
+		got = append(got, c.Type.String()+" "+c.Key.NameFQDN)
+	}
+	sort.Strings(got)
+	want := []string{"CHANGE www.f.com", "DELETE _dnscontrol-owner.old.f.com", "DELETE old.f.com"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPurgeOnlyKeep(t *testing.T) {
+	existing := models.Records{
+		makeRec("www", "A", "1.1.1.1"),
+		makeRec("old", "A", "2.2.2.2"),
+		makeRec("old", "TXT", "foo"),
+		makeRec("@", "MX", "10 mx.f.com."),
+	}
+	desired := models.Records{
+		makeRec("www", "A", "1.2.3.4"),
+	}
+	dc := &models.DomainConfig{Name: "f.com", Records: desired, PurgeOnly: []string{"A", "TXT"}, Keep: []string{"TXT"}}
+
+	cl, err := ByRecordSet(existing, dc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the A records may be deleted.
+	var got []string
+	for _, c := range cl {
+		got = append(got, c.Type.String()+" "+c.Key.NameFQDN+" "+c.Key.Type)
+	}
+	sort.Strings(got)
+	want := []string{"CHANGE www.f.com A", "DELETE old.f.com A"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -32,6 +32,35 @@ type Change struct {
 	Msgs       []string                      // Human-friendly explanation of what changed
 	MsgsJoined string                        // strings.Join(Msgs, "\n")
 	MsgsByKey  map[models.RecordKey][]string // Messages for a given key
+
+	kind models.CorrectionKind // See Kind.
+}
+
+// Kind returns what making the change does, for the Kind of the
+// correction that makes it. A CHANGE of a recordset or label is as
+// destructive as the record changes it is made of: it is a
+// CorrectionDestroy if it removes a record.
+func (c Change) Kind() models.CorrectionKind {
+	if c.kind != 0 {
+		return c.kind
+	}
+	switch c.Type {
+	case CREATE:
+		return models.CorrectionCreate
+	case DELETE:
+		return models.CorrectionDestroy
+	}
+	return models.CorrectionModify
+}
+
+// Kind returns the most destructive Kind of the changes, or 0 if
+// there are none.
+func (cl ChangeList) Kind() models.CorrectionKind {
+	var kind models.CorrectionKind
+	for _, c := range cl {
+		kind = kind.Max(c.Kind())
+	}
+	return kind
 }
 
 /*
@@ -50,21 +79,24 @@ General instructions:
     switch change.Type {
     case diff2.CREATE:
       corr = &models.Correction{
-        Msg: change.MsgsJoined,
+        Msg:  change.MsgsJoined,
+        Kind: change.Kind(),
         F: func() error {
           return c.createRecord(FILL IN)
         },
       }
     case diff2.CHANGE:
       corr = &models.Correction{
-        Msg: change.MsgsJoined,
+        Msg:  change.MsgsJoined,
+        Kind: change.Kind(),
         F: func() error {
           return c.modifyRecord(FILL IN)
         },
       }
     case diff2.DELETE:
       corr = &models.Correction{
-        Msg: change.MsgsJoined,
+        Msg:  change.MsgsJoined,
+        Kind: change.Kind(),
         F: func() error {
           return c.deleteRecord(FILL IN)
         },
@@ -76,6 +108,22 @@ General instructions:
   return corrections, nil
 }
 
+Providers that consider some records equivalent even though they are
+not identical (for example, Cloudflare reports TTL=1 for "automatic")
+should register a Comparer in their init() function:
+
+  diff2.RegisterComparer(providerName, &diff2.Comparer{
+    Normalize: func(rc *models.RecordConfig) { ... },
+  })
+
+and use it instead of the package-level functions:
+
+  changes, err := diff2.GetComparer(providerName).ByRecord(existing, dc)
+
+The changes are ordered so that a change comes after the changes it
+depends on (see DefaultDependencies): for example, the NS records of a
+label are created before its DS records. Make the corrections in the
+order of the changes.
 
 */
 
@@ -90,18 +138,7 @@ General instructions:
 //
 // Examples include:
 func ByRecordSet(existing models.Records, dc *models.DomainConfig, compFunc ComparableFunc) (ChangeList, error) {
-	// dc stores the desired state.
-
-	desired := dc.Records
-	var err error
-	desired, err = handsoff(existing, desired, dc.Unmanaged, dc.UnmanagedUnsafe) // Handle UNMANAGED()
-	if err != nil {
-		return nil, err
-	}
-
-	cc := NewCompareConfig(dc.Name, existing, desired, compFunc)
-	instructions := analyzeByRecordSet(cc)
-	return processPurge(instructions, !dc.KeepUnknown), nil
+	return (&Comparer{Comparable: compFunc}).ByRecordSet(existing, dc)
 }
 
 // ByLabel takes two lists of records (existing and desired) and
@@ -113,18 +150,7 @@ func ByRecordSet(existing models.Records, dc *models.DomainConfig, compFunc Comp
 //
 // Examples include:
 func ByLabel(existing models.Records, dc *models.DomainConfig, compFunc ComparableFunc) (ChangeList, error) {
-	// dc stores the desired state.
-
-	desired := dc.Records
-	var err error
-	desired, err = handsoff(existing, desired, dc.Unmanaged, dc.UnmanagedUnsafe) // Handle UNMANAGED()
-	if err != nil {
-		return nil, err
-	}
-
-	cc := NewCompareConfig(dc.Name, existing, desired, compFunc)
-	instructions := analyzeByLabel(cc)
-	return processPurge(instructions, !dc.KeepUnknown), nil
+	return (&Comparer{Comparable: compFunc}).ByLabel(existing, dc)
 }
 
 // ByRecord takes two lists of records (existing and desired) and
@@ -140,18 +166,7 @@ func ByLabel(existing models.Records, dc *models.DomainConfig, compFunc Comparab
 //
 // Examples include: INWX
 func ByRecord(existing models.Records, dc *models.DomainConfig, compFunc ComparableFunc) (ChangeList, error) {
-	// dc stores the desired state.
-
-	desired := dc.Records
-	var err error
-	desired, err = handsoff(existing, desired, dc.Unmanaged, dc.UnmanagedUnsafe) // Handle UNMANAGED()
-	if err != nil {
-		return nil, err
-	}
-
-	cc := NewCompareConfig(dc.Name, existing, desired, compFunc)
-	instructions := analyzeByRecord(cc)
-	return processPurge(instructions, !dc.KeepUnknown), nil
+	return (&Comparer{Comparable: compFunc}).ByRecord(existing, dc)
 }
 
 // ByZone takes two lists of records (existing and desired) and
@@ -162,37 +177,20 @@ func ByRecord(existing models.Records, dc *models.DomainConfig, compFunc Compara
 // zone is uploaded.
 //
 // The user should see a list of changes as if individual records were
-// updated.
+// updated. ByZone returns those messages and the Kind of the changes
+// (see ChangeList.Kind), which is 0 if there are none.
 //
 // The caller of this function should:
 //
-//	changed, msgs := diff2.ByZone(existing, desired, origin, nil
-//		fmt.Sprintf("CREATING ZONEFILE FOR THE FIRST TIME: dir/example.com.zone"))
-//	if changed {
+//	msgs, kind, err := diff2.ByZone(existing, dc, nil)
+//	if kind != 0 {
 //		// output msgs
 //		// generate the zone using the "desired" records
 //	}
 //
 // Example providers include: BIND
-func ByZone(existing models.Records, dc *models.DomainConfig, compFunc ComparableFunc) ([]string, bool, error) {
-	// dc stores the desired state.
-
-	if len(existing) == 0 {
-		// Nothing previously existed. No need to output a list of individual changes.
-		return nil, true, nil
-	}
-
-	desired := dc.Records
-	var err error
-	desired, err = handsoff(existing, desired, dc.Unmanaged, dc.UnmanagedUnsafe) // Handle UNMANAGED()
-	if err != nil {
-		return nil, false, err
-	}
-
-	cc := NewCompareConfig(dc.Name, existing, desired, compFunc)
-	instructions := analyzeByRecord(cc)
-	instructions = processPurge(instructions, !dc.KeepUnknown)
-	return justMsgs(instructions), len(instructions) != 0, nil
+func ByZone(existing models.Records, dc *models.DomainConfig, compFunc ComparableFunc) ([]string, models.CorrectionKind, error) {
+	return (&Comparer{Comparable: compFunc}).ByZone(existing, dc)
 }
 
 func (c Change) String() string {
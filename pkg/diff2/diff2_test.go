@@ -0,0 +1,107 @@
+package diff2
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// benchZone returns the existing and desired records of a zone of n
+// records on n/2 labels, where 1% of the records change, 1% are
+// created and 1% are deleted.
+func benchZone(n int) (existing models.Records, dc *models.DomainConfig) {
+	dc = &models.DomainConfig{Name: "f.com"}
+	for i := 0; i < n/2; i++ {
+		label := fmt.Sprintf("host%d", i)
+		a := makeRec(label, "A", fmt.Sprintf("10.%d.%d.%d", i>>16&255, i>>8&255, i&255))
+		txt := makeRec(label, "TXT", "v=spf1 -all")
+		existing = append(existing, a, txt)
+		switch i % 100 {
+		case 0: // Change
+			dc.Records = append(dc.Records, makeRec(label, "A", "192.0.2.1"), txt)
+		case 1: // Delete
+			dc.Records = append(dc.Records, a)
+		case 2: // Create
+			dc.Records = append(dc.Records, a, txt, makeRec(label, "AAAA", "2001:db8::1"))
+		default:
+			dc.Records = append(dc.Records, a, txt)
+		}
+	}
+	return existing, dc
+}
+
+func TestChangeKind(t *testing.T) {
+	a1 := makeRec("www", "A", "1.1.1.1")
+	a2 := makeRec("www", "A", "2.2.2.2")
+	a3 := makeRec("www", "A", "3.3.3.3")
+	mx := makeRec("www", "MX", "1 mx.f.com.")
+	for _, tc := range []struct {
+		name              string
+		existing, desired models.Records
+		want              models.CorrectionKind
+	}{
+		{"add to set", models.Records{a1}, models.Records{a1, a2}, models.CorrectionCreate},
+		{"replace in set", models.Records{a1}, models.Records{a2}, models.CorrectionModify},
+		{"drop from set", models.Records{a1, a2}, models.Records{a1}, models.CorrectionDestroy},
+		{"drop and replace", models.Records{a1, a2}, models.Records{a3}, models.CorrectionDestroy},
+	} {
+		dc := &models.DomainConfig{Name: "f.com", Records: tc.desired}
+		for _, by := range []struct {
+			name string
+			fn   func(models.Records, *models.DomainConfig, ComparableFunc) (ChangeList, error)
+		}{
+			{"ByRecordSet", ByRecordSet},
+			{"ByLabel", ByLabel},
+		} {
+			// The MX record at the label is unchanged.
+			dc.Records = append(tc.desired, mx)
+			cl, err := by.fn(append(tc.existing, mx), dc, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cl) != 1 || cl[0].Type != CHANGE || cl[0].Kind() != tc.want {
+				t.Errorf("%s %s: got %v, want one CHANGE of kind %v", by.name, tc.name, cl, tc.want)
+			}
+		}
+		_, kind, err := ByZone(append(tc.existing, mx), dc, nil)
+		if err != nil || kind != tc.want {
+			t.Errorf("ByZone %s: got %v, %v, want %v", tc.name, kind, err, tc.want)
+		}
+	}
+}
+
+func BenchmarkDiff(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000} {
+		existing, dc := benchZone(n)
+		for _, by := range []struct {
+			name string
+			fn   func(models.Records, *models.DomainConfig, ComparableFunc) (ChangeList, error)
+		}{
+			{"ByRecord", ByRecord},
+			{"ByRecordSet", ByRecordSet},
+			{"ByLabel", ByLabel},
+		} {
+			b.Run(fmt.Sprintf("%s/%d", by.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					cl, err := by.fn(existing, dc, nil)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if len(cl) == 0 {
+						b.Fatal("no changes")
+					}
+				}
+			})
+		}
+		b.Run(fmt.Sprintf("ByZone/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := ByZone(existing, dc, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
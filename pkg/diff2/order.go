@@ -0,0 +1,143 @@
+package diff2
+
+// This file orders the changes so that a change is made after the
+// changes it depends on. For example, the NS records of a label are
+// created before its DS records, because many APIs reject a DS record
+// that has no NS records.
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// Step is what Order needs to know about a change.
+type Step struct {
+	Verb  Verb
+	Label string // The NameFQDN.
+	Type  string // "" if the change is to all the records at the label.
+}
+
+// Dependency reports whether step a must be made before step b. It is
+// only asked about steps at the same label.
+type Dependency func(a, b Step) bool
+
+// DefaultDependencies are the dependencies between changes that the
+// By*() functions respect.
+var DefaultDependencies = []Dependency{
+	TypeDependency("DS", "NS"),
+	CNAMEExclusive,
+}
+
+// TypeDependency declares that the records of type typ at a label need
+// the records of type on: the on records are created or changed before
+// the typ records, and the typ records are deleted before the on
+// records.
+func TypeDependency(typ, on string) Dependency {
+	return func(a, b Step) bool {
+		if b.Verb == DELETE {
+			return a.Verb == DELETE && a.Type == typ && b.Type == on
+		}
+		return a.Verb != DELETE && a.Type == on && b.Type == typ
+	}
+}
+
+// CNAMEExclusive is the Dependency that deletes the records at a label
+// before creating a record of another type there, if either is a
+// CNAME. A CNAME can't share its label, so for example, when a CNAME is
+// replaced by A records, the CNAME is deleted first.
+func CNAMEExclusive(a, b Step) bool {
+	return a.Verb == DELETE && b.Verb == CREATE && a.Type != b.Type && (a.Type == "CNAME" || b.Type == "CNAME")
+}
+
+// Order returns the indexes of steps in the order in which to make
+// them. Steps keep their order, except that a step is moved after the
+// steps it depends on according to deps. If the dependencies are
+// circular, the steps in the cycle keep their order.
+func Order(steps []Step, deps []Dependency) []int {
+	byLabel := map[string][]int{}
+	for i, s := range steps {
+		byLabel[s.Label] = append(byLabel[s.Label], i)
+	}
+	next := make([][]int, len(steps))  // The steps that wait for step i.
+	waiting := make([]int, len(steps)) // The number of steps that step i waits for.
+	for _, idx := range byLabel {
+		for _, i := range idx {
+			for _, j := range idx {
+				if i != j && dependsOn(deps, steps[i], steps[j]) {
+					next[i] = append(next[i], j)
+					waiting[j]++
+				}
+			}
+		}
+	}
+
+	// Always make the first step that waits for nothing.
+	ready := &intHeap{}
+	for i := range steps {
+		if waiting[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+	done := make([]bool, len(steps))
+	order := make([]int, 0, len(steps))
+	for len(order) < len(steps) {
+		if ready.Len() == 0 {
+			// A cycle. Break it at its first step.
+			for i := range steps {
+				if !done[i] {
+					heap.Push(ready, i)
+					break
+				}
+			}
+		}
+		i := heap.Pop(ready).(int)
+		if done[i] {
+			continue
+		}
+		done[i] = true
+		order = append(order, i)
+		for _, j := range next[i] {
+			waiting[j]--
+			if waiting[j] == 0 && !done[j] {
+				heap.Push(ready, j)
+			}
+		}
+	}
+	return order
+}
+
+func dependsOn(deps []Dependency, a, b Step) bool {
+	for _, d := range deps {
+		if d(a, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// orderChanges orders cl according to deps.
+func orderChanges(cl ChangeList, deps []Dependency) ChangeList {
+	if len(cl) < 2 {
+		return cl
+	}
+	steps := make([]Step, len(cl))
+	for i, c := range cl {
+		steps[i] = Step{Verb: c.Type, Label: c.Key.NameFQDN, Type: c.Key.Type}
+	}
+	ordered := make(ChangeList, 0, len(cl))
+	for _, i := range Order(steps, deps) {
+		ordered = append(ordered, cl[i])
+	}
+	return ordered
+}
+
+type intHeap struct{ sort.IntSlice }
+
+func (h *intHeap) Push(x interface{}) { h.IntSlice = append(h.IntSlice, x.(int)) }
+
+func (h *intHeap) Pop() interface{} {
+	n := len(h.IntSlice)
+	x := h.IntSlice[n-1]
+	h.IntSlice = h.IntSlice[:n-1]
+	return x
+}
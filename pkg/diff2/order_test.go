@@ -0,0 +1,113 @@
+package diff2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestOrder(t *testing.T) {
+	step := func(verb Verb, label, rtype string) Step { return Step{Verb: verb, Label: label, Type: rtype} }
+	tests := []struct {
+		name  string
+		steps []Step
+		deps  []Dependency
+		want  []int
+	}{
+		{
+			name: "no dependencies",
+			steps: []Step{
+				step(CREATE, "child.f.com", "DS"),
+				step(CREATE, "www.f.com", "A"),
+			},
+			want: []int{0, 1},
+		},
+		{
+			name: "NS created before DS",
+			steps: []Step{
+				step(CREATE, "child.f.com", "DS"),
+				step(CREATE, "other.f.com", "NS"),
+				step(CREATE, "child.f.com", "NS"),
+			},
+			deps: DefaultDependencies,
+			want: []int{1, 2, 0},
+		},
+		{
+			name: "DS deleted before NS",
+			steps: []Step{
+				step(DELETE, "child.f.com", "NS"),
+				step(DELETE, "child.f.com", "DS"),
+			},
+			deps: DefaultDependencies,
+			want: []int{1, 0},
+		},
+		{
+			name: "CNAME deleted before A created",
+			steps: []Step{
+				step(CREATE, "www.f.com", "A"),
+				step(CREATE, "www.f.com", "A"),
+				step(DELETE, "www.f.com", "CNAME"),
+			},
+			deps: DefaultDependencies,
+			want: []int{2, 0, 1},
+		},
+		{
+			name: "A deleted before CNAME created",
+			steps: []Step{
+				step(CREATE, "www.f.com", "CNAME"),
+				step(DELETE, "www.f.com", "A"),
+				step(DELETE, "www.f.com", "AAAA"),
+			},
+			deps: DefaultDependencies,
+			want: []int{1, 2, 0},
+		},
+		{
+			name: "cycle broken at its first step",
+			steps: []Step{
+				step(CREATE, "www.f.com", "A"),
+				step(CREATE, "www.f.com", "B"),
+				step(CREATE, "www.f.com", "C"),
+			},
+			deps: []Dependency{TypeDependency("A", "B"), TypeDependency("B", "A"), TypeDependency("A", "C")},
+			want: []int{2, 0, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Order(tt.steps, tt.deps); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Order() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByRecordOrder(t *testing.T) {
+	existing := models.Records{
+		makeRec("child", "DS", "1 13 1 da39a3ee5e6b4b0d3255bfef95601890afd80709"),
+		makeRec("child", "NS", "ns1.example.com."),
+		makeRec("www", "CNAME", "f.com."),
+	}
+	desired := models.Records{
+		makeRec("www", "A", "1.1.1.1"),
+	}
+	dc := &models.DomainConfig{Name: "f.com", Records: desired}
+
+	changes, err := ByRecord(existing, dc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Type.String()+" "+c.Key.NameFQDN+" "+c.Key.Type)
+	}
+	want := []string{
+		"DELETE child.f.com DS",
+		"DELETE child.f.com NS",
+		"DELETE www.f.com CNAME",
+		"CREATE www.f.com A",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -2,9 +2,6 @@ package diff2
 
 import (
 	"fmt"
-	"strings"
-
-	"github.com/gobwas/glob"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
@@ -29,16 +26,22 @@ func handsoff(
 	}
 
 	// What desired items might conflict?
-	conflicts, err := manyQueries(desired, unmanaged)
+	matched, err := manyQueries(desired, unmanaged)
 	if err != nil {
 		return nil, err
 	}
+	var conflicts models.Records
+	for _, r := range matched {
+		if !conflictException(r) {
+			conflicts = append(conflicts, r)
+		}
+	}
 	if len(conflicts) != 0 {
 		level := "WARN"
 		if beSafe {
 			level = "ERROR"
 		}
-		printer.Printf("%s: dnsconfig.js records that overlap MANAGED: (%d)\n", level, len(conflicts))
+		printer.Printf("%s: dnsconfig.js records that overlap IGNORE()d records: (%d)\n", level, len(conflicts))
 		for i, r := range conflicts {
 			printer.Printf("- % 4d: %s %s %s\n", i, r.GetLabelFQDN(), r.Type, r.GetTargetRFC1035Quoted())
 		}
@@ -58,72 +61,31 @@ func handsoff(
 func manyQueries(rcs models.Records, queries []*models.UnmanagedConfig) (result models.Records, err error) {
 
 	for _, q := range queries {
-
-		lab := q.Label
-		if lab == "" {
-			lab = "*"
-		}
-		glabel, err := glob.Compile(lab)
-		if err != nil {
+		if err := q.Compile(); err != nil {
 			return nil, err
 		}
+	}
 
-		targ := q.Target
-		if targ == "" {
-			targ = "*"
-		}
-		gtarget, err := glob.Compile(targ)
-		if err != nil {
-			return nil, err
-		}
-
-		hasRType := compileTypeGlob(q.RType)
-
-		for _, rc := range rcs {
-			if match(rc, glabel, gtarget, hasRType) {
+	// Each record is included at most once, even if many rules match it.
+	for _, rc := range rcs {
+		for _, q := range queries {
+			if q.Match(rc) {
 				result = append(result, rc)
+				break
 			}
 		}
 	}
 	return result, nil
 }
 
-func compileTypeGlob(g string) map[string]bool {
-	m := map[string]bool{}
-	for _, j := range strings.Split(g, ",") {
-		m[strings.TrimSpace(j)] = true
-	}
-	return m
-}
-
-func match(rc *models.RecordConfig, glabel, gtarget glob.Glob, hasRType map[string]bool) bool {
-	//printer.Printf("DEBUG: match(%v, %v, %v, %v)\n", rc.NameFQDN, glabel, gtarget, hasRType)
-
-	// _ = glabel.Match(rc.NameFQDN)
-	// _ = matchType(rc.Type, hasRType)
-	// x := rc.GetTargetField()
-	// _ = gtarget.Match(x)
-
-	if !glabel.Match(rc.NameFQDN) {
-		//printer.Printf("DEBUG: REJECTED LABEL: %s:%v\n", rc.NameFQDN, glabel)
-		return false
-	} else if !matchType(rc.Type, hasRType) {
-		//printer.Printf("DEBUG: REJECTED TYPE: %s:%v\n", rc.Type, hasRType)
-		return false
-	} else if gtarget == nil {
-		return true
-	} else if !gtarget.Match(rc.GetTargetField()) {
-		//printer.Printf("DEBUG: REJECTED TARGET: %v:%v\n", rc.GetTargetField(), gtarget)
-		return false
-	}
-	return true
-}
-
-func matchType(s string, hasRType map[string]bool) bool {
-	//printer.Printf("DEBUG: matchType map=%v\n", hasRType)
-	if len(hasRType) == 0 {
+// conflictException returns true if a desired record is permitted to
+// overlap an IGNORE()'d record. This mirrors the exceptions in pkg/diff.
+func conflictException(rc *models.RecordConfig) bool {
+	// Providers often add NS and SOA records at the apex.
+	if (rc.Type == "NS" || rc.Type == "SOA") && rc.GetLabel() == "@" {
 		return true
 	}
-	_, ok := hasRType[s]
+	// IGNORE_NAME_DISABLE_SAFETY_CHECK
+	_, ok := rc.Metadata["ignore_name_disable_safety_check"]
 	return ok
 }
@@ -4,144 +4,106 @@ import (
 	"testing"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
-	"github.com/gobwas/glob"
 )
 
-var rmapNil map[string]bool
-var rmapAMX = map[string]bool{
-	"A":  true,
-	"MX": true,
-}
-var rmapCNAME = map[string]bool{
-	"CNAME": true,
-}
-
-func Test_match(t *testing.T) {
+func Test_manyQueries(t *testing.T) {
 
 	testRecLammaA1234 := makeRec("lamma", "A", "1.2.3.4")
+	testRecLammaMX := makeRec("lamma", "MX", "10 mx.example.com.")
+	testRecDeepA := makeRec("a.lamma", "A", "1.2.3.5")
+	recs := models.Records{testRecLammaA1234, testRecLammaMX, testRecDeepA}
 
-	type args struct {
-		rc       *models.RecordConfig
-		glabel   glob.Glob
-		gtarget  glob.Glob
-		hasRType map[string]bool
-	}
 	tests := []struct {
-		name string
-		args args
-		want bool
+		name    string
+		queries []*models.UnmanagedConfig
+		want    models.Records
 	}{
 
 		{
-			name: "match3",
-			args: args{
-				rc:       testRecLammaA1234,
-				glabel:   glob.MustCompile("lam*"),
-				hasRType: rmapAMX,
-				gtarget:  glob.MustCompile("1.2.3.*"),
-			},
-			want: true,
+			name:    "match3",
+			queries: []*models.UnmanagedConfig{{Label: "lam*", RType: "A,MX", Target: "1.2.3.*"}},
+			want:    models.Records{testRecLammaA1234},
 		},
 
 		{
-			name: "match2",
-			args: args{
-				rc:       testRecLammaA1234,
-				glabel:   glob.MustCompile("lam*"),
-				hasRType: rmapAMX,
-				gtarget:  nil,
-			},
-			want: true,
+			name:    "match2",
+			queries: []*models.UnmanagedConfig{{Label: "lam*", RType: "A,MX"}},
+			want:    models.Records{testRecLammaA1234, testRecLammaMX},
 		},
 
 		{
-			name: "match1",
-			args: args{
-				rc:       testRecLammaA1234,
-				glabel:   glob.MustCompile("lam*"),
-				hasRType: rmapNil,
-				gtarget:  nil,
-			},
-			want: true,
+			name:    "match1",
+			queries: []*models.UnmanagedConfig{{Label: "lam*"}},
+			want:    models.Records{testRecLammaA1234, testRecLammaMX},
 		},
 
 		{
-			name: "reject1",
-			args: args{
-				rc:       testRecLammaA1234,
-				glabel:   glob.MustCompile("yyyy"),
-				hasRType: rmapAMX,
-				gtarget:  glob.MustCompile("1.2.3.*"),
-			},
-			want: false,
+			name:    "matchStarType",
+			queries: []*models.UnmanagedConfig{{Label: "lamma", RType: "*", Target: "*"}},
+			want:    models.Records{testRecLammaA1234, testRecLammaMX},
 		},
 
 		{
-			name: "reject2",
-			args: args{
-				rc:       testRecLammaA1234,
-				glabel:   glob.MustCompile("lam*"),
-				hasRType: rmapCNAME,
-				gtarget:  glob.MustCompile("1.2.3.*"),
-			},
-			want: false,
+			name:    "matchDeep",
+			queries: []*models.UnmanagedConfig{{Label: "**.lamma"}},
+			want:    models.Records{testRecDeepA},
 		},
 
 		{
-			name: "reject3",
-			args: args{
-				rc:       testRecLammaA1234,
-				glabel:   glob.MustCompile("lam*"),
-				hasRType: rmapAMX,
-				gtarget:  glob.MustCompile("zzzzz"),
-			},
-			want: false,
+			name:    "matchAll",
+			queries: []*models.UnmanagedConfig{{Label: "*"}},
+			want:    recs,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := match(tt.args.rc, tt.args.glabel, tt.args.gtarget, tt.args.hasRType); got != tt.want {
-				t.Errorf("match() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
 
-func Test_matchType(t *testing.T) {
-	type args struct {
-		s        string
-		hasRType map[string]bool
-	}
-	tests := []struct {
-		name string
-		args args
-		want bool
-	}{
+		{
+			name: "matchOnce",
+			queries: []*models.UnmanagedConfig{
+				{Label: "lamma", RType: "A"},
+				{Label: "*", Target: "1.2.3.4"},
+			},
+			want: models.Records{testRecLammaA1234},
+		},
 
 		{
-			name: "matchCNAME",
-			args: args{"CNAME", rmapCNAME},
-			want: true,
+			name:    "reject1",
+			queries: []*models.UnmanagedConfig{{Label: "yyyy", RType: "A,MX", Target: "1.2.3.*"}},
+			want:    nil,
 		},
 
 		{
-			name: "rejectCNAME",
-			args: args{"MX", rmapCNAME},
-			want: false,
+			name:    "reject2",
+			queries: []*models.UnmanagedConfig{{Label: "lam*", RType: "CNAME", Target: "1.2.3.*"}},
+			want:    nil,
 		},
 
 		{
-			name: "matchNIL",
-			args: args{"CNAME", rmapNil},
-			want: true,
+			name:    "reject3",
+			queries: []*models.UnmanagedConfig{{Label: "lam*", RType: "A,MX", Target: "zzzzz"}},
+			want:    nil,
 		},
 	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := matchType(tt.args.s, tt.args.hasRType); got != tt.want {
-				t.Errorf("matchType() = %v, want %v", got, tt.want)
+			got, err := manyQueries(recs, tt.queries)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("manyQueries() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("manyQueries()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
 			}
 		})
 	}
 }
+
+func Test_manyQueriesInvalid(t *testing.T) {
+	_, err := manyQueries(nil, []*models.UnmanagedConfig{{Label: "[abc"}})
+	if err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
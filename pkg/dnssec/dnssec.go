@@ -0,0 +1,104 @@
+// Package dnssec passes the DS records of signed zones from the DNS
+// providers that sign them to the registrar, which publishes them in
+// the parent zone.
+//
+// DNS providers that support AUTODNSSEC_ON implement
+// providers.DSLister. After the DNS providers' corrections are run,
+// DetermineDSRecordsForProviders collects their DS records into
+// dc.DSRecords. Registrars that can publish DS records compare them
+// with the ones at the registry (see Diff) and fix the difference.
+package dnssec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+// DetermineDSRecordsForProviders returns the DS records that the
+// registrar should publish for a domain. It returns nil, which means
+// that the DS records at the registrar are left alone, unless the
+// domain uses AUTODNSSEC_ON and at least one of the providers reports
+// DS records.
+func DetermineDSRecordsForProviders(dc *models.DomainConfig, dsps []*models.DNSProviderInstance) (models.Records, error) {
+	if dc.AutoDNSSEC != "on" {
+		return nil, nil
+	}
+	var ds models.Records
+	seen := map[string]bool{}
+	for _, dsp := range dsps {
+		lister, ok := dsp.Driver.(providers.DSLister)
+		if !ok {
+			continue
+		}
+		if !printer.SkinnyReport {
+			fmt.Printf("----- Getting DS records from: %s\n", dsp.Name)
+		}
+		recs, err := lister.GetDSRecords(dc.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, rc := range recs {
+			if rc.Type != "DS" {
+				return nil, fmt.Errorf("%s returned a %s record as a DS record for %s", dsp.Name, rc.Type, dc.Name)
+			}
+			if k := key(rc); !seen[k] {
+				seen[k] = true
+				ds = append(ds, rc)
+			}
+		}
+	}
+	sortDS(ds)
+	return ds, nil
+}
+
+// key returns a string that identifies a DS record. Digests are
+// compared case-insensitively.
+func key(rc *models.RecordConfig) string {
+	return fmt.Sprintf("%d %d %d %s", rc.DsKeyTag, rc.DsAlgorithm, rc.DsDigestType, strings.ToUpper(rc.DsDigest))
+}
+
+func sortDS(recs models.Records) {
+	sort.SliceStable(recs, func(i, j int) bool {
+		return key(recs[i]) < key(recs[j])
+	})
+}
+
+// Diff returns the DS records to add to and to remove from the
+// registry so that the existing records match the desired ones.
+func Diff(existing, desired models.Records) (add, del models.Records) {
+	have := map[string]bool{}
+	for _, rc := range existing {
+		have[key(rc)] = true
+	}
+	want := map[string]bool{}
+	for _, rc := range desired {
+		k := key(rc)
+		if !want[k] && !have[k] {
+			add = append(add, rc)
+		}
+		want[k] = true
+	}
+	for _, rc := range existing {
+		if !want[key(rc)] {
+			del = append(del, rc)
+		}
+	}
+	sortDS(add)
+	sortDS(del)
+	return add, del
+}
+
+// String returns the DS records as a comma-separated list, for the
+// messages of corrections.
+func String(recs models.Records) string {
+	s := make([]string, len(recs))
+	for i, rc := range recs {
+		s[i] = fmt.Sprintf("%d %d %d %s", rc.DsKeyTag, rc.DsAlgorithm, rc.DsDigestType, rc.DsDigest)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,73 @@
+package dnssec
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func ds(s string) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: "DS"}
+	rc.SetLabel("@", "example.com")
+	if err := rc.SetTargetDSString(s); err != nil {
+		panic(err)
+	}
+	return rc
+}
+
+// plainDSP is a DNS provider that doesn't report DS records.
+type plainDSP struct{}
+
+func (plainDSP) GetNameservers(string) ([]*models.Nameserver, error) { return nil, nil }
+func (plainDSP) GetZoneRecords(string) (models.Records, error)       { return nil, nil }
+func (plainDSP) GetDomainCorrections(*models.DomainConfig) ([]*models.Correction, error) {
+	return nil, nil
+}
+
+// fakeDSP is a DNS provider that reports DS records.
+type fakeDSP struct {
+	plainDSP
+	ds models.Records
+}
+
+func (f fakeDSP) GetDSRecords(string) (models.Records, error) { return f.ds, nil }
+
+func TestDetermineDSRecordsForProviders(t *testing.T) {
+	a, b := ds("1 13 2 ABCD"), ds("2 13 2 EF01")
+	dsps := []*models.DNSProviderInstance{
+		{ProviderBase: models.ProviderBase{Name: "one"}, Driver: fakeDSP{ds: models.Records{b, a}}},
+		{ProviderBase: models.ProviderBase{Name: "two"}, Driver: fakeDSP{ds: models.Records{ds("1 13 2 abcd")}}},
+		{ProviderBase: models.ProviderBase{Name: "three"}, Driver: plainDSP{}},
+	}
+
+	dc := &models.DomainConfig{Name: "example.com", AutoDNSSEC: "on"}
+	got, err := DetermineDSRecordsForProviders(dc, dsps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if String(got) != "1 13 2 ABCD, 2 13 2 EF01" {
+		t.Errorf("got %s", String(got))
+	}
+
+	dc.AutoDNSSEC = ""
+	if got, _ := DetermineDSRecordsForProviders(dc, dsps); got != nil {
+		t.Errorf("expected nil without AUTODNSSEC_ON, got %s", String(got))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	existing := models.Records{ds("1 13 2 ABCD"), ds("3 8 2 1234")}
+	desired := models.Records{ds("1 13 2 abcd"), ds("2 13 2 EF01")}
+	add, del := Diff(existing, desired)
+	if String(add) != "2 13 2 EF01" {
+		t.Errorf("add = %s", String(add))
+	}
+	if String(del) != "3 8 2 1234" {
+		t.Errorf("del = %s", String(del))
+	}
+
+	add, del = Diff(desired, desired)
+	if len(add) != 0 || len(del) != 0 {
+		t.Errorf("expected no changes, got %s and %s", String(add), String(del))
+	}
+}
@@ -0,0 +1,98 @@
+// Package dualhost compares the records that the DNS providers of a
+// domain will serve.
+//
+// When a domain has several DNS providers, each is given the same
+// records, but not every provider can store them as they are: some
+// raise the TTLs below their minimum, join the strings of TXT records,
+// or don't manage the NS records at the apex. Resolvers get one answer
+// or the other depending on which nameserver they ask. Validation only
+// rejects what a provider can't do at all; this package reports the
+// rest.
+package dualhost
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Zone is the records that one DNS provider will serve for a domain:
+// the records of the domain as the provider changed them in
+// GetDomainCorrections().
+type Zone struct {
+	Provider string
+	Records  models.Records
+}
+
+// Check compares the zones and returns a warning for each name and
+// type whose records aren't the same in all of them. SOA records, which
+// each provider has its own, are not compared.
+func Check(domain string, zones []Zone) []string {
+	if len(zones) < 2 {
+		return nil
+	}
+	sets := make([]map[models.RecordKey][]string, len(zones))
+	keys := map[models.RecordKey]bool{}
+	for i, z := range zones {
+		sets[i] = map[models.RecordKey][]string{}
+		for _, rc := range z.Records {
+			if rc.Type == "SOA" {
+				continue
+			}
+			k := rc.Key()
+			k.NameFQDN = strings.ToLower(k.NameFQDN)
+			sets[i][k] = append(sets[i][k], fmt.Sprintf("%s ttl=%d", rc.GetTargetCombined(), rc.TTL))
+			keys[k] = true
+		}
+	}
+
+	var warnings []string
+	for _, k := range sortedKeys(keys) {
+		var have []string
+		same := true
+		for i, z := range zones {
+			set := sets[i][k]
+			sort.Strings(set)
+			if i > 0 && !equal(set, sets[0][k]) {
+				same = false
+			}
+			if len(set) == 0 {
+				have = append(have, fmt.Sprintf("%s has none", z.Provider))
+			} else {
+				have = append(have, fmt.Sprintf("%s has [%s]", z.Provider, strings.Join(set, ", ")))
+			}
+		}
+		if !same {
+			warnings = append(warnings, fmt.Sprintf("%s: %s %s differs: %s", domain, k.NameFQDN, k.Type, strings.Join(have, "; ")))
+		}
+	}
+	return warnings
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[models.RecordKey]bool) []models.RecordKey {
+	keys := make([]models.RecordKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].NameFQDN != keys[j].NameFQDN {
+			return keys[i].NameFQDN < keys[j].NameFQDN
+		}
+		return keys[i].Type < keys[j].Type
+	})
+	return keys
+}
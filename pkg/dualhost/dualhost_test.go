@@ -0,0 +1,63 @@
+package dualhost
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func makeRC(label, rtype, target string, ttl uint32) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: rtype, TTL: ttl}
+	rc.SetLabel(label, "example.com")
+	rc.SetTarget(target)
+	return rc
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		zones []Zone
+		want  []string
+	}{
+		{
+			name: "same",
+			zones: []Zone{
+				{"A", models.Records{makeRC("www", "A", "1.2.3.4", 300), makeRC("www", "A", "1.2.3.5", 300)}},
+				{"B", models.Records{makeRC("www", "A", "1.2.3.5", 300), makeRC("www", "A", "1.2.3.4", 300)}},
+			},
+		},
+		{
+			name: "one zone",
+			zones: []Zone{
+				{"A", models.Records{makeRC("www", "A", "1.2.3.4", 300)}},
+			},
+		},
+		{
+			name: "soa ignored",
+			zones: []Zone{
+				{"A", models.Records{makeRC("@", "SOA", "ns1.example.net.", 300)}},
+				{"B", nil},
+			},
+		},
+		{
+			name: "ttl and missing",
+			zones: []Zone{
+				{"A", models.Records{makeRC("www", "A", "1.2.3.4", 60), makeRC("@", "NS", "ns1.example.net.", 300)}},
+				{"B", models.Records{makeRC("www", "A", "1.2.3.4", 300)}},
+			},
+			want: []string{
+				"example.com: example.com NS differs: A has [ns1.example.net. ttl=300]; B has none",
+				"example.com: www.example.com A differs: A has [1.2.3.4 ttl=60]; B has [1.2.3.4 ttl=300]",
+			},
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			got := Check("example.com", tst.zones)
+			if !reflect.DeepEqual(got, tst.want) {
+				t.Errorf("got %q, want %q", got, tst.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Package glue manages the host objects of a domain at its registrar.
+//
+// A nameserver in the domain that it serves (ns1.example.com for
+// example.com) can only be found with the addresses that the parent
+// zone publishes for it, the glue records. Registries keep them in host
+// objects, which the registrar creates and updates. They are declared
+// with the addresses of NAMESERVER():
+//
+//	D("example.com", REG,
+//	    NAMESERVER("ns1.example.com.", "192.0.2.1", "2001:db8::1"),
+//	...
+//
+// Registrars that can manage host objects implement
+// providers.HostCorrector. They report the addresses of the host objects
+// and a function that sets them to Corrections, which compares them
+// with dnsconfig.js. Host objects that dnsconfig.js doesn't mention are
+// left alone, because other domains may be delegated to them.
+package glue
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Desired returns the host objects that dc declares: the sorted glue
+// addresses of each nameserver, by name (lowercase, without the
+// trailing dot).
+func Desired(dc *models.DomainConfig) map[string][]string {
+	desired := map[string][]string{}
+	for _, ns := range dc.Nameservers {
+		if len(ns.Glue) == 0 {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSuffix(ns.Name, "."))
+		ips := append([]string(nil), ns.Glue...)
+		sort.Strings(ips)
+		desired[name] = ips
+	}
+	return desired
+}
+
+// Hosts returns the names of the host objects that dc declares, in
+// order.
+func Hosts(dc *models.DomainConfig) []string {
+	desired := Desired(dc)
+	hosts := make([]string, 0, len(desired))
+	for host := range desired {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// Corrections returns the corrections that create the host objects of
+// dc that aren't in current and update those whose addresses differ.
+// current maps the names of the host objects that exist to their
+// addresses. set creates (if create is true) or updates a host object.
+func Corrections(dc *models.DomainConfig, current map[string][]string, set func(host string, ips []string, create bool) error) []*models.Correction {
+	desired := Desired(dc)
+	var corrections []*models.Correction
+	for _, host := range Hosts(dc) {
+		want := desired[host]
+		have, exists := current[host]
+		have = append([]string(nil), have...)
+		sort.Strings(have)
+		if exists && strings.Join(have, ",") == strings.Join(want, ",") {
+			continue
+		}
+		host := host
+		if !exists {
+			corrections = append(corrections, &models.Correction{
+				Msg:  fmt.Sprintf("Create host %s (%s)", host, strings.Join(want, ", ")),
+				Kind: models.CorrectionCreate,
+				F:    func() error { return set(host, want, true) },
+			})
+			continue
+		}
+		corrections = append(corrections, &models.Correction{
+			Msg:  fmt.Sprintf("Update host %s (%s) -> (%s)", host, strings.Join(have, ", "), strings.Join(want, ", ")),
+			Kind: models.CorrectionModify,
+			F:    func() error { return set(host, want, false) },
+		})
+	}
+	return corrections
+}
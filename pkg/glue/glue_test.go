@@ -0,0 +1,48 @@
+package glue
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestCorrections(t *testing.T) {
+	dc := &models.DomainConfig{Name: "example.com", Nameservers: []*models.Nameserver{
+		{Name: "ns1.example.com", Glue: []string{"2001:db8::1", "192.0.2.1"}},
+		{Name: "NS2.example.com.", Glue: []string{"192.0.2.2"}},
+		{Name: "ns3.example.com", Glue: []string{"192.0.2.3"}},
+		{Name: "ns.example.net"},
+	}}
+	current := map[string][]string{
+		"ns1.example.com":   {"192.0.2.1", "2001:db8::1"},
+		"ns3.example.com":   {"192.0.2.30"},
+		"other.example.com": {"192.0.2.9"},
+	}
+	set := map[string]bool{}
+	corrections := Corrections(dc, current, func(host string, ips []string, create bool) error {
+		set[host] = create
+		return nil
+	})
+
+	var msgs []string
+	for _, c := range corrections {
+		msgs = append(msgs, c.Msg)
+		if err := c.F(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{
+		"Create host ns2.example.com (192.0.2.2)",
+		"Update host ns3.example.com (192.0.2.30) -> (192.0.2.3)",
+	}
+	if !reflect.DeepEqual(msgs, want) {
+		t.Errorf("got %q, want %q", msgs, want)
+	}
+	if !reflect.DeepEqual(set, map[string]bool{"ns2.example.com": true, "ns3.example.com": false}) {
+		t.Errorf("got set %v", set)
+	}
+	if corrections[0].Kind != models.CorrectionCreate || corrections[1].Kind != models.CorrectionModify {
+		t.Errorf("got kinds %v, %v", corrections[0].Kind, corrections[1].Kind)
+	}
+}
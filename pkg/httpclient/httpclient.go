@@ -0,0 +1,114 @@
+// Package httpclient is the HTTP transport that the providers use to
+// talk to their APIs. Each request is
+//
+//   - counted for `preview --report stats` (see pkg/apistats),
+//   - traced with an OpenTelemetry span (see EnableTracing),
+//   - and, if EnableMetrics was called, recorded in Prometheus metrics.
+//
+// Install() puts the transport in front of http.DefaultTransport, which
+// covers the providers that use http.DefaultClient or an http.Client
+// without a Transport. Providers that need their own transport (or
+// whose library builds one) use Wrap() or Send(). dnscontrol only calls
+// Install() when a flag asks for the statistics, the traces or the
+// metrics; until then, the requests aren't instrumented.
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/apistats"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the OpenTelemetry instrumentation.
+const tracerName = "github.com/StackExchange/dnscontrol/v3/pkg/httpclient"
+
+// defaultTransport is http.DefaultTransport before Install().
+var defaultTransport = http.DefaultTransport
+
+var (
+	installOnce sync.Once
+	installed   bool
+)
+
+// Install puts the instrumented transport in front of
+// http.DefaultTransport, and turns on Wrap and Send. It must be called
+// before the providers are created. It may be called more than once.
+func Install() {
+	installOnce.Do(func() {
+		installed = true
+		http.DefaultTransport = Wrap(defaultTransport)
+	})
+}
+
+// Installed reports whether Install was called.
+func Installed() bool {
+	return installed
+}
+
+// Wrap returns a transport that instruments the requests it sends with
+// base (Go's default transport if nil). Before Install, it returns base.
+func Wrap(base http.RoundTripper) http.RoundTripper {
+	if !installed {
+		return base
+	}
+	if base == nil {
+		base = defaultTransport
+	}
+	return &transport{base: &apistats.Transport{Base: base}}
+}
+
+// Send sends req with send, instrumented as Wrap's transport does, for
+// the libraries whose transport can't be wrapped but that let the
+// caller intercept the requests. Before Install, it just calls send.
+func Send(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	return Wrap(roundTripperFunc(send)).RoundTrip(req)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper. The span and the metrics
+// only record the method and the host, as the path and the query of
+// some APIs contain credentials.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), "HTTP "+req.Method+" "+host,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("net.peer.name", host),
+		))
+	defer span.End()
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	elapsed := time.Since(start)
+
+	code := "error"
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		code = strconv.Itoa(resp.StatusCode)
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	observe(host, req.Method, code, elapsed)
+	return resp, err
+}
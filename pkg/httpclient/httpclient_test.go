@@ -0,0 +1,58 @@
+package httpclient
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-down" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	var spans bytes.Buffer
+	shutdown, err := EnableTracing(&spans)
+	if err != nil {
+		t.Fatal(err)
+	}
+	EnableMetrics()
+
+	if Wrap(nil) != nil {
+		t.Error("Wrap() instruments the requests before Install()")
+	}
+	Install()
+	client := &http.Client{Transport: Wrap(nil)}
+	for _, path := range []string{"/zones", "/zones?key=secret", "/slow-down"} {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if err := shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := getMetrics()
+	host := "127.0.0.1"
+	if got := testutil.ToFloat64(m.requests.WithLabelValues(host, "GET", "200")); got != 2 {
+		t.Errorf("got %v requests with status 200, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.throttled.WithLabelValues(host)); got != 1 {
+		t.Errorf("got %v throttled requests, want 1", got)
+	}
+	if got := strings.Count(spans.String(), `"Name":"HTTP GET 127.0.0.1"`); got != 3 {
+		t.Errorf("got %d spans, want 3:\n%s", got, spans.String())
+	}
+	if strings.Contains(spans.String(), "secret") {
+		t.Errorf("the spans contain the query:\n%s", spans.String())
+	}
+}
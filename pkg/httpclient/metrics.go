@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// metrics are the Prometheus metrics of the requests.
+type metrics struct {
+	registry  *prometheus.Registry
+	requests  *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	throttled *prometheus.CounterVec
+}
+
+var (
+	metricsMu sync.Mutex
+	current   *metrics // nil until EnableMetrics()
+)
+
+// EnableMetrics starts recording the Prometheus metrics of the
+// requests:
+//
+//	dnscontrol_http_requests_total{host, method, code}
+//	dnscontrol_http_request_duration_seconds{host, method}
+//	dnscontrol_http_throttled_total{host}
+//
+// code is "error" for the requests that got no response. The metrics
+// are exported by ServeMetrics() or PushMetrics().
+func EnableMetrics() {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if current != nil {
+		return
+	}
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dnscontrol_http_requests_total",
+			Help: "Number of HTTP requests sent to the APIs of the providers.",
+		}, []string{"host", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "dnscontrol_http_request_duration_seconds",
+			Help:    "Duration of the HTTP requests sent to the APIs of the providers.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"host", "method"}),
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dnscontrol_http_throttled_total",
+			Help: "Number of HTTP requests that the APIs answered with status 429 (Too Many Requests).",
+		}, []string{"host"}),
+	}
+	m.registry.MustRegister(m.requests, m.duration, m.throttled)
+	current = m
+}
+
+func getMetrics() *metrics {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	return current
+}
+
+// observe records a request in the metrics, if they are enabled.
+func observe(host, method, code string, elapsed time.Duration) {
+	m := getMetrics()
+	if m == nil {
+		return
+	}
+	m.requests.WithLabelValues(host, method, code).Inc()
+	m.duration.WithLabelValues(host, method).Observe(elapsed.Seconds())
+	if code == "429" {
+		m.throttled.WithLabelValues(host).Inc()
+	}
+}
+
+// ServeMetrics serves the metrics at http://addr/metrics until the
+// process exits, for the commands that keep running (watch, serve).
+// It returns once it listens.
+func ServeMetrics(addr string) error {
+	m := getMetrics()
+	if m == nil {
+		return fmt.Errorf("metrics are not enabled")
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	go http.Serve(ln, mux)
+	return nil
+}
+
+// PushMetrics sends the metrics to the Prometheus Pushgateway at url,
+// for the commands that exit (preview, push in CI).
+func PushMetrics(url string) error {
+	m := getMetrics()
+	if m == nil {
+		return fmt.Errorf("metrics are not enabled")
+	}
+	// The Pushgateway isn't instrumented itself.
+	client := &http.Client{Transport: defaultTransport}
+	return push.New(url, "dnscontrol").Gatherer(m.registry).Client(client).Push()
+}
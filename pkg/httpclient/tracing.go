@@ -0,0 +1,30 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// EnableTracing exports the OpenTelemetry spans of the requests to w,
+// as JSON objects. Until it is called, the spans go nowhere. Call the
+// returned function before exiting; it writes the remaining spans.
+func EnableTracing(w io.Writer) (shutdown func() error, err error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "dnscontrol"))),
+	)
+	otel.SetTracerProvider(tp)
+	return func() error {
+		return tp.Shutdown(context.Background())
+	}, nil
+}
@@ -0,0 +1,150 @@
+package js
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/robertkrimen/otto"
+	"gopkg.in/yaml.v3"
+)
+
+// dataFile returns the path of the data file that fn was asked to
+// load. Like with require_glob(), a relative path is relative to the
+// file being run.
+func dataFile(call otto.FunctionCall, fn string) string {
+	if len(call.ArgumentList) < 1 || !call.Argument(0).IsString() {
+		throw(call.Otto, fn+" requires a file name")
+	}
+	file := call.Argument(0).String()
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(currentDirectory, file)
+	}
+	printer.Debugf("%s: %s\n", fn, file)
+	return filepath.ToSlash(filepath.Clean(file))
+}
+
+// toValue converts v to a JavaScript value. v must be something that
+// encoding/json can marshal.
+func toValue(call otto.FunctionCall, fn string, v interface{}) otto.Value {
+	data, err := json.Marshal(v)
+	if err != nil {
+		throw(call.Otto, fmt.Sprintf("%s: %s", fn, err))
+	}
+	value, err := call.Otto.Call("JSON.parse", nil, string(data))
+	if err != nil {
+		throw(call.Otto, fmt.Sprintf("%s: %s", fn, err))
+	}
+	return value
+}
+
+// requireYAML implements require_yaml(), which returns the contents of
+// a YAML file.
+func requireYAML(call otto.FunctionCall) otto.Value {
+	file := dataFile(call, "require_yaml")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		throw(call.Otto, fmt.Sprintf("File %s: %s", filepath.Base(file), err))
+	}
+	return toValue(call, "require_yaml", yamlToJSON(v))
+}
+
+// yamlToJSON converts the mappings with keys that aren't strings,
+// which JSON doesn't have, to mappings with string keys.
+func yamlToJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = yamlToJSON(e)
+		}
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = yamlToJSON(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = yamlToJSON(e)
+		}
+	}
+	return v
+}
+
+// requireCSV implements require_csv(), which returns the rows of a CSV
+// file. Each row is an object whose keys are the names in the first
+// row, or with {header: false}, an array of the fields.
+func requireCSV(call otto.FunctionCall) otto.Value {
+	file := dataFile(call, "require_csv")
+	header := true
+	comma := ','
+	if opts := call.Argument(1); opts.IsObject() {
+		if v, err := opts.Object().Get("header"); err == nil && v.IsDefined() {
+			header, _ = v.ToBoolean()
+		}
+		if v, err := opts.Object().Get("separator"); err == nil && v.IsDefined() {
+			s := v.String()
+			if utf8.RuneCountInString(s) != 1 {
+				throw(call.Otto, "require_csv: separator must be one character")
+			}
+			comma, _ = utf8.DecodeRuneInString(s)
+		}
+	} else if opts.IsDefined() {
+		throw(call.Otto, "require_csv: the second argument must be an object")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	rows, err := readCSV(data, comma, header)
+	if err != nil {
+		throw(call.Otto, fmt.Sprintf("File %s: %s", filepath.Base(file), err))
+	}
+	return toValue(call, "require_csv", rows)
+}
+
+// readCSV parses data. Lines that start with "#" are comments. With
+// header, each row is a map from the names in the first row to the
+// fields; without, it is the list of fields.
+func readCSV(data []byte, comma rune, header bool) ([]interface{}, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	r.Comma = comma
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+
+	rows := []interface{}{}
+	var names []string
+	for {
+		fields, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case !header:
+			rows = append(rows, fields)
+		case names == nil:
+			names = fields
+		default:
+			row := make(map[string]string, len(names))
+			for i, name := range names {
+				row[name] = fields[i]
+			}
+			rows = append(rows, row)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package js
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	data := []byte("\ufeffname;ip\n# a comment\nweb1; 10.2.3.1\n\"web;2\";10.2.3.2\n")
+
+	got, err := readCSV(data, ';', true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{
+		map[string]string{"name": "web1", "ip": "10.2.3.1"},
+		map[string]string{"name": "web;2", "ip": "10.2.3.2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got, err = readCSV(data, ';', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || !reflect.DeepEqual(got[0], []string{"name", "ip"}) {
+		t.Errorf("got %v, want the header as the first row", got)
+	}
+
+	if _, err := readCSV([]byte("name,ip\nweb1\n"), ',', true); err == nil {
+		t.Error("a row with missing fields should be an error")
+	}
+}
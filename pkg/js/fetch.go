@@ -0,0 +1,329 @@
+package js
+
+// This file implements the requests of FETCH(). The JavaScript side of
+// fetch() comes from ottoext; the requests are made here so that they
+// can be restricted to the URLs of --fetch-allow and cached.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/robertkrimen/otto"
+	"github.com/xddxdd/ottoext/fetch"
+	"github.com/xddxdd/ottoext/loop"
+)
+
+var logger = printer.Module("pkg.js")
+
+// FetchAllow lists the URLs that fetch() may request. An entry is a URL
+// prefix, such as "https://ipam.example.com/api/", or a host name,
+// which allows any https URL of the host. If empty, any URL is allowed.
+var FetchAllow []string
+
+// FetchCacheDir is the directory where the responses of fetch() are
+// kept. If empty, the responses are only kept in memory while the
+// configuration is run.
+var FetchCacheDir string
+
+// FetchCacheTTL is how long a response in FetchCacheDir is used instead
+// of making the request again. An older response is only used if the
+// request fails.
+var FetchCacheTTL = time.Hour
+
+// fetchClient makes the requests of fetch().
+var fetchClient = &http.Client{
+	Timeout: time.Minute,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return checkFetchAllowed(req.URL)
+	},
+}
+
+// fetchCache holds the responses of the GET requests made while the
+// configuration runs, so that requesting a URL twice gives the same
+// answer. It is reset by ExecuteJavascript.
+var fetchCache = struct {
+	sync.Mutex
+	m map[string]*fetchCacheEntry
+}{m: map[string]*fetchCacheEntry{}}
+
+type fetchCacheEntry struct {
+	once sync.Once
+	res  *fetchResponse
+	err  error
+}
+
+// fetchResponse is a response, as stored in the cache.
+type fetchResponse struct {
+	URL        string
+	Status     int
+	StatusText string
+	Header     http.Header
+	Body       []byte
+	Time       time.Time
+}
+
+// defineFetch defines fetch() in vm.
+func defineFetch(vm *otto.Otto, l *loop.Loop) error {
+	if err := fetch.Define(vm, l); err != nil {
+		return err
+	}
+	// Replace the function that ottoext uses to make the requests.
+	return vm.Set("__private__fetch_execute", func(call otto.FunctionCall) otto.Value {
+		t := &fetchTask{
+			jsRes: call.Argument(1).Object(),
+			cb:    call.Argument(2),
+		}
+		req, err := newFetchRequest(call.Argument(0).Object())
+		l.Add(t)
+		go func() {
+			defer l.Ready(t)
+			if err != nil {
+				t.err = err
+				return
+			}
+			t.res, t.err = doFetch(req)
+		}()
+		return otto.UndefinedValue()
+	})
+}
+
+// newFetchRequest converts the Request object of fetch() to a request.
+func newFetchRequest(jsReq *otto.Object) (*http.Request, error) {
+	method, err := jsReq.Get("method")
+	if err != nil {
+		return nil, err
+	}
+	u, err := jsReq.Get("url")
+	if err != nil {
+		return nil, err
+	}
+	var body io.Reader
+	if b, err := jsReq.Get("body"); err == nil && b.IsString() {
+		body = strings.NewReader(b.String())
+	}
+	req, err := http.NewRequest(method.String(), u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := jsReq.Get("headers")
+	if err != nil {
+		return nil, err
+	}
+	names, err := headers.Object().Get("_headers")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names.Object().Keys() {
+		v, err := headers.Object().Call("get", name)
+		if err == nil && !v.IsNull() && !v.IsUndefined() {
+			req.Header.Set(name, v.String())
+		}
+	}
+	return req, nil
+}
+
+// checkFetchAllowed returns an error if FetchAllow doesn't allow u.
+func checkFetchAllowed(u *url.URL) error {
+	if len(FetchAllow) == 0 {
+		return nil
+	}
+	for _, allowed := range FetchAllow {
+		if fetchAllows(allowed, u) {
+			return nil
+		}
+	}
+	return fmt.Errorf("fetch of %s is not allowed by --fetch-allow", u.Redacted())
+}
+
+// fetchAllows reports whether the entry allowed of FetchAllow allows u.
+func fetchAllows(allowed string, u *url.URL) bool {
+	if !strings.Contains(allowed, "://") {
+		allowed = "https://" + allowed + "/"
+	}
+	a, err := url.Parse(allowed)
+	if err != nil {
+		return false
+	}
+	if !strings.EqualFold(a.Scheme, u.Scheme) || !strings.EqualFold(a.Host, u.Host) {
+		return false
+	}
+	// The path must be in the allowed directory: "/api" allows "/api"
+	// and "/api/x", but not "/apix".
+	p, dir := u.EscapedPath(), strings.TrimSuffix(a.EscapedPath(), "/")
+	if strings.Contains(u.Path+"/", "/../") {
+		return false
+	}
+	return p == dir || strings.HasPrefix(p, dir+"/")
+}
+
+// doFetch makes req, or answers it from the cache.
+func doFetch(req *http.Request) (*fetchResponse, error) {
+	if err := checkFetchAllowed(req.URL); err != nil {
+		return nil, err
+	}
+	if req.Method != http.MethodGet {
+		return doRequest(req)
+	}
+
+	key := fetchCacheKey(req)
+	fetchCache.Lock()
+	e, ok := fetchCache.m[key]
+	if !ok {
+		e = &fetchCacheEntry{}
+		fetchCache.m[key] = e
+	}
+	fetchCache.Unlock()
+	e.once.Do(func() { e.res, e.err = doCachedFetch(req, key) })
+	return e.res, e.err
+}
+
+// doCachedFetch makes req, unless FetchCacheDir has a recent response.
+func doCachedFetch(req *http.Request, key string) (*fetchResponse, error) {
+	stored := readFetchCache(key)
+	if stored != nil && time.Since(stored.Time) < FetchCacheTTL {
+		logger.Debugf("fetch: %s from the cache of %s\n", req.URL.Redacted(), stored.Time.Format(time.RFC3339))
+		return stored, nil
+	}
+
+	res, err := doRequest(req)
+	if err == nil && res.Status >= 500 {
+		err = fmt.Errorf("fetch of %s: %s", req.URL.Redacted(), res.StatusText)
+	}
+	switch {
+	case err != nil && stored != nil:
+		logger.Warnf("%s; using the response of %s from the cache\n", err, stored.Time.Format(time.RFC3339))
+		return stored, nil
+	case err != nil && res == nil:
+		return nil, err
+	case res.Status == http.StatusOK:
+		writeFetchCache(key, res)
+	}
+	return res, nil
+}
+
+func doRequest(req *http.Request) (*fetchResponse, error) {
+	logger.Debugf("fetch: %s %s\n", req.Method, req.URL.Redacted())
+	resp, err := fetchClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &fetchResponse{
+		URL:        req.URL.String(),
+		Status:     resp.StatusCode,
+		StatusText: resp.Status,
+		Header:     resp.Header,
+		Body:       body,
+		Time:       time.Now(),
+	}, nil
+}
+
+// fetchCacheKey identifies the response to req. The headers are part
+// of the key because they can change the response, e.g. credentials.
+func fetchCacheKey(req *http.Request) string {
+	var names []string
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	key := req.URL.String()
+	for _, name := range names {
+		key += "\n" + name + ": " + strings.Join(req.Header[name], ", ")
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func readFetchCache(key string) *fetchResponse {
+	if FetchCacheDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(FetchCacheDir, key+".json"))
+	if err != nil {
+		return nil
+	}
+	res := &fetchResponse{}
+	if err := json.Unmarshal(data, res); err != nil {
+		logger.Warnf("fetch: ignoring the cache file %s: %s\n", key, err)
+		return nil
+	}
+	return res
+}
+
+func writeFetchCache(key string, res *fetchResponse) {
+	if FetchCacheDir == "" {
+		return
+	}
+	data, err := json.Marshal(res)
+	if err == nil {
+		err = os.MkdirAll(FetchCacheDir, 0o700)
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(FetchCacheDir, key+".json"), data, 0o600)
+	}
+	if err != nil {
+		logger.Warnf("fetch: can't cache %s: %s\n", res.URL, err)
+	}
+}
+
+// fetchTask passes the response of a request to the callback of
+// fetch() in the event loop.
+type fetchTask struct {
+	id    int64
+	jsRes *otto.Object
+	cb    otto.Value
+	res   *fetchResponse
+	err   error
+}
+
+func (t *fetchTask) SetID(id int64) { t.id = id }
+func (t *fetchTask) GetID() int64   { return t.id }
+func (t *fetchTask) Cancel()        {}
+
+func (t *fetchTask) Execute(vm *otto.Otto, l *loop.Loop) error {
+	if t.err != nil {
+		e, err := vm.Call(`new Error`, nil, t.err.Error())
+		if err != nil {
+			return err
+		}
+		_, err = t.cb.Call(otto.NullValue(), e)
+		return err
+	}
+
+	t.jsRes.Set("status", t.res.Status)
+	t.jsRes.Set("statusText", t.res.StatusText)
+	h, err := t.jsRes.Get("headers")
+	if err != nil {
+		return err
+	}
+	for k, vs := range t.res.Header {
+		for _, v := range vs {
+			if _, err := h.Object().Call("append", k, v); err != nil {
+				return err
+			}
+		}
+	}
+	t.jsRes.Set("_body", string(t.res.Body))
+	_, err = t.cb.Call(otto.NullValue())
+	return err
+}
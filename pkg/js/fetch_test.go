@@ -0,0 +1,112 @@
+package js
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchAllows(t *testing.T) {
+	tests := []struct {
+		allowed, url string
+		want         bool
+	}{
+		{"ipam.example.com", "https://ipam.example.com/api/v1", true},
+		{"ipam.example.com", "http://ipam.example.com/api/v1", false},
+		{"ipam.example.com", "https://ipam.example.com.evil.com/", false},
+		{"https://ipam.example.com/api/", "https://IPAM.example.com/api/v1?x=1", true},
+		{"https://ipam.example.com/api/", "https://ipam.example.com/api", true},
+		{"https://ipam.example.com/api", "https://ipam.example.com/apix", false},
+		{"https://ipam.example.com/api/", "https://ipam.example.com/api/../admin", false},
+		{"https://ipam.example.com/api/", "https://ipam.example.com/api/%2e%2e/admin", false},
+		{"https://ipam.example.com:8443/", "https://ipam.example.com/", false},
+	}
+	for _, tst := range tests {
+		u, err := url.Parse(tst.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fetchAllows(tst.allowed, u); got != tst.want {
+			t.Errorf("fetchAllows(%q, %q) = %v, want %v", tst.allowed, tst.url, got, tst.want)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/down" {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, "office %d", requests)
+	}))
+	defer server.Close()
+	defer func() {
+		EnableFetch, FetchAllow, FetchCacheDir = false, nil, ""
+	}()
+
+	// run runs a configuration that creates a TXT record with the text
+	// of each of the URLs, or the error. FETCH() would exit on errors.
+	run := func(urls ...string) (string, error) {
+		var fetches []string
+		for _, u := range urls {
+			fetches = append(fetches, fmt.Sprintf("fetch(%q).then(function(r) { return r.text(); }, function(e) { return e.message; })", u))
+		}
+		file := filepath.Join(t.TempDir(), "dnsconfig.js")
+		os.WriteFile(file, []byte(`var REG = NewRegistrar("none");
+Promise.all([`+strings.Join(fetches, ", ")+`]).then(function(texts) {
+  D("example.com", REG, TXT("@", texts));
+});`), 0o600)
+		conf, err := ExecuteJavascript(file, true, nil)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(conf.Domains[0].Records[0].TxtStrings, ","), nil
+	}
+
+	EnableFetch = true
+	got, err := run(server.URL+"/a", server.URL+"/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "office 1,office 1"; got != want {
+		t.Errorf("got %q, want %q from the same request", got, want)
+	}
+
+	FetchAllow = []string{server.URL + "/b/"}
+	if got, err = run(server.URL + "/a"); err != nil || !strings.Contains(got, "not allowed") {
+		t.Errorf("got %q, %v; want not allowed", got, err)
+	}
+
+	FetchCacheDir = t.TempDir()
+	FetchAllow = []string{server.URL}
+	if got, err = run(server.URL + "/b"); err != nil || got != "office 2" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if got, err = run(server.URL + "/b"); err != nil || got != "office 2" {
+		t.Errorf("got %q, %v; want the response from the cache", got, err)
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2", requests)
+	}
+
+	// A response older than the TTL is only used if the request fails.
+	defer func(ttl time.Duration) { FetchCacheTTL = ttl }(FetchCacheTTL)
+	FetchCacheTTL = 0
+	if got, err = run(server.URL + "/b"); err != nil || got != "office 3" {
+		t.Errorf("got %q, %v; want a new response", got, err)
+	}
+	req, _ := http.NewRequest("GET", server.URL+"/down", nil)
+	writeFetchCache(fetchCacheKey(req), &fetchResponse{Status: 200, Body: []byte("stale")})
+	if got, err = run(server.URL + "/down"); err != nil || got != "stale" {
+		t.Errorf("got %q, %v; want the stale response", got, err)
+	}
+}
@@ -21,6 +21,9 @@ var conf = {
 
 var defaultArgs = [];
 
+// IP_POOL() definitions, by name.
+var ipPools = {};
+
 function initialize() {
     conf = {
         registrars: [],
@@ -28,6 +31,7 @@ function initialize() {
         domains: [],
     };
     defaultArgs = [];
+    ipPools = {};
 }
 
 function _isDomain(d) {
@@ -257,6 +261,69 @@ function DefaultTTL(v) {
     };
 }
 
+// TTL_POLICY(rules): Set the default TTL of the records of each type.
+// Usage: TTL_POLICY({ MX: '1h', TXT: '5m', '*': 300 })
+function TTL_POLICY(rules) {
+    if (!_.isObject(rules) || _.isArray(rules) || _.isFunction(rules)) {
+        throw 'TTL_POLICY requires an object such as {MX: "1h", "*": 300}';
+    }
+    var policy = {};
+    for (var rtype in rules) {
+        var v = rules[rtype];
+        if (_.isString(v)) {
+            v = stringToDuration(v);
+        }
+        if (!_.isNumber(v) || v <= 0) {
+            throw 'TTL_POLICY: the TTL of ' + rtype + ' must be a positive duration';
+        }
+        policy[rtype.toUpperCase()] = v;
+    }
+    return function (d) {
+        d.ttl_policy = policy;
+    };
+}
+
+// CNAME_CHAINS(opts): Warn about, or flatten, the CNAMEs that lead to
+// other CNAMEs of the zone more than max_hops times.
+// Usage: CNAME_CHAINS({ max_hops: 1, action: 'flatten' })
+function CNAME_CHAINS(opts) {
+    opts = opts || {};
+    var maxHops = opts.max_hops === undefined ? 1 : opts.max_hops;
+    if (
+        !_.isNumber(maxHops) ||
+        maxHops < 1 ||
+        Math.floor(maxHops) != maxHops
+    ) {
+        throw 'CNAME_CHAINS: max_hops must be a positive integer';
+    }
+    var action = opts.action || 'warn';
+    if (action != 'warn' && action != 'flatten') {
+        throw 'CNAME_CHAINS: action must be "warn" or "flatten"';
+    }
+    return function (d) {
+        d.cname_chains = { max_hops: maxHops, action: action };
+    };
+}
+
+// ZONE_TAGS(tags): Set the tags (labels) of the hosted zone. The tags
+// of several ZONE_TAGS() are merged, so that DEFAULTS() can set the
+// tags of all the zones.
+// Usage: ZONE_TAGS({ team: 'dns', env: 'prod' })
+function ZONE_TAGS(tags) {
+    if (!_.isObject(tags) || _.isArray(tags) || _.isFunction(tags)) {
+        throw 'ZONE_TAGS requires an object such as {team: "dns"}';
+    }
+    for (var k in tags) {
+        if (!_.isString(tags[k])) {
+            throw 'ZONE_TAGS: the value of ' + k + ' must be a string';
+        }
+    }
+    return function (d) {
+        var merged = d.meta.zone_tags ? JSON.parse(d.meta.zone_tags) : {};
+        d.meta.zone_tags = JSON.stringify(_.extend(merged, tags));
+    };
+}
+
 function makeCAAFlag(value) {
     return function (record) {
         record.caaflag |= value;
@@ -281,9 +348,40 @@ function DnsProvider(name, nsCount) {
 // A(name,ip, recordModifiers...)
 var A = recordBuilder('A');
 
+// A_POOL(name,pool, recordModifiers...)
+function A_POOL(name, pool) {
+    var modifiers = Array.prototype.slice.call(arguments, 2);
+    return poolBuilder('A_POOL', A, 4, name, pool, modifiers);
+}
+
 // AAAA(name,ip, recordModifiers...)
 var AAAA = recordBuilder('AAAA');
 
+// AAAA_POOL(name,pool, recordModifiers...)
+function AAAA_POOL(name, pool) {
+    var modifiers = Array.prototype.slice.call(arguments, 2);
+    return poolBuilder('AAAA_POOL', AAAA, 6, name, pool, modifiers);
+}
+
+// poolBuilder returns a domain modifier that adds a record for each
+// address of the given IP version in the IP_POOL named pool.
+function poolBuilder(fname, builder, version, name, pool, modifiers) {
+    if (!_.has(ipPools, pool)) {
+        throw fname + ' ' + name + ': unknown IP_POOL "' + pool + '"';
+    }
+    var addrs = _.filter(ipPools[pool], function (ip) {
+        return _ipVersion(ip) === version;
+    });
+    if (addrs.length === 0) {
+        throw (
+            fname + ' ' + name + ': IP_POOL "' + pool + '" has no IPv' + version + ' addresses'
+        );
+    }
+    return _.map(addrs, function (ip) {
+        return builder.apply(null, [name, ip].concat(modifiers));
+    });
+}
+
 // AKAMAICDN(name, target, recordModifiers...)
 var AKAMAICDN = recordBuilder('AKAMAICDN');
 
@@ -368,7 +466,8 @@ function validateR53AliasType(value) {
 
 // CAA(name,tag,value, recordModifiers...)
 var CAA = recordBuilder('CAA', {
-    // TODO(tlim): It should be an error if value is not 0 or 128.
+    // The flags other than 0 and 128 (CAA_CRITICAL) are rejected by
+    // pkg/normalize.
     args: [
         ['name', _.isString],
         ['tag', _.isString],
@@ -406,9 +505,45 @@ var DS = recordBuilder('DS', {
     },
 });
 
+// HTTPS(name,priority,target,params, recordModifiers...)
+var HTTPS = recordBuilder('HTTPS', {
+    args: [
+        ['name', _.isString],
+        ['priority', _.isNumber],
+        ['target', _.isString],
+        ['params', _.isString],
+    ],
+    transform: function (record, args, modifiers) {
+        record.name = args.name;
+        record.svcpriority = args.priority;
+        record.target = args.target;
+        record.svcparams = args.params;
+    },
+});
+
 // PTR(name,target, recordModifiers...)
 var PTR = recordBuilder('PTR');
 
+// PTR_RANGE(cidr, hostFn, recordModifiers...)
+// Generates a PTR record for each address in cidr. hostFn(address, index)
+// returns the target, or a false value to skip the address.
+function PTR_RANGE(cidr, hostFn) {
+    var modifiers = Array.prototype.slice.call(arguments, 2);
+    if (!_.isFunction(hostFn)) {
+        throw 'PTR_RANGE ' + cidr + ': second argument must be a function that returns the target for an address';
+    }
+    var addrs = _addressesInCIDR(cidr);
+    return function (d) {
+        for (var i = 0; i < addrs.length; i++) {
+            var target = hostFn(addrs[i], i);
+            if (!target) {
+                continue;
+            }
+            PTR.apply(null, [addrs[i], target].concat(modifiers))(d);
+        }
+    };
+}
+
 // NAPTR(name,order,preference,flags,service,regexp,target, recordModifiers...)
 var NAPTR = recordBuilder('NAPTR', {
     args: [
@@ -431,6 +566,23 @@ var NAPTR = recordBuilder('NAPTR', {
     },
 });
 
+// RFC3597(name,type,rdata, recordModifiers...)
+// A record of a type that DNSControl doesn't know, in the generic format
+// of RFC 3597: type is 'TYPE65534' or 65534, rdata is '\\# 4 0a000001'
+// or the hex data alone.
+var RFC3597 = recordBuilder('RFC3597', {
+    args: [
+        ['name', _.isString],
+        ['type', function (x) { return _.isString(x) || _.isNumber(x); }],
+        ['target', _.isString],
+    ],
+    transform: function (record, args, modifiers) {
+        record.name = args.name;
+        record.type = _.isNumber(args.type) ? 'TYPE' + args.type : args.type.toUpperCase();
+        record.target = args.target;
+    },
+});
+
 // SOA(name,ns,mbox,refresh,retry,expire,minimum, recordModifiers...)
 var SOA = recordBuilder('SOA', {
     args: [
@@ -487,6 +639,22 @@ var SSHFP = recordBuilder('SSHFP', {
     },
 });
 
+// SVCB(name,priority,target,params, recordModifiers...)
+var SVCB = recordBuilder('SVCB', {
+    args: [
+        ['name', _.isString],
+        ['priority', _.isNumber],
+        ['target', _.isString],
+        ['params', _.isString],
+    ],
+    transform: function (record, args, modifiers) {
+        record.name = args.name;
+        record.svcpriority = args.priority;
+        record.target = args.target;
+        record.svcparams = args.params;
+    },
+});
+
 // name, usage, selector, matchingtype, certificate
 var TLSA = recordBuilder('TLSA', {
     args: [
@@ -505,6 +673,22 @@ var TLSA = recordBuilder('TLSA', {
     },
 });
 
+// URI(name,priority,weight,target, recordModifiers...)
+var URI = recordBuilder('URI', {
+    args: [
+        ['name', _.isString],
+        ['priority', _.isNumber],
+        ['weight', _.isNumber],
+        ['target', _.isString],
+    ],
+    transform: function (record, args, modifiers) {
+        record.name = args.name;
+        record.uripriority = args.priority;
+        record.uriweight = args.weight;
+        record.target = args.target;
+    },
+});
+
 function isStringOrArray(x) {
     return _.isString(x) || _.isArray(x);
 }
@@ -531,6 +715,93 @@ var TXT = recordBuilder('TXT', {
     },
 });
 
+// LOC(name,d1,m1,s1,ns,d2,m2,s2,ew,alt,siz,hp,vp, recordModifiers...)
+var LOC = recordBuilder('LOC', {
+    args: [
+        ['name', _.isString],
+        ['d1', _.isNumber], // latitude degrees
+        ['m1', _.isNumber], // latitude minutes
+        ['s1', _.isNumber], // latitude seconds
+        ['ns', _.isString], // 'N' or 'S'
+        ['d2', _.isNumber], // longitude degrees
+        ['m2', _.isNumber], // longitude minutes
+        ['s2', _.isNumber], // longitude seconds
+        ['ew', _.isString], // 'E' or 'W'
+        ['alt', _.isNumber], // altitude in meters
+        ['siz', _.isNumber], // size in meters
+        ['hp', _.isNumber], // horizontal precision in meters
+        ['vp', _.isNumber], // vertical precision in meters
+    ],
+    transform: function (record, args, modifiers) {
+        record.name = args.name;
+        record.target = '';
+        record.loclatitude = locEncodeDMS(
+            args.d1,
+            args.m1,
+            args.s1,
+            args.ns,
+            'N',
+            'S',
+            90
+        );
+        record.loclongitude = locEncodeDMS(
+            args.d2,
+            args.m2,
+            args.s2,
+            args.ew,
+            'E',
+            'W',
+            180
+        );
+        record.localtitude = locEncodeAltitude(args.alt);
+        record.locsize = locEncodeMeters(args.siz);
+        record.lochorizpre = locEncodeMeters(args.hp);
+        record.locvertpre = locEncodeMeters(args.vp);
+    },
+});
+
+// LOC records store latitude and longitude as thousandths of an
+// arcsecond, offset from 2^31 (the equator or prime meridian). See RFC 1876.
+var LOC_EQUATOR = Math.pow(2, 31);
+
+function locEncodeDMS(d, m, s, dir, pos, neg, max) {
+    dir = dir.toUpperCase();
+    if (dir !== pos && dir !== neg) {
+        throw 'LOC direction must be ' + pos + ' or ' + neg + ', not ' + dir;
+    }
+    if (!(d >= 0 && d <= max && m >= 0 && m < 60 && s >= 0 && s < 60)) {
+        throw 'LOC coordinate ' + d + ' ' + m + ' ' + s + ' is out of range';
+    }
+    var v = d * 3600000 + m * 60000 + Math.round(s * 1000);
+    if (v > max * 3600000) {
+        throw 'LOC coordinate ' + d + ' ' + m + ' ' + s + ' is out of range';
+    }
+    return dir === pos ? LOC_EQUATOR + v : LOC_EQUATOR - v;
+}
+
+// Altitude is stored in centimeters above a base of -100000 meters.
+function locEncodeAltitude(meters) {
+    if (!(meters >= -100000 && meters <= 42849672.95)) {
+        throw 'LOC altitude ' + meters + 'm is out of range';
+    }
+    return Math.round(meters * 100) + 10000000;
+}
+
+// Size and precision are stored in centimeters as a 4-bit mantissa and
+// 4-bit exponent.
+function locEncodeMeters(meters) {
+    if (!(meters >= 0 && meters <= 90000000)) {
+        throw 'LOC size/precision ' + meters + 'm is out of range';
+    }
+    var cm = Math.round(meters * 100);
+    var exp = 0;
+    while (cm >= 10 && exp < 9) {
+        cm = Math.floor(cm / 10);
+        exp++;
+    }
+    return (cm << 4) | exp;
+}
+
 // MX(name,priority,target, recordModifiers...)
 var MX = recordBuilder('MX', {
     args: [
@@ -548,13 +819,25 @@ var MX = recordBuilder('MX', {
 // NS(name,target, recordModifiers...)
 var NS = recordBuilder('NS');
 
-// NAMESERVER(name,target)
+// NAMESERVER(name, glue...)
+// The addresses are the glue records of a nameserver in the domain.
 function NAMESERVER(name) {
-    if (arguments.length != 1) {
-        throw 'NAMESERVER only accepts one argument for name.';
+    if (arguments.length < 1 || !_.isString(name)) {
+        throw 'NAMESERVER requires the name of the nameserver.';
+    }
+    var glue = [];
+    for (var i = 1; i < arguments.length; i++) {
+        if (!_.isString(arguments[i])) {
+            throw 'NAMESERVER: the glue addresses of ' + name + ' must be strings.';
+        }
+        glue.push(arguments[i]);
     }
     return function (d) {
-        d.nameservers.push({ name: name });
+        var ns = { name: name };
+        if (glue.length) {
+            ns.glue = glue;
+        }
+        d.nameservers.push(ns);
     };
 }
 
@@ -569,6 +852,7 @@ function NAMESERVER_TTL(v) {
 function format_tt(transform_table) {
     // Turn [[low: 1, high: 2, newBase: 3], [low: 4, high: 5, newIP: 6]]
     // into "1 ~ 2 ~ 3 ~; 4 ~ 5 ~  ~ 6"
+    // A row may use [cidr: "10.1.0.0/16", ...] instead of low and high.
     var lines = [];
     for (var i = 0; i < transform_table.length; i++) {
         var ip = transform_table[i];
@@ -592,16 +876,44 @@ function format_tt(transform_table) {
                 newBase = num2dot(newBase);
             }
         }
-        var row = [num2dot(ip.low), num2dot(ip.high), newBase, newIP];
+        var row = ip.cidr
+            ? [ip.cidr, '', newBase, newIP]
+            : [num2dot(ip.low), num2dot(ip.high), newBase, newIP];
         lines.push(row.join(' ~ '));
     }
     return lines.join(' ; ');
 }
 
-// IGNORE(name)
-function IGNORE(name) {
-    // deprecated, use IGNORE_NAME
-    return IGNORE_NAME(name);
+// TRANSFORM_IP6(cidr, newBase)
+// Returns a row of a transform table that rebases the IPv6 addresses in
+// cidr on newBase (an address or a list of addresses).
+function TRANSFORM_IP6(cidr, newBase) {
+    if (!_.isString(cidr) || cidr.indexOf(':') === -1 || cidr.indexOf('/') === -1) {
+        throw 'TRANSFORM_IP6: ' + cidr + ' is not an IPv6 CIDR range (Ex: "2001:db8::/64")';
+    }
+    return { cidr: cidr, newBase: newBase };
+}
+
+// IGNORE(labelPattern, rTypes, targetPattern)
+function IGNORE(labelPattern, rTypes, targetPattern) {
+    if (labelPattern === undefined || labelPattern === '') {
+        labelPattern = '*';
+    }
+    if (rTypes === undefined || rTypes === '') {
+        rTypes = '*';
+    }
+    if (targetPattern === undefined || targetPattern === '') {
+        // Without a target, IGNORE() is an alias of IGNORE_NAME(), as it
+        // always has been.
+        return IGNORE_NAME(labelPattern, rTypes);
+    }
+    return function (d) {
+        d.unmanaged.push({
+            label_pattern: labelPattern,
+            rType_pattern: rTypes,
+            target_pattern: targetPattern,
+        });
+    };
 }
 
 // IGNORE_NAME(name, rTypes)
@@ -660,6 +972,37 @@ function NO_PURGE(d) {
     d.KeepUnknown = true;
 }
 
+// PURGE_ONLY(types...)
+function PURGE_ONLY() {
+    var types = Array.prototype.slice.call(arguments);
+    return function (d) {
+        d.purge_only = (d.purge_only || []).concat(
+            types.map(function (t) {
+                return t.toUpperCase();
+            })
+        );
+    };
+}
+
+// KEEP(types...)
+function KEEP() {
+    var types = Array.prototype.slice.call(arguments);
+    return function (d) {
+        d.keep = (d.keep || []).concat(
+            types.map(function (t) {
+                return t.toUpperCase();
+            })
+        );
+    };
+}
+
+// MANAGED_BY(owner)
+function MANAGED_BY(owner) {
+    return function (d) {
+        d.managed_by = owner;
+    };
+}
+
 // AUTODNSSEC
 // Permitted values are:
 // ""  Do not modify the setting (the default)
@@ -725,6 +1068,9 @@ function getModifiers(args, start) {
  *        Take (record, args, modifier) as arguments. Any modifiers will be
  *        applied before this function. It should mutate the given record.
  * @param {function=} opts.applyModifier Function to apply modifiers to the record
+ * @param {boolean=} opts.zoneLevel The record applies to the whole zone
+ *        (its name is always '@'), therefore D_EXTEND() does not add the
+ *        subdomain to its name.
  */
 function recordBuilder(type, opts) {
     opts = _.defaults({}, opts, {
@@ -817,12 +1163,7 @@ function recordBuilder(type, opts) {
             opts.transform(record, parsedArgs, modifiers);
 
             // Handle D_EXTEND() with subdomains.
-            if (
-                d.subdomain &&
-                record.type != 'CF_REDIRECT' &&
-                record.type != 'CF_TEMP_REDIRECT' &&
-                record.type != 'CF_WORKER_ROUTE'
-            ) {
+            if (d.subdomain && !opts.zoneLevel) {
                 fqdn = [d.subdomain, d.name].join('.');
 
                 record.subdomain = d.subdomain;
@@ -894,6 +1235,29 @@ function IP(dot) {
     return ((((((+d[0]) * 256) + (+d[1])) * 256) + (+d[2])) * 256) + (+d[3]);
 }
 
+// IP_POOL(name, addresses)
+// Defines a named list of addresses for use with A_POOL() and AAAA_POOL().
+function IP_POOL(name, addresses) {
+    if (_.has(ipPools, name)) {
+        throw 'IP_POOL "' + name + '" is declared more than once';
+    }
+    if (!_.isArray(addresses) || addresses.length === 0) {
+        throw 'IP_POOL "' + name + '": addresses must be a non-empty list';
+    }
+    var pool = [];
+    for (var i = 0; i < addresses.length; i++) {
+        var ip = num2dot(addresses[i]);
+        if (!_ipVersion(ip)) {
+            throw 'IP_POOL "' + name + '": "' + ip + '" is not a valid IP address';
+        }
+        if (_.contains(pool, ip)) {
+            throw 'IP_POOL "' + name + '": "' + ip + '" is listed more than once';
+        }
+        pool.push(ip);
+    }
+    ipPools[name] = pool;
+}
+
 function num2dot(num) {
     if (num === undefined) {
         return '';
@@ -924,6 +1288,32 @@ var CF_PROXY_DEFAULT_ON = { cloudflare_proxy_default: 'on' };
 var CF_UNIVERSALSSL_OFF = { cloudflare_universalssl: 'off' };
 // UniversalSSL on for entire domain:
 var CF_UNIVERSALSSL_ON = { cloudflare_universalssl: 'on' };
+// CNAME flattening only at the apex for entire domain:
+var CF_CNAME_FLATTEN_AT_ROOT = { cloudflare_cname_flattening: 'flatten_at_root' };
+// CNAME flattening of all CNAMEs for entire domain:
+var CF_CNAME_FLATTEN_ALL = { cloudflare_cname_flattening: 'flatten_all' };
+
+// Google Cloud DNS routing policies:
+
+// GCLOUD_GEO(location): Serve the record to clients near a Google Cloud region.
+function GCLOUD_GEO(location) {
+    if (!_.isString(location) || location === '') {
+        throw 'GCLOUD_GEO requires a location, like "us-east1"';
+    }
+    return function (r) {
+        r.meta.gcloud_geo = location;
+    };
+}
+
+// GCLOUD_WRR(weight): Serve the record in proportion to its weight.
+function GCLOUD_WRR(weight) {
+    if (!_.isNumber(weight) && !_.isString(weight)) {
+        throw 'GCLOUD_WRR requires a weight';
+    }
+    return function (r) {
+        r.meta.gcloud_wrr = weight.toString();
+    };
+}
 
 // CUSTOM, PROVIDER SPECIFIC RECORD TYPES
 
@@ -943,6 +1333,7 @@ var CF_REDIRECT = recordBuilder('CF_REDIRECT', {
         record.name = '@';
         record.target = args.source + ',' + args.destination;
     },
+    zoneLevel: true,
 });
 
 var CF_TEMP_REDIRECT = recordBuilder('CF_TEMP_REDIRECT', {
@@ -954,6 +1345,7 @@ var CF_TEMP_REDIRECT = recordBuilder('CF_TEMP_REDIRECT', {
         record.name = '@';
         record.target = args.source + ',' + args.destination;
     },
+    zoneLevel: true,
 });
 
 var CF_WORKER_ROUTE = recordBuilder('CF_WORKER_ROUTE', {
@@ -965,6 +1357,73 @@ var CF_WORKER_ROUTE = recordBuilder('CF_WORKER_ROUTE', {
         record.name = '@';
         record.target = args.pattern + ',' + args.script;
     },
+    zoneLevel: true,
+});
+
+// CF_RULESET(name, rule) declares a WAF custom rule of the requests for
+// name, or a rate limiting rule if rule.rate_limit is set. The rule is
+// encoded as JSON; the Cloudflare provider validates it.
+var CF_RULESET = recordBuilder('CF_RULESET', {
+    args: [
+        ['name', _.isString],
+        ['rule', _.isObject],
+    ],
+    transform: function (record, args, modifiers) {
+        var rule = args.rule;
+        _.each(_.keys(rule), function (k) {
+            if (!_.contains(CF_RULESET_OPTIONS, k)) {
+                throw (
+                    'CF_RULESET: unknown option "' +
+                    k +
+                    '" (options: ' +
+                    CF_RULESET_OPTIONS.join(', ') +
+                    ')'
+                );
+            }
+        });
+        if (!_.isString(rule.action)) {
+            throw 'CF_RULESET requires an action';
+        }
+        var rateLimit = rule.rate_limit;
+        if (rateLimit !== undefined) {
+            if (
+                !_.isObject(rateLimit) ||
+                !_.isNumber(rateLimit.requests) ||
+                !_.isNumber(rateLimit.period)
+            ) {
+                throw 'CF_RULESET: rate_limit requires requests and period';
+            }
+            rateLimit = {
+                requests: rateLimit.requests,
+                period: rateLimit.period,
+                timeout: rateLimit.timeout || 0,
+            };
+        }
+        record.name = args.name;
+        record.target = JSON.stringify({
+            action: rule.action,
+            expression: rule.expression || '',
+            description: rule.description || '',
+            rate_limit: rateLimit,
+        });
+    },
+});
+
+var CF_RULESET_OPTIONS = ['action', 'expression', 'description', 'rate_limit'];
+
+// CF_SPECTRUM_APP(name, protocol, port, origin) proxies port of name, over
+// protocol ('tcp' or 'udp'), to origin ('host:port').
+var CF_SPECTRUM_APP = recordBuilder('CF_SPECTRUM_APP', {
+    args: [
+        ['name', _.isString],
+        ['protocol', _.isString],
+        ['port', _.isNumber],
+        ['origin', _.isString],
+    ],
+    transform: function (record, args, modifiers) {
+        record.name = args.name;
+        record.target = args.protocol + '/' + args.port + ',' + args.origin;
+    },
 });
 
 var URL = recordBuilder('URL');
@@ -972,6 +1431,22 @@ var URL301 = recordBuilder('URL301');
 var FRAME = recordBuilder('FRAME');
 var NS1_URLFWD = recordBuilder('NS1_URLFWD');
 var CLOUDNS_WR = recordBuilder('CLOUDNS_WR');
+var NETLIFY = recordBuilder('NETLIFY');
+var NETLIFYv6 = recordBuilder('NETLIFYv6');
+
+// LUA(name, type, script, recordModifiers...)
+var LUA = recordBuilder('LUA', {
+    args: [
+        ['name', _.isString],
+        ['type', _.isString],
+        ['script', _.isString],
+    ],
+    transform: function (record, args, modifiers) {
+        record.name = args.name;
+        record.luartype = args.type.toUpperCase();
+        record.target = args.script;
+    },
+});
 
 // SPF_BUILDER takes an object:
 // parts: The parts of the SPF record (to be joined with ' ').
@@ -982,6 +1457,8 @@ var CLOUDNS_WR = recordBuilder('CLOUDNS_WR');
 // flatten: A list of domains to be flattened.
 // overhead1: Amout of "buffer room" to reserve on the first item in the spf chain.
 // txtMaxSize: The maximum size for each TXT string. Values over 255 will result in multiple strings (default: '255')
+// cacheFile: The file that caches the DNS lookups done while flattening (default: 'spfcache.json')
+// lookupLimit: The maximum number of DNS lookups the published record may require.
 
 function SPF_BUILDER(value) {
     if (!value.parts || value.parts.length < 2) {
@@ -1025,6 +1502,14 @@ function SPF_BUILDER(value) {
         p.txtMaxSize = value.txtMaxSize;
     }
 
+    if (value.cacheFile) {
+        p.spfcache = value.cacheFile;
+    }
+
+    if (value.lookupLimit !== undefined) {
+        p.lookupLimit = String(value.lookupLimit);
+    }
+
     // Generate a TXT record with the metaparameters.
     if (value.ttl) {
         r.push(TXT(value.label, rawspf, p, TTL(value.ttl)));
@@ -1037,50 +1522,177 @@ function SPF_BUILDER(value) {
 
 // CAA_BUILDER takes an object:
 // label: The DNS label for the CAA record. (default: '@')
-// iodef: The contact mail address. (optional)
+// iodef: The contact mail address or URL, or a list of them. (optional)
 // iodef_critical: Boolean if sending report is required/critical. If not supported, certificate should be refused. (optional)
 // issue: List of CAs which are allowed to issue certificates for the domain (creates one record for each).
+// issue_critical: Boolean to set the critical flag on the issue records. (optional)
+// issue_profile: CAs of the profile catalog, joined by '+', to add to issue. (optional)
 // issuewild: Allowed CAs which can issue wildcard certificates for this domain. (creates one record for each)
+// issuewild_critical: Boolean to set the critical flag on the issuewild records. (optional)
+// issuewild_profile: Like issue_profile, for issuewild. (optional)
+
+// CAA_PROFILES are the issuer domain names of the CAs that
+// CAA_BUILDER's issue_profile and issuewild_profile can name.
+var CAA_PROFILES = {
+    amazon: ['amazon.com', 'amazontrust.com', 'awstrust.com', 'amazonaws.com'],
+    buypass: ['buypass.com'],
+    digicert: ['digicert.com'],
+    globalsign: ['globalsign.com'],
+    google: ['pki.goog'],
+    letsencrypt: ['letsencrypt.org'],
+    sectigo: ['sectigo.com'],
+    zerossl: ['sectigo.com'],
+};
+
+// caaIssuers returns the issuers of list ("none", or an array) followed
+// by those of the profiles of profile ("letsencrypt+google"), without
+// duplicates.
+function caaIssuers(list, profile, param) {
+    if (list == 'none') {
+        if (profile) {
+            throw (
+                'CAA_BUILDER: ' +
+                param +
+                ' "none" can not be used with ' +
+                param +
+                '_profile'
+            );
+        }
+        return [';'];
+    }
+    if (typeof list === 'string') {
+        list = [list];
+    }
+    var issuers = [];
+    function add(issuer) {
+        if (issuers.indexOf(issuer) == -1) issuers.push(issuer);
+    }
+    _.each(list || [], add);
+    if (profile) {
+        _.each(profile.split('+'), function (name) {
+            if (!_.has(CAA_PROFILES, name)) {
+                throw (
+                    'CAA_BUILDER: unknown ' +
+                    param +
+                    '_profile "' +
+                    name +
+                    '" (known: ' +
+                    _.keys(CAA_PROFILES).join(', ') +
+                    ')'
+                );
+            }
+            _.each(CAA_PROFILES[name], add);
+        });
+    }
+    return issuers;
+}
 
 function CAA_BUILDER(value) {
     if (!value.label) {
         value.label = '@';
     }
 
-    if (value.issue && value.issue == 'none') value.issue = [';'];
-    if (value.issuewild && value.issuewild == 'none') value.issuewild = [';'];
-
-    if (
-        (!value.issue && !value.issuewild) ||
-        (value.issue &&
-            value.issue.length == 0 &&
-            value.issuewild &&
-            value.issuewild.length == 0)
-    ) {
+    var issue = caaIssuers(value.issue, value.issue_profile, 'issue');
+    var issuewild = caaIssuers(
+        value.issuewild,
+        value.issuewild_profile,
+        'issuewild'
+    );
+    if (issue.length == 0 && issuewild.length == 0) {
         throw 'CAA_BUILDER requires at least one entry at issue or issuewild';
     }
 
     r = []; // The list of records to return.
 
-    if (value.iodef) {
-        if (value.iodef_critical) {
-            r.push(CAA(value.label, 'iodef', value.iodef, CAA_CRITICAL));
-        } else {
-            r.push(CAA(value.label, 'iodef', value.iodef));
-        }
+    function add(tag, values, critical) {
+        _.each(values, function (v) {
+            if (critical) {
+                r.push(CAA(value.label, tag, v, CAA_CRITICAL));
+            } else {
+                r.push(CAA(value.label, tag, v));
+            }
+        });
     }
 
-    if (value.issue)
-        for (var i = 0, len = value.issue.length; i < len; i++)
-            r.push(CAA(value.label, 'issue', value.issue[i]));
-
-    if (value.issuewild)
-        for (var i = 0, len = value.issuewild.length; i < len; i++)
-            r.push(CAA(value.label, 'issuewild', value.issuewild[i]));
+    var iodef = value.iodef || [];
+    if (typeof iodef === 'string') {
+        iodef = [iodef];
+    }
+    add('iodef', iodef, value.iodef_critical);
+    add('issue', issue, value.issue_critical);
+    add('issuewild', issuewild, value.issuewild_critical);
 
     return r;
 }
 
+// LOC_BUILDER_DD takes an object:
+// label: The DNS label for the LOC record (default: '@')
+// lat: Latitude in decimal degrees (negative is south)
+// lon: Longitude in decimal degrees (negative is west)
+// alt: Altitude in meters (default: 0)
+// siz, hp, vp: Size, horizontal and vertical precision in meters (defaults: 1, 10000, 10)
+// ttl: Input for TTL method (optional)
+function LOC_BUILDER_DD(value) {
+    if (!_.isNumber(value.lat) || !_.isNumber(value.lon)) {
+        throw 'LOC_BUILDER_DD requires numeric lat and lon';
+    }
+    var lat = locDecimalToDMS(value.lat);
+    var lon = locDecimalToDMS(value.lon);
+    return locBuild(
+        value,
+        lat.concat(value.lat < 0 ? 'S' : 'N'),
+        lon.concat(value.lon < 0 ? 'W' : 'E')
+    );
+}
+
+// LOC_BUILDER_DMS_STR takes an object:
+// label: The DNS label for the LOC record (default: '@')
+// str: Coordinates in degrees, minutes and seconds, such as
+//      '33°51′31″S 151°12′51″E' or '52 22 23.000 N 4 53 32.000 E'
+// alt: Altitude in meters (default: 0)
+// siz, hp, vp: Size, horizontal and vertical precision in meters (defaults: 1, 10000, 10)
+// ttl: Input for TTL method (optional)
+function LOC_BUILDER_DMS_STR(value) {
+    if (!_.isString(value.str)) {
+        throw 'LOC_BUILDER_DMS_STR requires str';
+    }
+    var f = value.str
+        .replace(/[°′″'"]/g, ' ')
+        .replace(/([NSEW])/gi, ' $1 ')
+        .trim()
+        .split(/[\s,]+/);
+    if (f.length !== 8) {
+        throw 'LOC_BUILDER_DMS_STR could not parse "' + value.str + '"';
+    }
+    return locBuild(
+        value,
+        [Number(f[0]), Number(f[1]), Number(f[2]), f[3]],
+        [Number(f[4]), Number(f[5]), Number(f[6]), f[7]]
+    );
+}
+
+// locDecimalToDMS converts decimal degrees to [degrees, minutes, seconds].
+function locDecimalToDMS(dd) {
+    var ms = Math.round(Math.abs(dd) * 3600000); // thousandths of an arcsecond
+    var d = Math.floor(ms / 3600000);
+    var m = Math.floor((ms % 3600000) / 60000);
+    var s = (ms % 60000) / 1000;
+    return [d, m, s];
+}
+
+function locBuild(value, lat, lon) {
+    var label = value.label || '@';
+    var alt = _.isNumber(value.alt) ? value.alt : 0;
+    var siz = _.isNumber(value.siz) ? value.siz : 1;
+    var hp = _.isNumber(value.hp) ? value.hp : 10000;
+    var vp = _.isNumber(value.vp) ? value.vp : 10;
+    var args = [label].concat(lat, lon, [alt, siz, hp, vp]);
+    if (value.ttl) {
+        args.push(TTL(value.ttl));
+    }
+    return LOC.apply(null, args);
+}
+
 // DMARC_BUILDER takes an object:
 // label: The DNS label for the DMARC record (_dmarc prefix is added; default: '@')
 // version: The DMARC version, by default DMARC1 (optional)
@@ -1116,11 +1728,7 @@ function DMARC_BUILDER(value) {
         value.policy = 'none';
     }
 
-    if (
-        !value.policy === 'none' ||
-        !value.policy === 'quarantine' ||
-        !value.policy === 'reject'
-    ) {
+    if (!_.contains(DMARC_POLICIES, value.policy)) {
         throw 'Invalid DMARC policy';
     }
 
@@ -1129,14 +1737,10 @@ function DMARC_BUILDER(value) {
     record.push('p=' + value.policy);
 
     // Subdomain policy
-    if (
-        !value.subdomainPolicy === 'none' ||
-        !value.subdomainPolicy === 'quarantine' ||
-        !value.subdomainPolicy === 'reject'
-    ) {
-        throw 'Invalid DMARC subdomain policy';
-    }
     if (value.subdomainPolicy) {
+        if (!_.contains(DMARC_POLICIES, value.subdomainPolicy)) {
+            throw 'Invalid DMARC subdomain policy';
+        }
         record.push('sp=' + value.subdomainPolicy);
     }
 
@@ -1171,23 +1775,33 @@ function DMARC_BUILDER(value) {
             case 's':
                 break;
             default:
-                throw 'Invalid DMARC DKIM alignment policy';
+                throw 'Invalid DMARC SPF alignment policy';
         }
         record.push('aspf=' + value.alignmentSPF);
     }
 
     // Percentage
-    if (value.percent) {
+    if (value.percent !== undefined) {
+        if (
+            !_.isNumber(value.percent) ||
+            value.percent % 1 !== 0 ||
+            value.percent < 0 ||
+            value.percent > 100
+        ) {
+            throw 'Invalid DMARC percent: must be a whole number from 0 to 100';
+        }
         record.push('pct=' + value.percent);
     }
 
     // Aggregate reports
     if (value.rua && value.rua.length > 0) {
+        checkDmarcURIs('rua', value.rua);
         record.push('rua=' + value.rua.join(','));
     }
 
     // Failure reports
     if (value.ruf && value.ruf.length > 0) {
+        checkDmarcURIs('ruf', value.ruf);
         record.push('ruf=' + value.ruf.join(','));
     }
 
@@ -1227,10 +1841,242 @@ function DMARC_BUILDER(value) {
         record.push('ri=' + value.reportInterval);
     }
 
+    // The record is checked again in Go (pkg/mailauth).
+    var meta = { builder: 'DMARC' };
     if (value.ttl) {
-        return TXT(label, record.join('; '), TTL(value.ttl));
+        return TXT(label, record.join('; '), meta, TTL(value.ttl));
+    }
+    return TXT(label, record.join('; '), meta);
+}
+
+var DMARC_POLICIES = ['none', 'quarantine', 'reject'];
+
+// checkDmarcURIs throws if a DMARC report target is not a mailto:
+// address or http(s) URL, optionally followed by a size limit (!10m).
+function checkDmarcURIs(tag, uris) {
+    for (var i = 0; i < uris.length; i++) {
+        if (
+            !/^(?:mailto:[^@,!\s]+@[^@,!\s]+|https?:\/\/[^,!\s]+)(?:![0-9]+[kmgt]?)?$/.test(
+                uris[i]
+            )
+        ) {
+            throw 'Invalid DMARC ' + tag + ' target: ' + uris[i];
+        }
+    }
+}
+
+// MTA_STS_BUILDER takes an object:
+// label: The DNS label of the mail domain (default: '@')
+// id: The id of the current policy, 1 to 32 letters and digits. Change it whenever the policy changes.
+// policyHost: The host that serves the policy file. mta-sts.<label> is made a CNAME to it. (optional)
+// tlsrpt: Array of SMTP TLS report targets (optional)
+// ttl: Input for TTL method
+function MTA_STS_BUILDER(value) {
+    if (!value) {
+        value = {};
+    }
+    if (!value.label) {
+        value.label = '@';
     }
-    return TXT(label, record.join('; '));
+    if (value.id === undefined) {
+        throw 'MTA_STS_BUILDER requires an id';
+    }
+    value.id = String(value.id);
+    if (!/^[a-zA-Z0-9]{1,32}$/.test(value.id)) {
+        throw 'Invalid MTA-STS id: must be 1 to 32 letters and digits';
+    }
+
+    function name(prefix) {
+        if (value.label === '@') {
+            return prefix;
+        }
+        return prefix + '.' + value.label;
+    }
+    function mods(meta) {
+        var m = meta ? [meta] : [];
+        if (value.ttl) {
+            m.push(TTL(value.ttl));
+        }
+        return m;
+    }
+
+    // The TXT records are checked again in Go (pkg/mailauth).
+    var r = []; // The list of records to return.
+    r.push(
+        TXT.apply(
+            null,
+            [name('_mta-sts'), 'v=STSv1; id=' + value.id].concat(
+                mods({ builder: 'MTA-STS' })
+            )
+        )
+    );
+
+    if (value.policyHost) {
+        r.push(
+            CNAME.apply(null, [name('mta-sts'), value.policyHost].concat(mods()))
+        );
+    }
+
+    if (value.tlsrpt && value.tlsrpt.length > 0) {
+        for (var i = 0; i < value.tlsrpt.length; i++) {
+            if (
+                !/^(?:mailto:[^@,!\s]+@[^@,!\s]+|https:\/\/[^,!\s]+)$/.test(
+                    value.tlsrpt[i]
+                )
+            ) {
+                throw 'Invalid TLSRPT target: ' + value.tlsrpt[i];
+            }
+        }
+        r.push(
+            TXT.apply(
+                null,
+                [
+                    name('_smtp._tls'),
+                    'v=TLSRPTv1; rua=' + value.tlsrpt.join(','),
+                ].concat(mods({ builder: 'TLSRPT' }))
+            )
+        );
+    }
+
+    return r;
+}
+
+// SRV_SERVICES are the services that SRV_BUILDER knows: the names
+// (_service._proto, and more labels if needed) and ports of their SRV
+// records.
+var SRV_SERVICES = {
+    'active-directory': [
+        ['_ldap._tcp', 389],
+        ['_ldap._tcp.dc._msdcs', 389],
+        ['_kerberos._tcp', 88],
+        ['_kerberos._udp', 88],
+        ['_kerberos._tcp.dc._msdcs', 88],
+        ['_kpasswd._tcp', 464],
+        ['_kpasswd._udp', 464],
+        ['_gc._tcp', 3268],
+    ],
+    autodiscover: [['_autodiscover._tcp', 443]],
+    caldavs: [['_caldavs._tcp', 443]],
+    carddavs: [['_carddavs._tcp', 443]],
+    imaps: [['_imaps._tcp', 993]],
+    minecraft: [['_minecraft._tcp', 25565]],
+    sip: [
+        ['_sip._udp', 5060],
+        ['_sip._tcp', 5060],
+        ['_sips._tcp', 5061],
+    ],
+    submission: [['_submission._tcp', 587]],
+    xmpp: [
+        ['_xmpp-client._tcp', 5222],
+        ['_xmpp-server._tcp', 5269],
+    ],
+};
+
+// SRV_BUILDER takes an object:
+// label: The DNS label under which the service is published (default: '@')
+// service: A service of SRV_SERVICES, or any other service name (with proto and port)
+// proto: The protocol ('tcp', 'udp'...) of a service that isn't in SRV_SERVICES
+// port: The port of the service (default: the port of SRV_SERVICES)
+// targets: The hosts that provide the service: hostnames, or objects
+//          {target, priority, weight, port}; 'none' if the service isn't available
+// priority: The default priority of the targets (default: 10)
+// weight: The default weight of the targets (default: 10)
+// ttl: Input for TTL method (optional)
+function SRV_BUILDER(value) {
+    if (!value || !value.service) {
+        throw 'SRV_BUILDER requires a service';
+    }
+    if (!value.label) {
+        value.label = '@';
+    }
+
+    var services = SRV_SERVICES[value.service];
+    if (value.proto) {
+        // Only one protocol, and possibly a service outside the catalog.
+        var name = '_' + value.service + '._' + value.proto;
+        var known = _.find(services || [], function (s) {
+            return s[0] === name;
+        });
+        services = [[name, known ? known[1] : undefined]];
+    } else if (!services) {
+        throw (
+            'SRV_BUILDER: unknown service "' +
+            value.service +
+            '" requires a proto and a port (known services: ' +
+            _.keys(SRV_SERVICES).join(', ') +
+            ')'
+        );
+    }
+    if (value.port !== undefined) {
+        services = _.map(services, function (s) {
+            return [s[0], value.port];
+        });
+    }
+    if (services[0][1] === undefined) {
+        throw 'SRV_BUILDER: service "' + value.service + '" requires a port';
+    }
+
+    function check(name, v) {
+        if (!_.isNumber(v) || v % 1 !== 0 || v < 0 || v > 65535) {
+            throw (
+                'SRV_BUILDER: ' + name + ' must be an integer from 0 to 65535'
+            );
+        }
+        return v;
+    }
+    var priority = check(
+        'priority',
+        value.priority === undefined ? 10 : value.priority
+    );
+    var weight = check(
+        'weight',
+        value.weight === undefined ? 10 : value.weight
+    );
+
+    var targets = value.targets;
+    if (targets === 'none') {
+        // RFC 2782: the service is decidedly not available.
+        targets = [{ target: '.', priority: 0, weight: 0, port: 0 }];
+    } else if (_.isString(targets)) {
+        targets = [targets];
+    }
+    if (!_.isArray(targets) || targets.length == 0) {
+        throw 'SRV_BUILDER requires targets, or "none"';
+    }
+
+    // The records are checked again in Go against the rules of
+    // pkg/rejectif.
+    var r = []; // The list of records to return.
+    _.each(services, function (service) {
+        var name = service[0];
+        if (value.label !== '@') {
+            name += '.' + value.label;
+        }
+        _.each(targets, function (t) {
+            if (_.isString(t)) {
+                t = { target: t };
+            }
+            if (!_.isString(t.target) || t.target === '') {
+                throw 'SRV_BUILDER: each target requires a hostname';
+            }
+            var args = [
+                name,
+                check(
+                    'priority',
+                    t.priority === undefined ? priority : t.priority
+                ),
+                check('weight', t.weight === undefined ? weight : t.weight),
+                check('port', t.port === undefined ? service[1] : t.port),
+                t.target,
+                { builder: 'SRV' },
+            ];
+            if (value.ttl) {
+                args.push(TTL(value.ttl));
+            }
+            r.push(SRV.apply(null, args));
+        });
+    });
+    return r;
 }
 
 // This is a no-op.  Long TXT records are handled natively now.
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,7 +15,6 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/pkg/transform"
 	"github.com/robertkrimen/otto"              // load underscore js into vm by default
 	_ "github.com/robertkrimen/otto/underscore" // required by otto
-	"github.com/xddxdd/ottoext/fetch"
 	"github.com/xddxdd/ottoext/loop"
 	"github.com/xddxdd/ottoext/promise"
 	"github.com/xddxdd/ottoext/timers"
@@ -32,7 +32,16 @@ var helpersJsFileName = "pkg/js/helpers.js"
 // far as require() is concerned, not the actual os.Getwd().
 var currentDirectory string
 
-// EnableFetch sets whether to enable fetch() in JS execution environment
+// loadStack lists the files currently being loaded by require() or
+// IMPORT(), outermost first. It is used to detect cycles.
+var loadStack []string
+
+// importCache holds the exports of each file loaded by IMPORT(), so
+// that each file is only run once.
+var importCache map[string]otto.Value
+
+// EnableFetch sets whether to enable fetch() in JS execution environment.
+// Setting FetchAllow enables it too.
 var EnableFetch bool = false
 
 // ExecuteJavascript accepts a javascript file and runs it, returning the resulting dnsConfig.
@@ -44,6 +53,9 @@ func ExecuteJavascript(file string, devMode bool, variables map[string]string) (
 
 	// Record the directory path leading up to this file.
 	currentDirectory = filepath.Dir(file)
+	loadStack = []string{filepath.Clean(file)}
+	importCache = map[string]otto.Value{}
+	fetchCache.m = map[string]*fetchCacheEntry{}
 
 	vm := otto.New()
 	l := loop.New(vm)
@@ -56,15 +68,20 @@ func ExecuteJavascript(file string, devMode bool, variables map[string]string) (
 	}
 
 	// only define fetch() when explicitly enabled
-	if EnableFetch {
-		if err := fetch.Define(vm, l); err != nil {
+	if EnableFetch || len(FetchAllow) > 0 {
+		if err := defineFetch(vm, l); err != nil {
 			return nil, err
 		}
 	}
 
 	vm.Set("require", require)
+	vm.Set("IMPORT", importModule)
 	vm.Set("REV", reverse)
-	vm.Set("glob", listFiles) // used for require_glob()
+	vm.Set("_addressesInCIDR", addressesInCIDR) // used for PTR_RANGE()
+	vm.Set("_ipVersion", ipVersion)             // used for IP_POOL()
+	vm.Set("glob", listFiles)                   // used for require_glob()
+	vm.Set("require_yaml", requireYAML)
+	vm.Set("require_csv", requireCSV)
 	vm.Set("PANIC", jsPanic)
 
 	// add cli variables to otto
@@ -79,7 +96,11 @@ func ExecuteJavascript(file string, devMode bool, variables map[string]string) (
 	}
 
 	// run user script
-	if err := l.Eval(script); err != nil {
+	src, err := transpile(string(script))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if err := l.Eval(src); err != nil {
 		return nil, err
 	}
 
@@ -133,6 +154,11 @@ func require(call otto.FunctionCall) otto.Value {
 		relFile = cleanFile
 	}
 
+	if err := pushLoadStack(cleanFile); err != nil {
+		throw(call.Otto, "require: "+err.Error())
+	}
+	defer popLoadStack()
+
 	// Record the old currentDirectory so that we can return there.
 	currentDirectoryOld := currentDirectory
 	// Record the directory path leading up to the file we're about to require.
@@ -153,7 +179,10 @@ func require(call otto.FunctionCall) otto.Value {
 		cmd := fmt.Sprintf(`JSON.parse(JSON.stringify(%s))`, string(data))
 		value, err = call.Otto.Run(cmd)
 	} else {
-		_, err = call.Otto.Run(string(data))
+		var src string
+		if src, err = transpile(string(data)); err == nil {
+			_, err = call.Otto.Run(src)
+		}
 	}
 
 	if err != nil {
@@ -166,6 +195,90 @@ func require(call otto.FunctionCall) otto.Value {
 	return value
 }
 
+// importModule implements IMPORT(). Unlike require(), the path is
+// always relative to the importing file, the file is run in its own
+// scope (like a nodejs module) and only once, and IMPORT() returns the
+// value of module.exports.
+func importModule(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 1 {
+		throw(call.Otto, "IMPORT takes exactly one argument")
+	}
+	file := call.Argument(0).String() // The filename as given by the user
+
+	cleanFile := filepath.Clean(file)
+	if !filepath.IsAbs(cleanFile) {
+		cleanFile = filepath.Clean(filepath.Join(currentDirectory, file))
+	}
+
+	if err := pushLoadStack(cleanFile); err != nil {
+		throw(call.Otto, "IMPORT: "+err.Error())
+	}
+	defer popLoadStack()
+
+	if v, ok := importCache[cleanFile]; ok {
+		return v
+	}
+
+	printer.Debugf("importing: %s (%s)\n", file, cleanFile)
+	// quick fix, by replacing to linux slashes, to make it work with windows paths too.
+	data, err := os.ReadFile(filepath.ToSlash(cleanFile))
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+
+	currentDirectoryOld := currentDirectory
+	currentDirectory = filepath.Dir(cleanFile)
+	defer func() { currentDirectory = currentDirectoryOld }()
+
+	var value otto.Value
+	if strings.HasSuffix(filepath.Ext(cleanFile), "json") {
+		value, err = call.Otto.Run(fmt.Sprintf(`JSON.parse(JSON.stringify(%s))`, string(data)))
+	} else {
+		// Run the file in its own scope. The function header is on the
+		// same line as the first line of the file so that line numbers
+		// in error messages are correct.
+		var fn otto.Value
+		var src string
+		if src, err = transpile(string(data)); err == nil {
+			fn, err = call.Otto.Run("(function (module, exports) {" + src + "\n})")
+		}
+		if err == nil {
+			var module *otto.Object
+			module, err = call.Otto.Object(`({exports: {}})`)
+			if err == nil {
+				exports, _ := module.Get("exports")
+				_, err = fn.Call(otto.NullValue(), module.Value(), exports)
+			}
+			if err == nil {
+				value, err = module.Get("exports")
+			}
+		}
+	}
+	if err != nil {
+		throw(call.Otto, fmt.Sprintf("File %s: %s", filepath.Base(cleanFile), err.Error()))
+	}
+
+	importCache[cleanFile] = value
+	return value
+}
+
+// pushLoadStack records that file is being loaded. It returns an error
+// if file is already being loaded, i.e. there is a cycle.
+func pushLoadStack(file string) error {
+	for i, f := range loadStack {
+		if f == file {
+			cycle := append(append([]string{}, loadStack[i:]...), file)
+			return fmt.Errorf("cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	loadStack = append(loadStack, file)
+	return nil
+}
+
+func popLoadStack() {
+	loadStack = loadStack[:len(loadStack)-1]
+}
+
 func listFiles(call otto.FunctionCall) otto.Value {
 	// Check amount of arguments provided
 	if !(len(call.ArgumentList) >= 1 && len(call.ArgumentList) <= 3) {
@@ -254,6 +367,38 @@ func listFiles(call otto.FunctionCall) otto.Value {
 	return value
 }
 
+func addressesInCIDR(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 1 {
+		throw(call.Otto, "PTR_RANGE requires a CIDR block")
+	}
+	addrs, err := transform.AddressesInCIDR(call.Argument(0).String())
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	v, err := call.Otto.ToValue(addrs)
+	if err != nil {
+		throw(call.Otto, fmt.Sprintf("converting value failed: %v", err.Error()))
+	}
+	return v
+}
+
+// ipVersion returns 4 or 6 if the argument is an IPv4 or IPv6
+// address, or 0 if it is not an IP address.
+func ipVersion(call otto.FunctionCall) otto.Value {
+	version := 0
+	if s := call.Argument(0).String(); call.Argument(0).IsString() {
+		if ip := net.ParseIP(s); ip != nil {
+			if ip.To4() != nil && !strings.Contains(s, ":") {
+				version = 4
+			} else {
+				version = 6
+			}
+		}
+	}
+	v, _ := otto.ToValue(version)
+	return v
+}
+
 func jsPanic(call otto.FunctionCall) otto.Value {
 	if len(call.ArgumentList) != 1 {
 		throw(call.Otto, "PANIC takes exactly one argument")
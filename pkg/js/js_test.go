@@ -151,3 +151,10 @@ func TestErrors(t *testing.T) {
 
 	}
 }
+
+func TestImportCycle(t *testing.T) {
+	for _, f := range []string{"cycle-a.js", "cycle-b.js"} {
+		_, err := ExecuteJavascript(filepath.Join(testDir, "importModules", f), true, nil)
+		testifyrequire.ErrorContains(t, err, "cycle detected")
+	}
+}
@@ -10,14 +10,14 @@ var REG2b = NewRegistrar("foo2b", {
     regmetakey: "reg2b"
 });
 var CF2b = NewDnsProvider("dns2b", {
-    dnsmetakey: "dns2b"
+    ignored_labels: ["dns2b"]
 });
 
 var REG3 = NewRegistrar("foo3", "MANUAL", {
     regmetakey: "reg3"
 });
 var CF3 = NewDnsProvider("dns3", "CLOUDFLAREAPI", {
-    dnsmetakey: "dns3"
+    ignored_labels: ["dns3"]
 });
 
 var REG1h = NewRegistrar("foo1h", "-");
@@ -27,5 +27,5 @@ var REG2bh = NewRegistrar("foo2bh", "-", {
     regmetakey: "reg2bh"
 });
 var CF2bh = NewDnsProvider("dns2bh", "-", {
-    dnsmetakey: "dns2bh"
+    ignored_labels: ["dns2bh"]
 });
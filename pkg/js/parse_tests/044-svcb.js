@@ -0,0 +1,4 @@
+D("foo.com","none",
+    HTTPS("@",1,".","alpn=h3,h2 port=443"),
+    SVCB("_8443._foo.api",2,"foo.com.","port=8443")
+);
@@ -0,0 +1,5 @@
+D("foo.com","none",
+    LOC("@",52,22,23.000,"N",4,53,32.000,"E",-2,0,0,0),
+    LOC_BUILDER_DD({label: "dd", lat: 51.5007, lon: -0.1246, alt: 10}),
+    LOC_BUILDER_DMS_STR({label: "dms", str: "33°51′31″S 151°12′51″E", ttl: 3600})
+);
@@ -0,0 +1,8 @@
+D("foo.com", "none"
+  , IGNORE("mylabel")
+  , IGNORE("mylabel2", "")
+  , IGNORE("mylabel3", "A,MX")
+  , IGNORE("", "A,MX")
+  , IGNORE("*", "CNAME", "*.acm-validations.aws.")
+  , IGNORE("**", "TXT", "google-site-verification=*")
+);
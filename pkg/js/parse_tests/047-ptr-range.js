@@ -0,0 +1,12 @@
+D(REV("10.1.2.0/24"), "none",
+  PTR_RANGE("10.1.2.0/30", function (ip, i) {
+    if (i === 0) { return null; }
+    return "host-" + ip.replace(/\./g, "-") + ".example.com.";
+  })
+);
+
+D(REV("2001:db8::/64"), "none",
+  PTR_RANGE("2001:db8::10/126", function (ip, i) {
+    return "pool" + i + ".example.com.";
+  }, TTL(3600))
+);
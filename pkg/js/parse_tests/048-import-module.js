@@ -0,0 +1,13 @@
+var zones = IMPORT('./importModules/zones.js');
+
+D("foo.com", "none",
+  zones.webServers(zones.settings.ttl)
+);
+
+// team-b.js declares its own domain.
+IMPORT('./importModules/team-b.js');
+
+// Modules only run once.
+if (IMPORT('importModules/zones.js') !== zones || zones.loaded !== 1) {
+  throw "module loaded more than once";
+}
@@ -0,0 +1,9 @@
+IP_POOL("web", ["1.2.3.4", "1.2.3.5", IP("1.2.3.6"), "2001:db8::1"]);
+IP_POOL("mail", ["10.0.0.1"]);
+
+D("foo.com", "none",
+  A_POOL("@", "web"),
+  A_POOL("www", "web", TTL(600)),
+  AAAA_POOL("www", "web"),
+  A_POOL("mx", "mail")
+);
@@ -0,0 +1,27 @@
+D("foo.com", "none",
+    DMARC_BUILDER({
+        policy: "reject",
+        subdomainPolicy: "quarantine",
+        percent: 50,
+        alignmentDKIM: "strict",
+        rua: ["mailto:dmarc@foo.com", "https://dmarc.example.com/submit"],
+        ruf: ["mailto:dmarc-failures@foo.com!10m"],
+        failureOptions: { SPF: false, DKIM: true },
+        reportInterval: "1h",
+    }),
+    DMARC_BUILDER({
+        label: "sub",
+        policy: "none",
+        ttl: 300,
+    }),
+    MTA_STS_BUILDER({
+        id: "20230101T000000",
+        policyHost: "mta-sts.example.net.",
+        tlsrpt: ["mailto:tlsrpt@foo.com"],
+    }),
+    MTA_STS_BUILDER({
+        label: "sub",
+        id: 2,
+        ttl: 600,
+    })
+);
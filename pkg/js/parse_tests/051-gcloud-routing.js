@@ -0,0 +1,6 @@
+D('foo.com', 'none',
+    A('www', '1.2.3.4', GCLOUD_GEO('us-east1')),
+    A('www', '1.2.3.5', GCLOUD_GEO('europe-west1')),
+    A('api', '1.2.3.6', GCLOUD_WRR(0.8)),
+    A('api', '1.2.3.7', GCLOUD_WRR('0.2'))
+);
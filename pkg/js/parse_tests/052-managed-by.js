@@ -0,0 +1,3 @@
+D('foo.com', 'none', MANAGED_BY('prod'),
+    A('www', '1.2.3.4')
+);
@@ -0,0 +1,3 @@
+D('foo.com', 'none', PURGE_ONLY('a', 'CNAME'), KEEP('TXT'),
+    A('www', '1.2.3.4')
+);
@@ -0,0 +1,6 @@
+var TRANSFORM_INT = [
+    { cidr: '10.1.0.0/16', newBase: '10.2.0.0' },
+    TRANSFORM_IP6('2001:db8::/64', ['fd00:1::', 'fd00:2::']),
+];
+D('foo2.com', 'reg', AAAA('www', '2001:db8::11'));
+D('foo.com', 'reg', IMPORT_TRANSFORM(TRANSFORM_INT, 'foo2.com', 60));
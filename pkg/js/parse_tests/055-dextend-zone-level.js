@@ -0,0 +1,12 @@
+var REG = NewRegistrar("Third-Party", "NONE");
+var CF = NewDnsProvider("Cloudflare", "CLOUDFLAREAPI");
+
+D("foo.com", REG, DnsProvider(CF));
+D_EXTEND("foo.com",
+    CF_REDIRECT("foo.com/*","https://goo.com/$1")
+);
+D_EXTEND("a.b.foo.com",
+    A("@","10.2.3.1"),
+    CF_TEMP_REDIRECT("a.b.foo.com/*","https://goo.com/$1"),
+    CF_WORKER_ROUTE("a.b.foo.com/api/*","api-worker")
+);
@@ -0,0 +1,17 @@
+var web = require_yaml("dataFiles/hosts.yaml");
+var db = require_csv("./dataFiles/hosts.csv");
+var dbNoHeader = require_csv("dataFiles/hosts.csv", {header: false});
+
+var records = [];
+var ptrs = [];
+web.hosts.concat(db).forEach(function(h) {
+  records.push(A(h.name, h.ip, TTL(web.ttl)));
+  ptrs.push(PTR(h.ip, h.name + ".foo.com."));
+});
+
+D("foo.com", "none", records);
+D(REV("10.2.0.0/16"), "none", ptrs);
+
+if (dbNoHeader.length !== 3 || dbNoHeader[0][1] !== "ip") {
+  throw "require_csv with {header: false} should return the first row";
+}
@@ -0,0 +1,4 @@
+D("example.com", "none",
+  NAMESERVER("ns1.example.com.", "192.0.2.1", "2001:db8::1"),
+  NAMESERVER("ns2.example.net.")
+);
@@ -0,0 +1,6 @@
+DEFAULTS(TTL_POLICY({ mx: "1h", TXT: "5m", "*": 600 }));
+D("foo.com", "none",
+  A("@", "1.2.3.4"),
+  MX("@", 10, "mail.foo.com."),
+  TXT("@", "hello", TTL(60))
+);
@@ -0,0 +1,9 @@
+const hosts = ["www", "api"];
+let records = hosts.map((h, i) => A(h, `1.2.3.${i + 10}`));
+const mx = (prio, targets) => targets.map(t => MX("@", prio, `${t}.foo.com.`));
+D("foo.com", "none",
+  records,
+  mx(10, ["mx1", "mx2"]),
+  TXT("@", `v=spf1 include:_spf.${hosts[1]}.foo.com
+    -all`.replace(/\s+/g, " "))
+);
@@ -0,0 +1,5 @@
+D("foo.com","none",
+    URI("_http._tcp",10,1,"https://www.foo.com/"),
+    URI("_ftp._tcp",10,1,"ftp://ftp.foo.com/Public"),
+    URI("_sip._udp",20,5,"sip:help@foo.com", TTL(600))
+);
@@ -0,0 +1,5 @@
+D("foo.com","none",
+    RFC3597("@","TYPE65534","\\# 4 0A000001"),
+    RFC3597("exp",65280,"\\# 6 0102 0304 0506"),
+    RFC3597("exp2","type65280","c0ffee", TTL(600))
+);
@@ -0,0 +1,4 @@
+D("foo.com","none",
+    LUA("www","A","ifportup(443, {'192.0.2.1', '192.0.2.2'})"),
+    LUA("geo","cname",";return 'www.' .. country() .. '.foo.com.'", TTL(60))
+);
@@ -0,0 +1,15 @@
+D("foo.com", "none",
+    CAA_BUILDER({
+        iodef: ["mailto:security@foo.com", "https://foo.com/caa-report"],
+        iodef_critical: true,
+        issue: ["example-ca.net"],
+        issue_profile: "letsencrypt+amazon+google",
+        issue_critical: true,
+        issuewild: "none",
+    }),
+    // issuewild only: any CA may issue the other certificates.
+    CAA_BUILDER({
+        label: "wild",
+        issuewild_profile: "letsencrypt+zerossl+sectigo",
+    })
+);
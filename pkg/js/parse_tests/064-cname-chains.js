@@ -0,0 +1,13 @@
+D("foo.com", "none",
+  CNAME_CHAINS({ action: "flatten" }),
+  CNAME("www", "web"),
+  CNAME("web", "lb"),
+  CNAME("lb", "lb.cdn.example.net."),
+  A("host", "1.2.3.4"),
+  CNAME("api", "host")
+);
+D("bar.com", "none",
+  CNAME_CHAINS({ max_hops: 2 }),
+  CNAME("www", "web"),
+  CNAME("web", "lb.cdn.example.net.")
+);
@@ -0,0 +1,23 @@
+D("foo.com", "none",
+    SRV_BUILDER({
+        service: "xmpp",
+        targets: ["xmpp1.foo.com.", { target: "xmpp2.foo.com.", priority: 20 }],
+    }),
+    SRV_BUILDER({
+        service: "minecraft",
+        label: "games",
+        port: 25566,
+        targets: "mc.foo.com.",
+        ttl: 600,
+    }),
+    SRV_BUILDER({
+        service: "sip",
+        proto: "tls",
+        port: 5061,
+        targets: [{ target: "sip.foo.com.", weight: 0 }],
+    }),
+    SRV_BUILDER({
+        service: "submission",
+        targets: "none",
+    })
+);
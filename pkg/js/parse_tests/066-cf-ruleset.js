@@ -0,0 +1,14 @@
+D("foo.com", "none",
+    CF_RULESET("www", {
+        action: "managed_challenge",
+        expression: 'http.request.uri.path eq "/wp-login.php"',
+        description: "No WordPress here",
+    }),
+    CF_RULESET("api", {
+        action: "block",
+        rate_limit: { requests: 100, period: 60, timeout: 600 },
+    })
+);
+D_EXTEND("sub.foo.com",
+    CF_RULESET("@", { action: "log" })
+);
@@ -0,0 +1,5 @@
+D("foo.com", "none",
+    CF_SPECTRUM_APP("ssh", "tcp", 22, "192.0.2.1:22"),
+    CF_SPECTRUM_APP("game", "udp", 27015, "origin.foo.com:27015"),
+    CF_SPECTRUM_APP("@", "tcp", 25, "[2001:db8::1]:2525")
+);
@@ -0,0 +1,6 @@
+DEFAULTS(ZONE_TAGS({ costcenter: "1234", env: "test" }));
+D("foo.com", "none",
+  ZONE_TAGS({ team: "dns", env: "prod" }),
+  A("@", "1.2.3.4")
+);
+D("bar.com", "none", {zone_tags: '{"team":"web"}'});
@@ -0,0 +1 @@
+IMPORT('./cycle-b.js');
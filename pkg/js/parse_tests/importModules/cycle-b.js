@@ -0,0 +1 @@
+IMPORT('./cycle-a.js');
@@ -0,0 +1,6 @@
+var zones = IMPORT('./zones.js');
+
+D("bar.com", "none",
+  zones.webServers(300),
+  CNAME("blog", "foo.com.")
+);
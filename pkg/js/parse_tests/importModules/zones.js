@@ -0,0 +1,17 @@
+// Variables declared here are private to this file.
+var loaded = 0;
+var settings = IMPORT('./settings.json');
+
+function webServers(ttl) {
+  return [
+    A("@", settings.web, TTL(ttl)),
+    A("www", settings.web, TTL(ttl)),
+  ];
+}
+
+loaded++;
+module.exports = {
+  loaded: loaded,
+  settings: settings,
+  webServers: webServers,
+};
@@ -0,0 +1,608 @@
+package js
+
+// The Otto interpreter only understands ES5. transpile() rewrites a
+// small subset of the newer syntax into ES5 before the file is run:
+//
+//   - let and const at the top level of the file or of a function
+//     become var (in a block, they would be scoped differently, so they
+//     are rejected);
+//   - template literals without a tag become string concatenations;
+//   - arrow functions whose parameters are plain names become function
+//     expressions, bound to this if the body uses it.
+//
+// Everything else is left as it is, for Otto to run or to report as a
+// syntax error. docs/code-tricks.md lists the subset; TestTranspileCorpus
+// runs the examples of the documentation and the test data through it.
+// The whitespace and comments are kept, so that the line numbers in
+// error messages match the file.
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokNumber
+	tokString
+	tokTemplate
+	tokRegex
+	tokPunct
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	pre  string // The whitespace and comments before the token.
+	text string
+	line int
+
+	// Template literals: the raw text around each ${...}, the nodes of
+	// the expressions and what comes before their closing brace.
+	quasis []string
+	exprs  [][]*node
+	trails []string
+}
+
+// node is a token, or a group of nodes between brackets.
+type node struct {
+	tok   *token
+	items []*node
+	close *token // The closing bracket of a group, nil for a token.
+}
+
+func (n *node) isGroup(open string) bool {
+	return n != nil && n.close != nil && n.tok.text == open
+}
+
+func (n *node) is(kind tokenKind, text string) bool {
+	return n != nil && n.close == nil && n.tok.kind == kind && n.tok.text == text
+}
+
+// transpile returns src rewritten in ES5. If src can't be tokenized, it
+// is returned as it is, so that Otto reports the syntax error.
+func transpile(src string) (string, error) {
+	l := &lexer{src: src, line: 1}
+	toks, eof, err := l.lexUntil("")
+	if err != nil {
+		return src, nil
+	}
+	nodes, err := group(toks)
+	if err != nil {
+		return src, nil
+	}
+	out, err := emit(nodes, true)
+	if err != nil {
+		return "", err
+	}
+	return out + eof.pre, nil
+}
+
+// lexer splits JavaScript source into tokens.
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	prev *token // The last token, to tell regular expressions from divisions.
+}
+
+var puncts = []string{
+	">>>=", "...", "===", "!==", "**=", "<<=", ">>=", ">>>",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>",
+}
+
+// keywords are the words after which an expression starts, rather than
+// continues.
+var keywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "debugger": true, "default": true, "delete": true,
+	"do": true, "else": true, "export": true, "extends": true,
+	"finally": true, "for": true, "function": true, "if": true,
+	"import": true, "in": true, "instanceof": true, "let": true, "new": true,
+	"of": true, "return": true, "switch": true, "throw": true, "try": true,
+	"typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"yield": true,
+}
+
+func (l *lexer) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", l.line, fmt.Sprintf(format, args...))
+}
+
+// lexUntil returns the tokens up to the closing brace close (the end of
+// a ${...} expression), or up to the end of the source if close is "".
+// The last token is returned separately.
+func (l *lexer) lexUntil(close string) ([]*token, *token, error) {
+	var toks []*token
+	depth := 0
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case t.kind == tokEOF:
+			if close != "" {
+				return nil, nil, l.errorf("unterminated template literal")
+			}
+			return toks, t, nil
+		case t.kind == tokPunct && t.text == "{":
+			depth++
+		case t.kind == tokPunct && t.text == "}" && close != "":
+			if depth == 0 {
+				return toks, t, nil
+			}
+			depth--
+		}
+		toks = append(toks, t)
+	}
+}
+
+func (l *lexer) next() (*token, error) {
+	start := l.pos
+	if err := l.skipTrivia(); err != nil {
+		return nil, err
+	}
+	t := &token{pre: l.src[start:l.pos], line: l.line}
+	if l.pos >= len(l.src) {
+		t.kind = tokEOF
+		return t, nil
+	}
+	begin := l.pos
+	c := l.src[l.pos]
+	switch {
+	case isIdentStart(l.peekRune()):
+		t.kind = tokIdent
+		for l.pos < len(l.src) && isIdentPart(l.peekRune()) {
+			l.pos += len(string(l.peekRune()))
+		}
+	case c >= '0' && c <= '9' || c == '.' && l.pos+1 < len(l.src) && l.src[l.pos+1] >= '0' && l.src[l.pos+1] <= '9':
+		t.kind = tokNumber
+		for l.pos < len(l.src) {
+			c := l.src[l.pos]
+			if (c == '+' || c == '-') && (l.src[l.pos-1] == 'e' || l.src[l.pos-1] == 'E') && !strings.HasPrefix(l.src[begin:], "0x") {
+				l.pos++
+				continue
+			}
+			if c != '.' && c != '_' && !isAlnum(c) {
+				break
+			}
+			l.pos++
+		}
+	case c == '"' || c == '\'':
+		t.kind = tokString
+		if err := l.skipString(c); err != nil {
+			return nil, err
+		}
+	case c == '`':
+		t.kind = tokTemplate
+		if err := l.lexTemplate(t); err != nil {
+			return nil, err
+		}
+	case c == '/' && l.regexAllowed():
+		t.kind = tokRegex
+		if err := l.skipRegex(); err != nil {
+			return nil, err
+		}
+	default:
+		t.kind = tokPunct
+		l.pos++
+		for _, p := range puncts {
+			if strings.HasPrefix(l.src[begin:], p) {
+				l.pos = begin + len(p)
+				break
+			}
+		}
+	}
+	t.text = l.src[begin:l.pos]
+	l.prev = t
+	return t, nil
+}
+
+func (l *lexer) peekRune() rune {
+	for _, r := range l.src[l.pos:] {
+		return r
+	}
+	return 0
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
+
+func isAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (l *lexer) skipTrivia() error {
+	for l.pos < len(l.src) {
+		switch {
+		case l.src[l.pos] == '\n':
+			l.line++
+			l.pos++
+		case l.src[l.pos] == ' ' || l.src[l.pos] == '\t' || l.src[l.pos] == '\r' || l.src[l.pos] == '\f' || l.src[l.pos] == '\v':
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "\u00a0"), strings.HasPrefix(l.src[l.pos:], "\ufeff"):
+			l.pos += len(string(l.peekRune()))
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return l.errorf("unterminated comment")
+			}
+			l.line += strings.Count(l.src[l.pos:l.pos+end+4], "\n")
+			l.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *lexer) skipString(quote byte) error {
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos++
+			if l.pos < len(l.src) && l.src[l.pos] == '\n' {
+				l.line++
+			}
+		case '\n':
+			return l.errorf("unterminated string")
+		case quote:
+			l.pos++
+			return nil
+		}
+	}
+	return l.errorf("unterminated string")
+}
+
+// regexAllowed tells whether a / starts a regular expression rather
+// than being a division.
+func (l *lexer) regexAllowed() bool {
+	p := l.prev
+	switch {
+	case p == nil:
+		return true
+	case p.kind == tokIdent:
+		return keywords[p.text]
+	case p.kind == tokPunct:
+		return p.text != ")" && p.text != "]" && p.text != "}"
+	}
+	return false
+}
+
+func (l *lexer) skipRegex() error {
+	inClass := false
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos++
+		case '\n':
+			return l.errorf("unterminated regular expression")
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				l.pos++
+				for l.pos < len(l.src) && isAlnum(l.src[l.pos]) {
+					l.pos++
+				}
+				return nil
+			}
+		}
+	}
+	return l.errorf("unterminated regular expression")
+}
+
+func (l *lexer) lexTemplate(t *token) error {
+	l.pos++
+	var quasi strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\\' && l.pos+1 < len(l.src):
+			if l.src[l.pos+1] == '\n' {
+				l.line++
+			}
+			quasi.WriteString(l.src[l.pos : l.pos+2])
+			l.pos += 2
+		case c == '`':
+			l.pos++
+			t.quasis = append(t.quasis, quasi.String())
+			return nil
+		case strings.HasPrefix(l.src[l.pos:], "${"):
+			l.pos += 2
+			t.quasis = append(t.quasis, quasi.String())
+			quasi.Reset()
+			l.prev = nil
+			toks, end, err := l.lexUntil("}")
+			if err != nil {
+				return err
+			}
+			nodes, err := group(toks)
+			if err != nil {
+				return err
+			}
+			t.exprs = append(t.exprs, nodes)
+			t.trails = append(t.trails, end.pre)
+		default:
+			if c == '\n' {
+				l.line++
+			}
+			quasi.WriteByte(c)
+			l.pos++
+		}
+	}
+	return l.errorf("unterminated template literal")
+}
+
+// group nests the tokens between brackets.
+func group(toks []*token) ([]*node, error) {
+	closing := map[string]string{"(": ")", "[": "]", "{": "}"}
+	stack := []*node{{}}
+	for _, t := range toks {
+		top := stack[len(stack)-1]
+		if t.kind != tokPunct {
+			top.items = append(top.items, &node{tok: t})
+			continue
+		}
+		switch t.text {
+		case "(", "[", "{":
+			n := &node{tok: t}
+			top.items = append(top.items, n)
+			stack = append(stack, n)
+		case ")", "]", "}":
+			if len(stack) == 1 || closing[top.tok.text] != t.text {
+				return nil, fmt.Errorf("line %d: unexpected %s", t.line, t.text)
+			}
+			top.close = t
+			stack = stack[:len(stack)-1]
+		default:
+			top.items = append(top.items, &node{tok: t})
+		}
+	}
+	if len(stack) != 1 {
+		top := stack[len(stack)-1]
+		return nil, fmt.Errorf("line %d: unclosed %s", top.tok.line, top.tok.text)
+	}
+	return stack[0].items, nil
+}
+
+// endsOperand tells whether n can end an operand, in which case a
+// following template literal is tagged.
+func endsOperand(n *node) bool {
+	switch {
+	case n == nil:
+		return false
+	case n.close != nil:
+		return n.tok.text != "{"
+	case n.tok.kind == tokIdent:
+		return !keywords[n.tok.text]
+	case n.tok.kind == tokPunct:
+		return false
+	}
+	return true
+}
+
+func usesThis(nodes []*node) bool {
+	for _, n := range nodes {
+		if n.is(tokIdent, "this") || usesThis(n.items) {
+			return true
+		}
+		for _, e := range n.tok.exprs {
+			if usesThis(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// emit returns the ES5 text of nodes. top tells whether nodes are the
+// statements of the file or of a function body, where let and const
+// may be declared.
+func emit(nodes []*node, top bool) (string, error) {
+	out := make([]string, len(nodes))
+	for i := 0; i < len(nodes); i++ {
+		n := nodes[i]
+		var prev *node
+		if i > 0 {
+			prev = nodes[i-1]
+		}
+		var err error
+		switch {
+		case i+1 < len(nodes) && nodes[i+1].is(tokPunct, "=>") && (n.close == nil && n.tok.kind == tokIdent || n.isGroup("(")):
+			var end int
+			out[i], end, err = emitArrow(nodes, i)
+			i = end - 1
+		case n.is(tokIdent, "function") && !prev.is(tokPunct, ".") &&
+			i+2 < len(nodes) && nodes[i+1].isGroup("(") && nodes[i+2].isGroup("{"):
+			out[i], err = emitFunction(n, "", nodes[i+1], nodes[i+2])
+			i += 2
+		case n.is(tokIdent, "function") && !prev.is(tokPunct, ".") &&
+			i+3 < len(nodes) && nodes[i+1].close == nil && nodes[i+1].tok.kind == tokIdent && nodes[i+2].isGroup("(") && nodes[i+3].isGroup("{"):
+			out[i], err = emitFunction(n, nodes[i+1].tok.pre+nodes[i+1].tok.text, nodes[i+2], nodes[i+3])
+			i += 3
+		case isLexicalDeclaration(nodes, i):
+			if !top {
+				return "", fmt.Errorf("line %d: %s is only supported at the top level of a file or a function, use var in a block", n.tok.line, n.tok.text)
+			}
+			out[i] = n.tok.pre + "var"
+		case n.close == nil && n.tok.kind == tokTemplate:
+			if endsOperand(prev) {
+				return "", fmt.Errorf("line %d: tagged template literals are not supported", n.tok.line)
+			}
+			out[i], err = emitTemplate(n.tok)
+		case n.close != nil:
+			var inner string
+			inner, err = emit(n.items, false)
+			out[i] = n.tok.pre + n.tok.text + inner + n.close.pre + n.close.text
+		default:
+			out[i] = n.tok.pre + n.tok.text
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(out, ""), nil
+}
+
+// isLexicalDeclaration tells whether nodes[i] starts a let or const
+// declaration.
+func isLexicalDeclaration(nodes []*node, i int) bool {
+	n := nodes[i]
+	if i > 0 && nodes[i-1].is(tokPunct, ".") {
+		return false
+	}
+	return (n.is(tokIdent, "const") || n.is(tokIdent, "let")) && i+1 < len(nodes) &&
+		nodes[i+1].close == nil && nodes[i+1].tok.kind == tokIdent && !keywords[nodes[i+1].tok.text]
+}
+
+// emitFunction emits a function whose body may declare let and const.
+func emitFunction(fn *node, name string, params, body *node) (string, error) {
+	ps, err := emit(params.items, false)
+	if err != nil {
+		return "", err
+	}
+	inner, err := emit(body.items, true)
+	if err != nil {
+		return "", err
+	}
+	return fn.tok.pre + fn.tok.text + name +
+		params.tok.pre + "(" + ps + params.close.pre + ")" +
+		body.tok.pre + "{" + inner + body.close.pre + "}", nil
+}
+
+// emitTemplate turns a template literal into a string concatenation.
+// The newlines in the template are kept, between the strings.
+func emitTemplate(t *token) (string, error) {
+	var b strings.Builder
+	b.WriteString(t.pre + "(")
+	for i, q := range t.quasis {
+		if i > 0 {
+			expr, err := emit(t.exprs[i-1], false)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(" + (" + expr + t.trails[i-1] + ") + ")
+		}
+		b.WriteString(`"`)
+		for j := 0; j < len(q); j++ {
+			switch c := q[j]; c {
+			case '\\':
+				j++
+				switch q[j] {
+				case '`', '$', '{', '}':
+					b.WriteByte(q[j])
+				default:
+					b.WriteString(q[j-1 : j+1])
+				}
+			case '"':
+				b.WriteString(`\"`)
+			case '\r':
+				// Template literals normalize their line breaks to \n.
+				if j+1 < len(q) && q[j+1] == '\n' {
+					continue
+				}
+				b.WriteString(`\n`)
+			case '\n':
+				b.WriteString("\\n\" +\n\"")
+			default:
+				b.WriteByte(c)
+			}
+		}
+		b.WriteString(`"`)
+	}
+	b.WriteString(")")
+	return b.String(), nil
+}
+
+// emitArrow emits the arrow function starting at nodes[i], and returns
+// the index of the node after it.
+func emitArrow(nodes []*node, i int) (string, int, error) {
+	p := nodes[i]
+	names := p.tok.text
+	if p.close != nil {
+		names = ""
+		for k, n := range p.items {
+			if k%2 == 0 && (n.close != nil || n.tok.kind != tokIdent) || k%2 == 1 && !n.is(tokPunct, ",") {
+				return "", 0, fmt.Errorf("line %d: the parameters of an arrow function must be names", n.tok.line)
+			}
+			names += n.tok.pre + n.tok.text
+		}
+		names += p.close.pre
+	}
+
+	bodyNodes, block, end, err := arrowBody(nodes, i)
+	if err != nil {
+		return "", 0, err
+	}
+	var body string
+	if block {
+		b := nodes[end-1]
+		inner, err := emit(b.items, true)
+		if err != nil {
+			return "", 0, err
+		}
+		body = b.tok.pre + "{" + inner + b.close.pre + "}"
+	} else {
+		expr, err := emit(bodyNodes, false)
+		if err != nil {
+			return "", 0, err
+		}
+		body = " { return (" + expr + "); }"
+	}
+
+	fn := "function (" + names + ")" + body
+	if usesThis(bodyNodes) {
+		fn = "(" + fn + ").bind(this)"
+	}
+	return p.tok.pre + fn, end, nil
+}
+
+// arrowBody returns the body of the arrow function starting at
+// nodes[i], whether it is a block, and the index of the node after it.
+func arrowBody(nodes []*node, i int) ([]*node, bool, int, error) {
+	end := i + 2
+	if end < len(nodes) && nodes[end].isGroup("{") {
+		return nodes[end].items, true, end + 1, nil
+	}
+	ternaries := 0
+	for ; end < len(nodes); end++ {
+		n := nodes[end]
+		if n.is(tokPunct, ",") || n.is(tokPunct, ";") {
+			break
+		}
+		if n.is(tokPunct, "?") {
+			ternaries++
+		}
+		if n.is(tokPunct, ":") {
+			if ternaries == 0 {
+				break
+			}
+			ternaries--
+		}
+		// A newline between two operands starts a new statement.
+		if end > i+2 && strings.Contains(n.tok.pre, "\n") && endsOperand(nodes[end-1]) &&
+			(n.close == nil && n.tok.kind != tokPunct && !keywords[n.tok.text] || n.isGroup("{")) {
+			break
+		}
+	}
+	if end == i+2 {
+		return nil, false, 0, fmt.Errorf("line %d: missing the body of an arrow function", nodes[i+1].tok.line)
+	}
+	return nodes[i+2 : end], false, end, nil
+}
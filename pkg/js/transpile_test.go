@@ -0,0 +1,151 @@
+package js
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/robertkrimen/otto"
+	"github.com/robertkrimen/otto/parser"
+)
+
+func TestTranspile(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"let const", "let a = 1; const b = 2; a + b", "3"},
+		{"let as name", "var let = 1; let", "1"},
+		{"template", "var x = 'b'; `a${x}c${1 + 1}`", `"abc2"`},
+		{"template escapes", "`\"q\" \\` \\${x} \\n`", `"\"q\" ` + "` ${x} \\n\""},
+		{"template multiline", "`a\nb`", `"a\nb"`},
+		{"nested template", "var x = 1; `a${`b${x}`}`", `"ab1"`},
+		{"template object", "var o = {a: 1}; `${o.a}${{b: 2}.b}`", `"12"`},
+		{"arrow expression", "[1, 2].map(x => x * 2)", "[2,4]"},
+		{"arrow params", "var f = (a, b) => a + b; f(1, 2)", "3"},
+		{"arrow no params", "var f = () => 'x'; f()", `"x"`},
+		{"arrow block", "var f = x => { return x + 1; }; f(1)", "2"},
+		{"arrow object", "var f = x => ({v: x}); f(1)", `{"v":1}`},
+		{"arrow ternary", "var f = x => x ? 'y' : 'n'; [f(1), f(0)]", `["y","n"]`},
+		{"arrow nested", "var add = a => b => a + b; add(1)(2)", "3"},
+		{"arrow this", "var o = {v: 1, f: function () { return [1].map(x => this.v + x); }}; o.f()", "[2]"},
+		{"arrow newline", "var f = x => x\nf(1)", "1"},
+		{"arrow body newline", "var f = x =>\n  x + 1; f(1)", "2"},
+		{"regex", "var re = /`[/]/g; 'a`/'.replace(re, '-')", `"a-"`},
+		{"division", "var a = 4, b = 2; a / b / 1", "2"},
+		{"comments", "// `x`\n/* y => z */ 1", "1"},
+		{"const in callback", "const r = []; [1, 2].forEach(x => { const y = x * 2; r.push(() => y); }); r.map(f => f())", "[2,4]"},
+		{"let in function", "function f(a) { let b = a + 1; return b; } var g = function () { const c = 2; return c; }; f(1) + g()", "4"},
+		{"object", "var c = 2; var o = {\n  a: 1\n  , b: {c: c}\n}; o.a + o.b.c", "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := transpile(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := strings.Count(src, "\n"), strings.Count(tt.src, "\n"); got != want {
+				t.Errorf("%q has %d lines, want %d", src, got, want)
+			}
+			vm := otto.New()
+			v, err := vm.Run(src)
+			if err != nil {
+				t.Fatalf("%q: %v", src, err)
+			}
+			if err := vm.Set("v", v); err != nil {
+				t.Fatal(err)
+			}
+			js, err := vm.Run("JSON.stringify(v)")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if js.String() != tt.want {
+				t.Errorf("%q = %s, want %s", src, js.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTranspileErrors(t *testing.T) {
+	for _, src := range []string{
+		"f`x`",
+		"if (a) { let x = 1; }",
+		"for (let i = 0; i < 3; i++) {}",
+		"{ const x = 1; }",
+		"var f = (a = 1) => a",
+		"var f = (...a) => a",
+		"var f = ({a}) => a",
+	} {
+		if _, err := transpile(src); err == nil {
+			t.Errorf("%q: expected an error", src)
+		}
+	}
+}
+
+// docExample matches the JavaScript examples of the documentation.
+var docExample = regexp.MustCompile("(?s)```(?:js|javascript)\n(.*?)```")
+
+// TestTranspileCorpus runs the examples of the documentation and the
+// .js files of the tests through transpile(). It must leave the ES5
+// ones as they are, and turn the others into ES5. Many examples of the
+// documentation aren't complete programs (they use ... for the
+// arguments, for example), so they are only checked if transpile()
+// changes them.
+func TestTranspileCorpus(t *testing.T) {
+	corpus := map[string]string{}
+	err := filepath.WalkDir("docs", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !strings.HasSuffix(path, ".md") {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for i, m := range docExample.FindAllStringSubmatch(string(data), -1) {
+			corpus[path+"#"+strconv.Itoa(i+1)] = m[1]
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"pkg/js", "commands/test_data"} {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".js") {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			corpus[path] = string(data)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, src := range corpus {
+		out, err := transpile(src)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if got, want := strings.Count(out, "\n"), strings.Count(src, "\n"); got != want {
+			t.Errorf("%s: got %d lines, want %d", name, got, want)
+		}
+		_, srcErr := parser.ParseFile(nil, name, src, 0)
+		_, outErr := parser.ParseFile(nil, name, out, 0)
+		switch {
+		case srcErr == nil && out != src:
+			t.Errorf("%s is ES5, but transpile() changed it to:\n%s", name, out)
+		case out != src && outErr != nil:
+			t.Errorf("%s: %v in:\n%s", name, outErr, out)
+		case strings.HasSuffix(name, ".js") && outErr != nil:
+			t.Errorf("%s: %v", name, outErr)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Package lint checks a dnsconfig.js for mistakes that are legal but
+// probably unintended, such as a CNAME target without a trailing dot.
+//
+// The rules run on the configuration as it comes out of the
+// JavaScript interpreter, before it is normalized, so that they see
+// the records the way the user wrote them.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Severity says how serious a finding is.
+type Severity string
+
+// The severities. A rule whose severity is Off is not run.
+const (
+	Off     Severity = "off"
+	Warning Severity = "warning"
+	Error   Severity = "error"
+)
+
+// Finding is a problem found by a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Domain   string   `json:"domain,omitempty"`
+	Label    string   `json:"label,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	where := f.Domain
+	if f.Label != "" {
+		where += ": " + f.Label
+		if f.Type != "" {
+			where += " " + f.Type
+		}
+	}
+	if where != "" {
+		where += ": "
+	}
+	return fmt.Sprintf("%s [%s] %s%s", strings.ToUpper(string(f.Severity)), f.Rule, where, f.Message)
+}
+
+// Rule is a lint check.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity // The default severity.
+	Check       func(dc *models.DomainConfig, opts Options) []Finding
+}
+
+// Rules lists all the rules, in the order they are run.
+var Rules = []*Rule{
+	duplicateRecords,
+	cnameConflict,
+	missingTrailingDot,
+	ttlOutlier,
+	orphanedDS,
+	spfLookups,
+}
+
+// Options are the settings of a rule, from the lint config file.
+type Options map[string]json.Number
+
+// Int returns the named option, or def if it is not set.
+func (o Options) Int(name string, def int) int {
+	if v, ok := o[name]; ok {
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+	}
+	return def
+}
+
+// ruleConfig is the configuration of one rule.
+type ruleConfig struct {
+	Severity Severity
+	Options  Options
+}
+
+// Config selects the rules to run and their settings.
+type Config struct {
+	rules map[string]ruleConfig
+}
+
+// ParseConfig parses a lint config:
+//
+//	{
+//	  "rules": {
+//	    "ttl-outlier": "off",
+//	    "missing-trailing-dot": "error",
+//	    "spf-lookups": { "severity": "error", "max": 8 }
+//	  }
+//	}
+//
+// Each rule is set to a severity, or to an object with an optional
+// "severity" and the rule's options. Rules not listed keep their
+// default severity.
+func ParseConfig(data []byte) (*Config, error) {
+	var raw struct {
+		Rules map[string]json.RawMessage `json:"rules"`
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("lint config: %w", err)
+	}
+
+	cfg := &Config{rules: map[string]ruleConfig{}}
+	for name, msg := range raw.Rules {
+		if findRule(name) == nil {
+			return nil, fmt.Errorf("lint config: unknown rule %q", name)
+		}
+
+		var rc ruleConfig
+		var sev string
+		if err := json.Unmarshal(msg, &sev); err == nil {
+			rc.Severity = Severity(sev)
+		} else {
+			var obj map[string]json.RawMessage
+			if err := json.Unmarshal(msg, &obj); err != nil {
+				return nil, fmt.Errorf("lint config: rule %q must be a severity or an object", name)
+			}
+			rc.Options = Options{}
+			for k, v := range obj {
+				if k == "severity" {
+					if err := json.Unmarshal(v, &sev); err != nil {
+						return nil, fmt.Errorf("lint config: rule %q: severity must be a string", name)
+					}
+					rc.Severity = Severity(sev)
+					continue
+				}
+				var n json.Number
+				if err := json.Unmarshal(v, &n); err != nil {
+					return nil, fmt.Errorf("lint config: rule %q: option %q must be a number", name, k)
+				}
+				rc.Options[k] = n
+			}
+		}
+		switch rc.Severity {
+		case "", Off, Warning, Error:
+		default:
+			return nil, fmt.Errorf("lint config: rule %q: severity must be off, warning or error", name)
+		}
+		cfg.rules[name] = rc
+	}
+	return cfg, nil
+}
+
+// LoadConfig reads a lint config file. An empty filename returns the
+// default configuration.
+func LoadConfig(filename string) (*Config, error) {
+	if filename == "" {
+		return &Config{}, nil
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+func findRule(name string) *Rule {
+	for _, r := range Rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// Run runs the enabled rules on every domain of dnsConfig. The
+// findings are sorted by domain, in the order of the rules.
+func Run(dnsConfig *models.DNSConfig, cfg *Config) []Finding {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	var findings []Finding
+	for _, dc := range dnsConfig.Domains {
+		for _, rule := range Rules {
+			rc := cfg.rules[rule.Name]
+			sev := rc.Severity
+			if sev == "" {
+				sev = rule.Severity
+			}
+			if sev == Off {
+				continue
+			}
+			for _, f := range rule.Check(dc, rc.Options) {
+				f.Rule = rule.Name
+				f.Severity = sev
+				f.Domain = dc.Name
+				findings = append(findings, f)
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Domain < findings[j].Domain })
+	return findings
+}
@@ -0,0 +1,146 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func rec(label, typ, target string, ttl uint32) *models.RecordConfig {
+	// Records from dnsconfig.js have not been normalized yet, so the
+	// label is stored the way it was written.
+	rc := &models.RecordConfig{Type: typ, TTL: ttl, Name: label}
+	switch typ {
+	case "TXT":
+		rc.SetTargetTXT(target)
+	case "MX":
+		rc.SetTargetMX(10, target)
+	default:
+		rc.SetTarget(target)
+	}
+	return rc
+}
+
+func lintRecords(t *testing.T, config string, recs ...*models.RecordConfig) []string {
+	t.Helper()
+	cfg := &Config{}
+	if config != "" {
+		var err error
+		if cfg, err = ParseConfig([]byte(config)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dnsConfig := &models.DNSConfig{Domains: []*models.DomainConfig{{Name: "example.com", Records: recs}}}
+	var got []string
+	for _, f := range Run(dnsConfig, cfg) {
+		got = append(got, f.Rule+" "+string(f.Severity)+" "+f.Label+" "+f.Type)
+	}
+	return got
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		recs   []*models.RecordConfig
+		want   []string
+	}{
+		{
+			name: "clean",
+			recs: []*models.RecordConfig{
+				rec("@", "A", "1.2.3.4", 300),
+				rec("www", "CNAME", "@", 0),
+				rec("@", "MX", "mx.example.net.", 3600),
+				rec("@", "TXT", "v=spf1 include:_spf.example.net -all", 300),
+			},
+		},
+		{
+			name: "duplicate",
+			recs: []*models.RecordConfig{
+				rec("@", "A", "1.2.3.4", 300),
+				rec("example.com.", "A", "1.2.3.4", 600),
+			},
+			want: []string{"duplicate-records error @ A"},
+		},
+		{
+			name: "cname conflict",
+			recs: []*models.RecordConfig{
+				rec("www", "CNAME", "foo.example.net.", 300),
+				rec("www", "A", "1.2.3.4", 300),
+			},
+			want: []string{"cname-conflict error www CNAME"},
+		},
+		{
+			name: "trailing dot",
+			recs: []*models.RecordConfig{
+				rec("www", "CNAME", "foo.example.net", 300),
+				rec("@", "MX", "mx", 300),
+			},
+			want: []string{"missing-trailing-dot warning www CNAME"},
+		},
+		{
+			name: "ttl",
+			recs: []*models.RecordConfig{
+				rec("a", "A", "1.2.3.4", 30),
+				rec("b", "A", "1.2.3.4", 172800),
+			},
+			want: []string{"ttl-outlier warning a A", "ttl-outlier warning b A"},
+		},
+		{
+			name:   "ttl options",
+			config: `{"rules": {"ttl-outlier": {"min": 10, "max": 604800}}}`,
+			recs: []*models.RecordConfig{
+				rec("a", "A", "1.2.3.4", 30),
+				rec("b", "A", "1.2.3.4", 172800),
+			},
+		},
+		{
+			name: "orphaned ds",
+			recs: []*models.RecordConfig{
+				rec("sub", "NS", "ns1.example.net.", 300),
+				rec("sub", "DS", "1 2 3 ABCD", 300),
+				rec("other", "DS", "1 2 3 ABCD", 300),
+			},
+			want: []string{"orphaned-ds warning other DS"},
+		},
+		{
+			name: "spf",
+			recs: []*models.RecordConfig{
+				rec("@", "TXT", "v=spf1 a mx include:a.test include:b.test include:c.test include:d.test include:e.test include:f.test include:g.test include:h.test include:i.test -all", 300),
+			},
+			want: []string{"spf-lookups error @ TXT"},
+		},
+		{
+			name:   "disabled and severity",
+			config: `{"rules": {"duplicate-records": "off", "missing-trailing-dot": "error"}}`,
+			recs: []*models.RecordConfig{
+				rec("www", "CNAME", "foo.example.net", 300),
+				rec("www", "CNAME", "foo.example.net", 300),
+			},
+			want: []string{"cname-conflict error www CNAME", "missing-trailing-dot error www CNAME", "missing-trailing-dot error www CNAME"},
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			got := lintRecords(t, tst.config, tst.recs...)
+			if strings.Join(got, "\n") != strings.Join(tst.want, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tst.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseConfig_errors(t *testing.T) {
+	for _, config := range []string{
+		`{"rules": {"no-such-rule": "off"}}`,
+		`{"rules": {"ttl-outlier": "fatal"}}`,
+		`{"rules": {"ttl-outlier": {"max": "lots"}}}`,
+		`{"rules": {"ttl-outlier": 5}}`,
+		`not json`,
+	} {
+		if _, err := ParseConfig([]byte(config)); err == nil {
+			t.Errorf("%s: expected an error", config)
+		}
+	}
+}
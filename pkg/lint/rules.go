@@ -0,0 +1,184 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/spflib"
+)
+
+// shortLabel returns the label of rc relative to the domain, the way
+// the records are written in dnsconfig.js ("@", "www", or a FQDN with
+// a trailing dot).
+func shortLabel(rc *models.RecordConfig, domain string) string {
+	label := strings.ToLower(rc.GetLabel())
+	domain = strings.ToLower(domain)
+	if label == domain+"." {
+		return "@"
+	}
+	return strings.TrimSuffix(label, "."+domain+".")
+}
+
+func ttlOf(rc *models.RecordConfig) uint32 {
+	if rc.TTL == 0 {
+		return models.DefaultTTL
+	}
+	return rc.TTL
+}
+
+func finding(rc *models.RecordConfig, domain string, format string, args ...interface{}) Finding {
+	return Finding{
+		Label:   shortLabel(rc, domain),
+		Type:    rc.Type,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+var duplicateRecords = &Rule{
+	Name:        "duplicate-records",
+	Description: "The same record appears more than once.",
+	Severity:    Error,
+	Check: func(dc *models.DomainConfig, opts Options) (findings []Finding) {
+		seen := map[string]bool{}
+		for _, rc := range dc.Records {
+			key := shortLabel(rc, dc.Name) + " " + rc.Type + " " + strings.ToLower(rc.GetTargetCombined())
+			if seen[key] {
+				findings = append(findings, finding(rc, dc.Name, "duplicate record %s", rc.GetTargetCombined()))
+			}
+			seen[key] = true
+		}
+		return findings
+	},
+}
+
+var cnameConflict = &Rule{
+	Name:        "cname-conflict",
+	Description: "A label has a CNAME and other records.",
+	Severity:    Error,
+	Check: func(dc *models.DomainConfig, opts Options) (findings []Finding) {
+		types := map[string][]string{}
+		var labels []string
+		for _, rc := range dc.Records {
+			label := shortLabel(rc, dc.Name)
+			if _, ok := types[label]; !ok {
+				labels = append(labels, label)
+			}
+			types[label] = append(types[label], rc.Type)
+		}
+		for _, label := range labels {
+			hasCNAME, others := false, map[string]bool{}
+			for _, t := range types[label] {
+				if t == "CNAME" {
+					hasCNAME = true
+				} else {
+					others[t] = true
+				}
+			}
+			if !hasCNAME {
+				continue
+			}
+			if n := strings.Count(strings.Join(types[label], " "), "CNAME"); n > 1 {
+				findings = append(findings, Finding{Label: label, Type: "CNAME", Message: fmt.Sprintf("%d CNAME records", n)})
+			}
+			for t := range others {
+				findings = append(findings, Finding{Label: label, Type: "CNAME", Message: fmt.Sprintf("CNAME can not coexist with %s records", t)})
+			}
+		}
+		return findings
+	},
+}
+
+var missingTrailingDot = &Rule{
+	Name:        "missing-trailing-dot",
+	Description: "A hostname target contains a dot but doesn't end with one, so the domain will be appended to it.",
+	Severity:    Warning,
+	Check: func(dc *models.DomainConfig, opts Options) (findings []Finding) {
+		for _, rc := range dc.Records {
+			switch rc.Type {
+			case "ALIAS", "CNAME", "MX", "NS", "PTR", "SRV":
+			default:
+				continue
+			}
+			target := rc.GetTargetField()
+			if strings.Contains(target, ".") && !strings.HasSuffix(target, ".") {
+				findings = append(findings, finding(rc, dc.Name, "target %q has no trailing dot; it means %s.%s.", target, target, dc.Name))
+			}
+		}
+		return findings
+	},
+}
+
+var ttlOutlier = &Rule{
+	Name:        "ttl-outlier",
+	Description: `A TTL is less than "min" (default 60) or more than "max" (default 86400) seconds.`,
+	Severity:    Warning,
+	Check: func(dc *models.DomainConfig, opts Options) (findings []Finding) {
+		min := uint32(opts.Int("min", 60))
+		max := uint32(opts.Int("max", 86400))
+		for _, rc := range dc.Records {
+			if ttl := ttlOf(rc); ttl < min {
+				findings = append(findings, finding(rc, dc.Name, "TTL %d is less than %d", ttl, min))
+			} else if ttl > max {
+				findings = append(findings, finding(rc, dc.Name, "TTL %d is more than %d", ttl, max))
+			}
+		}
+		return findings
+	},
+}
+
+var orphanedDS = &Rule{
+	Name:        "orphaned-ds",
+	Description: "A DS record is not at a delegation (a label with NS records).",
+	Severity:    Warning,
+	Check: func(dc *models.DomainConfig, opts Options) (findings []Finding) {
+		delegated := map[string]bool{}
+		for _, rc := range dc.Records {
+			if rc.Type == "NS" {
+				delegated[shortLabel(rc, dc.Name)] = true
+			}
+		}
+		for _, rc := range dc.Records {
+			label := shortLabel(rc, dc.Name)
+			// DS records at the apex are published by the parent zone.
+			if rc.Type == "DS" && label != "@" && !delegated[label] {
+				findings = append(findings, finding(rc, dc.Name, "DS record without NS records at %s", label))
+			}
+		}
+		return findings
+	},
+}
+
+var spfLookups = &Rule{
+	Name:        "spf-lookups",
+	Description: `An SPF record needs more than "max" (default 10) DNS lookups. Only the record itself is counted, not the records it includes.`,
+	Severity:    Error,
+	Check: func(dc *models.DomainConfig, opts Options) (findings []Finding) {
+		max := opts.Int("max", 10)
+		for _, rc := range dc.Records {
+			if rc.Type != "TXT" {
+				continue
+			}
+			// SPF_BUILDER records are checked when they are flattened.
+			if rc.Metadata["flatten"] != "" || rc.Metadata["split"] != "" {
+				continue
+			}
+			txt := strings.Join(rc.TxtStrings, "")
+			if len(rc.TxtStrings) == 0 {
+				txt = rc.GetTargetField()
+			}
+			if !strings.HasPrefix(txt, "v=spf1 ") {
+				continue
+			}
+			rec, err := spflib.Parse(txt, nil)
+			if err != nil {
+				findings = append(findings, finding(rc, dc.Name, "invalid SPF record: %s", err))
+				continue
+			}
+			if n := rec.Lookups(); n > max {
+				findings = append(findings, finding(rc, dc.Name, "SPF record needs %d DNS lookups, more than %d", n, max))
+			}
+		}
+		return findings
+	},
+}
@@ -0,0 +1,75 @@
+package mailauth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidateDMARC returns an error if txt is not a valid DMARC policy
+// record.
+func ValidateDMARC(txt string) error {
+	tags, err := parseTags(txt)
+	if err != nil {
+		return fmt.Errorf("DMARC: %w", err)
+	}
+	if len(tags) == 0 || tags[0].Name != "v" || tags[0].Value != "DMARC1" {
+		return fmt.Errorf("DMARC: record must start with v=DMARC1")
+	}
+	if len(tags) < 2 || tags[1].Name != "p" {
+		return fmt.Errorf("DMARC: p= must follow v=DMARC1")
+	}
+
+	for _, t := range tags[1:] {
+		if err := checkDMARCTag(t); err != nil {
+			return fmt.Errorf("DMARC: %s=%s: %w", t.Name, t.Value, err)
+		}
+	}
+	return nil
+}
+
+func checkDMARCTag(t tag) error {
+	switch t.Name {
+	case "p", "sp":
+		switch t.Value {
+		case "none", "quarantine", "reject":
+		default:
+			return fmt.Errorf("policy must be none, quarantine or reject")
+		}
+	case "adkim", "aspf":
+		if t.Value != "r" && t.Value != "s" {
+			return fmt.Errorf("alignment must be r or s")
+		}
+	case "pct":
+		n, err := strconv.Atoi(t.Value)
+		if err != nil || n < 0 || n > 100 {
+			return fmt.Errorf("must be a number from 0 to 100")
+		}
+	case "ri":
+		if _, err := strconv.ParseUint(t.Value, 10, 32); err != nil {
+			return fmt.Errorf("must be a number of seconds")
+		}
+	case "rua", "ruf":
+		// RFC 7489 only defines mailto: but allows other schemes.
+		return checkReportURIs(t.Value, true, "mailto", "https", "http")
+	case "fo":
+		for _, o := range strings.Split(t.Value, ":") {
+			switch o {
+			case "0", "1", "d", "s":
+			default:
+				return fmt.Errorf("options must be 0, 1, d or s, separated by colons")
+			}
+		}
+	case "rf":
+		for _, f := range strings.Split(t.Value, ":") {
+			if f != "afrf" && f != "iodef" {
+				return fmt.Errorf("formats must be afrf or iodef, separated by colons")
+			}
+		}
+	case "v":
+		return fmt.Errorf("must be the first tag")
+	default:
+		return fmt.Errorf("unknown tag")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package mailauth
+
+import "testing"
+
+func TestValidateDMARC(t *testing.T) {
+	tests := []struct {
+		txt   string
+		valid bool
+	}{
+		{"v=DMARC1; p=none", true},
+		{"v=DMARC1; p=reject;", true},
+		{"v=DMARC1; p=quarantine; sp=reject; adkim=s; aspf=r; pct=50; ri=3600", true},
+		{"v=DMARC1; p=reject; rua=mailto:a@example.com,mailto:b@example.com!10m; ruf=https://dmarc.example.com/submit", true},
+		{"v=DMARC1; p=reject; ruf=mailto:a@example.com; fo=d:s; rf=afrf", true},
+		{"", false},
+		{"v=DMARC1", false},
+		{"p=none; v=DMARC1", false},
+		{"v=DMARC2; p=none", false},
+		{"v=DMARC1; pct=50; p=none", false},
+		{"v=DMARC1; p=block", false},
+		{"v=DMARC1; p=none; sp=block", false},
+		{"v=DMARC1; p=none; adkim=strict", false},
+		{"v=DMARC1; p=none; pct=101", false},
+		{"v=DMARC1; p=none; ri=1h", false},
+		{"v=DMARC1; p=none; rua=a@example.com", false},
+		{"v=DMARC1; p=none; rua=mailto:example.com", false},
+		{"v=DMARC1; p=none; rua=mailto:a@example.com!10x", false},
+		{"v=DMARC1; p=none; rua=ftp://example.com/", false},
+		{"v=DMARC1; p=none; fo=2", false},
+		{"v=DMARC1; p=none; rf=json", false},
+		{"v=DMARC1; p=none; p=reject", false},
+		{"v=DMARC1; p=none; foo=bar", false},
+		{"v=DMARC1;; p=none", false},
+	}
+	for _, tst := range tests {
+		err := ValidateDMARC(tst.txt)
+		if (err == nil) != tst.valid {
+			t.Errorf("ValidateDMARC(%q) = %v, want valid %v", tst.txt, err, tst.valid)
+		}
+	}
+}
+
+func TestValidateMTASTS(t *testing.T) {
+	tests := []struct {
+		txt   string
+		valid bool
+	}{
+		{"v=STSv1; id=20230101T000000", true},
+		{"v=STSv1; id=1;", true},
+		{"v=STSv1", false},
+		{"id=1; v=STSv1", false},
+		{"v=STSv1; id=2023-01-01", false},
+		{"v=STSv1; id=123456789012345678901234567890123", false},
+	}
+	for _, tst := range tests {
+		err := ValidateMTASTS(tst.txt)
+		if (err == nil) != tst.valid {
+			t.Errorf("ValidateMTASTS(%q) = %v, want valid %v", tst.txt, err, tst.valid)
+		}
+	}
+}
+
+func TestValidateTLSRPT(t *testing.T) {
+	tests := []struct {
+		txt   string
+		valid bool
+	}{
+		{"v=TLSRPTv1; rua=mailto:tlsrpt@example.com", true},
+		{"v=TLSRPTv1; rua=mailto:tlsrpt@example.com,https://tlsrpt.example.com/v1", true},
+		{"v=TLSRPTv1", false},
+		{"v=TLSRPTv1; rua=http://tlsrpt.example.com/v1", false},
+		{"v=TLSRPTv1; rua=mailto:tlsrpt@example.com!10m", false},
+	}
+	for _, tst := range tests {
+		err := ValidateTLSRPT(tst.txt)
+		if (err == nil) != tst.valid {
+			t.Errorf("ValidateTLSRPT(%q) = %v, want valid %v", tst.txt, err, tst.valid)
+		}
+	}
+}
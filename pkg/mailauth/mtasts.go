@@ -0,0 +1,52 @@
+package mailauth
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// mtaSTSID matches the id of an MTA-STS policy: 1 to 32 letters and
+// digits (RFC 8461, section 3.1).
+var mtaSTSID = regexp.MustCompile(`^[a-zA-Z0-9]{1,32}$`)
+
+// ValidateMTASTS returns an error if txt is not a valid MTA-STS record,
+// the TXT record at _mta-sts.
+func ValidateMTASTS(txt string) error {
+	tags, err := parseTags(txt)
+	if err != nil {
+		return fmt.Errorf("MTA-STS: %w", err)
+	}
+	if len(tags) == 0 || tags[0].Name != "v" || tags[0].Value != "STSv1" {
+		return fmt.Errorf("MTA-STS: record must start with v=STSv1")
+	}
+	for _, t := range tags[1:] {
+		if t.Name == "id" {
+			if !mtaSTSID.MatchString(t.Value) {
+				return fmt.Errorf("MTA-STS: id %q must be 1 to 32 letters and digits", t.Value)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("MTA-STS: record has no id")
+}
+
+// ValidateTLSRPT returns an error if txt is not a valid SMTP TLS
+// reporting record, the TXT record at _smtp._tls.
+func ValidateTLSRPT(txt string) error {
+	tags, err := parseTags(txt)
+	if err != nil {
+		return fmt.Errorf("TLSRPT: %w", err)
+	}
+	if len(tags) == 0 || tags[0].Name != "v" || tags[0].Value != "TLSRPTv1" {
+		return fmt.Errorf("TLSRPT: record must start with v=TLSRPTv1")
+	}
+	for _, t := range tags[1:] {
+		if t.Name == "rua" {
+			if err := checkReportURIs(t.Value, false, "mailto", "https"); err != nil {
+				return fmt.Errorf("TLSRPT: rua=%s: %w", t.Value, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("TLSRPT: record has no rua")
+}
@@ -0,0 +1,97 @@
+// Package mailauth validates the TXT records used to authenticate
+// email: DMARC (RFC 7489), MTA-STS (RFC 8461) and SMTP TLS reporting
+// (RFC 8460).
+package mailauth
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// tag is one "name=value" pair of a tag-value list.
+type tag struct {
+	Name, Value string
+}
+
+// parseTags splits a record such as "v=DMARC1; p=none" into its tags.
+// Whitespace around tags is ignored and a trailing ";" is allowed.
+// Duplicate tags are an error.
+func parseTags(txt string) ([]tag, error) {
+	var tags []tag
+	seen := map[string]bool{}
+	parts := strings.Split(txt, ";")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if i == len(parts)-1 {
+				continue // trailing ";"
+			}
+			return nil, fmt.Errorf("empty tag")
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("tag %q has no value", part)
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if seen[name] {
+			return nil, fmt.Errorf("tag %q appears more than once", name)
+		}
+		seen[name] = true
+		tags = append(tags, tag{Name: name, Value: value})
+	}
+	return tags, nil
+}
+
+// reportSize matches the optional size limit on a report URI, such as
+// the "!10m" in "mailto:dmarc@example.com!10m".
+var reportSize = regexp.MustCompile(`^[0-9]+[kmgt]?$`)
+
+// mailDomain matches the domain part of an email address.
+var mailDomain = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?$`)
+
+// checkReportURIs validates a comma-separated list of report URIs.
+// schemes lists the acceptable URI schemes. If sizes is true, each URI
+// may end with a size limit.
+func checkReportURIs(list string, sizes bool, schemes ...string) error {
+	if list == "" {
+		return fmt.Errorf("no URIs")
+	}
+	for _, u := range strings.Split(list, ",") {
+		u = strings.TrimSpace(u)
+		if sizes {
+			if i := strings.LastIndex(u, "!"); i >= 0 {
+				if !reportSize.MatchString(u[i+1:]) {
+					return fmt.Errorf("%q has an invalid size limit", u)
+				}
+				u = u[:i]
+			}
+		}
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return fmt.Errorf("%q is not a URI", u)
+		}
+		ok := false
+		for _, s := range schemes {
+			if parsed.Scheme == s {
+				ok = true
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%q must start with %s", u, strings.Join(schemes, ": or ")+":")
+		}
+		switch parsed.Scheme {
+		case "mailto":
+			local, domain, found := strings.Cut(parsed.Opaque, "@")
+			if !found || local == "" || !mailDomain.MatchString(domain) {
+				return fmt.Errorf("%q is not a valid email address", u)
+			}
+		default:
+			if parsed.Host == "" {
+				return fmt.Errorf("%q has no host", u)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Package metaschema describes the provider-level metadata that a DNS
+// provider accepts, i.e. the third argument of NewDnsProvider().
+package metaschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Type is the JSON type of a metadata value.
+type Type int
+
+// The types of metadata values.
+const (
+	String Type = iota
+	Bool
+	Number
+	StringList
+	Object
+)
+
+func (t Type) String() string {
+	switch t {
+	case String:
+		return "a string"
+	case Bool:
+		return "a boolean"
+	case Number:
+		return "a number"
+	case StringList:
+		return "a list of strings"
+	case Object:
+		return "an object"
+	}
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
+// Schema maps the metadata keys that a provider accepts to their types.
+type Schema map[string]Type
+
+// Validate returns an error that lists the keys of meta that aren't in
+// the schema and the values that have the wrong type. Null values are
+// accepted for any key, as the providers ignore them.
+func (s Schema) Validate(meta json.RawMessage) error {
+	if len(meta) == 0 {
+		return nil
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(meta, &m); err != nil {
+		return fmt.Errorf("metadata must be an object, not %s", kind(meta))
+	}
+
+	var problems []string
+	for key, value := range m {
+		t, ok := s[key]
+		if !ok {
+			msg := fmt.Sprintf("unknown key %q", key)
+			if near := s.closest(key); near != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", near)
+			}
+			problems = append(problems, msg)
+			continue
+		}
+		if got := kind(value); got != "null" && !t.matches(value) {
+			problems = append(problems, fmt.Sprintf("%q must be %s, not %s", key, t, got))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("%s", strings.Join(problems, "; "))
+}
+
+func (t Type) matches(value json.RawMessage) bool {
+	var err error
+	switch t {
+	case String:
+		var v string
+		err = json.Unmarshal(value, &v)
+	case Bool:
+		var v bool
+		err = json.Unmarshal(value, &v)
+	case Number:
+		var v float64
+		err = json.Unmarshal(value, &v)
+	case StringList:
+		var v []string
+		err = json.Unmarshal(value, &v)
+	case Object:
+		var v map[string]interface{}
+		err = json.Unmarshal(value, &v)
+	}
+	return err == nil
+}
+
+// kind describes the JSON type of value, for error messages.
+func kind(value json.RawMessage) string {
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return "invalid JSON"
+	}
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case []interface{}:
+		return "a list"
+	}
+	return "an object"
+}
+
+// closest returns the key of the schema that key is most likely a typo
+// of, or "".
+func (s Schema) closest(key string) string {
+	best, bestDist := "", 3
+	for k := range s {
+		d := distance(strings.ToLower(key), k)
+		if d < bestDist || d == bestDist && k < best {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
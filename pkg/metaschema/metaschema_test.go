@@ -0,0 +1,38 @@
+package metaschema
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	s := Schema{
+		"default_ns":       StringList,
+		"manage_redirects": Bool,
+		"ip_conversions":   String,
+		"default_soa":      Object,
+		"port":             Number,
+	}
+	tests := []struct {
+		meta string
+		want string
+	}{
+		{``, ""},
+		{`{}`, ""},
+		{`{"default_ns": ["ns1.example.com."], "manage_redirects": true, "ip_conversions": "1.2.3.4 ~ 5.6.7.8", "default_soa": {"master": "ns1."}, "port": 53}`, ""},
+		{`{"manage_redirects": null}`, ""},
+		{`{"manage_redirect": true}`, `unknown key "manage_redirect" (did you mean "manage_redirects"?)`},
+		{`{"Default_NS": []}`, `unknown key "Default_NS" (did you mean "default_ns"?)`},
+		{`{"colour": "blue"}`, `unknown key "colour"`},
+		{`{"manage_redirects": "true"}`, `"manage_redirects" must be a boolean, not a string`},
+		{`{"default_ns": "ns1.example.com."}`, `"default_ns" must be a list of strings, not a string`},
+		{`{"default_ns": [1], "port": "53"}`, `"default_ns" must be a list of strings, not a list; "port" must be a number, not a string`},
+		{`[]`, "metadata must be an object, not a list"},
+	}
+	for _, tt := range tests {
+		got := ""
+		if err := s.Validate([]byte(tt.meta)); err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("Validate(%s) = %q, want %q", tt.meta, got, tt.want)
+		}
+	}
+}
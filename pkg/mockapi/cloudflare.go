@@ -0,0 +1,495 @@
+package mockapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	Register("CLOUDFLAREAPI", func() Mock { return NewCloudflare() })
+}
+
+// Cloudflare is a mock of the Cloudflare API v4
+// (https://developers.cloudflare.com/api/): zones with their DNS
+// records, settings, page rules, worker routes, the rules of their
+// entrypoint rulesets and their Spectrum applications, and the workers
+// of the account.
+type Cloudflare struct {
+	mu     sync.Mutex
+	nextID int
+	zones  []*cloudflareZone // In the order of creation.
+}
+
+type cloudflareZone struct {
+	id           string
+	name         string
+	records      []cfObject
+	settings     map[string]interface{}
+	universalSSL bool
+	pageRules    []cfObject
+	workerRoutes []cfObject
+	rulesets     map[string]cfObject // Entrypoint rulesets, by phase.
+	spectrumApps []cfObject
+}
+
+// cfObject is an object of the API, as JSON.
+type cfObject = map[string]interface{}
+
+// Errors of the Cloudflare API.
+const (
+	cloudflareErrAuth         = 9106
+	cloudflareErrNotFound     = 7003
+	cloudflareErrInvalid      = 1004
+	cloudflareErrRecordExists = 81057
+	cloudflareErrZoneExists   = 1061
+)
+
+// NewCloudflare returns a mock of the Cloudflare API without zones.
+func NewCloudflare() *Cloudflare {
+	return &Cloudflare{}
+}
+
+// Creds returns the creds.json fields of the CLOUDFLAREAPI provider.
+func (m *Cloudflare) Creds(url string) map[string]string {
+	return map[string]string{"apitoken": "mock-token", "accountid": "mock-account", "baseURL": url}
+}
+
+// AddZone creates a zone without records.
+func (m *Cloudflare) AddZone(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.createZone(name)
+}
+
+// AddRecord adds a record to the zone name, as the API creates it from
+// the fields of rec (name, type, content, ttl, data, ...).
+func (m *Cloudflare) AddRecord(zone string, rec map[string]interface{}) {
+	// As if it was sent as JSON.
+	b, _ := json.Marshal(rec)
+	body := cfObject{}
+	json.Unmarshal(b, &body)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, z := range m.zones {
+		if z.name == zone {
+			z.records = append(z.records, m.newRecord(z, body))
+		}
+	}
+}
+
+func (m *Cloudflare) id() string {
+	m.nextID++
+	return fmt.Sprintf("%032x", m.nextID)
+}
+
+func (m *Cloudflare) createZone(name string) *cloudflareZone {
+	z := &cloudflareZone{
+		id:           m.id(),
+		name:         name,
+		settings:     map[string]interface{}{"cname_flattening": "flatten_at_root"},
+		universalSSL: true,
+		rulesets:     map[string]cfObject{},
+	}
+	m.zones = append(m.zones, z)
+	return z
+}
+
+func (z *cloudflareZone) object() cfObject {
+	return cfObject{
+		"id":           z.id,
+		"name":         z.name,
+		"status":       "active",
+		"type":         "full",
+		"name_servers": []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"},
+	}
+}
+
+func (m *Cloudflare) zone(id string) *cloudflareZone {
+	for _, z := range m.zones {
+		if z.id == id {
+			return z
+		}
+	}
+	return nil
+}
+
+// ServeHTTP serves the API at the root of the server, as the default
+// https://api.cloudflare.com/client/v4.
+func (m *Cloudflare) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") == "" && r.Header.Get("X-Auth-Key") == "" {
+		cloudflareError(w, http.StatusBadRequest, cloudflareErrAuth, "Missing X-Auth-Key, X-Auth-Email or Authorization headers")
+		return
+	}
+	// The bodies are JSON, except the scripts of workers.
+	body := cfObject{}
+	if !strings.HasPrefix(r.URL.Path, "/accounts/") {
+		b, _ := io.ReadAll(r.Body)
+		if len(b) > 0 {
+			if err := json.Unmarshal(b, &body); err != nil {
+				cloudflareError(w, http.StatusBadRequest, cloudflareErrInvalid, "Invalid JSON: "+err.Error())
+				return
+			}
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	route := r.Method + " " + cloudflareRoute(path)
+	switch route {
+	case "GET zones":
+		var zones []cfObject
+		for _, z := range m.zones {
+			if name := r.URL.Query().Get("name"); name == "" || name == z.name {
+				zones = append(zones, z.object())
+			}
+		}
+		cloudflarePage(w, r, zones, 20, 50)
+		return
+	case "POST zones":
+		name, _ := body["name"].(string)
+		for _, z := range m.zones {
+			if z.name == name {
+				cloudflareError(w, http.StatusBadRequest, cloudflareErrZoneExists, name+" already exists")
+				return
+			}
+		}
+		cloudflareOK(w, m.createZone(name).object())
+		return
+	case "PUT accounts/ID/workers/scripts/ID":
+		cloudflareOK(w, cfObject{"id": path[4]})
+		return
+	}
+
+	var z *cloudflareZone
+	if len(path) > 1 && path[0] == "zones" {
+		z = m.zone(path[1])
+	}
+	if z == nil {
+		cloudflareError(w, http.StatusNotFound, cloudflareErrNotFound, "Could not route to "+r.URL.Path+", perhaps your object identifier is invalid?")
+		return
+	}
+	switch route {
+	case "GET zones/ID":
+		cloudflareOK(w, z.object())
+	case "GET zones/ID/dns_records":
+		var records []cfObject
+		q := r.URL.Query()
+		for _, rec := range z.records {
+			if (q.Get("type") == "" || q.Get("type") == rec["type"]) && (q.Get("name") == "" || q.Get("name") == rec["name"]) {
+				records = append(records, rec)
+			}
+		}
+		cloudflarePage(w, r, records, 100, 5000)
+	case "POST zones/ID/dns_records":
+		rec := m.newRecord(z, body)
+		for _, other := range z.records {
+			if other["name"] == rec["name"] && other["type"] == rec["type"] && other["content"] == rec["content"] {
+				cloudflareError(w, http.StatusBadRequest, cloudflareErrRecordExists, "Record already exists.")
+				return
+			}
+		}
+		z.records = append(z.records, rec)
+		cloudflareOK(w, rec)
+	case "GET zones/ID/dns_records/ID", "PUT zones/ID/dns_records/ID", "PATCH zones/ID/dns_records/ID", "DELETE zones/ID/dns_records/ID":
+		i := findObject(z.records, path[3])
+		if i < 0 {
+			cloudflareError(w, http.StatusNotFound, cloudflareErrNotFound, "Record not found")
+			return
+		}
+		switch r.Method {
+		case "PUT":
+			z.records[i] = m.updateRecord(z, cfObject{"id": path[3], "created_on": z.records[i]["created_on"]}, body)
+		case "PATCH":
+			z.records[i] = m.updateRecord(z, z.records[i], body)
+		case "DELETE":
+			z.records = append(z.records[:i:i], z.records[i+1:]...)
+			cloudflareOK(w, cfObject{"id": path[3]})
+			return
+		}
+		cloudflareOK(w, z.records[i])
+	case "GET zones/ID/ssl/universal/settings":
+		cloudflareOK(w, cfObject{"enabled": z.universalSSL})
+	case "PATCH zones/ID/ssl/universal/settings":
+		z.universalSSL, _ = body["enabled"].(bool)
+		cloudflareOK(w, cfObject{"enabled": z.universalSSL})
+	case "GET zones/ID/settings/ID", "PATCH zones/ID/settings/ID":
+		if r.Method == "PATCH" {
+			z.settings[path[3]] = body["value"]
+		}
+		value, ok := z.settings[path[3]]
+		if !ok {
+			cloudflareError(w, http.StatusNotFound, cloudflareErrNotFound, "Unknown setting "+path[3])
+			return
+		}
+		cloudflareOK(w, cfObject{"id": path[3], "value": value, "editable": true})
+	case "GET zones/ID/pagerules":
+		cloudflareOK(w, orEmpty(z.pageRules))
+	case "POST zones/ID/pagerules":
+		body["id"] = m.id()
+		z.pageRules = append(z.pageRules, body)
+		cloudflareOK(w, body)
+	case "DELETE zones/ID/pagerules/ID":
+		z.pageRules = m.deleteObject(w, z.pageRules, path[3])
+	case "GET zones/ID/workers/routes":
+		cloudflareOK(w, orEmpty(z.workerRoutes))
+	case "POST zones/ID/workers/routes":
+		body["id"] = m.id()
+		z.workerRoutes = append(z.workerRoutes, body)
+		cloudflareOK(w, body)
+	case "DELETE zones/ID/workers/routes/ID":
+		z.workerRoutes = m.deleteObject(w, z.workerRoutes, path[4])
+	case "GET zones/ID/rulesets/phases/ID/entrypoint":
+		rs, ok := z.rulesets[path[4]]
+		if !ok {
+			cloudflareError(w, http.StatusNotFound, cloudflareErrNotFound, "could not find entrypoint ruleset in the "+path[4]+" phase")
+			return
+		}
+		cloudflareOK(w, rs)
+	case "PUT zones/ID/rulesets/phases/ID/entrypoint":
+		rs, ok := z.rulesets[path[4]]
+		if !ok {
+			rs = cfObject{"id": m.id(), "kind": "zone", "phase": path[4]}
+			z.rulesets[path[4]] = rs
+		}
+		rules, _ := body["rules"].([]interface{})
+		rs["rules"] = []cfObject{}
+		for _, rule := range rules {
+			rule, _ := rule.(cfObject)
+			rs["rules"] = append(rs["rules"].([]cfObject), m.newRule(rule))
+		}
+		cloudflareOK(w, rs)
+	case "POST zones/ID/rulesets/ID/rules", "PATCH zones/ID/rulesets/ID/rules/ID", "DELETE zones/ID/rulesets/ID/rules/ID":
+		var rs cfObject
+		for _, r := range z.rulesets {
+			if r["id"] == path[3] {
+				rs = r
+			}
+		}
+		if rs == nil {
+			cloudflareError(w, http.StatusNotFound, cloudflareErrNotFound, "Ruleset not found")
+			return
+		}
+		rules := rs["rules"].([]cfObject)
+		if r.Method == "POST" {
+			rs["rules"] = append(rules, m.newRule(body))
+			cloudflareOK(w, rs)
+			return
+		}
+		i := findObject(rules, path[5])
+		if i < 0 {
+			cloudflareError(w, http.StatusNotFound, cloudflareErrNotFound, "Rule not found")
+			return
+		}
+		if r.Method == "PATCH" {
+			body["id"] = path[5]
+			rules[i] = body
+		} else {
+			rs["rules"] = append(rules[:i:i], rules[i+1:]...)
+		}
+		cloudflareOK(w, rs)
+	case "GET zones/ID/spectrum/apps":
+		cloudflarePage(w, r, z.spectrumApps, 20, 100)
+	case "POST zones/ID/spectrum/apps":
+		body["id"] = m.id()
+		z.spectrumApps = append(z.spectrumApps, body)
+		cloudflareOK(w, body)
+	case "PUT zones/ID/spectrum/apps/ID":
+		i := findObject(z.spectrumApps, path[4])
+		if i < 0 {
+			cloudflareError(w, http.StatusNotFound, cloudflareErrNotFound, "Application not found")
+			return
+		}
+		body["id"] = path[4]
+		z.spectrumApps[i] = body
+		cloudflareOK(w, body)
+	case "DELETE zones/ID/spectrum/apps/ID":
+		z.spectrumApps = m.deleteObject(w, z.spectrumApps, path[4])
+	default:
+		cloudflareError(w, http.StatusNotFound, cloudflareErrNotFound, "No route for that URI")
+	}
+}
+
+// cloudflareRoute returns path with the identifiers replaced by ID, as
+// in "zones/ID/dns_records/ID".
+func cloudflareRoute(path []string) string {
+	route := append([]string(nil), path...)
+	for i := range route {
+		switch {
+		case i == 1,
+			i == 3 && (path[2] == "dns_records" || path[2] == "settings" || path[2] == "pagerules"),
+			i == 4 && (path[2] == "workers" || path[2] == "spectrum"),
+			i == 3 && path[2] == "rulesets" && path[3] != "phases",
+			i == 4 && path[2] == "rulesets" && path[3] == "phases",
+			i == 5 && path[2] == "rulesets" && path[4] == "rules":
+			route[i] = "ID"
+		}
+	}
+	return strings.Join(route, "/")
+}
+
+// newRule returns the rule of a ruleset that the API creates from body.
+func (m *Cloudflare) newRule(body cfObject) cfObject {
+	rule := cfObject{"enabled": true}
+	for k, v := range body {
+		rule[k] = v
+	}
+	rule["id"] = m.id()
+	return rule
+}
+
+// newRecord returns the record that the API creates from body.
+func (m *Cloudflare) newRecord(z *cloudflareZone, body cfObject) cfObject {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return m.updateRecord(z, cfObject{"id": m.id(), "created_on": now}, body)
+}
+
+// updateRecord returns rec with the fields of body, as the API
+// completes them.
+func (m *Cloudflare) updateRecord(z *cloudflareZone, rec cfObject, body cfObject) cfObject {
+	updated := cfObject{}
+	for k, v := range rec {
+		updated[k] = v
+	}
+	for k, v := range body {
+		updated[k] = v
+	}
+	rtype, _ := updated["type"].(string)
+	name, _ := updated["name"].(string)
+	updated["name"] = cloudflareName(name, z.name)
+	updated["zone_id"] = z.id
+	updated["zone_name"] = z.name
+	updated["proxiable"] = rtype == "A" || rtype == "AAAA" || rtype == "CNAME"
+	updated["locked"] = false
+	updated["modified_on"] = time.Now().UTC().Format(time.RFC3339)
+	if updated["proxied"] == nil {
+		updated["proxied"] = false
+	}
+	if ttl, _ := updated["ttl"].(float64); ttl == 0 {
+		updated["ttl"] = 1 // Automatic.
+	}
+	if data, ok := updated["data"].(map[string]interface{}); ok {
+		updated["content"] = cloudflareContent(rtype, data)
+		if rtype == "SRV" {
+			updated["priority"] = data["priority"]
+		}
+	}
+	return updated
+}
+
+// cloudflareName returns name as a FQDN without the trailing dot.
+func cloudflareName(name, zone string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if name == "@" || name == "" {
+		return zone
+	}
+	if name == zone || strings.HasSuffix(name, "."+zone) {
+		return name
+	}
+	return name + "." + zone
+}
+
+// cloudflareContent returns the content of the records whose fields are
+// given in data.
+func cloudflareContent(rtype string, data map[string]interface{}) string {
+	var fields []string
+	switch rtype {
+	case "SRV":
+		fields = []string{"weight", "port", "target"}
+	case "CAA":
+		return fmt.Sprintf("%v %v %q", data["flags"], data["tag"], data["value"])
+	case "TLSA":
+		fields = []string{"usage", "selector", "matching_type", "certificate"}
+	case "SSHFP":
+		fields = []string{"algorithm", "type", "fingerprint"}
+	case "DS":
+		fields = []string{"key_tag", "algorithm", "digest_type", "digest"}
+	}
+	var values []string
+	for _, f := range fields {
+		values = append(values, fmt.Sprint(data[f]))
+	}
+	return strings.Join(values, " ")
+}
+
+func findObject(objects []cfObject, id string) int {
+	for i, o := range objects {
+		if o["id"] == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Cloudflare) deleteObject(w http.ResponseWriter, objects []cfObject, id string) []cfObject {
+	i := findObject(objects, id)
+	if i < 0 {
+		cloudflareError(w, http.StatusNotFound, cloudflareErrNotFound, "Not found")
+		return objects
+	}
+	cloudflareOK(w, cfObject{"id": id})
+	return append(objects[:i:i], objects[i+1:]...)
+}
+
+func orEmpty(objects []cfObject) []cfObject {
+	if objects == nil {
+		return []cfObject{}
+	}
+	return objects
+}
+
+func cloudflareOK(w http.ResponseWriter, result interface{}) {
+	writeJSON(w, http.StatusOK, cfObject{
+		"success":  true,
+		"errors":   []interface{}{},
+		"messages": []interface{}{},
+		"result":   result,
+	})
+}
+
+// cloudflarePage answers with the page of items that the query asks
+// for, with defaultPerPage items unless the query asks for more, up to
+// maxPerPage.
+func cloudflarePage(w http.ResponseWriter, r *http.Request, items []cfObject, defaultPerPage, maxPerPage int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 {
+		perPage = defaultPerPage
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+	result, pages := Paginate(orEmpty(items), page, perPage)
+	writeJSON(w, http.StatusOK, cfObject{
+		"success":  true,
+		"errors":   []interface{}{},
+		"messages": []interface{}{},
+		"result":   result,
+		"result_info": cfObject{
+			"page":        page,
+			"per_page":    perPage,
+			"count":       len(result),
+			"total_count": len(items),
+			"total_pages": pages,
+		},
+	})
+}
+
+func cloudflareError(w http.ResponseWriter, status int, code int, message string) {
+	writeJSON(w, status, cfObject{
+		"success":  false,
+		"errors":   []cfObject{{"code": code, "message": message}},
+		"messages": []interface{}{},
+		"result":   nil,
+	})
+}
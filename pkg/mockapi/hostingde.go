@@ -0,0 +1,319 @@
+package mockapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	Register("HOSTINGDE", func() Mock { return NewHostingde() })
+}
+
+// Hostingde is a mock of the hosting.de API
+// (https://www.hosting.de/api/): the zone configs and records of the
+// DNS service, and the domains of the domain service.
+type Hostingde struct {
+	mu      sync.Mutex
+	nextID  int
+	zones   map[string]*hostingdeZone // By name.
+	domains map[string]*hostingdeDomain
+}
+
+type hostingdeZone struct {
+	ID      string             `json:"id"`
+	Name    string             `json:"name"`
+	Type    string             `json:"type"`
+	records []*hostingdeRecord // In the order of creation.
+}
+
+type hostingdeRecord struct {
+	ID           string `json:"id"`
+	ZoneConfigID string `json:"zoneConfigId"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Content      string `json:"content"`
+	TTL          uint32 `json:"ttl"`
+	Priority     uint16 `json:"priority"`
+}
+
+type hostingdeDomain struct {
+	Name                string                `json:"name"`
+	Contacts            []json.RawMessage     `json:"contacts"`
+	Nameservers         []hostingdeNameserver `json:"nameservers"`
+	TransferLockEnabled bool                  `json:"transferLockEnabled"`
+	// Read-only.
+	CurrentContractPeriodEnd *time.Time `json:"currentContractPeriodEnd,omitempty"`
+}
+
+type hostingdeNameserver struct {
+	Name string `json:"name"`
+}
+
+type hostingdeRequest struct {
+	AuthToken string `json:"authToken"`
+	Filter    struct {
+		Field string `json:"field"`
+		Value string `json:"value"`
+	} `json:"filter"`
+	Limit int `json:"limit"`
+	Page  int `json:"page"`
+
+	ZoneConfig      *hostingdeZone     `json:"zoneConfig"`
+	RecordsToAdd    []*hostingdeRecord `json:"recordsToAdd"`
+	RecordsToModify []*hostingdeRecord `json:"recordsToModify"`
+	RecordsToDelete []*hostingdeRecord `json:"recordsToDelete"`
+	Records         []*hostingdeRecord `json:"records"`
+	Domain          *hostingdeDomain   `json:"domain"`
+}
+
+// Errors of the hosting.de API.
+const (
+	hostingdeErrAuth         = 10100
+	hostingdeErrZoneExists   = 10202
+	hostingdeErrZoneNotFound = 10203
+	hostingdeErrRecord       = 10204
+)
+
+// NewHostingde returns a mock of the hosting.de API without zones.
+func NewHostingde() *Hostingde {
+	return &Hostingde{
+		zones:   map[string]*hostingdeZone{},
+		domains: map[string]*hostingdeDomain{},
+	}
+}
+
+// Creds returns the creds.json fields of the HOSTINGDE provider.
+func (m *Hostingde) Creds(url string) map[string]string {
+	return map[string]string{"authToken": "mock-token", "baseURL": url}
+}
+
+// AddZone creates a zone with the SOA and NS records of hosting.de,
+// and a domain with the hosting.de nameservers.
+func (m *Hostingde) AddZone(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	z := m.createZone(name)
+	z.add(m.id(), &hostingdeRecord{Name: name, Type: "SOA", Content: "ns1.hosting.de. hostmaster." + name + ". 1 10800 3600 604800 3600", TTL: 86400})
+	for _, ns := range []string{"ns1.hosting.de", "ns2.hosting.de", "ns3.hosting.de"} {
+		z.add(m.id(), &hostingdeRecord{Name: name, Type: "NS", Content: ns, TTL: 86400})
+	}
+}
+
+// AddRecord adds a record to the zone name. The name of the record is
+// a FQDN, and the content is as the API returns it.
+func (m *Hostingde) AddRecord(zone, name, rtype, content string, ttl uint32, priority uint16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if z, ok := m.zones[zone]; ok {
+		z.add(m.id(), &hostingdeRecord{Name: name, Type: rtype, Content: content, TTL: ttl, Priority: priority})
+	}
+}
+
+// SetDomainExpiry sets the end of the current contract period of the
+// domain name.
+func (m *Hostingde) SetDomainExpiry(name string, end time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if d, ok := m.domains[name]; ok {
+		d.CurrentContractPeriodEnd = &end
+	}
+}
+
+func (m *Hostingde) id() string {
+	m.nextID++
+	return fmt.Sprintf("%016x", m.nextID)
+}
+
+func (m *Hostingde) createZone(name string) *hostingdeZone {
+	z := &hostingdeZone{ID: m.id(), Name: name, Type: "NATIVE"}
+	m.zones[name] = z
+	d := &hostingdeDomain{Name: name}
+	for _, ns := range []string{"ns1.hosting.de", "ns2.hosting.de", "ns3.hosting.de"} {
+		d.Nameservers = append(d.Nameservers, hostingdeNameserver{Name: ns})
+	}
+	m.domains[name] = d
+	return z
+}
+
+func (z *hostingdeZone) add(id string, r *hostingdeRecord) {
+	r.ID = id
+	r.ZoneConfigID = z.ID
+	z.records = append(z.records, r)
+}
+
+func (z *hostingdeZone) find(id string) int {
+	for i, r := range z.records {
+		if r.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// zoneConfig is the zone config as the API returns it.
+func (z *hostingdeZone) zoneConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"id":                    z.ID,
+		"name":                  z.Name,
+		"nameUnicode":           z.Name,
+		"type":                  z.Type,
+		"dnsSecMode":            "off",
+		"masterIp":              "",
+		"zoneTransferWhitelist": []string{},
+	}
+}
+
+// ServeHTTP serves POST /api/SERVICE/v1/json/METHOD.
+func (m *Hostingde) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if r.Method != "POST" || len(parts) != 5 || parts[0] != "api" || parts[2] != "v1" || parts[3] != "json" {
+		http.NotFound(w, r)
+		return
+	}
+	var req hostingdeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.AuthToken == "" {
+		hostingdeError(w, hostingdeErrAuth, "Authentication failed")
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch parts[1] + "/" + parts[4] {
+	case "dns/zoneConfigsFind":
+		var found []map[string]interface{}
+		for _, name := range m.sortedZones() {
+			if req.Filter.Value == "*" || req.Filter.Value == name {
+				found = append(found, m.zones[name].zoneConfig())
+			}
+		}
+		hostingdePage(w, found, req.Page, req.Limit)
+	case "dns/recordsFind":
+		var found []*hostingdeRecord
+		for _, z := range m.zones {
+			if z.ID == req.Filter.Value {
+				found = z.records
+			}
+		}
+		hostingdePage(w, found, req.Page, req.Limit)
+	case "dns/zoneCreate":
+		if req.ZoneConfig == nil || m.zones[req.ZoneConfig.Name] != nil {
+			hostingdeError(w, hostingdeErrZoneExists, "Zone already exists")
+			return
+		}
+		z := m.createZone(req.ZoneConfig.Name)
+		for _, rec := range req.Records {
+			z.add(m.id(), rec)
+		}
+		hostingdeOK(w, z.zoneConfig())
+	case "dns/zoneUpdate":
+		m.zoneUpdate(w, req)
+	case "domain/domainsFind":
+		var found []*hostingdeDomain
+		if d, ok := m.domains[req.Filter.Value]; ok {
+			found = append(found, d)
+		}
+		hostingdePage(w, found, 1, 0)
+	case "domain/domainUpdate":
+		if req.Domain == nil || m.domains[req.Domain.Name] == nil {
+			hostingdeError(w, hostingdeErrZoneNotFound, "Domain not found")
+			return
+		}
+		req.Domain.CurrentContractPeriodEnd = m.domains[req.Domain.Name].CurrentContractPeriodEnd
+		m.domains[req.Domain.Name] = req.Domain
+		hostingdeOK(w, req.Domain)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// zoneUpdate deletes, modifies and adds records in one transaction.
+func (m *Hostingde) zoneUpdate(w http.ResponseWriter, req hostingdeRequest) {
+	var z *hostingdeZone
+	for _, zone := range m.zones {
+		if req.ZoneConfig != nil && zone.ID == req.ZoneConfig.ID {
+			z = zone
+		}
+	}
+	if z == nil {
+		hostingdeError(w, hostingdeErrZoneNotFound, "Zone not found")
+		return
+	}
+	records := append([]*hostingdeRecord(nil), z.records...)
+	for _, rec := range append(req.RecordsToDelete, req.RecordsToModify...) {
+		if z.find(rec.ID) < 0 {
+			hostingdeError(w, hostingdeErrRecord, fmt.Sprintf("Record %s not found", rec.ID))
+			return
+		}
+	}
+	for _, rec := range req.RecordsToDelete {
+		i := z.find(rec.ID)
+		z.records = append(z.records[:i:i], z.records[i+1:]...)
+	}
+	for _, rec := range req.RecordsToModify {
+		rec.ZoneConfigID = z.ID
+		z.records[z.find(rec.ID)] = rec
+	}
+	for _, rec := range req.RecordsToAdd {
+		for _, other := range z.records {
+			if other.Name == rec.Name && other.Type == rec.Type && other.Content == rec.Content {
+				z.records = records
+				hostingdeError(w, hostingdeErrRecord, fmt.Sprintf("Record %s %s %s already exists", rec.Name, rec.Type, rec.Content))
+				return
+			}
+		}
+		z.add(m.id(), rec)
+	}
+	hostingdeOK(w, z.zoneConfig())
+}
+
+func (m *Hostingde) sortedZones() []string {
+	var names []string
+	for name := range m.zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func hostingdeOK(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"errors":   []interface{}{},
+		"status":   "success",
+		"response": data,
+	})
+}
+
+func hostingdePage[T any](w http.ResponseWriter, items []T, page, limit int) {
+	if page < 1 {
+		page = 1
+	}
+	data, pages := Paginate(items, page, limit)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"errors": []interface{}{},
+		"status": "success",
+		"response": map[string]interface{}{
+			"data":       data,
+			"limit":      limit,
+			"page":       page,
+			"totalPages": pages,
+		},
+	})
+}
+
+// hostingdeError answers with an error, which the API reports with the
+// status 200.
+func hostingdeError(w http.ResponseWriter, code int, text string) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"errors": []map[string]interface{}{{"code": code, "text": text}},
+		"status": "error",
+	})
+}
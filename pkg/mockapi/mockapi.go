@@ -0,0 +1,174 @@
+// Package mockapi serves fakes of the APIs of DNS providers with
+// net/http/httptest. A provider pointed at a mock runs without
+// credentials, so its pagination, retries and handling of API errors
+// can be tested in CI, and integrationTest can run its whole suite
+// against a mock (go test -provider NAME -mock).
+//
+// A mock keeps its zones in memory and implements as much of the API
+// as the provider uses, answering as the API documents. It is not a
+// reference for the API: when the real API and a mock disagree, fix
+// the mock.
+package mockapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A Mock serves the API of a provider type.
+type Mock interface {
+	http.Handler
+	// Creds returns the creds.json fields that make the provider use
+	// the API at url.
+	Creds(url string) map[string]string
+	// AddZone creates a zone, as if it had been created in the web UI.
+	AddZone(name string)
+}
+
+var mocks = map[string]func() Mock{}
+
+// Register makes the mocks of providerType available to Start.
+func Register(providerType string, fn func() Mock) {
+	if _, ok := mocks[providerType]; ok {
+		panic(fmt.Sprintf("mockapi: %s is already registered", providerType))
+	}
+	mocks[providerType] = fn
+}
+
+// Types returns the provider types that have a mock.
+func Types() []string {
+	var types []string
+	for t := range mocks {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// A Fault is an error that the server returns instead of passing a
+// request to the mock.
+type Fault struct {
+	Method string // Only requests with this method fail; "" is any method.
+	Path   string // Only requests whose path contains Path fail.
+	Status int    // The HTTP status; 0 is 500.
+	Header http.Header
+	Body   string
+	Times  int // The number of requests that fail; 0 is 1.
+}
+
+// Server serves a Mock, and records the requests it receives.
+type Server struct {
+	*httptest.Server
+	Mock Mock
+
+	mu       sync.Mutex
+	requests []string
+	faults   []*Fault
+}
+
+// Start serves a new mock of providerType.
+func Start(providerType string) (*Server, error) {
+	fn, ok := mocks[providerType]
+	if !ok {
+		return nil, fmt.Errorf("mockapi: no mock of %s (mocks: %s)", providerType, strings.Join(Types(), ", "))
+	}
+	return NewServer(fn()), nil
+}
+
+// NewServer serves m.
+func NewServer(m Mock) *Server {
+	s := &Server{Mock: m}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Creds returns the creds.json fields of a provider that uses the
+// server.
+func (s *Server) Creds() map[string]string {
+	return s.Mock.Creds(s.URL)
+}
+
+// Inject makes the next requests that match f fail.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f.Status == 0 {
+		f.Status = http.StatusInternalServerError
+	}
+	if f.Times == 0 {
+		f.Times = 1
+	}
+	s.faults = append(s.faults, &f)
+}
+
+// Requests returns the requests received so far, as "METHOD /path?query".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if f := s.record(r); f != nil {
+		for k, v := range f.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(f.Status)
+		fmt.Fprint(w, f.Body)
+		return
+	}
+	s.Mock.ServeHTTP(w, r)
+}
+
+// record adds r to the requests, and returns the fault that it
+// triggers, if any.
+func (s *Server) record(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+	for i, f := range s.faults {
+		if (f.Method == "" || f.Method == r.Method) && strings.Contains(r.URL.Path, f.Path) {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+			return f
+		}
+	}
+	return nil
+}
+
+// Paginate returns the items on page (from 1) when there are perPage
+// items per page, and the number of pages (at least 1).
+func Paginate[T any](items []T, page, perPage int) ([]T, int) {
+	if perPage <= 0 {
+		return items, 1
+	}
+	pages := (len(items) + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * perPage
+	if start >= len(items) {
+		return []T{}, pages
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end], pages
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
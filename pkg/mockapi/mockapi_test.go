@@ -0,0 +1,76 @@
+package mockapi
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	for _, tc := range []struct {
+		page, perPage int
+		want          []int
+		wantPages     int
+	}{
+		{1, 2, []int{1, 2}, 3},
+		{3, 2, []int{5}, 3},
+		{4, 2, []int{}, 3},
+		{1, 0, []int{1, 2, 3, 4, 5}, 1},
+		{0, 10, []int{1, 2, 3, 4, 5}, 1},
+	} {
+		got, pages := Paginate(items, tc.page, tc.perPage)
+		if !reflect.DeepEqual(got, tc.want) || pages != tc.wantPages {
+			t.Errorf("Paginate(page=%d, perPage=%d) = %v, %d; want %v, %d", tc.page, tc.perPage, got, pages, tc.want, tc.wantPages)
+		}
+	}
+	if _, pages := Paginate([]int{}, 1, 10); pages != 1 {
+		t.Errorf("Paginate(empty) has %d pages, want 1", pages)
+	}
+}
+
+func TestInject(t *testing.T) {
+	s := NewServer(NewCloudflare())
+	defer s.Close()
+	s.Inject(Fault{Path: "/zones", Status: http.StatusTooManyRequests, Times: 2})
+
+	get := func() int {
+		req, _ := http.NewRequest("GET", s.URL+"/zones", nil)
+		req.Header.Set("Authorization", "Bearer mock-token")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	var got []int
+	for i := 0; i < 3; i++ {
+		got = append(got, get())
+	}
+	if want := []int{429, 429, 200}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got statuses %v, want %v", got, want)
+	}
+	if want := "GET /zones"; strings.Join(s.Requests(), ",") != strings.Repeat(want+",", 2)+want {
+		t.Errorf("got requests %q", s.Requests())
+	}
+}
+
+func TestStart(t *testing.T) {
+	for _, name := range Types() {
+		s, err := Start(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Creds()["baseURL"] != s.URL {
+			t.Errorf("%s: the creds don't point at the mock: %v", name, s.Creds())
+		}
+		s.Close()
+	}
+	if _, err := Start("NOSUCHPROVIDER"); err == nil {
+		t.Error("Start(NOSUCHPROVIDER) should fail")
+	}
+}
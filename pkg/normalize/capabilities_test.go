@@ -4,9 +4,12 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/providers"
 )
 
 const providersImportDir = "../../providers"
@@ -17,6 +20,7 @@ func TestCapabilitiesAreFiltered(t *testing.T) {
 	// something we can test against.
 	skipCheckCapabilities := make(map[string]struct{})
 	//skipCheckCapabilities["CanUseBlahBlahBlah"] = struct{}{}
+	skipCheckCapabilities["CanUseTXTMulti"] = struct{}{} // checkProviderTXT()
 
 	fset := token.NewFileSet()
 	pkgs, err := parser.ParseDir(fset, providersImportDir, nil, 0)
@@ -72,3 +76,18 @@ func TestCapabilitiesAreFiltered(t *testing.T) {
 	}
 
 }
+
+func TestCapabilitiesOfType(t *testing.T) {
+	if caps := CapabilitiesOfType("A"); caps != nil {
+		t.Errorf("A needs %v, want nothing", caps)
+	}
+	if caps := CapabilitiesOfType("DS"); !reflect.DeepEqual(caps, []providers.Capability{providers.CanUseDS, providers.CanUseDSForChildren}) {
+		t.Errorf("DS needs %v", caps)
+	}
+	if types := TypesOfCapability(providers.CanUseDSForChildren); !reflect.DeepEqual(types, []string{"DS"}) {
+		t.Errorf("CanUseDSForChildren allows %v, want [DS]", types)
+	}
+	if types := TypesOfCapability(providers.CanAutoDNSSEC); types != nil {
+		t.Errorf("CanAutoDNSSEC allows %v, want no types of records", types)
+	}
+}
@@ -0,0 +1,82 @@
+package normalize
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// checkCNAMEChains implements CNAME_CHAINS(): it follows each CNAME of
+// dc through the other CNAMEs of dc, and either warns about the chains
+// longer than dc.CNAMEChains.MaxHops or points their first CNAME to the
+// end of the chain. A loop is always an error.
+func checkCNAMEChains(dc *models.DomainConfig) (errs []error) {
+	policy := dc.CNAMEChains
+	if policy == nil {
+		return nil
+	}
+	cnames := map[string]*models.RecordConfig{}
+	for _, r := range dc.Records {
+		if r.Type == "CNAME" {
+			cnames[strings.ToLower(r.GetLabelFQDN())] = r
+		}
+	}
+
+	// The ends are found before any target is changed, so that the
+	// result doesn't depend on the order of the records.
+	ends := map[*models.RecordConfig]string{}
+	loops := map[string]bool{} // The loops reported, by the least of their names.
+	for _, r := range dc.Records {
+		if r.Type != "CNAME" {
+			continue
+		}
+		chain := []string{r.GetLabelFQDN()}
+		seen := map[string]int{strings.ToLower(r.GetLabelFQDN()): 0} // Index in chain
+		target := r.GetTargetField()
+		loop := -1
+		for {
+			name := strings.ToLower(strings.TrimSuffix(target, "."))
+			next, ok := cnames[name]
+			if !ok {
+				break
+			}
+			if i, ok := seen[name]; ok {
+				loop = i
+				break
+			}
+			seen[name] = len(chain)
+			chain = append(chain, strings.TrimSuffix(target, "."))
+			target = next.GetTargetField()
+		}
+		if loop >= 0 {
+			// The CNAMEs that lead to a loop or are part of it all find
+			// the same loop; it is reported once.
+			var members []string
+			for _, name := range chain[loop:] {
+				members = append(members, strings.ToLower(name))
+			}
+			sort.Strings(members)
+			if !loops[members[0]] {
+				loops[members[0]] = true
+				errs = append(errs, fmt.Errorf("CNAME loop in domain %s: %s -> %s", dc.Name, strings.Join(chain[loop:], " -> "), chain[loop]))
+			}
+			continue
+		}
+		chain = append(chain, strings.TrimSuffix(target, "."))
+		hops := len(chain) - 1
+		if hops <= policy.MaxHops {
+			continue
+		}
+		if policy.Action == "flatten" {
+			ends[r] = target
+			continue
+		}
+		errs = append(errs, Warning{fmt.Errorf("CNAME chain of %d hops (CNAME_CHAINS allows %d): %s", hops, policy.MaxHops, strings.Join(chain, " -> "))})
+	}
+	for r, target := range ends {
+		r.SetTarget(target)
+	}
+	return errs
+}
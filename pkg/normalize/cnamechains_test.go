@@ -0,0 +1,66 @@
+package normalize
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestCheckCNAMEChains(t *testing.T) {
+	makeDC := func(action string) *models.DomainConfig {
+		cname := func(label, target string) *models.RecordConfig {
+			return makeRC(label, "example.com", target, models.RecordConfig{Type: "CNAME"})
+		}
+		return &models.DomainConfig{
+			Name:        "example.com",
+			CNAMEChains: &models.CNAMEChains{MaxHops: 1, Action: action},
+			Records: []*models.RecordConfig{
+				cname("www", "web.example.com."),
+				cname("web", "lb.example.com."),
+				cname("lb", "lb.cdn.example.net."),
+				cname("api", "host.example.com."),
+				makeRC("host", "example.com", "192.0.2.1", models.RecordConfig{Type: "A"}),
+				cname("loop1", "loop2.example.com."),
+				cname("loop2", "LOOP1.example.com."),
+				cname("toloop", "loop1.example.com."),
+			},
+		}
+	}
+	targets := func(dc *models.DomainConfig) []string {
+		var s []string
+		for _, r := range dc.Records {
+			s = append(s, r.GetTargetField())
+		}
+		return s
+	}
+	loop := "CNAME loop in domain example.com: loop1.example.com -> loop2.example.com -> loop1.example.com"
+
+	dc := makeDC("warn")
+	var got []string
+	for _, err := range checkCNAMEChains(dc) {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"CNAME chain of 3 hops (CNAME_CHAINS allows 1): www.example.com -> web.example.com -> lb.example.com -> lb.cdn.example.net",
+		"CNAME chain of 2 hops (CNAME_CHAINS allows 1): web.example.com -> lb.example.com -> lb.cdn.example.net",
+		loop,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("warn: got %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(targets(dc), targets(makeDC("warn"))) {
+		t.Errorf("warn changed the targets: %q", targets(dc))
+	}
+
+	dc = makeDC("flatten")
+	errs := checkCNAMEChains(dc)
+	if len(errs) != 1 || errs[0].Error() != loop {
+		t.Errorf("flatten: got %v, want only the loop", errs)
+	}
+	gotTargets := targets(dc)
+	wantTargets := []string{"lb.cdn.example.net.", "lb.cdn.example.net.", "lb.cdn.example.net.", "host.example.com.", "192.0.2.1", "loop2.example.com.", "LOOP1.example.com.", "loop1.example.com."}
+	if !reflect.DeepEqual(gotTargets, wantTargets) {
+		t.Errorf("flatten: got targets %q, want %q", gotTargets, wantTargets)
+	}
+}
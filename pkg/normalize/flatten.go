@@ -8,6 +8,7 @@ import (
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/spflib"
+	"github.com/StackExchange/dnscontrol/v3/providers"
 	"golang.org/x/exp/constraints"
 )
 
@@ -22,9 +23,20 @@ func sortedKeys[K constraints.Ordered, V any](m map[K]V) []K {
 	return keys
 }
 
-// hasSpfRecords returns true if this record requests SPF unrolling.
+// spfCacheFile is the flattening cache used when SPF_BUILDER does not
+// name one.
+const spfCacheFile = "spfcache.json"
+
+// updatedCacheFile returns the name the refreshed copy of a cache file
+// is written to: "spfcache.json" becomes "spfcache.updated.json".
+func updatedCacheFile(filename string) string {
+	return strings.TrimSuffix(filename, ".json") + ".updated.json"
+}
+
+// flattenSPFs flattens and splits the TXT records that SPF_BUILDER
+// marked with the "flatten" or "split" metadata.
 func flattenSPFs(cfg *models.DNSConfig) []error {
-	var cache spflib.CachingResolver
+	caches := map[string]spflib.CachingResolver{}
 	var errs []error
 	var err error
 	for _, domain := range cfg.Domains {
@@ -33,19 +45,31 @@ func flattenSPFs(cfg *models.DNSConfig) []error {
 		for _, txt := range txtRecords {
 			var rec *spflib.SPFRecord
 			txtTarget := strings.Join(txt.TxtStrings, "")
-			if txt.Metadata["flatten"] != "" || txt.Metadata["split"] != "" {
-				if cache == nil {
-					cache, err = spflib.NewCache("spfcache.json")
-					if err != nil {
-						return []error{err}
-					}
-				}
-				rec, err = spflib.Parse(txtTarget, cache)
+			if txt.Metadata["flatten"] == "" && txt.Metadata["split"] == "" && txt.Metadata["lookupLimit"] == "" {
+				continue
+			}
+
+			// Each SPF_BUILDER may keep its lookups in a cache file of
+			// its own (for example, one per domain).
+			cacheFile := spfCacheFile
+			if f := txt.Metadata["spfcache"]; f != "" {
+				cacheFile = f
+			}
+			cache, ok := caches[cacheFile]
+			if !ok {
+				cache, err = spflib.NewCache(cacheFile)
 				if err != nil {
-					errs = append(errs, err)
+					errs = append(errs, fmt.Errorf("reading SPF cache %s: %w", cacheFile, err))
 					continue
 				}
+				caches[cacheFile] = cache
 			}
+			rec, err = spflib.Parse(txtTarget, cache)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
 			if flatten, ok := txt.Metadata["flatten"]; ok && strings.HasPrefix(txtTarget, "v=spf1") {
 				rec = rec.Flatten(flatten)
 				err = txt.SetTargetTXT(rec.TXT())
@@ -54,7 +78,10 @@ func flattenSPFs(cfg *models.DNSConfig) []error {
 					continue
 				}
 			}
+
 			// now split if needed
+			var extra []*models.RecordConfig
+			lookups := rec.Lookups()
 			if split, ok := txt.Metadata["split"]; ok {
 
 				overhead1 := 0
@@ -86,38 +113,104 @@ func flattenSPFs(cfg *models.DNSConfig) []error {
 					errs = append(errs, Warning{fmt.Errorf("split format `%s` in `%s` is not proper format (missing %%d)", split, txt.GetLabelFQDN())})
 					continue
 				}
-				recs := rec.TXTSplit(split+"."+domain.Name, overhead1, txtMaxSize)
-
-				for _, k := range sortedKeys(recs) {
-					v := recs[k]
-					if k == "@" {
-						txt.SetTargetTXTs(v)
-					} else {
-						cp, _ := txt.Copy()
-						cp.SetTargetTXTs(v)
-						cp.SetLabelFromFQDN(k, domain.Name)
-						domain.Records = append(domain.Records, cp)
+				extra = splitSPF(txt, rec, split+"."+domain.Name, overhead1, txtMaxSize, domain.Name)
+
+				// If a provider can't store TXT records with
+				// several strings, fall back to one string per record.
+				if txtMaxSize > 255 {
+					if pType, aerr := auditSPF(domain, txt, extra); aerr != nil {
+						errs = append(errs, Warning{fmt.Errorf("SPF record %s: %s rejects it when split with txtMaxSize %d (%s); splitting with txtMaxSize 255 instead", txt.GetLabelFQDN(), pType, txtMaxSize, aerr)})
+						extra = splitSPF(txt, rec, split+"."+domain.Name, overhead1, 255, domain.Name)
 					}
 				}
+
+				// Each additional record in the chain is reached
+				// through an include, which costs a lookup.
+				lookups += len(extra)
+			}
+
+			// Don't publish a flattened record that a provider would
+			// reject. The unflattened record is used instead.
+			if pType, aerr := auditSPF(domain, txt, extra); aerr != nil {
+				hint := ""
+				if _, ok := txt.Metadata["split"]; !ok {
+					hint = ". Set overflow to split it"
+				}
+				errs = append(errs, Warning{fmt.Errorf("SPF record %s: %s rejects the flattened record (%s); using the unflattened record instead%s", txt.GetLabelFQDN(), pType, aerr, hint)})
+				if err := txt.SetTargetTXT(txtTarget); err != nil {
+					errs = append(errs, err)
+				}
+				continue
 			}
+
+			if ll, ok := txt.Metadata["lookupLimit"]; ok {
+				limit, err := strconv.Atoi(ll)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("SPF record %s: lookupLimit %q is not an int", txt.GetLabelFQDN(), ll))
+				} else if lookups > limit {
+					errs = append(errs, fmt.Errorf("SPF record %s requires %d DNS lookups, which exceeds its lookupLimit of %d; flatten more includes", txt.GetLabelFQDN(), lookups, limit))
+				}
+			}
+
+			domain.Records = append(domain.Records, extra...)
 		}
 	}
-	if cache == nil {
-		return errs
-	}
-	// check if cache is stale
-	for _, e := range cache.ResolveErrors() {
-		errs = append(errs, Warning{fmt.Errorf("problem resolving SPF record: %s", e)})
-	}
-	if len(cache.ResolveErrors()) == 0 {
-		changed := cache.ChangedRecords()
-		if len(changed) > 0 {
-			if err := cache.Save("spfcache.updated.json"); err != nil {
-				errs = append(errs, err)
-			} else {
-				errs = append(errs, Warning{fmt.Errorf("%d spf record lookups are out of date with cache (%s).\nWrote changes to spfcache.updated.json. Please rename and commit:\n    $ mv spfcache.updated.json spfcache.json\n    $ git commit -m 'Update spfcache.json' spfcache.json", len(changed), strings.Join(changed, ","))})
+
+	for _, cacheFile := range sortedKeys(caches) {
+		cache := caches[cacheFile]
+		// check if cache is stale
+		for _, e := range cache.ResolveErrors() {
+			errs = append(errs, Warning{fmt.Errorf("problem resolving SPF record: %s", e)})
+		}
+		if len(cache.ResolveErrors()) == 0 {
+			changed := cache.ChangedRecords()
+			if len(changed) > 0 {
+				updated := updatedCacheFile(cacheFile)
+				if err := cache.Save(updated); err != nil {
+					errs = append(errs, err)
+				} else {
+					errs = append(errs, Warning{fmt.Errorf("%d spf record lookups are out of date with cache (%s).\nWrote changes to %s. Please rename and commit:\n    $ mv %s %s\n    $ git commit -m 'Update %s' %s", len(changed), strings.Join(changed, ","), updated, updated, cacheFile, cacheFile, cacheFile)})
+				}
 			}
 		}
 	}
 	return errs
 }
+
+// splitSPF splits rec into a chain of TXT records. txt becomes the
+// first record of the chain; the others are returned.
+func splitSPF(txt *models.RecordConfig, rec *spflib.SPFRecord, pattern string, overhead1, txtMaxSize int, origin string) []*models.RecordConfig {
+	var extra []*models.RecordConfig
+	recs := rec.TXTSplit(pattern, overhead1, txtMaxSize)
+	for _, k := range sortedKeys(recs) {
+		v := recs[k]
+		if k == "@" {
+			txt.SetTargetTXTs(v)
+		} else {
+			cp, _ := txt.Copy()
+			cp.SetTargetTXTs(v)
+			cp.SetLabelFromFQDN(k, origin)
+			extra = append(extra, cp)
+		}
+	}
+	return extra
+}
+
+// auditSPF asks each of the domain's DNS providers whether it can
+// store the generated SPF records. It returns the type of the first
+// provider that can't, and its reason.
+func auditSPF(domain *models.DomainConfig, txt *models.RecordConfig, extra []*models.RecordConfig) (string, error) {
+	recs := append(models.Records{txt}, extra...)
+	for _, provider := range domain.DNSProviderInstances {
+		pType := provider.ProviderType
+		if fns, ok := providers.DNSProviderTypes[pType]; !ok || fns.RecordAuditor == nil {
+			// Unknown (or "-", during "dnscontrol check"). The
+			// full audit in ValidateAndNormalizeConfig reports this.
+			continue
+		}
+		if es := providers.AuditRecords(pType, recs); len(es) != 0 {
+			return pType, es[0]
+		}
+	}
+	return "", nil
+}
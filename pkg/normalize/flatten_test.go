@@ -0,0 +1,142 @@
+package normalize
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/rejectif"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+const (
+	ProviderShortTXT  = "SHORT_TXT_ONLY"
+	ProviderSingleTXT = "SINGLE_TXT_STRING"
+)
+
+func init() {
+	providers.RegisterDomainServiceProviderType(ProviderShortTXT, providers.DspFuncs{
+		RecordAuditor: func(rcs []*models.RecordConfig) []error {
+			a := rejectif.Auditor{}
+			a.Add("TXT", rejectif.TxtHasSegmentLen256orLonger)
+			return a.Audit(rcs)
+		},
+	}, providers.DocumentationNotes{})
+	providers.RegisterDomainServiceProviderType(ProviderSingleTXT, providers.DspFuncs{
+		RecordAuditor: func(rcs []*models.RecordConfig) []error {
+			a := rejectif.Auditor{}
+			a.Add("TXT", rejectif.TxtHasMultipleSegments)
+			return a.Audit(rcs)
+		},
+	}, providers.DocumentationNotes{})
+}
+
+// longSPF returns an SPF record with n ip4 parts and no lookups.
+func longSPF(n int) string {
+	parts := []string{"v=spf1"}
+	for i := 0; i < n; i++ {
+		parts = append(parts, fmt.Sprintf("ip4:192.0.2.%d", i))
+	}
+	return strings.Join(append(parts, "~all"), " ")
+}
+
+func spfDomain(pType, spf string, meta map[string]string) *models.DomainConfig {
+	txt := &models.RecordConfig{Type: "TXT", Metadata: meta}
+	txt.SetLabel("@", "example.com")
+	txt.SetTargetTXT(spf)
+	return &models.DomainConfig{
+		Name:    "example.com",
+		Records: models.Records{txt},
+		DNSProviderInstances: []*models.DNSProviderInstance{
+			{ProviderBase: models.ProviderBase{ProviderType: pType}},
+		},
+	}
+}
+
+func countWarnings(errs []error) (warnings, others int) {
+	for _, err := range errs {
+		if _, ok := err.(Warning); ok {
+			warnings++
+		} else {
+			others++
+		}
+	}
+	return warnings, others
+}
+
+func TestFlattenSPFs_lookupLimit(t *testing.T) {
+	tests := []struct {
+		spf     string
+		meta    map[string]string
+		wantErr bool
+	}{
+		{"v=spf1 a mx ~all", map[string]string{"lookupLimit": "2"}, false},
+		{"v=spf1 a mx exists:example.net ~all", map[string]string{"lookupLimit": "2"}, true},
+		// Each record in the chain adds an include.
+		{"v=spf1 a " + longSPF(30)[7:], map[string]string{"lookupLimit": "1"}, false},
+		{"v=spf1 a " + longSPF(30)[7:], map[string]string{"lookupLimit": "1", "split": "_spf%d"}, true},
+		{"v=spf1 a " + longSPF(30)[7:], map[string]string{"lookupLimit": "3", "split": "_spf%d"}, false},
+		{"v=spf1 a ~all", map[string]string{"lookupLimit": "ten"}, true},
+	}
+	for i, tst := range tests {
+		dc := spfDomain("-", tst.spf, tst.meta)
+		_, others := countWarnings(flattenSPFs(&models.DNSConfig{Domains: []*models.DomainConfig{dc}}))
+		if (others != 0) != tst.wantErr {
+			t.Errorf("%d: %q %v: got %d errors, want error %v", i, tst.spf, tst.meta, others, tst.wantErr)
+		}
+	}
+}
+
+func TestFlattenSPFs_providerLimits(t *testing.T) {
+	spf := longSPF(30)
+
+	t.Run("unsplit record too long", func(t *testing.T) {
+		dc := spfDomain(ProviderShortTXT, spf, map[string]string{"flatten": "*"})
+		errs := flattenSPFs(&models.DNSConfig{Domains: []*models.DomainConfig{dc}})
+		if w, o := countWarnings(errs); w != 1 || o != 0 {
+			t.Fatalf("got %v, want one warning", errs)
+		}
+		if len(dc.Records) != 1 || strings.Join(dc.Records[0].TxtStrings, "") != spf {
+			t.Errorf("expected the unflattened record, got %v", dc.Records)
+		}
+	})
+
+	t.Run("multiple strings not supported", func(t *testing.T) {
+		dc := spfDomain(ProviderSingleTXT, spf, map[string]string{"split": "_spf%d", "txtMaxSize": "450"})
+		errs := flattenSPFs(&models.DNSConfig{Domains: []*models.DomainConfig{dc}})
+		if w, o := countWarnings(errs); w != 1 || o != 0 {
+			t.Fatalf("got %v, want one warning", errs)
+		}
+		if len(dc.Records) < 2 {
+			t.Fatalf("expected the record to be split, got %d records", len(dc.Records))
+		}
+		for _, rc := range dc.Records {
+			if len(rc.TxtStrings) != 1 || len(rc.TxtStrings[0]) > 255 {
+				t.Errorf("%s: expected one string of at most 255 bytes, got %q", rc.GetLabel(), rc.TxtStrings)
+			}
+		}
+	})
+
+	t.Run("record fits", func(t *testing.T) {
+		dc := spfDomain(ProviderShortTXT, spf, map[string]string{"split": "_spf%d"})
+		if errs := flattenSPFs(&models.DNSConfig{Domains: []*models.DomainConfig{dc}}); len(errs) != 0 {
+			t.Fatalf("unexpected errors %v", errs)
+		}
+		if len(dc.Records) < 2 {
+			t.Errorf("expected the record to be split, got %d records", len(dc.Records))
+		}
+	})
+}
+
+func TestUpdatedCacheFile(t *testing.T) {
+	for in, want := range map[string]string{
+		"spfcache.json":        "spfcache.updated.json",
+		"spf/example.com.json": "spf/example.com.updated.json",
+		"spfcache":             "spfcache.updated.json",
+	} {
+		if got := updatedCacheFile(in); got != want {
+			t.Errorf("updatedCacheFile(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
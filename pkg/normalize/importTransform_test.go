@@ -47,3 +47,35 @@ func TestImportTransform(t *testing.T) {
 		t.Fatalf("Expected 3 records in internal, but got %d", len(d.Records))
 	}
 }
+
+func TestImportTransformIP6(t *testing.T) {
+	src := &models.DomainConfig{
+		Name: "stackexchange.com",
+		Records: []*models.RecordConfig{
+			makeRC("www", "stackexchange.com", "0.0.1.1", models.RecordConfig{Type: "A"}),
+			makeRC("www", "stackexchange.com", "2001:db8::11", models.RecordConfig{Type: "AAAA"}),
+		},
+	}
+	dst := &models.DomainConfig{
+		Name: "internal",
+		Records: []*models.RecordConfig{
+			makeRC("@", "internal", "stackexchange.com", models.RecordConfig{Type: "IMPORT_TRANSFORM", Metadata: map[string]string{"transform_table": "2001:db8::/64 ~ ~ fd00:1:: ~"}}),
+		},
+	}
+	cfg := &models.DNSConfig{
+		Domains: []*models.DomainConfig{src, dst},
+	}
+	if errs := ValidateAndNormalizeConfig(cfg); len(errs) != 0 {
+		for _, err := range errs {
+			t.Error(err)
+		}
+		t.FailNow()
+	}
+	got := map[string]string{}
+	for _, r := range cfg.FindDomain("internal").Records {
+		got[r.Type] = r.GetTargetField()
+	}
+	if got["A"] != "0.0.1.1" || got["AAAA"] != "fd00:1::11" {
+		t.Errorf("Expected A 0.0.1.1 and AAAA fd00:1::11, got %v", got)
+	}
+}
@@ -1,11 +1,16 @@
 package normalize
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/mailauth"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ownership"
+	"github.com/StackExchange/dnscontrol/v3/pkg/rejectif"
 	"github.com/StackExchange/dnscontrol/v3/pkg/transform"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	"github.com/miekg/dns"
@@ -58,7 +63,9 @@ func validateRecordTypes(rec *models.RecordConfig, domain string, pTypes []strin
 		"CAA":              true,
 		"CNAME":            true,
 		"DS":               true,
+		"HTTPS":            true,
 		"IMPORT_TRANSFORM": false,
+		"LOC":              true,
 		"MX":               true,
 		"NAPTR":            true,
 		"NS":               true,
@@ -66,10 +73,15 @@ func validateRecordTypes(rec *models.RecordConfig, domain string, pTypes []strin
 		"SOA":              true,
 		"SRV":              true,
 		"SSHFP":            true,
+		"SVCB":             true,
 		"TLSA":             true,
 		"TXT":              true,
+		"URI":              true,
 	}
 	_, ok := validTypes[rec.Type]
+	if !ok && models.IsRFC3597Type(rec.Type) {
+		return nil
+	}
 	if !ok {
 		cType := providers.GetCustomRecordType(rec.Type)
 		if cType == nil {
@@ -181,10 +193,7 @@ func checkTargets(rec *models.RecordConfig, domain string) (errs []error) {
 	case "ALIAS":
 		check(checkTarget(target))
 	case "CNAME":
-		check(checkTarget(target))
-		if label == "@" {
-			check(fmt.Errorf("cannot create CNAME record for bare domain"))
-		}
+		check(checkTarget(target)) // CNAMEs at the apex are checked by checkCNAMEs.
 	case "MX":
 		check(checkTarget(target))
 	case "NAPTR":
@@ -206,8 +215,17 @@ func checkTargets(rec *models.RecordConfig, domain string) (errs []error) {
 		}
 	case "SRV":
 		check(checkTarget(target))
-	case "TXT", "IMPORT_TRANSFORM", "CAA", "SSHFP", "TLSA", "DS":
+	case "HTTPS", "SVCB":
+		check(checkTarget(target))
+		if _, err := rec.GetSVCBValue(); err != nil {
+			check(err)
+		}
+	case "TXT", "IMPORT_TRANSFORM", "CAA", "SSHFP", "TLSA", "DS", "LOC", "URI":
 	default:
+		if models.IsRFC3597Type(rec.Type) {
+			// The rdata is checked when it is canonicalized.
+			return
+		}
 		if rec.Metadata["orig_custom_type"] != "" {
 			// it is a valid custom type. We perform no validation on target
 			return
@@ -230,10 +248,10 @@ func transformCNAME(target, oldDomain, newDomain string) string {
 // import_transform imports the records of one zone into another, modifying records along the way.
 func importTransform(srcDomain, dstDomain *models.DomainConfig, transforms []transform.IPConversion, ttl uint32) error {
 	// Read srcDomain.Records, transform, and append to dstDomain.Records:
-	// 1. Skip any that aren't A or CNAMEs.
+	// 1. Skip any that aren't A, AAAA or CNAMEs.
 	// 2. Append destDomainname to the end of the label.
 	// 3. For CNAMEs, append destDomainname to the end of the target.
-	// 4. For As, change the target as described the transforms.
+	// 4. For As and AAAAs, change the target as described the transforms.
 
 	for _, rec := range srcDomain.Records {
 		if dstDomain.Records.HasRecordTypeName(rec.Type, rec.GetLabelFQDN()) {
@@ -249,7 +267,7 @@ func importTransform(srcDomain, dstDomain *models.DomainConfig, transforms []tra
 			return rec2
 		}
 		switch rec.Type { // #rtype_variations
-		case "A":
+		case "A", "AAAA":
 			trs, err := transform.IPToList(net.ParseIP(rec.GetTargetField()), transforms)
 			if err != nil {
 				return fmt.Errorf("import_transform: TransformIP(%v, %v) returned err=%s", rec.GetTargetField(), transforms, err)
@@ -263,7 +281,7 @@ func importTransform(srcDomain, dstDomain *models.DomainConfig, transforms []tra
 			r := newRec()
 			r.SetTarget(transformCNAME(r.GetTargetField(), srcDomain.Name, dstDomain.Name))
 			dstDomain.Records = append(dstDomain.Records, r)
-		case "AKAMAICDN", "MX", "NAPTR", "NS", "SOA", "SRV", "TXT", "CAA", "TLSA":
+		case "AKAMAICDN", "HTTPS", "LOC", "MX", "NAPTR", "NS", "SOA", "SRV", "SVCB", "TXT", "CAA", "TLSA":
 			// Not imported.
 			continue
 		default:
@@ -297,6 +315,17 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 		return []error{err}
 	}
 
+	for _, p := range config.DNSProviders {
+		// "-" means that the type isn't known yet (see below).
+		// providers.CreateDNSProvider() checks the metadata then.
+		if p.Type == "-" {
+			continue
+		}
+		if err := providers.ValidateMetadata(p.Type, p.Metadata); err != nil {
+			errs = append(errs, fmt.Errorf("NewDnsProvider(%q): %w", p.Name, err))
+		}
+	}
+
 	for _, domain := range config.Domains {
 		pTypes := []string{}
 		for _, provider := range domain.DNSProviderInstances {
@@ -314,6 +343,27 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 			if domain.KeepUnknown && providers.ProviderHasCapability(pType, providers.CantUseNOPURGE) {
 				errs = append(errs, fmt.Errorf("%s uses NO_PURGE which is not supported by %s(%s)", domain.Name, provider.Name, pType))
 			}
+			// MANAGED_BY needs the same support as NO_PURGE.
+			if domain.ManagedBy != "" && providers.ProviderHasCapability(pType, providers.CantUseNOPURGE) {
+				errs = append(errs, fmt.Errorf("%s uses MANAGED_BY which is not supported by %s(%s)", domain.Name, provider.Name, pType))
+			}
+			// So do PURGE_ONLY and KEEP.
+			if (len(domain.PurgeOnly) > 0 || len(domain.Keep) > 0) && providers.ProviderHasCapability(pType, providers.CantUseNOPURGE) {
+				errs = append(errs, fmt.Errorf("%s uses PURGE_ONLY or KEEP which is not supported by %s(%s)", domain.Name, provider.Name, pType))
+			}
+		}
+
+		if domain.ManagedBy != "" {
+			if err := ownership.CheckOwner(domain.ManagedBy); err != nil {
+				errs = append(errs, fmt.Errorf("domain %s: %w", domain.Name, err))
+			}
+		}
+
+		// Check the patterns of IGNORE() and friends.
+		for _, uc := range domain.Unmanaged {
+			if err := uc.Compile(); err != nil {
+				errs = append(errs, fmt.Errorf("domain %s: IGNORE: %w", domain.Name, err))
+			}
 		}
 
 		// Normalize Nameservers.
@@ -326,13 +376,14 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 			// Unlike any other FQDN in this system, it is stored as a FQDN without the trailing dot.
 			n := dnsutil.AddOrigin(ns.Name, domain.Name+".")
 			ns.Name = strings.TrimSuffix(n, ".")
+			errs = append(errs, checkGlue(ns, domain.Name)...)
 		}
 
 		// Normalize Records.
 		models.PostProcessRecords(domain.Records)
 		for _, rec := range domain.Records {
 			if rec.TTL == 0 {
-				rec.TTL = models.DefaultTTL
+				rec.TTL = defaultTTL(domain, rec.Type)
 			}
 
 			// Canonicalize Label:
@@ -367,6 +418,9 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 			if errs2 := checkTargets(rec, domain.Name); errs2 != nil {
 				errs = append(errs, errs2...)
 			}
+			if err := checkBuilderTXT(rec, domain.Name); err != nil {
+				errs = append(errs, err)
+			}
 
 			// Canonicalize Targets.
 			if rec.Type == "CNAME" || rec.Type == "MX" || rec.Type == "NS" || rec.Type == "SRV" {
@@ -405,10 +459,18 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 					errs = append(errs, fmt.Errorf("TLSA MatchingType %d is invalid in record %s (domain %s)",
 						rec.TlsaMatchingType, rec.GetLabel(), domain.Name))
 				}
+			} else if models.IsRFC3597Type(rec.Type) {
+				if err := rec.SetTargetRFC3597String(rec.Type, rec.GetTargetField()); err != nil {
+					errs = append(errs, fmt.Errorf("%w in record %s (domain %s)", err, rec.GetLabel(), domain.Name))
+				}
 			}
 
 			// Populate FQDN:
 			rec.SetLabel(rec.GetLabel(), domain.Name)
+
+			if err := checkBuilderSRV(rec); err != nil {
+				errs = append(errs, err)
+			}
 		}
 	}
 
@@ -449,9 +511,18 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 		}
 	}
 
+	// Add the ownership records of MANAGED_BY().
+	for _, domain := range config.Domains {
+		ownership.AddTags(domain)
+	}
+
 	for _, d := range config.Domains {
+		// Report or flatten the chains of CNAMEs (CNAME_CHAINS)
+		errs = append(errs, checkCNAMEChains(d)...)
 		// Check that CNAMES don't have to co-exist with any other records
 		errs = append(errs, checkCNAMEs(d)...)
+		// Check the flags and the combinations of the CAA records
+		errs = append(errs, checkCAAs(d)...)
 		// Check that if any advanced record types are used in a domain, every provider for that domain supports them
 		err := checkProviderCapabilities(d)
 		if err != nil {
@@ -469,6 +540,10 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 		}
 		// Verify AutoDNSSEC is valid.
 		errs = append(errs, checkAutoDNSSEC(d)...)
+		// Warn about the TTLs that the providers will change.
+		errs = append(errs, checkProviderTTLs(d)...)
+		// Check the ALIAS records that the providers store as CNAMEs.
+		errs = append(errs, checkProviderAliases(d)...)
 	}
 
 	// At this point we've munged anything that needs to be munged, and
@@ -487,6 +562,11 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 			}
 			if es := providers.AuditRecords(provider.ProviderBase.ProviderType, domain.Records); len(es) != 0 {
 				for _, e := range es {
+					var w rejectif.AuditWarn
+					if errors.As(e, &w) {
+						errs = append(errs, Warning{fmt.Errorf("%s warns about domain %s: %w", provider.ProviderBase.ProviderType, domain.Name, w.Err)})
+						continue
+					}
 					errs = append(errs, fmt.Errorf("%s rejects domain %s: %w", provider.ProviderBase.ProviderType, domain.Name, e))
 				}
 			}
@@ -498,15 +578,11 @@ func ValidateAndNormalizeConfig(config *models.DNSConfig) (errs []error) {
 
 // UpdateNameSplitHorizon fills in the split horizon fields.
 func UpdateNameSplitHorizon(dc *models.DomainConfig) {
-	if dc.UniqueName == "" {
-		dc.UniqueName = dc.Name
-	}
 	if dc.Tag == "" {
-		l := strings.SplitN(dc.Name, "!", 2)
-		if len(l) == 2 {
-			dc.Name = l[0]
-			dc.Tag = l[1]
-		}
+		dc.Name, dc.Tag = models.SplitDomainName(dc.Name)
+	}
+	if dc.UniqueName == "" {
+		dc.UniqueName = models.UniqueDomainName(dc.Name, dc.Tag)
 	}
 }
 
@@ -526,17 +602,112 @@ func processSplitHorizonDomains(config *models.DNSConfig) error {
 		seen[d.UniqueName] = true
 	}
 
+	// Only one view of a zone can set its nameservers at the registrar.
+	// The registrars whose type comes from creds.json ("-"), and those
+	// that aren't declared at all (""), can't be checked yet.
+	regTypes := map[string]string{}
+	for _, r := range config.Registrars {
+		regTypes[r.Name] = r.Type
+	}
+	registered := map[string]string{} // The view that uses a registrar, by zone.
+	for _, d := range config.Domains {
+		if t := regTypes[d.RegistrarName]; t == "NONE" || t == "-" || t == "" {
+			continue
+		}
+		if other, ok := registered[d.Name]; ok {
+			return fmt.Errorf("%q and %q both use a registrar; only one view of a split horizon domain can (use a registrar of type NONE for the others, e.g. NewRegistrar(\"none\", \"NONE\"))", other, d.UniqueName)
+		}
+		registered[d.Name] = d.UniqueName
+	}
+
 	return nil
 }
 
-//// parseDomainSpec parses "domain.tld!tag" into its component parts.
-//func parseDomainSpec(s string) (domain, tag string) {
-//	l := strings.SplitN(s, "!", 2)
-//	if len(l) == 2 {
-//		return l[0], l[1]
-//	}
-//	return l[0], ""
-//}
+// defaultTTL returns the TTL of the records of type rType that have
+// none: the TTL_POLICY() rule of the type, or its "*" rule, or
+// models.DefaultTTL.
+func defaultTTL(dc *models.DomainConfig, rType string) uint32 {
+	if ttl, ok := dc.TTLPolicy[rType]; ok && ttl != 0 {
+		return ttl
+	}
+	if ttl, ok := dc.TTLPolicy["*"]; ok && ttl != 0 {
+		return ttl
+	}
+	return models.DefaultTTL
+}
+
+// checkProviderTTLs warns, for each provider that registered
+// ttlutil.Limits, about the records whose TTL it will change. The
+// pseudo records of the providers (CF_REDIRECT, R53_ALIAS...) are
+// skipped, as they aren't published with their TTL.
+func checkProviderTTLs(dc *models.DomainConfig) (errs []error) {
+	for _, provider := range dc.DNSProviderInstances {
+		limits, ok := providers.GetTTLLimits(provider.ProviderType)
+		if !ok {
+			continue
+		}
+		var changed []string
+		for _, rec := range dc.Records {
+			if providers.GetCustomRecordType(rec.Type) != nil {
+				// validateRecordTypes() replaced the custom types that
+				// have a real type; the others are pseudo records.
+				continue
+			}
+			if ttl := limits.Fix(rec.TTL); ttl != rec.TTL {
+				changed = append(changed, fmt.Sprintf("%s %s %d -> %d", rec.GetLabelFQDN(), rec.Type, rec.TTL, ttl))
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		example := changed[0]
+		if len(changed) > 1 {
+			example += fmt.Sprintf(" and %d more", len(changed)-1)
+		}
+		errs = append(errs, Warning{fmt.Errorf("domain %s: %s(%s) doesn't support the TTLs of %d records; it will change them (%s)", dc.Name, provider.Name, provider.ProviderType, len(changed), example)})
+	}
+	return errs
+}
+
+// checkProviderAliases checks, for each provider that registered an
+// aliastypes.Policy, the ALIAS records that it stores differently.
+func checkProviderAliases(dc *models.DomainConfig) (errs []error) {
+	for _, provider := range dc.DNSProviderInstances {
+		policy, ok := providers.GetAliasPolicy(provider.ProviderType)
+		if !ok {
+			continue
+		}
+		for _, rec := range dc.Records {
+			warning, err := policy.Check(rec, dc.Records)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("domain %s: %s(%s): %w", dc.Name, provider.Name, provider.ProviderType, err))
+			} else if warning != nil {
+				errs = append(errs, Warning{fmt.Errorf("domain %s: %s(%s): %w", dc.Name, provider.Name, provider.ProviderType, warning)})
+			}
+		}
+	}
+	return errs
+}
+
+// checkGlue checks and canonicalizes the glue addresses of ns. Only a
+// nameserver in the domain itself can have glue.
+func checkGlue(ns *models.Nameserver, domain string) (errs []error) {
+	if len(ns.Glue) == 0 {
+		return nil
+	}
+	if !dns.IsSubDomain(domain, ns.Name) {
+		return []error{fmt.Errorf("NAMESERVER %s has glue addresses, but it isn't in %s", ns.Name, domain)}
+	}
+	for i, addr := range ns.Glue {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			errs = append(errs, fmt.Errorf("NAMESERVER %s: glue address %q is not an IP address", ns.Name, addr))
+			continue
+		}
+		ns.Glue[i] = ip.String()
+	}
+	return errs
+}
 
 func checkAutoDNSSEC(dc *models.DomainConfig) (errs []error) {
 	if dc.AutoDNSSEC != "" && dc.AutoDNSSEC != "on" && dc.AutoDNSSEC != "off" {
@@ -545,10 +716,16 @@ func checkAutoDNSSEC(dc *models.DomainConfig) (errs []error) {
 	return
 }
 
+// checkCNAMEs checks that there are no CNAMEs at the apex, and that
+// CNAMEs don't co-exist with other records. The DNS providers would
+// reject them, but only in the middle of a push.
 func checkCNAMEs(dc *models.DomainConfig) (errs []error) {
 	cnames := map[string]bool{}
 	for _, r := range dc.Records {
 		if r.Type == "CNAME" {
+			if r.GetLabel() == "@" {
+				errs = append(errs, fmt.Errorf("cannot create CNAME record for bare domain %s%s", dc.Name, apexCNAMEHint(dc)))
+			}
 			if cnames[r.GetLabel()] {
 				errs = append(errs, fmt.Errorf("cannot have multiple CNAMEs with same name: %s", r.GetLabelFQDN()))
 			}
@@ -563,6 +740,90 @@ func checkCNAMEs(dc *models.DomainConfig) (errs []error) {
 	return
 }
 
+// checkCAAs checks the flags of the CAA records, and that the CAA
+// records of each label make sense together: an issue (or issuewild)
+// ";", which forbids all the CAs, can't be combined with a CA that is
+// allowed, and the iodef URLs must be mailto:, http: or https:.
+func checkCAAs(dc *models.DomainConfig) (errs []error) {
+	type label struct {
+		refuse, allow map[string]bool // by tag
+		iodef         bool
+	}
+	labels := map[string]*label{}
+	var order []string
+	for _, r := range dc.Records {
+		if r.Type != "CAA" {
+			continue
+		}
+		l := labels[r.GetLabel()]
+		if l == nil {
+			l = &label{refuse: map[string]bool{}, allow: map[string]bool{}}
+			labels[r.GetLabel()] = l
+			order = append(order, r.GetLabel())
+		}
+		if r.CaaFlag != 0 && r.CaaFlag != 128 {
+			errs = append(errs, fmt.Errorf("CAA flag %d is invalid in record %s (domain %s): only 0 and 128 (CAA_CRITICAL) are defined", r.CaaFlag, r.GetLabel(), dc.Name))
+		}
+		switch r.CaaTag {
+		case "iodef":
+			l.iodef = true
+			u, err := url.Parse(r.GetTargetField())
+			if err != nil || (u.Scheme != "mailto" && u.Scheme != "http" && u.Scheme != "https") {
+				errs = append(errs, fmt.Errorf("CAA iodef %q is invalid in record %s (domain %s): it must be a mailto:, http: or https: URL", r.GetTargetField(), r.GetLabel(), dc.Name))
+			}
+		case "issue", "issuewild":
+			if strings.TrimSpace(r.GetTargetField()) == ";" {
+				l.refuse[r.CaaTag] = true
+			} else {
+				l.allow[r.CaaTag] = true
+			}
+		}
+	}
+	for _, name := range order {
+		l := labels[name]
+		for _, tag := range []string{"issue", "issuewild"} {
+			if l.refuse[tag] && l.allow[tag] {
+				errs = append(errs, fmt.Errorf("CAA records of %s (domain %s) both forbid all the CAs (%s \";\") and allow some (%s)", name, dc.Name, tag, tag))
+			}
+		}
+		if l.iodef && len(l.refuse) == 0 && len(l.allow) == 0 {
+			errs = append(errs, Warning{fmt.Errorf("CAA records of %s (domain %s) only have iodef; they don't restrict any CA", name, dc.Name)})
+		}
+	}
+	return errs
+}
+
+// apexCNAMEHint suggests a record type that all the DNS providers of dc
+// can use at the apex instead of a CNAME, if there is one.
+func apexCNAMEHint(dc *models.DomainConfig) string {
+	alternatives := []struct {
+		fn  string
+		cap providers.Capability
+	}{
+		{"ALIAS", providers.CanUseAlias},
+		{"R53_ALIAS", providers.CanUseRoute53Alias},
+		{"AZURE_ALIAS", providers.CanUseAzureAlias},
+	}
+	for _, alt := range alternatives {
+		all := false
+		for _, provider := range dc.DNSProviderInstances {
+			if provider.ProviderType == "-" {
+				// The type isn't known (see checkProviderCapabilities).
+				all = false
+				break
+			}
+			all = providers.ProviderHasCapability(provider.ProviderType, alt.cap)
+			if !all {
+				break
+			}
+		}
+		if all {
+			return fmt.Sprintf(". Use %s() instead", alt.fn)
+		}
+	}
+	return ""
+}
+
 func checkDuplicates(records []*models.RecordConfig) (errs []error) {
 	seen := map[string]*models.RecordConfig{}
 	for _, r := range records {
@@ -617,13 +878,18 @@ var providerCapabilityChecks = []pairTypeCapability{
 	capabilityCheck("AUTODNSSEC", providers.CanAutoDNSSEC),
 	capabilityCheck("AZURE_ALIAS", providers.CanUseAzureAlias),
 	capabilityCheck("CAA", providers.CanUseCAA),
+	capabilityCheck("HTTPS", providers.CanUseHTTPS),
+	capabilityCheck("LOC", providers.CanUseLOC),
 	capabilityCheck("NAPTR", providers.CanUseNAPTR),
 	capabilityCheck("PTR", providers.CanUsePTR),
 	capabilityCheck("R53_ALIAS", providers.CanUseRoute53Alias),
+	capabilityCheck("RFC3597", providers.CanUseRFC3597), // Any TYPEnnn (see capabilityType)
 	capabilityCheck("SOA", providers.CanUseSOA),
 	capabilityCheck("SRV", providers.CanUseSRV),
 	capabilityCheck("SSHFP", providers.CanUseSSHFP),
+	capabilityCheck("SVCB", providers.CanUseSVCB),
 	capabilityCheck("TLSA", providers.CanUseTLSA),
+	capabilityCheck("URI", providers.CanUseURI),
 
 	// DS needs special record-level checks
 	{
@@ -651,6 +917,47 @@ func capabilityCheck(rType string, caps ...providers.Capability) pairTypeCapabil
 	}
 }
 
+// CapabilitiesOfType returns the capabilities that let a provider use
+// records of type rType (any one of them is enough), or nil if every
+// provider can.
+func CapabilitiesOfType(rType string) []providers.Capability {
+	rType = capabilityType(rType)
+	for _, ty := range providerCapabilityChecks {
+		if ty.rType == rType {
+			return ty.caps
+		}
+	}
+	return nil
+}
+
+// capabilityType returns the rType of providerCapabilityChecks that
+// covers records of type rType: types in the generic format of RFC 3597
+// are all covered by "RFC3597".
+func capabilityType(rType string) string {
+	if models.IsRFC3597Type(rType) {
+		return "RFC3597"
+	}
+	return rType
+}
+
+// TypesOfCapability returns the types of records that a provider may
+// use if it has cap.
+func TypesOfCapability(cap providers.Capability) []string {
+	var types []string
+	for _, ty := range providerCapabilityChecks {
+		if ty.rType == "AUTODNSSEC" {
+			// A setting of the zone, not a type of record.
+			continue
+		}
+		for _, c := range ty.caps {
+			if c == cap {
+				types = append(types, ty.rType)
+			}
+		}
+	}
+	return types
+}
+
 func providerHasAtLeastOneCapability(pType string, caps ...providers.Capability) bool {
 	for _, cap := range caps {
 		if providers.ProviderHasCapability(pType, cap) {
@@ -684,6 +991,48 @@ func checkProviderDS(pType string, records models.Records) error {
 	return nil
 }
 
+// checkBuilderTXT validates the TXT records generated by
+// DMARC_BUILDER and MTA_STS_BUILDER.
+func checkBuilderTXT(rec *models.RecordConfig, domain string) error {
+	if rec.Type != "TXT" {
+		return nil
+	}
+	var check func(string) error
+	switch rec.Metadata["builder"] {
+	case "DMARC":
+		check = mailauth.ValidateDMARC
+	case "MTA-STS":
+		check = mailauth.ValidateMTASTS
+	case "TLSRPT":
+		check = mailauth.ValidateTLSRPT
+	default:
+		return nil
+	}
+	if err := check(strings.Join(rec.TxtStrings, "")); err != nil {
+		return fmt.Errorf("TXT record %s in domain %s: %w", rec.GetLabel(), domain, err)
+	}
+	return nil
+}
+
+// srvBuilderRules are the rules of pkg/rejectif that the SRV records
+// of SRV_BUILDER must pass, whatever their DNS provider.
+var srvBuilderRules = []func(*models.RecordConfig) error{
+	rejectif.SrvNullTargetWithPort,
+}
+
+// checkBuilderSRV validates the SRV records generated by SRV_BUILDER.
+func checkBuilderSRV(rec *models.RecordConfig) error {
+	if rec.Type != "SRV" || rec.Metadata["builder"] != "SRV" {
+		return nil
+	}
+	for _, rule := range srvBuilderRules {
+		if err := rule(rec); err != nil {
+			return fmt.Errorf("SRV_BUILDER: %w", err)
+		}
+	}
+	return nil
+}
+
 func checkProviderCapabilities(dc *models.DomainConfig) error {
 	// Check if the zone uses a capability that the provider doesn't
 	// support.
@@ -696,7 +1045,7 @@ func checkProviderCapabilities(dc *models.DomainConfig) error {
 			}
 		default:
 			for _, r := range dc.Records {
-				if r.Type == ty.rType {
+				if capabilityType(r.Type) == ty.rType {
 					hasAny = true
 					break
 				}
@@ -729,6 +1078,27 @@ func checkProviderCapabilities(dc *models.DomainConfig) error {
 			}
 		}
 	}
+	return checkProviderTXT(dc)
+}
+
+// checkProviderTXT checks the TXT records against the TXT policy
+// (txtutil.Policy) of each provider that registered one. This is where
+// providers.CanUseTXTMulti is checked.
+func checkProviderTXT(dc *models.DomainConfig) error {
+	for _, provider := range dc.DNSProviderInstances {
+		policy, ok := providers.GetTXTPolicy(provider.ProviderType)
+		if !ok {
+			continue
+		}
+		for _, rec := range dc.Records {
+			if rec.Type != "TXT" {
+				continue
+			}
+			if err := policy.Check(rec); err != nil {
+				return fmt.Errorf("while checking TXT records in domain %s for DNS provider type %s: %w", dc.Name, provider.ProviderType, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -3,9 +3,12 @@ package normalize
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/aliastypes"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ttlutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 )
 
@@ -163,6 +166,27 @@ func Test_assert_valid_target(t *testing.T) {
 	}
 }
 
+func TestCheckTargetsSVCB(t *testing.T) {
+	tests := []struct {
+		params  string
+		isError bool
+	}{
+		{"", false},
+		{"alpn=h3,h2 port=443", false},
+		{"port=https", true},
+		{"no-such-key=1", true},
+		{"ipv4hint=::1", true},
+	}
+	for _, tst := range tests {
+		rc := &models.RecordConfig{Type: "HTTPS", SvcPriority: 1, SvcParams: tst.params}
+		rc.SetLabel("@", "example.com")
+		rc.SetTarget("www")
+		if errs := checkTargets(rc, "example.com"); (len(errs) != 0) != tst.isError {
+			t.Errorf("%q: got %v, want error %v", tst.params, errs, tst.isError)
+		}
+	}
+}
+
 func Test_transform_cname(t *testing.T) {
 	var tests = []struct {
 		experiment string
@@ -267,6 +291,31 @@ func TestCNAMEMutex(t *testing.T) {
 	}
 }
 
+func TestCNAMEAtApex(t *testing.T) {
+	rec := &models.RecordConfig{Type: "CNAME"}
+	rec.SetLabel("@", "example.com")
+	rec.SetTarget("example.net.")
+	tests := []struct {
+		pTypes []string
+		want   string
+	}{
+		{nil, "cannot create CNAME record for bare domain example.com"},
+		{[]string{ProviderNoDS}, "cannot create CNAME record for bare domain example.com"},
+		{[]string{ProviderAlias}, "cannot create CNAME record for bare domain example.com. Use ALIAS() instead"},
+		{[]string{ProviderAlias, ProviderNoDS}, "cannot create CNAME record for bare domain example.com"},
+	}
+	for _, tst := range tests {
+		dc := &models.DomainConfig{Name: "example.com", Records: models.Records{rec}}
+		for _, pType := range tst.pTypes {
+			dc.DNSProviderInstances = append(dc.DNSProviderInstances, &models.DNSProviderInstance{ProviderBase: models.ProviderBase{ProviderType: pType}})
+		}
+		errs := checkCNAMEs(dc)
+		if len(errs) != 1 || errs[0].Error() != tst.want {
+			t.Errorf("%v: got %v, want %q", tst.pTypes, errs, tst.want)
+		}
+	}
+}
+
 func TestCAAValidation(t *testing.T) {
 	config := &models.DNSConfig{
 		Domains: []*models.DomainConfig{
@@ -285,6 +334,71 @@ func TestCAAValidation(t *testing.T) {
 	}
 }
 
+func TestCheckCAAs(t *testing.T) {
+	caa := func(label string, flag uint8, tag, value string) *models.RecordConfig {
+		return makeRC(label, "example.com", value, models.RecordConfig{Type: "CAA", CaaFlag: flag, CaaTag: tag})
+	}
+	tests := []struct {
+		name    string
+		records []*models.RecordConfig
+		want    []string
+	}{
+		{
+			name: "valid",
+			records: []*models.RecordConfig{
+				caa("@", 0, "issue", "letsencrypt.org"),
+				caa("@", 128, "issue", "pki.goog"),
+				caa("@", 0, "issuewild", ";"),
+				caa("@", 0, "iodef", "mailto:security@example.com"),
+				caa("@", 128, "iodef", "https://example.com/caa"),
+				caa("wild", 0, "issuewild", "letsencrypt.org"),
+			},
+		},
+		{
+			name: "flag",
+			records: []*models.RecordConfig{
+				caa("@", 1, "issue", "letsencrypt.org"),
+			},
+			want: []string{"CAA flag 1 is invalid in record @ (domain example.com): only 0 and 128 (CAA_CRITICAL) are defined"},
+		},
+		{
+			name: "iodef",
+			records: []*models.RecordConfig{
+				caa("@", 0, "issue", "letsencrypt.org"),
+				caa("@", 0, "iodef", "security@example.com"),
+			},
+			want: []string{`CAA iodef "security@example.com" is invalid in record @ (domain example.com): it must be a mailto:, http: or https: URL`},
+		},
+		{
+			name: "none and a CA",
+			records: []*models.RecordConfig{
+				caa("@", 0, "issue", ";"),
+				caa("@", 0, "issue", "letsencrypt.org"),
+				caa("@", 0, "issuewild", ";"),
+			},
+			want: []string{`CAA records of @ (domain example.com) both forbid all the CAs (issue ";") and allow some (issue)`},
+		},
+		{
+			name: "iodef only",
+			records: []*models.RecordConfig{
+				caa("www", 0, "iodef", "mailto:security@example.com"),
+			},
+			want: []string{"CAA records of www (domain example.com) only have iodef; they don't restrict any CA"},
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			var got []string
+			for _, err := range checkCAAs(&models.DomainConfig{Name: "example.com", Records: tst.records}) {
+				got = append(got, err.Error())
+			}
+			if !reflect.DeepEqual(got, tst.want) {
+				t.Errorf("got %q, want %q", got, tst.want)
+			}
+		})
+	}
+}
+
 func TestCheckDuplicates(t *testing.T) {
 	records := []*models.RecordConfig{
 		// The only difference is the target:
@@ -391,6 +505,7 @@ const (
 	ProviderFullDS      = "FULL_DS_SUPPORT"
 	ProviderChildDSOnly = "CHILD_DS_SUPPORT"
 	ProviderBothDSCaps  = "BOTH_DS_CAPABILITIES"
+	ProviderAlias       = "ALIAS_SUPPORT"
 )
 
 func init() {
@@ -405,6 +520,9 @@ func init() {
 		providers.CanUseDS:            providers.Can(),
 		providers.CanUseDSForChildren: providers.Can(),
 	})
+	providers.RegisterDomainServiceProviderType(ProviderAlias, providers.DspFuncs{}, providers.DocumentationNotes{
+		providers.CanUseAlias: providers.Can(),
+	})
 }
 
 func Test_DSChecks(t *testing.T) {
@@ -493,3 +611,177 @@ func Test_errorRepeat(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckBuilderTXT(t *testing.T) {
+	tests := []struct {
+		builder, txt string
+		valid        bool
+	}{
+		{"DMARC", "v=DMARC1; p=reject; rua=mailto:dmarc@example.com", true},
+		{"DMARC", "v=DMARC1; p=reject; rua=dmarc@example.com", false},
+		{"MTA-STS", "v=STSv1; id=20230101", true},
+		{"MTA-STS", "v=STSv1", false},
+		{"TLSRPT", "v=TLSRPTv1; rua=mailto:tlsrpt@example.com", true},
+		{"", "v=DMARC1; anything goes", true},
+	}
+	for _, tst := range tests {
+		rc := &models.RecordConfig{Type: "TXT", Metadata: map[string]string{"builder": tst.builder}}
+		rc.SetLabel("@", "example.com")
+		rc.SetTargetTXT(tst.txt)
+		err := checkBuilderTXT(rc, "example.com")
+		if (err == nil) != tst.valid {
+			t.Errorf("%s %q: got %v, want valid %v", tst.builder, tst.txt, err, tst.valid)
+		}
+	}
+}
+
+func TestCheckBuilderSRV(t *testing.T) {
+	tests := []struct {
+		builder, target string
+		port            uint16
+		valid           bool
+	}{
+		{"SRV", "xmpp.example.com.", 5222, true},
+		{"SRV", ".", 0, true},
+		{"SRV", ".", 5222, false},
+		{"", ".", 5222, true}, // Only the records of SRV_BUILDER.
+	}
+	for _, tst := range tests {
+		rc := &models.RecordConfig{Type: "SRV", Metadata: map[string]string{"builder": tst.builder}}
+		rc.SetLabel("_xmpp-client._tcp", "example.com")
+		rc.SetTargetSRV(10, 10, tst.port, tst.target)
+		err := checkBuilderSRV(rc)
+		if (err == nil) != tst.valid {
+			t.Errorf("%s %q %d: got %v, want valid %v", tst.builder, tst.target, tst.port, err, tst.valid)
+		}
+	}
+}
+
+func TestProcessSplitHorizonDomains(t *testing.T) {
+	config := func(names ...string) *models.DNSConfig {
+		cfg := &models.DNSConfig{Registrars: []*models.RegistrarConfig{
+			{Name: "none", Type: "NONE"},
+			{Name: "reg", Type: "NAMEDOTCOM"},
+		}}
+		for _, name := range names {
+			reg := "none"
+			if strings.HasPrefix(name, "+") {
+				name, reg = name[1:], "reg"
+			}
+			cfg.Domains = append(cfg.Domains, &models.DomainConfig{Name: name, RegistrarName: reg})
+		}
+		return cfg
+	}
+
+	cfg := config("example.com", "+example.com!outside", "example.com!inside", "example.net")
+	if err := processSplitHorizonDomains(cfg); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range cfg.Domains {
+		got = append(got, d.Name+" "+d.Tag+" "+d.UniqueName)
+	}
+	want := []string{
+		"example.com  example.com",
+		"example.com outside example.com!outside",
+		"example.com inside example.com!inside",
+		"example.net  example.net",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Registrars that aren't declared can't be checked.
+	cfg = config("+example.com!a", "+example.com!b")
+	cfg.Domains[1].RegistrarName = "undeclared"
+	if err := processSplitHorizonDomains(cfg); err != nil {
+		t.Error(err)
+	}
+
+	for _, tst := range []struct {
+		names []string
+		err   string
+	}{
+		{[]string{"example.com", "example.com!"}, `duplicate domain name: "example.com"`},
+		{[]string{"example.com!a", "example.com!a"}, `duplicate domain name: "example.com!a"`},
+		{[]string{"+example.com!a", "+example.com!b"}, `"example.com!a" and "example.com!b" both use a registrar`},
+	} {
+		err := processSplitHorizonDomains(config(tst.names...))
+		if err == nil || !strings.Contains(err.Error(), tst.err) {
+			t.Errorf("%v: got error %v, want %s", tst.names, err, tst.err)
+		}
+	}
+}
+
+func TestCheckGlue(t *testing.T) {
+	ns := &models.Nameserver{Name: "ns1.example.com", Glue: []string{"2001:DB8::1", "192.0.2.1"}}
+	if errs := checkGlue(ns, "example.com"); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if strings.Join(ns.Glue, " ") != "2001:db8::1 192.0.2.1" {
+		t.Errorf("got glue %v", ns.Glue)
+	}
+	if errs := checkGlue(&models.Nameserver{Name: "ns1.example.net", Glue: []string{"192.0.2.1"}}, "example.com"); len(errs) != 1 {
+		t.Errorf("glue outside the domain: got %v", errs)
+	}
+	if errs := checkGlue(&models.Nameserver{Name: "ns1.example.com", Glue: []string{"ns1"}}, "example.com"); len(errs) != 1 {
+		t.Errorf("bad address: got %v", errs)
+	}
+}
+
+func TestCheckProviderAliases(t *testing.T) {
+	providers.RegisterDomainServiceProviderType("ALIAS_APEX_ONLY", providers.DspFuncs{}, aliastypes.Policy{Type: "ALIAS", ApexOnly: true})
+	dc := &models.DomainConfig{
+		Name:                 "example.com",
+		DNSProviderInstances: []*models.DNSProviderInstance{{ProviderBase: models.ProviderBase{Name: "p", ProviderType: "ALIAS_APEX_ONLY"}}},
+	}
+	for _, r := range []struct{ label, rtype string }{{"@", "ALIAS"}, {"www", "ALIAS"}, {"shop", "ALIAS"}, {"shop", "TXT"}} {
+		rc := &models.RecordConfig{Type: r.rtype}
+		rc.SetLabel(r.label, "example.com")
+		dc.Records = append(dc.Records, rc)
+	}
+	if !providers.ProviderHasCapability("ALIAS_APEX_ONLY", providers.CanUseAlias) {
+		t.Errorf("an ALIAS policy should set CanUseAlias")
+	}
+
+	errs := checkProviderAliases(dc)
+	if len(errs) != 2 {
+		t.Fatalf("got %v, want 2 errors", errs)
+	}
+	if want := "domain example.com: p(ALIAS_APEX_ONLY): ALIAS www.example.com is stored as a CNAME"; errs[0].Error() != want {
+		t.Errorf("got %q, want %q", errs[0], want)
+	}
+	if _, ok := errs[0].(Warning); !ok {
+		t.Errorf("got %T, want a Warning", errs[0])
+	}
+	if _, ok := errs[1].(Warning); ok || !strings.Contains(errs[1].Error(), "can't have the same name as the TXT record") {
+		t.Errorf("got %v, want an error about the TXT record", errs[1])
+	}
+}
+
+func TestCheckProviderTTLs(t *testing.T) {
+	providers.RegisterDomainServiceProviderType("TTL_LIMITS", providers.DspFuncs{}, ttlutil.Limits{Min: 120, Keep: []uint32{1}})
+	dc := &models.DomainConfig{
+		Name:                 "example.com",
+		DNSProviderInstances: []*models.DNSProviderInstance{{ProviderBase: models.ProviderBase{Name: "p", ProviderType: "TTL_LIMITS"}}},
+	}
+	for _, ttl := range []uint32{1, 60, 300, 30} {
+		rc := &models.RecordConfig{Type: "A", TTL: ttl}
+		rc.SetLabel(fmt.Sprint(ttl), "example.com")
+		dc.Records = append(dc.Records, rc)
+	}
+	// Pseudo records don't count.
+	providers.RegisterCustomRecordType("TTL_LIMITS_PSEUDO", "TTL_LIMITS", "")
+	pseudo := &models.RecordConfig{Type: "TTL_LIMITS_PSEUDO", TTL: 60}
+	pseudo.SetLabel("pseudo", "example.com")
+	dc.Records = append(dc.Records, pseudo)
+
+	errs := checkProviderTTLs(dc)
+	want := "domain example.com: p(TTL_LIMITS) doesn't support the TTLs of 2 records; it will change them (60.example.com A 60 -> 120 and 1 more)"
+	if len(errs) != 1 || errs[0].Error() != want {
+		t.Fatalf("got %v, want %s", errs, want)
+	}
+	if _, ok := errs[0].(Warning); !ok {
+		t.Errorf("got %T, want a Warning", errs[0])
+	}
+}
@@ -5,13 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 func init() {
 	initers = append(initers, func(cfg map[string]string) Notifier {
 		if url, ok := cfg["slack_url"]; ok {
 			notifier := &slackNotifier{
-				URL: url,
+				URL:    url,
+				Blocks: cfg["slack_blocks"] == "true",
 			}
 			return notifier
 		}
@@ -22,9 +24,20 @@ func init() {
 // slackNotifier sends notifications to slack or mattermost
 type slackNotifier struct {
 	URL string
+
+	// Blocks sends one Block Kit message per run (with an attachment
+	// per domain) instead of a plain text message per correction.
+	// Mattermost does not support Block Kit.
+	Blocks  bool
+	summary summary
 }
 
 func (s *slackNotifier) Notify(domain, provider, msg string, err error, preview bool) {
+	if s.Blocks {
+		s.summary.add(domain, provider, msg, err, preview)
+		return
+	}
+
 	var payload struct {
 		Username string `json:"username"`
 		Text     string `json:"text"`
@@ -43,4 +56,97 @@ func (s *slackNotifier) Notify(domain, provider, msg string, err error, preview
 	http.Post(s.URL, "text/json", bytes.NewReader(json))
 }
 
-func (s *slackNotifier) Done() {}
+func (s *slackNotifier) Done() {
+	if !s.Blocks {
+		return
+	}
+	defer s.summary.reset()
+
+	// Slack accepts a limited number of attachments per message.
+	const maxAttachments = 20
+	domains := s.summary.domains
+	for len(domains) > 0 {
+		n := len(domains)
+		if n > maxAttachments {
+			n = maxAttachments
+		}
+		json, _ := json.Marshal(slackBlocksPayload(domains[:n]))
+		http.Post(s.URL, "application/json", bytes.NewReader(json))
+		domains = domains[n:]
+	}
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackPayload struct {
+	Username    string            `json:"username"`
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+// slackBlocksPayload builds a message with one attachment per domain.
+func slackBlocksPayload(domains []*domainSummary) slackPayload {
+	// Slack rejects section text longer than 3000 characters.
+	const maxText = 3000
+
+	failed := 0
+	for _, ds := range domains {
+		if ds.Failed() {
+			failed++
+		}
+	}
+	verb := "push"
+	if len(domains) != 0 && domains[0].Preview {
+		verb = "preview"
+	}
+
+	p := slackPayload{
+		Username: "DNSControl",
+		Text:     fmt.Sprintf("DNSControl %s: %d domain(s) with corrections, %d failed", verb, len(domains), failed),
+	}
+	for _, ds := range domains {
+		color := "good"
+		status := "Success"
+		if ds.Preview {
+			color, status = "#439fe0", "Preview"
+		} else if ds.Failed() {
+			color, status = "danger", "FAILED"
+		}
+
+		header := fmt.Sprintf("*%s* [%s] — %s\nCreate: %d  Modify: %d  Delete: %d",
+			ds.Domain, strings.Join(ds.Providers, ", "), status,
+			ds.Creates, ds.Modifies, ds.Deletes)
+		if ds.Others != 0 {
+			header += fmt.Sprintf("  Other: %d", ds.Others)
+		}
+
+		a := slackAttachment{
+			Color: color,
+			Blocks: []slackBlock{
+				{Type: "section", Text: &slackText{Type: "mrkdwn", Text: header}},
+				{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "```" + truncate(strings.Join(ds.Msgs, "\n"), maxText-6) + "```"}},
+			},
+		}
+		if ds.Failed() {
+			a.Blocks = append(a.Blocks, slackBlock{
+				Type: "section",
+				Text: &slackText{Type: "mrkdwn", Text: truncate("*Errors:*\n"+strings.Join(ds.Errors, "\n"), maxText)},
+			})
+		}
+		p.Attachments = append(p.Attachments, a)
+	}
+	return p
+}
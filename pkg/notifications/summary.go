@@ -0,0 +1,109 @@
+package notifications
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// domainSummary collects the notifications for one domain so that
+// notifiers can send one message per domain instead of one per
+// correction.
+type domainSummary struct {
+	Domain    string
+	Providers []string
+	Preview   bool
+
+	Creates  int
+	Modifies int
+	Deletes  int
+	Others   int
+
+	Msgs   []string // The correction messages, in order.
+	Errors []string // The errors, formatted as "provider: message: error".
+}
+
+// Failed returns true if any correction for the domain failed.
+func (ds *domainSummary) Failed() bool {
+	return len(ds.Errors) != 0
+}
+
+// summary collects domainSummary's in the order the domains were
+// first seen.
+type summary struct {
+	domains []*domainSummary
+	index   map[string]*domainSummary
+}
+
+// add records a notification. The arguments are the same as Notify().
+func (s *summary) add(domain, provider, msg string, err error, preview bool) {
+	if s.index == nil {
+		s.index = map[string]*domainSummary{}
+	}
+	ds, ok := s.index[domain]
+	if !ok {
+		ds = &domainSummary{Domain: domain, Preview: preview}
+		s.index[domain] = ds
+		s.domains = append(s.domains, ds)
+	}
+
+	found := false
+	for _, p := range ds.Providers {
+		if p == provider {
+			found = true
+			break
+		}
+	}
+	if !found {
+		ds.Providers = append(ds.Providers, provider)
+	}
+
+	ds.Msgs = append(ds.Msgs, msg)
+	if err != nil {
+		ds.Errors = append(ds.Errors, provider+": "+msg+": "+err.Error())
+	}
+
+	for _, line := range strings.Split(msg, "\n") {
+		switch correctionVerb(line) {
+		case "CREATE":
+			ds.Creates++
+		case "MODIFY", "CHANGE":
+			ds.Modifies++
+		case "DELETE":
+			ds.Deletes++
+		case "":
+			// Not a correction line (for example, a continuation of the previous line).
+		default:
+			ds.Others++
+		}
+	}
+}
+
+// reset discards everything collected so far.
+func (s *summary) reset() {
+	s.domains, s.index = nil, nil
+}
+
+// correctionVerb returns the first word of a correction message,
+// skipping the "+", "-", "±" markers some providers add.  It returns ""
+// for blank or indented lines.
+func correctionVerb(line string) string {
+	if line == "" || line[0] == ' ' || line[0] == '\t' {
+		return ""
+	}
+	line = strings.TrimLeft(line, "+-±~ ")
+	verb, _, _ := strings.Cut(line, " ")
+	return strings.ToUpper(verb)
+}
+
+// truncate shortens s to at most n bytes, marking that it was shortened.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	const more = "\n…"
+	n -= len(more)
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + more
+}
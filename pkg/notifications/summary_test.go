@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	var s summary
+	s.add("example.com", "cloudflare", "CREATE A www.example.com 1.2.3.4", nil, false)
+	s.add("example.com", "cloudflare", "± MODIFY A foo.example.com: (1.1.1.1) -> (2.2.2.2)", nil, false)
+	s.add("example.org", "bind", "DELETE TXT example.org \"x\"\nDELETE TXT example.org \"y\"", errors.New("boom"), false)
+	s.add("example.com", "route53", "CHANGE example.com MX 10 mx.example.com.\n    (ttl 300->600)", nil, false)
+	s.add("example.com", "registrar", "Update nameservers", nil, false)
+
+	if len(s.domains) != 2 {
+		t.Fatalf("got %d domains, want 2", len(s.domains))
+	}
+
+	com := s.domains[0]
+	if com.Domain != "example.com" {
+		t.Errorf("first domain = %q, want example.com", com.Domain)
+	}
+	if got := strings.Join(com.Providers, ","); got != "cloudflare,route53,registrar" {
+		t.Errorf("providers = %q", got)
+	}
+	if com.Creates != 1 || com.Modifies != 2 || com.Deletes != 0 || com.Others != 1 {
+		t.Errorf("counts = %d/%d/%d/%d, want 1/2/0/1", com.Creates, com.Modifies, com.Deletes, com.Others)
+	}
+	if com.Failed() {
+		t.Errorf("example.com should not have failed")
+	}
+
+	org := s.domains[1]
+	if org.Deletes != 2 {
+		t.Errorf("deletes = %d, want 2", org.Deletes)
+	}
+	if !org.Failed() || org.Errors[0] != "bind: DELETE TXT example.org \"x\"\nDELETE TXT example.org \"y\": boom" {
+		t.Errorf("errors = %q", org.Errors)
+	}
+
+	s.reset()
+	if len(s.domains) != 0 {
+		t.Errorf("reset did not clear the summary")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("truncate(short) = %q", got)
+	}
+	got := truncate(strings.Repeat("é", 20), 15)
+	if len(got) > 15 || !strings.HasSuffix(got, "\n…") || !strings.HasPrefix(got, "éééé") {
+		t.Errorf("truncate = %q", got)
+	}
+}
@@ -22,31 +22,85 @@ func init() {
 	})
 }
 
-// teamsNotifier sends notifications to teams or mattermost
+// teamsNotifier sends notifications to a Microsoft Teams incoming
+// webhook. The corrections are collected and sent in Done() as one
+// card per domain.
 type teamsNotifier struct {
-	URL string
+	URL     string
+	summary summary
 }
 
 func (s *teamsNotifier) Notify(domain, provider, msg string, err error, preview bool) {
-	var payload struct {
-		Username string `json:"username"`
-		Text     string `json:"text"`
+	s.summary.add(domain, provider, msg, err, preview)
+}
+
+func (s *teamsNotifier) Done() {
+	defer s.summary.reset()
+	for _, ds := range s.summary.domains {
+		json, _ := json.Marshal(teamsCard(ds))
+		http.Post(s.URL, "application/json", bytes.NewReader(json))
+	}
+}
+
+type teamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type teamsSection struct {
+	ActivityTitle string      `json:"activityTitle,omitempty"`
+	Facts         []teamsFact `json:"facts,omitempty"`
+	Text          string      `json:"text,omitempty"`
+}
+
+type teamsMessageCard struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	ThemeColor string         `json:"themeColor"`
+	Summary    string         `json:"summary"`
+	Sections   []teamsSection `json:"sections"`
+}
+
+// teamsCard builds a MessageCard describing the corrections for one domain.
+func teamsCard(ds *domainSummary) teamsMessageCard {
+	// Teams rejects messages larger than about 28KB.
+	const maxText = 20000
+
+	title := fmt.Sprintf("DnsControl Successfully Changed %s", ds.Domain)
+	color := "2EB886"
+	if ds.Preview {
+		title = fmt.Sprintf("DnsControl Preview %s", ds.Domain)
+		color = "439FE0"
+	} else if ds.Failed() {
+		title = fmt.Sprintf("DnsControl Error Making Changes %s", ds.Domain)
+		color = "A30200"
+	}
+
+	facts := []teamsFact{
+		{Name: "Providers", Value: strings.Join(ds.Providers, ", ")},
+		{Name: "Create", Value: fmt.Sprint(ds.Creates)},
+		{Name: "Modify", Value: fmt.Sprint(ds.Modifies)},
+		{Name: "Delete", Value: fmt.Sprint(ds.Deletes)},
+	}
+	if ds.Others != 0 {
+		facts = append(facts, teamsFact{Name: "Other", Value: fmt.Sprint(ds.Others)})
 	}
-	payload.Username = "DnsControl"
 
 	// Format changes as 'preformated' text
-	msg = strings.ReplaceAll(msg, "\n", "\n    ")
-
-	if preview {
-		payload.Text = fmt.Sprintf("**DnsControl Preview %s**\n%s", domain, msg)
-	} else if err != nil {
-		payload.Text = fmt.Sprintf("**DnsControl Error Making Changes %s**\n%s\nError: %s", domain, msg, err)
-	} else {
-		payload.Text = fmt.Sprintf("**DnsControl Successfully Changed %s**\n%s", domain, msg)
+	text := "    " + strings.ReplaceAll(strings.Join(ds.Msgs, "\n"), "\n", "\n    ")
+	if ds.Failed() {
+		text += "\n\n**Errors:**\n\n" + strings.Join(ds.Errors, "\n\n")
 	}
 
-	json, _ := json.Marshal(payload)
-	http.Post(s.URL, "text/json", bytes.NewReader(json))
+	return teamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		ThemeColor: color,
+		Summary:    title,
+		Sections: []teamsSection{{
+			ActivityTitle: title,
+			Facts:         facts,
+			Text:          truncate(text, maxText),
+		}},
+	}
 }
-
-func (s *teamsNotifier) Done() {}
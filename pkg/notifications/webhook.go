@@ -0,0 +1,148 @@
+package notifications
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+func init() {
+	initers = append(initers, func(cfg map[string]string) Notifier {
+		url, ok := cfg["webhook_url"]
+		if !ok {
+			return nil
+		}
+
+		notifier := &webhookNotifier{
+			URL:    url,
+			Secret: cfg["webhook_secret"],
+		}
+		return notifier
+	})
+}
+
+// webhookSignatureHeader is the HTTP header that holds the HMAC-SHA256
+// signature of the request body, formatted as "sha256=<hex digest>".
+const webhookSignatureHeader = "X-DNSControl-Signature"
+
+// webhookClient sends the notifications. The changes have already been
+// made by then, so an endpoint that doesn't answer mustn't hang the run.
+var webhookClient = &http.Client{Timeout: 30 * time.Second}
+
+// webhookNotifier POSTs the results of the run as JSON to a URL.
+type webhookNotifier struct {
+	URL    string
+	Secret string // If set, the body is signed with this key.
+
+	corrections []webhookCorrection
+	summary     summary
+}
+
+type webhookCorrection struct {
+	Domain   string `json:"domain"`
+	Provider string `json:"provider"`
+	Message  string `json:"message"`
+	Error    string `json:"error,omitempty"`
+}
+
+type webhookDomain struct {
+	Domain      string              `json:"domain"`
+	Providers   []string            `json:"providers"`
+	Success     bool                `json:"success"`
+	Creates     int                 `json:"creates"`
+	Modifies    int                 `json:"modifies"`
+	Deletes     int                 `json:"deletes"`
+	Others      int                 `json:"others"`
+	Corrections []webhookCorrection `json:"corrections"`
+}
+
+type webhookPayload struct {
+	Preview bool            `json:"preview"`
+	Time    time.Time       `json:"time"`
+	Host    string          `json:"host,omitempty"`
+	Domains []webhookDomain `json:"domains"`
+}
+
+func (w *webhookNotifier) Notify(domain, provider, msg string, err error, preview bool) {
+	c := webhookCorrection{Domain: domain, Provider: provider, Message: msg}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	w.corrections = append(w.corrections, c)
+	w.summary.add(domain, provider, msg, err, preview)
+}
+
+func (w *webhookNotifier) Done() {
+	defer func() {
+		w.corrections = nil
+		w.summary.reset()
+	}()
+	if len(w.corrections) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(w.payload())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "webhook notification: %s\n", err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "webhook notification: %s\n", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+webhookSignature(w.Secret, body))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "webhook notification: %s\n", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		fmt.Fprintf(os.Stderr, "webhook notification: %s returned %s\n", w.URL, resp.Status)
+	}
+}
+
+// payload builds the JSON document that is sent to the webhook.
+func (w *webhookNotifier) payload() webhookPayload {
+	p := webhookPayload{Time: time.Now().UTC()}
+	p.Host, _ = os.Hostname()
+
+	for _, ds := range w.summary.domains {
+		p.Preview = ds.Preview
+		d := webhookDomain{
+			Domain:    ds.Domain,
+			Providers: ds.Providers,
+			Success:   !ds.Failed(),
+			Creates:   ds.Creates,
+			Modifies:  ds.Modifies,
+			Deletes:   ds.Deletes,
+			Others:    ds.Others,
+		}
+		for _, c := range w.corrections {
+			if c.Domain == ds.Domain {
+				d.Corrections = append(d.Corrections, c)
+			}
+		}
+		p.Domains = append(p.Domains, d)
+	}
+	return p
+}
+
+// webhookSignature returns the hex-encoded HMAC-SHA256 of body.
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
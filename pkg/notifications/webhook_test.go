@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	const secret = "s3cret"
+
+	var gotSig string
+	var got webhookPayload
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get(webhookSignatureHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		if err := json.Unmarshal(gotBody, &got); err != nil {
+			t.Errorf("bad JSON: %s", err)
+		}
+	}))
+	defer srv.Close()
+
+	n := &webhookNotifier{URL: srv.URL, Secret: secret}
+	n.Notify("example.com", "bind", "CREATE A www.example.com 1.2.3.4", nil, false)
+	n.Notify("example.org", "bind", "DELETE A www.example.org 1.2.3.4", errors.New("boom"), false)
+	n.Done()
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(gotBody)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); gotSig != want {
+		t.Errorf("signature = %q, want %q", gotSig, want)
+	}
+
+	if len(got.Domains) != 2 {
+		t.Fatalf("got %d domains, want 2", len(got.Domains))
+	}
+	if d := got.Domains[0]; d.Domain != "example.com" || !d.Success || d.Creates != 1 || len(d.Corrections) != 1 {
+		t.Errorf("domain 0 = %+v", d)
+	}
+	if d := got.Domains[1]; d.Success || d.Corrections[0].Error != "boom" {
+		t.Errorf("domain 1 = %+v", d)
+	}
+
+	// Nothing is sent when there were no corrections.
+	gotSig = "unchanged"
+	n.Done()
+	if gotSig != "unchanged" {
+		t.Errorf("Done() with no corrections sent a request")
+	}
+}
@@ -0,0 +1,166 @@
+// Package ownership implements MANAGED_BY(), which lets DNSControl
+// share a zone with other systems (external-dns, manual edits, another
+// DNSControl configuration) without NO_PURGE's all-or-nothing
+// semantics.
+//
+// For each record set (label and type) in dnsconfig.js, a TXT record is
+// added at "_dnscontrol-owner.LABEL" that says which owner manages it:
+//
+//	_dnscontrol-owner.www  TXT  "dnscontrol-owner=prod type=A"
+//
+// When the zone is compared with dnsconfig.js, existing record sets that
+// are neither in dnsconfig.js nor tagged with the owner are left alone.
+// Record sets that the owner tagged earlier are deleted as usual once
+// they are removed from dnsconfig.js.
+package ownership
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Prefix is the label that the ownership records are placed under.
+const Prefix = "_dnscontrol-owner"
+
+// wildcardLabel replaces "*" in the labels of ownership records, since
+// a "*" may only be the leftmost label.
+const wildcardLabel = "_wildcard"
+
+var (
+	validOwner = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+	tagText    = regexp.MustCompile(`^dnscontrol-owner=(\S+) type=(\S+)$`)
+)
+
+// CheckOwner returns an error if owner can't be used with MANAGED_BY().
+func CheckOwner(owner string) error {
+	if !validOwner.MatchString(owner) {
+		return fmt.Errorf("MANAGED_BY(%q): the owner must only contain letters, digits, '.', '_' and '-'", owner)
+	}
+	return nil
+}
+
+// TagLabel returns the label of the ownership record for label.
+func TagLabel(label string) string {
+	if label == "@" {
+		return Prefix
+	}
+	parts := strings.Split(label, ".")
+	for i, p := range parts {
+		if p == "*" {
+			parts[i] = wildcardLabel
+		}
+	}
+	return Prefix + "." + strings.Join(parts, ".")
+}
+
+// AddTags adds the ownership records of dc, if it uses MANAGED_BY(). It
+// must be called after the records are normalized.
+func AddTags(dc *models.DomainConfig) {
+	if dc.ManagedBy == "" {
+		return
+	}
+	seen := map[models.RecordKey]bool{}
+	var tags models.Records
+	for _, rc := range dc.Records {
+		k := models.RecordKey{NameFQDN: rc.NameFQDN, Type: rc.Type}
+		if seen[k] || isTag(rc) {
+			continue
+		}
+		seen[k] = true
+		t := &models.RecordConfig{Type: "TXT", TTL: models.DefaultTTL, Metadata: map[string]string{}}
+		t.SetLabel(TagLabel(rc.GetLabel()), dc.Name)
+		t.SetTargetTXT(fmt.Sprintf("dnscontrol-owner=%s type=%s", dc.ManagedBy, rc.Type))
+		tags = append(tags, t)
+	}
+	dc.Records = append(dc.Records, tags...)
+}
+
+// Foreign returns the existing records that dc doesn't own, which
+// must be left alone: those of record sets that are neither in
+// dc.Records nor tagged by the owner, and the ownership records of
+// other owners. It returns nil if dc doesn't use MANAGED_BY().
+//
+// An error is returned if dc.Records has a record set that is tagged by
+// another owner.
+func Foreign(existing models.Records, dc *models.DomainConfig) (models.Records, error) {
+	if dc.ManagedBy == "" {
+		return nil, nil
+	}
+
+	owned := map[models.RecordKey]bool{}
+	others := map[models.RecordKey]string{}
+	for _, rc := range existing {
+		owner, k, ok := parseTag(rc, dc.Name)
+		if !ok {
+			continue
+		}
+		if owner == dc.ManagedBy {
+			owned[k] = true
+		} else {
+			others[k] = owner
+		}
+	}
+
+	desired := map[models.RecordKey]bool{}
+	for _, rc := range dc.Records {
+		k := models.RecordKey{NameFQDN: rc.NameFQDN, Type: rc.Type}
+		desired[k] = true
+		if owner, ok := others[k]; ok && !isTag(rc) {
+			return nil, fmt.Errorf("%s %s is managed by %q, not %q (MANAGED_BY)", rc.NameFQDN, rc.Type, owner, dc.ManagedBy)
+		}
+	}
+
+	var foreign models.Records
+	for _, rc := range existing {
+		if owner, _, ok := parseTag(rc, dc.Name); ok {
+			if owner != dc.ManagedBy {
+				foreign = append(foreign, rc)
+			}
+			continue
+		}
+		if (rc.Type == "NS" || rc.Type == "SOA") && rc.GetLabel() == "@" {
+			// The apex NS records are always managed by DNSControl.
+			continue
+		}
+		k := models.RecordKey{NameFQDN: rc.NameFQDN, Type: rc.Type}
+		if !desired[k] && !owned[k] {
+			foreign = append(foreign, rc)
+		}
+	}
+	return foreign, nil
+}
+
+// isTag reports whether rc is an ownership record.
+func isTag(rc *models.RecordConfig) bool {
+	if rc.Type != "TXT" {
+		return false
+	}
+	l := rc.GetLabel()
+	return l == Prefix || strings.HasPrefix(l, Prefix+".")
+}
+
+// parseTag returns the owner and the record set of an ownership record.
+func parseTag(rc *models.RecordConfig, domain string) (owner string, k models.RecordKey, ok bool) {
+	if !isTag(rc) {
+		return "", k, false
+	}
+	m := tagText.FindStringSubmatch(rc.GetTargetTXTJoined())
+	if m == nil {
+		return "", k, false
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(rc.GetLabel(), Prefix), ".")
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		if p == wildcardLabel {
+			parts[i] = "*"
+		}
+	}
+	fqdn := domain
+	if name != "" {
+		fqdn = strings.Join(parts, ".") + "." + domain
+	}
+	return m[1], models.RecordKey{NameFQDN: fqdn, Type: m[2]}, true
+}
@@ -0,0 +1,110 @@
+package ownership
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func rec(label, rtype, target string) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: rtype, TTL: 300}
+	rc.SetLabel(label, "example.com")
+	if rtype == "TXT" {
+		rc.SetTargetTXT(target)
+	} else {
+		rc.SetTarget(target)
+	}
+	return rc
+}
+
+func names(recs models.Records) string {
+	var s []string
+	for _, rc := range recs {
+		s = append(s, rc.GetLabel()+" "+rc.Type+" "+rc.GetTargetCombined())
+	}
+	sort.Strings(s)
+	return strings.Join(s, "\n")
+}
+
+func TestTagLabel(t *testing.T) {
+	tests := map[string]string{
+		"@":     "_dnscontrol-owner",
+		"www":   "_dnscontrol-owner.www",
+		"*":     "_dnscontrol-owner._wildcard",
+		"*.foo": "_dnscontrol-owner._wildcard.foo",
+	}
+	for label, want := range tests {
+		if got := TagLabel(label); got != want {
+			t.Errorf("TagLabel(%q) = %q, want %q", label, got, want)
+		}
+	}
+}
+
+func TestAddTags(t *testing.T) {
+	dc := &models.DomainConfig{Name: "example.com", ManagedBy: "prod", Records: models.Records{
+		rec("@", "MX", "mx.example.com."),
+		rec("www", "A", "1.2.3.4"),
+		rec("www", "A", "1.2.3.5"),
+		rec("*", "CNAME", "www.example.com."),
+	}}
+	AddTags(dc)
+	want := `* CNAME www.example.com.
+@ MX 0 mx.example.com.
+_dnscontrol-owner TXT "dnscontrol-owner=prod type=MX"
+_dnscontrol-owner._wildcard TXT "dnscontrol-owner=prod type=CNAME"
+_dnscontrol-owner.www TXT "dnscontrol-owner=prod type=A"
+www A 1.2.3.4
+www A 1.2.3.5`
+	if got := names(dc.Records); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Adding the tags again doesn't tag the tags.
+	n := len(dc.Records)
+	dc.Records = dc.Records[:4]
+	AddTags(dc)
+	if len(dc.Records) != n {
+		t.Errorf("got %d records, want %d", len(dc.Records), n)
+	}
+}
+
+func TestForeign(t *testing.T) {
+	existing := models.Records{
+		rec("@", "NS", "ns1.example.net."),
+		rec("www", "A", "1.1.1.1"),    // desired: managed
+		rec("old", "A", "2.2.2.2"),    // tagged by us: managed (deleted)
+		rec("manual", "A", "3.3.3.3"), // untagged: foreign
+		rec("k8s", "A", "4.4.4.4"),    // tagged by another owner: foreign
+		rec("_dnscontrol-owner.www", "TXT", "dnscontrol-owner=prod type=A"),
+		rec("_dnscontrol-owner.old", "TXT", "dnscontrol-owner=prod type=A"),
+		rec("_dnscontrol-owner.k8s", "TXT", "dnscontrol-owner=staging type=A"),
+	}
+	dc := &models.DomainConfig{Name: "example.com", ManagedBy: "prod", Records: models.Records{
+		rec("www", "A", "1.2.3.4"),
+	}}
+	AddTags(dc)
+
+	foreign, err := Foreign(existing, dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `_dnscontrol-owner.k8s TXT "dnscontrol-owner=staging type=A"
+k8s A 4.4.4.4
+manual A 3.3.3.3`
+	if got := names(foreign); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Without MANAGED_BY nothing is foreign.
+	if foreign, _ := Foreign(existing, &models.DomainConfig{Name: "example.com"}); foreign != nil {
+		t.Errorf("expected nil, got %v", names(foreign))
+	}
+
+	// Record sets of other owners can't be claimed.
+	dc.Records = append(dc.Records, rec("k8s", "A", "5.5.5.5"))
+	if _, err := Foreign(existing, dc); err == nil {
+		t.Errorf("expected an error for a record set of another owner")
+	}
+}
@@ -94,6 +94,15 @@ func PrettySort(records models.Records, origin string, defaultTTL uint32, commen
 	return z
 }
 
+// CommentMetadataKey is the metadata key of a comment that is written
+// at the end of the record's line.
+const CommentMetadataKey = "zonefile_comment"
+
+// Write writes the zone file.
+func (z *ZoneGenData) Write(w io.Writer) error {
+	return z.generateZoneFileHelper(w)
+}
+
 // generateZoneFileHelper creates a pretty zonefile.
 func (z *ZoneGenData) generateZoneFileHelper(w io.Writer) error {
 
@@ -104,6 +113,9 @@ func (z *ZoneGenData) generateZoneFileHelper(w io.Writer) error {
 		z.DefaultTTL = 300
 	}
 	fmt.Fprintln(w, "$TTL", z.DefaultTTL)
+	if z.Relative {
+		fmt.Fprintln(w, "$ORIGIN", z.Origin)
+	}
 	for _, comment := range z.Comments {
 		for _, line := range strings.Split(comment, "\n") {
 			if line != "" {
@@ -116,7 +128,7 @@ func (z *ZoneGenData) generateZoneFileHelper(w io.Writer) error {
 		// Fake types are commented out.
 		prefix := ""
 		_, ok := dns.StringToType[rr.Type]
-		if !ok {
+		if !ok && !models.IsRFC3597Type(rr.Type) {
 			prefix = ";"
 		}
 
@@ -139,6 +151,9 @@ func (z *ZoneGenData) generateZoneFileHelper(w io.Writer) error {
 
 		// the remaining line
 		target := rr.GetTargetCombined()
+		if z.Relative {
+			target = z.relativeTarget(rr)
+		}
 
 		// comment
 		comment := ""
@@ -147,6 +162,9 @@ func (z *ZoneGenData) generateZoneFileHelper(w io.Writer) error {
 				comment = " ; CF_PROXY_ON"
 			}
 		}
+		if c := rr.Metadata[CommentMetadataKey]; c != "" {
+			comment += " ; " + strings.TrimSpace(strings.TrimPrefix(strings.ReplaceAll(c, "\n", " "), ";"))
+		}
 
 		fmt.Fprintf(w, "%s%s%s\n",
 			prefix, FormatLine([]int{10, 5, 2, 5, 0}, []string{name, ttl, "IN", typeStr, target}), comment)
@@ -154,6 +172,34 @@ func (z *ZoneGenData) generateZoneFileHelper(w io.Writer) error {
 	return nil
 }
 
+// relativeTarget returns the target of rr with the hostname written
+// relative to the origin, if it is within the origin.
+func (z *ZoneGenData) relativeTarget(rr *models.RecordConfig) string {
+	name := relativeName(rr.GetTargetField(), z.Origin)
+	switch rr.Type { // #rtype_variations
+	case "ALIAS", "CNAME", "DNAME", "NS", "PTR":
+		return name
+	case "MX":
+		return fmt.Sprintf("%d %s", rr.MxPreference, name)
+	case "SRV":
+		return fmt.Sprintf("%d %d %d %s", rr.SrvPriority, rr.SrvWeight, rr.SrvPort, name)
+	}
+	return rr.GetTargetCombined()
+}
+
+// relativeName returns name relative to origin (which must end with a
+// dot), or name unchanged if it is not within the origin.
+func relativeName(name, origin string) string {
+	lname, lorigin := strings.ToLower(name), strings.ToLower(origin)
+	if lname == lorigin {
+		return "@"
+	}
+	if strings.HasSuffix(lname, "."+lorigin) {
+		return name[:len(name)-len(origin)-1]
+	}
+	return name
+}
+
 // FormatLine formats a zonefile line.
 func FormatLine(lengths []int, fields []string) string {
 	c := 0
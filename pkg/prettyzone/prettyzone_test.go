@@ -117,6 +117,77 @@ www              IN CNAME bosun.org.
 	parseAndRegen(t, buf, expected)
 }
 
+func TestWriteZoneFileRelative(t *testing.T) {
+	var rrs []dns.RR
+	for _, s := range []string{
+		"bosun.org. 300 IN MX 10 mx.bosun.org.",
+		"bosun.org. 300 IN MX 20 mx.example.com.",
+		"www.bosun.org. 300 IN CNAME bosun.org.",
+		"ftp.bosun.org. 300 IN CNAME www.bosun.org.",
+		"_sip._tcp.bosun.org. 300 IN SRV 10 20 5060 sip.bosun.org.",
+		"sub.bosun.org. 300 IN NS ns1.notbosun.org.",
+	} {
+		rr, _ := dns.NewRR(s)
+		rrs = append(rrs, rr)
+	}
+	rcs, err := models.RRstoRCs(rrs, "bosun.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcs[2].Metadata = map[string]string{CommentMetadataKey: "; the web site"}
+
+	z := PrettySort(rcs, "bosun.org", 0, nil)
+	z.Relative = true
+	buf := &bytes.Buffer{}
+	if err := z.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `$TTL 300
+$ORIGIN bosun.org.
+@                IN MX    10 mx
+                 IN MX    20 mx.example.com.
+_sip._tcp        IN SRV   10 20 5060 sip
+ftp              IN CNAME www
+sub              IN NS    ns1.notbosun.org.
+www              IN CNAME @ ; the web site
+`
+	if buf.String() != expected {
+		t.Fatalf("Zone file does not match: got=(\n%v\n)\nexpected=(\n%v\n)\n", buf.String(), expected)
+	}
+
+	// Reading the relative names back should give the same records.
+	zp := dns.NewZoneParser(buf, "bosun.org", "bosun.org.zone")
+	var parsed []dns.RR
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		parsed = append(parsed, rr)
+	}
+	if err := zp.Err(); err != nil {
+		t.Fatal(err)
+	}
+	buf2 := &bytes.Buffer{}
+	WriteZoneFileRR(buf2, parsed, "bosun.org")
+	buf3 := &bytes.Buffer{}
+	WriteZoneFileRR(buf3, rrs, "bosun.org")
+	if buf2.String() != buf3.String() {
+		t.Fatalf("Parsed zonefile does not match: got=(\n%v\n)\nexpected=(\n%v\n)\n", buf2.String(), buf3.String())
+	}
+}
+
+func TestRelativeName(t *testing.T) {
+	for _, tst := range []struct{ name, want string }{
+		{"bosun.org.", "@"},
+		{"BOSUN.ORG.", "@"},
+		{"www.bosun.org.", "www"},
+		{"a.b.Bosun.Org.", "a.b"},
+		{"notbosun.org.", "notbosun.org."},
+		{"example.com.", "example.com."},
+	} {
+		if got := relativeName(tst.name, "bosun.org."); got != tst.want {
+			t.Errorf("relativeName(%q) = %q, want %q", tst.name, got, tst.want)
+		}
+	}
+}
+
 func TestWriteZoneFileSimpleTtl(t *testing.T) {
 	r1, _ := dns.NewRR("bosun.org. 100 IN A 192.30.252.153")
 	r2, _ := dns.NewRR("bosun.org. 100 IN A 192.30.252.154")
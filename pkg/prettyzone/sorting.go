@@ -18,6 +18,10 @@ type ZoneGenData struct {
 	DefaultTTL uint32
 	Records    models.Records
 	Comments   []string
+
+	// If Relative is set, the zone file starts with $ORIGIN and
+	// hostnames within the origin are written relative to it.
+	Relative bool
 }
 
 func (z *ZoneGenData) Len() int      { return len(z.Records) }
@@ -129,50 +133,57 @@ func LabelLess(a, b string) bool {
 		return false
 	}
 
-	// Split into elements and match up last elements to first. Compare the
-	// first non-equal elements.
-
-	as := strings.Split(a, ".")
-	bs := strings.Split(b, ".")
-	ia := len(as) - 1
-	ib := len(bs) - 1
-
-	var min int
-	if ia < ib {
-		min = len(as) - 1
-	} else {
-		min = len(bs) - 1
-	}
-
-	// Skip the matching highest elements, then compare the next item.
-	for i, j := ia, ib; min >= 0; i, j, min = i-1, j-1, min-1 {
-		// Compare as[i] < bs[j]
-		// Sort @ at the top, then *, then everything else.
-		// i.e. @ always is less. * is is less than everything but @.
-		// If both are numeric, compare as integers, otherwise as strings.
+	// Match up the elements from last to first. Compare the first
+	// non-equal elements. The labels aren't split, as this is called a
+	// lot when sorting large zones.
+	for {
+		ia := strings.LastIndexByte(a, '.')
+		ib := strings.LastIndexByte(b, '.')
+		ea, eb := a[ia+1:], b[ib+1:]
 
-		if as[i] != bs[j] {
+		if ea != eb {
+			// Sort @ at the top, then *, then everything else.
+			// i.e. @ always is less. * is is less than everything but @.
+			// If both are numeric, compare as integers, otherwise as strings.
 
 			// If the first element is *, it is always less.
-			if i == 0 && as[i] == "*" {
+			if ia < 0 && ea == "*" {
 				return true
 			}
-			if j == 0 && bs[j] == "*" {
+			if ib < 0 && eb == "*" {
 				return false
 			}
 
 			// If the elements are both numeric, compare as integers:
-			au, aerr := strconv.ParseUint(as[i], 10, 64)
-			bu, berr := strconv.ParseUint(bs[j], 10, 64)
-			if aerr == nil && berr == nil {
-				return au < bu
+			if au, ok := parseNumeric(ea); ok {
+				if bu, ok := parseNumeric(eb); ok {
+					return au < bu
+				}
 			}
 			// otherwise, compare as strings:
-			return as[i] < bs[j]
+			return ea < eb
+		}
+
+		if ia < 0 || ib < 0 {
+			// The top elements were equal, so the shorter name is less.
+			return ia < 0 && ib >= 0
+		}
+		a, b = a[:ia], b[:ib]
+	}
+}
+
+// parseNumeric returns the value of s if it is a decimal number.
+func parseNumeric(s string) (uint64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false // Not a number; avoids the error of ParseUint.
 		}
 	}
-	// The min top elements were equal, so the shorter name is less.
-	return ia < ib
+	u, err := strconv.ParseUint(s, 10, 64)
+	return u, err == nil
 }
 
 func zoneRrtypeLess(a, b string) bool {
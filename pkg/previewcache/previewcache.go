@@ -0,0 +1,80 @@
+// Package previewcache saves the corrections computed by "preview" so
+// that the next preview can skip downloading the zones that haven't
+// changed.
+//
+// The cache is a directory with one file per DNS provider and zone:
+//
+//	CACHEDIR/r53_main/example.com.json
+//
+// Each file holds the key it was computed for and the messages of the
+// corrections. The key must change whenever the zone at the provider or
+// its configuration in dnsconfig.js changes (see Key).
+package previewcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Cache is a preview cache in a directory.
+type Cache struct {
+	Dir string
+}
+
+// New returns the cache in dir.
+func New(dir string) *Cache {
+	return &Cache{Dir: dir}
+}
+
+// Key returns a key made of parts, which must be marshalable to JSON.
+func Key(parts ...interface{}) (string, error) {
+	b, err := json.Marshal(parts)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type entry struct {
+	Key      string   `json:"key"`
+	Messages []string `json:"messages"`
+}
+
+func (c *Cache) file(provider, zone string) string {
+	return filepath.Join(c.Dir, provider, zone+".json")
+}
+
+// Get returns the messages saved for zone at provider, if they were
+// saved with the same key.
+func (c *Cache) Get(provider, zone, key string) ([]string, bool) {
+	b, err := os.ReadFile(c.file(provider, zone))
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(b, &e); err != nil || e.Key != key {
+		return nil, false
+	}
+	return e.Messages, true
+}
+
+// Put saves the messages of the corrections of zone at provider.
+func (c *Cache) Put(provider, zone, key string, messages []string) error {
+	b, err := json.Marshal(entry{Key: key, Messages: messages})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(c.Dir, provider), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(c.file(provider, zone), b, 0o640)
+}
+
+// Clear removes all the entries of the cache.
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.Dir)
+}
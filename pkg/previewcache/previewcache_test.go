@@ -0,0 +1,43 @@
+package previewcache
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "cache"))
+	k1, err := Key("desec", "example.com", "2023-01-02T15:04:05Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, _ := Key("desec", "example.com", "2023-01-02T15:04:06Z")
+	if k1 == k2 {
+		t.Fatalf("Key() returned %q for different parts", k1)
+	}
+
+	if _, ok := c.Get("desec", "example.com", k1); ok {
+		t.Error("Get() found an entry in an empty cache")
+	}
+	msgs := []string{"+ CREATE www.example.com A 1.2.3.4 ttl=300"}
+	if err := c.Put("desec", "example.com", k1, msgs); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := c.Get("desec", "example.com", k1); !ok || !reflect.DeepEqual(got, msgs) {
+		t.Errorf("Get() = %v, %v; want %v, true", got, ok, msgs)
+	}
+	if _, ok := c.Get("desec", "example.com", k2); ok {
+		t.Error("Get() found an entry saved with another key")
+	}
+	if _, ok := c.Get("other", "example.com", k1); ok {
+		t.Error("Get() found an entry saved for another provider")
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("desec", "example.com", k1); ok {
+		t.Error("Get() found an entry after Clear()")
+	}
+}
@@ -0,0 +1,176 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a message.
+type Level int
+
+const (
+	// LevelDebug messages are only printed with -v or --vmodule.
+	LevelDebug Level = iota
+	// LevelInfo is the level of the normal output.
+	LevelInfo
+	// LevelWarn is the level of Warnf.
+	LevelWarn
+	// LevelError is the level of Errorf.
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the Level named s ("debug", "info", "warn" or "error").
+func ParseLevel(s string) (Level, error) {
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q (valid: %s)", s, strings.Join(levelNames, ", "))
+}
+
+var (
+	modulesMu    sync.RWMutex
+	moduleLevels = map[string]Level{}
+)
+
+// SetModuleLevels sets the level of the messages printed for each
+// module. Each spec is "module=level" (e.g. "providers.cloudflare=debug")
+// or a comma-separated list of them. A module's level also applies to
+// its submodules: "providers=debug" applies to "providers.cloudflare".
+func SetModuleLevels(specs []string) error {
+	levels := map[string]Level{}
+	for _, spec := range specs {
+		for _, item := range strings.Split(spec, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			module, name, ok := strings.Cut(item, "=")
+			if !ok || module == "" {
+				return fmt.Errorf("invalid module level %q: want module=level", item)
+			}
+			level, err := ParseLevel(name)
+			if err != nil {
+				return err
+			}
+			levels[module] = level
+		}
+	}
+	modulesMu.Lock()
+	moduleLevels = levels
+	modulesMu.Unlock()
+	return nil
+}
+
+// minLevel returns the lowest level printed for module: the level of
+// the closest module set by SetModuleLevels or, if none, LevelDebug
+// when verbose and LevelInfo otherwise.
+func minLevel(module string, verbose bool) Level {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+	for m := module; m != ""; {
+		if level, ok := moduleLevels[m]; ok {
+			return level
+		}
+		i := strings.LastIndex(m, ".")
+		if i < 0 {
+			break
+		}
+		m = m[:i]
+	}
+	if verbose {
+		return LevelDebug
+	}
+	return LevelInfo
+}
+
+// logEntry is a message written by the JSON sink. The messages of the
+// CLI methods also tell which event they report, and its details.
+type logEntry struct {
+	Time        string `json:"time"`
+	Level       string `json:"level"`
+	Module      string `json:"module,omitempty"`
+	Event       string `json:"event,omitempty"`
+	Domain      string `json:"domain,omitempty"`
+	Provider    string `json:"provider,omitempty"`
+	Registrar   string `json:"registrar,omitempty"`
+	Skip        bool   `json:"skip,omitempty"`
+	Correction  int    `json:"correction,omitempty"`
+	Corrections *int   `json:"corrections,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Message     string `json:"msg"`
+}
+
+// writeJSON writes e as a JSON line, with the time, level and message.
+func (c ConsolePrinter) writeJSON(level Level, e logEntry, msg string) {
+	e.Time = time.Now().UTC().Format(time.RFC3339)
+	e.Level = level.String()
+	e.Message = strings.TrimRight(msg, "\n")
+	json.NewEncoder(c.Writer).Encode(e)
+}
+
+// log prints a message of module at level, unless the level is below
+// the one configured for the module.
+func (c ConsolePrinter) log(module string, level Level, format string, args ...interface{}) {
+	if level < minLevel(module, c.Verbose) {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	if c.JSON {
+		c.writeJSON(level, logEntry{Module: module}, msg)
+		return
+	}
+	switch level {
+	case LevelWarn:
+		msg = "WARNING: " + msg
+	case LevelError:
+		msg = "ERROR: " + msg
+	}
+	fmt.Fprint(c.Writer, msg)
+}
+
+// Module returns a Printer for the messages of a module. The name is
+// the path of the package, with dots: "providers.cloudflare",
+// "pkg.diff2". A package keeps it in a variable named logger:
+//
+//	var logger = printer.Module("providers.cloudflare")
+//
+// --vmodule (see SetModuleLevels) sets the level of each module, e.g.
+// --vmodule providers.cloudflare=debug,pkg.diff2=warn.
+func Module(name string) Printer {
+	return modulePrinter(name)
+}
+
+type modulePrinter string
+
+func (m modulePrinter) Debugf(format string, args ...interface{}) {
+	DefaultPrinter.log(string(m), LevelDebug, format, args...)
+}
+
+func (m modulePrinter) Printf(format string, args ...interface{}) {
+	DefaultPrinter.log(string(m), LevelInfo, format, args...)
+}
+
+func (m modulePrinter) Println(lines ...string) {
+	DefaultPrinter.log(string(m), LevelInfo, "%v\n", lines)
+}
+
+func (m modulePrinter) Warnf(format string, args ...interface{}) {
+	DefaultPrinter.log(string(m), LevelWarn, format, args...)
+}
+
+func (m modulePrinter) Errorf(format string, args ...interface{}) {
+	DefaultPrinter.log(string(m), LevelError, format, args...)
+}
@@ -20,8 +20,23 @@ type CLI interface {
 
 	PrintCorrection(n int, c *models.Correction)
 	EndCorrection(err error)
-	PromptToRun() bool
-}
+	PromptToRun() Answer
+}
+
+// Answer is the user's answer to PromptToRun.
+type Answer int
+
+const (
+	// AnswerNo skips the correction.
+	AnswerNo Answer = iota
+	// AnswerYes runs the correction.
+	AnswerYes
+	// AnswerAll runs the correction and all the following ones without
+	// asking.
+	AnswerAll
+	// AnswerQuit skips the correction and all the following ones.
+	AnswerQuit
+)
 
 // Printer is a simple abstraction for printing data. Can be passed to providers to give simple output capabilities.
 type Printer interface {
@@ -77,38 +92,69 @@ type ConsolePrinter struct {
 	Writer io.Writer
 
 	Verbose bool
+	JSON    bool // Write the messages as JSON lines
 }
 
 // StartDomain is called at the start of each domain.
 func (c ConsolePrinter) StartDomain(domain string) {
+	if c.JSON {
+		c.writeJSON(LevelInfo, logEntry{Event: "domain", Domain: domain}, "Domain: "+domain)
+		return
+	}
 	fmt.Fprintf(c.Writer, "******************** Domain: %s\n", domain)
 }
 
 // PrintCorrection is called to print/format each correction.
 func (c ConsolePrinter) PrintCorrection(i int, correction *models.Correction) {
+	if c.JSON {
+		c.writeJSON(LevelInfo, logEntry{Event: "correction", Correction: i + 1}, correction.Msg)
+		return
+	}
 	fmt.Fprintf(c.Writer, "#%d: %s\n", i+1, correction.Msg)
 }
 
 // PromptToRun prompts the user to see if they want to execute a correction.
-func (c ConsolePrinter) PromptToRun() bool {
-	fmt.Fprint(c.Writer, "Run? (Y/n): ")
-	txt, err := c.Reader.ReadString('\n')
-	run := true
-	if err != nil {
-		run = false
+func (c ConsolePrinter) PromptToRun() Answer {
+	say := func(msg string) {
+		if c.JSON {
+			c.writeJSON(LevelInfo, logEntry{Event: "prompt"}, msg)
+			return
+		}
+		fmt.Fprintln(c.Writer, msg)
+	}
+	if c.JSON {
+		say("Run? (y)es, (n)o, (a)ll, (q)uit")
+	} else {
+		fmt.Fprint(c.Writer, "Run? (y)es, (n)o, (a)ll, (q)uit: ")
 	}
-	txt = strings.ToLower(strings.TrimSpace(txt))
-	if txt != "y" {
-		run = false
+	txt, err := c.Reader.ReadString('\n')
+	if err != nil && txt == "" {
+		say("Quitting")
+		return AnswerQuit
 	}
-	if !run {
-		fmt.Fprintln(c.Writer, "Skipping")
+	switch strings.ToLower(strings.TrimSpace(txt)) {
+	case "y", "yes":
+		return AnswerYes
+	case "a", "all":
+		return AnswerAll
+	case "q", "quit":
+		say("Quitting")
+		return AnswerQuit
 	}
-	return run
+	say("Skipping")
+	return AnswerNo
 }
 
 // EndCorrection is called at the end of each correction.
 func (c ConsolePrinter) EndCorrection(err error) {
+	if c.JSON {
+		if err != nil {
+			c.writeJSON(LevelError, logEntry{Event: "correction_result", Error: err.Error()}, "FAILURE!")
+		} else {
+			c.writeJSON(LevelInfo, logEntry{Event: "correction_result"}, "SUCCESS!")
+		}
+		return
+	}
 	if err != nil {
 		fmt.Fprintln(c.Writer, "FAILURE!", err)
 	} else {
@@ -118,6 +164,12 @@ func (c ConsolePrinter) EndCorrection(err error) {
 
 // StartDNSProvider is called at the start of each new provider.
 func (c ConsolePrinter) StartDNSProvider(provider string, skip bool) {
+	if c.JSON {
+		// Always written, as it tells which provider the following
+		// corrections belong to.
+		c.writeJSON(LevelInfo, logEntry{Event: "dns_provider", Provider: provider, Skip: skip}, "DNS Provider: "+provider)
+		return
+	}
 	lbl := ""
 	if skip {
 		lbl = " (skipping)\n"
@@ -129,6 +181,10 @@ func (c ConsolePrinter) StartDNSProvider(provider string, skip bool) {
 
 // StartRegistrar is called at the start of each new registrar.
 func (c ConsolePrinter) StartRegistrar(provider string, skip bool) {
+	if c.JSON {
+		c.writeJSON(LevelInfo, logEntry{Event: "registrar", Registrar: provider, Skip: skip}, "Registrar: "+provider)
+		return
+	}
 	lbl := ""
 	if skip {
 		lbl = " (skipping)\n"
@@ -140,14 +196,22 @@ func (c ConsolePrinter) StartRegistrar(provider string, skip bool) {
 
 // EndProvider is called at the end of each provider.
 func (c ConsolePrinter) EndProvider(numCorrections int, err error) {
+	plural := "s"
+	if numCorrections == 1 {
+		plural = ""
+	}
+	if c.JSON {
+		if err != nil {
+			c.writeJSON(LevelError, logEntry{Event: "provider_result", Error: err.Error()}, "Error getting corrections")
+		} else {
+			c.writeJSON(LevelInfo, logEntry{Event: "provider_result", Corrections: &numCorrections}, fmt.Sprintf("%d correction%s", numCorrections, plural))
+		}
+		return
+	}
 	if err != nil {
 		fmt.Fprintln(c.Writer, "ERROR")
 		fmt.Fprintf(c.Writer, "Error getting corrections: %s\n", err)
 	} else {
-		plural := "s"
-		if numCorrections == 1 {
-			plural = ""
-		}
 		if (SkinnyReport) && (numCorrections == 0) {
 			return
 		}
@@ -157,27 +221,25 @@ func (c ConsolePrinter) EndProvider(numCorrections int, err error) {
 
 // Debugf is called to print/format debug information.
 func (c ConsolePrinter) Debugf(format string, args ...interface{}) {
-	if c.Verbose {
-		fmt.Fprintf(c.Writer, format, args...)
-	}
+	c.log("", LevelDebug, format, args...)
 }
 
 // Printf is called to print/format information.
 func (c ConsolePrinter) Printf(format string, args ...interface{}) {
-	fmt.Fprintf(c.Writer, format, args...)
+	c.log("", LevelInfo, format, args...)
 }
 
 // Println is called to print/format information.
 func (c ConsolePrinter) Println(lines ...string) {
-	fmt.Fprintln(c.Writer, lines)
+	c.log("", LevelInfo, "%v\n", lines)
 }
 
 // Warnf is called to print/format a warning.
 func (c ConsolePrinter) Warnf(format string, args ...interface{}) {
-	fmt.Fprintf(c.Writer, "WARNING: "+format, args...)
+	c.log("", LevelWarn, format, args...)
 }
 
 // Errorf is called to print/format an error.
 func (c ConsolePrinter) Errorf(format string, args ...interface{}) {
-	fmt.Fprintf(c.Writer, "ERROR: "+format, args...)
+	c.log("", LevelError, format, args...)
 }
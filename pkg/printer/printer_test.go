@@ -2,8 +2,12 @@ package printer
 
 import (
 	"bytes"
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/StackExchange/dnscontrol/v3/models"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,3 +49,56 @@ func TestVerbose(t *testing.T) {
 	p.Debugf("more debugging\n")
 	assert.Equal(t, "WARNING: a dire warning!\noutput\nmore debugging\n", output.String())
 }
+
+func TestModuleLevels(t *testing.T) {
+	old := DefaultPrinter
+	defer func() {
+		DefaultPrinter = old
+		SetModuleLevels(nil)
+	}()
+
+	output := &bytes.Buffer{}
+	DefaultPrinter = &ConsolePrinter{Writer: output}
+	assert.NoError(t, SetModuleLevels([]string{"providers=debug,providers.bind=warn", "pkg.diff2=error"}))
+
+	Module("providers.cloudflare").Debugf("cf\n")
+	Module("providers.bind").Printf("bind info\n")
+	Module("providers.bind").Warnf("bind\n")
+	Module("pkg.diff2").Warnf("diff2\n")
+	Module("pkg.diff").Debugf("diff\n")
+	assert.Equal(t, "cf\nWARNING: bind\n", output.String())
+
+	assert.Error(t, SetModuleLevels([]string{"providers"}))
+	assert.Error(t, SetModuleLevels([]string{"providers=loud"}))
+}
+
+func TestJSON(t *testing.T) {
+	output := &bytes.Buffer{}
+	p := ConsolePrinter{Writer: output, JSON: true}
+	p.Warnf("a dire %s!\n", "warning")
+	p.Debugf("debugging\n")
+	assert.Regexp(t, `^\{"time":"[^"]+","level":"warn","msg":"a dire warning!"\}\n$`, output.String())
+}
+
+func TestJSONCLI(t *testing.T) {
+	output := &bytes.Buffer{}
+	p := ConsolePrinter{Writer: output, JSON: true}
+	p.StartDomain("example.com")
+	p.StartDNSProvider("bind", false)
+	p.PrintCorrection(0, &models.Correction{Msg: "CREATE A www.example.com"})
+	p.EndCorrection(errors.New("boom"))
+	p.EndProvider(1, nil)
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	want := []string{
+		`"level":"info","event":"domain","domain":"example.com","msg":"Domain: example.com"}`,
+		`"level":"info","event":"dns_provider","provider":"bind","msg":"DNS Provider: bind"}`,
+		`"level":"info","event":"correction","correction":1,"msg":"CREATE A www.example.com"}`,
+		`"level":"error","event":"correction_result","error":"boom","msg":"FAILURE!"}`,
+		`"level":"info","event":"provider_result","corrections":1,"msg":"1 correction"}`,
+	}
+	if assert.Len(t, lines, len(want)) {
+		for i := range want {
+			assert.Contains(t, lines[i], want[i])
+		}
+	}
+}
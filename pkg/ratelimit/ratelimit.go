@@ -0,0 +1,87 @@
+// Package ratelimit spaces the requests that providers make to APIs
+// that limit their rate.
+//
+// A provider creates one Limiter, calls Wait before each request and
+// returns the Limiter from RateLimit() (see providers.RateLimiter).
+// As the provider is shared by all the domains that use it, the limit
+// holds when preview and push run several domains at once.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket: up to Burst requests may be made at once,
+// then PerSecond requests per second. It is safe for concurrent use.
+type Limiter struct {
+	PerSecond float64
+	Burst     int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	sleep  func(time.Duration) // time.Sleep, except in tests
+	now    func() time.Time    // time.Now, except in tests
+}
+
+// New returns a Limiter that allows perSecond requests per second,
+// and up to burst at once.
+func New(perSecond float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		PerSecond: perSecond,
+		Burst:     burst,
+		tokens:    float64(burst),
+		sleep:     time.Sleep,
+		now:       time.Now,
+	}
+}
+
+// Wait blocks until a request may be made. A nil Limiter doesn't
+// limit anything.
+func (l *Limiter) Wait() {
+	if l == nil || l.PerSecond <= 0 {
+		return
+	}
+	l.mu.Lock()
+	l.refill()
+	// Take the token now, even if it is only available later, so that
+	// the waiting callers are served in order.
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.PerSecond * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if wait > 0 {
+		l.sleep(wait)
+	}
+}
+
+// Available returns how many requests may be made at once right now,
+// i.e. the headroom under the limit. It is negative if requests are
+// waiting. A nil Limiter returns 0.
+func (l *Limiter) Available() float64 {
+	if l == nil || l.PerSecond <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	return l.tokens
+}
+
+// refill adds the tokens earned since the last call. l.mu must be held.
+func (l *Limiter) refill() {
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.PerSecond
+		if l.tokens > float64(l.Burst) {
+			l.tokens = float64(l.Burst)
+		}
+	}
+	l.last = now
+}
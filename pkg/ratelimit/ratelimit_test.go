@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWait(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	var waits []time.Duration
+	l := New(2, 2)
+	l.now = func() time.Time { return now }
+	l.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	// The burst is free; the next ones are spaced by half a second.
+	for i := 0; i < 4; i++ {
+		l.Wait()
+	}
+	if len(waits) != 2 || waits[0] != 500*time.Millisecond || waits[1] != time.Second {
+		t.Errorf("got waits %v, want [500ms 1s]", waits)
+	}
+
+	// After a while, the bucket is full again (but not more than full).
+	waits = nil
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		l.Wait()
+	}
+	if len(waits) != 1 || waits[0] != 500*time.Millisecond {
+		t.Errorf("got waits %v, want [500ms]", waits)
+	}
+
+	if got := l.Available(); got != -1 {
+		t.Errorf("got %v available, want -1", got)
+	}
+	now = now.Add(time.Minute)
+	if got := l.Available(); got != 2 {
+		t.Errorf("got %v available, want 2", got)
+	}
+
+	var nilLimiter *Limiter
+	nilLimiter.Wait()
+	if got := nilLimiter.Available(); got != 0 {
+		t.Errorf("got %v available from a nil Limiter, want 0", got)
+	}
+}
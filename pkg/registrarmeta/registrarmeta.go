@@ -0,0 +1,88 @@
+// Package registrarmeta manages the registrar settings of a domain
+// (auto-renew, WHOIS privacy) from the metadata of D():
+//
+//	D("example.com", REG, {registrar_autorenew: "on", registrar_whois_privacy: "on"}, ...)
+//
+// Registrars that can manage these settings implement
+// providers.DomainMetaCorrector. They report the current settings and
+// a function that changes them to Corrections, which compares them
+// with the metadata.
+package registrarmeta
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// The metadata keys. Their values are "on" or "off"; when a key isn't
+// set, the setting is left alone.
+const (
+	AutoRenew    = "registrar_autorenew"
+	WhoisPrivacy = "registrar_whois_privacy"
+)
+
+// Keys lists the metadata keys, in the order the corrections are made.
+var Keys = []string{AutoRenew, WhoisPrivacy}
+
+var names = map[string]string{
+	AutoRenew:    "auto-renew",
+	WhoisPrivacy: "WHOIS privacy",
+}
+
+// Desired returns the settings requested by the metadata of dc.
+func Desired(dc *models.DomainConfig) (map[string]bool, error) {
+	desired := map[string]bool{}
+	for _, key := range Keys {
+		switch v := strings.ToLower(dc.Metadata[key]); v {
+		case "":
+		case "on":
+			desired[key] = true
+		case "off":
+			desired[key] = false
+		default:
+			return nil, fmt.Errorf("bad metadata value for %s: '%s'. Use on/off", key, dc.Metadata[key])
+		}
+	}
+	return desired, nil
+}
+
+// Corrections returns the corrections that change the settings in
+// current to the ones requested by the metadata of dc. set changes one
+// setting. It is an error if the metadata requests a setting that isn't
+// in current, i.e. that the registrar can't manage.
+func Corrections(dc *models.DomainConfig, current map[string]bool, set func(key string, on bool) error) ([]*models.Correction, error) {
+	desired, err := Desired(dc)
+	if err != nil {
+		return nil, err
+	}
+	var corrections []*models.Correction
+	for _, key := range Keys {
+		want, ok := desired[key]
+		if !ok {
+			continue
+		}
+		have, ok := current[key]
+		if !ok {
+			return nil, fmt.Errorf("%s: this registrar can't manage %s", key, names[key])
+		}
+		if have == want {
+			continue
+		}
+		key := key
+		corrections = append(corrections, &models.Correction{
+			Msg:  fmt.Sprintf("Turn %s %s", names[key], onOff(want)),
+			Kind: models.CorrectionModify,
+			F:    func() error { return set(key, want) },
+		})
+	}
+	return corrections, nil
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,47 @@
+package registrarmeta
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestCorrections(t *testing.T) {
+	dc := &models.DomainConfig{Name: "example.com", Metadata: map[string]string{
+		AutoRenew:    "on",
+		WhoisPrivacy: "Off",
+	}}
+	set := map[string]bool{}
+	corrections, err := Corrections(dc, map[string]bool{AutoRenew: true, WhoisPrivacy: true}, func(key string, on bool) error {
+		set[key] = on
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 1 || corrections[0].Msg != "Turn WHOIS privacy off" {
+		t.Fatalf("got corrections %v", corrections)
+	}
+	if err := corrections[0].F(); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := set[WhoisPrivacy]; !ok || v {
+		t.Errorf("got set %v", set)
+	}
+
+	// The registrar can't manage auto-renew.
+	if _, err := Corrections(dc, map[string]bool{WhoisPrivacy: false}, nil); err == nil {
+		t.Error("expected an error for an unmanaged setting, got none")
+	}
+
+	dc.Metadata[AutoRenew] = "yes"
+	if _, err := Desired(dc); err == nil {
+		t.Error("expected an error for a bad value, got none")
+	}
+
+	// Nothing requested.
+	corrections, err = Corrections(&models.DomainConfig{Name: "example.com"}, nil, nil)
+	if err != nil || len(corrections) != 0 {
+		t.Errorf("got %v, %v", corrections, err)
+	}
+}
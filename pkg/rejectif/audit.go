@@ -5,11 +5,13 @@ import "github.com/StackExchange/dnscontrol/v3/models"
 // Auditor stores a list of checks to be executed during Audit().
 type Auditor struct {
 	checksFor map[string][]checker
+	warnsFor  map[string][]checker
 }
 
 type checker = func(*models.RecordConfig) error
 
-// Add registers a function to call on each record of a given type.
+// Add registers a function to call on each record of a given type, or
+// of any type if rtype is "*".
 func (aud *Auditor) Add(rtype string, fn checker) {
 	if aud.checksFor == nil {
 		aud.checksFor = map[string][]checker{}
@@ -22,22 +24,53 @@ func (aud *Auditor) Add(rtype string, fn checker) {
 	}
 }
 
+// AddWarn registers a function to call on each record of a given type,
+// like Add(), for records that are risky but allowed. Its errors are
+// returned as AuditWarn, which validation reports as warnings rather
+// than rejecting the configuration.
+func (aud *Auditor) AddWarn(rtype string, fn checker) {
+	if aud.warnsFor == nil {
+		aud.warnsFor = map[string][]checker{}
+	}
+	aud.warnsFor[rtype] = append(aud.warnsFor[rtype], fn)
+
+	// SPF records get any checkers that TXT records do.
+	if rtype == "TXT" {
+		aud.AddWarn("SPF", fn)
+	}
+}
+
+// AuditWarn is the error of a check registered with AddWarn().
+type AuditWarn struct {
+	Err error
+}
+
+func (w AuditWarn) Error() string { return w.Err.Error() }
+
+// Unwrap returns the error of the check.
+func (w AuditWarn) Unwrap() error { return w.Err }
+
 // Audit performs the audit. For each record it calls each function in
 // the list of checks.
 func (aud *Auditor) Audit(records models.Records) (errs []error) {
 	// No checks? Exit early.
-	if aud.checksFor == nil {
+	if aud.checksFor == nil && aud.warnsFor == nil {
 		return nil
 	}
 
 	// For each record, call the checks for that type, gather errors.
 	for _, rc := range records {
-		for _, f := range aud.checksFor[rc.Type] {
+		for _, f := range append(aud.checksFor[rc.Type], aud.checksFor["*"]...) {
 			e := f(rc)
 			if e != nil {
 				errs = append(errs, e)
 			}
 		}
+		for _, f := range append(aud.warnsFor[rc.Type], aud.warnsFor["*"]...) {
+			if e := f(rc); e != nil {
+				errs = append(errs, AuditWarn{e})
+			}
+		}
 	}
 
 	return errs
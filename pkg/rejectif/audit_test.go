@@ -0,0 +1,42 @@
+package rejectif
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestAudit(t *testing.T) {
+	a := Auditor{}
+	a.Add("TXT", TxtIsEmpty)
+	a.AddWarn("*", TTLIsVeryLow)
+	a.AddWarn("TXT", TxtSpfNearLookupLimit)
+
+	txt := func(ttl uint32, s string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: "TXT", TTL: ttl}
+		rc.SetTargetTXT(s)
+		return rc
+	}
+	errs := a.Audit(models.Records{
+		txt(300, "ok"),
+		txt(300, ""),
+		txt(30, "low"),
+		txt(300, "v=spf1 a mx include:a.example include:b.example include:c.example ptr exists:%{i}.example redirect=d.example"),
+		txt(300, "v=spf1 a mx include:a.example -all"),
+		{Type: "A", TTL: 10},
+	})
+
+	var warnings, rejections int
+	for _, e := range errs {
+		var w AuditWarn
+		if errors.As(e, &w) {
+			warnings++
+		} else {
+			rejections++
+		}
+	}
+	if warnings != 3 || rejections != 1 {
+		t.Errorf("got %d warnings and %d rejections, want 3 and 1: %v", warnings, rejections, errs)
+	}
+}
@@ -17,6 +17,16 @@ func CaaFlagIsNonZero(rc *models.RecordConfig) error {
 	return nil
 }
 
+// CaaInvalidTag identifies CAA records with a tag other than the ones
+// defined in RFC 8659 (issue, issuewild, iodef).
+func CaaInvalidTag(rc *models.RecordConfig) error {
+	switch rc.CaaTag {
+	case "issue", "issuewild", "iodef":
+		return nil
+	}
+	return fmt.Errorf("caa tag %q on %s is invalid (must be issue, issuewild or iodef)", rc.CaaTag, rc.GetLabelFQDN())
+}
+
 // CaaTargetContainsWhitespace identifies CAA records that have
 // whitespace in the target.
 // See https://github.com/StackExchange/dnscontrol/issues/1374
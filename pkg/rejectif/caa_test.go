@@ -0,0 +1,41 @@
+package rejectif
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestCaa(t *testing.T) {
+	caa := func(flag uint8, tag, target string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: "CAA", NameFQDN: "example.com", CaaFlag: flag, CaaTag: tag}
+		rc.SetTarget(target)
+		return rc
+	}
+	tests := []struct {
+		name   string
+		fn     func(*models.RecordConfig) error
+		rc     *models.RecordConfig
+		reject bool
+	}{
+		{"flag zero", CaaFlagIsNonZero, caa(0, "issue", "letsencrypt.org"), false},
+		{"flag critical", CaaFlagIsNonZero, caa(128, "issue", "letsencrypt.org"), true},
+		{"tag issue", CaaInvalidTag, caa(0, "issue", "letsencrypt.org"), false},
+		{"tag issuewild", CaaInvalidTag, caa(0, "issuewild", "letsencrypt.org"), false},
+		{"tag iodef", CaaInvalidTag, caa(0, "iodef", "mailto:hostmaster@example.com"), false},
+		{"tag unknown", CaaInvalidTag, caa(0, "issuemail", "letsencrypt.org"), true},
+		{"tag uppercase", CaaInvalidTag, caa(0, "ISSUE", "letsencrypt.org"), true},
+		{"target plain", CaaTargetContainsWhitespace, caa(0, "issue", "letsencrypt.org"), false},
+		{"target space", CaaTargetContainsWhitespace, caa(0, "issue", "letsencrypt.org; accounturi=x y"), true},
+		{"target tab", CaaTargetContainsWhitespace, caa(0, "issue", "letsencrypt.org\t"), true},
+		{"target no semicolon", CaaTargetHasSemicolon, caa(0, "issue", "letsencrypt.org"), false},
+		{"target semicolon", CaaTargetHasSemicolon, caa(0, "issue", "letsencrypt.org;validationmethods=dns-01"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(tt.rc); (err != nil) != tt.reject {
+				t.Errorf("got %v, want reject=%v", err, tt.reject)
+			}
+		})
+	}
+}
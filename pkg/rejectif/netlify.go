@@ -0,0 +1,28 @@
+package rejectif
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Keep these in alphabetical order.
+
+// NetlifyTargetEmpty detects NETLIFY/NETLIFYv6 records with no target.
+func NetlifyTargetEmpty(rc *models.RecordConfig) error {
+	if rc.GetTargetField() == "" {
+		return fmt.Errorf("%s has empty target", rc.Type)
+	}
+	return nil
+}
+
+// NetlifyTargetHasDot detects NETLIFY/NETLIFYv6 records whose target
+// ends with a dot. Netlify stores the bare site hostname (e.g.
+// "example.netlify.app"), so such a record would never match.
+func NetlifyTargetHasDot(rc *models.RecordConfig) error {
+	if strings.HasSuffix(rc.GetTargetField(), ".") {
+		return fmt.Errorf("%s target ends with a dot", rc.Type)
+	}
+	return nil
+}
@@ -15,3 +15,13 @@ func SrvHasNullTarget(rc *models.RecordConfig) error {
 	}
 	return nil
 }
+
+// SrvNullTargetWithPort detects SRV records that have a null target
+// but a non-zero port. RFC 2782 uses a target of "." to indicate the
+// service is not available, therefore the port is meaningless.
+func SrvNullTargetWithPort(rc *models.RecordConfig) error {
+	if rc.GetTargetField() == "." && rc.SrvPort != 0 {
+		return fmt.Errorf("srv on %s has null target (\".\") but port %d (must be 0)", rc.GetLabelFQDN(), rc.SrvPort)
+	}
+	return nil
+}
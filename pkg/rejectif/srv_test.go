@@ -0,0 +1,34 @@
+package rejectif
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestSrv(t *testing.T) {
+	srv := func(port uint16, target string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: "SRV", NameFQDN: "_sip._tcp.example.com", SrvPort: port}
+		rc.SetTarget(target)
+		return rc
+	}
+	tests := []struct {
+		name   string
+		fn     func(*models.RecordConfig) error
+		rc     *models.RecordConfig
+		reject bool
+	}{
+		{"target host", SrvHasNullTarget, srv(5060, "sip.example.com."), false},
+		{"target null", SrvHasNullTarget, srv(0, "."), true},
+		{"null target port zero", SrvNullTargetWithPort, srv(0, "."), false},
+		{"null target with port", SrvNullTargetWithPort, srv(5060, "."), true},
+		{"host target with port", SrvNullTargetWithPort, srv(5060, "sip.example.com."), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(tt.rc); (err != nil) != tt.reject {
+				t.Errorf("got %v, want reject=%v", err, tt.reject)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package rejectif
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Keep these in alphabetical order.
+
+// SshfpUnknownAlgorithm identifies SSHFP records with an algorithm
+// that is not registered with IANA (1=RSA, 2=DSA, 3=ECDSA, 4=Ed25519,
+// 6=Ed448).
+func SshfpUnknownAlgorithm(rc *models.RecordConfig) error {
+	switch rc.SshfpAlgorithm {
+	case 1, 2, 3, 4, 6:
+		return nil
+	}
+	return fmt.Errorf("sshfp algorithm %d on %s is unknown (must be 1, 2, 3, 4 or 6)", rc.SshfpAlgorithm, rc.GetLabelFQDN())
+}
+
+// SshfpUnknownFingerprintType identifies SSHFP records with a
+// fingerprint type that is not registered with IANA (1=SHA-1,
+// 2=SHA-256).
+func SshfpUnknownFingerprintType(rc *models.RecordConfig) error {
+	switch rc.SshfpFingerprint {
+	case 1, 2:
+		return nil
+	}
+	return fmt.Errorf("sshfp fingerprint type %d on %s is unknown (must be 1 or 2)", rc.SshfpFingerprint, rc.GetLabelFQDN())
+}
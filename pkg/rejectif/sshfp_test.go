@@ -0,0 +1,36 @@
+package rejectif
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestSshfp(t *testing.T) {
+	sshfp := func(alg, fp uint8) *models.RecordConfig {
+		return &models.RecordConfig{Type: "SSHFP", NameFQDN: "host.example.com", SshfpAlgorithm: alg, SshfpFingerprint: fp}
+	}
+	tests := []struct {
+		name   string
+		fn     func(*models.RecordConfig) error
+		rc     *models.RecordConfig
+		reject bool
+	}{
+		{"algorithm rsa", SshfpUnknownAlgorithm, sshfp(1, 2), false},
+		{"algorithm ed25519", SshfpUnknownAlgorithm, sshfp(4, 2), false},
+		{"algorithm ed448", SshfpUnknownAlgorithm, sshfp(6, 2), false},
+		{"algorithm zero", SshfpUnknownAlgorithm, sshfp(0, 2), true},
+		{"algorithm unassigned", SshfpUnknownAlgorithm, sshfp(5, 2), true},
+		{"fingerprint sha1", SshfpUnknownFingerprintType, sshfp(1, 1), false},
+		{"fingerprint sha256", SshfpUnknownFingerprintType, sshfp(1, 2), false},
+		{"fingerprint zero", SshfpUnknownFingerprintType, sshfp(1, 0), true},
+		{"fingerprint unassigned", SshfpUnknownFingerprintType, sshfp(1, 3), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(tt.rc); (err != nil) != tt.reject {
+				t.Errorf("got %v, want reject=%v", err, tt.reject)
+			}
+		})
+	}
+}
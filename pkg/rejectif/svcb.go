@@ -0,0 +1,29 @@
+package rejectif
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Keep these in alphabetical order.
+
+// SvcbAliasModeHasParams identifies SVCB and HTTPS records in
+// AliasMode (priority 0) that have SvcParams. RFC 9460 says AliasMode
+// records should not include any SvcParams; many providers reject them.
+func SvcbAliasModeHasParams(rc *models.RecordConfig) error {
+	if rc.SvcPriority == 0 && rc.SvcParams != "" {
+		return fmt.Errorf("%s on %s is in AliasMode (priority 0) but has params %q", rc.Type, rc.GetLabelFQDN(), rc.SvcParams)
+	}
+	return nil
+}
+
+// SvcbNullTargetInAliasMode identifies SVCB and HTTPS records in
+// AliasMode (priority 0) whose target is ".", which RFC 9460 defines
+// as "service not available" and some providers do not accept.
+func SvcbNullTargetInAliasMode(rc *models.RecordConfig) error {
+	if rc.SvcPriority == 0 && rc.GetTargetField() == "." {
+		return fmt.Errorf("%s on %s is in AliasMode (priority 0) with a null target (\".\")", rc.Type, rc.GetLabelFQDN())
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package rejectif
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestSvcb(t *testing.T) {
+	svcb := func(priority uint16, target, params string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: "HTTPS", NameFQDN: "example.com", SvcPriority: priority, SvcParams: params}
+		rc.SetTarget(target)
+		return rc
+	}
+	tests := []struct {
+		name   string
+		fn     func(*models.RecordConfig) error
+		rc     *models.RecordConfig
+		reject bool
+	}{
+		{"alias without params", SvcbAliasModeHasParams, svcb(0, "cdn.example.net.", ""), false},
+		{"alias with params", SvcbAliasModeHasParams, svcb(0, "cdn.example.net.", "alpn=h2"), true},
+		{"service with params", SvcbAliasModeHasParams, svcb(1, "cdn.example.net.", "alpn=h2"), false},
+		{"alias with host target", SvcbNullTargetInAliasMode, svcb(0, "cdn.example.net.", ""), false},
+		{"alias with null target", SvcbNullTargetInAliasMode, svcb(0, ".", ""), true},
+		{"service with null target", SvcbNullTargetInAliasMode, svcb(1, ".", "alpn=h2"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(tt.rc); (err != nil) != tt.reject {
+				t.Errorf("got %v, want reject=%v", err, tt.reject)
+			}
+		})
+	}
+}
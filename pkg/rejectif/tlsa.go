@@ -0,0 +1,37 @@
+package rejectif
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Keep these in alphabetical order.
+
+// TlsaInvalidMatchingType identifies TLSA records with a matching type
+// other than 0 (full), 1 (SHA-256) or 2 (SHA-512). See RFC 6698.
+func TlsaInvalidMatchingType(rc *models.RecordConfig) error {
+	if rc.TlsaMatchingType > 2 {
+		return fmt.Errorf("tlsa matching type %d on %s is invalid (must be 0-2)", rc.TlsaMatchingType, rc.GetLabelFQDN())
+	}
+	return nil
+}
+
+// TlsaInvalidSelector identifies TLSA records with a selector other
+// than 0 (full certificate) or 1 (public key). See RFC 6698.
+func TlsaInvalidSelector(rc *models.RecordConfig) error {
+	if rc.TlsaSelector > 1 {
+		return fmt.Errorf("tlsa selector %d on %s is invalid (must be 0 or 1)", rc.TlsaSelector, rc.GetLabelFQDN())
+	}
+	return nil
+}
+
+// TlsaInvalidUsage identifies TLSA records with a certificate usage
+// other than 0 (PKIX-TA), 1 (PKIX-EE), 2 (DANE-TA) or 3 (DANE-EE).
+// See RFC 7218.
+func TlsaInvalidUsage(rc *models.RecordConfig) error {
+	if rc.TlsaUsage > 3 {
+		return fmt.Errorf("tlsa usage %d on %s is invalid (must be 0-3)", rc.TlsaUsage, rc.GetLabelFQDN())
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package rejectif
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestTlsa(t *testing.T) {
+	tlsa := func(usage, selector, matching uint8) *models.RecordConfig {
+		return &models.RecordConfig{Type: "TLSA", NameFQDN: "_443._tcp.example.com", TlsaUsage: usage, TlsaSelector: selector, TlsaMatchingType: matching}
+	}
+	tests := []struct {
+		name   string
+		fn     func(*models.RecordConfig) error
+		rc     *models.RecordConfig
+		reject bool
+	}{
+		{"matching full", TlsaInvalidMatchingType, tlsa(3, 1, 0), false},
+		{"matching sha512", TlsaInvalidMatchingType, tlsa(3, 1, 2), false},
+		{"matching unknown", TlsaInvalidMatchingType, tlsa(3, 1, 3), true},
+		{"selector cert", TlsaInvalidSelector, tlsa(3, 0, 1), false},
+		{"selector spki", TlsaInvalidSelector, tlsa(3, 1, 1), false},
+		{"selector unknown", TlsaInvalidSelector, tlsa(3, 2, 1), true},
+		{"usage pkix-ta", TlsaInvalidUsage, tlsa(0, 1, 1), false},
+		{"usage dane-ee", TlsaInvalidUsage, tlsa(3, 1, 1), false},
+		{"usage unknown", TlsaInvalidUsage, tlsa(4, 1, 1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(tt.rc); (err != nil) != tt.reject {
+				t.Errorf("got %v, want reject=%v", err, tt.reject)
+			}
+		})
+	}
+}
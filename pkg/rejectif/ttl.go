@@ -0,0 +1,20 @@
+package rejectif
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// ttlVeryLow is the TTL under which TTLIsVeryLow warns.
+const ttlVeryLow = 60
+
+// TTLIsVeryLow audits records with a TTL under a minute, which most
+// resolvers don't honor and which multiply the queries. It is meant for
+// Auditor.AddWarn().
+func TTLIsVeryLow(rc *models.RecordConfig) error {
+	if rc.TTL < ttlVeryLow {
+		return fmt.Errorf("TTL %d is very low", rc.TTL)
+	}
+	return nil
+}
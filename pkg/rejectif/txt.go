@@ -102,3 +102,32 @@ func TxtHasUnpairedDoubleQuotes(rc *models.RecordConfig) error {
 	}
 	return nil
 }
+
+// spfLookupWarning is the number of DNS lookups of an SPF record from
+// which TxtSpfNearLookupLimit warns. RFC 7208 allows 10.
+const spfLookupWarning = 8
+
+// TxtSpfNearLookupLimit audits SPF records whose mechanisms need close
+// to the 10 DNS lookups allowed, not counting the lookups of the
+// included records. It is meant for Auditor.AddWarn().
+func TxtSpfNearLookupLimit(rc *models.RecordConfig) error {
+	txt := strings.Join(rc.TxtStrings, "")
+	if !strings.HasPrefix(strings.ToLower(txt), "v=spf1 ") {
+		return nil
+	}
+	lookups := 0
+	for _, term := range strings.Fields(txt)[1:] {
+		term = strings.TrimLeft(strings.ToLower(term), "+-~?")
+		name, _, _ := strings.Cut(term, ":")
+		name, _, _ = strings.Cut(name, "/")
+		switch {
+		case name == "a", name == "mx", name == "ptr", name == "include", name == "exists",
+			strings.HasPrefix(term, "redirect="):
+			lookups++
+		}
+	}
+	if lookups >= spfLookupWarning {
+		return fmt.Errorf("SPF record needs %d of the 10 DNS lookups allowed, before those of its includes", lookups)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Package snapshot saves the records of zones before push changes them,
+// so that "dnscontrol rollback" can restore them.
+//
+// A snapshot is a directory with one file per domain and DNS provider:
+//
+//	SNAPSHOTDIR/20230102-150405/example.com/r53_main.json
+//
+// The files are either JSON (the default, which keeps the metadata of
+// the records) or zone files (which are easier to read, but can only
+// hold standard record types).
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/prettyzone"
+	"github.com/miekg/dns"
+)
+
+// Formats lists the formats that snapshots can be written in.
+var Formats = []string{"json", "zone"}
+
+// NewDir returns the name of a new snapshot directory in dir, named after
+// the current time.
+func NewDir(dir string, now time.Time) string {
+	return filepath.Join(dir, now.UTC().Format("20060102-150405"))
+}
+
+// Write saves the records of domain at provider in the snapshot
+// directory dir, in format ("json" or "zone"). It returns the name of
+// the file.
+func Write(dir, domain, provider string, records models.Records, format string) (string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "json":
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if records == nil {
+			records = models.Records{}
+		}
+		if err := enc.Encode(records); err != nil {
+			return "", fmt.Errorf("snapshot of %s at %s: %w", domain, provider, err)
+		}
+	case "zone":
+		if err := prettyzone.WriteZoneFileRC(&buf, records, domain, 0, nil); err != nil {
+			return "", fmt.Errorf("snapshot of %s at %s: %w", domain, provider, err)
+		}
+	default:
+		return "", fmt.Errorf("unknown snapshot format %q (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, domain), 0o750); err != nil {
+		return "", err
+	}
+	name := filepath.Join(dir, domain, provider+"."+format)
+	if err := os.WriteFile(name, buf.Bytes(), 0o640); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// Zone is the snapshot of one domain at one DNS provider.
+type Zone struct {
+	Domain   string
+	Provider string
+	File     string
+}
+
+// List returns the zones in the snapshot directory dir, sorted by
+// domain and provider.
+func List(dir string) ([]Zone, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+	var zones []Zone
+	for _, f := range files {
+		ext := strings.TrimPrefix(filepath.Ext(f), ".")
+		if ext != "json" && ext != "zone" {
+			continue
+		}
+		zones = append(zones, Zone{
+			Domain:   filepath.Base(filepath.Dir(f)),
+			Provider: strings.TrimSuffix(filepath.Base(f), "."+ext),
+			File:     f,
+		})
+	}
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("%s is not a snapshot: no DOMAIN/PROVIDER.json or DOMAIN/PROVIDER.zone files found", dir)
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		if zones[i].Domain != zones[j].Domain {
+			return zones[i].Domain < zones[j].Domain
+		}
+		return zones[i].Provider < zones[j].Provider
+	})
+	return zones, nil
+}
+
+// Read returns the records of a zone in a snapshot.
+func (z Zone) Read() (models.Records, error) {
+	content, err := os.ReadFile(z.File)
+	if err != nil {
+		return nil, err
+	}
+
+	records := models.Records{}
+	if filepath.Ext(z.File) == ".json" {
+		if err := json.Unmarshal(content, &records); err != nil {
+			return nil, fmt.Errorf("can't parse %s: %w", z.File, err)
+		}
+		for _, rc := range records {
+			// NameFQDN isn't saved.
+			rc.SetLabel(rc.GetLabel(), z.Domain)
+		}
+		return records, nil
+	}
+
+	zp := dns.NewZoneParser(bytes.NewReader(content), z.Domain, z.File)
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		rec, err := models.RRtoRC(rr, z.Domain)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &rec)
+	}
+	if err := zp.Err(); err != nil {
+		return nil, fmt.Errorf("can't parse %s: %w", z.File, err)
+	}
+	return records, nil
+}
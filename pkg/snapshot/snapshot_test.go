@@ -0,0 +1,73 @@
+package snapshot
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func makeRec(label, rtype, content string) *models.RecordConfig {
+	rc := &models.RecordConfig{TTL: 300}
+	rc.SetLabel(label, "example.com")
+	if err := rc.PopulateFromString(rtype, content, "example.com"); err != nil {
+		panic(err)
+	}
+	return rc
+}
+
+func TestWriteRead(t *testing.T) {
+	dir := NewDir(t.TempDir(), time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC))
+	if filepath.Base(dir) != "20230102-150405" {
+		t.Errorf("NewDir() = %q", dir)
+	}
+	records := models.Records{
+		makeRec("@", "MX", "10 mx.example.com."),
+		makeRec("www", "A", "1.2.3.4"),
+		makeRec("www", "TXT", "hello world"),
+	}
+	records[1].Metadata = map[string]string{"cloudflare_proxy": "on"}
+
+	for _, format := range Formats {
+		if _, err := Write(dir, "example.com", "dsp_"+format, records, format); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Write(dir, "example.com", "other", records, "yaml"); err == nil {
+		t.Error("expected an error for an unknown format, got none")
+	}
+
+	zones, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 2 || zones[0].Provider != "dsp_json" || zones[1].Provider != "dsp_zone" || zones[0].Domain != "example.com" {
+		t.Fatalf("List() = %+v", zones)
+	}
+	for _, z := range zones {
+		got, err := z.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(records) {
+			t.Fatalf("%s: got %d records, want %d", z.File, len(got), len(records))
+		}
+		want := map[string]bool{}
+		for _, rc := range records {
+			want[rc.NameFQDN+" "+rc.ToDiffable()] = true
+		}
+		for _, rc := range got {
+			if !want[rc.NameFQDN+" "+rc.ToDiffable()] || rc.TTL != 300 {
+				t.Errorf("%s: unexpected record %s %s %s", z.File, rc.NameFQDN, rc.Type, rc.ToDiffable())
+			}
+		}
+		if z.Provider == "dsp_json" && got[1].Metadata["cloudflare_proxy"] != "on" {
+			t.Errorf("%s: lost the metadata: %v", z.File, got[1].Metadata)
+		}
+	}
+
+	if _, err := List(t.TempDir()); err == nil {
+		t.Error("expected an error for an empty directory, got none")
+	}
+}
@@ -114,3 +114,40 @@ func uitoa(val uint) string {
 }
 
 const hexDigit = "0123456789abcdef"
+
+// MaxAddressesInCIDR is the largest block AddressesInCIDR will expand.
+const MaxAddressesInCIDR = 65536
+
+// AddressesInCIDR returns every address in a CIDR block, in order.
+// Like ReverseDomainName, the bits outside the netmask must be zero.
+// Blocks larger than MaxAddressesInCIDR are an error.
+func AddressesInCIDR(cidr string) ([]string, error) {
+	a, c, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	if !a.Equal(c.IP) {
+		return nil, fmt.Errorf("CIDR %v has 1 bits beyond the mask", cidr)
+	}
+
+	ones, bits := c.Mask.Size()
+	if bits-ones > 16 {
+		return nil, fmt.Errorf("CIDR %v has more than %d addresses", cidr, MaxAddressesInCIDR)
+	}
+	n := 1 << (bits - ones)
+
+	ip := make(net.IP, len(c.IP))
+	copy(ip, c.IP)
+	addrs := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		addrs = append(addrs, ip.String())
+		// Increment ip.
+		for j := len(ip) - 1; j >= 0; j-- {
+			ip[j]++
+			if ip[j] != 0 {
+				break
+			}
+		}
+	}
+	return addrs, nil
+}
@@ -92,3 +92,38 @@ func TestReverse(t *testing.T) {
 		})
 	}
 }
+
+func TestAddressesInCIDR(t *testing.T) {
+	var tests = []struct {
+		in      string
+		isError bool
+		n       int
+		first   string
+		last    string
+	}{
+		{"10.1.2.0/24", false, 256, "10.1.2.0", "10.1.2.255"},
+		{"10.1.2.128/30", false, 4, "10.1.2.128", "10.1.2.131"},
+		{"10.1.2.5/32", false, 1, "10.1.2.5", "10.1.2.5"},
+		{"10.1.0.0/16", false, 65536, "10.1.0.0", "10.1.255.255"},
+		{"2001:db8::/120", false, 256, "2001:db8::", "2001:db8::ff"},
+		{"2001:db8::ff00/119", true, 0, "", ""},
+		{"10.1.2.1/24", true, 0, "", ""},
+		{"10.0.0.0/15", true, 0, "", ""},
+		{"2001:db8::/64", true, 0, "", ""},
+		{"10.1.2.0", true, 0, "", ""},
+	}
+	for _, tst := range tests {
+		t.Run(tst.in, func(t *testing.T) {
+			d, err := AddressesInCIDR(tst.in)
+			if (err != nil) != tst.isError {
+				t.Fatalf("AddressesInCIDR(%q) error = %v, isError %v", tst.in, err, tst.isError)
+			}
+			if err != nil {
+				return
+			}
+			if len(d) != tst.n || d[0] != tst.first || d[len(d)-1] != tst.last {
+				t.Errorf("AddressesInCIDR(%q) = %d addresses %s..%s, want %d %s..%s", tst.in, len(d), d[0], d[len(d)-1], tst.n, tst.first, tst.last)
+			}
+		})
+	}
+}
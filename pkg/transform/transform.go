@@ -2,6 +2,7 @@ package transform
 
 import (
 	"fmt"
+	"math/big"
 	"net"
 	"strings"
 )
@@ -31,7 +32,55 @@ func UintToIP(u uint32) net.IP {
 		byte((u)&255))
 }
 
+// ipToInt converts an IPv4 or IPv6 address into an integer. v4 reports
+// whether it is an IPv4 address.
+func ipToInt(i net.IP) (n *big.Int, v4 bool, err error) {
+	if p := i.To4(); p != nil {
+		return new(big.Int).SetBytes(p), true, nil
+	}
+	if p := i.To16(); p != nil {
+		return new(big.Int).SetBytes(p), false, nil
+	}
+	return nil, false, fmt.Errorf("%s is not an ip address", i.String())
+}
+
+// intToIP converts an integer into an IPv4 or IPv6 address. It returns
+// nil if n doesn't fit.
+func intToIP(n *big.Int, v4 bool) net.IP {
+	size := net.IPv6len
+	if v4 {
+		size = net.IPv4len
+	}
+	b := n.Bytes()
+	if n.Sign() < 0 || len(b) > size {
+		return nil
+	}
+	ip := make(net.IP, size)
+	copy(ip[size-len(b):], b)
+	return ip
+}
+
+// parseCIDR returns the first and last address of a CIDR range such as
+// "10.1.0.0/16" or "2001:db8::/64".
+func parseCIDR(s string) (low, high net.IP, err error) {
+	_, ipnet, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	low = ipnet.IP
+	high = make(net.IP, len(low))
+	for i := range low {
+		high[i] = low[i] | ^ipnet.Mask[i]
+	}
+	return low, high, nil
+}
+
 // DecodeTransformTable turns a string-encoded table into a list of conversions.
+//
+// Each row is "low ~ high ~ newBases ~ newIPs". Instead of low and high,
+// the range may be given in CIDR syntax with high left empty:
+// "10.1.0.0/16 ~ ~ 10.2.0.0 ~". IPv4 and IPv6 rows may be mixed, but
+// all the addresses of a row must be of the same family.
 func DecodeTransformTable(transforms string) ([]IPConversion, error) {
 	result := []IPConversion{}
 	rows := strings.Split(transforms, ";")
@@ -44,9 +93,21 @@ func DecodeTransformTable(transforms string) ([]IPConversion, error) {
 			items[i] = strings.TrimSpace(item)
 		}
 
-		con := IPConversion{
-			Low:  net.ParseIP(items[0]),
-			High: net.ParseIP(items[1]),
+		con := IPConversion{}
+		if strings.Contains(items[0], "/") {
+			if items[1] != "" {
+				return nil, fmt.Errorf("transform_table row (%v) should not specify High when Low is a CIDR range (%v)", ri, items[0])
+			}
+			var err error
+			if con.Low, con.High, err = parseCIDR(items[0]); err != nil {
+				return nil, fmt.Errorf("transform_table row (%v): %w", ri, err)
+			}
+		} else {
+			con.Low = net.ParseIP(items[0])
+			con.High = net.ParseIP(items[1])
+			if con.Low == nil || con.High == nil {
+				return nil, fmt.Errorf("transform_table row (%v) should specify a valid Low and High (%v - %v)", ri, items[0], items[1])
+			}
 		}
 		parseList := func(s string) ([]net.IP, error) {
 			ips := []net.IP{}
@@ -71,11 +132,16 @@ func DecodeTransformTable(transforms string) ([]IPConversion, error) {
 			return nil, err
 		}
 
-		low, _ := ipToUint(con.Low)
-		high, _ := ipToUint(con.High)
-		if low > high {
+		low, lowV4, _ := ipToInt(con.Low)
+		high, highV4, _ := ipToInt(con.High)
+		if low.Cmp(high) > 0 {
 			return nil, fmt.Errorf("transform_table Low should be less than High. row (%v) %v>%v (%v)", ri, con.Low, con.High, transforms)
 		}
+		for _, ip := range append(append([]net.IP{con.High}, con.NewBases...), con.NewIPs...) {
+			if _, v4, _ := ipToInt(ip); v4 != lowV4 || highV4 != lowV4 {
+				return nil, fmt.Errorf("transform_table row (%v) mixes IPv4 and IPv6 addresses (%v)", ri, row)
+			}
+		}
 		if len(con.NewBases) > 0 && len(con.NewIPs) > 0 {
 			return nil, fmt.Errorf("transform_table_rows should only specify one of NewBases or NewIPs, Not both")
 		}
@@ -98,31 +164,40 @@ func IP(address net.IP, transforms []IPConversion) (net.IP, error) {
 }
 
 // IPToList manipulates an net.IP based on a list of IPConversions. It can potentially expand one ip address into multiple addresses.
+// Conversions of the other address family (IPv4 or IPv6) are skipped.
 func IPToList(address net.IP, transforms []IPConversion) ([]net.IP, error) {
-	thisIP, err := ipToUint(address)
+	thisIP, v4, err := ipToInt(address)
 	if err != nil {
 		return nil, err
 	}
 	for _, conv := range transforms {
-		min, err := ipToUint(conv.Low)
+		min, minV4, err := ipToInt(conv.Low)
 		if err != nil {
 			return nil, err
 		}
-		max, err := ipToUint(conv.High)
+		max, _, err := ipToInt(conv.High)
 		if err != nil {
 			return nil, err
 		}
-		if (thisIP >= min) && (thisIP <= max) {
+		if minV4 != v4 {
+			continue
+		}
+		if thisIP.Cmp(min) >= 0 && thisIP.Cmp(max) <= 0 {
 			if len(conv.NewIPs) > 0 {
 				return conv.NewIPs, nil
 			}
+			offset := new(big.Int).Sub(thisIP, min)
 			list := []net.IP{}
 			for _, nb := range conv.NewBases {
-				newbase, err := ipToUint(nb)
+				newbase, _, err := ipToInt(nb)
 				if err != nil {
 					return nil, err
 				}
-				list = append(list, UintToIP(newbase+(thisIP-min)))
+				ip := intToIP(newbase.Add(newbase, offset), v4)
+				if ip == nil {
+					return nil, fmt.Errorf("transforming %s with base %s overflows", address, nb)
+				}
+				list = append(list, ip)
 			}
 			return list, nil
 		}
@@ -222,3 +222,72 @@ func Test_IP_NewIP(t *testing.T) {
 		}
 	}
 }
+
+func Test_DecodeTransformTable_CIDR(t *testing.T) {
+	result, err := DecodeTransformTable("10.1.0.0/16 ~ ~ 10.2.0.0 ~ ; 2001:db8::/64 ~ ~ 2001:db8:1:: ~")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 rows, got %v", len(result))
+	}
+	testIP(t, "Low[0]", "10.1.0.0", result[0].Low)
+	testIP(t, "High[0]", "10.1.255.255", result[0].High)
+	testIP(t, "Low[1]", "2001:db8::", result[1].Low)
+	testIP(t, "High[1]", "2001:db8::ffff:ffff:ffff:ffff", result[1].High)
+
+	for _, raw := range []string{
+		"10.1.0.0/16 ~ 10.1.0.5 ~ 10.2.0.0 ~",   // High with a CIDR range
+		"10.1.0.0/33 ~ ~ 10.2.0.0 ~",            // Invalid CIDR
+		"10.1.0.0/16 ~ ~ 2001:db8:1:: ~",        // Mixed families
+		"2001:db8::1 ~ 2001:db8::9 ~ ~ 1.2.3.4", // Mixed families
+	} {
+		if _, err := DecodeTransformTable(raw); err == nil {
+			t.Errorf("expected an error for %q, got none", raw)
+		}
+	}
+}
+
+func Test_IP6(t *testing.T) {
+	transforms, err := DecodeTransformTable("2001:db8::/64 ~ ~ 2001:db8:1::,2001:db8:2::10 ~ ; 10.0.0.0/8 ~ ~ 11.0.0.0 ~ ; 2001:db8:ff:: ~ 2001:db8:ff::ff ~ ~ 2001:db8::53")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		experiment string
+		expected   string
+	}{
+		{"2001:db8::5", "2001:db8:1::5,2001:db8:2::15"},
+		{"2001:db8::1:0:0:5", "2001:db8:1:0:1::5,2001:db8:2:0:1::15"},
+		{"2001:db8:0:1::5", "2001:db8:0:1::5"},
+		{"2001:db8:ff::80", "2001:db8::53"},
+		{"10.9.8.7", "11.9.8.7"},
+		{"::ffff:10.9.8.7", "11.9.8.7"},
+	}
+
+	for _, test := range tests {
+		experiment := net.ParseIP(test.experiment)
+		actual, err := IPToList(experiment, transforms)
+		if err != nil {
+			t.Errorf("%v: got an err: %v\n", experiment, err)
+		}
+		list := []string{}
+		for _, ip := range actual {
+			list = append(list, ip.String())
+		}
+		act := strings.Join(list, ",")
+		if test.expected != act {
+			t.Errorf("%v: expected (%v) got (%v)\n", experiment, test.expected, act)
+		}
+	}
+
+	overflow := []IPConversion{{
+		Low:      net.ParseIP("10.0.0.0"),
+		High:     net.ParseIP("10.255.255.255"),
+		NewBases: []net.IP{net.ParseIP("255.255.255.0")},
+	}}
+	if _, err := IPToList(net.ParseIP("10.0.1.0"), overflow); err == nil {
+		t.Error("expected an overflow error, got none")
+	}
+}
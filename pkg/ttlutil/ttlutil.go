@@ -0,0 +1,63 @@
+// Package ttlutil describes the TTLs that the API of a DNS provider
+// accepts.
+package ttlutil
+
+import (
+	"sort"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Limits are the bounds and steps of the TTLs of a provider.
+// Validation warns about the records whose TTL Fix() changes.
+//
+// Providers whose limits depend on the account or the zone (deSEC,
+// ClouDNS) check the TTLs themselves.
+type Limits struct {
+	// Min and Max are the lowest and highest TTLs. 0 means there is no
+	// limit.
+	Min uint32
+	Max uint32
+	// Step, if set, rounds the TTLs down to a multiple of Step.
+	Step uint32
+	// Allowed, if set, lists the only TTLs the API accepts, in
+	// increasing order. The other TTLs are rounded up to the next one,
+	// or down to the last one.
+	Allowed []uint32
+	// Keep lists the TTLs that have a special meaning for the API, which
+	// are left as they are (e.g. 1, "automatic", at Cloudflare).
+	Keep []uint32
+}
+
+// Fix returns the TTL that the API accepts instead of ttl.
+func (l Limits) Fix(ttl uint32) uint32 {
+	for _, k := range l.Keep {
+		if ttl == k {
+			return ttl
+		}
+	}
+	if l.Min > 0 && ttl < l.Min {
+		ttl = l.Min
+	}
+	if l.Max > 0 && ttl > l.Max {
+		ttl = l.Max
+	}
+	if l.Step > 0 {
+		ttl -= ttl % l.Step
+	}
+	if n := len(l.Allowed); n > 0 {
+		i := sort.Search(n, func(i int) bool { return l.Allowed[i] >= ttl })
+		if i == n {
+			i = n - 1
+		}
+		ttl = l.Allowed[i]
+	}
+	return ttl
+}
+
+// Apply changes the TTLs of the records to those that the API accepts.
+func (l Limits) Apply(records []*models.RecordConfig) {
+	for _, rc := range records {
+		rc.TTL = l.Fix(rc.TTL)
+	}
+}
@@ -0,0 +1,34 @@
+package ttlutil
+
+import "testing"
+
+func TestFix(t *testing.T) {
+	cloudflare := Limits{Min: 120, Keep: []uint32{1}}
+	range60 := Limits{Min: 60, Max: 604800, Step: 60}
+	allowed := Limits{Allowed: []uint32{300, 3600, 86400}}
+	tests := []struct {
+		name   string
+		limits Limits
+		ttl    uint32
+		want   uint32
+	}{
+		{"none", Limits{}, 1, 1},
+		{"min", cloudflare, 60, 120},
+		{"keep", cloudflare, 1, 1},
+		{"above min", cloudflare, 300, 300},
+		{"max", range60, 1000000, 604800},
+		{"step", range60, 3599, 3540},
+		{"step min", range60, 30, 60},
+		{"allowed up", allowed, 301, 3600},
+		{"allowed exact", allowed, 3600, 3600},
+		{"allowed low", allowed, 60, 300},
+		{"allowed high", allowed, 100000, 86400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.Fix(tt.ttl); got != tt.want {
+				t.Errorf("Fix(%d) = %d, want %d", tt.ttl, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package txtutil
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Policy says how many strings a TXT record may have at a provider, and
+// how long they may be; it is a providers.ProviderMetadata.
+type Policy struct {
+	// Multi is true if the API stores each string of a TXT record
+	// separately (providers.CanUseTXTMulti).
+	Multi bool
+	// MaxLength is the length of the longest string the API accepts.
+	// 0 means there is no limit.
+	MaxLength int
+	// AutoSplit splits the strings longer than MaxLength into strings of
+	// MaxLength octets.
+	AutoSplit bool
+	// AutoJoin joins the strings of a TXT record into one, for APIs that
+	// store only one string.
+	AutoJoin bool
+}
+
+// SplitLong is the policy of most providers: TXT records may have
+// several strings, and strings longer than 255 octets are split.
+var SplitLong = Policy{Multi: true, MaxLength: 255, AutoSplit: true}
+
+// Apply rewrites the TXT records (and the records that are formatted
+// like TXT records) the way the API stores them.
+func (p Policy) Apply(records []*models.RecordConfig) {
+	for _, rc := range records {
+		if !rc.HasFormatIdenticalToTXT() {
+			continue
+		}
+		txts := rc.TxtStrings
+		changed := false
+		if p.AutoJoin && !p.Multi && len(txts) > 1 {
+			txts = []string{rc.GetTargetTXTJoined()}
+			changed = true
+		}
+		if p.AutoSplit && p.MaxLength > 0 {
+			var split []string
+			for _, s := range txts {
+				if len(s) > p.MaxLength {
+					split = append(split, splitChunks(s, p.MaxLength)...)
+					changed = true
+				} else {
+					split = append(split, s)
+				}
+			}
+			txts = split
+		}
+		if changed {
+			rc.SetTargetTXTs(txts)
+		}
+	}
+}
+
+// Check returns an error if the API can't store rc, even after Apply().
+func (p Policy) Check(rc *models.RecordConfig) error {
+	if !rc.HasFormatIdenticalToTXT() {
+		return nil
+	}
+	txts := rc.TxtStrings
+	if len(txts) > 1 && !p.Multi {
+		if !p.AutoJoin {
+			return fmt.Errorf("%s has %d strings, but the provider only supports one per TXT record", rc.GetLabelFQDN(), len(txts))
+		}
+		txts = []string{rc.GetTargetTXTJoined()}
+	}
+	if p.MaxLength > 0 && !p.AutoSplit {
+		for _, s := range txts {
+			if len(s) > p.MaxLength {
+				return fmt.Errorf("%s has a string of %d octets, but the provider only supports %d", rc.GetLabelFQDN(), len(s), p.MaxLength)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package txtutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func makeTXT(txts ...string) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: "TXT"}
+	rc.SetLabel("foo", "example.com")
+	rc.SetTargetTXTs(txts)
+	return rc
+}
+
+func TestPolicy(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	tests := []struct {
+		name    string
+		policy  Policy
+		txts    []string
+		want    []string
+		wantErr bool
+	}{
+		{"split", SplitLong, []string{long}, []string{long[:255], long[255:]}, false},
+		{"split each", SplitLong, []string{"x", long}, []string{"x", long[:255], long[255:]}, false},
+		{"multi", SplitLong, []string{"x", "y"}, []string{"x", "y"}, false},
+		{"join", Policy{AutoJoin: true}, []string{"x", "y"}, []string{"xy"}, false},
+		{"join and split", Policy{AutoJoin: true, MaxLength: 255, AutoSplit: true}, []string{long[:200], long[200:]}, []string{long[:255], long[255:]}, false},
+		{"no multi", Policy{}, []string{"x", "y"}, []string{"x", "y"}, true},
+		{"too long", Policy{Multi: true, MaxLength: 255}, []string{long}, []string{long}, true},
+		{"joined too long", Policy{AutoJoin: true, MaxLength: 255}, []string{long[:200], long[200:]}, []string{long}, true},
+		{"no limit", Policy{}, []string{long}, []string{long}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := makeTXT(tt.txts...)
+			if err := tt.policy.Check(rc); (err != nil) != tt.wantErr {
+				t.Errorf("Check() = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.policy.Apply([]*models.RecordConfig{rc})
+			if !reflect.DeepEqual(rc.TxtStrings, tt.want) {
+				t.Errorf("Apply() = %q, want %q", rc.TxtStrings, tt.want)
+			}
+		})
+	}
+}
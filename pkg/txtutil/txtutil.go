@@ -6,6 +6,8 @@ import "github.com/StackExchange/dnscontrol/v3/models"
 // into 255-octet chunks. This is used by providers that, when a user specifies
 // one long TXT string, split it into smaller strings behind the scenes.
 // Typically this replaces the TXTMulti capability.
+//
+// Deprecated: Register a Policy (usually SplitLong) and call its Apply().
 func SplitSingleLongTxt(records []*models.RecordConfig) {
 	for _, rc := range records {
 		if rc.HasFormatIdenticalToTXT() {
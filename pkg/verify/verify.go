@@ -0,0 +1,257 @@
+// Package verify checks that the nameservers of a DNS provider serve
+// the records that push has just changed (push --verify).
+//
+// A DNS provider may accept a change and serve it only later, or not
+// at all on some of its nameservers. After the corrections of a zone
+// have run, the RRsets that they changed are queried, without
+// recursion, on each nameserver of the provider until every one of
+// them serves the new values or the timeout expires.
+package verify
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/miekg/dns"
+)
+
+// Check is an RRset to verify.
+type Check struct {
+	Name string // FQDN, without the final dot
+	Type string
+	// Want are the records that must be served: the RRset after the push.
+	Want []dns.RR
+	// Gone are the records that the push removed, which must no longer
+	// be served.
+	Gone []dns.RR
+}
+
+func (c Check) String() string {
+	return c.Name + " " + c.Type
+}
+
+// types are the record types that can be queried and compared. The
+// others are either pseudo-records, which the nameservers serve in
+// another form (ALIAS, R53_ALIAS...), or change by themselves (SOA).
+// NS records below the apex are delegations, which are answered
+// without authority; they aren't verified either.
+var types = map[string]bool{
+	"A": true, "AAAA": true, "CAA": true, "CNAME": true, "DS": true,
+	"HTTPS": true, "LOC": true, "MX": true, "NAPTR": true, "NS": true,
+	"PTR": true, "SPF": true, "SRV": true, "SSHFP": true, "SVCB": true,
+	"TLSA": true, "TXT": true, "URI": true,
+}
+
+// Changes returns the RRsets whose records differ between existing (the
+// zone before the push) and desired, sorted by name and type. TTLs
+// aren't compared. If removals is false, Gone is left empty: the
+// records of the zone that the push didn't remove on purpose (see
+// IGNORE) would otherwise be reported.
+func Changes(existing, desired models.Records, removals bool) []Check {
+	before := rrsets(existing)
+	after := rrsets(desired)
+	keys := map[models.RecordKey]bool{}
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+
+	var checks []Check
+	for k := range keys {
+		want := after[k]
+		gone := subtract(before[k], want)
+		if len(gone) == 0 && len(subtract(want, before[k])) == 0 {
+			continue
+		}
+		if !removals {
+			if len(want) == 0 {
+				continue
+			}
+			gone = nil
+		}
+		checks = append(checks, Check{Name: k.NameFQDN, Type: k.Type, Want: want, Gone: gone})
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		if checks[i].Name != checks[j].Name {
+			return checks[i].Name < checks[j].Name
+		}
+		return checks[i].Type < checks[j].Type
+	})
+	return checks
+}
+
+func rrsets(recs models.Records) map[models.RecordKey][]dns.RR {
+	sets := map[models.RecordKey][]dns.RR{}
+	for _, rc := range recs {
+		if !types[rc.Type] || rc.R53Alias != nil || (rc.Type == "NS" && rc.GetLabel() != "@") {
+			continue
+		}
+		k := models.RecordKey{NameFQDN: strings.ToLower(rc.NameFQDN), Type: rc.Type}
+		sets[k] = append(sets[k], rc.ToRR())
+	}
+	return sets
+}
+
+// subtract returns the records of a that aren't in b.
+func subtract(a, b []dns.RR) []dns.RR {
+	var diff []dns.RR
+	for _, rr := range a {
+		if !contains(b, rr) {
+			diff = append(diff, rr)
+		}
+	}
+	return diff
+}
+
+// contains tells whether rrs has the data of rr, whatever its TTL.
+func contains(rrs []dns.RR, rr dns.RR) bool {
+	for _, r := range rrs {
+		if dns.IsDuplicate(r, rr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sample returns at most n of checks (all of them if n is 0), spread
+// over the list so that a large change isn't verified on its first
+// names only.
+func Sample(checks []Check, n int) []Check {
+	if n <= 0 || len(checks) <= n {
+		return checks
+	}
+	sample := make([]Check, n)
+	for i := range sample {
+		sample[i] = checks[i*len(checks)/n]
+	}
+	return sample
+}
+
+// QueryFunc returns the records of type rtype at name that server
+// answers with authority.
+type QueryFunc func(server, name string, rtype uint16) ([]dns.RR, error)
+
+// Options are the knobs of Run.
+type Options struct {
+	// Timeout is how long to wait for all the nameservers to serve the
+	// records. They are queried once if it is 0.
+	Timeout time.Duration
+	// Interval is the time between two rounds of queries.
+	Interval time.Duration
+	// Query is Query if nil.
+	Query QueryFunc
+}
+
+// Run queries each check on each server, again every opts.Interval,
+// until they all serve the expected records or opts.Timeout expires.
+// It returns a message for each check that a server still doesn't
+// serve; the result is empty if all of them do.
+func Run(servers []string, checks []Check, opts Options) []string {
+	query := opts.Query
+	if query == nil {
+		query = Query
+	}
+	deadline := time.Now().Add(opts.Timeout)
+
+	type pending struct {
+		check  int // index in checks
+		server string
+	}
+	var todo []pending
+	for i := range checks {
+		for _, s := range servers {
+			todo = append(todo, pending{i, s})
+		}
+	}
+	failures := map[pending]string{}
+	for {
+		var left []pending
+		for _, p := range todo {
+			if msg := verify(query, p.server, checks[p.check]); msg != "" {
+				failures[p] = msg
+				left = append(left, p)
+			}
+		}
+		todo = left
+		if len(todo) == 0 || !time.Now().Add(opts.Interval).Before(deadline) {
+			break
+		}
+		time.Sleep(opts.Interval)
+	}
+
+	var msgs []string
+	for _, p := range todo {
+		msgs = append(msgs, fmt.Sprintf("%s at %s: %s", checks[p.check], p.server, failures[p]))
+	}
+	return msgs
+}
+
+// verify returns why server doesn't serve c, or "" if it does.
+func verify(query QueryFunc, server string, c Check) string {
+	served, err := query(server, c.Name, dns.StringToType[c.Type])
+	if err != nil {
+		return err.Error()
+	}
+	var missing, stale []string
+	for _, rr := range c.Want {
+		if !contains(served, rr) {
+			missing = append(missing, rdata(rr))
+		}
+	}
+	for _, rr := range c.Gone {
+		if contains(served, rr) {
+			stale = append(stale, rdata(rr))
+		}
+	}
+	var msgs []string
+	if len(missing) != 0 {
+		msgs = append(msgs, "missing "+strings.Join(missing, ", "))
+	}
+	if len(stale) != 0 {
+		msgs = append(msgs, "still serving "+strings.Join(stale, ", "))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// rdata returns the data of rr in zone file format.
+func rdata(rr dns.RR) string {
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
+
+// dnsTimeout is the timeout of each query.
+const dnsTimeout = 5 * time.Second
+
+// Query is a QueryFunc that sends the query to port 53 of server, over
+// UDP, or TCP if the answer is truncated.
+func Query(server, name string, rtype uint16) ([]dns.RR, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), rtype)
+	msg.RecursionDesired = false
+	addr := net.JoinHostPort(server, "53")
+	resp, _, err := (&dns.Client{Timeout: dnsTimeout}).Exchange(msg, addr)
+	if err == nil && resp.Truncated {
+		resp, _, err = (&dns.Client{Net: "tcp", Timeout: dnsTimeout}).Exchange(msg, addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError:
+		return nil, fmt.Errorf("answered %s", dns.RcodeToString[resp.Rcode])
+	case !resp.Authoritative:
+		return nil, fmt.Errorf("is not authoritative for %s", name)
+	}
+	var rrs []dns.RR
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype == rtype && strings.EqualFold(rr.Header().Name, dns.Fqdn(name)) {
+			rrs = append(rrs, rr)
+		}
+	}
+	return rrs, nil
+}
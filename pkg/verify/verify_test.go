@@ -0,0 +1,108 @@
+package verify
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/miekg/dns"
+)
+
+func makeRec(label, rtype, target string) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: rtype, TTL: 300}
+	rc.SetLabel(label, "example.com")
+	rc.SetTarget(target)
+	return rc
+}
+
+func names(checks []Check) []string {
+	var s []string
+	for _, c := range checks {
+		s = append(s, fmt.Sprintf("%s %d/%d", c, len(c.Want), len(c.Gone)))
+	}
+	return s
+}
+
+func TestChanges(t *testing.T) {
+	existing := models.Records{
+		makeRec("same", "A", "1.2.3.4"),
+		makeRec("www", "A", "1.2.3.4"),
+		makeRec("old", "CNAME", "www.example.com."),
+		makeRec("@", "MX", "mx.example.com."),
+	}
+	existing[3].MxPreference = 10
+	desired := models.Records{
+		makeRec("same", "A", "1.2.3.4"),
+		makeRec("www", "A", "5.6.7.8"),
+		makeRec("new", "TXT", "hello"),
+		makeRec("@", "MX", "mx.example.com."),
+		makeRec("@", "ALIAS", "elsewhere.example.net."),
+	}
+	desired[3].MxPreference = 20
+	desired[0].TTL = 600 // TTLs aren't compared.
+
+	got := names(Changes(existing, desired, true))
+	want := []string{"example.com MX 1/1", "new.example.com TXT 1/0", "old.example.com CNAME 0/1", "www.example.com A 1/1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("with removals: got %q, want %q", got, want)
+	}
+	got = names(Changes(existing, desired, false))
+	want = []string{"example.com MX 1/0", "new.example.com TXT 1/0", "www.example.com A 1/0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("without removals: got %q, want %q", got, want)
+	}
+}
+
+func TestSample(t *testing.T) {
+	var checks []Check
+	for i := 0; i < 10; i++ {
+		checks = append(checks, Check{Name: fmt.Sprint(i), Type: "A"})
+	}
+	got := names(Sample(checks, 3))
+	want := []string{"0 A 0/0", "3 A 0/0", "6 A 0/0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := Sample(checks, 0); len(got) != 10 {
+		t.Errorf("got %d checks without a limit, want 10", len(got))
+	}
+}
+
+func TestRun(t *testing.T) {
+	existing := models.Records{makeRec("www", "A", "1.2.3.4")}
+	desired := models.Records{makeRec("www", "A", "5.6.7.8")}
+	checks := Changes(existing, desired, true)
+
+	// ns1 serves the new address; ns2 on its third query only.
+	queries := map[string]int{}
+	query := func(server, name string, rtype uint16) ([]dns.RR, error) {
+		queries[server]++
+		if server == "ns2" && queries[server] < 3 {
+			return []dns.RR{existing[0].ToRR()}, nil
+		}
+		if server == "ns3" {
+			return nil, fmt.Errorf("i/o timeout")
+		}
+		return []dns.RR{desired[0].ToRR()}, nil
+	}
+
+	got := Run([]string{"ns1", "ns2"}, checks, Options{Timeout: time.Second, Interval: time.Millisecond, Query: query})
+	if len(got) != 0 {
+		t.Errorf("got %q, want no failures", got)
+	}
+	if queries["ns1"] != 1 || queries["ns2"] != 3 {
+		t.Errorf("got %v queries, want 1 to ns1 and 3 to ns2", queries)
+	}
+
+	queries = map[string]int{}
+	got = Run([]string{"ns2", "ns3"}, checks, Options{Query: query})
+	want := []string{
+		"www.example.com A at ns2: missing 5.6.7.8; still serving 1.2.3.4",
+		"www.example.com A at ns3: i/o timeout",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
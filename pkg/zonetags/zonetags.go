@@ -0,0 +1,95 @@
+// Package zonetags manages the tags (labels) of the hosted zone of a
+// domain, for example to enforce cost-allocation tagging, from the
+// metadata of D():
+//
+//	D("example.com", REG, DnsProvider(DSP), ZONE_TAGS({team: "dns", env: "prod"}), ...)
+//
+// ZONE_TAGS() stores the tags as a JSON object in the zone_tags
+// metadata. DNS providers that can manage the tags of their zones
+// implement providers.ZoneTagCorrector. They report the current tags
+// and a function that changes them to Corrections, which compares them
+// with the metadata.
+package zonetags
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Key is the metadata key. When it isn't set, the tags are left alone;
+// when it is, the zone has exactly these tags.
+const Key = "zone_tags"
+
+// Desired returns the tags requested by the metadata of dc, or nil if
+// the metadata doesn't set them.
+func Desired(dc *models.DomainConfig) (map[string]string, error) {
+	v, ok := dc.Metadata[Key]
+	if !ok {
+		return nil, nil
+	}
+	tags := map[string]string{}
+	if err := json.Unmarshal([]byte(v), &tags); err != nil {
+		return nil, fmt.Errorf("bad metadata value for %s: '%s'. Use a JSON object of strings", Key, v)
+	}
+	for k := range tags {
+		if strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("bad metadata value for %s: empty tag key", Key)
+		}
+	}
+	return tags, nil
+}
+
+// Corrections returns the correction that changes the tags in current
+// to the ones requested by the metadata of dc. set adds (or changes)
+// the tags in add and removes the tags in remove. Providers leave the
+// tags that they reserve for themselves out of current.
+func Corrections(dc *models.DomainConfig, current map[string]string, set func(add map[string]string, remove []string) error) ([]*models.Correction, error) {
+	desired, err := Desired(dc)
+	if err != nil || desired == nil {
+		return nil, err
+	}
+	var keys []string
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	for k := range current {
+		if _, ok := desired[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	add := map[string]string{}
+	var remove, changes []string
+	var kind models.CorrectionKind
+	for _, k := range keys {
+		want, wanted := desired[k]
+		have, had := current[k]
+		switch {
+		case !wanted:
+			remove = append(remove, k)
+			changes = append(changes, fmt.Sprintf("remove %s", k))
+			kind = kind.Max(models.CorrectionDestroy)
+		case !had:
+			add[k] = want
+			changes = append(changes, fmt.Sprintf("add %s=%q", k, want))
+			kind = kind.Max(models.CorrectionCreate)
+		case have != want:
+			add[k] = want
+			changes = append(changes, fmt.Sprintf("change %s=%q to %q", k, have, want))
+			kind = kind.Max(models.CorrectionModify)
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return []*models.Correction{{
+		Msg:  "Update zone tags: " + strings.Join(changes, ", "),
+		Kind: kind,
+		F:    func() error { return set(add, remove) },
+	}}, nil
+}
@@ -0,0 +1,60 @@
+package zonetags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestCorrections(t *testing.T) {
+	dc := &models.DomainConfig{Name: "example.com", Metadata: map[string]string{
+		Key: `{"env":"prod","team":"dns","owner":"ops"}`,
+	}}
+	current := map[string]string{"env": "test", "owner": "ops", "old": "x"}
+	var add map[string]string
+	var remove []string
+	corrections, err := Corrections(dc, current, func(a map[string]string, r []string) error {
+		add, remove = a, r
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Update zone tags: change env="test" to "prod", remove old, add team="dns"`
+	if len(corrections) != 1 || corrections[0].Msg != want {
+		t.Fatalf("got corrections %v, want %q", corrections, want)
+	}
+	if err := corrections[0].F(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(add, map[string]string{"env": "prod", "team": "dns"}) || !reflect.DeepEqual(remove, []string{"old"}) {
+		t.Errorf("got add %v, remove %v", add, remove)
+	}
+
+	// The tags are already right.
+	corrections, err = Corrections(dc, map[string]string{"env": "prod", "team": "dns", "owner": "ops"}, nil)
+	if err != nil || len(corrections) != 0 {
+		t.Errorf("got %v, %v", corrections, err)
+	}
+
+	// An empty object removes all the tags.
+	dc.Metadata[Key] = `{}`
+	corrections, err = Corrections(dc, current, nil)
+	if err != nil || len(corrections) != 1 || corrections[0].Msg != "Update zone tags: remove env, remove old, remove owner" {
+		t.Errorf("got %v, %v", corrections, err)
+	}
+
+	for _, bad := range []string{`env=prod`, `{"env":1}`, `{"":"x"}`} {
+		dc.Metadata[Key] = bad
+		if _, err := Desired(dc); err == nil {
+			t.Errorf("expected an error for %s, got none", bad)
+		}
+	}
+
+	// Nothing requested.
+	corrections, err = Corrections(&models.DomainConfig{Name: "example.com"}, current, nil)
+	if err != nil || len(corrections) != 0 {
+		t.Errorf("got %v, %v", corrections, err)
+	}
+}
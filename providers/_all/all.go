@@ -7,9 +7,11 @@ import (
 	_ "github.com/StackExchange/dnscontrol/v3/providers/autodns"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/axfrddns"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/azuredns"
+	_ "github.com/StackExchange/dnscontrol/v3/providers/azureprivatedns"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/bind"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/cloudflare"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/cloudns"
+	_ "github.com/StackExchange/dnscontrol/v3/providers/constellix"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/cscglobal"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/desec"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/digitalocean"
@@ -39,6 +41,7 @@ import (
 	_ "github.com/StackExchange/dnscontrol/v3/providers/oracle"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/ovh"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/packetframe"
+	_ "github.com/StackExchange/dnscontrol/v3/providers/plugin"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/porkbun"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/powerdns"
 	_ "github.com/StackExchange/dnscontrol/v3/providers/route53"
@@ -54,7 +54,7 @@ func init() {
 		Initializer:   newEdgeDNSDSP,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("AKAMAIEDGEDNS", fns, features)
+	providers.RegisterDomainServiceProviderType("AKAMAIEDGEDNS", fns, features, txtutil.SplitLong)
 	providers.RegisterCustomRecordType("AKAMAICDN", "AKAMAIEDGEDNS", "")
 }
 
@@ -120,7 +120,7 @@ func (a *edgeDNSProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 	}
 
 	models.PostProcessRecords(existingRecords)
-	txtutil.SplitSingleLongTxt(dc.Records)
+	txtutil.SplitLong.Apply(dc.Records)
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 || true { // Remove "|| true" when diff2 version arrives
@@ -153,7 +153,8 @@ func (a *edgeDNSProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 			if okExisting && !okDesired {
 				// In the existing map but not in the desired map: Delete
 				corrections = append(corrections, &models.Correction{
-					Msg: strings.Join(msg, "\n   "),
+					Msg:  strings.Join(msg, "\n   "),
+					Kind: models.CorrectionDestroy,
 					F: func() error {
 						return deleteRecordset(existing, dc.Name)
 					},
@@ -165,7 +166,8 @@ func (a *edgeDNSProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 			} else if !okExisting && okDesired {
 				// Not in the existing map but in the desired map: Create
 				lastCorrections = append(lastCorrections, &models.Correction{
-					Msg: strings.Join(msg, "\n   "),
+					Msg:  strings.Join(msg, "\n   "),
+					Kind: models.CorrectionCreate,
 					F: func() error {
 						return createRecordset(desired, dc.Name)
 					},
@@ -177,7 +179,8 @@ func (a *edgeDNSProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 			} else if okExisting && okDesired {
 				// In the existing map and in the desired map: Replace
 				lastCorrections = append(lastCorrections, &models.Correction{
-					Msg: strings.Join(msg, "\n   "),
+					Msg:  strings.Join(msg, "\n   "),
+					Kind: diff.ReplaceKind(existing, desired),
 					F: func() error {
 						return replaceRecordset(desired, dc.Name)
 					},
@@ -203,7 +206,8 @@ func (a *edgeDNSProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		if !existingAutoDNSSecEnabled && desiredAutoDNSSecEnabled {
 			// Existing false (disabled), Desired true (enabled)
 			corrections = append(corrections, &models.Correction{
-				Msg: "Enable AutoDnsSec\n",
+				Msg:  "Enable AutoDnsSec\n",
+				Kind: models.CorrectionCreate,
 				F: func() error {
 					return autoDNSSecEnable(true, dc.Name)
 				},
@@ -212,7 +216,8 @@ func (a *edgeDNSProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		} else if existingAutoDNSSecEnabled && !desiredAutoDNSSecEnabled {
 			// Existing true (enabled), Desired false (disabled)
 			corrections = append(corrections, &models.Correction{
-				Msg: "Disable AutoDnsSec\n",
+				Msg:  "Disable AutoDnsSec\n",
+				Kind: models.CorrectionDestroy,
 				F: func() error {
 					return autoDNSSecEnable(false, dc.Name)
 				},
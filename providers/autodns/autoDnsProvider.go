@@ -41,7 +41,7 @@ func init() {
 		Initializer:   New,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("AUTODNS", fns, features)
+	providers.RegisterDomainServiceProviderType("AUTODNS", fns, features, txtutil.SplitLong)
 }
 
 // New creates a new API handle.
@@ -90,7 +90,7 @@ func (api *autoDNSProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 
 	// Normalize
 	models.PostProcessRecords(existingRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 || true { // Remove "|| true" when diff2 version arrives
@@ -124,7 +124,8 @@ func (api *autoDNSProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 		if len(create) > 0 || len(del) > 0 || len(modify) > 0 {
 			corrections = append(corrections,
 				&models.Correction{
-					Msg: "Zone update for " + domain,
+					Msg:  "Zone update for " + domain,
+					Kind: diff.Kind(create, del, modify),
 					F: func() error {
 						zoneTTL := uint32(0)
 						nameServers := []*models.Nameserver{}
@@ -26,6 +26,7 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/metaschema"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
 	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
@@ -44,6 +45,7 @@ var features = providers.DocumentationNotes{
 	providers.CanUseCAA:              providers.Can(),
 	providers.CanUseNAPTR:            providers.Can(),
 	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseRFC3597:          providers.Can(),
 	providers.CanUseSRV:              providers.Can(),
 	providers.CanUseSSHFP:            providers.Can(),
 	providers.CanUseTLSA:             providers.Can(),
@@ -131,6 +133,7 @@ func initAxfrDdns(config map[string]string, providermeta json.RawMessage) (provi
 	if err != nil {
 		return nil, err
 	}
+	setupGSSTSIG(api.master, config, api.updateKey, api.transferKey)
 	for key := range config {
 		switch key {
 		case "master",
@@ -138,7 +141,11 @@ func initAxfrDdns(config map[string]string, providermeta json.RawMessage) (provi
 			"update-key",
 			"transfer-key",
 			"update-mode",
-			"transfer-mode":
+			"transfer-mode",
+			"gss-spn",
+			"gss-username",
+			"gss-password",
+			"gss-domain":
 			continue
 		default:
 			printer.Printf("[Warning] AXFRDDNS: unknown key in `creds.json` (%s)\n", key)
@@ -152,7 +159,7 @@ func init() {
 		Initializer:   initAxfrDdns,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("AXFRDDNS", fns, features)
+	providers.RegisterDomainServiceProviderType("AXFRDDNS", fns, features, txtutil.SplitLong, metaSchema)
 }
 
 // Param is used to decode extra parameters sent to provider.
@@ -160,17 +167,29 @@ type Param struct {
 	DefaultNS []string `json:"default_ns"`
 }
 
+// metaSchema lists the fields of Param.
+var metaSchema = metaschema.Schema{
+	"default_ns": metaschema.StringList,
+}
+
 // Key stores the individual parts of a TSIG key.
 type Key struct {
 	algo   string
 	id     string
 	secret string
+	gss    *gssTSIG // Only for GSS-TSIG, which has no id or secret.
 }
 
 func readKey(raw string, kind string) (*Key, error) {
 	if raw == "" {
 		return nil, nil
 	}
+	if raw == "gss-tsig" {
+		if !gssSupported {
+			return nil, fmt.Errorf("gss-tsig (%s) is only supported when DNSControl runs on Windows", kind)
+		}
+		return &Key{algo: gssTsigAlgorithm}, nil
+	}
 	arr := strings.Split(raw, ":")
 	if len(arr) != 3 {
 		return nil, fmt.Errorf("invalid key format (%s) in AXFRDDNS.TSIG", kind)
@@ -195,6 +214,68 @@ func readKey(raw string, kind string) (*Key, error) {
 	return &Key{algo: algo, id: arr[1] + ".", secret: arr[2]}, nil
 }
 
+// setupGSSTSIG prepares the keys that use GSS-TSIG, which are
+// negotiated with server. If several keys use GSS-TSIG, they share one
+// security context.
+func setupGSSTSIG(server string, config map[string]string, keys ...*Key) {
+	var gss *gssTSIG
+	for _, key := range keys {
+		if key != nil && key.algo == gssTsigAlgorithm {
+			if gss == nil {
+				gss = newGSSTSIG(server, config)
+			}
+			key.gss = gss
+		}
+	}
+}
+
+// sign adds a TSIG record to msg, and returns the secrets and the
+// TsigProvider (if any) that the client must use.
+func (key *Key) sign(msg *dns.Msg) (map[string]string, dns.TsigProvider, error) {
+	if key.gss != nil {
+		name, err := key.gss.keyName()
+		if err != nil {
+			return nil, nil, err
+		}
+		msg.SetTsig(name, gssTsigAlgorithm, 300, time.Now().Unix())
+		return nil, key.gss, nil
+	}
+	msg.SetTsig(key.id, key.algo, 300, time.Now().Unix())
+	var provider dns.TsigProvider
+	if key.algo == dns.HmacMD5 {
+		provider = md5Provider(key.secret)
+	}
+	return map[string]string{key.id: key.secret}, provider, nil
+}
+
+// sendUpdate sends a DDNS update (RFC2136) to server ("host:port"),
+// over mode ("" for UDP, "tcp" or "tcp-tls"), signed with key if it
+// isn't nil. pType is the provider type, for the error messages.
+func sendUpdate(pType string, update *dns.Msg, server string, mode string, key *Key) error {
+	client := new(dns.Client)
+	client.Net = mode
+	client.Timeout = dnsTimeout
+	if key != nil {
+		var err error
+		client.TsigSecret, client.TsigProvider, err = key.sign(update)
+		if err != nil {
+			return err
+		}
+	}
+
+	msg, _, err := client.Exchange(update, server)
+	if err != nil {
+		return err
+	}
+	if msg.MsgHdr.Rcode != 0 {
+		return fmt.Errorf("[Error] %s: nameserver refused to update the zone: %s (%d)",
+			pType,
+			dns.RcodeToString[msg.MsgHdr.Rcode],
+			msg.MsgHdr.Rcode)
+	}
+	return nil
+}
+
 // GetNameservers returns the nameservers for a domain.
 func (c *axfrddnsProvider) GetNameservers(domain string) ([]*models.Nameserver, error) {
 	return c.nameservers, nil
@@ -229,11 +310,9 @@ func (c *axfrddnsProvider) FetchZoneRecords(domain string) ([]dns.RR, error) {
 	request.SetAxfr(domain + ".")
 
 	if c.transferKey != nil {
-		transfer.TsigSecret =
-			map[string]string{c.transferKey.id: c.transferKey.secret}
-		request.SetTsig(c.transferKey.id, c.transferKey.algo, 300, time.Now().Unix())
-		if c.transferKey.algo == dns.HmacMD5 {
-			transfer.TsigProvider = md5Provider(c.transferKey.secret)
+		transfer.TsigSecret, transfer.TsigProvider, err = c.transferKey.sign(request)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -348,7 +427,7 @@ func (c *axfrddnsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 
 	// Normalize
 	models.PostProcessRecords(foundRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	var create, del, mod diff.Changeset
@@ -384,7 +463,8 @@ func (c *axfrddnsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 
 		corrections = append(corrections,
 			&models.Correction{
-				Msg: msg,
+				Msg:  msg,
+				Kind: diff.Kind(create, del, mod),
 				F: func() error {
 
 					// An RFC2136-compliant server must silently ignore an
@@ -428,29 +508,7 @@ func (c *axfrddnsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 						}
 					}
 
-					client := new(dns.Client)
-					client.Net = c.updateMode
-					client.Timeout = dnsTimeout
-					if c.updateKey != nil {
-						client.TsigSecret =
-							map[string]string{c.updateKey.id: c.updateKey.secret}
-						update.SetTsig(c.updateKey.id, c.updateKey.algo, 300, time.Now().Unix())
-						if c.updateKey.algo == dns.HmacMD5 {
-							client.TsigProvider = md5Provider(c.updateKey.secret)
-						}
-					}
-
-					msg, _, err := client.Exchange(update, c.master)
-					if err != nil {
-						return err
-					}
-					if msg.MsgHdr.Rcode != 0 {
-						return fmt.Errorf("[Error] AXFRDDNS: nameserver refused to update the zone: %s (%d)",
-							dns.RcodeToString[msg.MsgHdr.Rcode],
-							msg.MsgHdr.Rcode)
-					}
-
-					return nil
+					return sendUpdate("AXFRDDNS", update, c.master, c.updateMode, c.updateKey)
 				},
 			})
 	}
@@ -0,0 +1,219 @@
+package axfrddns
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// GSS-TSIG (RFC 3645) signs messages with a security context that is
+// negotiated with the server (with Kerberos, in practice), rather than
+// with a shared secret. It is what Active Directory-integrated DNS
+// zones use for "secure only" dynamic updates.
+
+// gssTsigAlgorithm is the TSIG algorithm name of GSS-TSIG.
+const gssTsigAlgorithm = "gss-tsig."
+
+// gssRenewBefore is how long before a security context expires that a
+// new one is negotiated.
+const gssRenewBefore = 5 * time.Minute
+
+// gssContext is a GSS-API security context on the client side. There
+// is one implementation per platform; see newGSSContext.
+type gssContext interface {
+	// step processes the token received from the server (nil on the
+	// first call), and returns the token to send to the server (if
+	// any) and whether the context is established.
+	step(input []byte) (output []byte, done bool, err error)
+	// sign returns the MIC of msg.
+	sign(msg []byte) ([]byte, error)
+	// verify checks the MIC of msg.
+	verify(msg, mic []byte) error
+	// close releases the context.
+	close() error
+}
+
+// gssCredentials are explicit credentials for GSS-TSIG. If they are
+// empty, the credentials of the current user are used.
+type gssCredentials struct {
+	username string
+	password string
+	domain   string
+}
+
+// gssTSIG negotiates GSS-TSIG keys with a server and signs messages
+// with them. It implements dns.TsigProvider.
+type gssTSIG struct {
+	server     string // host:port
+	spn        string // The service principal name of the server.
+	creds      gssCredentials
+	newContext func(spn string, creds gssCredentials) (gssContext, error)
+
+	mu      sync.Mutex
+	ctx     gssContext
+	name    string // The TSIG key name.
+	expires time.Time
+}
+
+func newGSSTSIG(server string, config map[string]string) *gssTSIG {
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		host = server
+	}
+	spn := config["gss-spn"]
+	if spn == "" {
+		spn = "DNS/" + strings.TrimSuffix(host, ".")
+	}
+	return &gssTSIG{
+		server: server,
+		spn:    spn,
+		creds: gssCredentials{
+			username: config["gss-username"],
+			password: config["gss-password"],
+			domain:   config["gss-domain"],
+		},
+		newContext: newGSSContext,
+	}
+}
+
+// keyName returns the name of a key that is valid for at least
+// gssRenewBefore, negotiating a new one if needed.
+func (g *gssTSIG) keyName() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.ctx != nil && time.Now().Add(gssRenewBefore).Before(g.expires) {
+		return g.name, nil
+	}
+	if g.ctx != nil {
+		g.ctx.close()
+		g.ctx = nil
+	}
+	if err := g.negotiate(); err != nil {
+		return "", fmt.Errorf("[Error] AXFRDDNS: GSS-TSIG negotiation with %s (%s) failed: %w", g.server, g.spn, err)
+	}
+	return g.name, nil
+}
+
+// negotiate establishes a security context with the server, by
+// exchanging GSS-API tokens in TKEY queries (RFC 3645 section 3.1).
+func (g *gssTSIG) negotiate() error {
+	ctx, err := g.newContext(g.spn, g.creds)
+	if err != nil {
+		return err
+	}
+	host, _, err := net.SplitHostPort(g.server)
+	if err != nil {
+		host = g.server
+	}
+	name := dns.Fqdn(fmt.Sprintf("%d.sig-%s", rand.Uint32(), strings.TrimSuffix(host, ".")))
+
+	var input []byte
+	var expires time.Time
+	for {
+		output, done, err := ctx.step(input)
+		if err != nil {
+			ctx.close()
+			return err
+		}
+		if len(output) != 0 {
+			tkey, err := g.exchangeTKEY(name, output)
+			if err != nil {
+				ctx.close()
+				return err
+			}
+			expires = time.Unix(int64(tkey.Expiration), 0)
+			input, err = hex.DecodeString(tkey.Key)
+			if err != nil {
+				ctx.close()
+				return err
+			}
+		}
+		if done {
+			break
+		}
+		if len(output) == 0 {
+			ctx.close()
+			return fmt.Errorf("GSS-API context not established, but there is no token to send")
+		}
+	}
+
+	g.ctx, g.name, g.expires = ctx, name, expires
+	return nil
+}
+
+// exchangeTKEY sends a TKEY query with a GSS-API token and returns
+// the server's TKEY.
+func (g *gssTSIG) exchangeTKEY(name string, token []byte) (*dns.TKEY, error) {
+	now := time.Now()
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, dns.TypeTKEY)
+	msg.Question[0].Qclass = dns.ClassANY
+	msg.RecursionDesired = false
+	msg.Extra = append(msg.Extra, &dns.TKEY{
+		Hdr:        dns.RR_Header{Name: name, Rrtype: dns.TypeTKEY, Class: dns.ClassANY},
+		Algorithm:  gssTsigAlgorithm,
+		Mode:       3, // GSS-API negotiation
+		Inception:  uint32(now.Unix()),
+		Expiration: uint32(now.Add(time.Hour).Unix()),
+		KeySize:    uint16(len(token)),
+		Key:        hex.EncodeToString(token),
+	})
+
+	client := &dns.Client{Net: "tcp", Timeout: dnsTimeout}
+	resp, _, err := client.Exchange(msg, g.server)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("TKEY query failed: %s", dns.RcodeToString[resp.Rcode])
+	}
+	for _, rr := range resp.Answer {
+		if tkey, ok := rr.(*dns.TKEY); ok {
+			if tkey.Error != dns.RcodeSuccess {
+				return nil, fmt.Errorf("TKEY query failed: %s", tkeyErrorString(tkey.Error))
+			}
+			return tkey, nil
+		}
+	}
+	return nil, fmt.Errorf("no TKEY in the answer to the TKEY query")
+}
+
+func tkeyErrorString(code uint16) string {
+	if s, ok := dns.RcodeToString[int(code)]; ok {
+		return s
+	}
+	return fmt.Sprintf("error %d", code)
+}
+
+// Generate implements dns.TsigProvider.
+func (g *gssTSIG) Generate(msg []byte, _ *dns.TSIG) ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.ctx == nil {
+		return nil, fmt.Errorf("no GSS-TSIG context")
+	}
+	return g.ctx.sign(msg)
+}
+
+// Verify implements dns.TsigProvider.
+func (g *gssTSIG) Verify(msg []byte, t *dns.TSIG) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.ctx == nil {
+		return fmt.Errorf("no GSS-TSIG context")
+	}
+	mic, err := hex.DecodeString(t.MAC)
+	if err != nil {
+		return err
+	}
+	if err := g.ctx.verify(msg, mic); err != nil {
+		return dns.ErrSig
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !windows
+
+package axfrddns
+
+import "fmt"
+
+// gssSupported tells whether GSS-TSIG is available on this platform.
+const gssSupported = false
+
+func newGSSContext(spn string, creds gssCredentials) (gssContext, error) {
+	return nil, fmt.Errorf("GSS-TSIG is only supported on Windows")
+}
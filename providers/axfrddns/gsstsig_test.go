@@ -0,0 +1,162 @@
+package axfrddns
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// fakeGSS is a gssContext that needs two round trips to be
+// established, and signs with HMAC-SHA256.
+type fakeGSS struct {
+	steps int
+}
+
+var fakeGSSKey = []byte("fake session key")
+
+func (f *fakeGSS) step(input []byte) ([]byte, bool, error) {
+	f.steps++
+	switch f.steps {
+	case 1:
+		return []byte("hello"), false, nil
+	case 2:
+		if string(input) != "hello yourself" {
+			return nil, false, fmt.Errorf("unexpected token %q", input)
+		}
+		return []byte("done"), false, nil
+	default:
+		return nil, string(input) == "welcome", nil
+	}
+}
+
+func (f *fakeGSS) sign(msg []byte) ([]byte, error) {
+	h := hmac.New(sha256.New, fakeGSSKey)
+	h.Write(msg)
+	return h.Sum(nil), nil
+}
+
+func (f *fakeGSS) verify(msg, mic []byte) error {
+	want, _ := f.sign(msg)
+	if !bytes.Equal(want, mic) {
+		return fmt.Errorf("bad MIC")
+	}
+	return nil
+}
+
+func (f *fakeGSS) close() error { return nil }
+
+// fakeGSSServer answers TKEY queries like a server that speaks
+// fakeGSS, and accepts updates signed with the negotiated key.
+func fakeGSSServer(t *testing.T) (addr string, updates chan string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates = make(chan string, 10)
+	serverCtx := &gssTSIG{ctx: &fakeGSS{}}
+	mux := dns.NewServeMux()
+	mux.HandleFunc(".", func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		switch {
+		case len(req.Question) == 1 && req.Question[0].Qtype == dns.TypeTKEY:
+			in := req.Extra[0].(*dns.TKEY)
+			token, _ := hex.DecodeString(in.Key)
+			reply := map[string]string{"hello": "hello yourself", "done": "welcome"}[string(token)]
+			out := *in
+			out.Key = hex.EncodeToString([]byte(reply))
+			out.KeySize = uint16(len(reply))
+			resp.Answer = append(resp.Answer, &out)
+		case req.Opcode == dns.OpcodeUpdate:
+			if w.TsigStatus() != nil {
+				resp.Rcode = dns.RcodeNotAuth
+			} else {
+				updates <- req.IsTsig().Hdr.Name
+			}
+		}
+		w.WriteMsg(resp)
+	})
+	srv := &dns.Server{
+		Listener:      l,
+		Handler:       mux,
+		TsigProvider:  serverCtx,
+		MsgAcceptFunc: func(dns.Header) dns.MsgAcceptAction { return dns.MsgAccept },
+	}
+	go srv.ActivateAndServe()
+	t.Cleanup(func() { srv.Shutdown() })
+	return l.Addr().String(), updates
+}
+
+func TestGSSTSIG(t *testing.T) {
+	addr, updates := fakeGSSServer(t)
+	g := newGSSTSIG(addr, map[string]string{})
+	g.newContext = func(spn string, creds gssCredentials) (gssContext, error) {
+		if spn != "DNS/127.0.0.1" {
+			t.Errorf("spn = %q", spn)
+		}
+		return &fakeGSS{}, nil
+	}
+	key := &Key{algo: gssTsigAlgorithm, gss: g}
+
+	for i := 0; i < 2; i++ {
+		update := new(dns.Msg)
+		update.SetUpdate("example.com.")
+		rr, _ := dns.NewRR("www.example.com. 300 IN A 1.2.3.4")
+		update.Insert([]dns.RR{rr})
+
+		client := &dns.Client{Net: "tcp", Timeout: 5 * time.Second}
+		var err error
+		client.TsigSecret, client.TsigProvider, err = key.sign(update)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, _, err := client.Exchange(update, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Rcode != dns.RcodeSuccess {
+			t.Fatalf("update failed: %s", dns.RcodeToString[resp.Rcode])
+		}
+		if name := <-updates; name != g.name {
+			t.Errorf("update signed with key %q, want %q", name, g.name)
+		}
+	}
+}
+
+func TestGSSTSIG_renew(t *testing.T) {
+	addr, _ := fakeGSSServer(t)
+	g := newGSSTSIG(addr, map[string]string{"gss-spn": "DNS/ns1.example.com"})
+	contexts := 0
+	g.newContext = func(spn string, creds gssCredentials) (gssContext, error) {
+		if spn != "DNS/ns1.example.com" {
+			t.Errorf("spn = %q", spn)
+		}
+		contexts++
+		return &fakeGSS{}, nil
+	}
+
+	first, err := g.keyName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The server's TKEY expires in an hour, so the key is reused...
+	if again, _ := g.keyName(); again != first || contexts != 1 {
+		t.Errorf("key was renegotiated too early")
+	}
+	// ...until it is about to expire.
+	g.expires = time.Now().Add(time.Minute)
+	if _, err := g.keyName(); err != nil {
+		t.Fatal(err)
+	}
+	if contexts != 2 {
+		t.Errorf("key was not renegotiated before it expired")
+	}
+}
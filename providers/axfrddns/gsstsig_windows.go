@@ -0,0 +1,234 @@
+package axfrddns
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// On Windows, GSS-TSIG uses SSPI's "Kerberos" package.
+
+// gssSupported tells whether GSS-TSIG is available on this platform.
+const gssSupported = true
+
+var (
+	secur32                        = syscall.NewLazyDLL("secur32.dll")
+	procAcquireCredentialsHandleW  = secur32.NewProc("AcquireCredentialsHandleW")
+	procInitializeSecurityContextW = secur32.NewProc("InitializeSecurityContextW")
+	procQueryContextAttributesW    = secur32.NewProc("QueryContextAttributesW")
+	procMakeSignature              = secur32.NewProc("MakeSignature")
+	procVerifySignature            = secur32.NewProc("VerifySignature")
+	procDeleteSecurityContext      = secur32.NewProc("DeleteSecurityContext")
+	procFreeCredentialsHandle      = secur32.NewProc("FreeCredentialsHandle")
+	procFreeContextBuffer          = secur32.NewProc("FreeContextBuffer")
+)
+
+const (
+	secEOK                 = 0
+	secIContinueNeeded     = 0x00090312
+	secpkgCredOutbound     = 2
+	securityNativeDrep     = 0x10
+	secbufferVersion       = 0
+	secbufferData          = 1
+	secbufferToken         = 2
+	secpkgAttrSizes        = 0
+	secWinntAuthIdentUnicd = 2
+
+	iscReqMutualAuth     = 0x00000002
+	iscReqReplayDetect   = 0x00000004
+	iscReqSequenceDetect = 0x00000008
+	iscReqAllocateMemory = 0x00000100
+	iscReqIntegrity      = 0x00010000
+)
+
+type secHandle struct {
+	lower, upper uintptr
+}
+
+type secBuffer struct {
+	size       uint32
+	bufferType uint32
+	buffer     *byte
+}
+
+type secBufferDesc struct {
+	version uint32
+	count   uint32
+	buffers *secBuffer
+}
+
+type secPkgContextSizes struct {
+	maxToken        uint32
+	maxSignature    uint32
+	blockSize       uint32
+	securityTrailer uint32
+}
+
+type secWinntAuthIdentity struct {
+	user           *uint16
+	userLength     uint32
+	domain         *uint16
+	domainLength   uint32
+	password       *uint16
+	passwordLength uint32
+	flags          uint32
+}
+
+type sspiContext struct {
+	target *uint16
+	cred   secHandle
+	ctx    secHandle
+	hasCtx bool
+	maxSig uint32
+}
+
+func newGSSContext(spn string, creds gssCredentials) (gssContext, error) {
+	target, err := syscall.UTF16PtrFromString(spn)
+	if err != nil {
+		return nil, err
+	}
+	pkg, _ := syscall.UTF16PtrFromString("Kerberos")
+
+	var identity *secWinntAuthIdentity
+	if creds.username != "" {
+		user, _ := syscall.UTF16FromString(creds.username)
+		domain, _ := syscall.UTF16FromString(creds.domain)
+		password, _ := syscall.UTF16FromString(creds.password)
+		identity = &secWinntAuthIdentity{
+			user:           &user[0],
+			userLength:     uint32(len(user) - 1),
+			domain:         &domain[0],
+			domainLength:   uint32(len(domain) - 1),
+			password:       &password[0],
+			passwordLength: uint32(len(password) - 1),
+			flags:          secWinntAuthIdentUnicd,
+		}
+	}
+
+	c := &sspiContext{target: target}
+	var expiry int64
+	r, _, _ := procAcquireCredentialsHandleW.Call(
+		0,
+		uintptr(unsafe.Pointer(pkg)),
+		secpkgCredOutbound,
+		0,
+		uintptr(unsafe.Pointer(identity)),
+		0,
+		0,
+		uintptr(unsafe.Pointer(&c.cred)),
+		uintptr(unsafe.Pointer(&expiry)),
+	)
+	runtime.KeepAlive(identity)
+	if r != secEOK {
+		return nil, fmt.Errorf("AcquireCredentialsHandle: %s", sspiError(r))
+	}
+	return c, nil
+}
+
+func (c *sspiContext) step(input []byte) ([]byte, bool, error) {
+	var in *secBufferDesc
+	if len(input) != 0 {
+		in = &secBufferDesc{
+			version: secbufferVersion,
+			count:   1,
+			buffers: &secBuffer{size: uint32(len(input)), bufferType: secbufferToken, buffer: &input[0]},
+		}
+	}
+	outBuf := secBuffer{bufferType: secbufferToken}
+	out := &secBufferDesc{version: secbufferVersion, count: 1, buffers: &outBuf}
+
+	var ctx uintptr
+	if c.hasCtx {
+		ctx = uintptr(unsafe.Pointer(&c.ctx))
+	}
+	var attrs uint32
+	var expiry int64
+	r, _, _ := procInitializeSecurityContextW.Call(
+		uintptr(unsafe.Pointer(&c.cred)),
+		ctx,
+		uintptr(unsafe.Pointer(c.target)),
+		iscReqMutualAuth|iscReqReplayDetect|iscReqSequenceDetect|iscReqIntegrity|iscReqAllocateMemory,
+		0,
+		securityNativeDrep,
+		uintptr(unsafe.Pointer(in)),
+		0,
+		uintptr(unsafe.Pointer(&c.ctx)),
+		uintptr(unsafe.Pointer(out)),
+		uintptr(unsafe.Pointer(&attrs)),
+		uintptr(unsafe.Pointer(&expiry)),
+	)
+	if r != secEOK && r != secIContinueNeeded {
+		return nil, false, fmt.Errorf("InitializeSecurityContext: %s", sspiError(r))
+	}
+	c.hasCtx = true
+
+	var output []byte
+	if outBuf.buffer != nil {
+		output = make([]byte, outBuf.size)
+		copy(output, unsafe.Slice(outBuf.buffer, outBuf.size))
+		procFreeContextBuffer.Call(uintptr(unsafe.Pointer(outBuf.buffer)))
+	}
+	if r == secIContinueNeeded {
+		return output, false, nil
+	}
+
+	var sizes secPkgContextSizes
+	r, _, _ = procQueryContextAttributesW.Call(
+		uintptr(unsafe.Pointer(&c.ctx)),
+		secpkgAttrSizes,
+		uintptr(unsafe.Pointer(&sizes)),
+	)
+	if r != secEOK {
+		return nil, false, fmt.Errorf("QueryContextAttributes: %s", sspiError(r))
+	}
+	c.maxSig = sizes.maxSignature
+	return output, true, nil
+}
+
+func (c *sspiContext) sign(msg []byte) ([]byte, error) {
+	if len(msg) == 0 {
+		return nil, fmt.Errorf("nothing to sign")
+	}
+	sig := make([]byte, c.maxSig)
+	buffers := []secBuffer{
+		{size: uint32(len(msg)), bufferType: secbufferData, buffer: &msg[0]},
+		{size: uint32(len(sig)), bufferType: secbufferToken, buffer: &sig[0]},
+	}
+	desc := &secBufferDesc{version: secbufferVersion, count: 2, buffers: &buffers[0]}
+	r, _, _ := procMakeSignature.Call(uintptr(unsafe.Pointer(&c.ctx)), 0, uintptr(unsafe.Pointer(desc)), 0)
+	if r != secEOK {
+		return nil, fmt.Errorf("MakeSignature: %s", sspiError(r))
+	}
+	return sig[:buffers[1].size], nil
+}
+
+func (c *sspiContext) verify(msg, mic []byte) error {
+	if len(msg) == 0 || len(mic) == 0 {
+		return fmt.Errorf("nothing to verify")
+	}
+	buffers := []secBuffer{
+		{size: uint32(len(msg)), bufferType: secbufferData, buffer: &msg[0]},
+		{size: uint32(len(mic)), bufferType: secbufferToken, buffer: &mic[0]},
+	}
+	desc := &secBufferDesc{version: secbufferVersion, count: 2, buffers: &buffers[0]}
+	var qop uint32
+	r, _, _ := procVerifySignature.Call(uintptr(unsafe.Pointer(&c.ctx)), uintptr(unsafe.Pointer(desc)), 0, uintptr(unsafe.Pointer(&qop)))
+	if r != secEOK {
+		return fmt.Errorf("VerifySignature: %s", sspiError(r))
+	}
+	return nil
+}
+
+func (c *sspiContext) close() error {
+	if c.hasCtx {
+		procDeleteSecurityContext.Call(uintptr(unsafe.Pointer(&c.ctx)))
+		c.hasCtx = false
+	}
+	procFreeCredentialsHandle.Call(uintptr(unsafe.Pointer(&c.cred)))
+	return nil
+}
+
+func sspiError(status uintptr) string {
+	return fmt.Sprintf("SSPI error 0x%08X", uint32(status))
+}
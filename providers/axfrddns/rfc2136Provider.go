@@ -0,0 +1,391 @@
+package axfrddns
+
+/*
+
+rfc2136 -
+  Like AXFRDDNS, push Dynamic DNS updates (RFC2136) to a primary master,
+  but without zone transfers: the existing records are read from a zone
+  file, or queried over DNS-over-HTTPS (RFC8484).
+
+  Each change is sent as its own update.
+
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/miekg/dns"
+)
+
+var rfc2136Features = providers.DocumentationNotes{
+	providers.CanGetZones:            providers.Cannot(),
+	providers.CanUseCAA:              providers.Can(),
+	providers.CanUseNAPTR:            providers.Can(),
+	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseSRV:              providers.Can(),
+	providers.CanUseSSHFP:            providers.Can(),
+	providers.CanUseTLSA:             providers.Can(),
+	providers.CantUseNOPURGE:         providers.Cannot(),
+	providers.DocCreateDomains:       providers.Cannot(),
+	providers.DocDualHost:            providers.Cannot(),
+	providers.DocOfficiallySupported: providers.Cannot(),
+}
+
+// dohTypes are the types that are queried at each label in doh mode.
+var dohTypes = []uint16{
+	dns.TypeA,
+	dns.TypeAAAA,
+	dns.TypeCAA,
+	dns.TypeCNAME,
+	dns.TypeMX,
+	dns.TypeNAPTR,
+	dns.TypeNS,
+	dns.TypePTR,
+	dns.TypeSRV,
+	dns.TypeSSHFP,
+	dns.TypeTLSA,
+	dns.TypeTXT,
+}
+
+// rfc2136Provider stores the client info for the provider.
+type rfc2136Provider struct {
+	rand        *rand.Rand
+	master      string
+	updateMode  string
+	nameservers []*models.Nameserver
+	updateKey   *Key
+	zonefile    string // The zone file, with %s for the name of the zone.
+	doh         string // The URL of the DoH server.
+	client      *http.Client
+}
+
+func initRfc2136(config map[string]string, providermeta json.RawMessage) (providers.DNSServiceProvider, error) {
+	var err error
+	api := &rfc2136Provider{
+		rand:     rand.New(rand.NewSource(int64(time.Now().Nanosecond()))),
+		zonefile: config["zonefile"],
+		doh:      config["doh"],
+		client:   &http.Client{Timeout: dnsTimeout},
+	}
+	param := &Param{}
+	if len(providermeta) != 0 {
+		err := json.Unmarshal(providermeta, param)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var nss []string
+	if config["nameservers"] != "" {
+		nss = strings.Split(config["nameservers"], ",")
+	}
+	for _, ns := range param.DefaultNS {
+		nss = append(nss, ns[0:len(ns)-1])
+	}
+	api.nameservers, err = models.ToNameservers(nss)
+	if err != nil {
+		return nil, err
+	}
+	switch config["update-mode"] {
+	case "tcp",
+		"tcp-tls":
+		api.updateMode = config["update-mode"]
+	case "", "udp":
+		api.updateMode = ""
+	default:
+		printer.Printf("[Warning] RFC2136: Unknown update-mode in `creds.json` (%s)\n", config["update-mode"])
+	}
+	if config["master"] != "" {
+		api.master = config["master"]
+		if !strings.Contains(api.master, ":") {
+			api.master = api.master + ":53"
+		}
+	} else if len(api.nameservers) != 0 {
+		api.master = api.nameservers[0].Name + ":53"
+	} else {
+		return nil, fmt.Errorf("nameservers list is empty: creds.json needs a default `nameservers` or an explicit `master`")
+	}
+	if (api.zonefile == "") == (api.doh == "") {
+		return nil, fmt.Errorf("creds.json needs either `zonefile` or `doh` to read the zones")
+	}
+	api.updateKey, err = readKey(config["update-key"], "update-key")
+	if err != nil {
+		return nil, err
+	}
+	setupGSSTSIG(api.master, config, api.updateKey)
+	for key := range config {
+		switch key {
+		case "master",
+			"nameservers",
+			"update-key",
+			"update-mode",
+			"zonefile",
+			"doh",
+			"gss-spn",
+			"gss-username",
+			"gss-password",
+			"gss-domain":
+			continue
+		default:
+			printer.Printf("[Warning] RFC2136: unknown key in `creds.json` (%s)\n", key)
+		}
+	}
+	return api, err
+}
+
+func init() {
+	fns := providers.DspFuncs{
+		Initializer:   initRfc2136,
+		RecordAuditor: AuditRecords,
+	}
+	providers.RegisterDomainServiceProviderType("RFC2136", fns, rfc2136Features, txtutil.SplitLong, metaSchema)
+}
+
+// GetNameservers returns the nameservers for a domain.
+func (c *rfc2136Provider) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	return c.nameservers, nil
+}
+
+// GetZoneRecords gets the records of a zone and returns them in RecordConfig format.
+// Only the zonefile mode can list all the records of a zone.
+func (c *rfc2136Provider) GetZoneRecords(domain string) (models.Records, error) {
+	if c.zonefile == "" {
+		return nil, fmt.Errorf("[Error] RFC2136: can't list the records of %s with DoH; use `zonefile`", domain)
+	}
+	return c.readZonefile(domain)
+}
+
+// readZonefile returns the records in the zone file of domain, without
+// the SOA and the DNSSEC records.
+func (c *rfc2136Provider) readZonefile(domain string) (models.Records, error) {
+	name := strings.ReplaceAll(c.zonefile, "%s", domain)
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	records := models.Records{}
+	zp := dns.NewZoneParser(bytes.NewReader(content), domain+".", name)
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		if skipRR(rr) {
+			continue
+		}
+		rec, err := models.RRtoRC(rr, domain)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &rec)
+	}
+	if err := zp.Err(); err != nil {
+		return nil, fmt.Errorf("[Error] RFC2136: can't parse %s: %w", name, err)
+	}
+	return records, nil
+}
+
+// queryRecords returns the records at the labels of dc (and at the apex)
+// from the DoH server. The records at other labels aren't seen.
+func (c *rfc2136Provider) queryRecords(dc *models.DomainConfig) (models.Records, error) {
+	names := []string{dc.Name + "."}
+	seen := map[string]bool{names[0]: true}
+	for _, rc := range dc.Records {
+		name := strings.ToLower(rc.GetLabelFQDN()) + "."
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	records := models.Records{}
+	for _, name := range names {
+		for _, qtype := range dohTypes {
+			rrs, err := c.query(name, qtype)
+			if err != nil {
+				return nil, err
+			}
+			for _, rr := range rrs {
+				rec, err := models.RRtoRC(rr, dc.Name)
+				if err != nil {
+					return nil, err
+				}
+				records = append(records, &rec)
+			}
+		}
+	}
+	return records, nil
+}
+
+// query asks the DoH server for the records of type qtype at name. The
+// server must be authoritative for the zone: a resolver would answer
+// with the TTLs of its cache.
+func (c *rfc2136Provider) query(name string, qtype uint16) ([]dns.RR, error) {
+	request := new(dns.Msg)
+	request.SetQuestion(name, qtype)
+	request.Id = 0 // As recommended by RFC8484.
+	request.RecursionDesired = false
+	packed, err := request.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.doh, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("[Error] RFC2136: DoH query for %s %s: %s", name, dns.TypeToString[qtype], resp.Status)
+	}
+
+	answer := new(dns.Msg)
+	if err := answer.Unpack(body); err != nil {
+		return nil, fmt.Errorf("[Error] RFC2136: DoH query for %s %s: %w", name, dns.TypeToString[qtype], err)
+	}
+	if answer.Rcode == dns.RcodeNameError {
+		return nil, nil
+	}
+	if answer.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("[Error] RFC2136: DoH query for %s %s: %s", name, dns.TypeToString[qtype], dns.RcodeToString[answer.Rcode])
+	}
+	if !answer.Authoritative {
+		// A delegation (NS records at name) is answered with a referral.
+		var delegation []dns.RR
+		for _, rr := range answer.Ns {
+			if rr.Header().Rrtype == dns.TypeNS && strings.EqualFold(rr.Header().Name, name) {
+				delegation = append(delegation, rr)
+			}
+		}
+		if delegation == nil {
+			return nil, fmt.Errorf("[Error] RFC2136: the DoH server isn't authoritative for %s", name)
+		}
+		if qtype != dns.TypeNS {
+			return nil, nil
+		}
+		return delegation, nil
+	}
+
+	var rrs []dns.RR
+	for _, rr := range answer.Answer {
+		// Skip the CNAME chains.
+		if rr.Header().Rrtype == qtype && strings.EqualFold(rr.Header().Name, name) {
+			rrs = append(rrs, rr)
+		}
+	}
+	return rrs, nil
+}
+
+// skipRR reports whether rr is a record that the provider doesn't manage.
+func skipRR(rr dns.RR) bool {
+	switch rr.(type) {
+	case *dns.SOA,
+		*dns.RRSIG,
+		*dns.DNSKEY,
+		*dns.CDNSKEY,
+		*dns.CDS,
+		*dns.NSEC,
+		*dns.NSEC3,
+		*dns.NSEC3PARAM:
+		return true
+	}
+	return false
+}
+
+// GetDomainCorrections returns a list of corrections to update a domain.
+func (c *rfc2136Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	dc.Punycode()
+
+	var foundRecords models.Records
+	var err error
+	if c.zonefile != "" {
+		foundRecords, err = c.readZonefile(dc.Name)
+	} else {
+		foundRecords, err = c.queryRecords(dc)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize
+	models.PostProcessRecords(foundRecords)
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
+
+	var create, del, mod diff.Changeset
+	if !diff2.EnableDiff2 {
+		differ := diff.New(dc)
+		_, create, del, mod, err = differ.IncrementalDiff(foundRecords)
+	} else {
+		differ := diff.NewCompat(dc)
+		_, create, del, mod, err = differ.IncrementalDiff(foundRecords)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The updates are sent in the same order as AXFRDDNS puts them in
+	// its single update: the new NS records first (so that the zone
+	// always has one), then the removals (an RFC2136-compliant server
+	// ignores a CNAME added where other records remain, and
+	// vice-versa), then the modifications and the other new records.
+	var corrections []*models.Correction
+	for _, cr := range create {
+		if cr.Desired.Type == "NS" {
+			corrections = append(corrections, c.updateCorrection(dc.Name, cr, nil, cr.Desired))
+		}
+	}
+	for _, d := range del {
+		corrections = append(corrections, c.updateCorrection(dc.Name, d, d.Existing, nil))
+	}
+	for _, m := range mod {
+		corrections = append(corrections, c.updateCorrection(dc.Name, m, m.Existing, m.Desired))
+	}
+	for _, cr := range create {
+		if cr.Desired.Type != "NS" {
+			corrections = append(corrections, c.updateCorrection(dc.Name, cr, nil, cr.Desired))
+		}
+	}
+	return corrections, nil
+}
+
+// updateCorrection returns the correction that removes existing (if not
+// nil) and inserts desired (if not nil) in one update.
+func (c *rfc2136Provider) updateCorrection(domain string, change diff.Correlation, existing, desired *models.RecordConfig) *models.Correction {
+	return &models.Correction{
+		Msg:  change.String(),
+		Kind: change.Kind(),
+		F: func() error {
+			update := new(dns.Msg)
+			update.SetUpdate(domain + ".")
+			update.Id = uint16(c.rand.Intn(math.MaxUint16))
+			if existing != nil {
+				update.Remove([]dns.RR{existing.ToRR()})
+			}
+			if desired != nil {
+				update.Insert([]dns.RR{desired.ToRR()})
+			}
+			return sendUpdate("RFC2136", update, c.master, c.updateMode, c.updateKey)
+		},
+	}
+}
@@ -0,0 +1,157 @@
+package axfrddns
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/miekg/dns"
+)
+
+func makeRC(label, rtype, content string) *models.RecordConfig {
+	rc := &models.RecordConfig{TTL: 300}
+	rc.SetLabel(label, "example.com")
+	if err := rc.PopulateFromString(rtype, content, "example.com"); err != nil {
+		panic(err)
+	}
+	return rc
+}
+
+func TestRfc2136Zonefile(t *testing.T) {
+	dir := t.TempDir()
+	zone := `$TTL 300
+@    IN SOA ns1.example.com. hostmaster.example.com. 1 3600 600 604800 1440
+@    IN NS  ns1.example.com.
+www  IN A   1.2.3.4
+old  IN A   5.6.7.8
+mail IN MX  10 mx.example.com.
+`
+	if err := os.WriteFile(filepath.Join(dir, "example.com.zone"), []byte(zone), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	c := &rfc2136Provider{zonefile: filepath.Join(dir, "%s.zone")}
+
+	records, err := c.GetZoneRecords("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4 (without the SOA)", len(records))
+	}
+
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: models.Records{
+			makeRC("@", "NS", "ns1.example.com."),
+			makeRC("@", "NS", "ns2.example.com."),
+			makeRC("www", "A", "1.2.3.5"),
+			makeRC("mail", "MX", "10 mx.example.com."),
+			makeRC("new", "A", "9.9.9.9"),
+		},
+	}
+	corrections, err := c.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []string
+	for _, cr := range corrections {
+		msgs = append(msgs, cr.Msg)
+	}
+	// One update per change: new NS, deletions, modifications, creations.
+	want := []string{
+		"CREATE NS example.com ns2.example.com. ttl=300",
+		"DELETE A old.example.com 5.6.7.8 ttl=300",
+		"MODIFY A www.example.com: (1.2.3.4 ttl=300) -> (1.2.3.5 ttl=300)",
+		"CREATE A new.example.com 9.9.9.9 ttl=300",
+	}
+	if strings.Join(msgs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(msgs, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// dohServer answers the DoH queries with the records in zone, as an
+// authoritative server for example.com with sub.example.com delegated.
+func dohServer(t *testing.T, zone string) *httptest.Server {
+	var rrs []dns.RR
+	zp := dns.NewZoneParser(strings.NewReader(zone), "example.com.", "")
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		rrs = append(rrs, rr)
+	}
+	if err := zp.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := new(dns.Msg)
+		if err := req.Unpack(body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		q := req.Question[0]
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Authoritative = true
+		for _, rr := range rrs {
+			h := rr.Header()
+			if h.Name == "sub.example.com." && h.Rrtype == dns.TypeNS && q.Name == h.Name {
+				resp.Authoritative = false
+				resp.Ns = append(resp.Ns, rr)
+			} else if h.Name == q.Name && (h.Rrtype == q.Qtype || h.Rrtype == dns.TypeCNAME) {
+				resp.Answer = append(resp.Answer, rr)
+			}
+		}
+		if !resp.Authoritative {
+			resp.Answer = nil
+		}
+		packed, _ := resp.Pack()
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(packed)
+	}))
+}
+
+func TestRfc2136DoH(t *testing.T) {
+	server := dohServer(t, `$TTL 300
+@    IN NS    ns1.example.com.
+www  IN A     1.2.3.4
+www  IN TXT   "hello"
+ftp  IN CNAME www.example.com.
+sub  IN NS    ns.elsewhere.net.
+hidden IN A   7.7.7.7
+`)
+	defer server.Close()
+	c := &rfc2136Provider{doh: server.URL, client: server.Client()}
+
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: models.Records{
+			makeRC("www", "A", "1.2.3.4"),
+			makeRC("ftp", "CNAME", "www.example.com."),
+			makeRC("sub", "NS", "ns.elsewhere.net."),
+		},
+	}
+	records, err := c.queryRecords(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, rc := range records {
+		got = append(got, rc.GetLabel()+" "+rc.Type+" "+rc.ToDiffable())
+	}
+	// The records at the labels of dnsconfig.js only, and the CNAME is
+	// only returned for its own type.
+	want := []string{
+		"@ NS ns1.example.com. ttl=300",
+		"www A 1.2.3.4 ttl=300",
+		"www TXT \"hello\" ttl=300",
+		"ftp CNAME www.example.com. ttl=300",
+		"sub NS ns.elsewhere.net. ttl=300",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -4,16 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	aauth "github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	adns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/httpclient"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
 	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
@@ -31,18 +35,34 @@ func newAzureDNSDsp(conf map[string]string, metadata json.RawMessage) (providers
 	return newAzureDNS(conf, metadata)
 }
 
+// httpclientPolicy sends the requests of the SDK through pkg/httpclient.
+type httpclientPolicy struct{}
+
+func (httpclientPolicy) Do(req *policy.Request) (*http.Response, error) {
+	return httpclient.Send(req.Raw(), func(r *http.Request) (*http.Response, error) {
+		return req.Clone(r.Context()).Next()
+	})
+}
+
 func newAzureDNS(m map[string]string, metadata json.RawMessage) (*azurednsProvider, error) {
 	subID, rg := m["SubscriptionID"], m["ResourceGroup"]
 	clientID, clientSecret, tenantID := m["ClientID"], m["ClientSecret"], m["TenantID"]
-	credential, authErr := aauth.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+	var clientOptions policy.ClientOptions
+	if httpclient.Installed() {
+		// The SDK doesn't use http.DefaultTransport: instrument the
+		// requests that its own transport sends.
+		clientOptions.PerRetryPolicies = []policy.Policy{httpclientPolicy{}}
+	}
+	credential, authErr := aauth.NewClientSecretCredential(tenantID, clientID, clientSecret, &aauth.ClientSecretCredentialOptions{ClientOptions: clientOptions})
 	if authErr != nil {
 		return nil, authErr
 	}
-	zonesClient, zoneErr := adns.NewZonesClient(subID, credential, nil)
+	options := &arm.ClientOptions{ClientOptions: clientOptions}
+	zonesClient, zoneErr := adns.NewZonesClient(subID, credential, options)
 	if zoneErr != nil {
 		return nil, zoneErr
 	}
-	recordsClient, recordErr := adns.NewRecordSetsClient(subID, credential, nil)
+	recordsClient, recordErr := adns.NewRecordSetsClient(subID, credential, options)
 	if recordErr != nil {
 		return nil, recordErr
 	}
@@ -55,6 +75,8 @@ func newAzureDNS(m map[string]string, metadata json.RawMessage) (*azurednsProvid
 	return api, nil
 }
 
+var logger = printer.Module("providers.azuredns")
+
 var features = providers.DocumentationNotes{
 	providers.CanGetZones:            providers.Can(),
 	providers.CanUseAlias:            providers.Cannot("Azure DNS does not provide a generic ALIAS functionality. Use AZURE_ALIAS instead."),
@@ -75,7 +97,7 @@ func init() {
 		Initializer:   newAzureDNSDsp,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("AZURE_DNS", fns, features)
+	providers.RegisterDomainServiceProviderType("AZURE_DNS", fns, features, txtutil.SplitLong)
 	providers.RegisterCustomRecordType("AZURE_ALIAS", "AZURE_DNS", "")
 }
 
@@ -194,7 +216,7 @@ func (a *azurednsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 		return nil, err
 	}
 
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 {
@@ -209,6 +231,7 @@ func (a *azurednsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 			return nil, nil
 		}
 
+		existing := existingRecords.GroupedByKey()
 		updates := map[models.RecordKey][]*models.RecordConfig{}
 
 		for k := range namesToUpdate {
@@ -242,7 +265,8 @@ func (a *azurednsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 				if rrset != nil {
 					corrections = append(corrections,
 						&models.Correction{
-							Msg: strings.Join(namesToUpdate[k], "\n"),
+							Msg:  strings.Join(namesToUpdate[k], "\n"),
+							Kind: models.CorrectionDestroy,
 							F: func() error {
 								ctx, cancel := context.WithTimeout(context.Background(), 6000*time.Second)
 								defer cancel()
@@ -285,7 +309,8 @@ func (a *azurednsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 						if existingRecordType == adns.RecordTypeA || existingRecordType == adns.RecordTypeAAAA || changedRecordType == adns.RecordTypeA || changedRecordType == adns.RecordTypeAAAA { //CNAME cannot coexist with an A or AA
 							corrections = append(corrections,
 								&models.Correction{
-									Msg: strings.Join(namesToUpdate[k], "\n"),
+									Msg:  strings.Join(namesToUpdate[k], "\n"),
+									Kind: models.CorrectionDestroy,
 									F: func() error {
 										ctx, cancel := context.WithTimeout(context.Background(), 6000*time.Second)
 										defer cancel()
@@ -302,7 +327,8 @@ func (a *azurednsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 
 				corrections = append(corrections,
 					&models.Correction{
-						Msg: strings.Join(namesToUpdate[k], "\n"),
+						Msg:  strings.Join(namesToUpdate[k], "\n"),
+						Kind: diff.ReplaceKind(existing[k], recs),
 						F: func() error {
 							ctx, cancel := context.WithTimeout(context.Background(), 6000*time.Second)
 							defer cancel()
@@ -358,7 +384,8 @@ func (a *azurednsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 
 			corrections = append(corrections,
 				&models.Correction{
-					Msg: strings.Join(inst.Msgs, "\n"),
+					Msg:  strings.Join(inst.Msgs, "\n"),
+					Kind: inst.Kind(),
 					F: func() error {
 						ctx, cancel := context.WithTimeout(context.Background(), 6000*time.Second)
 						defer cancel()
@@ -371,11 +398,12 @@ func (a *azurednsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 				})
 
 		case diff2.DELETE:
-			fmt.Printf("DEBUG: azure inst=%s\n", inst)
+			logger.Debugf("azure inst=%s\n", inst)
 			rrset := inst.Old[0].Original.(*adns.RecordSet)
 			corrections = append(corrections,
 				&models.Correction{
-					Msg: strings.Join(inst.Msgs, "\n"),
+					Msg:  strings.Join(inst.Msgs, "\n"),
+					Kind: inst.Kind(),
 					F: func() error {
 						ctx, cancel := context.WithTimeout(context.Background(), 6000*time.Second)
 						defer cancel()
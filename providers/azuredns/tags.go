@@ -0,0 +1,55 @@
+package azuredns
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	adns "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/zonetags"
+)
+
+// GetZoneTagCorrections returns the corrections of the resource tags of
+// the zone (see package pkg/zonetags). The tags whose key starts with
+// "hidden-" belong to the Azure portal and are left alone.
+func (a *azurednsProvider) GetZoneTagCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	if _, ok := dc.Metadata[zonetags.Key]; !ok {
+		return nil, nil
+	}
+	zone, ok := a.zones[dc.Name]
+	if !ok {
+		return nil, errNoExist{dc.Name}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 6000*time.Second)
+	defer cancel()
+	resp, err := a.zonesClient.Get(ctx, *a.resourceGroup, *zone.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]string{}
+	for k, v := range resp.Tags {
+		if !strings.HasPrefix(k, "hidden-") {
+			current[k] = to.String(v)
+		}
+	}
+	return zonetags.Corrections(dc, current, func(add map[string]string, remove []string) error {
+		// The update replaces all the tags of the zone. The ETag makes
+		// it fail if they changed since they were read.
+		tags := map[string]*string{}
+		for k, v := range resp.Tags {
+			tags[k] = v
+		}
+		for k, v := range add {
+			tags[k] = to.StringPtr(v)
+		}
+		for _, k := range remove {
+			delete(tags, k)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 6000*time.Second)
+		defer cancel()
+		_, err := a.zonesClient.Update(ctx, *a.resourceGroup, *zone.Name, adns.ZoneUpdate{Tags: tags}, &adns.ZonesClientUpdateOptions{IfMatch: resp.Etag})
+		return err
+	})
+}
@@ -0,0 +1,372 @@
+package azureprivatedns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	aauth "github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+// Azure Private DNS zones are a separate API from the public Azure DNS
+// zones (see providers/azuredns). Private zones are only visible from
+// the virtual networks that they are linked to, and the records of
+// the virtual machines in those networks can be registered
+// automatically.
+
+type azurePrivateDNSProvider struct {
+	client *client
+	zones  map[string]zone
+}
+
+func newAzurePrivateDNSDsp(conf map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
+	return newAzurePrivateDNS(conf, metadata)
+}
+
+func newAzurePrivateDNS(m map[string]string, metadata json.RawMessage) (*azurePrivateDNSProvider, error) {
+	subID, rg := m["SubscriptionID"], m["ResourceGroup"]
+	clientID, clientSecret, tenantID := m["ClientID"], m["ClientSecret"], m["TenantID"]
+	if subID == "" || rg == "" {
+		return nil, fmt.Errorf("AZURE_PRIVATE_DNS: SubscriptionID and ResourceGroup are required")
+	}
+	credential, err := aauth.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newClient(subID, rg, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	api := &azurePrivateDNSProvider{client: c}
+	if err := api.getZones(); err != nil {
+		return nil, err
+	}
+	return api, nil
+}
+
+var features = providers.DocumentationNotes{
+	providers.CanGetZones:            providers.Can(),
+	providers.CanUseAlias:            providers.Cannot(),
+	providers.CanUseCAA:              providers.Cannot("Azure Private DNS does not support CAA records"),
+	providers.CanUseNAPTR:            providers.Cannot(),
+	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseSRV:              providers.Can(),
+	providers.CanUseSSHFP:            providers.Cannot(),
+	providers.CanUseTLSA:             providers.Cannot(),
+	providers.DocCreateDomains:       providers.Can(),
+	providers.DocDualHost:            providers.Cannot("Private zones are not delegated"),
+	providers.DocOfficiallySupported: providers.Cannot(),
+}
+
+func init() {
+	fns := providers.DspFuncs{
+		Initializer:   newAzurePrivateDNSDsp,
+		RecordAuditor: AuditRecords,
+	}
+	providers.RegisterDomainServiceProviderType("AZURE_PRIVATE_DNS", fns, features, txtutil.SplitLong)
+}
+
+// timeout is the timeout of each operation. Creating a zone or a
+// virtual network link can take a few minutes.
+const timeout = 30 * time.Minute
+
+func (a *azurePrivateDNSProvider) getZones() error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	zones, err := a.client.listZones(ctx)
+	if err != nil {
+		return err
+	}
+	a.zones = make(map[string]zone)
+	for _, z := range zones {
+		a.zones[strings.TrimSuffix(z.Name, ".")] = z
+	}
+	return nil
+}
+
+type errNoExist struct {
+	domain string
+}
+
+func (e errNoExist) Error() string {
+	return fmt.Sprintf("Private zone %s not found in your Azure resource group", e.domain)
+}
+
+// GetNameservers returns no nameservers: private zones aren't
+// delegated.
+func (a *azurePrivateDNSProvider) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	if _, ok := a.zones[domain]; !ok {
+		return nil, errNoExist{domain}
+	}
+	return nil, nil
+}
+
+// ListZones lists the private zones in the resource group.
+func (a *azurePrivateDNSProvider) ListZones() ([]string, error) {
+	if err := a.getZones(); err != nil {
+		return nil, err
+	}
+	var zones []string
+	for name := range a.zones {
+		zones = append(zones, name)
+	}
+	sort.Strings(zones)
+	return zones, nil
+}
+
+// GetZoneRecords gets the records of a zone and returns them in RecordConfig format.
+func (a *azurePrivateDNSProvider) GetZoneRecords(domain string) (models.Records, error) {
+	existingRecords, _, err := a.getExistingRecords(domain)
+	if err != nil {
+		return nil, err
+	}
+	return existingRecords, nil
+}
+
+func (a *azurePrivateDNSProvider) getExistingRecords(domain string) (models.Records, []recordSet, error) {
+	if _, ok := a.zones[domain]; !ok {
+		return nil, nil, errNoExist{domain}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	sets, err := a.client.listRecordSets(ctx, domain)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var existingRecords models.Records
+	var managed []recordSet
+	for _, set := range sets {
+		if set.Properties.IsAutoRegistered {
+			// The records of virtual machines that Azure registers are
+			// not managed by dnscontrol.
+			continue
+		}
+		recs, err := nativeToRecords(set, domain)
+		if err != nil {
+			return nil, nil, err
+		}
+		existingRecords = append(existingRecords, recs...)
+		managed = append(managed, set)
+	}
+	return existingRecords, managed, nil
+}
+
+// GetDomainCorrections returns a list of corrections to update a domain.
+func (a *azurePrivateDNSProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	if err := dc.Punycode(); err != nil {
+		return nil, err
+	}
+
+	existingRecords, sets, err := a.getExistingRecords(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	linkCorrections, err := a.getLinkCorrections(dc)
+	if err != nil {
+		return nil, err
+	}
+
+	models.PostProcessRecords(existingRecords)
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
+
+	differ := diff.New(dc)
+	namesToUpdate, err := differ.ChangedGroups(existingRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []models.RecordKey
+	for k := range namesToUpdate {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].NameFQDN == keys[j].NameFQDN {
+			return keys[i].Type < keys[j].Type
+		}
+		return keys[i].NameFQDN < keys[j].NameFQDN
+	})
+
+	// Deletions come first, so that a CNAME can replace other records
+	// at the same label (and the other way round).
+	existing := existingRecords.GroupedByKey()
+	var deletes, updates []*models.Correction
+	for _, k := range keys {
+		var recs []*models.RecordConfig
+		for _, rc := range dc.Records {
+			if rc.Key() == k {
+				recs = append(recs, rc)
+			}
+		}
+		msg := strings.Join(namesToUpdate[k], "\n")
+		name := relativeName(k.NameFQDN, dc.Name)
+		rtype := k.Type
+
+		if len(recs) == 0 {
+			if !hasRecordSet(sets, name, rtype) {
+				// This should not happen.
+				return nil, fmt.Errorf("no record set found to delete. Name: '%s'. Type: '%s'", k.NameFQDN, k.Type)
+			}
+			deletes = append(deletes, &models.Correction{
+				Msg:  msg,
+				Kind: models.CorrectionDestroy,
+				F: func() error {
+					ctx, cancel := context.WithTimeout(context.Background(), timeout)
+					defer cancel()
+					return a.client.deleteRecordSet(ctx, dc.Name, rtype, name)
+				},
+			})
+			continue
+		}
+
+		set, err := recordsToNative(recs)
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, &models.Correction{
+			Msg:  msg,
+			Kind: diff.ReplaceKind(existing[k], recs),
+			F: func() error {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				defer cancel()
+				return a.client.putRecordSet(ctx, dc.Name, rtype, name, set)
+			},
+		})
+	}
+
+	corrections := append(deletes, updates...)
+	return append(corrections, linkCorrections...), nil
+}
+
+// relativeName returns the name of a record set relative to the zone,
+// which is "@" at the apex.
+func relativeName(fqdn, origin string) string {
+	if fqdn == origin {
+		return "@"
+	}
+	return strings.TrimSuffix(fqdn, "."+origin)
+}
+
+func hasRecordSet(sets []recordSet, name, rtype string) bool {
+	for _, set := range sets {
+		if set.Name == name && strings.TrimPrefix(set.Type, recordTypePrefix) == rtype {
+			return true
+		}
+	}
+	return false
+}
+
+// recordTypePrefix is the prefix of the resource type of record sets.
+const recordTypePrefix = "Microsoft.Network/privateDnsZones/"
+
+func nativeToRecords(set recordSet, origin string) ([]*models.RecordConfig, error) {
+	var results []*models.RecordConfig
+	add := func(rtype string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: rtype, TTL: uint32(set.Properties.TTL), Original: set}
+		rc.SetLabel(set.Name, origin)
+		results = append(results, rc)
+		return rc
+	}
+
+	p := set.Properties
+	var err error
+	switch rtype := strings.TrimPrefix(set.Type, recordTypePrefix); rtype {
+	case "A":
+		for _, rec := range p.ARecords {
+			err = add(rtype).SetTarget(rec.IPv4Address)
+		}
+	case "AAAA":
+		for _, rec := range p.AaaaRecords {
+			err = add(rtype).SetTarget(rec.IPv6Address)
+		}
+	case "CNAME":
+		if p.CnameRecord != nil {
+			err = add(rtype).SetTarget(p.CnameRecord.Cname)
+		}
+	case "MX":
+		for _, rec := range p.MxRecords {
+			err = add(rtype).SetTargetMX(uint16(rec.Preference), rec.Exchange)
+		}
+	case "PTR":
+		for _, rec := range p.PtrRecords {
+			err = add(rtype).SetTarget(rec.Ptrdname)
+		}
+	case "SRV":
+		for _, rec := range p.SrvRecords {
+			err = add(rtype).SetTargetSRV(uint16(rec.Priority), uint16(rec.Weight), uint16(rec.Port), rec.Target)
+		}
+	case "TXT":
+		if len(p.TxtRecords) == 0 { // Empty String Record Parsing
+			err = add(rtype).SetTargetTXT("")
+		}
+		for _, rec := range p.TxtRecords {
+			err = add(rtype).SetTargetTXTs(rec.Value)
+		}
+	case "SOA":
+		// The SOA record is managed by Azure.
+	default:
+		return nil, fmt.Errorf("unsupported record type %q received from Azure", set.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// recordsToNative returns the record set for records with the same
+// label and type.
+func recordsToNative(recs []*models.RecordConfig) (recordSet, error) {
+	set := recordSet{}
+	p := &set.Properties
+	for _, rec := range recs {
+		// A set has one TTL. Validation warns about the labels whose
+		// records have different TTLs; the last one wins.
+		p.TTL = int64(rec.TTL)
+		switch rec.Type {
+		case "A":
+			p.ARecords = append(p.ARecords, aRecord{IPv4Address: rec.GetTargetField()})
+		case "AAAA":
+			p.AaaaRecords = append(p.AaaaRecords, aaaaRecord{IPv6Address: rec.GetTargetField()})
+		case "CNAME":
+			p.CnameRecord = &cnameRecord{Cname: rec.GetTargetField()}
+		case "MX":
+			p.MxRecords = append(p.MxRecords, mxRecord{Preference: int32(rec.MxPreference), Exchange: rec.GetTargetField()})
+		case "PTR":
+			p.PtrRecords = append(p.PtrRecords, ptrRecord{Ptrdname: rec.GetTargetField()})
+		case "SRV":
+			p.SrvRecords = append(p.SrvRecords, srvRecord{Priority: int32(rec.SrvPriority), Weight: int32(rec.SrvWeight), Port: int32(rec.SrvPort), Target: rec.GetTargetField()})
+		case "TXT":
+			// An empty TXT record has no value.
+			if !(len(rec.TxtStrings) == 1 && rec.TxtStrings[0] == "") {
+				p.TxtRecords = append(p.TxtRecords, txtRecord{Value: rec.TxtStrings})
+			}
+		default:
+			return recordSet{}, fmt.Errorf("rc.String rtype %v unimplemented", rec.Type)
+		}
+	}
+	return set, nil
+}
+
+// EnsureDomainExists creates the private zone if it doesn't exist.
+func (a *azurePrivateDNSProvider) EnsureDomainExists(domain string) error {
+	if _, ok := a.zones[domain]; ok {
+		return nil
+	}
+	printer.Printf("Adding private zone for %s to Azure resource group\n", domain)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := a.client.createZone(ctx, domain); err != nil {
+		return err
+	}
+	return a.getZones()
+}
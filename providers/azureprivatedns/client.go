@@ -0,0 +1,265 @@
+package azureprivatedns
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// client is a small client for the Microsoft.Network/privateDnsZones
+// API. It only has the operations that this provider needs.
+type client struct {
+	host           string
+	subscriptionID string
+	resourceGroup  string
+	pl             runtime.Pipeline
+}
+
+const (
+	moduleName    = "dnscontrol-azureprivatedns"
+	moduleVersion = "v1.0.0"
+	apiVersion    = "2020-06-01"
+)
+
+func newClient(subscriptionID, resourceGroup string, credential azcore.TokenCredential) (*client, error) {
+	pl, err := armruntime.NewPipeline(moduleName, moduleVersion, credential, runtime.PipelineOptions{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &client{
+		host:           cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint,
+		subscriptionID: subscriptionID,
+		resourceGroup:  resourceGroup,
+		pl:             pl,
+	}, nil
+}
+
+// zone is a private DNS zone.
+type zone struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Location string `json:"location"`
+}
+
+// recordSet is a record set in a private DNS zone.
+type recordSet struct {
+	ID         string              `json:"id,omitempty"`
+	Name       string              `json:"name,omitempty"`
+	Type       string              `json:"type,omitempty"`
+	Etag       string              `json:"etag,omitempty"`
+	Properties recordSetProperties `json:"properties"`
+}
+
+type recordSetProperties struct {
+	TTL              int64        `json:"ttl"`
+	Fqdn             string       `json:"fqdn,omitempty"`
+	IsAutoRegistered bool         `json:"isAutoRegistered,omitempty"`
+	ARecords         []aRecord    `json:"aRecords,omitempty"`
+	AaaaRecords      []aaaaRecord `json:"aaaaRecords,omitempty"`
+	CnameRecord      *cnameRecord `json:"cnameRecord,omitempty"`
+	MxRecords        []mxRecord   `json:"mxRecords,omitempty"`
+	PtrRecords       []ptrRecord  `json:"ptrRecords,omitempty"`
+	SrvRecords       []srvRecord  `json:"srvRecords,omitempty"`
+	TxtRecords       []txtRecord  `json:"txtRecords,omitempty"`
+}
+
+type aRecord struct {
+	IPv4Address string `json:"ipv4Address"`
+}
+
+type aaaaRecord struct {
+	IPv6Address string `json:"ipv6Address"`
+}
+
+type cnameRecord struct {
+	Cname string `json:"cname"`
+}
+
+type mxRecord struct {
+	Preference int32  `json:"preference"`
+	Exchange   string `json:"exchange"`
+}
+
+type ptrRecord struct {
+	Ptrdname string `json:"ptrdname"`
+}
+
+type srvRecord struct {
+	Priority int32  `json:"priority"`
+	Weight   int32  `json:"weight"`
+	Port     int32  `json:"port"`
+	Target   string `json:"target"`
+}
+
+type txtRecord struct {
+	Value []string `json:"value"`
+}
+
+// virtualNetworkLink links a private DNS zone to a virtual network.
+type virtualNetworkLink struct {
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Location   string `json:"location"`
+	Properties struct {
+		VirtualNetwork struct {
+			ID string `json:"id"`
+		} `json:"virtualNetwork"`
+		RegistrationEnabled bool   `json:"registrationEnabled"`
+		ProvisioningState   string `json:"provisioningState,omitempty"`
+	} `json:"properties"`
+}
+
+// path returns the URL of a resource in the resource group.
+func (c *client) path(elems ...string) string {
+	p := "/subscriptions/" + url.PathEscape(c.subscriptionID) +
+		"/resourceGroups/" + url.PathEscape(c.resourceGroup) +
+		"/providers/Microsoft.Network/privateDnsZones"
+	for _, e := range elems {
+		p += "/" + url.PathEscape(e)
+	}
+	return runtime.JoinPaths(c.host, p)
+}
+
+func (c *client) newRequest(ctx context.Context, method, u string, body interface{}) (*policy.Request, error) {
+	req, err := runtime.NewRequest(ctx, method, u)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(u, "api-version=") {
+		qp := req.Raw().URL.Query()
+		qp.Set("api-version", apiVersion)
+		req.Raw().URL.RawQuery = qp.Encode()
+	}
+	req.Raw().Header["Accept"] = []string{"application/json"}
+	if body != nil {
+		if err := runtime.MarshalAsJSON(req, body); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// do sends a request and decodes the response into result (if it isn't
+// nil). Any status other than the expected ones is an error.
+func (c *client) do(req *policy.Request, result interface{}, statusCodes ...int) (*http.Response, error) {
+	resp, err := c.pl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if !runtime.HasStatusCode(resp, statusCodes...) {
+		return nil, runtime.NewResponseError(resp)
+	}
+	if result != nil && resp.StatusCode != http.StatusNoContent {
+		if err := runtime.UnmarshalAsJSON(resp, result); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// list fetches all the pages of a list operation.
+func list[T any](ctx context.Context, c *client, u string) ([]T, error) {
+	var items []T
+	for u != "" {
+		req, err := c.newRequest(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Value    []T    `json:"value"`
+			NextLink string `json:"nextLink"`
+		}
+		if _, err := c.do(req, &page, http.StatusOK); err != nil {
+			return nil, err
+		}
+		items = append(items, page.Value...)
+		u = page.NextLink
+	}
+	return items, nil
+}
+
+// wait waits for a long-running operation to finish.
+func (c *client) wait(ctx context.Context, resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	poller, err := runtime.NewPoller[struct{}](resp, c.pl, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, nil)
+	return err
+}
+
+func (c *client) listZones(ctx context.Context) ([]zone, error) {
+	return list[zone](ctx, c, c.path())
+}
+
+func (c *client) createZone(ctx context.Context, name string) error {
+	req, err := c.newRequest(ctx, http.MethodPut, c.path(name), zone{Location: "global"})
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, nil, http.StatusOK, http.StatusCreated, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+	return c.wait(ctx, resp)
+}
+
+func (c *client) listRecordSets(ctx context.Context, zoneName string) ([]recordSet, error) {
+	return list[recordSet](ctx, c, c.path(zoneName, "ALL"))
+}
+
+func (c *client) putRecordSet(ctx context.Context, zoneName, recordType, name string, set recordSet) error {
+	req, err := c.newRequest(ctx, http.MethodPut, c.path(zoneName, recordType, name), set)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(req, nil, http.StatusOK, http.StatusCreated)
+	return err
+}
+
+func (c *client) deleteRecordSet(ctx context.Context, zoneName, recordType, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, c.path(zoneName, recordType, name), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(req, nil, http.StatusOK, http.StatusNoContent)
+	return err
+}
+
+func (c *client) listLinks(ctx context.Context, zoneName string) ([]virtualNetworkLink, error) {
+	return list[virtualNetworkLink](ctx, c, c.path(zoneName, "virtualNetworkLinks"))
+}
+
+func (c *client) putLink(ctx context.Context, zoneName string, link virtualNetworkLink) error {
+	req, err := c.newRequest(ctx, http.MethodPut, c.path(zoneName, "virtualNetworkLinks", link.Name), link)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, nil, http.StatusOK, http.StatusCreated, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+	return c.wait(ctx, resp)
+}
+
+func (c *client) deleteLink(ctx context.Context, zoneName, name string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, c.path(zoneName, "virtualNetworkLinks", name), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, nil, http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+	return c.wait(ctx, resp)
+}
@@ -0,0 +1,175 @@
+package azureprivatedns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Domain metadata for virtual network links.
+const (
+	// metaLinks is the list of virtual networks that a zone is linked
+	// to, as resource IDs separated by commas. If it is set, links are
+	// created and deleted to match it.
+	metaLinks = "vnet_links"
+	// metaRegistration is the list of virtual networks (also in
+	// vnet_links) whose virtual machines' records are registered in the
+	// zone automatically.
+	metaRegistration = "vnet_registration"
+)
+
+// parseVNets parses a comma-separated list of virtual network IDs.
+func parseVNets(s string) ([]string, error) {
+	var vnets []string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.HasPrefix(item, "/subscriptions/") || !strings.Contains(strings.ToLower(item), "/providers/microsoft.network/virtualnetworks/") {
+			return nil, fmt.Errorf("invalid virtual network %q: want a resource ID like /subscriptions/.../resourceGroups/.../providers/Microsoft.Network/virtualNetworks/name", item)
+		}
+		vnets = append(vnets, strings.TrimSuffix(item, "/"))
+	}
+	return vnets, nil
+}
+
+// linkName returns the name of a new link to a virtual network, which
+// is the name of the virtual network.
+func linkName(vnet string) string {
+	return vnet[strings.LastIndex(vnet, "/")+1:]
+}
+
+// desiredLinks returns the links that the metadata of a domain asks
+// for, or nil if links aren't managed.
+func desiredLinks(dc *models.DomainConfig) ([]virtualNetworkLink, error) {
+	value, ok := dc.Metadata[metaLinks]
+	if !ok {
+		if _, ok := dc.Metadata[metaRegistration]; ok {
+			return nil, fmt.Errorf("%s: %s requires %s", dc.Name, metaRegistration, metaLinks)
+		}
+		return nil, nil
+	}
+	vnets, err := parseVNets(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dc.Name, err)
+	}
+	registration, err := parseVNets(dc.Metadata[metaRegistration])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dc.Name, err)
+	}
+
+	linked := map[string]bool{}
+	for _, vnet := range vnets {
+		linked[strings.ToLower(vnet)] = true
+	}
+	register := map[string]bool{}
+	for _, vnet := range registration {
+		if !linked[strings.ToLower(vnet)] {
+			return nil, fmt.Errorf("%s: virtual network %s is in %s but not in %s", dc.Name, vnet, metaRegistration, metaLinks)
+		}
+		register[strings.ToLower(vnet)] = true
+	}
+
+	var links []virtualNetworkLink
+	for _, vnet := range vnets {
+		link := virtualNetworkLink{Name: linkName(vnet), Location: "global"}
+		link.Properties.VirtualNetwork.ID = vnet
+		link.Properties.RegistrationEnabled = register[strings.ToLower(vnet)]
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// diffLinks returns the links to create or update, and the links to
+// delete. Links are matched by virtual network, since Azure resource
+// IDs are case-insensitive and existing links may have any name.
+func diffLinks(existing, desired []virtualNetworkLink) (put, del []virtualNetworkLink) {
+	have := map[string]virtualNetworkLink{}
+	for _, link := range existing {
+		have[strings.ToLower(link.Properties.VirtualNetwork.ID)] = link
+	}
+	want := map[string]bool{}
+	for _, link := range desired {
+		key := strings.ToLower(link.Properties.VirtualNetwork.ID)
+		want[key] = true
+		old, ok := have[key]
+		if !ok {
+			put = append(put, link)
+			continue
+		}
+		if old.Properties.RegistrationEnabled != link.Properties.RegistrationEnabled {
+			link.Name = old.Name
+			put = append(put, link)
+		}
+	}
+	for _, link := range existing {
+		if !want[strings.ToLower(link.Properties.VirtualNetwork.ID)] {
+			del = append(del, link)
+		}
+	}
+	sort.Slice(del, func(i, j int) bool {
+		return del[i].Name < del[j].Name
+	})
+	return put, del
+}
+
+// getLinkCorrections returns the corrections that make the virtual
+// network links of a zone match the vnet_links metadata.
+func (a *azurePrivateDNSProvider) getLinkCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	desired, err := desiredLinks(dc)
+	if err != nil || desired == nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	existing, err := a.client.listLinks(ctx, dc.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	put, del := diffLinks(existing, desired)
+	names := map[string]bool{}
+	for _, link := range existing {
+		names[link.Name] = true
+	}
+	var corrections []*models.Correction
+	for _, link := range put {
+		link := link
+		kind := models.CorrectionCreate
+		if names[link.Name] {
+			// diffLinks kept the name of the link that it updates.
+			kind = models.CorrectionModify
+		}
+		msg := fmt.Sprintf("Link virtual network %s to private zone %s", link.Properties.VirtualNetwork.ID, dc.Name)
+		if link.Properties.RegistrationEnabled {
+			msg += " (with auto-registration)"
+		}
+		corrections = append(corrections, &models.Correction{
+			Msg:  msg,
+			Kind: kind,
+			F: func() error {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				defer cancel()
+				return a.client.putLink(ctx, dc.Name, link)
+			},
+		})
+	}
+	for _, link := range del {
+		link := link
+		corrections = append(corrections, &models.Correction{
+			Msg:  fmt.Sprintf("Unlink virtual network %s from private zone %s", link.Properties.VirtualNetwork.ID, dc.Name),
+			Kind: models.CorrectionDestroy,
+			F: func() error {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				defer cancel()
+				return a.client.deleteLink(ctx, dc.Name, link.Name)
+			},
+		})
+	}
+	return corrections, nil
+}
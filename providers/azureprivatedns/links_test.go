@@ -0,0 +1,84 @@
+package azureprivatedns
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+const (
+	vnet1 = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet1"
+	vnet2 = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet2"
+	vnet3 = "/subscriptions/sub/resourceGroups/other/providers/Microsoft.Network/virtualNetworks/vnet3"
+)
+
+func link(name, vnet string, registration bool) virtualNetworkLink {
+	l := virtualNetworkLink{Name: name, Location: "global"}
+	l.Properties.VirtualNetwork.ID = vnet
+	l.Properties.RegistrationEnabled = registration
+	return l
+}
+
+func Test_parseVNets(t *testing.T) {
+	got, err := parseVNets(vnet1 + ", " + vnet2 + "/,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{vnet1, vnet2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseVNets() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"vnet1", "/subscriptions/sub/resourceGroups/rg"} {
+		if _, err := parseVNets(bad); err == nil {
+			t.Errorf("parseVNets(%q): expected an error", bad)
+		}
+	}
+}
+
+func Test_desiredLinks(t *testing.T) {
+	dc := &models.DomainConfig{Name: "example.internal", Metadata: map[string]string{
+		metaLinks:        vnet1 + "," + vnet2,
+		metaRegistration: vnet2,
+	}}
+	got, err := desiredLinks(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []virtualNetworkLink{link("vnet1", vnet1, false), link("vnet2", vnet2, true)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("desiredLinks() = %+v, want %+v", got, want)
+	}
+
+	dc.Metadata = map[string]string{}
+	if got, err := desiredLinks(dc); got != nil || err != nil {
+		t.Errorf("desiredLinks() without metadata = %v, %v", got, err)
+	}
+
+	dc.Metadata = map[string]string{metaLinks: vnet1, metaRegistration: vnet2}
+	if _, err := desiredLinks(dc); err == nil {
+		t.Errorf("expected an error for registration on an unlinked virtual network")
+	}
+}
+
+func Test_diffLinks(t *testing.T) {
+	existing := []virtualNetworkLink{
+		link("a-link", vnet1, false),
+		link("b-link", vnet2, false),
+	}
+	desired := []virtualNetworkLink{
+		link("vnet2", vnet2, true),
+		link("vnet3", vnet3, false),
+	}
+	put, del := diffLinks(existing, desired)
+	if want := []virtualNetworkLink{link("b-link", vnet2, true), link("vnet3", vnet3, false)}; !reflect.DeepEqual(put, want) {
+		t.Errorf("put = %+v, want %+v", put, want)
+	}
+	if want := []virtualNetworkLink{link("a-link", vnet1, false)}; !reflect.DeepEqual(del, want) {
+		t.Errorf("del = %+v, want %+v", del, want)
+	}
+
+	put, del = diffLinks(existing, existing)
+	if len(put) != 0 || len(del) != 0 {
+		t.Errorf("expected no changes, got %v and %v", put, del)
+	}
+}
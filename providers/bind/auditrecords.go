@@ -1,10 +1,20 @@
 package bind
 
-import "github.com/StackExchange/dnscontrol/v3/models"
+import (
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/rejectif"
+)
 
 // AuditRecords returns a list of errors corresponding to the records
 // that aren't supported by this provider.  If all records are
-// supported, an empty list is returned.
+// supported, an empty list is returned. Records that are risky, but
+// supported, are returned as rejectif.AuditWarn.
 func AuditRecords(records []*models.RecordConfig) []error {
-	return nil
+	a := rejectif.Auditor{}
+
+	a.AddWarn("*", rejectif.TTLIsVeryLow)
+
+	a.AddWarn("TXT", rejectif.TxtSpfNearLookupLimit)
+
+	return a.Audit(records)
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/metaschema"
 	"github.com/StackExchange/dnscontrol/v3/pkg/prettyzone"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
 	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
@@ -32,17 +33,33 @@ import (
 	"github.com/miekg/dns"
 )
 
+// metaSchema lists the metadata that initBind() parses into bindProvider.
+var metaSchema = metaschema.Schema{
+	"default_ns":        metaschema.StringList,
+	"default_soa":       metaschema.Object,
+	"relative_names":    metaschema.Bool,
+	"preserve_comments": metaschema.Bool,
+	"serial_scheme":     metaschema.String,
+}
+
+var logger = printer.Module("providers.bind")
+
 var features = providers.DocumentationNotes{
 	providers.CanAutoDNSSEC:          providers.Can("Just writes out a comment indicating DNSSEC was requested"),
 	providers.CanGetZones:            providers.Can(),
 	providers.CanUseCAA:              providers.Can(),
 	providers.CanUseDS:               providers.Can(),
+	providers.CanUseHTTPS:            providers.Can(),
+	providers.CanUseLOC:              providers.Can(),
 	providers.CanUseNAPTR:            providers.Can(),
 	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseRFC3597:          providers.Can(),
 	providers.CanUseSOA:              providers.Can(),
 	providers.CanUseSRV:              providers.Can(),
 	providers.CanUseSSHFP:            providers.Can(),
+	providers.CanUseSVCB:             providers.Can(),
 	providers.CanUseTLSA:             providers.Can(),
+	providers.CanUseURI:              providers.Can(),
 	providers.CantUseNOPURGE:         providers.Cannot(),
 	providers.DocCreateDomains:       providers.Can("Driver just maintains list of zone files. It should automatically add missing ones."),
 	providers.DocDualHost:            providers.Can(),
@@ -68,6 +85,11 @@ func initBind(config map[string]string, providermeta json.RawMessage) (providers
 			return nil, err
 		}
 	}
+	switch api.SerialScheme {
+	case "", serialDate, serialEpoch, serialIncrement:
+	default:
+		return nil, fmt.Errorf("serial_scheme (%v) must be %q, %q or %q", api.SerialScheme, serialDate, serialEpoch, serialIncrement)
+	}
 	var nss []string
 	for i, ns := range api.DefaultNS {
 		if ns == "" {
@@ -91,7 +113,7 @@ func init() {
 		Initializer:   initBind,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("BIND", fns, features)
+	providers.RegisterDomainServiceProviderType("BIND", fns, features, txtutil.SplitLong, metaSchema)
 }
 
 // SoaDefaults contains the parts of the default SOA settings.
@@ -112,13 +134,17 @@ func (s SoaDefaults) String() string {
 
 // bindProvider is the provider handle for the bindProvider driver.
 type bindProvider struct {
-	DefaultNS      []string    `json:"default_ns"`
-	DefaultSoa     SoaDefaults `json:"default_soa"`
-	nameservers    []*models.Nameserver
-	directory      string
-	filenameformat string
-	zonefile       string // Where the zone data is expected
-	zoneFileFound  bool   // Did the zonefile exist?
+	DefaultNS        []string    `json:"default_ns"`
+	DefaultSoa       SoaDefaults `json:"default_soa"`
+	RelativeNames    bool        `json:"relative_names"`
+	PreserveComments bool        `json:"preserve_comments"`
+	SerialScheme     string      `json:"serial_scheme"`
+	nameservers      []*models.Nameserver
+	directory        string
+	filenameformat   string
+	zonefile         string            // Where the zone data is expected
+	zoneFileFound    bool              // Did the zonefile exist?
+	recordComments   map[string]string // The comments in the zonefile, by commentKey()
 }
 
 // GetNameservers returns the nameservers for a domain.
@@ -169,13 +195,14 @@ func (c *bindProvider) GetZoneRecords(domain string) (models.Records, error) {
 	if os.IsNotExist(err) {
 		// If the file doesn't exist, that's not an error. Just informational.
 		c.zoneFileFound = false
-		fmt.Fprintf(os.Stderr, "File does not yet exist: %q (will create)\n", c.zonefile)
+		logger.Printf("File does not yet exist: %q (will create)\n", c.zonefile)
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("can't open %s: %w", c.zonefile, err)
 	}
 	c.zoneFileFound = true
+	c.recordComments = map[string]string{}
 
 	zp := dns.NewZoneParser(strings.NewReader(string(content)), domain, c.zonefile)
 
@@ -184,6 +211,9 @@ func (c *bindProvider) GetZoneRecords(domain string) (models.Records, error) {
 		if err != nil {
 			return nil, err
 		}
+		if comment := zp.Comment(); comment != "" {
+			c.recordComments[commentKey(&rec)] = comment
+		}
 		foundRecords = append(foundRecords, &rec)
 	}
 
@@ -232,7 +262,7 @@ func (c *bindProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.
 			break
 		}
 	}
-	soaRec, nextSerial := makeSoa(dc.Name, &c.DefaultSoa, foundSoa, desiredSoa)
+	soaRec, nextSerial := makeSoa(dc.Name, &c.DefaultSoa, foundSoa, desiredSoa, c.SerialScheme)
 	if desiredSoa == nil {
 		dc.Records = append(dc.Records, soaRec)
 		desiredSoa = dc.Records[len(dc.Records)-1]
@@ -242,9 +272,9 @@ func (c *bindProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.
 
 	// Normalize
 	models.PostProcessRecords(foundRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
-	changes := false
+	var kind models.CorrectionKind // 0 if nothing changes.
 	var msg string
 
 	if !diff2.EnableDiff2 {
@@ -259,24 +289,22 @@ func (c *bindProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.
 		// Print a list of changes. Generate an actual change that is the zone
 
 		for _, i := range create {
-			changes = true
 			if c.zoneFileFound {
 				fmt.Fprintln(buf, i)
 			}
 		}
 		for _, i := range del {
-			changes = true
 			if c.zoneFileFound {
 				fmt.Fprintln(buf, i)
 			}
 		}
 		for _, i := range mod {
-			changes = true
 			if c.zoneFileFound {
 				fmt.Fprintln(buf, i)
 			}
 		}
 
+		kind = create.Kind().Max(del.Kind()).Max(mod.Kind())
 		if c.zoneFileFound {
 			msg = fmt.Sprintf("GENERATE_ZONEFILE: '%s'. Changes:\n%s", dc.Name, buf)
 		} else {
@@ -286,25 +314,26 @@ func (c *bindProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.
 	} else {
 
 		var msgs []string
-		msgs, changes, err = diff2.ByZone(foundRecords, dc, nil)
+		msgs, kind, err = diff2.ByZone(foundRecords, dc, nil)
 		if err != nil {
 			return nil, err
 		}
-		//fmt.Printf("DEBUG: BIND changes=%v\n", changes)
+		//fmt.Printf("DEBUG: BIND kind=%v\n", kind)
 		msg = strings.Join(msgs, "\n")
 
 	}
 
 	var corrections []*models.Correction
-	//fmt.Printf("DEBUG: BIND changes=%v\n", changes)
-	if changes {
+	//fmt.Printf("DEBUG: BIND kind=%v\n", kind)
+	if kind != 0 {
 
 		// We only change the serial number if there is a change.
 		desiredSoa.SoaSerial = nextSerial
 
 		corrections = append(corrections,
 			&models.Correction{
-				Msg: msg,
+				Msg:  msg,
+				Kind: kind,
 				F: func() error {
 					printer.Printf("WRITING ZONEFILE: %v\n", c.zonefile)
 					zf, err := os.Create(c.zonefile)
@@ -314,7 +343,12 @@ func (c *bindProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.
 					// Beware that if there are any fake types, then they will
 					// be commented out on write, but we don't reverse that when
 					// reading, so there will be a diff on every invocation.
-					err = prettyzone.WriteZoneFileRC(zf, dc.Records, dc.Name, 0, comments)
+					if c.PreserveComments {
+						c.restoreComments(dc.Records)
+					}
+					z := prettyzone.PrettySort(dc.Records, dc.Name, 0, comments)
+					z.Relative = c.RelativeNames
+					err = z.Write(zf)
 
 					if err != nil {
 						return fmt.Errorf("failed WriteZoneFile: %w", err)
@@ -330,3 +364,24 @@ func (c *bindProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.
 
 	return corrections, nil
 }
+
+// commentKey identifies a record for the purpose of matching the
+// comments in the old zonefile to the records in the new one.
+func commentKey(rc *models.RecordConfig) string {
+	return strings.ToLower(rc.GetLabelFQDN()) + " " + rc.Type + " " + strings.ToLower(rc.GetTargetCombined())
+}
+
+// restoreComments copies the comments of the records in the old
+// zonefile to the same records in recs.
+func (c *bindProvider) restoreComments(recs models.Records) {
+	for _, rc := range recs {
+		comment, ok := c.recordComments[commentKey(rc)]
+		if !ok {
+			continue
+		}
+		if rc.Metadata == nil {
+			rc.Metadata = map[string]string{}
+		}
+		rc.Metadata[prettyzone.CommentMetadataKey] = comment
+	}
+}
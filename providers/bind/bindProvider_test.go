@@ -0,0 +1,85 @@
+package bind
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func Test_relativeNamesAndComments(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2022-11-02T10:00:00Z")
+	nowFunc = func() time.Time { return now }
+	defer func() { nowFunc = time.Now }()
+
+	dir := t.TempDir()
+	old := `$TTL 300
+@                IN SOA   ns1.example.com. hostmaster.example.com. 7 3600 600 604800 1440
+                 IN MX    10 mx.example.com. ; primary mail
+www              IN A     1.2.3.4 ; web server
+`
+	if err := os.WriteFile(filepath.Join(dir, "example.com.zone"), []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta := json.RawMessage(`{"relative_names": true, "preserve_comments": true, "serial_scheme": "increment"}`)
+	p, err := initBind(map[string]string{"directory": dir}, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dc := &models.DomainConfig{Name: "example.com", UniqueName: "example.com"}
+	for _, r := range []struct{ label, rtype, target string }{
+		{"@", "MX", "mx.example.com."},
+		{"www", "A", "1.2.3.4"},
+		{"ftp", "CNAME", "www.example.com."},
+	} {
+		rc := &models.RecordConfig{Type: r.rtype, TTL: 300}
+		rc.SetLabel(r.label, "example.com")
+		if r.rtype == "MX" {
+			rc.SetTargetMX(10, r.target)
+		} else {
+			rc.SetTarget(r.target)
+		}
+		dc.Records = append(dc.Records, rc)
+	}
+
+	corrections, err := p.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 1 {
+		t.Fatalf("got %d corrections, want 1", len(corrections))
+	}
+	if err := corrections[0].F(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "example.com.zone"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Skip the "generated with" comment.
+	lines := strings.Split(string(content), "\n")
+	got := strings.Join(append(lines[:2], lines[3:]...), "\n")
+	want := `$TTL 300
+$ORIGIN example.com.
+@                IN SOA   ns1.example.com. hostmaster.example.com. 8 3600 600 604800 1440
+                 IN MX    10 mx ; primary mail
+ftp              IN CNAME www
+www              IN A     1.2.3.4 ; web server
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func Test_initBindSerialScheme(t *testing.T) {
+	if _, err := initBind(map[string]string{}, json.RawMessage(`{"serial_scheme": "weekly"}`)); err == nil {
+		t.Errorf("expected an error for an unknown serial_scheme")
+	}
+}
@@ -3,7 +3,6 @@ package bind
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -12,7 +11,7 @@ import (
 // makeFileName uses format to generate a zone's filename.  See the
 func makeFileName(format, uniquename, domain, tag string) string {
 	if format == "" {
-		fmt.Fprintf(os.Stderr, "BUG: makeFileName called with null format\n")
+		logger.Errorf("BUG: makeFileName called with null format\n")
 		return uniquename
 	}
 
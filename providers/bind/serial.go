@@ -8,6 +8,36 @@ import (
 
 var nowFunc = time.Now
 
+// The SOA serial number schemes that can be selected with the
+// "serial_scheme" metadata.
+const (
+	serialDate      = "date"      // yyyymmddvv (the default)
+	serialEpoch     = "epoch"     // Unix time
+	serialIncrement = "increment" // oldSerial + 1
+)
+
+// nextSerial returns the serial number that follows oldSerial in the
+// given scheme. Like generateSerial, it never goes backwards and never
+// returns 0.
+func nextSerial(scheme string, oldSerial uint32) uint32 {
+	var newSerial uint32
+	switch scheme {
+	case serialEpoch:
+		newSerial = uint32(nowFunc().Unix())
+		if oldSerial >= newSerial {
+			newSerial = oldSerial + 1
+		}
+	case serialIncrement:
+		newSerial = oldSerial + 1
+	default:
+		return generateSerial(oldSerial)
+	}
+	if newSerial == 0 {
+		newSerial = 1
+	}
+	return newSerial
+}
+
 // generateSerial takes an old SOA serial number and increments it.
 func generateSerial(oldSerial uint32) uint32 {
 	// Serial numbers are in the format yyyymmddvv
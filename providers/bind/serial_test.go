@@ -52,3 +52,34 @@ func Test_generate_serial_1(t *testing.T) {
 		}
 	}
 }
+
+func Test_nextSerial(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2022-11-02T10:00:00Z") // 1667383200
+	nowFunc = func() time.Time { return now }
+	defer func() { nowFunc = time.Now }()
+
+	var tests = []struct {
+		Scheme   string
+		Given    uint32
+		Expected uint32
+	}{
+		{"", 2022110205, 2022110206},
+		{"date", 1, 2022110200},
+		{"epoch", 1, 1667383200},
+		// A date serial is larger than the current Unix time, and serial
+		// numbers must not go backwards:
+		{"epoch", 2022110200, 2022110201},
+		{"epoch", 1667383200, 1667383201},
+		{"epoch", 4000000000, 4000000001},
+		{"increment", 0, 1},
+		{"increment", 41, 42},
+		{"increment", 4294967295, 1},
+	}
+
+	for i, tst := range tests {
+		found := nextSerial(tst.Scheme, tst.Given)
+		if tst.Expected != found {
+			t.Errorf("Test:%d/%q/%v: Expected (%d) got (%d)\n", i, tst.Scheme, tst.Given, tst.Expected, found)
+		}
+	}
+}
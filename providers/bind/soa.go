@@ -2,7 +2,7 @@ package bind
 
 import "github.com/StackExchange/dnscontrol/v3/models"
 
-func makeSoa(origin string, defSoa *SoaDefaults, existing, desired *models.RecordConfig) (*models.RecordConfig, uint32) {
+func makeSoa(origin string, defSoa *SoaDefaults, existing, desired *models.RecordConfig, serialScheme string) (*models.RecordConfig, uint32) {
 	// Create a SOA record.  Take data from desired, existing, default,
 	// or hardcoded defaults.
 	soaRec := models.RecordConfig{}
@@ -30,7 +30,7 @@ func makeSoa(origin string, defSoa *SoaDefaults, existing, desired *models.Recor
 		firstNonZero(desired.SoaMinttl, existing.SoaMinttl, defSoa.Minttl, 1440),
 	)
 
-	return &soaRec, generateSerial(soaRec.SoaSerial)
+	return &soaRec, nextSerial(serialScheme, soaRec.SoaSerial)
 }
 
 func firstNonNull(items ...string) string {
@@ -101,7 +101,7 @@ func Test_makeSoa(t *testing.T) {
 		tst.expectedSoa.SetLabel("@", origin)
 		tst.expectedSoa.Type = "SOA"
 
-		r1, r2 := makeSoa(origin, tst.def, tst.existing, tst.desired)
+		r1, r2 := makeSoa(origin, tst.def, tst.existing, tst.desired, "")
 		if !areEqualSoa(r1, tst.expectedSoa) {
 			t.Fatalf("Test %d soa:\nExpected (%v)\n     got (%v)\n", i, tst.expectedSoa.String(), r1.String())
 		}
@@ -2,7 +2,16 @@
 
 package providers
 
-import "log"
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/aliastypes"
+	"github.com/StackExchange/dnscontrol/v3/pkg/metaschema"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ttlutil"
+	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
+)
 
 // Capability is a bitmasked set of "features" that a provider supports. Only use constants from this package.
 type Capability uint32
@@ -17,6 +26,13 @@ const (
 	// so folks can ask for that.
 	CanAutoDNSSEC Capability = iota
 
+	// CanConcurrentlyModify indicates that the corrections returned by the
+	// provider's GetDomainCorrections set their Label, and that only the
+	// corrections at the same label depend on each other, so that push
+	// may run the corrections at different labels at the same time. The
+	// provider must be safe to use from several goroutines.
+	CanConcurrentlyModify
+
 	// CanGetZones indicates the provider supports the get-zones subcommand.
 	CanGetZones
 
@@ -40,12 +56,22 @@ const (
 	// only for children records, not at the root of the zone.
 	CanUseDSForChildren
 
+	// CanUseHTTPS indicates the provider can handle HTTPS records
+	CanUseHTTPS
+
+	// CanUseLOC indicates the provider can handle LOC records
+	CanUseLOC
+
 	// CanUseNAPTR indicates the provider can handle NAPTR records
 	CanUseNAPTR
 
 	// CanUsePTR indicates the provider can handle PTR records
 	CanUsePTR
 
+	// CanUseRFC3597 indicates the provider can handle records of unknown
+	// types in the generic format of RFC 3597 (TYPEnnn \# len hexdata)
+	CanUseRFC3597
+
 	// CanUseRoute53Alias indicates the provider support the specific R53_ALIAS records that only the Route53 provider supports
 	CanUseRoute53Alias
 
@@ -58,9 +84,20 @@ const (
 	// CanUseSSHFP indicates the provider can handle SSHFP records
 	CanUseSSHFP
 
+	// CanUseSVCB indicates the provider can handle SVCB records
+	CanUseSVCB
+
 	// CanUseTLSA indicates the provider can handle TLSA records
 	CanUseTLSA
 
+	// CanUseTXTMulti indicates the provider can handle TXT records with
+	// multiple strings, either as they are or by joining them. It is set
+	// from the txtutil.Policy of the provider.
+	CanUseTXTMulti
+
+	// CanUseURI indicates the provider can handle URI records
+	CanUseURI
+
 	// CantUseNOPURGE indicates NO_PURGE is broken for this provider. To make it
 	// work would require complex emulation of an incremental update mechanism,
 	// so it is easier to simply mark this feature as not working for this
@@ -79,6 +116,48 @@ const (
 
 var providerCapabilities = map[string]map[Capability]bool{}
 
+var txtPolicies = map[string]txtutil.Policy{}
+
+var ttlLimits = map[string]ttlutil.Limits{}
+
+var aliasPolicies = map[string]aliastypes.Policy{}
+
+var metaSchemas = map[string]metaschema.Schema{}
+
+// ValidateMetadata checks the metadata given to NewDnsProvider() for a
+// provider of type pType against the schema that the provider
+// registered. Providers without a schema accept any metadata.
+func ValidateMetadata(pType string, meta json.RawMessage) error {
+	s, ok := metaSchemas[pType]
+	if !ok {
+		return nil
+	}
+	if err := s.Validate(meta); err != nil {
+		return fmt.Errorf("invalid metadata for %s: %w", pType, err)
+	}
+	return nil
+}
+
+// GetTTLLimits returns the TTL limits of a provider, if it registered
+// them.
+func GetTTLLimits(pType string) (ttlutil.Limits, bool) {
+	l, ok := ttlLimits[pType]
+	return l, ok
+}
+
+// GetAliasPolicy returns the ALIAS policy of a provider, if it
+// registered one.
+func GetAliasPolicy(pType string) (aliastypes.Policy, bool) {
+	p, ok := aliasPolicies[pType]
+	return p, ok
+}
+
+// GetTXTPolicy returns the TXT policy of a provider, if it registered one.
+func GetTXTPolicy(pType string) (txtutil.Policy, bool) {
+	p, ok := txtPolicies[pType]
+	return p, ok
+}
+
 // ProviderHasCapability returns true if provider has capability.
 func ProviderHasCapability(pType string, cap Capability) bool {
 	if providerCapabilities[pType] == nil {
@@ -98,7 +177,20 @@ type DocumentationNote struct {
 // DocumentationNotes is a full list of notes for a single provider
 type DocumentationNotes map[Capability]*DocumentationNote
 
-// ProviderMetadata is a common interface for DocumentationNotes and Capability to be used interchangeably
+// ProviderMetadata is what a provider registers along with its
+// initializer (see RegisterDomainServiceProviderType). It is one of:
+//
+//   - Capability, which the provider has;
+//   - DocumentationNotes, its capabilities with notes for the docs;
+//   - txtutil.Policy, how its API stores TXT records;
+//   - ttlutil.Limits, the TTLs its API accepts;
+//   - aliastypes.Policy, how its API serves ALIAS records;
+//   - metaschema.Schema, the metadata its NewDnsProvider() accepts.
+//
+// Validation uses them to reject, or warn about, the configurations
+// that the provider can't store as they are. The provider applies the
+// policies and limits itself, by calling their Apply() method once in
+// GetDomainCorrections().
 type ProviderMetadata interface{}
 
 // Notes is a collection of all documentation notes, keyed by provider type
@@ -120,6 +212,16 @@ func unwrapProviderCapabilities(pName string, meta []ProviderMetadata) {
 				Notes[pName][k] = v
 				providerCapabilities[pName][k] = v.HasFeature
 			}
+		case txtutil.Policy:
+			txtPolicies[pName] = x
+			providerCapabilities[pName][CanUseTXTMulti] = x.Multi || x.AutoJoin
+		case ttlutil.Limits:
+			ttlLimits[pName] = x
+		case aliastypes.Policy:
+			aliasPolicies[pName] = x
+			providerCapabilities[pName][CanUseAlias] = true
+		case metaschema.Schema:
+			metaSchemas[pName] = x
 		default:
 			log.Fatalf("Unrecognized ProviderMetadata type: %T", pm)
 		}
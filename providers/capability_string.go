@@ -9,29 +9,36 @@ func _() {
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
 	_ = x[CanAutoDNSSEC-0]
-	_ = x[CanGetZones-1]
-	_ = x[CanUseAKAMAICDN-2]
-	_ = x[CanUseAlias-3]
-	_ = x[CanUseAzureAlias-4]
-	_ = x[CanUseCAA-5]
-	_ = x[CanUseDS-6]
-	_ = x[CanUseDSForChildren-7]
-	_ = x[CanUseNAPTR-8]
-	_ = x[CanUsePTR-9]
-	_ = x[CanUseRoute53Alias-10]
-	_ = x[CanUseSOA-11]
-	_ = x[CanUseSRV-12]
-	_ = x[CanUseSSHFP-13]
-	_ = x[CanUseTLSA-14]
-	_ = x[CantUseNOPURGE-15]
-	_ = x[DocCreateDomains-16]
-	_ = x[DocDualHost-17]
-	_ = x[DocOfficiallySupported-18]
+	_ = x[CanConcurrentlyModify-1]
+	_ = x[CanGetZones-2]
+	_ = x[CanUseAKAMAICDN-3]
+	_ = x[CanUseAlias-4]
+	_ = x[CanUseAzureAlias-5]
+	_ = x[CanUseCAA-6]
+	_ = x[CanUseDS-7]
+	_ = x[CanUseDSForChildren-8]
+	_ = x[CanUseHTTPS-9]
+	_ = x[CanUseLOC-10]
+	_ = x[CanUseNAPTR-11]
+	_ = x[CanUsePTR-12]
+	_ = x[CanUseRFC3597-13]
+	_ = x[CanUseRoute53Alias-14]
+	_ = x[CanUseSOA-15]
+	_ = x[CanUseSRV-16]
+	_ = x[CanUseSSHFP-17]
+	_ = x[CanUseSVCB-18]
+	_ = x[CanUseTLSA-19]
+	_ = x[CanUseTXTMulti-20]
+	_ = x[CanUseURI-21]
+	_ = x[CantUseNOPURGE-22]
+	_ = x[DocCreateDomains-23]
+	_ = x[DocDualHost-24]
+	_ = x[DocOfficiallySupported-25]
 }
 
-const _Capability_name = "CanAutoDNSSECCanGetZonesCanUseAKAMAICDNCanUseAliasCanUseAzureAliasCanUseCAACanUseDSCanUseDSForChildrenCanUseNAPTRCanUsePTRCanUseRoute53AliasCanUseSOACanUseSRVCanUseSSHFPCanUseTLSACantUseNOPURGEDocCreateDomainsDocDualHostDocOfficiallySupported"
+const _Capability_name = "CanAutoDNSSECCanConcurrentlyModifyCanGetZonesCanUseAKAMAICDNCanUseAliasCanUseAzureAliasCanUseCAACanUseDSCanUseDSForChildrenCanUseHTTPSCanUseLOCCanUseNAPTRCanUsePTRCanUseRFC3597CanUseRoute53AliasCanUseSOACanUseSRVCanUseSSHFPCanUseSVCBCanUseTLSACanUseTXTMultiCanUseURICantUseNOPURGEDocCreateDomainsDocDualHostDocOfficiallySupported"
 
-var _Capability_index = [...]uint8{0, 13, 24, 39, 50, 66, 75, 83, 102, 113, 122, 140, 149, 158, 169, 179, 193, 209, 220, 242}
+var _Capability_index = [...]uint16{0, 13, 34, 45, 60, 71, 87, 96, 104, 123, 134, 143, 154, 163, 176, 194, 203, 212, 223, 233, 243, 257, 266, 280, 296, 307, 329}
 
 func (i Capability) String() string {
 	if i >= Capability(len(_Capability_index)-1) {
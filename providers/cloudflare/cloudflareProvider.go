@@ -3,18 +3,20 @@ package cloudflare
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"net"
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/aliastypes"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/metaschema"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
 	"github.com/StackExchange/dnscontrol/v3/pkg/transform"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ttlutil"
+	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	"github.com/cloudflare/cloudflare-go"
-	"github.com/miekg/dns/dnsutil"
 )
 
 /*
@@ -36,6 +38,8 @@ Domain level metadata available:
    - ip_conversions
 */
 
+var logger = printer.Module("providers.cloudflare")
+
 var features = providers.DocumentationNotes{
 	providers.CanGetZones:            providers.Can(),
 	providers.CanUseAlias:            providers.Can("CF automatically flattens CNAME records into A records dynamically"),
@@ -50,15 +54,39 @@ var features = providers.DocumentationNotes{
 	providers.DocOfficiallySupported: providers.Can(),
 }
 
+// Cloudflare's API only supports one TXT string of any non-zero length.
+// When serving the DNS record, it splits strings >255 octets into
+// individual segments of 255 each. However that is hidden from the API.
+// TXT records with multiple strings are rejected rather than joined, as
+// joining them would change the record.
+var txtPolicy = txtutil.Policy{}
+
+// The lowest TTL is 120, except for 1, which means "automatic".
+var ttlLimits = ttlutil.Limits{Min: 120, Keep: []uint32{1}}
+
+// metaSchema lists the metadata that newCloudflare() parses.
+var metaSchema = metaschema.Schema{
+	"ip_conversions":      metaschema.String,
+	"ignored_labels":      metaschema.StringList,
+	"manage_redirects":    metaschema.Bool,
+	"manage_workers":      metaschema.Bool,
+	"manage_rulesets":     metaschema.Bool,
+	"manage_spectrum":     metaschema.Bool,
+	"fail_on_ns_conflict": metaschema.Bool,
+	"ignore_ns_conflict":  metaschema.Bool,
+}
+
 func init() {
 	fns := providers.DspFuncs{
 		Initializer:   newCloudflare,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("CLOUDFLAREAPI", fns, features)
+	providers.RegisterDomainServiceProviderType("CLOUDFLAREAPI", fns, features, txtPolicy, ttlLimits, aliastypes.FlattenedCNAME, metaSchema)
 	providers.RegisterCustomRecordType("CF_REDIRECT", "CLOUDFLAREAPI", "")
 	providers.RegisterCustomRecordType("CF_TEMP_REDIRECT", "CLOUDFLAREAPI", "")
 	providers.RegisterCustomRecordType("CF_WORKER_ROUTE", "CLOUDFLAREAPI", "")
+	providers.RegisterCustomRecordType("CF_RULESET", "CLOUDFLAREAPI", "")
+	providers.RegisterCustomRecordType("CF_SPECTRUM_APP", "CLOUDFLAREAPI", "")
 }
 
 // cloudflareProvider is the handle for API calls.
@@ -66,20 +94,15 @@ type cloudflareProvider struct {
 	domainIndex     map[string]string // Call c.fetchDomainList() to populate before use.
 	nameservers     map[string][]string
 	ipConversions   []transform.IPConversion
-	ignoredLabels   []string
 	manageRedirects bool
 	manageWorkers   bool
-	cfClient        *cloudflare.API
-}
-
-func labelMatches(label string, matches []string) bool {
-	printer.Debugf("DEBUG: labelMatches(%#v, %#v)\n", label, matches)
-	for _, tst := range matches {
-		if label == tst {
-			return true
-		}
-	}
-	return false
+	manageRulesets  bool
+	manageSpectrum  bool
+	// What to do with the NS records at the apex that aren't
+	// Cloudflare's: see checkNSModifications.
+	failOnNSConflict bool
+	ignoreNSConflict bool
+	cfClient         *cloudflare.API
 }
 
 // GetNameservers returns the nameservers for a domain.
@@ -166,24 +189,15 @@ func (c *cloudflareProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 	if err := c.preprocessConfig(dc); err != nil {
 		return nil, err
 	}
-	for i := len(records) - 1; i >= 0; i-- {
-		rec := records[i]
-		// Delete ignore labels
-		if labelMatches(dnsutil.TrimDomainName(rec.Original.(cloudflare.DNSRecord).Name, dc.Name), c.ignoredLabels) {
-			printer.Debugf("ignored_label: %s\n", rec.Original.(cloudflare.DNSRecord).Name)
-			records = append(records[:i], records[i+1:]...)
-		}
-	}
-
 	if c.manageRedirects {
 		prs, err := c.getPageRules(id, dc.Name)
-		//printer.Printf("GET PAGE RULES:\n")
-		//for i, p := range prs {
-		//	printer.Printf("%03d: %q\n", i, p.GetTargetField())
-		//}
 		if err != nil {
 			return nil, err
 		}
+		logger.Debugf("GET PAGE RULES:\n")
+		for i, p := range prs {
+			logger.Debugf("%03d: %q\n", i, p.GetTargetField())
+		}
 		records = append(records, prs...)
 	}
 
@@ -195,33 +209,39 @@ func (c *cloudflareProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 		records = append(records, wrs...)
 	}
 
-	for _, rec := range dc.Records {
-		if rec.Type == "ALIAS" {
-			rec.Type = "CNAME"
+	if c.manageRulesets {
+		rrs, err := c.getRulesets(id, dc.Name)
+		if err != nil {
+			return nil, err
 		}
+		records = append(records, rrs...)
+	}
+
+	if c.manageSpectrum {
+		srs, err := c.getSpectrumApps(id, dc.Name)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, srs...)
+	}
+
+	aliastypes.FlattenedCNAME.Apply(dc.Records)
+	for _, rec := range dc.Records {
 		// As per CF-API documentation proxied records are always forced to have a TTL of 1.
 		// When not forcing this property change here, dnscontrol tries each time to update
 		// the TTL of a record which simply cannot be changed anyway.
 		if rec.Metadata[metaProxy] != "off" {
 			rec.TTL = 1
 		}
-		if labelMatches(rec.GetLabel(), c.ignoredLabels) {
-			log.Fatalf("FATAL: dnsconfig contains label that matches ignored_labels: %#v is in %v)\n", rec.GetLabel(), c.ignoredLabels)
-		}
 	}
 
-	checkNSModifications(dc)
+	if err := c.checkNSModifications(dc); err != nil {
+		return nil, err
+	}
 
 	// Normalize
 	models.PostProcessRecords(records)
-	//txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
-	// Don't split.
-	// Cloudflare's API only supports one TXT string of any non-zero length. No
-	// multiple strings (TXTMulti).
-	// When serving the DNS record, it splits strings >255 octets into
-	// individual segments of 255 each. However that is hidden from the API.
-	// Therefore, whether the string is 1 octet or thousands, just store it as
-	// one string in the first element of .TxtStrings.
+	txtPolicy.Apply(dc.Records)
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 || true { // Remove "|| true" when diff2 version arrives
@@ -234,80 +254,107 @@ func (c *cloudflareProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 
 		corrections := []*models.Correction{}
 
-		for _, d := range del {
+		// Order makes the NS records of a label exist whenever its DS
+		// records do.
+		for _, d := range diff.Order(create, del, mod) {
+			rec := d.Desired
 			ex := d.Existing
-			if ex.Type == "PAGE_RULE" {
+			switch {
+			case rec == nil && ex.Type == "PAGE_RULE":
 				corrections = append(corrections, &models.Correction{
-					Msg: d.String(),
-					F:   func() error { return c.deletePageRule(ex.Original.(cloudflare.PageRule).ID, id) },
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.deletePageRule(ex.Original.(cloudflare.PageRule).ID, id) },
 				})
-			} else if ex.Type == "WORKER_ROUTE" {
+			case rec == nil && ex.Type == "WORKER_ROUTE":
 				corrections = append(corrections, &models.Correction{
-					Msg: d.String(),
-					F:   func() error { return c.deleteWorkerRoute(ex.Original.(cloudflare.WorkerRoute).ID, id) },
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.deleteWorkerRoute(ex.Original.(cloudflare.WorkerRoute).ID, id) },
 				})
-			} else {
-				corr := c.deleteRec(ex.Original.(cloudflare.DNSRecord), id)
-				// DS records must always have a corresponding NS record.
-				// Therefore, we remove DS records before any NS records.
-				if d.Existing.Type == "DS" {
-					corrections = append([]*models.Correction{corr}, corrections...)
-				} else {
-					corrections = append(corrections, corr)
-				}
-			}
-		}
-		for _, d := range create {
-			des := d.Desired
-			if des.Type == "PAGE_RULE" {
+			case rec == nil && ex.Type == "RULESET":
 				corrections = append(corrections, &models.Correction{
-					Msg: d.String(),
-					F:   func() error { return c.createPageRule(id, des.GetTargetField()) },
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.deleteRule(ex.Original.(cfRuleRef), id) },
 				})
-			} else if des.Type == "WORKER_ROUTE" {
+			case rec == nil && ex.Type == "SPECTRUM_APP":
 				corrections = append(corrections, &models.Correction{
-					Msg: d.String(),
-					F:   func() error { return c.createWorkerRoute(id, des.GetTargetField()) },
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.deleteSpectrumApp(ex.Original.(string), id) },
 				})
-			} else {
-				corr := c.createRec(des, id)
-				// DS records must always have a corresponding NS record.
-				// Therefore, we create NS records before any DS records.
-				if d.Desired.Type == "NS" {
-					corrections = append(corr, corrections...)
-				} else {
-					corrections = append(corrections, corr...)
-				}
-			}
-		}
-
-		for _, d := range mod {
-			rec := d.Desired
-			ex := d.Existing
-			if rec.Type == "PAGE_RULE" {
+			case rec == nil:
+				corrections = append(corrections, c.deleteRec(ex.Original.(cloudflare.DNSRecord), id))
+			case ex == nil && rec.Type == "PAGE_RULE":
+				corrections = append(corrections, &models.Correction{
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.createPageRule(id, rec.GetTargetField()) },
+				})
+			case ex == nil && rec.Type == "WORKER_ROUTE":
+				corrections = append(corrections, &models.Correction{
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.createWorkerRoute(id, rec.GetTargetField()) },
+				})
+			case ex == nil && rec.Type == "RULESET":
+				corrections = append(corrections, &models.Correction{
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.createRule(id, rec.GetTargetField()) },
+				})
+			case ex == nil && rec.Type == "SPECTRUM_APP":
 				corrections = append(corrections, &models.Correction{
-					Msg: d.String(),
-					F:   func() error { return c.updatePageRule(ex.Original.(cloudflare.PageRule).ID, id, rec.GetTargetField()) },
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.createSpectrumApp(id, rec) },
 				})
-			} else if rec.Type == "WORKER_ROUTE" {
+			case ex == nil:
+				corrections = append(corrections, c.createRec(rec, id)...)
+			case rec.Type == "PAGE_RULE":
 				corrections = append(corrections, &models.Correction{
-					Msg: d.String(),
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.updatePageRule(ex.Original.(cloudflare.PageRule).ID, id, rec.GetTargetField()) },
+				})
+			case rec.Type == "WORKER_ROUTE":
+				corrections = append(corrections, &models.Correction{
+					Msg:  d.String(),
+					Kind: d.Kind(),
 					F: func() error {
 						return c.updateWorkerRoute(ex.Original.(cloudflare.WorkerRoute).ID, id, rec.GetTargetField())
 					},
 				})
-			} else {
+			case rec.Type == "RULESET":
+				corrections = append(corrections, &models.Correction{
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.updateRule(ex.Original.(cfRuleRef), id, rec.GetTargetField()) },
+				})
+			case rec.Type == "SPECTRUM_APP":
+				corrections = append(corrections, &models.Correction{
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.updateSpectrumApp(ex.Original.(string), id, rec) },
+				})
+			default:
 				e := ex.Original.(cloudflare.DNSRecord)
 				proxy := e.Proxiable && rec.Metadata[metaProxy] != "off"
 				corrections = append(corrections, &models.Correction{
-					Msg: d.String(),
-					F:   func() error { return c.modifyRecord(id, e.ID, proxy, rec) },
+					Msg:  d.String(),
+					Kind: d.Kind(),
+					F:    func() error { return c.modifyRecord(id, e.ID, proxy, rec, ex.ProviderFields) },
 				})
 			}
 		}
 
 		// Add universalSSL change to corrections when needed
-		if changed, newState, err := c.checkUniversalSSL(dc, id); err == nil && changed {
+		changed, newState, err := c.checkUniversalSSL(dc, id)
+		if err != nil && dc.Metadata[metaUniversalSSL] != "" {
+			return nil, err
+		}
+		if err == nil && changed {
 			var newStateString string
 			if newState {
 				newStateString = "enabled"
@@ -315,11 +362,27 @@ func (c *cloudflareProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 				newStateString = "disabled"
 			}
 			corrections = append(corrections, &models.Correction{
-				Msg: fmt.Sprintf("Universal SSL will be %s for this domain.", newStateString),
-				F:   func() error { return c.changeUniversalSSL(id, newState) },
+				Msg:  fmt.Sprintf("Universal SSL will be %s for this domain.", newStateString),
+				Kind: models.CorrectionModify,
+				F:    func() error { return c.changeUniversalSSL(id, newState) },
 			})
 		}
 
+		// Add CNAME flattening change to corrections when needed
+		if want := dc.Metadata[metaCNAMEFlattening]; want != "" {
+			actual, err := c.getCNAMEFlattening(id)
+			if err != nil {
+				return nil, fmt.Errorf("error receiving cname flattening setting: %w", err)
+			}
+			if actual != want {
+				corrections = append(corrections, &models.Correction{
+					Msg:  fmt.Sprintf("CNAME flattening will be changed from %s to %s for this domain.", actual, want),
+					Kind: models.CorrectionModify,
+					F:    func() error { return c.changeCNAMEFlattening(id, want) },
+				})
+			}
+		}
+
 		return corrections, nil
 	}
 
@@ -329,49 +392,89 @@ func (c *cloudflareProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 
 }
 
-func checkNSModifications(dc *models.DomainConfig) {
+// checkNSModifications removes the NS records at the apex, which
+// Cloudflare manages. It warns about those that aren't Cloudflare's
+// nameservers, or with fail_on_ns_conflict returns an error, unless
+// ignore_ns_conflict is set (e.g. because NS records are added for all
+// the providers of a dual-hosted domain on purpose).
+func (c *cloudflareProvider) checkNSModifications(dc *models.DomainConfig) error {
 	newList := make([]*models.RecordConfig, 0, len(dc.Records))
+	var dropped []string
 	for _, rec := range dc.Records {
 		if rec.Type == "NS" && rec.GetLabelFQDN() == dc.Name {
 			if !strings.HasSuffix(rec.GetTargetField(), ".ns.cloudflare.com.") {
-				printer.Warnf("cloudflare does not support modifying NS records on base domain. %s will not be added.\n", rec.GetTargetField())
+				dropped = append(dropped, rec.GetTargetField())
 			}
 			continue
 		}
 		newList = append(newList, rec)
 	}
 	dc.Records = newList
+	switch {
+	case len(dropped) == 0 || c.ignoreNSConflict:
+	case c.failOnNSConflict:
+		return fmt.Errorf("cloudflare does not support modifying NS records on base domain %s: %s (fail_on_ns_conflict is set)", dc.Name, strings.Join(dropped, ", "))
+	default:
+		for _, ns := range dropped {
+			logger.Warnf("cloudflare does not support modifying NS records on base domain. %s will not be added.\n", ns)
+		}
+	}
+	return nil
 }
 
+// checkUniversalSSL compares the Universal SSL state of the zone with
+// the cloudflare_universalssl metadata. If the metadata isn't set, the
+// current state is only reported.
 func (c *cloudflareProvider) checkUniversalSSL(dc *models.DomainConfig, id string) (changed bool, newState bool, err error) {
+	actual, err := c.getUniversalSSL(id)
+	if err != nil {
+		return false, false, fmt.Errorf("error receiving universal ssl state: %w", err)
+	}
+
 	expectedStr := dc.Metadata[metaUniversalSSL]
 	if expectedStr == "" {
-		return false, false, fmt.Errorf("metadata not set")
+		logger.Printf("Universal SSL is %s for this domain (not managed: %s is not set).\n", onOff(actual), metaUniversalSSL)
+		return false, actual, nil
 	}
 
-	if actual, err := c.getUniversalSSL(id); err == nil {
-		// convert str to bool
-		var expected bool
-		if expectedStr == "off" {
-			expected = false
-		} else {
-			expected = true
-		}
-		// did something change?
-		if actual != expected {
-			return true, expected, nil
-		}
-		return false, expected, nil
+	expected := strings.ToLower(expectedStr) != "off"
+	return actual != expected, expected, nil
+}
+
+// GetZoneMetadata returns the zone settings of domain as metadata.
+func (c *cloudflareProvider) GetZoneMetadata(domain string) (map[string]string, error) {
+	id, err := c.getDomainID(domain)
+	if err != nil {
+		return nil, err
+	}
+	universalSSL, err := c.getUniversalSSL(id)
+	if err != nil {
+		return nil, err
+	}
+	cnameFlattening, err := c.getCNAMEFlattening(id)
+	if err != nil {
+		return nil, err
 	}
-	return false, false, fmt.Errorf("error receiving universal ssl state")
+	return map[string]string{
+		metaUniversalSSL:    onOff(universalSSL),
+		metaCNAMEFlattening: cnameFlattening,
+	}, nil
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
 }
 
 const (
-	metaProxy         = "cloudflare_proxy"
-	metaProxyDefault  = metaProxy + "_default"
-	metaOriginalIP    = "original_ip" // TODO(tlim): Unclear what this means.
-	metaUniversalSSL  = "cloudflare_universalssl"
-	metaIPConversions = "ip_conversions" // TODO(tlim): Rename to obscure_rules.
+	metaProxy           = "cloudflare_proxy"
+	metaProxyDefault    = metaProxy + "_default"
+	metaOriginalIP      = "original_ip" // TODO(tlim): Unclear what this means.
+	metaUniversalSSL    = "cloudflare_universalssl"
+	metaCNAMEFlattening = "cloudflare_cname_flattening"
+	metaIPConversions   = "ip_conversions" // TODO(tlim): Rename to obscure_rules.
 )
 
 func checkProxyVal(v string) (string, error) {
@@ -404,6 +507,11 @@ func (c *cloudflareProvider) preprocessConfig(dc *models.DomainConfig) error {
 		}
 	}
 
+	// Check CNAME flattening setting
+	if f := dc.Metadata[metaCNAMEFlattening]; f != "" && f != "flatten_at_root" && f != "flatten_all" {
+		return fmt.Errorf("bad metadata value for %s: '%s'. Use flatten_at_root/flatten_all", metaCNAMEFlattening, f)
+	}
+
 	// Normalize the proxy setting for each record.
 	// A and CNAMEs: Validate. If null, set to default.
 	// else: Make sure it wasn't set.  Set to default.
@@ -420,9 +528,7 @@ func (c *cloudflareProvider) preprocessConfig(dc *models.DomainConfig) error {
 		if rec.TTL == 0 || rec.TTL == 300 {
 			rec.TTL = 1
 		}
-		if rec.TTL != 1 && rec.TTL < 120 {
-			rec.TTL = 120
-		}
+		rec.TTL = ttlLimits.Fix(rec.TTL)
 
 		if rec.Type != "A" && rec.Type != "CNAME" && rec.Type != "AAAA" && rec.Type != "ALIAS" {
 			if rec.Metadata[metaProxy] != "" {
@@ -470,11 +576,39 @@ func (c *cloudflareProvider) preprocessConfig(dc *models.DomainConfig) error {
 			rec.TTL = 1
 			rec.Type = "WORKER_ROUTE"
 		}
+
+		// CF_RULESET record types. Encode target as the JSON of the rule.
+		if rec.Type == "CF_RULESET" {
+			if !c.manageRulesets {
+				return fmt.Errorf("you must add 'manage_rulesets: true' metadata to cloudflare provider to use CF_RULESET records")
+			}
+			target, err := rulesetTarget(rec)
+			if err != nil {
+				return err
+			}
+			rec.SetTarget(target)
+			rec.TTL = 1
+			rec.Type = "RULESET"
+		}
+
+		// CF_SPECTRUM_APP record types. Encode target as $PROTOCOL/$PORT,$ORIGIN
+		if rec.Type == "CF_SPECTRUM_APP" {
+			if !c.manageSpectrum {
+				return fmt.Errorf("you must add 'manage_spectrum: true' metadata to cloudflare provider to use CF_SPECTRUM_APP records")
+			}
+			target, err := spectrumTarget(rec)
+			if err != nil {
+				return err
+			}
+			rec.SetTarget(target)
+			rec.TTL = 1
+			rec.Type = "SPECTRUM_APP"
+		}
 	}
 
 	// look for ip conversions and transform records
 	for _, rec := range dc.Records {
-		if rec.Type != "A" {
+		if rec.Type != "A" && rec.Type != "AAAA" {
 			continue
 		}
 		// only transform "full"
@@ -511,11 +645,17 @@ func newCloudflare(m map[string]string, metadata json.RawMessage) (providers.DNS
 	// https://pkg.go.dev/github.com/cloudflare/cloudflare-go#UsingRetryPolicy
 	// The defaults are UsingRetryPolicy(3, 1, 30)
 
+	opts := []cloudflare.Option{optRP}
+	if m["baseURL"] != "" {
+		// Only used by the tests, with pkg/mockapi.
+		opts = append(opts, cloudflare.BaseURL(m["baseURL"]))
+	}
+
 	var err error
 	if m["apitoken"] != "" {
-		api.cfClient, err = cloudflare.NewWithAPIToken(m["apitoken"], optRP)
+		api.cfClient, err = cloudflare.NewWithAPIToken(m["apitoken"], opts...)
 	} else {
-		api.cfClient, err = cloudflare.New(m["apikey"], m["apiuser"], optRP)
+		api.cfClient, err = cloudflare.New(m["apikey"], m["apiuser"], opts...)
 	}
 
 	if err != nil {
@@ -529,10 +669,14 @@ func newCloudflare(m map[string]string, metadata json.RawMessage) (providers.DNS
 
 	if len(metadata) > 0 {
 		parsedMeta := &struct {
-			IPConversions   string   `json:"ip_conversions"`
-			IgnoredLabels   []string `json:"ignored_labels"`
-			ManageRedirects bool     `json:"manage_redirects"`
-			ManageWorkers   bool     `json:"manage_workers"`
+			IPConversions    string   `json:"ip_conversions"`
+			IgnoredLabels    []string `json:"ignored_labels"`
+			ManageRedirects  bool     `json:"manage_redirects"`
+			ManageWorkers    bool     `json:"manage_workers"`
+			ManageRulesets   bool     `json:"manage_rulesets"`
+			ManageSpectrum   bool     `json:"manage_spectrum"`
+			FailOnNSConflict bool     `json:"fail_on_ns_conflict"`
+			IgnoreNSConflict bool     `json:"ignore_ns_conflict"`
 		}{}
 		err := json.Unmarshal([]byte(metadata), parsedMeta)
 		if err != nil {
@@ -540,10 +684,16 @@ func newCloudflare(m map[string]string, metadata json.RawMessage) (providers.DNS
 		}
 		api.manageRedirects = parsedMeta.ManageRedirects
 		api.manageWorkers = parsedMeta.ManageWorkers
-		// ignored_labels:
-		api.ignoredLabels = append(api.ignoredLabels, parsedMeta.IgnoredLabels...)
-		if len(api.ignoredLabels) > 0 {
-			printer.Warnf("Cloudflare 'ignored_labels' configuration is deprecated and might be removed. Please use the IGNORE domain directive to achieve the same effect.\n")
+		api.manageRulesets = parsedMeta.ManageRulesets
+		api.manageSpectrum = parsedMeta.ManageSpectrum
+		if parsedMeta.FailOnNSConflict && parsedMeta.IgnoreNSConflict {
+			return nil, fmt.Errorf("cloudflare: 'fail_on_ns_conflict' and 'ignore_ns_conflict' can't both be set")
+		}
+		api.failOnNSConflict = parsedMeta.FailOnNSConflict
+		api.ignoreNSConflict = parsedMeta.IgnoreNSConflict
+		// ignored_labels was replaced by IGNORE_NAME() and IGNORE().
+		if len(parsedMeta.IgnoredLabels) > 0 {
+			return nil, fmt.Errorf("cloudflare: 'ignored_labels' is no longer supported. Use IGNORE_NAME() or IGNORE() in dnsconfig.js instead")
 		}
 		// parse provider level metadata
 		if len(parsedMeta.IPConversions) > 0 {
@@ -667,11 +817,29 @@ func (c *cloudflareProvider) nativeToRecord(domain string, cr cloudflare.DNSReco
 	}
 
 	rc := &models.RecordConfig{
-		TTL:      uint32(cr.TTL),
-		Original: cr,
+		TTL: uint32(cr.TTL),
+		// Only what the corrections use is kept, so that the rest of
+		// the native record can be freed.
+		Original: cloudflare.DNSRecord{
+			ID:        cr.ID,
+			Type:      cr.Type,
+			Name:      cr.Name,
+			Content:   cr.Content,
+			TTL:       cr.TTL,
+			Proxied:   cr.Proxied,
+			Proxiable: cr.Proxiable,
+		},
 	}
 	rc.SetLabelFromFQDN(cr.Name, domain)
 
+	// Save what we don't model (comments, tags, ...) so that updates
+	// don't wipe it.
+	fields, err := models.ProviderFieldsFromNative(cr, cfModeledFields...)
+	if err != nil {
+		return nil, err
+	}
+	rc.ProviderFields = fields
+
 	// workaround for https://github.com/StackExchange/dnscontrol/issues/446
 	if cr.Type == "SPF" {
 		cr.Type = "TXT"
@@ -732,7 +900,7 @@ func (c *cloudflareProvider) EnsureDomainExists(domain string) error {
 	}
 	var id string
 	id, err := c.createZone(domain)
-	printer.Printf("Added zone for %s to Cloudflare account: %s\n", domain, id)
+	logger.Printf("Added zone for %s to Cloudflare account: %s\n", domain, id)
 	return err
 }
 
@@ -87,6 +87,19 @@ func TestPreprocess_DefaultProxy_Validation(t *testing.T) {
 	}
 }
 
+func TestPreprocess_CNAMEFlattening_Validation(t *testing.T) {
+	cf := &cloudflareProvider{}
+	domain := newDomainConfig()
+	domain.Metadata[metaCNAMEFlattening] = "flatten_all"
+	if err := cf.preprocessConfig(domain); err != nil {
+		t.Fatal(err)
+	}
+	domain.Metadata[metaCNAMEFlattening] = "on"
+	if err := cf.preprocessConfig(domain); err == nil {
+		t.Fatal("Expected validation error, but got none")
+	}
+}
+
 func TestIpRewriting(t *testing.T) {
 	var tests = []struct {
 		Given, Expected string
@@ -126,3 +139,35 @@ func TestIpRewriting(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckNSModifications(t *testing.T) {
+	makeDC := func() *models.DomainConfig {
+		dc := newDomainConfig()
+		for _, target := range []string{"a.ns.cloudflare.com.", "ns1.example.net."} {
+			rc := &models.RecordConfig{Type: "NS"}
+			rc.SetLabel("@", dc.Name)
+			rc.SetTarget(target)
+			dc.Records = append(dc.Records, rc)
+		}
+		return dc
+	}
+
+	cf := &cloudflareProvider{}
+	dc := makeDC()
+	if err := cf.checkNSModifications(dc); err != nil {
+		t.Fatal(err)
+	}
+	if len(dc.Records) != 0 {
+		t.Errorf("got %d records, want the NS records removed", len(dc.Records))
+	}
+
+	cf = &cloudflareProvider{failOnNSConflict: true}
+	if err := cf.checkNSModifications(makeDC()); err == nil {
+		t.Error("expected an error with fail_on_ns_conflict")
+	}
+
+	cf = &cloudflareProvider{ignoreNSConflict: true}
+	if err := cf.checkNSModifications(makeDC()); err != nil {
+		t.Error(err)
+	}
+}
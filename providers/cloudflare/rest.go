@@ -1,10 +1,15 @@
 package cloudflare
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/cloudflare/cloudflare-go"
@@ -28,26 +33,188 @@ func (c *cloudflareProvider) fetchDomainList() error {
 }
 
 // get all records for a domain
+//
+// The records are requested a page at a time and each page is converted
+// before the next one is requested, so that the native records of a
+// large zone are never all in memory at once.
 func (c *cloudflareProvider) getRecordsForDomain(id string, domain string) ([]*models.RecordConfig, error) {
 	records := []*models.RecordConfig{}
-	rrs, err := c.cfClient.DNSRecords(context.Background(), id, cloudflare.DNSRecord{})
+	err := c.forEachRecordPage(id, func(page []cloudflare.DNSRecord) error {
+		for _, rec := range page {
+			rt, err := c.nativeToRecord(domain, rec)
+			if err != nil {
+				return err
+			}
+			records = append(records, rt)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed fetching record list from cloudflare(%q): %w", c.cfClient.APIEmail, err)
 	}
-	for _, rec := range rrs {
-		rt, err := c.nativeToRecord(domain, rec)
+	return records, nil
+}
+
+// recordsPerPage is the number of records requested at a time. It is
+// the page size of cloudflare-go's DNSRecords().
+const recordsPerPage = 100
+
+// The retries of a request of records, like the retry policy of
+// cfClient (see newCloudflare).
+const (
+	pageRetries    = 20
+	pageMinBackoff = 1 * time.Second
+	pageMaxBackoff = 120 * time.Second
+)
+
+// cfRecordPage is the response to a request of a page of records.
+type cfRecordPage struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result     []cloudflare.DNSRecord `json:"result"`
+	ResultInfo struct {
+		Page       int `json:"page"`
+		TotalPages int `json:"total_pages"`
+	} `json:"result_info"`
+}
+
+// forEachRecordPage calls fn with each page of the records of the zone
+// id. cloudflare-go's DNSRecords() only returns all the records at
+// once, thus the pages are requested here.
+func (c *cloudflareProvider) forEachRecordPage(id string, fn func([]cloudflare.DNSRecord) error) error {
+	for page := 1; ; page++ {
+		var resp cfRecordPage
+		endpoint := fmt.Sprintf("/zones/%s/dns_records?page=%d&per_page=%d", id, page, recordsPerPage)
+		if err := c.getWithRetries(endpoint, &resp); err != nil {
+			return err
+		}
+		if err := fn(resp.Result); err != nil {
+			return err
+		}
+		if len(resp.Result) < recordsPerPage || page >= resp.ResultInfo.TotalPages {
+			return nil
+		}
+	}
+}
+
+// getWithRetries decodes the response to a GET of endpoint into resp.
+// Requests that are rate-limited or fail on the server are retried.
+func (c *cloudflareProvider) getWithRetries(endpoint string, resp *cfRecordPage) error {
+	backoff := pageMinBackoff
+	for try := 0; ; try++ {
+		status, err := c.get(endpoint, resp)
+		if err == nil || try == pageRetries || (status != http.StatusTooManyRequests && status < 500) {
+			return err
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > pageMaxBackoff {
+			backoff = pageMaxBackoff
+		}
+	}
+}
+
+// get decodes the response to a GET of endpoint into resp, with the
+// credentials of cfClient. It returns the HTTP status.
+func (c *cloudflareProvider) get(endpoint string, resp *cfRecordPage) (int, error) {
+	req, err := c.newRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Body.Close()
+
+	*resp = cfRecordPage{}
+	if err := json.NewDecoder(r.Body).Decode(resp); err != nil {
+		return r.StatusCode, fmt.Errorf("GET %s: HTTP status %d: %w", endpoint, r.StatusCode, err)
+	}
+	if r.StatusCode != http.StatusOK || !resp.Success {
+		var msgs []string
+		for _, e := range resp.Errors {
+			msgs = append(msgs, fmt.Sprintf("%s (%d)", e.Message, e.Code))
+		}
+		return r.StatusCode, fmt.Errorf("GET %s: HTTP status %d: %s", endpoint, r.StatusCode, strings.Join(msgs, ", "))
+	}
+	return r.StatusCode, nil
+}
+
+// newRequest returns a request of endpoint with the credentials of
+// cfClient.
+func (c *cloudflareProvider) newRequest(method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.cfClient.BaseURL+endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.cfClient.APIToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfClient.APIToken)
+	} else {
+		req.Header.Set("X-Auth-Email", c.cfClient.APIEmail)
+		req.Header.Set("X-Auth-Key", c.cfClient.APIKey)
+	}
+	req.Header.Set("User-Agent", c.cfClient.UserAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// cfResponse is the response to a request of the API.
+type cfResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result interface{} `json:"result"`
+}
+
+// request sends body, as JSON if it isn't nil, to endpoint and decodes
+// the result of the response into result if it isn't nil. It returns
+// the HTTP status. It is used for the parts of the API whose functions
+// in cloudflare-go change too much from one version to the next.
+func (c *cloudflareProvider) request(method, endpoint string, body, result interface{}) (int, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
-		records = append(records, rt)
+		reqBody = bytes.NewReader(b)
 	}
-	return records, nil
+	req, err := c.newRequest(method, endpoint, reqBody)
+	if err != nil {
+		return 0, err
+	}
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Body.Close()
+
+	resp := cfResponse{Result: result}
+	if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+		return r.StatusCode, fmt.Errorf("%s %s: HTTP status %d: %w", method, endpoint, r.StatusCode, err)
+	}
+	if r.StatusCode != http.StatusOK || !resp.Success {
+		var msgs []string
+		for _, e := range resp.Errors {
+			msgs = append(msgs, fmt.Sprintf("%s (%d)", e.Message, e.Code))
+		}
+		return r.StatusCode, fmt.Errorf("%s %s: HTTP status %d: %s", method, endpoint, r.StatusCode, strings.Join(msgs, ", "))
+	}
+	return r.StatusCode, nil
 }
 
 // create a correction to delete a record
 func (c *cloudflareProvider) deleteRec(rec cloudflare.DNSRecord, domainID string) *models.Correction {
 	return &models.Correction{
-		Msg: fmt.Sprintf("DELETE record: %s %s %d %q (id=%s)", rec.Name, rec.Type, rec.TTL, rec.Content, rec.ID),
+		Msg:  fmt.Sprintf("DELETE record: %s %s %d %q (id=%s)", rec.Name, rec.Type, rec.TTL, rec.Content, rec.ID),
+		Kind: models.CorrectionDestroy,
 		F: func() error {
 			err := c.cfClient.DeleteDNSRecord(context.Background(), domainID, rec.ID)
 			return err
@@ -125,7 +292,8 @@ func (c *cloudflareProvider) createRec(rec *models.RecordConfig, domainID string
 		content = fmt.Sprintf("%d %d %d %s", rec.DsKeyTag, rec.DsAlgorithm, rec.DsDigestType, rec.DsDigest)
 	}
 	arr := []*models.Correction{{
-		Msg: fmt.Sprintf("CREATE record: %s %s %d%s %s", rec.GetLabel(), rec.Type, rec.TTL, prio, content),
+		Msg:  fmt.Sprintf("CREATE record: %s %s %d%s %s", rec.GetLabel(), rec.Type, rec.TTL, prio, content),
+		Kind: models.CorrectionCreate,
 		F: func() error {
 			cf := cloudflare.DNSRecord{
 				Name:     rec.GetLabel(),
@@ -161,14 +329,25 @@ func (c *cloudflareProvider) createRec(rec *models.RecordConfig, domainID string
 	}}
 	if rec.Metadata[metaProxy] != "off" {
 		arr = append(arr, &models.Correction{
-			Msg: fmt.Sprintf("ACTIVATE PROXY for new record %s %s %d %s", rec.GetLabel(), rec.Type, rec.TTL, rec.GetTargetField()),
-			F:   func() error { return c.modifyRecord(domainID, id, true, rec) },
+			Msg:  fmt.Sprintf("ACTIVATE PROXY for new record %s %s %d %s", rec.GetLabel(), rec.Type, rec.TTL, rec.GetTargetField()),
+			Kind: models.CorrectionCreate,
+			F:    func() error { return c.modifyRecord(domainID, id, true, rec, nil) },
 		})
 	}
 	return arr
 }
 
-func (c *cloudflareProvider) modifyRecord(domainID, recID string, proxied bool, rec *models.RecordConfig) error {
+// cfModeledFields are the JSON fields of cloudflare.DNSRecord that
+// DNSControl manages or that are read-only. The others are saved in
+// RecordConfig.ProviderFields.
+var cfModeledFields = []string{
+	"id", "type", "name", "content", "ttl", "priority", "data", "proxied",
+	"proxiable", "locked", "meta", "zone_id", "zone_name", "created_on", "modified_on",
+}
+
+// modifyRecord updates a record. fields are the ProviderFields of the
+// existing record, which are re-applied.
+func (c *cloudflareProvider) modifyRecord(domainID, recID string, proxied bool, rec *models.RecordConfig, fields map[string]interface{}) error {
 	if domainID == "" || recID == "" {
 		return fmt.Errorf("cannot modify record if domain or record id are empty")
 	}
@@ -202,6 +381,9 @@ func (c *cloudflareProvider) modifyRecord(domainID, recID string, proxied bool,
 		r.Data = cfDSData(rec)
 		r.Content = ""
 	}
+	if err := models.ApplyProviderFields(&r, fields); err != nil {
+		return err
+	}
 	return c.cfClient.UpdateDNSRecord(context.Background(), domainID, recID, r)
 }
 
@@ -217,6 +399,22 @@ func (c *cloudflareProvider) getUniversalSSL(domainID string) (bool, error) {
 	return result.Enabled, err
 }
 
+// change CNAME flattening setting ("flatten_at_root" or "flatten_all")
+func (c *cloudflareProvider) changeCNAMEFlattening(domainID string, value string) error {
+	_, err := c.cfClient.UpdateZoneSingleSetting(context.Background(), domainID, "cname_flattening", cloudflare.ZoneSetting{Value: value})
+	return err
+}
+
+// get CNAME flattening setting
+func (c *cloudflareProvider) getCNAMEFlattening(domainID string) (string, error) {
+	result, err := c.cfClient.ZoneSingleSetting(context.Background(), domainID, "cname_flattening")
+	if err != nil {
+		return "", err
+	}
+	value, _ := result.Value.(string)
+	return value, nil
+}
+
 func (c *cloudflareProvider) getPageRules(id string, domain string) ([]*models.RecordConfig, error) {
 	rules, err := c.cfClient.ListPageRules(context.Background(), id)
 	if err != nil {
@@ -0,0 +1,174 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/mockapi"
+)
+
+func newMockProvider(t testing.TB) (*cloudflareProvider, *mockapi.Server, *mockapi.Cloudflare) {
+	mock := mockapi.NewCloudflare()
+	mock.AddZone("example.com")
+	server := mockapi.NewServer(mock)
+	t.Cleanup(server.Close)
+	p, err := newCloudflare(server.Creds(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.(*cloudflareProvider), server, mock
+}
+
+// newMockProviderMeta returns a provider with the metadata meta, of a
+// mock with the zone example.com.
+func newMockProviderMeta(t testing.TB, meta string) *cloudflareProvider {
+	mock := mockapi.NewCloudflare()
+	mock.AddZone("example.com")
+	server := mockapi.NewServer(mock)
+	t.Cleanup(server.Close)
+	p, err := newCloudflare(server.Creds(), json.RawMessage(meta))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.(*cloudflareProvider)
+}
+
+// pushMock runs the corrections that make example.com have the records
+// recs, and returns their number.
+func pushMock(t testing.TB, c *cloudflareProvider, recs ...*models.RecordConfig) int {
+	t.Helper()
+	dc := newDomainConfig()
+	dc.Name = "example.com"
+	dc.Records = recs
+	corrections, err := c.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, corr := range corrections {
+		if err := corr.F(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return len(corrections)
+}
+
+func countRequests(server *mockapi.Server, prefix string) int {
+	n := 0
+	for _, r := range server.Requests() {
+		if strings.HasPrefix(r, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGetZoneRecordsMock(t *testing.T) {
+	c, server, mock := newMockProvider(t)
+	for i := 0; i < 250; i++ {
+		mock.AddRecord("example.com", map[string]interface{}{
+			"name": fmt.Sprintf("host%d", i), "type": "A", "content": "192.0.2.1", "ttl": 300,
+		})
+	}
+	mock.AddRecord("example.com", map[string]interface{}{
+		"name": "_sip._tcp", "type": "SRV", "ttl": 300,
+		"data": map[string]interface{}{"priority": 10, "weight": 20, "port": 5060, "target": "sip.example.com"},
+	})
+	// The first request of the records is rate-limited, and retried.
+	server.Inject(mockapi.Fault{
+		Path:   "/dns_records",
+		Status: http.StatusTooManyRequests,
+		Body:   `{"success": false, "errors": [{"code": 10000, "message": "Rate limited"}]}`,
+	})
+
+	records, err := c.GetZoneRecords("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 251 {
+		t.Errorf("got %d records, want 251", len(records))
+	}
+	// 1 rate-limited request, and the records on 3 pages of 100.
+	if n := countRequests(server, "GET /zones/"); n != 4 {
+		t.Errorf("got %d requests of records, want 4: %q", n, server.Requests())
+	}
+	srv := records[len(records)-1]
+	if got := srv.GetTargetCombined(); got != "10 20 5060 sip.example.com." {
+		t.Errorf("got SRV record %q", got)
+	}
+}
+
+func TestCorrectionErrorMock(t *testing.T) {
+	c, server, _ := newMockProvider(t)
+	server.Inject(mockapi.Fault{
+		Method: "POST",
+		Path:   "/dns_records",
+		Status: http.StatusBadRequest,
+		Body:   `{"success": false, "errors": [{"code": 81057, "message": "Record already exists."}], "messages": [], "result": null}`,
+	})
+
+	rc := &models.RecordConfig{Type: "A", TTL: 300, Metadata: map[string]string{}}
+	rc.SetLabel("www", "example.com")
+	rc.SetTarget("192.0.2.1")
+	dc := newDomainConfig()
+	dc.Name = "example.com"
+	dc.Records = models.Records{rc}
+	corrections, err := c.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) == 0 {
+		t.Fatal("no corrections")
+	}
+	err = corrections[0].F()
+	if err == nil || !strings.Contains(err.Error(), "Record already exists") {
+		t.Errorf("got error %v, want the message of the API", err)
+	}
+
+	// Without the error, the record is created.
+	if err := corrections[0].F(); err != nil {
+		t.Fatal(err)
+	}
+	records, err := c.GetZoneRecords("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].GetLabelFQDN() != "www.example.com" {
+		t.Errorf("got records %v, want www.example.com", records)
+	}
+}
+
+func BenchmarkGetZoneRecords(b *testing.B) {
+	for _, n := range []int{1000, 10000, 50000} {
+		b.Run(fmt.Sprintf("%d records", n), func(b *testing.B) {
+			c, _, mock := newMockProvider(b)
+			for i := 0; i < n; i++ {
+				name := fmt.Sprintf("host%d", i)
+				switch i % 4 {
+				case 0:
+					mock.AddRecord("example.com", map[string]interface{}{"name": name, "type": "A", "content": "192.0.2.1", "ttl": 300, "proxied": true})
+				case 1:
+					mock.AddRecord("example.com", map[string]interface{}{"name": name, "type": "AAAA", "content": "2001:db8::1", "ttl": 300, "proxied": false})
+				case 2:
+					mock.AddRecord("example.com", map[string]interface{}{"name": name, "type": "CNAME", "content": "www.example.com", "ttl": 1, "proxied": false})
+				case 3:
+					mock.AddRecord("example.com", map[string]interface{}{"name": name, "type": "TXT", "content": "v=spf1 -all", "ttl": 300})
+				}
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				records, err := c.GetZoneRecords("example.com")
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(records) != n {
+					b.Fatalf("got %d records, want %d", len(records), n)
+				}
+			}
+		})
+	}
+}
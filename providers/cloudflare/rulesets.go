@@ -0,0 +1,267 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// CF_RULESET records are the WAF custom rules and the rate limiting
+// rules of a name of the zone. They are rules of the entrypoint
+// rulesets of the zone in the two phases below, whose expression is
+// restricted to the requests for the name (see hostExpression). The
+// other rules of these rulesets are left alone.
+const (
+	phaseCustomRules = "http_request_firewall_custom"
+	phaseRateLimit   = "http_ratelimit"
+)
+
+var rulesetPhases = []string{phaseCustomRules, phaseRateLimit}
+
+// rulesetActions are the actions that a CF_RULESET rule can take.
+var rulesetActions = map[string]bool{
+	"block":             true,
+	"challenge":         true,
+	"js_challenge":      true,
+	"log":               true,
+	"managed_challenge": true,
+}
+
+// rateLimitPeriods are the periods, in seconds, over which a rate
+// limiting rule can count the requests.
+var rateLimitPeriods = map[int]bool{10: true, 60: true, 120: true, 300: true, 600: true, 3600: true}
+
+// rateLimitCharacteristics are what the requests are counted by: the
+// client IP address, in each Cloudflare data center.
+var rateLimitCharacteristics = []string{"cf.colo.id", "ip.src"}
+
+// cfRule is a rule of a ruleset, as the API sends it.
+type cfRule struct {
+	ID               string          `json:"id,omitempty"`
+	Action           string          `json:"action"`
+	ActionParameters json.RawMessage `json:"action_parameters,omitempty"`
+	Expression       string          `json:"expression"`
+	Description      string          `json:"description,omitempty"`
+	Enabled          *bool           `json:"enabled,omitempty"`
+	RateLimit        *cfRateLimit    `json:"ratelimit,omitempty"`
+}
+
+type cfRateLimit struct {
+	Characteristics   []string `json:"characteristics"`
+	Period            int      `json:"period"`
+	RequestsPerPeriod int      `json:"requests_per_period"`
+	MitigationTimeout int      `json:"mitigation_timeout"`
+}
+
+type cfRuleset struct {
+	ID    string   `json:"id"`
+	Phase string   `json:"phase"`
+	Rules []cfRule `json:"rules"`
+}
+
+// cfRuleRef is the Original of a RULESET record.
+type cfRuleRef struct {
+	RulesetID string
+	RuleID    string
+	Phase     string
+}
+
+// target returns the target of the RULESET record of r: the JSON of
+// the fields that CF_RULESET sets.
+func (r cfRule) target() string {
+	r.ID, r.ActionParameters, r.Enabled = "", nil, nil
+	b, _ := json.Marshal(r)
+	return string(b)
+}
+
+// phase returns the phase of the rulesets that r belongs to.
+func (r cfRule) phase() string {
+	if r.RateLimit != nil {
+		return phaseRateLimit
+	}
+	return phaseCustomRules
+}
+
+// hostExpression returns expr restricted to the requests for host.
+func hostExpression(host, expr string) string {
+	clause := `http.host eq "` + host + `"`
+	if expr == "" {
+		return clause
+	}
+	return "(" + clause + ") and (" + expr + ")"
+}
+
+// ruleHost returns the host that hostExpression restricted expr to, or
+// "" if expr isn't of that form.
+func ruleHost(expr string) string {
+	rest := strings.TrimPrefix(expr, "(")
+	paren := len(rest) != len(expr)
+	if !strings.HasPrefix(rest, `http.host eq "`) {
+		return ""
+	}
+	rest = strings.TrimPrefix(rest, `http.host eq "`)
+	i := strings.IndexByte(rest, '"')
+	if i < 0 {
+		return ""
+	}
+	host, rest := rest[:i], rest[i+1:]
+	if (paren && strings.HasPrefix(rest, ") and (") && strings.HasSuffix(rest, ")")) || (!paren && rest == "") {
+		return host
+	}
+	return ""
+}
+
+// cfRulesetConfig is the target of a CF_RULESET record, as helpers.js
+// encodes it.
+type cfRulesetConfig struct {
+	Action      string `json:"action"`
+	Expression  string `json:"expression"`
+	Description string `json:"description"`
+	RateLimit   *struct {
+		Requests int `json:"requests"`
+		Period   int `json:"period"`
+		Timeout  int `json:"timeout"`
+	} `json:"rate_limit"`
+}
+
+// rulesetTarget returns the target of the RULESET record that the
+// CF_RULESET record rec declares.
+func rulesetTarget(rec *models.RecordConfig) (string, error) {
+	name := rec.GetLabelFQDN()
+	var cfg cfRulesetConfig
+	if err := json.Unmarshal([]byte(rec.GetTargetField()), &cfg); err != nil {
+		return "", fmt.Errorf("invalid data specified for cloudflare ruleset record %s: %w", name, err)
+	}
+	if strings.Contains(name, "*") {
+		return "", fmt.Errorf("CF_RULESET %s: wildcard names are not supported", name)
+	}
+	if !rulesetActions[cfg.Action] {
+		var actions []string
+		for a := range rulesetActions {
+			actions = append(actions, a)
+		}
+		sort.Strings(actions)
+		return "", fmt.Errorf("CF_RULESET %s: unknown action %q (actions: %s)", name, cfg.Action, strings.Join(actions, ", "))
+	}
+	rule := cfRule{
+		Action:      cfg.Action,
+		Expression:  hostExpression(name, cfg.Expression),
+		Description: cfg.Description,
+	}
+	if rl := cfg.RateLimit; rl != nil {
+		switch {
+		case rl.Requests <= 0:
+			return "", fmt.Errorf("CF_RULESET %s: rate_limit requests must be positive", name)
+		case !rateLimitPeriods[rl.Period]:
+			return "", fmt.Errorf("CF_RULESET %s: rate_limit period must be 10, 60, 120, 300, 600 or 3600, not %d", name, rl.Period)
+		case rl.Timeout < 0:
+			return "", fmt.Errorf("CF_RULESET %s: rate_limit timeout must not be negative", name)
+		}
+		rule.RateLimit = &cfRateLimit{
+			Characteristics:   rateLimitCharacteristics,
+			Period:            rl.Period,
+			RequestsPerPeriod: rl.Requests,
+			MitigationTimeout: rl.Timeout,
+		}
+	}
+	return rule.target(), nil
+}
+
+// getRulesets returns the rules of the zone id that CF_RULESET can
+// declare, as RULESET records.
+func (c *cloudflareProvider) getRulesets(id string, domain string) ([]*models.RecordConfig, error) {
+	recs := []*models.RecordConfig{}
+	for _, phase := range rulesetPhases {
+		rs, err := c.getEntrypointRuleset(id, phase)
+		if err != nil {
+			return nil, fmt.Errorf("failed fetching %s ruleset from cloudflare: %w", phase, err)
+		}
+		if rs == nil {
+			continue
+		}
+		for _, rule := range rs.Rules {
+			host := strings.ToLower(ruleHost(rule.Expression))
+			switch {
+			case host != domain && !strings.HasSuffix(host, "."+domain),
+				!rulesetActions[rule.Action],
+				len(rule.ActionParameters) != 0,
+				rule.Enabled != nil && !*rule.Enabled,
+				rule.phase() != phase:
+				continue
+			}
+			r := &models.RecordConfig{
+				Type:     "RULESET",
+				Original: cfRuleRef{RulesetID: rs.ID, RuleID: rule.ID, Phase: phase},
+				TTL:      1,
+			}
+			r.SetLabelFromFQDN(host, domain)
+			r.SetTarget(rule.target())
+			recs = append(recs, r)
+		}
+	}
+	return recs, nil
+}
+
+func (c *cloudflareProvider) createRule(domainID string, target string) error {
+	var rule cfRule
+	if err := json.Unmarshal([]byte(target), &rule); err != nil {
+		return err
+	}
+	phase := rule.phase()
+	rs, err := c.getEntrypointRuleset(domainID, phase)
+	if err != nil {
+		return err
+	}
+	if rs == nil {
+		// The first rule of the phase creates its entrypoint ruleset.
+		endpoint := fmt.Sprintf("/zones/%s/rulesets/phases/%s/entrypoint", domainID, phase)
+		_, err := c.request(http.MethodPut, endpoint, cfRuleset{Rules: []cfRule{rule}}, nil)
+		return err
+	}
+	endpoint := fmt.Sprintf("/zones/%s/rulesets/%s/rules", domainID, rs.ID)
+	_, err = c.request(http.MethodPost, endpoint, rule, nil)
+	return err
+}
+
+func (c *cloudflareProvider) updateRule(ref cfRuleRef, domainID string, target string) error {
+	var rule cfRule
+	if err := json.Unmarshal([]byte(target), &rule); err != nil {
+		return err
+	}
+	if rule.phase() != ref.Phase {
+		// A rule that becomes a rate limiting rule, or stops being
+		// one, moves to another ruleset.
+		if err := c.deleteRule(ref, domainID); err != nil {
+			return err
+		}
+		return c.createRule(domainID, target)
+	}
+	endpoint := fmt.Sprintf("/zones/%s/rulesets/%s/rules/%s", domainID, ref.RulesetID, ref.RuleID)
+	_, err := c.request(http.MethodPatch, endpoint, rule, nil)
+	return err
+}
+
+func (c *cloudflareProvider) deleteRule(ref cfRuleRef, domainID string) error {
+	endpoint := fmt.Sprintf("/zones/%s/rulesets/%s/rules/%s", domainID, ref.RulesetID, ref.RuleID)
+	_, err := c.request(http.MethodDelete, endpoint, nil, nil)
+	return err
+}
+
+// getEntrypointRuleset returns the entrypoint ruleset of the zone id in
+// phase, or nil if the zone has none.
+func (c *cloudflareProvider) getEntrypointRuleset(id, phase string) (*cfRuleset, error) {
+	var rs cfRuleset
+	endpoint := fmt.Sprintf("/zones/%s/rulesets/phases/%s/entrypoint", id, phase)
+	status, err := c.request(http.MethodGet, endpoint, nil, &rs)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
@@ -0,0 +1,73 @@
+package cloudflare
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestRuleHost(t *testing.T) {
+	tests := []struct {
+		expr, host string
+	}{
+		{hostExpression("www.example.com", ""), "www.example.com"},
+		{hostExpression("www.example.com", `http.request.uri.path eq "/login"`), "www.example.com"},
+		{`http.request.uri.path eq "/login"`, ""},
+		{`http.host eq "www.example.com" or ip.src eq 192.0.2.1`, ""},
+		{`(http.host eq "www.example.com") or (ip.src eq 192.0.2.1)`, ""},
+	}
+	for _, tst := range tests {
+		if got := ruleHost(tst.expr); got != tst.host {
+			t.Errorf("ruleHost(%q) = %q, want %q", tst.expr, got, tst.host)
+		}
+	}
+}
+
+func makeRuleset(label, rule string) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: "CF_RULESET", Metadata: map[string]string{}}
+	rc.SetLabel(label, "example.com")
+	rc.SetTarget(rule)
+	return rc
+}
+
+func TestRulesetsMock(t *testing.T) {
+	c := newMockProviderMeta(t, `{"manage_rulesets": true}`)
+	id, err := c.getDomainID("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A rule of the whole zone, which CF_RULESET doesn't manage.
+	other := cfRuleset{Rules: []cfRule{{Action: "block", Expression: `ip.src eq 192.0.2.1`}}}
+	if _, err := c.request(http.MethodPut, "/zones/"+id+"/rulesets/phases/"+phaseCustomRules+"/entrypoint", other, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	www := `{"action":"block","expression":"http.request.uri.path eq \"/login\""}`
+	api := `{"action":"managed_challenge","rate_limit":{"requests":100,"period":60,"timeout":600}}`
+	if n := pushMock(t, c, makeRuleset("www", www), makeRuleset("api", api)); n != 2 {
+		t.Errorf("got %d corrections to create the rules, want 2", n)
+	}
+	if n := pushMock(t, c, makeRuleset("www", www), makeRuleset("api", api)); n != 0 {
+		t.Errorf("got %d corrections once the rules exist, want 0", n)
+	}
+	// The rule of api moves to the custom rules; the rule of www goes.
+	if n := pushMock(t, c, makeRuleset("api", `{"action":"log"}`)); n != 2 {
+		t.Errorf("got %d corrections to change the rules, want 2", n)
+	}
+
+	recs, err := c.getRulesets(id, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].GetLabel() != "api" || recs[0].Original.(cfRuleRef).Phase != phaseCustomRules {
+		t.Errorf("got rules %v, want the rule of api in %s", recs, phaseCustomRules)
+	}
+	rs, err := c.getEntrypointRuleset(id, phaseCustomRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs.Rules) != 2 || rs.Rules[0].Expression != other.Rules[0].Expression {
+		t.Errorf("got rules %+v, want the rule of the zone and the rule of api", rs.Rules)
+	}
+}
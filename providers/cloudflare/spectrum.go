@@ -0,0 +1,172 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// CF_SPECTRUM_APP records are the Spectrum applications of the zone
+// that proxy a TCP or UDP port of a name of the zone to one origin. The
+// target of their SPECTRUM_APP records is $PROTOCOL/$PORT,$ORIGIN, e.g.
+// "tcp/22,192.0.2.1:22". The applications that can't be written so
+// (HTTP traffic, several origins, port ranges...) are left alone.
+
+// cfSpectrumApp is a Spectrum application, as the API sends it.
+type cfSpectrumApp struct {
+	ID           string         `json:"id,omitempty"`
+	Protocol     string         `json:"protocol"` // $PROTOCOL/$PORT
+	DNS          cfSpectrumDNS  `json:"dns"`
+	OriginDirect []string       `json:"origin_direct,omitempty"` // $PROTOCOL://$IP:$PORT
+	OriginDNS    *cfSpectrumDNS `json:"origin_dns,omitempty"`
+	// OriginPort is the port of OriginDNS: a number, or a range as a
+	// string.
+	OriginPort  json.RawMessage `json:"origin_port,omitempty"`
+	TrafficType string          `json:"traffic_type,omitempty"`
+}
+
+// cfSpectrumDNS is the name of an application, or of its origin.
+type cfSpectrumDNS struct {
+	Type string `json:"type,omitempty"`
+	Name string `json:"name"`
+}
+
+// spectrumTarget returns the target of the SPECTRUM_APP record that the
+// CF_SPECTRUM_APP record rec declares.
+func spectrumTarget(rec *models.RecordConfig) (string, error) {
+	name := rec.GetLabelFQDN()
+	parts := strings.Split(rec.GetTargetField(), ",")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid data specified for cloudflare spectrum record %s", name)
+	}
+	proto, port, ok := strings.Cut(parts[0], "/")
+	if !ok || (proto != "tcp" && proto != "udp") {
+		return "", fmt.Errorf("CF_SPECTRUM_APP %s: the protocol must be tcp or udp, not %q", name, proto)
+	}
+	if !validPort(port) {
+		return "", fmt.Errorf("CF_SPECTRUM_APP %s: invalid port %q", name, port)
+	}
+	host, originPort, err := net.SplitHostPort(parts[1])
+	if err != nil || host == "" || !validPort(originPort) {
+		return "", fmt.Errorf("CF_SPECTRUM_APP %s: the origin must be HOST:PORT or [IPv6]:PORT, not %q", name, parts[1])
+	}
+	if strings.Contains(name, "*") {
+		return "", fmt.Errorf("CF_SPECTRUM_APP %s: wildcard names are not supported", name)
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	return proto + "/" + port + "," + net.JoinHostPort(host, originPort), nil
+}
+
+func validPort(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0 && n <= 65535
+}
+
+// target returns the target of the SPECTRUM_APP record of app, or "" if
+// CF_SPECTRUM_APP can't declare app.
+func (app cfSpectrumApp) target() string {
+	if app.TrafficType != "" && app.TrafficType != "direct" {
+		return ""
+	}
+	proto, port, _ := strings.Cut(app.Protocol, "/")
+	if (proto != "tcp" && proto != "udp") || !validPort(port) {
+		return ""
+	}
+	var origin string
+	switch {
+	case len(app.OriginDirect) == 1 && app.OriginDNS == nil:
+		scheme, addr, _ := strings.Cut(app.OriginDirect[0], "://")
+		if scheme != proto {
+			return ""
+		}
+		origin = addr
+	case len(app.OriginDirect) == 0 && app.OriginDNS != nil:
+		originPort := strings.Trim(string(app.OriginPort), `"`)
+		if !validPort(originPort) {
+			return ""
+		}
+		origin = net.JoinHostPort(strings.ToLower(strings.TrimSuffix(app.OriginDNS.Name, ".")), originPort)
+	default:
+		return ""
+	}
+	return app.Protocol + "," + origin
+}
+
+// spectrumApp returns the Spectrum application of name that target
+// declares.
+func spectrumApp(name, target string) cfSpectrumApp {
+	parts := strings.Split(target, ",")
+	proto, _, _ := strings.Cut(parts[0], "/")
+	host, port, _ := net.SplitHostPort(parts[1])
+
+	app := cfSpectrumApp{
+		Protocol:    parts[0],
+		DNS:         cfSpectrumDNS{Type: "CNAME", Name: name},
+		TrafficType: "direct",
+	}
+	if net.ParseIP(host) != nil {
+		app.OriginDirect = []string{proto + "://" + parts[1]}
+	} else {
+		app.OriginDNS = &cfSpectrumDNS{Name: host}
+		app.OriginPort = json.RawMessage(port)
+	}
+	return app
+}
+
+// spectrumAppsPerPage is the number of applications requested at a
+// time.
+const spectrumAppsPerPage = 100
+
+// getSpectrumApps returns the Spectrum applications of the zone id that
+// CF_SPECTRUM_APP can declare, as SPECTRUM_APP records.
+func (c *cloudflareProvider) getSpectrumApps(id string, domain string) ([]*models.RecordConfig, error) {
+	recs := []*models.RecordConfig{}
+	for page := 1; ; page++ {
+		var apps []cfSpectrumApp
+		endpoint := fmt.Sprintf("/zones/%s/spectrum/apps?page=%d&per_page=%d", id, page, spectrumAppsPerPage)
+		if _, err := c.request(http.MethodGet, endpoint, nil, &apps); err != nil {
+			return nil, fmt.Errorf("failed fetching spectrum application list from cloudflare: %w", err)
+		}
+		for _, app := range apps {
+			name := strings.ToLower(strings.TrimSuffix(app.DNS.Name, "."))
+			target := app.target()
+			if target == "" || (name != domain && !strings.HasSuffix(name, "."+domain)) {
+				continue
+			}
+			r := &models.RecordConfig{
+				Type:     "SPECTRUM_APP",
+				Original: app.ID,
+				TTL:      1,
+			}
+			r.SetLabelFromFQDN(name, domain)
+			r.SetTarget(target)
+			recs = append(recs, r)
+		}
+		if len(apps) < spectrumAppsPerPage {
+			return recs, nil
+		}
+	}
+}
+
+func (c *cloudflareProvider) createSpectrumApp(domainID string, rec *models.RecordConfig) error {
+	endpoint := fmt.Sprintf("/zones/%s/spectrum/apps", domainID)
+	_, err := c.request(http.MethodPost, endpoint, spectrumApp(rec.GetLabelFQDN(), rec.GetTargetField()), nil)
+	return err
+}
+
+func (c *cloudflareProvider) updateSpectrumApp(appID, domainID string, rec *models.RecordConfig) error {
+	endpoint := fmt.Sprintf("/zones/%s/spectrum/apps/%s", domainID, appID)
+	_, err := c.request(http.MethodPut, endpoint, spectrumApp(rec.GetLabelFQDN(), rec.GetTargetField()), nil)
+	return err
+}
+
+func (c *cloudflareProvider) deleteSpectrumApp(appID, domainID string) error {
+	endpoint := fmt.Sprintf("/zones/%s/spectrum/apps/%s", domainID, appID)
+	_, err := c.request(http.MethodDelete, endpoint, nil, nil)
+	return err
+}
@@ -0,0 +1,83 @@
+package cloudflare
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func makeSpectrumApp(label, target string) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: "CF_SPECTRUM_APP", Metadata: map[string]string{}}
+	rc.SetLabel(label, "example.com")
+	rc.SetTarget(target)
+	return rc
+}
+
+func TestSpectrumTarget(t *testing.T) {
+	tests := []struct {
+		target, want string
+	}{
+		{"tcp/22,192.0.2.1:22", "tcp/22,192.0.2.1:22"},
+		{"udp/27015,Origin.Example.com.:27015", "udp/27015,origin.example.com:27015"},
+		{"tcp/25,[2001:db8::1]:2525", "tcp/25,[2001:db8::1]:2525"},
+		{"http/80,192.0.2.1:80", ""},
+		{"tcp/70000,192.0.2.1:22", ""},
+		{"tcp/22,192.0.2.1", ""},
+		{"tcp/22,2001:db8::1:22", ""},
+	}
+	for _, tst := range tests {
+		got, err := spectrumTarget(makeSpectrumApp("ssh", tst.target))
+		if got != tst.want || (err == nil) != (tst.want != "") {
+			t.Errorf("spectrumTarget(%q) = %q, %v; want %q", tst.target, got, err, tst.want)
+		}
+	}
+}
+
+func TestSpectrumAppsMock(t *testing.T) {
+	c := newMockProviderMeta(t, `{"manage_spectrum": true}`)
+	id, err := c.getDomainID("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An HTTP application, which CF_SPECTRUM_APP doesn't manage.
+	web := cfSpectrumApp{
+		Protocol:     "tcp/80",
+		DNS:          cfSpectrumDNS{Type: "CNAME", Name: "www.example.com"},
+		OriginDirect: []string{"tcp://192.0.2.2:80"},
+		TrafficType:  "http",
+	}
+	if _, err := c.request(http.MethodPost, "/zones/"+id+"/spectrum/apps", web, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ssh := makeSpectrumApp("ssh", "tcp/22,192.0.2.1:22")
+	game := makeSpectrumApp("game", "udp/27015,origin.example.com:27015")
+	if n := pushMock(t, c, ssh, game); n != 2 {
+		t.Errorf("got %d corrections to create the applications, want 2", n)
+	}
+	ssh = makeSpectrumApp("ssh", "tcp/22,192.0.2.1:22")
+	game = makeSpectrumApp("game", "udp/27015,origin.example.com:27015")
+	if n := pushMock(t, c, ssh, game); n != 0 {
+		t.Errorf("got %d corrections once the applications exist, want 0", n)
+	}
+	// The origin of ssh changes; game goes.
+	if n := pushMock(t, c, makeSpectrumApp("ssh", "tcp/22,[2001:db8::1]:2222")); n != 2 {
+		t.Errorf("got %d corrections to change the applications, want 2", n)
+	}
+
+	recs, err := c.getSpectrumApps(id, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].GetLabel() != "ssh" || recs[0].GetTargetField() != "tcp/22,[2001:db8::1]:2222" {
+		t.Errorf("got applications %v, want the new one of ssh", recs)
+	}
+	var apps []cfSpectrumApp
+	if _, err := c.request(http.MethodGet, "/zones/"+id+"/spectrum/apps", nil, &apps); err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 2 || apps[0].TrafficType != "http" {
+		t.Errorf("got applications %+v, want the HTTP one and the one of ssh", apps)
+	}
+}
@@ -113,75 +113,51 @@ func (c *cloudnsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 			return nil, err
 		}
 
-		// Deletes first so changing type works etc.
-		for _, m := range del {
-			id := m.Existing.Original.(*domainRecord).ID
-			corr := &models.Correction{
-				Msg: fmt.Sprintf("%s, ClouDNS ID: %s", m.String(), id),
-				F: func() error {
-					return c.deleteRecord(domainID, id)
-				},
+		// At ClouDNS, a DS record MUST have NS records at its label.
+		// Order deletes the DS records first and creates them last.
+		for _, m := range diff.Order(create, del, modify) {
+			if m.Desired == nil {
+				id := m.Existing.Original.(*domainRecord).ID
+				corrections = append(corrections, &models.Correction{
+					Msg:  fmt.Sprintf("%s, ClouDNS ID: %s", m.String(), id),
+					Kind: models.CorrectionDestroy,
+					F: func() error {
+						return c.deleteRecord(domainID, id)
+					},
+				})
+				continue
 			}
-			// at ClouDNS, we MUST have a NS for a DS
-			// So, when deleting, we must delete the DS first, otherwise deleting the NS throws an error
-			if m.Existing.Type == "DS" {
-				// type DS is prepended - so executed first
-				corrections = append([]*models.Correction{corr}, corrections...)
-			} else {
-				corrections = append(corrections, corr)
-			}
-		}
 
-		var createCorrections []*models.Correction
-		for _, m := range create {
 			req, err := toReq(m.Desired)
 			if err != nil {
 				return nil, err
 			}
 
-			// ClouDNS does not require the trailing period to be specified when creating an NS record where the A or AAAA record exists in the zone.
+			// ClouDNS does not require the trailing period to be specified when creating or updating an NS record where the A or AAAA record exists in the zone.
 			// So, modify it to remove the trailing period.
 			if req["record-type"] == "NS" && strings.HasSuffix(req["record"], domainID+".") {
 				req["record"] = strings.TrimSuffix(req["record"], ".")
 			}
 
-			corr := &models.Correction{
-				Msg: m.String(),
-				F: func() error {
-					return c.createRecord(domainID, req)
-				},
-			}
-			// at ClouDNS, we MUST have a NS for a DS
-			// So, when creating, we must create the NS first, otherwise creating the DS throws an error
-			if m.Desired.Type == "NS" {
-				// type NS is prepended - so executed first
-				createCorrections = append([]*models.Correction{corr}, createCorrections...)
-			} else {
-				createCorrections = append(createCorrections, corr)
+			if m.Existing == nil {
+				corrections = append(corrections, &models.Correction{
+					Msg:  m.String(),
+					Kind: models.CorrectionCreate,
+					F: func() error {
+						return c.createRecord(domainID, req)
+					},
+				})
+				continue
 			}
-		}
-		corrections = append(corrections, createCorrections...)
 
-		for _, m := range modify {
 			id := m.Existing.Original.(*domainRecord).ID
-			req, err := toReq(m.Desired)
-			if err != nil {
-				return nil, err
-			}
-
-			// ClouDNS does not require the trailing period to be specified when updating an NS record where the A or AAAA record exists in the zone.
-			// So, modify it to remove the trailing period.
-			if req["record-type"] == "NS" && strings.HasSuffix(req["record"], domainID+".") {
-				req["record"] = strings.TrimSuffix(req["record"], ".")
-			}
-
-			corr := &models.Correction{
-				Msg: fmt.Sprintf("%s, ClouDNS ID: %s: ", m.String(), id),
+			corrections = append(corrections, &models.Correction{
+				Msg:  fmt.Sprintf("%s, ClouDNS ID: %s: ", m.String(), id),
+				Kind: models.CorrectionModify,
 				F: func() error {
 					return c.modifyRecord(domainID, id, req)
 				},
-			}
-			corrections = append(corrections, corr)
+			})
 		}
 
 		return corrections, nil
@@ -0,0 +1,201 @@
+package constellix
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/ratelimit"
+)
+
+const (
+	baseURL = "https://api.dns.constellix.com/v4"
+)
+
+type constellixProvider struct {
+	apiKey    string
+	secretKey string
+	baseURL   string
+	client    *http.Client
+	limiter   *ratelimit.Limiter
+
+	domains map[string]domain // Cache of the domains, by name.
+	pools   []pool            // Cache of the pools of the account.
+}
+
+// token returns the value of the Authorization header: the API key, the
+// HMAC-SHA1 of the current time in milliseconds keyed with the secret
+// key, and the time.
+func (api *constellixProvider) token(now time.Time) string {
+	ts := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha1.New, []byte(api.secretKey))
+	mac.Write([]byte(ts))
+	return fmt.Sprintf("Bearer %s:%s:%s", api.apiKey, base64.StdEncoding.EncodeToString(mac.Sum(nil)), ts)
+}
+
+func (api *constellixProvider) request(endpoint string, method string, request interface{}, target interface{}) error {
+	var body io.Reader
+	if request != nil {
+		b, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, api.baseURL+endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", api.token(time.Now()))
+
+	api.limiter.Wait()
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(resp.Body)
+		var e errorResponse
+		if json.Unmarshal(data, &e) == nil && len(e.Errors) > 0 {
+			return fmt.Errorf("constellix: %s %s: %s", method, endpoint, strings.Join(e.Errors, "; "))
+		}
+		return fmt.Errorf("constellix: %s %s: %s", method, endpoint, resp.Status)
+	}
+	if target == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func (api *constellixProvider) fetchDomains() error {
+	api.domains = map[string]domain{}
+	for page := 1; ; page++ {
+		response := &domainsResponse{}
+		if err := api.request(fmt.Sprintf("/domains?page=%d", page), "GET", nil, response); err != nil {
+			return fmt.Errorf("failed listing domains: %w", err)
+		}
+		for _, d := range response.Data {
+			api.domains[d.Name] = d
+		}
+		if response.lastPage() {
+			return nil
+		}
+	}
+}
+
+func (api *constellixProvider) getDomain(name string) (domain, error) {
+	if api.domains == nil {
+		if err := api.fetchDomains(); err != nil {
+			return domain{}, err
+		}
+	}
+	d, ok := api.domains[name]
+	if !ok {
+		return domain{}, fmt.Errorf("%q is not a domain in your Constellix account", name)
+	}
+	return d, nil
+}
+
+func (api *constellixProvider) createDomain(name string) error {
+	response := &domainResponse{}
+	if err := api.request("/domains", "POST", createDomainRequest{Name: name}, response); err != nil {
+		return err
+	}
+	api.domains[response.Data.Name] = response.Data
+	return nil
+}
+
+func (api *constellixProvider) getRecords(domainID int) ([]record, error) {
+	var records []record
+	for page := 1; ; page++ {
+		response := &recordsResponse{}
+		if err := api.request(fmt.Sprintf("/domains/%d/records?page=%d", domainID, page), "GET", nil, response); err != nil {
+			return nil, err
+		}
+		records = append(records, response.Data...)
+		if response.lastPage() {
+			return records, nil
+		}
+	}
+}
+
+func (api *constellixProvider) createRecord(domainID int, r *record) error {
+	return api.request(fmt.Sprintf("/domains/%d/records", domainID), "POST", r, nil)
+}
+
+func (api *constellixProvider) updateRecord(domainID int, r *record) error {
+	return api.request(fmt.Sprintf("/domains/%d/records/%d", domainID, r.ID), "PUT", r, nil)
+}
+
+func (api *constellixProvider) deleteRecord(domainID int, recordID int) error {
+	return api.request(fmt.Sprintf("/domains/%d/records/%d", domainID, recordID), "DELETE", nil, nil)
+}
+
+func (api *constellixProvider) getPools() ([]pool, error) {
+	if api.pools != nil {
+		return api.pools, nil
+	}
+	pools := []pool{}
+	for page := 1; ; page++ {
+		response := &poolsResponse{}
+		if err := api.request(fmt.Sprintf("/pools?page=%d", page), "GET", nil, response); err != nil {
+			return nil, fmt.Errorf("failed listing pools: %w", err)
+		}
+		pools = append(pools, response.Data...)
+		if response.lastPage() {
+			api.pools = pools
+			return pools, nil
+		}
+	}
+}
+
+// findPool returns the pool of rtype with the given name or ID, or nil.
+func (api *constellixProvider) findPool(rtype string, name string, id int) (*pool, error) {
+	pools, err := api.getPools()
+	if err != nil {
+		return nil, err
+	}
+	for i := range pools {
+		p := &pools[i]
+		if p.Type == rtype && ((name != "" && p.Name == name) || (id != 0 && p.ID == id)) {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// ensurePool creates the pool p, or updates its values if a pool of
+// that type and name exists. It returns the ID of the pool.
+func (api *constellixProvider) ensurePool(p pool) (int, error) {
+	existing, err := api.findPool(p.Type, p.Name, 0)
+	if err != nil {
+		return 0, err
+	}
+	response := &poolResponse{}
+	if existing == nil {
+		if err := api.request("/pools", "POST", p, response); err != nil {
+			return 0, err
+		}
+		api.pools = append(api.pools, response.Data)
+		return response.Data.ID, nil
+	}
+	p.ID = existing.ID
+	if err := api.request(fmt.Sprintf("/pools/%s/%d", p.Type, p.ID), "PUT", p, nil); err != nil {
+		return 0, err
+	}
+	*existing = p
+	return p.ID, nil
+}
@@ -0,0 +1,25 @@
+package constellix
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/rejectif"
+)
+
+// AuditRecords returns a list of errors corresponding to the records
+// that aren't supported by this provider.  If all records are
+// supported, an empty list is returned.
+func AuditRecords(records []*models.RecordConfig) []error {
+	a := rejectif.Auditor{}
+
+	a.Add("CAA", rejectif.CaaTargetContainsWhitespace)
+
+	errs := a.Audit(records)
+	for _, rc := range records {
+		if err := checkMetadata(rc); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", rc.Type, rc.GetLabelFQDN(), err))
+		}
+	}
+	return errs
+}
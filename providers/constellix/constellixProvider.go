@@ -0,0 +1,223 @@
+package constellix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ratelimit"
+	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+var features = providers.DocumentationNotes{
+	providers.CanGetZones:            providers.Can(),
+	providers.CanUseAlias:            providers.Can("Stored as ANAME records"),
+	providers.CanUseCAA:              providers.Can(),
+	providers.CanUseDS:               providers.Cannot(),
+	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseSRV:              providers.Can(),
+	providers.CanUseSSHFP:            providers.Cannot(),
+	providers.CanUseTLSA:             providers.Cannot(),
+	providers.DocCreateDomains:       providers.Can(),
+	providers.DocDualHost:            providers.Cannot("Apex NS records are managed by Constellix"),
+	providers.DocOfficiallySupported: providers.Cannot(),
+}
+
+func init() {
+	fns := providers.DspFuncs{
+		Initializer:   New,
+		RecordAuditor: AuditRecords,
+	}
+	providers.RegisterDomainServiceProviderType("CONSTELLIX", fns, features, txtutil.SplitLong)
+	diff2.RegisterComparer("CONSTELLIX", &diff2.Comparer{Comparable: constellixComparable})
+}
+
+// New creates a new API handle.
+func New(settings map[string]string, _ json.RawMessage) (providers.DNSServiceProvider, error) {
+	if settings["api_key"] == "" || settings["secret_key"] == "" {
+		return nil, fmt.Errorf("missing CONSTELLIX api_key or secret_key")
+	}
+	return &constellixProvider{
+		apiKey:    settings["api_key"],
+		secretKey: settings["secret_key"],
+		baseURL:   baseURL,
+		client:    &http.Client{},
+		limiter:   ratelimit.New(10, 10), // Constellix limits the requests of each API key.
+	}, nil
+}
+
+// RateLimit returns the limiter of the requests to the Constellix API.
+func (api *constellixProvider) RateLimit() *ratelimit.Limiter {
+	return api.limiter
+}
+
+// ListZones lists the zones on this account.
+func (api *constellixProvider) ListZones() ([]string, error) {
+	if err := api.fetchDomains(); err != nil {
+		return nil, err
+	}
+	var zones []string
+	for name := range api.domains {
+		zones = append(zones, name)
+	}
+	return zones, nil
+}
+
+// EnsureDomainExists creates the domain if it does not exist.
+func (api *constellixProvider) EnsureDomainExists(name string) error {
+	if err := api.fetchDomains(); err != nil {
+		return err
+	}
+	if _, ok := api.domains[name]; ok {
+		return nil
+	}
+	return api.createDomain(name)
+}
+
+// GetNameservers returns the nameservers for a domain.
+func (api *constellixProvider) GetNameservers(name string) ([]*models.Nameserver, error) {
+	d, err := api.getDomain(name)
+	if err != nil {
+		return nil, err
+	}
+	return models.ToNameserversStripTD(d.Nameservers)
+}
+
+// GetZoneRecords gets the records of a zone and returns them in RecordConfig format.
+func (api *constellixProvider) GetZoneRecords(name string) (models.Records, error) {
+	d, err := api.getDomain(name)
+	if err != nil {
+		return nil, err
+	}
+	native, err := api.getRecords(d.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching zone records for %q: %w", name, err)
+	}
+	var existingRecords models.Records
+	for _, r := range native {
+		records, err := api.toRecordConfigs(r, name)
+		if err != nil {
+			return nil, err
+		}
+		existingRecords = append(existingRecords, records...)
+	}
+	return existingRecords, nil
+}
+
+// GetDomainCorrections returns the corrections for a domain.
+func (api *constellixProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	dc, err := dc.Copy()
+	if err != nil {
+		return nil, err
+	}
+	if err := dc.Punycode(); err != nil {
+		return nil, err
+	}
+	if err := checkRecordSets(dc.Records); err != nil {
+		return nil, err
+	}
+
+	d, err := api.getDomain(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	existingRecords, err := api.GetZoneRecords(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize
+	models.PostProcessRecords(existingRecords)
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
+
+	changes, err := diff2.GetComparer("CONSTELLIX").ByRecordSet(existingRecords, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	var corrections []*models.Correction
+	for _, change := range changes {
+		change := change
+		msg := strings.Join(change.Msgs, "\n")
+		switch change.Type {
+		case diff2.CREATE:
+			corrections = append(corrections, &models.Correction{
+				Msg:  msg,
+				Kind: change.Kind(),
+				F: func() error {
+					poolID, err := api.ensurePoolOf(change.New)
+					if err != nil {
+						return err
+					}
+					return api.createRecord(d.ID, nativeRecord(change.New, poolID))
+				},
+			})
+		case diff2.CHANGE:
+			old := change.Old[0].Original.(*record)
+			if poolOnly(change.Old, change.New) {
+				// The record set still returns the same pool: only
+				// the values of the pool change.
+				corrections = append(corrections, &models.Correction{
+					Msg:  msg,
+					Kind: change.Kind(),
+					F: func() error {
+						_, err := api.ensurePool(nativePool(change.New))
+						return err
+					},
+				})
+				continue
+			}
+			corrections = append(corrections, &models.Correction{
+				Msg:  msg,
+				Kind: change.Kind(),
+				F: func() error {
+					poolID, err := api.ensurePoolOf(change.New)
+					if err != nil {
+						return err
+					}
+					r := nativeRecord(change.New, poolID)
+					r.ID = old.ID
+					return api.updateRecord(d.ID, r)
+				},
+			})
+		case diff2.DELETE:
+			// The pool isn't deleted, as other records may use it.
+			old := change.Old[0].Original.(*record)
+			corrections = append(corrections, &models.Correction{
+				Msg:  msg,
+				Kind: change.Kind(),
+				F:    func() error { return api.deleteRecord(d.ID, old.ID) },
+			})
+		}
+	}
+	return corrections, nil
+}
+
+// ensurePoolOf creates or updates the pool of the record set recs, if
+// it is in one, and returns its ID.
+func (api *constellixProvider) ensurePoolOf(recs models.Records) (int, error) {
+	if recs[0].Metadata[metaPool] == "" {
+		return 0, nil
+	}
+	return api.ensurePool(nativePool(recs))
+}
+
+// poolOnly reports whether the record sets existing and desired are in
+// the same pool with the same TTL, so that only the values of the pool
+// differ.
+func poolOnly(existing, desired models.Records) bool {
+	pool := desired[0].Metadata[metaPool]
+	if pool == "" || existing[0].TTL != desired[0].TTL {
+		return false
+	}
+	for _, rc := range existing {
+		if rc.Metadata[metaPool] != pool {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package constellix
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+const pageMeta = `"meta": {"pagination": {"currentPage": 1, "lastPage": 1}}`
+
+// apiServer answers the requests to the Constellix API for the domain
+// example.com, and records the requests that change anything.
+func apiServer(t *testing.T, changes *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer key:") {
+			http.Error(w, `{"errors": ["unauthorized"]}`, http.StatusUnauthorized)
+			return
+		}
+		if r.Method != "GET" {
+			body, _ := io.ReadAll(r.Body)
+			*changes = append(*changes, r.Method+" "+r.URL.Path+" "+string(body))
+			w.Write([]byte(`{"data": {"id": 99}}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/domains":
+			w.Write([]byte(`{"data": [{"id": 1, "name": "example.com", "nameservers": ["ns11.constellix.com."]}], ` + pageMeta + `}`))
+		case "/domains/1/records":
+			w.Write([]byte(`{"data": [
+				{"id": 10, "name": "", "type": "aname", "ttl": 300, "mode": "standard", "enabled": true,
+				 "value": [{"value": "lb.example.net.", "enabled": true}]},
+				{"id": 11, "name": "www", "type": "a", "ttl": 300, "mode": "roundRobinFailover", "enabled": true,
+				 "value": {"enabled": true, "values": [{"value": "1.2.3.4", "order": 1, "enabled": true}, {"value": "5.6.7.8", "order": 2, "enabled": true}]}},
+				{"id": 12, "name": "app", "type": "a", "ttl": 300, "mode": "pools", "enabled": true, "value": [7]},
+				{"id": 13, "name": "", "type": "mx", "ttl": 300, "mode": "standard", "enabled": true,
+				 "value": [{"server": "mx.example.com.", "priority": 10, "enabled": true}]}
+			], ` + pageMeta + `}`))
+		case "/pools":
+			w.Write([]byte(`{"data": [{"id": 7, "name": "web", "type": "a", "return": 2, "minimumFailover": 1,
+				"values": [{"value": "10.0.0.1", "enabled": true}, {"value": "10.0.0.2", "enabled": true}]}], ` + pageMeta + `}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func newTestProvider(server *httptest.Server) *constellixProvider {
+	return &constellixProvider{
+		apiKey:    "key",
+		secretKey: "secret",
+		baseURL:   server.URL,
+		client:    server.Client(),
+	}
+}
+
+func makeRC(label, rtype, content string, meta map[string]string) *models.RecordConfig {
+	rc := &models.RecordConfig{TTL: 300, Metadata: meta}
+	rc.SetLabel(label, "example.com")
+	if err := rc.PopulateFromString(rtype, content, "example.com"); err != nil {
+		panic(err)
+	}
+	return rc
+}
+
+func TestGetZoneRecords(t *testing.T) {
+	var changes []string
+	server := apiServer(t, &changes)
+	defer server.Close()
+	api := newTestProvider(server)
+
+	records, err := api.GetZoneRecords("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, rc := range records {
+		got = append(got, rc.GetLabel()+" "+rc.Type+" "+rc.GetTargetCombined()+" "+constellixComparable(rc))
+	}
+	want := []string{
+		"@ ALIAS lb.example.net. ",
+		"www A 1.2.3.4 constellix_order=1",
+		"www A 5.6.7.8 constellix_order=2",
+		"app A 10.0.0.1 constellix_pool=web",
+		"app A 10.0.0.2 constellix_pool=web",
+		"@ MX 10 mx.example.com. ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPoolCorrections(t *testing.T) {
+	var changes []string
+	server := apiServer(t, &changes)
+	defer server.Close()
+	api := newTestProvider(server)
+
+	web := map[string]string{metaPool: "web"}
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: models.Records{
+			makeRC("@", "ALIAS", "lb.example.net.", nil),
+			makeRC("www", "A", "1.2.3.4", map[string]string{metaOrder: "1"}),
+			makeRC("www", "A", "5.6.7.8", map[string]string{metaOrder: "2"}),
+			makeRC("@", "MX", "10 mx.example.com.", nil),
+			// A value is added to the pool.
+			makeRC("app", "A", "10.0.0.1", web),
+			makeRC("app", "A", "10.0.0.2", web),
+			makeRC("app", "A", "10.0.0.3", web),
+		},
+	}
+	corrections, err := api.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 1 {
+		t.Fatalf("got %d corrections, want 1", len(corrections))
+	}
+	if err := corrections[0].F(); err != nil {
+		t.Fatal(err)
+	}
+	// Only the pool is updated, not the record.
+	if len(changes) != 1 || !strings.HasPrefix(changes[0], "PUT /pools/a/7 ") {
+		t.Fatalf("got %q, want an update of the pool", changes)
+	}
+	var p pool
+	if err := json.Unmarshal([]byte(strings.SplitN(changes[0], " ", 3)[2]), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "web" || len(p.Values) != 3 || p.Return != 3 {
+		t.Errorf("got pool %+v, want web with 3 values", p)
+	}
+}
+
+func TestCheckRecordSets(t *testing.T) {
+	web := map[string]string{metaPool: "web"}
+	for _, tc := range []struct {
+		name    string
+		records models.Records
+		wantErr bool
+	}{
+		{"same pool", models.Records{makeRC("a", "A", "1.1.1.1", web), makeRC("b", "A", "1.1.1.1", web)}, false},
+		{"mixed modes", models.Records{makeRC("a", "A", "1.1.1.1", web), makeRC("a", "A", "2.2.2.2", nil)}, true},
+		{"pool differs", models.Records{makeRC("a", "A", "1.1.1.1", web), makeRC("b", "A", "2.2.2.2", web)}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkRecordSets(tc.records)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("checkRecordSets() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
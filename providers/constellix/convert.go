@@ -0,0 +1,296 @@
+package constellix
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// Constellix returns the values of a record in turn, or in the order
+// of failover, or from a pool. This is given with metadata on the
+// records of the record set:
+//
+//	// Failover: 1.2.3.4 while it is up, then 5.6.7.8.
+//	A("www", "1.2.3.4", {constellix_order: "1"}),
+//	A("www", "5.6.7.8", {constellix_order: "2"}),
+//	// The pool "web", with the values 1.2.3.4 and 5.6.7.8.
+//	A("@", "1.2.3.4", {constellix_pool: "web"}),
+//	A("@", "5.6.7.8", {constellix_pool: "web"}),
+//
+// The records of a pool are its values, so adding or removing one only
+// updates the pool.
+const (
+	metaOrder = "constellix_order"
+	metaPool  = "constellix_pool"
+)
+
+// hasModes reports whether records of rtype may use failover and pools.
+func hasModes(rtype string) bool {
+	return rtype == "A" || rtype == "AAAA" || rtype == "CNAME"
+}
+
+// checkMetadata returns an error if the Constellix metadata of rc is
+// invalid.
+func checkMetadata(rc *models.RecordConfig) error {
+	order, pool := rc.Metadata[metaOrder], rc.Metadata[metaPool]
+	if order == "" && pool == "" {
+		return nil
+	}
+	if !hasModes(rc.Type) {
+		return fmt.Errorf("%s and %s are only supported on A, AAAA and CNAME records", metaOrder, metaPool)
+	}
+	if order != "" && pool != "" {
+		return fmt.Errorf("a record can't have both %s and %s", metaOrder, metaPool)
+	}
+	if n, err := strconv.Atoi(order); order != "" && (err != nil || n < 1) {
+		return fmt.Errorf("%s must be a number from 1, not %q", metaOrder, order)
+	}
+	return nil
+}
+
+// checkRecordSets returns an error if the records of a record set don't
+// have the same mode, or the records of a pool aren't the same
+// wherever the pool is used.
+func checkRecordSets(records models.Records) error {
+	modes := map[models.RecordKey]string{}
+	pools := map[poolKey]map[models.RecordKey][]string{}
+	for _, rc := range records {
+		if !hasModes(rc.Type) {
+			continue
+		}
+		mode := modeOf(rc)
+		if m, ok := modes[rc.Key()]; ok && m != mode {
+			return fmt.Errorf("%s %s: all the records must have %s, or the same %s, or neither", rc.Type, rc.GetLabelFQDN(), metaOrder, metaPool)
+		}
+		modes[rc.Key()] = mode
+		if name := rc.Metadata[metaPool]; name != "" {
+			key := poolKey{rc.Type, name}
+			if pools[key] == nil {
+				pools[key] = map[models.RecordKey][]string{}
+			}
+			pools[key][rc.Key()] = append(pools[key][rc.Key()], rc.GetTargetField())
+		}
+	}
+	for key, sets := range pools {
+		var first string
+		for rk, values := range sets {
+			sort.Strings(values)
+			v := strings.Join(values, " ")
+			if first != "" && v != first {
+				return fmt.Errorf("%s %s: the pool %q has other values elsewhere in the domain", rk.Type, rk.NameFQDN, key.name)
+			}
+			first = v
+		}
+	}
+	return nil
+}
+
+type poolKey struct {
+	rtype, name string
+}
+
+// modeOf returns the mode of the record set of rc, with the name of
+// its pool.
+func modeOf(rc *models.RecordConfig) string {
+	if name := rc.Metadata[metaPool]; name != "" {
+		return metaPool + ":" + name
+	}
+	if rc.Metadata[metaOrder] != "" {
+		return metaOrder
+	}
+	return modeStandard
+}
+
+// constellixComparable returns the metadata of r that the diff2
+// Comparer of CONSTELLIX compares.
+func constellixComparable(r *models.RecordConfig) string {
+	if name := r.Metadata[metaPool]; name != "" {
+		return metaPool + "=" + name
+	}
+	if order := r.Metadata[metaOrder]; order != "" {
+		return metaOrder + "=" + order
+	}
+	return ""
+}
+
+// fqdn returns name with a trailing dot.
+func fqdn(name string) string {
+	if name == "" || strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// toRecordConfigs returns the records of the record set r.
+func (api *constellixProvider) toRecordConfigs(r record, origin string) (models.Records, error) {
+	rtype := strings.ToUpper(r.Type)
+	var values []recordValue
+	var meta []map[string]string
+	switch r.Mode {
+	case modeStandard, "":
+		if err := json.Unmarshal(r.Value, &values); err != nil {
+			return nil, err
+		}
+	case modeFailover:
+		var fv failoverValue
+		if err := json.Unmarshal(r.Value, &fv); err != nil {
+			return nil, err
+		}
+		values = fv.Values
+		for _, v := range values {
+			meta = append(meta, map[string]string{metaOrder: strconv.Itoa(v.Order)})
+		}
+	case modePools:
+		var ids []int
+		if err := json.Unmarshal(r.Value, &ids); err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			p, err := api.findPool(r.Type, "", id)
+			if err != nil {
+				return nil, err
+			}
+			if p == nil {
+				return nil, fmt.Errorf("%s record %q uses the pool %d, which doesn't exist", rtype, r.Name, id)
+			}
+			for _, pv := range p.Values {
+				values = append(values, recordValue{Value: pv.Value, Enabled: pv.Enabled})
+				meta = append(meta, map[string]string{metaPool: p.Name})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%s record %q has the unsupported mode %q", rtype, r.Name, r.Mode)
+	}
+
+	records := make(models.Records, 0, len(values))
+	for i, v := range values {
+		rc := &models.RecordConfig{
+			TTL:      r.TTL,
+			Original: &r,
+		}
+		rc.SetLabel(r.Name, origin)
+		if r.Name == "" {
+			rc.SetLabel("@", origin)
+		}
+		if meta != nil {
+			rc.Metadata = meta[i]
+		}
+		var err error
+		switch rtype {
+		case "ANAME":
+			rc.Type = "ALIAS"
+			err = rc.SetTarget(fqdn(v.Value))
+		case "MX":
+			rc.Type = rtype
+			err = rc.SetTargetMX(v.Priority, fqdn(v.Server))
+		case "SRV":
+			rc.Type = rtype
+			err = rc.SetTargetSRV(v.Priority, v.Weight, v.Port, fqdn(v.Host))
+		case "CAA":
+			rc.Type = rtype
+			err = rc.SetTargetCAA(v.Flag, v.Tag, v.Data)
+		case "TXT":
+			rc.Type = rtype
+			err = rc.SetTargetTXTfromRFC1035Quoted(v.Value)
+		case "CNAME", "NS", "PTR":
+			rc.Type = rtype
+			err = rc.SetTarget(fqdn(v.Value))
+		default:
+			rc.Type = rtype
+			err = rc.SetTarget(v.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rc)
+	}
+	return records, nil
+}
+
+// nativeValue returns the value of rc, in standard or
+// roundRobinFailover mode.
+func nativeValue(rc *models.RecordConfig) map[string]interface{} {
+	v := map[string]interface{}{"enabled": true}
+	switch rc.Type {
+	case "MX":
+		v["server"] = rc.GetTargetField()
+		v["priority"] = rc.MxPreference
+	case "SRV":
+		v["host"] = rc.GetTargetField()
+		v["priority"] = rc.SrvPriority
+		v["weight"] = rc.SrvWeight
+		v["port"] = rc.SrvPort
+	case "CAA":
+		v["flag"] = rc.CaaFlag
+		v["tag"] = rc.CaaTag
+		v["data"] = rc.GetTargetField()
+	case "TXT":
+		v["value"] = rc.GetTargetRFC1035Quoted()
+	default:
+		v["value"] = rc.GetTargetField()
+	}
+	if order := rc.Metadata[metaOrder]; order != "" {
+		v["order"], _ = strconv.Atoi(order) // Validated by AuditRecords.
+	}
+	return v
+}
+
+// nativeRecord returns the record set of recs. A record set in a pool
+// returns the pool with the ID poolID.
+func nativeRecord(recs models.Records, poolID int) *record {
+	first := recs[0]
+	r := &record{
+		Name:    first.GetLabel(),
+		Type:    strings.ToLower(first.Type),
+		TTL:     first.TTL,
+		Mode:    modeStandard,
+		Enabled: true,
+	}
+	if r.Name == "@" {
+		r.Name = ""
+	}
+	if first.Type == "ALIAS" {
+		r.Type = "aname"
+	}
+
+	var value interface{}
+	switch mode := modeOf(first); {
+	case mode == metaOrder:
+		r.Mode = modeFailover
+		var values []map[string]interface{}
+		for _, rc := range recs {
+			values = append(values, nativeValue(rc))
+		}
+		value = map[string]interface{}{"enabled": true, "values": values}
+	case mode != modeStandard:
+		r.Mode = modePools
+		value = []int{poolID}
+	default:
+		var values []map[string]interface{}
+		for _, rc := range recs {
+			values = append(values, nativeValue(rc))
+		}
+		value = values
+	}
+	r.Value, _ = json.Marshal(value)
+	return r
+}
+
+// nativePool returns the pool of the record set recs, which returns all
+// of the values that are up.
+func nativePool(recs models.Records) pool {
+	p := pool{
+		Name:            recs[0].Metadata[metaPool],
+		Type:            strings.ToLower(recs[0].Type),
+		Return:          len(recs),
+		MinimumFailover: 1,
+	}
+	for _, rc := range recs {
+		p.Values = append(p.Values, poolValue{Value: rc.GetTargetField(), Enabled: true})
+	}
+	return p
+}
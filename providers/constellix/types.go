@@ -0,0 +1,111 @@
+package constellix
+
+import "encoding/json"
+
+// The modes of a record, which decide the format of its value.
+const (
+	modeStandard = "standard"
+	modeFailover = "roundRobinFailover"
+	modePools    = "pools"
+)
+
+type pagination struct {
+	Meta struct {
+		Pagination struct {
+			CurrentPage int `json:"currentPage"`
+			LastPage    int `json:"lastPage"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+func (p pagination) lastPage() bool {
+	return p.Meta.Pagination.CurrentPage >= p.Meta.Pagination.LastPage
+}
+
+type domain struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Nameservers []string `json:"nameservers"`
+}
+
+type domainsResponse struct {
+	Data []domain `json:"data"`
+	pagination
+}
+
+type domainResponse struct {
+	Data domain `json:"data"`
+}
+
+type createDomainRequest struct {
+	Name string `json:"name"`
+}
+
+// record is a record set: all the values of a name and type.
+type record struct {
+	ID      int             `json:"id,omitempty"`
+	Name    string          `json:"name"`
+	Type    string          `json:"type"`
+	TTL     uint32          `json:"ttl"`
+	Mode    string          `json:"mode"`
+	Enabled bool            `json:"enabled"`
+	Value   json.RawMessage `json:"value"`
+}
+
+type recordsResponse struct {
+	Data []record `json:"data"`
+	pagination
+}
+
+// recordValue is one value of a record in standard mode, or in
+// roundRobinFailover mode with its Order. The fields that are used
+// depend on the type of the record.
+type recordValue struct {
+	Value    string `json:"value"`
+	Enabled  bool   `json:"enabled"`
+	Order    int    `json:"order"`
+	Server   string `json:"server"`   // MX
+	Priority uint16 `json:"priority"` // MX, SRV
+	Host     string `json:"host"`     // SRV
+	Weight   uint16 `json:"weight"`   // SRV
+	Port     uint16 `json:"port"`     // SRV
+	Flag     uint8  `json:"flag"`     // CAA
+	Tag      string `json:"tag"`      // CAA
+	Data     string `json:"data"`     // CAA
+}
+
+// failoverValue is the value of a record in roundRobinFailover mode.
+type failoverValue struct {
+	Enabled bool          `json:"enabled"`
+	Values  []recordValue `json:"values"`
+}
+
+// A pool is a list of values that records in pools mode return, in
+// turn, leaving out the values that fail their checks. Pools belong to
+// the account, so several records may use the same pool.
+type pool struct {
+	ID              int         `json:"id,omitempty"`
+	Name            string      `json:"name"`
+	Type            string      `json:"type"`
+	Return          int         `json:"return"`
+	MinimumFailover int         `json:"minimumFailover"`
+	Values          []poolValue `json:"values"`
+}
+
+type poolValue struct {
+	Value   string `json:"value"`
+	Enabled bool   `json:"enabled"`
+}
+
+type poolsResponse struct {
+	Data []pool `json:"data"`
+	pagination
+}
+
+type poolResponse struct {
+	Data pool `json:"data"`
+}
+
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
@@ -175,7 +175,8 @@ func (client *providerClient) GenerateDomainCorrections(dc *models.DomainConfig,
 	}
 	if len(edits) > 0 {
 		c := &models.Correction{
-			Msg: "\t" + strings.Join(descriptions, "\n\t"),
+			Msg:  "\t" + strings.Join(descriptions, "\n\t"),
+			Kind: diff.Kind(creates, dels, modifications),
 			F: func() error {
 				// CSCGlobal's API only permits one pending update at a time.
 				// Therefore we block until any outstanding updates are done.
@@ -31,7 +31,8 @@ func (client *providerClient) GetRegistrarCorrections(dc *models.DomainConfig) (
 	if foundNameservers != expectedNameservers {
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+				Msg:  fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+				Kind: models.CorrectionModify,
 				F: func() error {
 					return client.updateNameservers(expected, dc.Name)
 				},
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
@@ -65,7 +66,7 @@ func init() {
 		Initializer:   NewDeSec,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("DESEC", fns, features)
+	providers.RegisterDomainServiceProviderType("DESEC", fns, features, txtutil.SplitLong)
 }
 
 // GetNameservers returns the nameservers for a domain.
@@ -112,6 +113,37 @@ func (c *desecProvider) GetZoneRecords(domain string) (models.Records, error) {
 	return existingRecords, nil
 }
 
+// GetDSRecords returns the DS records of the domain's keys, which the
+// registrar publishes in the parent zone.
+func (c *desecProvider) GetDSRecords(domain string) (models.Records, error) {
+	dm, err := c.getDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	var ds models.Records
+	for _, key := range dm.Keys {
+		for _, value := range key.Ds {
+			rc := &models.RecordConfig{Type: "DS"}
+			rc.SetLabel("@", domain)
+			if err := rc.SetTargetDSString(value); err != nil {
+				return nil, fmt.Errorf("invalid DS record %q received from deSEC: %w", value, err)
+			}
+			ds = append(ds, rc)
+		}
+	}
+	return ds, nil
+}
+
+// GetZoneVersion returns the time the records of the domain were last
+// changed.
+func (c *desecProvider) GetZoneVersion(domain string) (string, error) {
+	dm, err := c.getDomain(domain)
+	if err != nil {
+		return "", err
+	}
+	return dm.Touched.UTC().Format(time.RFC3339Nano), nil
+}
+
 // EnsureDomainExists returns an error if domain doesn't exist.
 func (c *desecProvider) EnsureDomainExists(domain string) error {
 	// domain already exists
@@ -139,7 +171,7 @@ func PrepDesiredRecords(dc *models.DomainConfig, minTTL uint32) {
 	// confusing.
 
 	dc.Punycode()
-	txtutil.SplitSingleLongTxt(dc.Records)
+	txtutil.SplitLong.Apply(dc.Records)
 	recordsToKeep := make([]*models.RecordConfig, 0, len(dc.Records))
 	for _, rec := range dc.Records {
 		if rec.Type == "ALIAS" {
@@ -178,14 +210,17 @@ func (c *desecProvider) GenerateDomainCorrections(dc *models.DomainConfig, exist
 			return nil, nil
 		}
 
+		existingRecords := existing.GroupedByKey()
 		desiredRecords := dc.Records.GroupedByKey()
 		var rrs []resourceRecord
+		var kind models.CorrectionKind
 		buf := &bytes.Buffer{}
 		// For any key with an update, delete or replace those records.
 		for label := range keysToUpdate {
 			if _, ok := desiredRecords[label]; !ok {
 				//we could not find this RecordKey in the desiredRecords
 				//this means it must be deleted
+				kind = kind.Max(models.CorrectionDestroy)
 				for i, msg := range keysToUpdate[label] {
 					if i == 0 {
 						rc := resourceRecord{}
@@ -206,6 +241,7 @@ func (c *desecProvider) GenerateDomainCorrections(dc *models.DomainConfig, exist
 				}
 			} else {
 				//it must be an update or create, both can be done with the same api call.
+				kind = kind.Max(diff.ReplaceKind(existingRecords[label], desiredRecords[label]))
 				ns := recordsToNative(desiredRecords[label], dc.Name)
 				if len(ns) > 1 {
 					panic("we got more than one resource record to create / modify")
@@ -224,7 +260,8 @@ func (c *desecProvider) GenerateDomainCorrections(dc *models.DomainConfig, exist
 		msg := fmt.Sprintf("Changes:\n%s", buf)
 		corrections = append(corrections,
 			&models.Correction{
-				Msg: msg,
+				Msg:  msg,
+				Kind: kind,
 				F: func() error {
 					rc := rrs
 					err := c.upsertRR(rc, dc.Name)
@@ -35,6 +35,7 @@ type domainObject struct {
 	MinimumTTL uint32      `json:"minimum_ttl,omitempty"`
 	Name       string      `json:"name,omitempty"`
 	Published  time.Time   `json:"published,omitempty"`
+	Touched    time.Time   `json:"touched,omitempty"`
 }
 
 type resourceRecord struct {
@@ -250,6 +251,18 @@ func (c *desecProvider) createDomain(domain string) error {
 	return nil
 }
 
+// getDomain returns the details of a domain, including its DNSSEC keys.
+func (c *desecProvider) getDomain(domain string) (domainObject, error) {
+	endpoint := fmt.Sprintf("/domains/%s/", domain)
+	var dm domainObject
+	bodyString, _, err := c.get(endpoint, "GET")
+	if err != nil {
+		return dm, fmt.Errorf("failed fetching domain %s (deSEC): %v", domain, err)
+	}
+	err = json.Unmarshal(bodyString, &dm)
+	return dm, err
+}
+
 // upsertRR will create or override the RRSet with the provided resource record.
 func (c *desecProvider) upsertRR(rr []resourceRecord, domain string) error {
 	endpoint := fmt.Sprintf("/domains/%s/rrsets/", domain)
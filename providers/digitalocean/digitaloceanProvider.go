@@ -4,13 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
 	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	"github.com/digitalocean/godo"
@@ -71,6 +71,8 @@ retry:
 	return api, nil
 }
 
+var logger = printer.Module("providers.digitalocean")
+
 var features = providers.DocumentationNotes{
 	providers.CanGetZones:            providers.Can(),
 	providers.CanUseCAA:              providers.Can(),
@@ -84,7 +86,7 @@ func init() {
 		Initializer:   NewDo,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("DIGITALOCEAN", fns, features)
+	providers.RegisterDomainServiceProviderType("DIGITALOCEAN", fns, features, txtutil.SplitLong)
 }
 
 // EnsureDomainExists returns an error if domain doesn't exist.
@@ -144,7 +146,7 @@ func (api *digitaloceanProvider) GetDomainCorrections(dc *models.DomainConfig) (
 
 	// Normalize
 	models.PostProcessRecords(existingRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	var create, delete, modify diff.Changeset
@@ -163,7 +165,8 @@ func (api *digitaloceanProvider) GetDomainCorrections(dc *models.DomainConfig) (
 	for _, m := range delete {
 		id := m.Existing.Original.(*godo.DomainRecord).ID
 		corr := &models.Correction{
-			Msg: fmt.Sprintf("%s, DO ID: %d", m.String(), id),
+			Msg:  fmt.Sprintf("%s, DO ID: %d", m.String(), id),
+			Kind: models.CorrectionDestroy,
 			F: func() error {
 			retry:
 				resp, err := api.client.Domains.DeleteRecord(ctx, dc.Name, id)
@@ -180,7 +183,8 @@ func (api *digitaloceanProvider) GetDomainCorrections(dc *models.DomainConfig) (
 	for _, m := range create {
 		req := toReq(dc, m.Desired)
 		corr := &models.Correction{
-			Msg: m.String(),
+			Msg:  m.String(),
+			Kind: models.CorrectionCreate,
 			F: func() error {
 			retry:
 				_, resp, err := api.client.Domains.CreateRecord(ctx, dc.Name, req)
@@ -198,7 +202,8 @@ func (api *digitaloceanProvider) GetDomainCorrections(dc *models.DomainConfig) (
 		id := m.Existing.Original.(*godo.DomainRecord).ID
 		req := toReq(dc, m.Desired)
 		corr := &models.Correction{
-			Msg: fmt.Sprintf("%s, DO ID: %d", m.String(), id),
+			Msg:  fmt.Sprintf("%s, DO ID: %d", m.String(), id),
+			Kind: models.CorrectionModify,
 			F: func() error {
 			retry:
 				_, resp, err := api.client.Domains.EditRecord(ctx, dc.Name, id, req)
@@ -337,7 +342,7 @@ func pauseAndRetry(resp *godo.Response) bool {
 	}
 
 	// a simple exponential back-off with a 3-minute max.
-	log.Printf("Delaying %v due to ratelimit\n", backoff)
+	logger.Printf("Delaying %v due to ratelimit\n", backoff)
 	time.Sleep(backoff)
 	backoff = backoff + (backoff / 2)
 	if backoff > maxBackoff {
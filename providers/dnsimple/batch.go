@@ -0,0 +1,92 @@
+package dnsimple
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	dnsimpleapi "github.com/dnsimple/dnsimple-go/dnsimple"
+	"golang.org/x/oauth2"
+)
+
+// zoneBatch is a set of record changes, as the zone batch endpoint of
+// the API takes them. DNSimple makes all of them, or none.
+type zoneBatch struct {
+	Creates []dnsimpleapi.ZoneRecordAttributes `json:"creates,omitempty"`
+	Updates []batchUpdate                      `json:"updates,omitempty"`
+	Deletes []batchDelete                      `json:"deletes,omitempty"`
+}
+
+type batchUpdate struct {
+	ID int64 `json:"id"`
+	dnsimpleapi.ZoneRecordAttributes
+}
+
+type batchDelete struct {
+	ID int64 `json:"id"`
+}
+
+// BeginZoneUpdate starts queuing the record changes of the zone.
+func (c *dnsimpleProvider) BeginZoneUpdate(domain string) error {
+	return c.batch.Begin(domain)
+}
+
+// CommitZoneUpdate sends the queued record changes of the zone in a
+// single zone batch request.
+func (c *dnsimpleProvider) CommitZoneUpdate(domain string) error {
+	var batch zoneBatch
+	for _, b := range c.batch.End(domain) {
+		batch.Creates = append(batch.Creates, b.Creates...)
+		batch.Updates = append(batch.Updates, b.Updates...)
+		batch.Deletes = append(batch.Deletes, b.Deletes...)
+	}
+	if len(batch.Creates) == 0 && len(batch.Updates) == 0 && len(batch.Deletes) == 0 {
+		return nil
+	}
+
+	accountID, err := c.getAccountID()
+	if err != nil {
+		var errorResponse *dnsimpleapi.ErrorResponse
+		if errors.As(err, &errorResponse) {
+			return compileAttributeErrors(errorResponse)
+		}
+		return err
+	}
+	// The client library doesn't cover the batch endpoint.
+	u := fmt.Sprintf("%s/v2/%s/zones/%s/batch", c.getClient().BaseURL, url.PathEscape(accountID), url.PathEscape(domain))
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "DNSControl")
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: c.AccountToken})
+	resp, err := oauth2.NewClient(context.Background(), ts).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		errorResponse := &dnsimpleapi.ErrorResponse{}
+		errorResponse.HTTPResponse = resp
+		json.NewDecoder(resp.Body).Decode(errorResponse)
+		return compileAttributeErrors(errorResponse)
+	}
+	return nil
+}
+
+// RollbackZoneUpdate discards the queued record changes of the zone.
+func (c *dnsimpleProvider) RollbackZoneUpdate(domain string) error {
+	c.batch.End(domain)
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/aliastypes"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
@@ -41,7 +42,7 @@ func init() {
 		Initializer:   newDsp,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("DNSIMPLE", fns, features)
+	providers.RegisterDomainServiceProviderType("DNSIMPLE", fns, features, aliastypes.Native)
 }
 
 const stateRegistered = "registered"
@@ -66,6 +67,8 @@ type dnsimpleProvider struct {
 	AccountToken string // The account access token
 	BaseURL      string // An alternate base URI
 	accountID    string // Account id cache
+
+	batch providers.ZoneBatch[zoneBatch] // The changes of the zone updates in progress
 }
 
 // GetNameservers returns the name servers for a domain.
@@ -184,16 +187,18 @@ func (c *dnsimpleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 	for _, del := range del {
 		rec := del.Existing.Original.(dnsimpleapi.ZoneRecord)
 		corrections = append(corrections, &models.Correction{
-			Msg: del.String(),
-			F:   c.deleteRecordFunc(rec.ID, dc.Name),
+			Msg:  del.String(),
+			Kind: models.CorrectionDestroy,
+			F:    c.deleteRecordFunc(rec.ID, dc.Name),
 		})
 	}
 
 	for _, cre := range create {
 		rec := cre.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: cre.String(),
-			F:   c.createRecordFunc(rec, dc.Name),
+			Msg:  cre.String(),
+			Kind: models.CorrectionCreate,
+			F:    c.createRecordFunc(rec, dc.Name),
 		})
 	}
 
@@ -201,8 +206,9 @@ func (c *dnsimpleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 		old := mod.Existing.Original.(dnsimpleapi.ZoneRecord)
 		rec := mod.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: mod.String(),
-			F:   c.updateRecordFunc(&old, rec, dc.Name),
+			Msg:  mod.String(),
+			Kind: models.CorrectionModify,
+			F:    c.updateRecordFunc(&old, rec, dc.Name),
 		})
 	}
 
@@ -242,8 +248,9 @@ func (c *dnsimpleProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*
 	if actual != expected {
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Update nameservers %s -> %s", actual, expected),
-				F:   c.updateNameserversFunc(expectedSet, dc.Name),
+				Msg:  fmt.Sprintf("Update nameservers %s -> %s", actual, expected),
+				Kind: models.CorrectionModify,
+				F:    c.updateNameserversFunc(expectedSet, dc.Name),
 			},
 		}, nil
 	}
@@ -261,8 +268,9 @@ func (c *dnsimpleProvider) getDNSSECCorrections(dc *models.DomainConfig) ([]*mod
 	if enabled && dc.AutoDNSSEC == "off" {
 		return []*models.Correction{
 			{
-				Msg: "Disable DNSSEC",
-				F:   func() error { _, err := c.disableDnssec(dc.Name); return err },
+				Msg:  "Disable DNSSEC",
+				Kind: models.CorrectionDestroy,
+				F:    func() error { _, err := c.disableDnssec(dc.Name); return err },
 			},
 		}, nil
 	}
@@ -270,8 +278,9 @@ func (c *dnsimpleProvider) getDNSSECCorrections(dc *models.DomainConfig) ([]*mod
 	if !enabled && dc.AutoDNSSEC == "on" {
 		return []*models.Correction{
 			{
-				Msg: "Enable DNSSEC",
-				F:   func() error { _, err := c.enableDnssec(dc.Name); return err },
+				Msg:  "Enable DNSSEC",
+				Kind: models.CorrectionCreate,
+				F:    func() error { _, err := c.enableDnssec(dc.Name); return err },
 			},
 		}, nil
 	}
@@ -505,6 +514,17 @@ func (c *dnsimpleProvider) updateNameserversFunc(nameServerNames []string, domai
 // Returns a function that can be invoked to create a record in a zone.
 func (c *dnsimpleProvider) createRecordFunc(rc *models.RecordConfig, domainName string) func() error {
 	return func() error {
+		record := dnsimpleapi.ZoneRecordAttributes{
+			Name:     dnsimpleapi.String(rc.GetLabel()),
+			Type:     rc.Type,
+			Content:  getTargetRecordContent(rc),
+			TTL:      int(rc.TTL),
+			Priority: getTargetRecordPriority(rc),
+		}
+		if c.batch.Add(domainName, zoneBatch{Creates: []dnsimpleapi.ZoneRecordAttributes{record}}) {
+			return providers.ErrQueued
+		}
+
 		client := c.getClient()
 
 		accountID, err := c.getAccountID()
@@ -515,13 +535,6 @@ func (c *dnsimpleProvider) createRecordFunc(rc *models.RecordConfig, domainName
 			}
 			return err
 		}
-		record := dnsimpleapi.ZoneRecordAttributes{
-			Name:     dnsimpleapi.String(rc.GetLabel()),
-			Type:     rc.Type,
-			Content:  getTargetRecordContent(rc),
-			TTL:      int(rc.TTL),
-			Priority: getTargetRecordPriority(rc),
-		}
 		_, err = client.Zones.CreateRecord(context.Background(), accountID, domainName, record)
 		if err != nil {
 			var errorResponse *dnsimpleapi.ErrorResponse
@@ -538,6 +551,10 @@ func (c *dnsimpleProvider) createRecordFunc(rc *models.RecordConfig, domainName
 // Returns a function that can be invoked to delete a record in a zone.
 func (c *dnsimpleProvider) deleteRecordFunc(recordID int64, domainName string) func() error {
 	return func() error {
+		if c.batch.Add(domainName, zoneBatch{Deletes: []batchDelete{{ID: recordID}}}) {
+			return providers.ErrQueued
+		}
+
 		client := c.getClient()
 
 		accountID, err := c.getAccountID()
@@ -566,6 +583,17 @@ func (c *dnsimpleProvider) deleteRecordFunc(recordID int64, domainName string) f
 // Returns a function that can be invoked to update a record in a zone.
 func (c *dnsimpleProvider) updateRecordFunc(old *dnsimpleapi.ZoneRecord, rc *models.RecordConfig, domainName string) func() error {
 	return func() error {
+		record := dnsimpleapi.ZoneRecordAttributes{
+			Name:     dnsimpleapi.String(rc.GetLabel()),
+			Type:     rc.Type,
+			Content:  getTargetRecordContent(rc),
+			TTL:      int(rc.TTL),
+			Priority: getTargetRecordPriority(rc),
+		}
+		if c.batch.Add(domainName, zoneBatch{Updates: []batchUpdate{{ID: old.ID, ZoneRecordAttributes: record}}}) {
+			return providers.ErrQueued
+		}
+
 		client := c.getClient()
 
 		accountID, err := c.getAccountID()
@@ -577,14 +605,6 @@ func (c *dnsimpleProvider) updateRecordFunc(old *dnsimpleapi.ZoneRecord, rc *mod
 			return err
 		}
 
-		record := dnsimpleapi.ZoneRecordAttributes{
-			Name:     dnsimpleapi.String(rc.GetLabel()),
-			Type:     rc.Type,
-			Content:  getTargetRecordContent(rc),
-			TTL:      int(rc.TTL),
-			Priority: getTargetRecordPriority(rc),
-		}
-
 		_, err = client.Zones.UpdateRecord(context.Background(), accountID, domainName, old.ID, record)
 		if err != nil {
 			var errorResponse *dnsimpleapi.ErrorResponse
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/aliastypes"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
 	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
@@ -34,7 +35,7 @@ func init() {
 		RecordAuditor: AuditRecords,
 	}
 
-	providers.RegisterDomainServiceProviderType("DNSMADEEASY", fns, features)
+	providers.RegisterDomainServiceProviderType("DNSMADEEASY", fns, features, txtutil.SplitLong, aliastypes.ANAME)
 }
 
 // New creates a new API handle.
@@ -74,11 +75,10 @@ func (api *dnsMadeEasyProvider) GetDomainCorrections(dc *models.DomainConfig) ([
 		return nil, err
 	}
 
+	// ALIAS is called ANAME on DNS Made Easy
+	aliastypes.ANAME.Apply(dc.Records)
 	for _, rec := range dc.Records {
-		if rec.Type == "ALIAS" {
-			// ALIAS is called ANAME on DNS Made Easy
-			rec.Type = "ANAME"
-		} else if rec.Type == "NS" {
+		if rec.Type == "NS" {
 			// NS records have fixed TTL on DNS Made Easy and it cannot be changed
 			rec.TTL = fixedNameServerRecordTTL
 		}
@@ -99,7 +99,7 @@ func (api *dnsMadeEasyProvider) GetDomainCorrections(dc *models.DomainConfig) ([
 
 	// Normalize
 	models.PostProcessRecords(existingRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 || true { // Remove "|| true" when diff2 version arrives
@@ -120,7 +120,8 @@ func (api *dnsMadeEasyProvider) GetDomainCorrections(dc *models.DomainConfig) ([
 
 		if len(deleteRecordIds) > 0 {
 			corr := &models.Correction{
-				Msg: strings.Join(deleteDescription, "\n\t"),
+				Msg:  strings.Join(deleteDescription, "\n\t"),
+				Kind: models.CorrectionDestroy,
 				F: func() error {
 					return api.deleteRecords(domain.ID, deleteRecordIds)
 				},
@@ -138,7 +139,8 @@ func (api *dnsMadeEasyProvider) GetDomainCorrections(dc *models.DomainConfig) ([
 
 		if len(createRecords) > 0 {
 			corr := &models.Correction{
-				Msg: strings.Join(createDescription, "\n\t"),
+				Msg:  strings.Join(createDescription, "\n\t"),
+				Kind: models.CorrectionCreate,
 				F: func() error {
 					return api.createRecords(domain.ID, createRecords)
 				},
@@ -161,7 +163,8 @@ func (api *dnsMadeEasyProvider) GetDomainCorrections(dc *models.DomainConfig) ([
 
 		if len(modifyRecords) > 0 {
 			corr := &models.Correction{
-				Msg: strings.Join(modifyDescription, "\n\t"),
+				Msg:  strings.Join(modifyDescription, "\n\t"),
+				Kind: models.CorrectionModify,
 				F: func() error {
 					return api.updateRecords(domain.ID, modifyRecords)
 				},
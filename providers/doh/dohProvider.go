@@ -52,7 +52,8 @@ func (c *dohProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*model
 
 	return []*models.Correction{
 		{
-			Msg: fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+			Msg:  fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+			Kind: models.CorrectionModify,
 			F: func() error {
 				return c.updateNameservers(expected, dc.Name)
 			},
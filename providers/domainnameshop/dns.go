@@ -8,6 +8,7 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ttlutil"
 )
 
 func (api *domainNameShopProvider) GetZoneRecords(domain string) (models.Records, error) {
@@ -66,8 +67,9 @@ func (api *domainNameShopProvider) GetDomainCorrections(dc *models.DomainConfig)
 		recordID := strconv.Itoa(r.Existing.Original.(*domainNameShopRecord).ID)
 
 		corr := &models.Correction{
-			Msg: fmt.Sprintf("%s, record id: %s", r.String(), recordID),
-			F:   func() error { return api.deleteRecord(domainID, recordID) },
+			Msg:  fmt.Sprintf("%s, record id: %s", r.String(), recordID),
+			Kind: models.CorrectionDestroy,
+			F:    func() error { return api.deleteRecord(domainID, recordID) },
 		}
 		corrections = append(corrections, corr)
 	}
@@ -83,8 +85,9 @@ func (api *domainNameShopProvider) GetDomainCorrections(dc *models.DomainConfig)
 		}
 
 		corr := &models.Correction{
-			Msg: r.String(),
-			F:   func() error { return api.CreateRecord(domainName, dnsR) },
+			Msg:  r.String(),
+			Kind: models.CorrectionCreate,
+			F:    func() error { return api.CreateRecord(domainName, dnsR) },
 		}
 
 		corrections = append(corrections, corr)
@@ -101,8 +104,9 @@ func (api *domainNameShopProvider) GetDomainCorrections(dc *models.DomainConfig)
 		dnsR.ID = r.Existing.Original.(*domainNameShopRecord).ID
 
 		corr := &models.Correction{
-			Msg: r.String(),
-			F:   func() error { return api.UpdateRecord(dnsR) },
+			Msg:  r.String(),
+			Kind: models.CorrectionModify,
+			F:    func() error { return api.UpdateRecord(dnsR) },
 		}
 
 		corrections = append(corrections, corr)
@@ -123,15 +127,9 @@ const minAllowedTTL = 60
 const maxAllowedTTL = 604800
 const multiplierTTL = 60
 
-func fixTTL(ttl uint32) uint32 {
-	// if the TTL is larger than the largest allowed value, return the largest allowed value
-	if ttl > maxAllowedTTL {
-		return maxAllowedTTL
-	} else if ttl < 60 {
-		return minAllowedTTL
-	}
+// The other TTLs are rounded down to a multiple of multiplierTTL.
+var ttlLimits = ttlutil.Limits{Min: minAllowedTTL, Max: maxAllowedTTL, Step: multiplierTTL}
 
-	// Return closest rounded down possible
-
-	return (ttl / multiplierTTL) * multiplierTTL
+func fixTTL(ttl uint32) uint32 {
+	return ttlLimits.Fix(ttl)
 }
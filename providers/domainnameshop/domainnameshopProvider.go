@@ -48,7 +48,7 @@ func init() {
 		RecordAuditor: AuditRecords,
 	}
 
-	providers.RegisterDomainServiceProviderType("DOMAINNAMESHOP", fns, features)
+	providers.RegisterDomainServiceProviderType("DOMAINNAMESHOP", fns, features, ttlLimits)
 }
 
 // newDomainNameShopProvider creates a Domainnameshop specific DNS provider.
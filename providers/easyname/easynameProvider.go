@@ -60,7 +60,8 @@ func (c *easynameProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*
 	if foundNameservers != expectedNameservers {
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+				Msg:  fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+				Kind: models.CorrectionModify,
 				F: func() error {
 					return c.updateNameservers(expected, domain.ID)
 				},
@@ -27,6 +27,7 @@ var ErrDomainNotFound = errors.New("domain not found")
 type exoscaleProvider struct {
 	client  *egoscale.Client
 	apiZone string
+	domains *providers.Memo[[]egoscale.DNSDomain] // All domains in the account.
 }
 
 // NewExoscale creates a new Exoscale DNS provider.
@@ -50,18 +51,21 @@ func NewExoscale(m map[string]string, metadata json.RawMessage) (providers.DNSSe
 	if z, ok := m["apizone"]; ok {
 		provider.apiZone = z
 	}
+	provider.domains = providers.NewMemo(func() ([]egoscale.DNSDomain, error) {
+		return provider.client.ListDNSDomains(context.Background(), provider.apiZone)
+	})
 
 	return &provider, nil
 }
 
 var features = providers.DocumentationNotes{
-	providers.CanGetZones:            providers.Unimplemented(),
+	providers.CanGetZones:            providers.Can(),
 	providers.CanUseAlias:            providers.Can(),
 	providers.CanUseCAA:              providers.Can(),
 	providers.CanUsePTR:              providers.Can(),
 	providers.CanUseSRV:              providers.Can("SRV records with empty targets are not supported"),
 	providers.CanUseTLSA:             providers.Cannot(),
-	providers.DocCreateDomains:       providers.Cannot(),
+	providers.DocCreateDomains:       providers.Can(),
 	providers.DocDualHost:            providers.Cannot("Exoscale does not allow sufficient control over the apex NS records"),
 	providers.DocOfficiallySupported: providers.Cannot(),
 }
@@ -74,56 +78,70 @@ func init() {
 	providers.RegisterDomainServiceProviderType("EXOSCALE", fns, features)
 }
 
-// EnsureDomainExists returns an error if domain doesn't exist.
+// EnsureDomainExists creates the domain if it doesn't exist.
 func (c *exoscaleProvider) EnsureDomainExists(domainName string) error {
 	_, err := c.findDomainByName(domainName)
+	if !errors.Is(err, ErrDomainNotFound) {
+		return err
+	}
+
+	_, err = c.client.CreateDNSDomain(
+		context.Background(),
+		c.apiZone,
+		&egoscale.DNSDomain{UnicodeName: &domainName},
+	)
+	c.domains.Invalidate()
 
 	return err
 }
 
+// ListZones returns the zones in the account.
+func (c *exoscaleProvider) ListZones() ([]string, error) {
+	domains, err := c.domains.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		if domain.UnicodeName != nil {
+			zones = append(zones, *domain.UnicodeName)
+		}
+	}
+	return zones, nil
+}
+
 // GetNameservers returns the nameservers for domain.
 func (c *exoscaleProvider) GetNameservers(domain string) ([]*models.Nameserver, error) {
 	return nil, nil
 }
 
 // GetZoneRecords gets the records of a zone and returns them in RecordConfig format.
-func (c *exoscaleProvider) GetZoneRecords(domain string) (models.Records, error) {
-	return nil, fmt.Errorf("not implemented")
-	// This enables the get-zones subcommand.
-	// Implement this by extracting the code from GetDomainCorrections into
-	// a single function.  For most providers this should be relatively easy.
-}
-
-// GetDomainCorrections returns a list of corretions for the  domain.
-func (c *exoscaleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
-	dc.Punycode()
-
-	domain, err := c.findDomainByName(dc.Name)
+func (c *exoscaleProvider) GetZoneRecords(domainName string) (models.Records, error) {
+	domain, err := c.findDomainByName(domainName)
 	if err != nil {
 		return nil, err
 	}
 
-	domainID := *domain.ID
+	return c.getZoneRecords(*domain.ID, domainName)
+}
 
-	ctx := context.Background()
-	records, err := c.client.ListDNSDomainRecords(ctx, c.apiZone, domainID)
+// getZoneRecords gets the records of the zone identified by domainID.
+func (c *exoscaleProvider) getZoneRecords(domainID, domainName string) (models.Records, error) {
+	records, err := c.client.ListDNSDomainRecords(context.Background(), c.apiZone, domainID)
 	if err != nil {
 		return nil, err
 	}
 
+	// The list endpoint returns every field we need, so there is no
+	// reason to call GetDNSDomainRecord for each record individually.
 	existingRecords := make([]*models.RecordConfig, 0, len(records))
-	for _, r := range records {
-		if r.ID == nil {
+	for i := range records {
+		record := &records[i]
+		if record.ID == nil {
 			continue
 		}
 
-		recordID := *r.ID
-
-		record, err := c.client.GetDNSDomainRecord(ctx, c.apiZone, domainID, recordID)
-		if err != nil {
-			return nil, err
-		}
-
 		// nil pointers are not expected, but just to be on the safe side...
 		var rtype, rcontent, rname string
 		if record.Type == nil {
@@ -164,7 +182,7 @@ func (c *exoscaleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 		if record.TTL != nil {
 			rc.TTL = uint32(*record.TTL)
 		}
-		rc.SetLabel(rname, dc.Name)
+		rc.SetLabel(rname, domainName)
 
 		switch rtype {
 		case "ALIAS", "URL":
@@ -177,7 +195,7 @@ func (c *exoscaleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 			}
 			err = rc.SetTargetMX(prio, rcontent)
 		default:
-			err = rc.PopulateFromString(rtype, rcontent, dc.Name)
+			err = rc.PopulateFromString(rtype, rcontent, domainName)
 		}
 		if err != nil {
 			return nil, fmt.Errorf("unparsable record received from exoscale: %w", err)
@@ -185,48 +203,103 @@ func (c *exoscaleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 
 		existingRecords = append(existingRecords, rc)
 	}
+
+	return existingRecords, nil
+}
+
+// GetDomainCorrections returns a list of corretions for the  domain.
+func (c *exoscaleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	dc.Punycode()
+
+	domain, err := c.findDomainByName(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	domainID := *domain.ID
+
+	existingRecords, err := c.getZoneRecords(domainID, dc.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	removeOtherNS(dc)
 
 	// Normalize
 	models.PostProcessRecords(existingRecords)
 
 	var corrections []*models.Correction
-	var create, delete, modify diff.Changeset
 	if !diff2.EnableDiff2 {
 		differ := diff.New(dc)
-		_, create, delete, modify, err = differ.IncrementalDiff(existingRecords)
-	} else {
-		differ := diff.NewCompat(dc)
-		_, create, delete, modify, err = differ.IncrementalDiff(existingRecords)
-	}
-	if err != nil {
-		return nil, err
-	}
+		_, create, delete, modify, err := differ.IncrementalDiff(existingRecords)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, del := range delete {
+			record := del.Existing.Original.(*egoscale.DNSDomainRecord)
+			corrections = append(corrections, &models.Correction{
+				Msg:  del.String(),
+				Kind: del.Kind(),
+				F:    c.deleteRecordFunc(*record.ID, domainID),
+			})
+		}
 
-	for _, del := range delete {
-		record := del.Existing.Original.(*egoscale.DNSDomainRecord)
-		corrections = append(corrections, &models.Correction{
-			Msg: del.String(),
-			F:   c.deleteRecordFunc(*record.ID, domainID),
-		})
+		for _, cre := range create {
+			rc := cre.Desired
+			corrections = append(corrections, &models.Correction{
+				Msg:  cre.String(),
+				Kind: cre.Kind(),
+				F:    c.createRecordFunc(rc, domainID),
+			})
+		}
+
+		for _, mod := range modify {
+			old := mod.Existing.Original.(*egoscale.DNSDomainRecord)
+			new := mod.Desired
+			corrections = append(corrections, &models.Correction{
+				Msg:  mod.String(),
+				Kind: mod.Kind(),
+				F:    c.updateRecordFunc(old, new, domainID),
+			})
+		}
+
+		return corrections, nil
 	}
 
-	for _, cre := range create {
-		rc := cre.Desired
-		corrections = append(corrections, &models.Correction{
-			Msg: cre.String(),
-			F:   c.createRecordFunc(rc, domainID),
-		})
+	changes, err := diff2.ByRecord(existingRecords, dc, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, mod := range modify {
-		old := mod.Existing.Original.(*egoscale.DNSDomainRecord)
-		new := mod.Desired
-		corrections = append(corrections, &models.Correction{
-			Msg: mod.String(),
-			F:   c.updateRecordFunc(old, new, domainID),
-		})
+	// Deletes first so changing type works etc.
+	var creates, updates []*models.Correction
+	for _, change := range changes {
+		switch change.Type {
+		case diff2.CREATE:
+			creates = append(creates, &models.Correction{
+				Msg:  change.Msgs[0],
+				Kind: change.Kind(),
+				F:    c.createRecordFunc(change.New[0], domainID),
+			})
+		case diff2.CHANGE:
+			old := change.Old[0].Original.(*egoscale.DNSDomainRecord)
+			updates = append(updates, &models.Correction{
+				Msg:  change.Msgs[0],
+				Kind: change.Kind(),
+				F:    c.updateRecordFunc(old, change.New[0], domainID),
+			})
+		case diff2.DELETE:
+			record := change.Old[0].Original.(*egoscale.DNSDomainRecord)
+			corrections = append(corrections, &models.Correction{
+				Msg:  change.Msgs[0],
+				Kind: change.Kind(),
+				F:    c.deleteRecordFunc(*record.ID, domainID),
+			})
+		}
 	}
+	corrections = append(corrections, creates...)
+	corrections = append(corrections, updates...)
 
 	return corrections, nil
 }
@@ -340,7 +413,7 @@ func (c *exoscaleProvider) updateRecordFunc(record *egoscale.DNSDomainRecord, rc
 }
 
 func (c *exoscaleProvider) findDomainByName(name string) (*egoscale.DNSDomain, error) {
-	domains, err := c.client.ListDNSDomains(context.Background(), c.apiZone)
+	domains, err := c.domains.Get()
 	if err != nil {
 		return nil, err
 	}
@@ -22,9 +22,11 @@ import (
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/aliastypes"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ttlutil"
 	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	"github.com/go-gandi/go-gandi"
@@ -40,10 +42,17 @@ func init() {
 		Initializer:   newDsp,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("GANDI_V5", fns, features)
+	providers.RegisterDomainServiceProviderType("GANDI_V5", fns, features, txtutil.SplitLong, ttlLimits, aliasPolicy)
 	providers.RegisterRegistrarType("GANDI_V5", newReg)
 }
 
+// Gandi supports TTLs from 5 minutes to 30 days.
+var ttlLimits = ttlutil.Limits{Min: 300, Max: 2592000}
+
+// aliasPolicy: Gandi only permits ALIAS records at the apex; they are
+// changed to CNAME records elsewhere.
+var aliasPolicy = aliastypes.Policy{Type: "ALIAS", ApexOnly: true}
+
 // features declares which features and options are available.
 var features = providers.DocumentationNotes{
 	providers.CanGetZones:            providers.Can(),
@@ -55,6 +64,7 @@ var features = providers.DocumentationNotes{
 	providers.CanUseSRV:              providers.Can(),
 	providers.CanUseSSHFP:            providers.Can(),
 	providers.CanUseTLSA:             providers.Can(),
+	providers.CanUseURI:              providers.Can(),
 	providers.CantUseNOPURGE:         providers.Cannot(),
 	providers.DocCreateDomains:       providers.Cannot("Can only manage domains registered through their service"),
 	providers.DocOfficiallySupported: providers.Cannot(),
@@ -189,20 +199,9 @@ func PrepDesiredRecords(dc *models.DomainConfig) {
 	dc.Punycode()
 
 	recordsToKeep := make([]*models.RecordConfig, 0, len(dc.Records))
+	aliasPolicy.Apply(dc.Records)
 	for _, rec := range dc.Records {
-		if rec.Type == "ALIAS" && rec.Name != "@" {
-			// GANDI only permits aliases on a naked domain.
-			// Therefore, we change this to a CNAME.
-			rec.Type = "CNAME"
-		}
-		if rec.TTL < 300 {
-			printer.Warnf("Gandi does not support ttls < 300. Setting %s from %d to 300\n", rec.GetLabelFQDN(), rec.TTL)
-			rec.TTL = 300
-		}
-		if rec.TTL > 2592000 {
-			printer.Warnf("Gandi does not support ttls > 30 days. Setting %s from %d to 2592000\n", rec.GetLabelFQDN(), rec.TTL)
-			rec.TTL = 2592000
-		}
+		rec.TTL = ttlLimits.Fix(rec.TTL)
 		if rec.Type == "TXT" {
 			rec.SetTarget("\"" + rec.GetTargetField() + "\"") // FIXME(tlim): Should do proper quoting.
 		}
@@ -227,7 +226,7 @@ func (client *gandiv5Provider) GenerateDomainCorrections(dc *models.DomainConfig
 		debugRecords("GenDC input", existing)
 	}
 
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 {
@@ -248,6 +247,7 @@ func (client *gandiv5Provider) GenerateDomainCorrections(dc *models.DomainConfig
 		// Regroup data by FQDN.  ChangedGroups returns data grouped by label:RType tuples.
 		affectedLabels, msgsForLabel := gatherAffectedLabels(keysToUpdate)
 		_, desiredRecords := dc.Records.GroupedByFQDN()
+		_, existingRecords := existing.GroupedByFQDN()
 		doesLabelExist := existing.FQDNMap()
 
 		g := gandi.NewLiveDNSClient(config.Config{
@@ -267,7 +267,8 @@ func (client *gandiv5Provider) GenerateDomainCorrections(dc *models.DomainConfig
 				shortname := dnsutil.TrimDomainName(label, dc.Name)
 				corrections = append(corrections,
 					&models.Correction{
-						Msg: msgs,
+						Msg:  msgs,
+						Kind: models.CorrectionDestroy,
 						F: func() error {
 							err := g.DeleteDomainRecordsByName(domain, shortname)
 							if err != nil {
@@ -291,7 +292,8 @@ func (client *gandiv5Provider) GenerateDomainCorrections(dc *models.DomainConfig
 					shortname := dnsutil.TrimDomainName(label, dc.Name)
 					corrections = append(corrections,
 						&models.Correction{
-							Msg: msg,
+							Msg:  msg,
+							Kind: diff.ReplaceKind(existingRecords[label], desiredRecords[label]),
 							F: func() error {
 								res, err := g.UpdateDomainRecordsByName(domain, shortname, ns)
 								if err != nil {
@@ -315,7 +317,8 @@ func (client *gandiv5Provider) GenerateDomainCorrections(dc *models.DomainConfig
 						values := n.RrsetValues
 						corrections = append(corrections,
 							&models.Correction{
-								Msg: msg,
+								Msg:  msg,
+								Kind: models.CorrectionCreate,
 								F: func() error {
 									res, err := g.CreateDomainRecord(domain, shortname, rtype, ttl, values)
 									if err != nil {
@@ -369,7 +372,8 @@ func (client *gandiv5Provider) GenerateDomainCorrections(dc *models.DomainConfig
 				msg := strings.Join(inst.MsgsByKey[key], "\n")
 				corrections = append(corrections,
 					&models.Correction{
-						Msg: msg,
+						Msg:  msg,
+						Kind: inst.Kind(),
 						F: func() error {
 							res, err := g.CreateDomainRecord(domain, shortname, rtype, ttl, values)
 							if err != nil {
@@ -388,7 +392,8 @@ func (client *gandiv5Provider) GenerateDomainCorrections(dc *models.DomainConfig
 			ns := recordsToNative(inst.New, dc.Name)
 			corrections = append(corrections,
 				&models.Correction{
-					Msg: msgs,
+					Msg:  msgs,
+					Kind: inst.Kind(),
 					F: func() error {
 						res, err := g.UpdateDomainRecordsByName(domain, shortname, ns)
 						if err != nil {
@@ -405,7 +410,8 @@ func (client *gandiv5Provider) GenerateDomainCorrections(dc *models.DomainConfig
 			shortname := dnsutil.TrimDomainName(label, dc.Name)
 			corrections = append(corrections,
 				&models.Correction{
-					Msg: msgs,
+					Msg:  msgs,
+					Kind: inst.Kind(),
 					F: func() error {
 						err := g.DeleteDomainRecordsByName(domain, shortname)
 						if err != nil {
@@ -480,7 +486,8 @@ func (client *gandiv5Provider) GetRegistrarCorrections(dc *models.DomainConfig)
 	if existing != desired {
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Change Nameservers from '%s' to '%s'", existing, desired),
+				Msg:  fmt.Sprintf("Change Nameservers from '%s' to '%s'", existing, desired),
+				Kind: models.CorrectionModify,
 				F: func() (err error) {
 					err = gd.UpdateNameServers(dc.Name, desiredNs)
 					return
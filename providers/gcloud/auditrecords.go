@@ -6,5 +6,11 @@ import "github.com/StackExchange/dnscontrol/v3/models"
 // that aren't supported by this provider.  If all records are
 // supported, an empty list is returned.
 func AuditRecords(records []*models.RecordConfig) []error {
-	return nil
+	var errs []error
+	for _, rc := range records {
+		if err := checkRoutingPolicy(rc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return append(errs, checkRoutingGroups(records)...)
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -21,6 +20,8 @@ import (
 	"google.golang.org/api/option"
 )
 
+var logger = printer.Module("providers.gcloud")
+
 var features = providers.DocumentationNotes{
 	providers.CanGetZones:            providers.Can(),
 	providers.CanUseCAA:              providers.Can(),
@@ -43,7 +44,7 @@ func init() {
 		Initializer:   New,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("GCLOUD", fns, features)
+	providers.RegisterDomainServiceProviderType("GCLOUD", fns, features, txtutil.SplitLong)
 }
 
 type gcloudProvider struct {
@@ -180,14 +181,11 @@ func (g *gcloudProvider) getZoneSets(domain string) (models.Records, map[key]*gd
 	oldRRs := map[key]*gdns.ResourceRecordSet{}
 	for _, set := range rrs {
 		oldRRs[keyFor(set)] = set
-		for _, rec := range set.Rrdatas {
-			rt, err := nativeToRecord(set, rec, domain)
-			if err != nil {
-				return nil, nil, "", err
-			}
-
-			existingRecords = append(existingRecords, rt)
+		recs, err := nativeToRecords(set, domain)
+		if err != nil {
+			return nil, nil, "", err
 		}
+		existingRecords = append(existingRecords, recs...)
 	}
 	return existingRecords, oldRRs, zoneName, err
 }
@@ -203,13 +201,13 @@ func (g *gcloudProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*model
 
 	// Normalize
 	models.PostProcessRecords(existingRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 || true { // Remove "|| true" when diff2 version arrives
 
 		// first collect keys that have changed
-		differ := diff.New(dc)
+		differ := diff.New(dc, getRoutingMap)
 		_, create, delete, modify, err := differ.IncrementalDiff(existingRecords)
 		if err != nil {
 			return nil, fmt.Errorf("incdiff error: %w", err)
@@ -239,19 +237,14 @@ func (g *gcloudProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*model
 				chg.Deletions = append(chg.Deletions, old)
 			}
 			// collect records to replace with
-			newRRs := &gdns.ResourceRecordSet{
-				Name: ck.Name,
-				Type: ck.Type,
-				Kind: "dns#resourceRecordSet",
-			}
+			var recs []*models.RecordConfig
 			for _, r := range dc.Records {
 				if keyForRec(r) == ck {
-					newRRs.Rrdatas = append(newRRs.Rrdatas, r.GetTargetCombined())
-					newRRs.Ttl = int64(r.TTL)
+					recs = append(recs, r)
 				}
 			}
-			if len(newRRs.Rrdatas) > 0 {
-				chg.Additions = append(chg.Additions, newRRs)
+			if len(recs) > 0 {
+				chg.Additions = append(chg.Additions, recordsToRRSet(ck, recs))
 			}
 		}
 
@@ -276,8 +269,9 @@ func (g *gcloudProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*model
 		}
 
 		return []*models.Correction{{
-			Msg: desc,
-			F:   runChange,
+			Msg:  desc,
+			Kind: diff.Kind(create, delete, modify),
+			F:    runChange,
 		}}, nil
 	}
 
@@ -387,7 +381,7 @@ func retryNeeded(resp *gdns.Change, err error) bool {
 			backoff404 = false
 			return false // Give up. We've done this already.
 		}
-		log.Printf("Special 404 pause-and-retry for GCLOUD: Pausing %s\n", backoff)
+		logger.Printf("Special 404 pause-and-retry for GCLOUD: Pausing %s\n", backoff)
 		time.Sleep(backoff)
 		backoff404 = true
 		return true // Request a retry.
@@ -404,13 +398,13 @@ func retryNeeded(resp *gdns.Change, err error) bool {
 	// file a bug with the contents!
 
 	if resp != nil {
-		log.Printf("NOTE: If you see this message, please file a bug with the output below:\n")
-		log.Printf("RUNCHANGE CODE = %+v\n", resp.HTTPStatusCode)
-		log.Printf("RUNCHANGE HEAD = %+v\n", resp.Header)
+		logger.Warnf("If you see this message, please file a bug with the output below:\n")
+		logger.Warnf("RUNCHANGE CODE = %+v\n", resp.HTTPStatusCode)
+		logger.Warnf("RUNCHANGE HEAD = %+v\n", resp.Header)
 	}
 
 	// a simple exponential back-off
-	log.Printf("Pausing due to ratelimit: %v seconds\n", backoff)
+	logger.Printf("Pausing due to ratelimit: %v seconds\n", backoff)
 	time.Sleep(backoff)
 	backoff = backoff + (backoff / 2)
 	if backoff > maxBackoff {
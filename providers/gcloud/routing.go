@@ -0,0 +1,217 @@
+package gcloud
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	gdns "google.golang.org/api/dns/v1"
+)
+
+// Cloud DNS routing policies (geolocation and weighted round robin) are
+// set with record metadata, usually with the GCLOUD_GEO() and
+// GCLOUD_WRR() record modifiers. A record set with a routing policy has
+// no rrdatas of its own: the records with the same location (or weight)
+// are one item of the policy.
+const (
+	metaGeo = "gcloud_geo"
+	metaWRR = "gcloud_wrr"
+)
+
+// getRoutingMap returns the normalized routing policy of a record, so
+// that the differ notices when it changes.
+func getRoutingMap(rc *models.RecordConfig) map[string]string {
+	m := map[string]string{}
+	if v, ok := rc.Metadata[metaGeo]; ok {
+		m[metaGeo] = strings.ToLower(strings.TrimSpace(v))
+	}
+	if v, ok := rc.Metadata[metaWRR]; ok {
+		m[metaWRR] = normalizeWeight(v)
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func normalizeWeight(v string) string {
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return v
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// routingPolicyKind returns the kind of routing policy of a record, or
+// "" if it has none.
+func routingPolicyKind(rc *models.RecordConfig) string {
+	_, geo := rc.Metadata[metaGeo]
+	_, wrr := rc.Metadata[metaWRR]
+	switch {
+	case geo && wrr:
+		return "mixed"
+	case geo:
+		return "geo"
+	case wrr:
+		return "wrr"
+	}
+	return ""
+}
+
+// checkRoutingPolicy returns an error if the routing policy metadata of
+// a record is invalid.
+func checkRoutingPolicy(rc *models.RecordConfig) error {
+	geo, isGeo := rc.Metadata[metaGeo]
+	wrr, isWRR := rc.Metadata[metaWRR]
+	if isGeo && isWRR {
+		return fmt.Errorf("%s %s: only one routing policy is allowed, found GCLOUD_GEO and GCLOUD_WRR", rc.Type, rc.GetLabelFQDN())
+	}
+	if isGeo && strings.TrimSpace(geo) == "" {
+		return fmt.Errorf("%s %s: GCLOUD_GEO requires a location", rc.Type, rc.GetLabelFQDN())
+	}
+	if isWRR {
+		f, err := strconv.ParseFloat(strings.TrimSpace(wrr), 64)
+		if err != nil || f < 0 || math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("%s %s: the GCLOUD_WRR weight must be a number of 0 or more, not %q", rc.Type, rc.GetLabelFQDN(), wrr)
+		}
+	}
+	return nil
+}
+
+// checkRoutingGroups returns an error for each record set whose records
+// don't agree on the kind of routing policy. Cloud DNS has one policy
+// per record set.
+func checkRoutingGroups(records []*models.RecordConfig) []error {
+	var errs []error
+	kinds := map[key]string{}
+	reported := map[key]bool{}
+	for _, rc := range records {
+		k := keyForRec(rc)
+		kind := routingPolicyKind(rc)
+		prev, ok := kinds[k]
+		if !ok {
+			kinds[k] = kind
+			continue
+		}
+		if prev != kind && !reported[k] {
+			errs = append(errs, fmt.Errorf("%s %s: the records of a record set must all use GCLOUD_GEO, all use GCLOUD_WRR, or neither", rc.Type, rc.GetLabelFQDN()))
+			reported[k] = true
+		}
+	}
+	return errs
+}
+
+// recordsToRRSet returns the record set for records with the same name
+// and type.
+func recordsToRRSet(k key, recs []*models.RecordConfig) *gdns.ResourceRecordSet {
+	rrset := &gdns.ResourceRecordSet{
+		Name: k.Name,
+		Type: k.Type,
+		Kind: "dns#resourceRecordSet",
+	}
+	for _, r := range recs {
+		rrset.Ttl = int64(r.TTL)
+	}
+
+	switch routingPolicyKind(recs[0]) {
+	case "geo":
+		geo := &gdns.RRSetRoutingPolicyGeoPolicy{}
+		items := map[string]*gdns.RRSetRoutingPolicyGeoPolicyGeoPolicyItem{}
+		for _, r := range recs {
+			location := getRoutingMap(r)[metaGeo]
+			item, ok := items[location]
+			if !ok {
+				item = &gdns.RRSetRoutingPolicyGeoPolicyGeoPolicyItem{Location: location}
+				items[location] = item
+				geo.Items = append(geo.Items, item)
+			}
+			item.Rrdatas = append(item.Rrdatas, r.GetTargetCombined())
+		}
+		sort.SliceStable(geo.Items, func(i, j int) bool {
+			return geo.Items[i].Location < geo.Items[j].Location
+		})
+		rrset.RoutingPolicy = &gdns.RRSetRoutingPolicy{Geo: geo}
+	case "wrr":
+		wrr := &gdns.RRSetRoutingPolicyWrrPolicy{}
+		items := map[string]*gdns.RRSetRoutingPolicyWrrPolicyWrrPolicyItem{}
+		for _, r := range recs {
+			weight := getRoutingMap(r)[metaWRR]
+			item, ok := items[weight]
+			if !ok {
+				f, _ := strconv.ParseFloat(weight, 64)
+				item = &gdns.RRSetRoutingPolicyWrrPolicyWrrPolicyItem{Weight: f}
+				items[weight] = item
+				wrr.Items = append(wrr.Items, item)
+			}
+			item.Rrdatas = append(item.Rrdatas, r.GetTargetCombined())
+		}
+		sort.SliceStable(wrr.Items, func(i, j int) bool {
+			return wrr.Items[i].Weight < wrr.Items[j].Weight
+		})
+		rrset.RoutingPolicy = &gdns.RRSetRoutingPolicy{Wrr: wrr}
+	default:
+		for _, r := range recs {
+			rrset.Rrdatas = append(rrset.Rrdatas, r.GetTargetCombined())
+		}
+	}
+	return rrset
+}
+
+// nativeToRecords returns the records of a record set. The records of
+// a set with a routing policy get the metadata of their item.
+func nativeToRecords(set *gdns.ResourceRecordSet, origin string) ([]*models.RecordConfig, error) {
+	var results []*models.RecordConfig
+	add := func(rrdatas []string, metaKey, metaValue string) error {
+		for _, rec := range rrdatas {
+			rc, err := nativeToRecord(set, rec, origin)
+			if err != nil {
+				return err
+			}
+			if metaKey != "" {
+				rc.Metadata = map[string]string{metaKey: metaValue}
+			}
+			results = append(results, rc)
+		}
+		return nil
+	}
+
+	p := set.RoutingPolicy
+	switch {
+	case p == nil:
+		if err := add(set.Rrdatas, "", ""); err != nil {
+			return nil, err
+		}
+	case p.Geo != nil:
+		for _, item := range p.Geo.Items {
+			if len(item.Rrdatas) == 0 {
+				return nil, errUnsupportedPolicy(set)
+			}
+			if err := add(item.Rrdatas, metaGeo, item.Location); err != nil {
+				return nil, err
+			}
+		}
+	case p.Wrr != nil:
+		for _, item := range p.Wrr.Items {
+			if len(item.Rrdatas) == 0 {
+				return nil, errUnsupportedPolicy(set)
+			}
+			weight := strconv.FormatFloat(item.Weight, 'g', -1, 64)
+			if err := add(item.Rrdatas, metaWRR, weight); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, errUnsupportedPolicy(set)
+	}
+	return results, nil
+}
+
+// errUnsupportedPolicy is the error for a record set whose routing
+// policy can't be expressed with records, such as a failover policy or
+// one with health-checked targets.
+func errUnsupportedPolicy(set *gdns.ResourceRecordSet) error {
+	return fmt.Errorf("GCLOUD: %s %s has a routing policy that dnscontrol does not support (only geolocation and weighted round robin policies with rrdatas are supported)", set.Type, set.Name)
+}
@@ -0,0 +1,140 @@
+package gcloud
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	gdns "google.golang.org/api/dns/v1"
+)
+
+func Test_checkRoutingPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    map[string]string
+		wantErr bool
+	}{
+		{"simple", nil, false},
+		{"geo", map[string]string{metaGeo: "us-east1"}, false},
+		{"wrr", map[string]string{metaWRR: "0.5"}, false},
+		{"wrr zero", map[string]string{metaWRR: "0"}, false},
+		{"both", map[string]string{metaGeo: "us-east1", metaWRR: "1"}, true},
+		{"empty location", map[string]string{metaGeo: " "}, true},
+		{"bad weight", map[string]string{metaWRR: "heavy"}, true},
+		{"negative weight", map[string]string{metaWRR: "-1"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &models.RecordConfig{Type: "A", Metadata: tt.meta}
+			rc.SetLabel("www", "example.com")
+			if err := checkRoutingPolicy(rc); (err != nil) != tt.wantErr {
+				t.Errorf("checkRoutingPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func rec(name, target string, meta map[string]string) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: "A", TTL: 300, Metadata: meta}
+	rc.SetLabel(name, "example.com")
+	rc.SetTarget(target)
+	return rc
+}
+
+func Test_checkRoutingGroups(t *testing.T) {
+	geo := map[string]string{metaGeo: "us-east1"}
+	wrr := map[string]string{metaWRR: "1"}
+	errs := checkRoutingGroups([]*models.RecordConfig{
+		rec("a", "1.1.1.1", geo), rec("a", "2.2.2.2", geo),
+		rec("b", "1.1.1.1", geo), rec("b", "2.2.2.2", wrr), rec("b", "3.3.3.3", nil),
+		rec("c", "1.1.1.1", nil), rec("c", "2.2.2.2", nil),
+	})
+	if len(errs) != 1 {
+		t.Errorf("expected one error, got %v", errs)
+	}
+}
+
+func Test_routingRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		recs []*models.RecordConfig
+	}{
+		{"simple", []*models.RecordConfig{
+			rec("www", "1.1.1.1", nil),
+			rec("www", "2.2.2.2", nil),
+		}},
+		{"geo", []*models.RecordConfig{
+			rec("www", "1.1.1.1", map[string]string{metaGeo: "us-east1"}),
+			rec("www", "2.2.2.2", map[string]string{metaGeo: "europe-west1"}),
+			rec("www", "3.3.3.3", map[string]string{metaGeo: "us-east1"}),
+		}},
+		{"wrr", []*models.RecordConfig{
+			rec("www", "1.1.1.1", map[string]string{metaWRR: "0.8"}),
+			rec("www", "2.2.2.2", map[string]string{metaWRR: "0.2"}),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := keyForRec(tt.recs[0])
+			rrset := recordsToRRSet(k, tt.recs)
+			got, err := nativeToRecords(rrset, "example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.recs) {
+				t.Fatalf("got %d records, want %d", len(got), len(tt.recs))
+			}
+			want := map[string]map[string]string{}
+			for _, rc := range tt.recs {
+				want[rc.GetTargetCombined()] = getRoutingMap(rc)
+			}
+			for _, rc := range got {
+				if m := getRoutingMap(rc); !reflect.DeepEqual(m, want[rc.GetTargetCombined()]) {
+					t.Errorf("%s: got routing %v, want %v", rc.GetTargetCombined(), m, want[rc.GetTargetCombined()])
+				}
+				if rc.TTL != 300 || rc.GetLabelFQDN() != "www.example.com" {
+					t.Errorf("unexpected record %+v", rc)
+				}
+			}
+		})
+	}
+}
+
+func Test_recordsToRRSet(t *testing.T) {
+	recs := []*models.RecordConfig{
+		rec("www", "1.1.1.1", map[string]string{metaGeo: "us-east1"}),
+		rec("www", "2.2.2.2", map[string]string{metaGeo: "Europe-West1"}),
+		rec("www", "3.3.3.3", map[string]string{metaGeo: "us-east1"}),
+	}
+	rrset := recordsToRRSet(keyForRec(recs[0]), recs)
+	if len(rrset.Rrdatas) != 0 || rrset.RoutingPolicy == nil || rrset.RoutingPolicy.Geo == nil {
+		t.Fatalf("expected a geo policy, got %+v", rrset)
+	}
+	items := rrset.RoutingPolicy.Geo.Items
+	want := []*gdns.RRSetRoutingPolicyGeoPolicyGeoPolicyItem{
+		{Location: "europe-west1", Rrdatas: []string{"2.2.2.2"}},
+		{Location: "us-east1", Rrdatas: []string{"1.1.1.1", "3.3.3.3"}},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+}
+
+func Test_nativeToRecordsUnsupported(t *testing.T) {
+	set := &gdns.ResourceRecordSet{
+		Name: "www.example.com.",
+		Type: "A",
+		RoutingPolicy: &gdns.RRSetRoutingPolicy{
+			Geo: &gdns.RRSetRoutingPolicyGeoPolicy{
+				Items: []*gdns.RRSetRoutingPolicyGeoPolicyGeoPolicyItem{{Location: "us-east1"}},
+			},
+		},
+	}
+	if _, err := nativeToRecords(set, "example.com"); err == nil {
+		t.Errorf("expected an error for an item without rrdatas")
+	}
+	set.RoutingPolicy = &gdns.RRSetRoutingPolicy{}
+	if _, err := nativeToRecords(set, "example.com"); err == nil {
+		t.Errorf("expected an error for an unknown policy")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"path"
 	"strings"
@@ -47,7 +46,7 @@ func dnssdkDo(c *dnssdk.Client, apiKey string, ctx context.Context, method, uri
 	}
 
 	if c.Debug {
-		log.Printf("[DEBUG] dns api request: %s %s %s \n", method, uri, bs)
+		logger.Printf("[DEBUG] dns api request: %s %s %s \n", method, uri, bs)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), strings.NewReader(string(bs)))
@@ -9,6 +9,7 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 
 	dnssdk "github.com/G-Core/gcore-dns-sdk-go"
@@ -41,6 +42,8 @@ func NewGCore(m map[string]string, metadata json.RawMessage) (providers.DNSServi
 	return c, nil
 }
 
+var logger = printer.Module("providers.gcore")
+
 var features = providers.DocumentationNotes{
 	providers.CanAutoDNSSEC:          providers.Cannot(),
 	providers.CanGetZones:            providers.Can(),
@@ -183,7 +186,8 @@ func (c *gcoreProvider) GenerateDomainCorrections(dc *models.DomainConfig, exist
 				typ := label.Type
 				msg := generateChangeMsg(keysToUpdate[label])
 				deletions = append(deletions, &models.Correction{
-					Msg: msg,
+					Msg:  msg,
+					Kind: models.CorrectionDestroy,
 					F: func() error {
 						return c.provider.DeleteRRSet(c.ctx, zone, name, typ)
 					},
@@ -202,7 +206,8 @@ func (c *gcoreProvider) GenerateDomainCorrections(dc *models.DomainConfig, exist
 				typ := label.Type
 				msg := generateChangeMsg(keysToUpdate[label])
 				corrections = append(corrections, &models.Correction{
-					Msg: msg,
+					Msg:  msg,
+					Kind: models.CorrectionCreate,
 					F: func() error {
 						return c.provider.CreateRRSet(c.ctx, zone, name, typ, *record)
 					},
@@ -221,7 +226,8 @@ func (c *gcoreProvider) GenerateDomainCorrections(dc *models.DomainConfig, exist
 				typ := label.Type
 				msg := generateChangeMsg(keysToUpdate[label])
 				corrections = append(corrections, &models.Correction{
-					Msg: msg,
+					Msg:  msg,
+					Kind: diff.ReplaceKind(existingRecords[label], desiredRecords[label]),
 					F: func() error {
 						return c.provider.UpdateRRSet(c.ctx, zone, name, typ, *record)
 					},
@@ -248,21 +254,24 @@ func (c *gcoreProvider) GenerateDomainCorrections(dc *models.DomainConfig, exist
 			switch change.Type {
 			case diff2.CREATE:
 				corrections = append(corrections, &models.Correction{
-					Msg: msg,
+					Msg:  msg,
+					Kind: change.Kind(),
 					F: func() error {
 						return c.provider.CreateRRSet(c.ctx, zone, name, typ, *record)
 					},
 				})
 			case diff2.CHANGE:
 				corrections = append(corrections, &models.Correction{
-					Msg: msg,
+					Msg:  msg,
+					Kind: change.Kind(),
 					F: func() error {
 						return c.provider.UpdateRRSet(c.ctx, zone, name, typ, *record)
 					},
 				})
 			case diff2.DELETE:
 				deletions = append(deletions, &models.Correction{
-					Msg: msg,
+					Msg:  msg,
+					Kind: change.Kind(),
 					F: func() error {
 						return c.provider.DeleteRRSet(c.ctx, zone, name, typ)
 					},
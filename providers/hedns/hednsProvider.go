@@ -65,7 +65,7 @@ func init() {
 		Initializer:   newHEDNSProvider,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("HEDNS", fns, features)
+	providers.RegisterDomainServiceProviderType("HEDNS", fns, features, txtutil.SplitLong)
 }
 
 var defaultNameservers = []string{
@@ -203,7 +203,7 @@ func (c *hednsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models
 
 	// Normalize
 	models.PostProcessRecords(prunedRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	// Fallback to legacy mode if diff2 is not enabled, remove when diff1 is deprecated.
 	if !diff2.EnableDiff2 {
@@ -226,16 +226,18 @@ func (c *hednsProvider) getDiff1DomainCorrections(dc *models.DomainConfig, zoneI
 	for _, del := range toDelete {
 		recordID := del.Existing.Original.(Record).RecordID
 		corrections = append(corrections, &models.Correction{
-			Msg: del.String(),
-			F:   func() error { return c.deleteZoneRecord(zoneID, recordID) },
+			Msg:  del.String(),
+			Kind: del.Kind(),
+			F:    func() error { return c.deleteZoneRecord(zoneID, recordID) },
 		})
 	}
 
 	for _, cre := range toCreate {
 		record := cre.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: cre.String(),
-			F:   func() error { return c.createZoneRecord(zoneID, record) },
+			Msg:  cre.String(),
+			Kind: cre.Kind(),
+			F:    func() error { return c.createZoneRecord(zoneID, record) },
 		})
 	}
 
@@ -243,8 +245,9 @@ func (c *hednsProvider) getDiff1DomainCorrections(dc *models.DomainConfig, zoneI
 		record := mod.Desired
 		recordID := mod.Existing.Original.(Record).RecordID
 		corrections = append(corrections, &models.Correction{
-			Msg: mod.String(),
-			F:   func() error { return c.changeZoneRecord(zoneID, recordID, record) },
+			Msg:  mod.String(),
+			Kind: mod.Kind(),
+			F:    func() error { return c.changeZoneRecord(zoneID, recordID, record) },
 		})
 	}
 
@@ -263,7 +266,8 @@ func (c *hednsProvider) getDiff2DomainCorrections(dc *models.DomainConfig, zoneI
 		case diff2.CREATE:
 			record := change.New[0]
 			corrections = append(corrections, &models.Correction{
-				Msg: change.MsgsJoined,
+				Msg:  change.MsgsJoined,
+				Kind: change.Kind(),
 				F: func() error {
 					return c.createZoneRecord(zoneID, record)
 				},
@@ -272,7 +276,8 @@ func (c *hednsProvider) getDiff2DomainCorrections(dc *models.DomainConfig, zoneI
 			record := change.New[0]
 			recordID := change.Old[0].Original.(Record).RecordID
 			corrections = append(corrections, &models.Correction{
-				Msg: change.MsgsJoined,
+				Msg:  change.MsgsJoined,
+				Kind: change.Kind(),
 				F: func() error {
 					return c.changeZoneRecord(zoneID, recordID, record)
 				},
@@ -280,7 +285,8 @@ func (c *hednsProvider) getDiff2DomainCorrections(dc *models.DomainConfig, zoneI
 		case diff2.DELETE:
 			recordID := change.Old[0].Original.(Record).RecordID
 			corrections = append(corrections, &models.Correction{
-				Msg: change.MsgsJoined,
+				Msg:  change.MsgsJoined,
+				Kind: change.Kind(),
 				F: func() error {
 					return c.deleteZoneRecord(zoneID, recordID)
 				},
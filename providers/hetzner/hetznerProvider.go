@@ -31,7 +31,7 @@ func init() {
 		Initializer:   New,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("HETZNER", fns, features)
+	providers.RegisterDomainServiceProviderType("HETZNER", fns, features, txtutil.SplitLong)
 }
 
 // New creates a new API handle.
@@ -100,7 +100,7 @@ func (api *hetznerProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 
 	// Normalize
 	models.PostProcessRecords(existingRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	var create, del, modify diff.Changeset
@@ -123,7 +123,8 @@ func (api *hetznerProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 	for _, m := range del {
 		record := m.Existing.Original.(*record)
 		corr := &models.Correction{
-			Msg: m.String(),
+			Msg:  m.String(),
+			Kind: m.Kind(),
 			F: func() error {
 				return api.deleteRecord(*record)
 			},
@@ -140,7 +141,8 @@ func (api *hetznerProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 	}
 	if len(createRecords) > 0 {
 		corr := &models.Correction{
-			Msg: strings.Join(createDescription, "\n\t"),
+			Msg:  strings.Join(createDescription, "\n\t"),
+			Kind: models.CorrectionCreate,
 			F: func() error {
 				return api.bulkCreateRecords(createRecords)
 			},
@@ -159,7 +161,8 @@ func (api *hetznerProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 	}
 	if len(modifyRecords) > 0 {
 		corr := &models.Correction{
-			Msg: strings.Join(modifyDescription, "\n\t"),
+			Msg:  strings.Join(modifyDescription, "\n\t"),
+			Kind: models.CorrectionModify,
 			F: func() error {
 				return api.bulkUpdateRecords(modifyRecords)
 			},
@@ -0,0 +1,81 @@
+package hexonet
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
+	"github.com/StackExchange/dnscontrol/v3/pkg/dnssec"
+)
+
+// getDSRaw returns the DS records of a domain at the registry.
+func (n *HXClient) getDSRaw(domain string) (models.Records, error) {
+	r := n.client.Request(map[string]interface{}{
+		"COMMAND": "StatusDomain",
+		"DOMAIN":  domain,
+	})
+	code := r.GetCode()
+	if code != 200 {
+		return nil, n.GetHXApiError("Could not get status for domain", domain, r)
+	}
+	column := r.GetColumn("SECDNS-DS")
+	if column == nil {
+		return nil, nil
+	}
+	var ds models.Records
+	for _, value := range column.GetData() {
+		rc := &models.RecordConfig{Type: "DS"}
+		rc.SetLabel("@", domain)
+		if err := rc.SetTargetDSString(value); err != nil {
+			return nil, fmt.Errorf("invalid DS record %q received from HEXONET: %w", value, err)
+		}
+		ds = append(ds, rc)
+	}
+	return ds, nil
+}
+
+// getDSCorrections returns the corrections that publish the DS records
+// reported by the DNS providers (dc.DSRecords).
+func (n *HXClient) getDSCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	if dc.DSRecords == nil {
+		return nil, nil
+	}
+	existing, err := n.getDSRaw(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	add, del := dnssec.Diff(existing, dc.DSRecords)
+	if len(add) == 0 && len(del) == 0 {
+		return nil, nil
+	}
+
+	msg := fmt.Sprintf("Update DS records %s -> %s", dnssec.String(existing), dnssec.String(dc.DSRecords))
+	return []*models.Correction{
+		{
+			Msg:  msg,
+			Kind: diff.ReplaceKind(existing, dc.DSRecords),
+			F:    n.updateDS(add, del, dc.Name),
+		},
+	}, nil
+}
+
+func (n *HXClient) updateDS(add, del models.Records, domain string) func() error {
+	return func() error {
+		cmd := map[string]interface{}{
+			"COMMAND": "ModifyDomain",
+			"DOMAIN":  domain,
+		}
+		for idx, rc := range add {
+			cmd[fmt.Sprintf("ADDSECDNS-DS%d", idx)] = dnssec.String(models.Records{rc})
+		}
+		for idx, rc := range del {
+			cmd[fmt.Sprintf("DELSECDNS-DS%d", idx)] = dnssec.String(models.Records{rc})
+		}
+		response := n.client.Request(cmd)
+		code := response.GetCode()
+		if code != 200 {
+			return fmt.Errorf("%d %s", code, response.GetDescription())
+		}
+		return nil
+	}
+}
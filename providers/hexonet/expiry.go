@@ -0,0 +1,29 @@
+package hexonet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GetDomainExpiry returns the registration expiration date of the domain.
+func (n *HXClient) GetDomainExpiry(domain string) (time.Time, error) {
+	r := n.client.Request(map[string]interface{}{
+		"COMMAND": "StatusDomain",
+		"DOMAIN":  domain,
+	})
+	if r.GetCode() != 200 {
+		return time.Time{}, n.GetHXApiError("Could not get status for domain", domain, r)
+	}
+	c := r.GetColumn("REGISTRATIONEXPIRATIONDATE")
+	if c == nil || len(c.GetData()) == 0 {
+		return time.Time{}, fmt.Errorf("error getting REGISTRATIONEXPIRATIONDATE column for domain: %s", domain)
+	}
+	return parseDate(c.GetData()[0])
+}
+
+// parseDate parses the dates of the API ("2024-05-01 12:30:00.0", in UTC).
+func parseDate(s string) (time.Time, error) {
+	s, _, _ = strings.Cut(s, ".")
+	return time.Parse("2006-01-02 15:04:05", s)
+}
@@ -0,0 +1,19 @@
+package hexonet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	got, err := parseDate("2024-05-01 12:30:00.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("parseDate() = %s, want %s", got, want)
+	}
+	if _, err := parseDate("01/05/2024"); err == nil {
+		t.Errorf("parseDate(01/05/2024) returned no error")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 	"github.com/StackExchange/dnscontrol/v3/pkg/version"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	hxcl "github.com/hexonet/go-sdk/v3/apiclient"
@@ -70,5 +71,5 @@ func init() {
 		RecordAuditor: AuditRecords,
 	}
 	providers.RegisterRegistrarType("HEXONET", newReg)
-	providers.RegisterDomainServiceProviderType("HEXONET", fns, features)
+	providers.RegisterDomainServiceProviderType("HEXONET", fns, features, txtutil.SplitLong)
 }
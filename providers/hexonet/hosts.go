@@ -0,0 +1,55 @@
+package hexonet
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/glue"
+)
+
+// codeNotFound is the response code of StatusNameserver for a host
+// object that doesn't exist ("Entity reference not found").
+const codeNotFound = 545
+
+// GetHostCorrections returns the corrections of the host objects
+// (glue records) of the nameservers of dc.
+func (n *HXClient) GetHostCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	current := map[string][]string{}
+	for _, host := range glue.Hosts(dc) {
+		r := n.client.Request(map[string]interface{}{
+			"COMMAND":    "StatusNameserver",
+			"NAMESERVER": host,
+		})
+		switch r.GetCode() {
+		case 200:
+			current[host] = []string{}
+			if column := r.GetColumn("IPADDRESS"); column != nil {
+				current[host] = column.GetData()
+			}
+		case codeNotFound:
+		default:
+			return nil, n.GetHXApiError("Could not get status for nameserver", host, r)
+		}
+	}
+	return glue.Corrections(dc, current, n.setHost), nil
+}
+
+// setHost creates or updates the host object of a nameserver.
+func (n *HXClient) setHost(host string, ips []string, create bool) error {
+	cmd := map[string]interface{}{
+		"COMMAND":    "ModifyNameserver",
+		"NAMESERVER": host,
+	}
+	if create {
+		cmd["COMMAND"] = "AddNameserver"
+	}
+	for idx, ip := range ips {
+		cmd[fmt.Sprintf("IPADDRESS%d", idx)] = ip
+	}
+	response := n.client.Request(cmd)
+	code := response.GetCode()
+	if code != 200 {
+		return fmt.Errorf("%d %s", code, response.GetDescription())
+	}
+	return nil
+}
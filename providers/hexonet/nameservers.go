@@ -74,15 +74,20 @@ func (n *HXClient) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models.C
 	sort.Strings(expected)
 	expectedNameservers := strings.Join(expected, ",")
 
+	var corrections []*models.Correction
 	if foundNameservers != expectedNameservers {
-		return []*models.Correction{
-			{
-				Msg: fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
-				F:   n.updateNameservers(expected, dc.Name),
-			},
-		}, nil
+		corrections = append(corrections, &models.Correction{
+			Msg:  fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+			Kind: models.CorrectionModify,
+			F:    n.updateNameservers(expected, dc.Name),
+		})
 	}
-	return nil, nil
+
+	dsCorrections, err := n.getDSCorrections(dc)
+	if err != nil {
+		return nil, err
+	}
+	return append(corrections, dsCorrections...), nil
 }
 
 func (n *HXClient) updateNameservers(ns []string, domain string) func() error {
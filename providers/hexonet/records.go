@@ -72,7 +72,7 @@ func (n *HXClient) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Corr
 
 	// Normalize
 	models.PostProcessRecords(actual)
-	txtutil.SplitSingleLongTxt(dc.Records)
+	txtutil.SplitLong.Apply(dc.Records)
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 || true { // Remove "|| true" when diff2 version arrives
@@ -127,7 +127,8 @@ func (n *HXClient) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Corr
 
 		if changes {
 			corrections = append(corrections, &models.Correction{
-				Msg: msg,
+				Msg:  msg,
+				Kind: diff.Kind(create, del, mod),
 				F: func() error {
 					return n.updateZoneBy(params, dc.Name)
 				},
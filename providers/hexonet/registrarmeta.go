@@ -0,0 +1,69 @@
+package hexonet
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/registrarmeta"
+)
+
+// GetDomainMetaCorrections returns the corrections of the auto-renew
+// and WHOIS privacy (ID protection) settings.
+func (n *HXClient) GetDomainMetaCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	desired, err := registrarmeta.Desired(dc)
+	if err != nil || len(desired) == 0 {
+		return nil, err
+	}
+	r := n.client.Request(map[string]interface{}{
+		"COMMAND": "StatusDomain",
+		"DOMAIN":  dc.Name,
+	})
+	if r.GetCode() != 200 {
+		return nil, n.GetHXApiError("Could not get status for domain", dc.Name, r)
+	}
+	value := func(column string) string {
+		if c := r.GetColumn(column); c != nil && len(c.GetData()) > 0 {
+			return c.GetData()[0]
+		}
+		return ""
+	}
+	current := map[string]bool{
+		registrarmeta.AutoRenew:    value("RENEWALMODE") == "AUTORENEW",
+		registrarmeta.WhoisPrivacy: value("X-ACCEPT-WHOISTRUSTEE-TAC") == "1",
+	}
+	return registrarmeta.Corrections(dc, current, func(key string, on bool) error {
+		return n.setDomainMeta(dc.Name, key, on)
+	})
+}
+
+func (n *HXClient) setDomainMeta(domain, key string, on bool) error {
+	var cmd map[string]interface{}
+	switch key {
+	case registrarmeta.AutoRenew:
+		mode := "AUTOEXPIRE"
+		if on {
+			mode = "AUTORENEW"
+		}
+		cmd = map[string]interface{}{
+			"COMMAND":     "SetDomainRenewalmode",
+			"DOMAIN":      domain,
+			"RENEWALMODE": mode,
+		}
+	case registrarmeta.WhoisPrivacy:
+		tac := "0"
+		if on {
+			tac = "1"
+		}
+		cmd = map[string]interface{}{
+			"COMMAND":                   "ModifyDomain",
+			"DOMAIN":                    domain,
+			"X-ACCEPT-WHOISTRUSTEE-TAC": tac,
+		}
+	}
+	response := n.client.Request(cmd)
+	code := response.GetCode()
+	if code != 200 {
+		return fmt.Errorf("%d %s", code, response.GetDescription())
+	}
+	return nil
+}
@@ -6,8 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
-	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
+	"github.com/StackExchange/dnscontrol/v3/providers"
 	"golang.org/x/net/idna"
 )
 
@@ -18,6 +19,8 @@ type hostingdeProvider struct {
 	ownerAccountID string
 	baseURL        string
 	nameservers    []string
+	zoneConfigs    *providers.MemoMap[string, *zoneConfig] // Cache of fetchZoneConfig().
+	batch          providers.ZoneBatch[recordChanges]      // The changes of the zone updates in progress.
 }
 
 func (hp *hostingdeProvider) getDomainConfig(domain string) (*domainConfig, error) {
@@ -45,6 +48,32 @@ func (hp *hostingdeProvider) getDomainConfig(domain string) (*domainConfig, erro
 	return domainConf[0], nil
 }
 
+// getDomainExpiry returns the end of the current contract period of the
+// domain, when it is renewed or deleted.
+func (hp *hostingdeProvider) getDomainExpiry(domain string) (time.Time, error) {
+	params := request{
+		Filter: filter{
+			Field: "domainName",
+			Value: domain,
+		},
+	}
+	resp, err := hp.get("domain", "domainsFind", params)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting domain info: %w", err)
+	}
+	// Not in domainConfig, which is sent back by updates.
+	found := []struct {
+		CurrentContractPeriodEnd time.Time `json:"currentContractPeriodEnd"`
+	}{}
+	if err := json.Unmarshal(resp.Data, &found); err != nil {
+		return time.Time{}, fmt.Errorf("error parsing response: %w", err)
+	}
+	if len(found) == 0 {
+		return time.Time{}, fmt.Errorf("could not get domain config: %s", domain)
+	}
+	return found[0].CurrentContractPeriodEnd, nil
+}
+
 func (hp *hostingdeProvider) createZone(domain string) error {
 	t, err := idna.ToASCII(domain)
 	if err != nil {
@@ -70,6 +99,7 @@ func (hp *hostingdeProvider) createZone(domain string) error {
 	}
 
 	_, err = hp.get("dns", "zoneCreate", params)
+	hp.zoneConfigs.Invalidate(domain)
 	if err != nil {
 		return fmt.Errorf("error creating zone: %w", err)
 	}
@@ -125,6 +155,50 @@ func (hp *hostingdeProvider) updateNameservers(nss []string, domain string) func
 	}
 }
 
+func (hp *hostingdeProvider) updateContacts(contacts map[string]string, domain string) func() error {
+	return func() error {
+		domainConf, err := hp.getDomainConfig(domain)
+		if err != nil {
+			return err
+		}
+
+		for i, c := range domainConf.Contacts {
+			if handle, ok := contacts[c.Type]; ok {
+				domainConf.Contacts[i].Contact = handle
+			}
+		}
+
+		params := request{
+			Domain: domainConf,
+		}
+
+		if _, err := hp.get("domain", "domainUpdate", params); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+func (hp *hostingdeProvider) updateTransferLock(enabled bool, domain string) func() error {
+	return func() error {
+		domainConf, err := hp.getDomainConfig(domain)
+		if err != nil {
+			return err
+		}
+
+		domainConf.TransferLockEnabled = enabled
+
+		params := request{
+			Domain: domainConf,
+		}
+
+		if _, err := hp.get("domain", "domainUpdate", params); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
 func (hp *hostingdeProvider) getRecords(domain string) ([]*record, error) {
 	zc, err := hp.getZoneConfig(domain)
 	if err != nil {
@@ -163,32 +237,12 @@ func (hp *hostingdeProvider) getRecords(domain string) ([]*record, error) {
 	return records, nil
 }
 
-func (hp *hostingdeProvider) updateRecords(domain string, create, del, mod diff.Changeset) error {
+func (hp *hostingdeProvider) updateRecords(domain string, toAdd, toDelete, toModify []*record) error {
 	zc, err := hp.getZoneConfig(domain)
 	if err != nil {
 		return err
 	}
 
-	toAdd := []*record{}
-	for _, c := range create {
-		r := recordToNative(c.Desired)
-		toAdd = append(toAdd, r)
-	}
-
-	toDelete := []*record{}
-	for _, d := range del {
-		r := recordToNative(d.Existing)
-		r.ID = d.Existing.Original.(*record).ID
-		toDelete = append(toDelete, r)
-	}
-
-	toModify := []*record{}
-	for _, m := range mod {
-		r := recordToNative(m.Desired)
-		r.ID = m.Existing.Original.(*record).ID
-		toModify = append(toModify, r)
-	}
-
 	params := request{
 		ZoneConfig:      zc,
 		RecordsToAdd:    toAdd,
@@ -197,6 +251,7 @@ func (hp *hostingdeProvider) updateRecords(domain string, create, del, mod diff.
 	}
 
 	_, err = hp.get("dns", "zoneUpdate", params)
+	hp.zoneConfigs.Invalidate(domain)
 	if err != nil {
 		return err
 	}
@@ -204,6 +259,10 @@ func (hp *hostingdeProvider) updateRecords(domain string, create, del, mod diff.
 }
 
 func (hp *hostingdeProvider) getZoneConfig(domain string) (*zoneConfig, error) {
+	return hp.zoneConfigs.Get(domain)
+}
+
+func (hp *hostingdeProvider) fetchZoneConfig(domain string) (*zoneConfig, error) {
 	t, err := idna.ToASCII(domain)
 	if err != nil {
 		return nil, err
@@ -233,6 +292,40 @@ func (hp *hostingdeProvider) getZoneConfig(domain string) (*zoneConfig, error) {
 	return zc[0], nil
 }
 
+func (hp *hostingdeProvider) listZones() ([]string, error) {
+	zones := []string{}
+	page := uint(1)
+	for {
+		params := request{
+			Filter: filter{
+				Field: "ZoneName",
+				Value: "*",
+			},
+			Limit: 1000,
+			Page:  page,
+		}
+
+		resp, err := hp.get("dns", "zoneConfigsFind", params)
+		if err != nil {
+			return nil, fmt.Errorf("could not list zones: %w", err)
+		}
+
+		zc := []*zoneConfig{}
+		if err := json.Unmarshal(resp.Data, &zc); err != nil {
+			return nil, fmt.Errorf("could not parse response: %w", err)
+		}
+		for _, z := range zc {
+			zones = append(zones, z.NameUnicode)
+		}
+
+		if page >= resp.TotalPages {
+			break
+		}
+		page++
+	}
+	return zones, nil
+}
+
 func (hp *hostingdeProvider) get(service, method string, params request) (*responseData, error) {
 	params.AuthToken = hp.authToken
 	params.OwnerAccountID = hp.ownerAccountID
@@ -11,11 +11,16 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/metaschema"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ttlutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 )
 
 var defaultNameservers = []string{"ns1.hosting.de.", "ns2.hosting.de.", "ns3.hosting.de."}
 
+var logger = printer.Module("providers.hostingde")
+
 var features = providers.DocumentationNotes{
 	providers.CanAutoDNSSEC:          providers.Unimplemented("Supported but not implemented yet."),
 	providers.CanGetZones:            providers.Can(),
@@ -32,13 +37,18 @@ var features = providers.DocumentationNotes{
 	providers.DocOfficiallySupported: providers.Cannot(),
 }
 
+// TTL must be between (inclusive) 1m and 1y (in fact, a little bit more)
+var ttlLimits = ttlutil.Limits{Min: 60, Max: 31556926}
+
 func init() {
 	providers.RegisterRegistrarType("HOSTINGDE", newHostingdeReg)
 	fns := providers.DspFuncs{
 		Initializer:   newHostingdeDsp,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("HOSTINGDE", fns, features)
+	providers.RegisterDomainServiceProviderType("HOSTINGDE", fns, features, ttlLimits, metaschema.Schema{
+		"default_ns": metaschema.StringList,
+	})
 }
 
 type providerMeta struct {
@@ -63,6 +73,7 @@ func newHostingde(m map[string]string, providermeta json.RawMessage) (*hostingde
 		baseURL:        baseURL,
 		nameservers:    defaultNameservers,
 	}
+	hp.zoneConfigs = providers.NewMemoMap(hp.fetchZoneConfig)
 
 	if len(providermeta) > 0 {
 		var pm providerMeta
@@ -86,6 +97,11 @@ func newHostingdeReg(m map[string]string) (providers.Registrar, error) {
 	return newHostingde(m, json.RawMessage{})
 }
 
+// ListZones returns the zones in the account.
+func (hp *hostingdeProvider) ListZones() ([]string, error) {
+	return hp.listZones()
+}
+
 func (hp *hostingdeProvider) GetNameservers(domain string) ([]*models.Nameserver, error) {
 	return models.ToNameserversStripTD(hp.nameservers)
 }
@@ -113,23 +129,14 @@ func (hp *hostingdeProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 		return nil, err
 	}
 
-	// TTL must be between (inclusive) 1m and 1y (in fact, a little bit more)
-	for _, r := range dc.Records {
-		if r.TTL < 60 {
-			r.TTL = 60
-		}
-		if r.TTL > 31556926 {
-			r.TTL = 31556926
-		}
-	}
+	ttlLimits.Apply(dc.Records)
 
 	records, err := hp.GetZoneRecords(dc.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	var corrections []*models.Correction
-	if !diff2.EnableDiff2 || true { // Remove "|| true" when diff2 version arrives
+	if !diff2.EnableDiff2 {
 
 		differ := diff.New(dc)
 		_, create, del, mod, err := differ.IncrementalDiff(records)
@@ -151,35 +158,139 @@ func (hp *hostingdeProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 			return nil, nil
 		}
 
-		corrections = []*models.Correction{
+		toAdd := []*record{}
+		for _, c := range create {
+			toAdd = append(toAdd, recordToNative(c.Desired))
+		}
+
+		toDelete := []*record{}
+		for _, d := range del {
+			r := recordToNative(d.Existing)
+			r.ID = d.Existing.Original.(*record).ID
+			toDelete = append(toDelete, r)
+		}
+
+		toModify := []*record{}
+		for _, m := range mod {
+			r := recordToNative(m.Desired)
+			r.ID = m.Existing.Original.(*record).ID
+			toModify = append(toModify, r)
+		}
+
+		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("\n%s", strings.Join(msg, "\n")),
-				F: func() error {
-					for i := 0; i < 10; i++ {
-						err := hp.updateRecords(dc.Name, create, del, mod)
-						if err == nil {
-							return nil
-						}
-						// Code:10205 indicates the zone is currently blocked due to a running zone update.
-						if !strings.Contains(err.Error(), "Code:10205") {
-							return err
-						}
-
-						// Exponential back-off retry.
-						// Base of 1.8 seemed like a good trade-off, retrying for approximately 45 seconds.
-						time.Sleep(time.Duration(math.Pow(1.8, float64(i))) * 100 * time.Millisecond)
-					}
-					return fmt.Errorf("retry exhaustion: zone blocked for 10 attempts")
-				},
+				Msg:  fmt.Sprintf("\n%s", strings.Join(msg, "\n")),
+				Kind: diff.Kind(create, del, mod),
+				F:    hp.changeRecordsFunc(dc.Name, recordChanges{toAdd: toAdd, toDelete: toDelete, toModify: toModify}),
 			},
-		}
+		}, nil
+	}
+
+	changes, err := diff2.ByRecord(records, dc, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
 	}
 
-	// Insert Future diff2 version here.
+	// Each change is its own correction. "push" sends the changes of
+	// the zone together, in a single zoneUpdate call (see
+	// BeginZoneUpdate).
+	var corrections []*models.Correction
+	for _, change := range changes {
+		rc := recordChanges{toAdd: []*record{}, toDelete: []*record{}, toModify: []*record{}}
+		switch change.Type {
+		case diff2.CREATE:
+			rc.toAdd = []*record{recordToNative(change.New[0])}
+		case diff2.CHANGE:
+			r := recordToNative(change.New[0])
+			r.ID = change.Old[0].Original.(*record).ID
+			rc.toModify = []*record{r}
+		case diff2.DELETE:
+			r := recordToNative(change.Old[0])
+			r.ID = change.Old[0].Original.(*record).ID
+			rc.toDelete = []*record{r}
+		default:
+			corrections = append(corrections, &models.Correction{Msg: change.MsgsJoined})
+			continue
+		}
+		corrections = append(corrections, &models.Correction{
+			Msg:  change.MsgsJoined,
+			Kind: change.Kind(),
+			F:    hp.changeRecordsFunc(dc.Name, rc),
+		})
+	}
 
 	return corrections, nil
 }
 
+// recordChanges are changes to the records of a zone, as a zoneUpdate
+// call makes them.
+type recordChanges struct {
+	toAdd, toDelete, toModify []*record
+}
+
+// changeRecordsFunc returns a function that makes the changes to the
+// zone, or queues them if an update of the zone is in progress.
+func (hp *hostingdeProvider) changeRecordsFunc(domain string, changes recordChanges) func() error {
+	return func() error {
+		if hp.batch.Add(domain, changes) {
+			return providers.ErrQueued
+		}
+		return hp.updateRecordsFunc(domain, changes.toAdd, changes.toDelete, changes.toModify)()
+	}
+}
+
+// BeginZoneUpdate starts queuing the record changes of the zone.
+func (hp *hostingdeProvider) BeginZoneUpdate(domain string) error {
+	return hp.batch.Begin(domain)
+}
+
+// CommitZoneUpdate sends the queued record changes of the zone in a
+// single zoneUpdate call, which the API applies atomically.
+func (hp *hostingdeProvider) CommitZoneUpdate(domain string) error {
+	toAdd, toDelete, toModify := []*record{}, []*record{}, []*record{}
+	for _, c := range hp.batch.End(domain) {
+		toAdd = append(toAdd, c.toAdd...)
+		toDelete = append(toDelete, c.toDelete...)
+		toModify = append(toModify, c.toModify...)
+	}
+	if len(toAdd) == 0 && len(toDelete) == 0 && len(toModify) == 0 {
+		return nil
+	}
+	return hp.updateRecordsFunc(domain, toAdd, toDelete, toModify)()
+}
+
+// RollbackZoneUpdate discards the queued record changes of the zone.
+func (hp *hostingdeProvider) RollbackZoneUpdate(domain string) error {
+	hp.batch.End(domain)
+	return nil
+}
+
+// updateRecordsFunc returns a function that applies all the changes to
+// the zone, retrying while the zone is blocked by another update.
+func (hp *hostingdeProvider) updateRecordsFunc(domain string, toAdd, toDelete, toModify []*record) func() error {
+	return func() error {
+		for i := 0; i < 10; i++ {
+			err := hp.updateRecords(domain, toAdd, toDelete, toModify)
+			if err == nil {
+				return nil
+			}
+			// Code:10205 indicates the zone is currently blocked due to a running zone update.
+			if !strings.Contains(err.Error(), "Code:10205") {
+				return err
+			}
+
+			// Exponential back-off retry.
+			// Base of 1.8 seemed like a good trade-off, retrying for approximately 45 seconds.
+			time.Sleep(time.Duration(math.Pow(1.8, float64(i))) * 100 * time.Millisecond)
+		}
+		return fmt.Errorf("retry exhaustion: zone blocked for 10 attempts")
+	}
+}
+
 func (hp *hostingdeProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
 	err := dc.Punycode()
 	if err != nil {
@@ -200,21 +311,110 @@ func (hp *hostingdeProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([
 	sort.Strings(expected)
 	expectedNameservers := strings.Join(expected, ",")
 
+	var corrections []*models.Correction
+
 	// We don't care about glued records because we disallowed them
 	if foundNameservers != expectedNameservers {
-		return []*models.Correction{
-			{
-				Msg: fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
-				F:   hp.updateNameservers(expected, dc.Name),
-			},
-		}, nil
+		corrections = append(corrections, &models.Correction{
+			Msg:  fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+			Kind: models.CorrectionModify,
+			F:    hp.updateNameservers(expected, dc.Name),
+		})
+	}
+
+	domainCorrections, err := hp.getDomainSettingsCorrections(dc)
+	if err != nil {
+		return nil, err
 	}
+	corrections = append(corrections, domainCorrections...)
 
-	return nil, nil
+	return corrections, nil
 
 	// TODO: Handle AutoDNSSEC
 }
 
+const (
+	metaContactOwner = "hostingde_contact_owner"
+	metaContactAdmin = "hostingde_contact_admin"
+	metaContactTech  = "hostingde_contact_tech"
+	metaTransferLock = "hostingde_transfer_lock"
+)
+
+// contactTypes maps the D() metadata keys to hosting.de contact types.
+var contactTypes = []struct{ meta, contactType string }{
+	{metaContactOwner, "owner"},
+	{metaContactAdmin, "admin"},
+	{metaContactTech, "tech"},
+}
+
+// getDomainSettingsCorrections returns the corrections needed to bring
+// the contact handles and transfer lock in line with the D() metadata.
+// The domain is only looked up if any of the metadata is set.
+func (hp *hostingdeProvider) getDomainSettingsCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	wantContacts := map[string]string{}
+	for _, ct := range contactTypes {
+		if handle := dc.Metadata[ct.meta]; handle != "" {
+			wantContacts[ct.contactType] = handle
+		}
+	}
+
+	wantLock := dc.Metadata[metaTransferLock]
+	if wantLock != "" && wantLock != "on" && wantLock != "off" {
+		return nil, fmt.Errorf("hosting.de: %s must be \"on\" or \"off\", got %q", metaTransferLock, wantLock)
+	}
+
+	if len(wantContacts) == 0 && wantLock == "" {
+		return nil, nil
+	}
+
+	domainConf, err := hp.getDomainConfig(dc.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting domain config: %w", err)
+	}
+
+	var corrections []*models.Correction
+
+	changes := []string{}
+	found := map[string]bool{}
+	for _, c := range domainConf.Contacts {
+		found[c.Type] = true
+		if handle, ok := wantContacts[c.Type]; ok && handle != c.Contact {
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", c.Type, c.Contact, handle))
+		}
+	}
+	for _, ct := range contactTypes {
+		if _, ok := wantContacts[ct.contactType]; ok && !found[ct.contactType] {
+			return nil, fmt.Errorf("hosting.de: domain %s has no %s contact to replace", dc.Name, ct.contactType)
+		}
+	}
+	if len(changes) > 0 {
+		corrections = append(corrections, &models.Correction{
+			Msg:  fmt.Sprintf("Update contacts: %s", strings.Join(changes, ", ")),
+			Kind: models.CorrectionModify,
+			F:    hp.updateContacts(wantContacts, dc.Name),
+		})
+	}
+
+	if wantLock != "" {
+		enabled := wantLock == "on"
+		if enabled != domainConf.TransferLockEnabled {
+			corrections = append(corrections, &models.Correction{
+				Msg:  fmt.Sprintf("Update transfer lock %t -> %t", domainConf.TransferLockEnabled, enabled),
+				Kind: models.CorrectionModify,
+				F:    hp.updateTransferLock(enabled, dc.Name),
+			})
+		}
+	}
+
+	return corrections, nil
+}
+
+// GetDomainExpiry returns the end of the current contract period of the
+// domain.
+func (hp *hostingdeProvider) GetDomainExpiry(domain string) (time.Time, error) {
+	return hp.getDomainExpiry(domain)
+}
+
 func (hp *hostingdeProvider) EnsureDomainExists(domain string) error {
 	_, err := hp.getZoneConfig(domain)
 	if err == errZoneNotFound {
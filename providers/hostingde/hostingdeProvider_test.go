@@ -0,0 +1,188 @@
+package hostingde
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/mockapi"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+func newMockProvider(t *testing.T) (*hostingdeProvider, *mockapi.Server, *mockapi.Hostingde) {
+	mock := mockapi.NewHostingde()
+	mock.AddZone("example.com")
+	server := mockapi.NewServer(mock)
+	t.Cleanup(server.Close)
+	hp, err := newHostingde(server.Creds(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hp, server, mock
+}
+
+func countRequests(server *mockapi.Server, method string) int {
+	n := 0
+	for _, r := range server.Requests() {
+		if strings.HasSuffix(r, "/"+method) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGetZoneRecordsPages(t *testing.T) {
+	hp, server, mock := newMockProvider(t)
+	for i := 0; i < 1500; i++ {
+		mock.AddRecord("example.com", fmt.Sprintf("host%d.example.com", i), "A", "192.0.2.1", 3600, 0)
+	}
+
+	records, err := hp.GetZoneRecords("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The NS records of the zone and the A records, without the SOA.
+	if len(records) != 1503 {
+		t.Errorf("got %d records, want 1503", len(records))
+	}
+	if n := countRequests(server, "recordsFind"); n != 2 {
+		t.Errorf("got %d requests of records, want 2 pages", n)
+	}
+}
+
+func TestCorrectionsRetryBlockedZone(t *testing.T) {
+	hp, server, _ := newMockProvider(t)
+	server.Inject(mockapi.Fault{
+		Path:   "zoneUpdate",
+		Status: http.StatusOK,
+		Body:   `{"errors": [{"code": 10205, "text": "Zone is blocked by a running update"}], "status": "error"}`,
+		Times:  2,
+	})
+
+	rc := &models.RecordConfig{Type: "A", TTL: 300}
+	rc.SetLabel("www", "example.com")
+	rc.SetTarget("192.0.2.1")
+	dc := &models.DomainConfig{Name: "example.com", Records: models.Records{rc}}
+	corrections, err := hp.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range corrections {
+		if c.F == nil {
+			continue
+		}
+		if err := c.F(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := countRequests(server, "zoneUpdate"); n != 3 {
+		t.Errorf("got %d updates, want 3 (2 retries)", n)
+	}
+
+	records, err := hp.GetZoneRecords("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range records {
+		got = append(got, r.GetLabel()+" "+r.Type+" "+r.GetTargetField())
+	}
+	if strings.Join(got, "\n") != "www A 192.0.2.1" {
+		t.Errorf("got records:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestZoneUpdateTransaction(t *testing.T) {
+	hp, server, _ := newMockProvider(t)
+	dc := &models.DomainConfig{Name: "example.com"}
+	for _, label := range []string{"www", "api"} {
+		rc := &models.RecordConfig{Type: "A", TTL: 300}
+		rc.SetLabel(label, "example.com")
+		rc.SetTarget("192.0.2.1")
+		dc.Records = append(dc.Records, rc)
+	}
+	run := func() {
+		corrections, err := hp.GetDomainCorrections(dc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := hp.BeginZoneUpdate("example.com"); err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range corrections {
+			if c.F == nil {
+				continue
+			}
+			if err := c.F(); err != providers.ErrQueued {
+				t.Fatalf("got %v, want the change to be queued", err)
+			}
+		}
+		if n := countRequests(server, "zoneUpdate"); n != 0 {
+			t.Errorf("got %d updates before the commit, want 0", n)
+		}
+	}
+
+	// Rolled back, the zone keeps its NS records.
+	run()
+	if err := hp.RollbackZoneUpdate("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if records, err := hp.GetZoneRecords("example.com"); err != nil || len(records) != 3 {
+		t.Errorf("got records %v, %v after the rollback, want the 3 NS records", records, err)
+	}
+
+	// Committed, the NS records are replaced by the A records at once.
+	run()
+	if err := hp.CommitZoneUpdate("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRequests(server, "zoneUpdate"); n != 1 {
+		t.Errorf("got %d updates, want 1", n)
+	}
+	if records, err := hp.GetZoneRecords("example.com"); err != nil || len(records) != 2 {
+		t.Errorf("got records %v, %v after the commit, want 2", records, err)
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	hp, server, _ := newMockProvider(t)
+
+	server.Inject(mockapi.Fault{Path: "recordsFind", Status: http.StatusInternalServerError})
+	if _, err := hp.GetZoneRecords("example.com"); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("got error %v, want the HTTP status", err)
+	}
+
+	if _, err := hp.GetZoneRecords("example.net"); err == nil || !strings.Contains(err.Error(), "zone not found") {
+		t.Errorf("got error %v for an unknown zone, want zone not found", err)
+	}
+
+	hp.authToken = "x"
+	server.Inject(mockapi.Fault{
+		Path:   "zoneConfigsFind",
+		Status: http.StatusOK,
+		Body:   `{"errors": [{"code": 10100, "text": "Authentication failed"}], "status": "error"}`,
+	})
+	if _, err := hp.ListZones(); err == nil || !strings.Contains(err.Error(), "Authentication failed") {
+		t.Errorf("got error %v, want the text of the API error", err)
+	}
+}
+
+func TestGetDomainExpiry(t *testing.T) {
+	hp, _, mock := newMockProvider(t)
+	end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.SetDomainExpiry("example.com", end)
+
+	got, err := hp.GetDomainExpiry("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(end) {
+		t.Errorf("GetDomainExpiry() = %s, want %s", got, end)
+	}
+	if _, err := hp.GetDomainExpiry("example.net"); err == nil {
+		t.Errorf("GetDomainExpiry() of an unknown domain returned no error")
+	}
+}
@@ -3,7 +3,6 @@ package hostingde
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"net"
 	"strings"
 
@@ -46,11 +45,16 @@ type nameserver struct {
 	IPs  []net.IP `json:"ips"`
 }
 
+type contact struct {
+	Type    string `json:"type"`
+	Contact string `json:"contact"`
+}
+
 type domainConfig struct {
-	Name                string          `json:"name"`
-	Contacts            json.RawMessage `json:"contacts"`
-	Nameservers         []nameserver    `json:"nameservers"`
-	TransferLockEnabled bool            `json:"transferLockEnabled"`
+	Name                string       `json:"name"`
+	Contacts            []contact    `json:"contacts"`
+	Nameservers         []nameserver `json:"nameservers"`
+	TransferLockEnabled bool         `json:"transferLockEnabled"`
 }
 
 type zoneConfig struct {
@@ -178,7 +182,7 @@ func recordToNative(rc *models.RecordConfig) *record {
 		record.Priority = rc.SrvPriority
 		record.Content = fmt.Sprintf("%d %d %s", rc.SrvWeight, rc.SrvPort, strings.TrimSuffix(rc.GetTargetField(), "."))
 	default:
-		log.Printf("hosting.de rtype %v unimplemented", rc.Type)
+		logger.Warnf("hosting.de rtype %v unimplemented\n", rc.Type)
 	}
 
 	return record
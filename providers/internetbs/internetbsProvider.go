@@ -53,7 +53,8 @@ func (c *internetbsProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([
 	if foundNameservers != expectedNameservers {
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Update nameservers (%s) -> (%s)", foundNameservers, expectedNameservers),
+				Msg:  fmt.Sprintf("Update nameservers (%s) -> (%s)", foundNameservers, expectedNameservers),
+				Kind: models.CorrectionModify,
 				F: func() error {
 					return c.updateNameservers(expected, dc.Name)
 				},
@@ -72,7 +72,7 @@ func init() {
 		Initializer:   newInwxDsp,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("INWX", fns, features)
+	providers.RegisterDomainServiceProviderType("INWX", fns, features, txtutil.SplitLong)
 }
 
 // getOTP either returns the TOTPValue or uses TOTPKey and the current time to generate a valid TOTPValue.
@@ -235,7 +235,7 @@ func (api *inwxAPI) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Cor
 	}
 
 	models.PostProcessRecords(foundRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	err = checkRecords(dc.Records)
 	if err != nil {
@@ -258,23 +258,26 @@ func (api *inwxAPI) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Cor
 	for _, d := range create {
 		des := d.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: d.String(),
-			F:   func() error { return api.createRecord(dc.Name, des) },
+			Msg:  d.String(),
+			Kind: d.Kind(),
+			F:    func() error { return api.createRecord(dc.Name, des) },
 		})
 	}
 	for _, d := range del {
 		existingID := d.Existing.Original.(goinwx.NameserverRecord).ID
 		corrections = append(corrections, &models.Correction{
-			Msg: d.String(),
-			F:   func() error { return api.deleteRecord(existingID) },
+			Msg:  d.String(),
+			Kind: d.Kind(),
+			F:    func() error { return api.deleteRecord(existingID) },
 		})
 	}
 	for _, d := range mod {
 		rec := d.Desired
 		existingID := d.Existing.Original.(goinwx.NameserverRecord).ID
 		corrections = append(corrections, &models.Correction{
-			Msg: d.String(),
-			F:   func() error { return api.updateRecord(existingID, rec) },
+			Msg:  d.String(),
+			Kind: d.Kind(),
+			F:    func() error { return api.updateRecord(existingID, rec) },
 		})
 	}
 
@@ -375,8 +378,9 @@ func (api *inwxAPI) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models.
 	if foundNameservers != expectedNameservers {
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
-				F:   api.updateNameservers(expected, dc.Name),
+				Msg:  fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+				Kind: models.CorrectionModify,
+				F:    api.updateNameservers(expected, dc.Name),
 			},
 		}, nil
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ttlutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	"github.com/miekg/dns/dnsutil"
 	"golang.org/x/oauth2"
@@ -41,6 +42,9 @@ var allowedTTLValues = []uint32{
 	2419200, // 4 weeks
 }
 
+// Linode rounds the other TTLs up to the next allowed value.
+var ttlLimits = ttlutil.Limits{Allowed: allowedTTLValues}
+
 var srvRegexp = regexp.MustCompile(`^_(?P<Service>\w+)\.\_(?P<Protocol>\w+)$`)
 
 // linodeProvider is the handle for this provider.
@@ -98,7 +102,7 @@ func init() {
 		Initializer:   NewLinode,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("LINODE", fns, features)
+	providers.RegisterDomainServiceProviderType("LINODE", fns, features, ttlLimits)
 }
 
 // GetNameservers returns the nameservers for a domain.
@@ -172,7 +176,8 @@ func (api *linodeProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 				continue
 			}
 			corr := &models.Correction{
-				Msg: fmt.Sprintf("%s, Linode ID: %d", m.String(), id),
+				Msg:  fmt.Sprintf("%s, Linode ID: %d", m.String(), id),
+				Kind: m.Kind(),
 				F: func() error {
 					return api.deleteRecord(domainID, id)
 				},
@@ -189,7 +194,8 @@ func (api *linodeProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 				return nil, err
 			}
 			corr := &models.Correction{
-				Msg: fmt.Sprintf("%s: %s", m.String(), string(j)),
+				Msg:  fmt.Sprintf("%s: %s", m.String(), string(j)),
+				Kind: m.Kind(),
 				F: func() error {
 					record, err := api.createRecord(domainID, req)
 					if err != nil {
@@ -215,7 +221,8 @@ func (api *linodeProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 				return nil, err
 			}
 			corr := &models.Correction{
-				Msg: fmt.Sprintf("%s, Linode ID: %d: %s", m.String(), id, string(j)),
+				Msg:  fmt.Sprintf("%s, Linode ID: %d: %s", m.String(), id, string(j)),
+				Kind: m.Kind(),
 				F: func() error {
 					return api.modifyRecord(domainID, id, req)
 				},
@@ -348,16 +355,5 @@ func fixTarget(target, domain string) string {
 }
 
 func fixTTL(ttl uint32) uint32 {
-	// if the TTL is larger than the largest allowed value, return the largest allowed value
-	if ttl > allowedTTLValues[len(allowedTTLValues)-1] {
-		return allowedTTLValues[len(allowedTTLValues)-1]
-	}
-
-	for _, v := range allowedTTLValues {
-		if v >= ttl {
-			return v
-		}
-	}
-
-	return allowedTTLValues[0]
+	return ttlLimits.Fix(ttl)
 }
@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"sync"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/apistats"
+)
+
+// Memo caches the result of an expensive API call, such as listing
+// all the zones in an account, so that the call is made once per run
+// rather than once per domain in dnsconfig.js.  Errors are not cached;
+// the next Get() tries again.  A Memo is safe for concurrent use.
+//
+// Typical use:
+//
+//	type myProvider struct {
+//		zones *providers.Memo[[]zone]
+//	}
+//
+//	api.zones = providers.NewMemo(api.listAllZones)
+//	...
+//	zones, err := api.zones.Get()
+//
+// Call Invalidate() after making a change that affects the result
+// (for example, after creating a zone).
+type Memo[T any] struct {
+	fetch func() (T, error)
+
+	mu    sync.Mutex
+	valid bool
+	value T
+}
+
+// NewMemo returns a Memo that calls fetch the first time Get() is called.
+func NewMemo[T any](fetch func() (T, error)) *Memo[T] {
+	return &Memo[T]{fetch: fetch}
+}
+
+// Get returns the cached value, calling fetch if there is none.
+func (m *Memo[T]) Get() (T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.valid {
+		v, err := m.fetch()
+		if err != nil {
+			return v, err
+		}
+		m.value, m.valid = v, true
+	} else {
+		apistats.CacheHit()
+	}
+	return m.value, nil
+}
+
+// Invalidate discards the cached value.
+func (m *Memo[T]) Invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var zero T
+	m.value, m.valid = zero, false
+}
+
+// MemoMap is like Memo but caches one value per key, for APIs that
+// look up one item at a time (for example, the settings of a zone).
+// The values of different keys are fetched concurrently.
+type MemoMap[K comparable, V any] struct {
+	fetch func(K) (V, error)
+
+	mu    sync.Mutex
+	memos map[K]*Memo[V]
+}
+
+// NewMemoMap returns a MemoMap that calls fetch(key) the first time
+// Get(key) is called.
+func NewMemoMap[K comparable, V any](fetch func(K) (V, error)) *MemoMap[K, V] {
+	return &MemoMap[K, V]{fetch: fetch, memos: map[K]*Memo[V]{}}
+}
+
+// Get returns the cached value for key, calling fetch if there is none.
+func (m *MemoMap[K, V]) Get(key K) (V, error) {
+	m.mu.Lock()
+	memo, ok := m.memos[key]
+	if !ok {
+		memo = NewMemo(func() (V, error) { return m.fetch(key) })
+		m.memos[key] = memo
+	}
+	m.mu.Unlock()
+	return memo.Get()
+}
+
+// Invalidate discards the cached value for key.
+func (m *MemoMap[K, V]) Invalidate(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.memos, key)
+}
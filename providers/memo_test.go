@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemo(t *testing.T) {
+	calls := 0
+	fail := true
+	m := NewMemo(func() ([]string, error) {
+		calls++
+		if fail {
+			return nil, errors.New("boom")
+		}
+		return []string{"a", "b"}, nil
+	})
+
+	if _, err := m.Get(); err == nil {
+		t.Fatal("expected error")
+	}
+	fail = false
+	for i := 0; i < 3; i++ {
+		v, err := m.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(v) != 2 {
+			t.Errorf("got %v", v)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("fetch called %d times, want 2 (errors are not cached)", calls)
+	}
+
+	m.Invalidate()
+	m.Get()
+	if calls != 3 {
+		t.Errorf("fetch called %d times after Invalidate, want 3", calls)
+	}
+}
+
+func TestMemoMap(t *testing.T) {
+	calls := map[string]int{}
+	m := NewMemoMap(func(k string) (int, error) {
+		calls[k]++
+		if k == "bad" {
+			return 0, errors.New("boom")
+		}
+		return len(k), nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if v, _ := m.Get("foo"); v != 3 {
+			t.Errorf("Get(foo) = %d, want 3", v)
+		}
+		if v, _ := m.Get("quux"); v != 4 {
+			t.Errorf("Get(quux) = %d, want 4", v)
+		}
+		if _, err := m.Get("bad"); err == nil {
+			t.Errorf("Get(bad): expected error")
+		}
+	}
+	m.Invalidate("foo")
+	m.Get("foo")
+
+	want := map[string]int{"foo": 2, "quux": 1, "bad": 3}
+	for k, n := range want {
+		if calls[k] != n {
+			t.Errorf("fetch(%q) called %d times, want %d", k, calls[k], n)
+		}
+	}
+}
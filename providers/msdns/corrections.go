@@ -4,7 +4,6 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
-	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 )
 
 // GetDomainCorrections gets existing records, diffs them against existing, and returns corrections.
@@ -12,7 +11,7 @@ func (client *msdnsProvider) GenerateDomainCorrections(dc *models.DomainConfig,
 
 	// Normalize
 	models.PostProcessRecords(foundRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtPolicy.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	var creates, dels, modifications diff.Changeset
@@ -45,7 +44,8 @@ func (client *msdnsProvider) GenerateDomainCorrections(dc *models.DomainConfig,
 func (client *msdnsProvider) deleteRec(dnsserver, domainname string, cor diff.Correlation) *models.Correction {
 	rec := cor.Existing
 	return &models.Correction{
-		Msg: cor.String(),
+		Msg:  cor.String(),
+		Kind: cor.Kind(),
 		F: func() error {
 			return client.shell.RecordDelete(dnsserver, domainname, rec)
 		},
@@ -55,7 +55,8 @@ func (client *msdnsProvider) deleteRec(dnsserver, domainname string, cor diff.Co
 func (client *msdnsProvider) createRec(dnsserver, domainname string, cre diff.Correlation) []*models.Correction {
 	rec := cre.Desired
 	arr := []*models.Correction{{
-		Msg: cre.String(),
+		Msg:  cre.String(),
+		Kind: cre.Kind(),
 		F: func() error {
 			return client.shell.RecordCreate(dnsserver, domainname, rec)
 		},
@@ -66,7 +67,8 @@ func (client *msdnsProvider) createRec(dnsserver, domainname string, cre diff.Co
 func (client *msdnsProvider) modifyRec(dnsserver, domainname string, m diff.Correlation) *models.Correction {
 	old, rec := m.Existing, m.Desired
 	return &models.Correction{
-		Msg: m.String(),
+		Msg:  m.String(),
+		Kind: m.Kind(),
 		F: func() error {
 			return client.shell.RecordModify(dnsserver, domainname, old, rec)
 		},
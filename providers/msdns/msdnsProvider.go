@@ -33,6 +33,10 @@ var features = providers.DocumentationNotes{
 	providers.DocOfficiallySupported: providers.Can(),
 }
 
+// txtPolicy splits the long TXT strings, but the API doesn't accept
+// TXT records with multiple strings from the user (see AuditRecords).
+var txtPolicy = txtutil.Policy{MaxLength: 255, AutoSplit: true}
+
 // Register with the dnscontrol system.
 //
 //	This establishes the name (all caps), and the function to call to initialize it.
@@ -41,12 +45,13 @@ func init() {
 		Initializer:   newDNS,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("MSDNS", fns, features)
+	providers.RegisterDomainServiceProviderType("MSDNS", fns, features, txtPolicy)
 }
 
 func newDNS(config map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
 
-	if runtime.GOOS != "windows" {
+	// Without WinRM, the PowerShell commands run locally.
+	if runtime.GOOS != "windows" && config["transport"] != "winrm" {
 		printer.Println("INFO: MSDNS deactivated. Required OS not detected.")
 		return providers.None{}, nil
 	}
@@ -87,7 +92,7 @@ func (client *msdnsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 		return nil, err
 	}
 	models.PostProcessRecords(existing)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtPolicy.Apply(dc.Records) // Autosplit long TXT records
 
 	clean := PrepFoundRecords(existing)
 	PrepDesiredRecords(dc)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
@@ -16,11 +17,24 @@ import (
 )
 
 type psHandle struct {
-	shell ps.Shell
+	shell  ps.Shell
+	remote bool // The shell runs on another machine, which can't write local files.
 }
 
 func newPowerShell(config map[string]string) (*psHandle, error) {
 
+	switch config["transport"] {
+	case "", "powershell":
+	case "winrm":
+		shell, err := newWinRMShell(config)
+		if err != nil {
+			return nil, err
+		}
+		return &psHandle{shell: shell, remote: true}, nil
+	default:
+		return nil, fmt.Errorf("MSDNS: transport must be powershell or winrm, not %q", config["transport"])
+	}
+
 	back := &backend.Local{}
 	sh, err := ps.New(back)
 	if err != nil {
@@ -100,6 +114,17 @@ func generatePSZoneAll(dnsserver string) string {
 }
 
 func (psh *psHandle) GetDNSZoneRecords(dnsserver, domain string) ([]nativeRecord, error) {
+	if psh.remote {
+		stdout, stderr, err := psh.shell.Execute(generatePSZoneDump(dnsserver, domain, ""))
+		if err != nil {
+			return nil, err
+		}
+		if stderr != "" {
+			printer.Printf("STDERROR GetDNSZR = %q\n", stderr)
+			return nil, fmt.Errorf("unexpected stderr from PSZoneDump: %q", stderr)
+		}
+		return parseZoneDump([]byte(stdout))
+	}
 
 	tmpfile, err := os.CreateTemp("", "zonerecords.*.json")
 	if err != nil {
@@ -130,8 +155,17 @@ func (psh *psHandle) GetDNSZoneRecords(dnsserver, domain string) ([]nativeRecord
 	//printer.Printf("CONTENTS STR = %q\n", contents[:10])
 	//printer.Printf("CONTENTS HEX = %v\n", []byte(contents)[:10])
 	//os.WriteFile("/temp/list.json", contents, 0777)
+	return parseZoneDump(contents)
+}
+
+// parseZoneDump decodes the JSON output of the command of generatePSZoneDump.
+func parseZoneDump(contents []byte) ([]nativeRecord, error) {
+	contents = bytes.TrimPrefix(contents, []byte("\xef\xbb\xbf")) // BOM
+	if len(bytes.TrimSpace(contents)) == 0 {
+		return nil, nil // An empty zone.
+	}
 	var records []nativeRecord
-	err = json.Unmarshal(contents, &records)
+	err := json.Unmarshal(contents, &records)
 	if err != nil {
 		// PowerShell generates bad JSON if there is only one record.  Therefore, if there
 		// is an error we try decoding the bad format before completing erroring out.
@@ -183,7 +217,11 @@ func generatePSZoneDump(dnsserver, domainname, filename string) string {
 
 	// Prevously we captured stdout. Now we write it to a file. This is
 	// safer since there is no chance of junk accidentally being mixed
-	// into stdout.
+	// into stdout. Remote shells can't write a local file, thus they
+	// still use stdout (filename is empty).
+	if filename == "" {
+		return strings.TrimSuffix(b.String(), ` | `)
+	}
 	fmt.Fprintf(&b, `Out-File "%s" -Encoding utf8`, filename)
 	return b.String()
 }
@@ -40,6 +40,7 @@ func Test_generatePSZoneDump(t *testing.T) {
 	type args struct {
 		domainname string
 		dnsserver  string
+		filename   string
 	}
 	tests := []struct {
 		name string
@@ -48,18 +49,23 @@ func Test_generatePSZoneDump(t *testing.T) {
 	}{
 		{
 			name: "local",
-			args: args{domainname: "example.com"},
+			args: args{domainname: "example.com", filename: "foo"},
 			want: `$OutputEncoding = [Text.UTF8Encoding]::UTF8 ; Get-DnsServerResourceRecord -ZoneName "example.com" | Select-Object -Property * -ExcludeProperty Cim* | ConvertTo-Json -depth 4 | Out-File "foo" -Encoding utf8`,
 		},
 		{
 			name: "remote",
-			args: args{domainname: "example.com", dnsserver: "mydnsserver"},
+			args: args{domainname: "example.com", dnsserver: "mydnsserver", filename: "foo"},
 			want: `$OutputEncoding = [Text.UTF8Encoding]::UTF8 ; Get-DnsServerResourceRecord -ComputerName "mydnsserver" -ZoneName "example.com" | Select-Object -Property * -ExcludeProperty Cim* | ConvertTo-Json -depth 4 | Out-File "foo" -Encoding utf8`,
 		},
+		{
+			name: "stdout",
+			args: args{domainname: "example.com"},
+			want: `$OutputEncoding = [Text.UTF8Encoding]::UTF8 ; Get-DnsServerResourceRecord -ZoneName "example.com" | Select-Object -Property * -ExcludeProperty Cim* | ConvertTo-Json -depth 4`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := generatePSZoneDump(tt.args.dnsserver, tt.args.domainname, "foo"); got != tt.want {
+			if got := generatePSZoneDump(tt.args.dnsserver, tt.args.domainname, tt.args.filename); got != tt.want {
 				t.Errorf("generatePSZoneDump() = got=(\n%s\n) want=(\n%s\n)", got, tt.want)
 			}
 		})
@@ -0,0 +1,390 @@
+package msdns
+
+// The WinRM transport runs the PowerShell commands on a remote Windows
+// server over WS-Management, so that dnscontrol doesn't need to run on
+// Windows. It implements just enough of [MS-WSMV]: a remote shell is
+// created once, each command runs powershell.exe in it, and the shell
+// is deleted by Exit().
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/Azure/go-ntlmssp"
+	"github.com/StackExchange/dnscontrol/v3/pkg/httpclient"
+	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+)
+
+const (
+	wsmanShellURI   = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/cmd"
+	wsmanCreate     = "http://schemas.xmlsoap.org/ws/2004/09/transfer/Create"
+	wsmanDelete     = "http://schemas.xmlsoap.org/ws/2004/09/transfer/Delete"
+	wsmanCommand    = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Command"
+	wsmanReceive    = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Receive"
+	wsmanSignal     = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Signal"
+	wsmanTerminate  = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/signal/terminate"
+	wsmanStateDone  = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/CommandState/Done"
+	wsmanTimedOut   = "2150858793" // The fault code of a Receive that had no output yet.
+	wsmanMaxCommand = 32000        // The longest command line Windows accepts, roughly.
+)
+
+// winrmShell runs PowerShell commands on a remote server. It
+// implements the Shell interface of go-powershell.
+type winrmShell struct {
+	endpoint string
+	username string
+	password string
+	ntlm     bool // Authenticate with NTLM rather than Basic.
+	client   *http.Client
+	shellID  string
+}
+
+// newWinRMShell connects to the WinRM service of the server named by
+// the pssession setting of creds.json.
+func newWinRMShell(config map[string]string) (*winrmShell, error) {
+	host := config["pssession"]
+	if host == "" {
+		return nil, fmt.Errorf("MSDNS: transport winrm requires pssession (the server to connect to)")
+	}
+	scheme := strings.ToLower(config["winrmscheme"])
+	if scheme == "" {
+		scheme = "https"
+	}
+	port := config["winrmport"]
+	switch {
+	case scheme != "https" && scheme != "http":
+		return nil, fmt.Errorf("MSDNS: winrmscheme must be https or http, not %q", scheme)
+	case port != "":
+	case scheme == "https":
+		port = "5986"
+	default:
+		port = "5985"
+	}
+	auth := strings.ToLower(config["winrmauth"])
+	if auth != "" && auth != "ntlm" && auth != "basic" {
+		return nil, fmt.Errorf("MSDNS: winrmauth must be ntlm or basic, not %q", auth)
+	}
+	insecure, _ := strconv.ParseBool(config["winrminsecure"])
+
+	s := &winrmShell{
+		endpoint: fmt.Sprintf("%s://%s/wsman", scheme, net.JoinHostPort(host, port)),
+		username: config["psusername"],
+		password: config["pspassword"],
+		ntlm:     auth != "basic",
+		client: &http.Client{
+			Timeout: 5 * time.Minute,
+			Transport: httpclient.Wrap(&http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				// NTLM authenticates the connection, thus all the
+				// requests must use the same one.
+				MaxConnsPerHost: 1,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+			}),
+		},
+	}
+	printer.Printf("INFO: PowerShell commands will run on %s\n", s.endpoint)
+	if scheme == "http" {
+		// The messages aren't sealed with the NTLM session key, as
+		// Windows clients do over HTTP.
+		printer.Warnf("MSDNS: WinRM over http sends the commands unencrypted; the server must allow it (AllowUnencrypted)\n")
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Execute runs a PowerShell command and returns its output. Errors
+// written by PowerShell are returned as stderr, like with a local shell.
+func (s *winrmShell) Execute(cmd string) (string, string, error) {
+	// Progress messages would be reported on stderr. The output is
+	// read as UTF-8.
+	script := `$ProgressPreference = 'SilentlyContinue' ; [Console]::OutputEncoding = [Text.Encoding]::UTF8 ; ` + cmd
+	encoded := base64.StdEncoding.EncodeToString(utf16le(script))
+	if len(encoded) > wsmanMaxCommand {
+		return "", "", fmt.Errorf("MSDNS: the PowerShell command is too long for WinRM (%d characters)", len(script))
+	}
+
+	resp, err := s.call(wsmanCommand, s.shellID, `<w:OptionSet><w:Option Name="WINRS_CONSOLEMODE_STDIN">TRUE</w:Option><w:Option Name="WINRS_SKIP_CMD_SHELL">TRUE</w:Option></w:OptionSet>`,
+		`<rsp:CommandLine><rsp:Command>powershell.exe</rsp:Command><rsp:Arguments>-NoProfile -NonInteractive -EncodedCommand `+encoded+`</rsp:Arguments></rsp:CommandLine>`)
+	if err != nil {
+		return "", "", err
+	}
+	commandID := resp.Body.CommandID
+	if commandID == "" {
+		return "", "", fmt.Errorf("MSDNS: WinRM returned no command id")
+	}
+	defer s.call(wsmanSignal, s.shellID, "",
+		`<rsp:Signal CommandId="`+commandID+`"><rsp:Code>`+wsmanTerminate+`</rsp:Code></rsp:Signal>`)
+
+	var stdout, stderr bytes.Buffer
+	for {
+		resp, err := s.call(wsmanReceive, s.shellID, "",
+			`<rsp:Receive><rsp:DesiredStream CommandId="`+commandID+`">stdout stderr</rsp:DesiredStream></rsp:Receive>`)
+		if err != nil {
+			if fault, ok := err.(*wsmanFault); ok && fault.Code == wsmanTimedOut {
+				continue // The command is still running.
+			}
+			return "", "", err
+		}
+		for _, st := range resp.Body.Streams {
+			data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(st.Data))
+			if err != nil {
+				return "", "", fmt.Errorf("MSDNS: invalid WinRM output: %w", err)
+			}
+			if st.Name == "stderr" {
+				stderr.Write(data)
+			} else {
+				stdout.Write(data)
+			}
+		}
+		if resp.Body.State.State == wsmanStateDone {
+			break
+		}
+	}
+	return stdout.String(), decodeCLIXML(stderr.String()), nil
+}
+
+// Exit deletes the remote shell.
+func (s *winrmShell) Exit() {
+	if s.shellID != "" {
+		s.call(wsmanDelete, s.shellID, "", "")
+		s.shellID = ""
+	}
+}
+
+// open creates the remote shell.
+func (s *winrmShell) open() error {
+	resp, err := s.call(wsmanCreate, "", `<w:OptionSet><w:Option Name="WINRS_NOPROFILE">TRUE</w:Option><w:Option Name="WINRS_CODEPAGE">65001</w:Option></w:OptionSet>`,
+		`<rsp:Shell><rsp:InputStreams>stdin</rsp:InputStreams><rsp:OutputStreams>stdout stderr</rsp:OutputStreams></rsp:Shell>`)
+	if err != nil {
+		return err
+	}
+	s.shellID = resp.Body.Shell.ShellID
+	for _, sel := range resp.Body.Created.Selectors {
+		if sel.Name == "ShellId" && s.shellID == "" {
+			s.shellID = sel.Value
+		}
+	}
+	if s.shellID == "" {
+		return fmt.Errorf("MSDNS: WinRM returned no shell id")
+	}
+	return nil
+}
+
+// wsmanResponse holds the parts of the responses that are used. The
+// elements are matched by their local name.
+type wsmanResponse struct {
+	Body struct {
+		Shell struct {
+			ShellID string `xml:"ShellId"`
+		} `xml:"Shell"`
+		Created struct {
+			Selectors []struct {
+				Name  string `xml:"Name,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"ReferenceParameters>SelectorSet>Selector"`
+		} `xml:"ResourceCreated"`
+		CommandID string `xml:"CommandResponse>CommandId"`
+		Streams   []struct {
+			Name string `xml:"Name,attr"`
+			Data string `xml:",chardata"`
+		} `xml:"ReceiveResponse>Stream"`
+		State struct {
+			State string `xml:"State,attr"`
+		} `xml:"ReceiveResponse>CommandState"`
+		Fault struct {
+			Reason string `xml:"Reason>Text"`
+			Detail struct {
+				Code string `xml:"Code,attr"`
+			} `xml:"Detail>WSManFault"`
+		} `xml:"Fault"`
+	} `xml:"Body"`
+}
+
+// wsmanFault is the error returned for a SOAP fault.
+type wsmanFault struct {
+	Code   string
+	Reason string
+}
+
+func (f *wsmanFault) Error() string {
+	return fmt.Sprintf("MSDNS: WinRM error %s: %s", f.Code, strings.TrimSpace(f.Reason))
+}
+
+// call sends a WS-Management request about the shell, and returns the
+// response.
+func (s *winrmShell) call(action, shellID, options, body string) (*wsmanResponse, error) {
+	var selector string
+	if shellID != "" {
+		selector = `<w:SelectorSet><w:Selector Name="ShellId">` + shellID + `</w:Selector></w:SelectorSet>`
+	}
+	envelope := `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:w="http://schemas.dmtf.org/wbem/wsman/1/wsman.xsd" xmlns:rsp="http://schemas.microsoft.com/wbem/wsman/1/windows/shell">` +
+		`<env:Header>` +
+		`<a:To>` + html.EscapeString(s.endpoint) + `</a:To>` +
+		`<a:ReplyTo><a:Address env:mustUnderstand="true">http://schemas.xmlsoap.org/ws/2004/08/addressing/role/anonymous</a:Address></a:ReplyTo>` +
+		`<w:MaxEnvelopeSize env:mustUnderstand="true">153600</w:MaxEnvelopeSize>` +
+		`<a:MessageID>uuid:` + newUUID() + `</a:MessageID>` +
+		`<w:Locale xml:lang="en-US" env:mustUnderstand="false"/>` +
+		`<w:OperationTimeout>PT60S</w:OperationTimeout>` +
+		`<w:ResourceURI env:mustUnderstand="true">` + wsmanShellURI + `</w:ResourceURI>` +
+		`<a:Action env:mustUnderstand="true">` + action + `</a:Action>` +
+		selector + options +
+		`</env:Header>` +
+		`<env:Body>` + body + `</env:Body>` +
+		`</env:Envelope>`
+
+	data, status, err := s.post([]byte(envelope))
+	if err != nil {
+		return nil, err
+	}
+	var resp wsmanResponse
+	if err := xml.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("MSDNS: WinRM returned HTTP %d and invalid XML: %w", status, err)
+	}
+	if f := resp.Body.Fault; f.Reason != "" || f.Detail.Code != "" {
+		return nil, &wsmanFault{Code: f.Detail.Code, Reason: f.Reason}
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("MSDNS: WinRM returned HTTP %d", status)
+	}
+	return &resp, nil
+}
+
+// post sends a request, authenticating it.
+func (s *winrmShell) post(body []byte) ([]byte, int, error) {
+	var auth string
+	if s.ntlm {
+		// Negotiate on the connection, then send the request with
+		// the answer to the challenge.
+		user, domain, domainNeeded := ntlmssp.GetDomain(s.username)
+		negotiate, err := ntlmssp.NewNegotiateMessage(domain, "")
+		if err != nil {
+			return nil, 0, err
+		}
+		resp, err := s.do(nil, "Negotiate "+base64.StdEncoding.EncodeToString(negotiate))
+		if err != nil {
+			return nil, 0, err
+		}
+		challenge, err := ntlmChallengeFrom(resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return nil, 0, fmt.Errorf("MSDNS: WinRM NTLM authentication failed (HTTP %d): %w", resp.StatusCode, err)
+		}
+		msg, err := ntlmssp.ProcessChallenge(challenge, user, s.password, domainNeeded)
+		if err != nil {
+			return nil, 0, fmt.Errorf("MSDNS: WinRM NTLM authentication failed: %w", err)
+		}
+		auth = "Negotiate " + base64.StdEncoding.EncodeToString(msg)
+	} else {
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(s.username+":"+s.password))
+	}
+
+	resp, err := s.do(body, auth)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, 0, fmt.Errorf("MSDNS: WinRM authentication failed for %q (HTTP 401)", s.username)
+	}
+	return resp.body, resp.StatusCode, nil
+}
+
+type winrmHTTPResponse struct {
+	*http.Response
+	body []byte
+}
+
+// do sends one HTTP request and reads the whole response, so that the
+// connection can be used again.
+func (s *winrmShell) do(body []byte, auth string) (*winrmHTTPResponse, error) {
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/soap+xml;charset=UTF-8")
+	req.Header.Set("Authorization", auth)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("MSDNS: WinRM: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("MSDNS: WinRM: %w", err)
+	}
+	return &winrmHTTPResponse{Response: resp, body: data}, nil
+}
+
+// ntlmChallengeFrom returns the NTLM challenge of a WWW-Authenticate
+// header.
+func ntlmChallengeFrom(header string) ([]byte, error) {
+	for _, scheme := range []string{"Negotiate ", "NTLM "} {
+		if strings.HasPrefix(header, scheme) {
+			return base64.StdEncoding.DecodeString(strings.TrimSpace(header[len(scheme):]))
+		}
+	}
+	return nil, fmt.Errorf("no NTLM challenge in %q", header)
+}
+
+func utf16le(s string) []byte {
+	u := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(u))
+	for i, r := range u {
+		binary.LittleEndian.PutUint16(b[2*i:], r)
+	}
+	return b
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%X-%X-%X-%X-%X", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+var clixmlEscape = regexp.MustCompile(`_x([0-9A-Fa-f]{4})_`)
+
+// decodeCLIXML returns the errors of a stderr that PowerShell
+// serialized as CLIXML, which it does when it isn't run interactively.
+// Other records (such as progress) are dropped. Any other stderr is
+// returned as is.
+func decodeCLIXML(stderr string) string {
+	const header = "#< CLIXML"
+	if !strings.HasPrefix(stderr, header) {
+		return stderr
+	}
+	var objs struct {
+		S []struct {
+			S    string `xml:"S,attr"`
+			Text string `xml:",chardata"`
+		} `xml:"S"`
+	}
+	if err := xml.Unmarshal([]byte(strings.TrimSpace(stderr[len(header):])), &objs); err != nil {
+		return stderr
+	}
+	var b strings.Builder
+	for _, s := range objs.S {
+		if s.S == "Error" {
+			b.WriteString(clixmlEscape.ReplaceAllStringFunc(s.Text, func(m string) string {
+				r, _ := strconv.ParseUint(m[2:6], 16, 16)
+				return string(rune(r))
+			}))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,209 @@
+package msdns
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/md4"
+)
+
+// fakeWinRM is a WinRM service that runs every command by answering
+// with the same output. It checks the NTLM authentication of each
+// request.
+type fakeWinRM struct {
+	password string
+	stdout   []string // The chunks of stdout.
+	stderr   string
+	commands []string // The PowerShell scripts that were run.
+	actions  []string
+	receives int
+}
+
+var (
+	fakeChallenge  = []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	fakeActionRE   = regexp.MustCompile(`<a:Action[^>]*>[^<]*/([A-Za-z]+)</a:Action>`)
+	fakeEncodedRE  = regexp.MustCompile(`-EncodedCommand ([A-Za-z0-9+/=]+)`)
+	fakeSoapHeader = `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:x="http://schemas.xmlsoap.org/ws/2004/09/transfer" xmlns:w="http://schemas.dmtf.org/wbem/wsman/1/wsman.xsd" xmlns:rsp="http://schemas.microsoft.com/wbem/wsman/1/windows/shell"><s:Body>`
+	fakeSoapFooter = `</s:Body></s:Envelope>`
+)
+
+func (f *fakeWinRM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	msg, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(r.Header.Get("Authorization"), "Negotiate "))
+	if len(msg) < 12 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch binary.LittleEndian.Uint32(msg[8:]) {
+	case 1:
+		w.Header().Set("WWW-Authenticate", "Negotiate "+base64.StdEncoding.EncodeToString(fakeChallengeMessage()))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	case 3:
+		if !f.checkAuthenticate(msg) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	action := fakeActionRE.FindStringSubmatch(string(body))[1]
+	f.actions = append(f.actions, action)
+	var resp string
+	switch action {
+	case "Create":
+		resp = `<x:ResourceCreated><a:ReferenceParameters><w:SelectorSet><w:Selector Name="ShellId">SHELL-1</w:Selector></w:SelectorSet></a:ReferenceParameters></x:ResourceCreated>`
+	case "Command":
+		enc := fakeEncodedRE.FindStringSubmatch(string(body))[1]
+		raw, _ := base64.StdEncoding.DecodeString(enc)
+		u := make([]uint16, len(raw)/2)
+		for i := range u {
+			u[i] = binary.LittleEndian.Uint16(raw[2*i:])
+		}
+		f.commands = append(f.commands, string(utf16.Decode(u)))
+		f.receives = 0
+		resp = `<rsp:CommandResponse><rsp:CommandId>CMD-1</rsp:CommandId></rsp:CommandResponse>`
+	case "Receive":
+		f.receives++
+		if f.receives == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, fakeSoapHeader+`<s:Fault><s:Reason><s:Text xml:lang="en-US">The WS-Management service cannot complete the operation within the time specified in OperationTimeout.</s:Text></s:Reason><s:Detail><f:WSManFault xmlns:f="http://schemas.microsoft.com/wbem/wsman/1/wsmanfault" Code="2150858793" Machine="dc1"></f:WSManFault></s:Detail></s:Fault>`+fakeSoapFooter)
+			return
+		}
+		resp = `<rsp:ReceiveResponse>`
+		if i := f.receives - 2; i < len(f.stdout) {
+			resp += `<rsp:Stream Name="stdout" CommandId="CMD-1">` + base64.StdEncoding.EncodeToString([]byte(f.stdout[i])) + `</rsp:Stream>`
+		}
+		state := "Running"
+		if f.receives-1 >= len(f.stdout) {
+			resp += `<rsp:Stream Name="stderr" CommandId="CMD-1">` + base64.StdEncoding.EncodeToString([]byte(f.stderr)) + `</rsp:Stream>`
+			state = "Done"
+		}
+		resp += `<rsp:CommandState CommandId="CMD-1" State="http://schemas.microsoft.com/wbem/wsman/1/windows/shell/CommandState/` + state + `"></rsp:CommandState></rsp:ReceiveResponse>`
+	}
+	fmt.Fprint(w, fakeSoapHeader+resp+fakeSoapFooter)
+}
+
+// fakeChallengeMessage returns the CHALLENGE_MESSAGE of the server
+// of the CORP domain.
+func fakeChallengeMessage() []byte {
+	const (
+		negotiateUnicode        = 0x00000001
+		requestTarget           = 0x00000004
+		negotiateNTLM           = 0x00000200
+		targetTypeDomain        = 0x00010000
+		extendedSessionSecurity = 0x00080000
+		negotiateTargetInfo     = 0x00800000
+	)
+	target := utf16le("CORP")
+	targetInfo := []byte{7, 0, 8, 0, 1, 2, 3, 4, 5, 6, 7, 8, 0, 0, 0, 0} // MsvAvTimestamp, MsvAvEOL
+	b := make([]byte, 48)
+	copy(b, "NTLMSSP\x00")
+	binary.LittleEndian.PutUint32(b[8:], 2)
+	binary.LittleEndian.PutUint16(b[12:], uint16(len(target)))
+	binary.LittleEndian.PutUint16(b[14:], uint16(len(target)))
+	binary.LittleEndian.PutUint32(b[16:], 48)
+	binary.LittleEndian.PutUint32(b[20:], negotiateUnicode|requestTarget|negotiateNTLM|targetTypeDomain|extendedSessionSecurity|negotiateTargetInfo)
+	copy(b[24:], fakeChallenge)
+	binary.LittleEndian.PutUint16(b[40:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint16(b[42:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint32(b[44:], uint32(48+len(target)))
+	return append(append(b, target...), targetInfo...)
+}
+
+// checkAuthenticate verifies the NTLMv2 response like a server would.
+func (f *fakeWinRM) checkAuthenticate(msg []byte) bool {
+	field := func(i int) []byte {
+		l, off := binary.LittleEndian.Uint16(msg[12+8*i:]), binary.LittleEndian.Uint32(msg[16+8*i:])
+		return msg[off : off+uint32(l)]
+	}
+	decode := func(b []byte) string {
+		u := make([]uint16, len(b)/2)
+		for i := range u {
+			u[i] = binary.LittleEndian.Uint16(b[2*i:])
+		}
+		return string(utf16.Decode(u))
+	}
+	hmacMD5 := func(key []byte, data ...[]byte) []byte {
+		m := hmac.New(md5.New, key)
+		for _, d := range data {
+			m.Write(d)
+		}
+		return m.Sum(nil)
+	}
+	nt, domain, user := field(1), decode(field(2)), decode(field(3))
+	if domain != "CORP" || user != "admin" || len(nt) < 16 {
+		return false
+	}
+	h := md4.New()
+	h.Write(utf16le(f.password))
+	hash := hmacMD5(h.Sum(nil), utf16le(strings.ToUpper(user)+domain))
+	return bytes.Equal(hmacMD5(hash, fakeChallenge, nt[16:]), nt[:16])
+}
+
+func newFakeWinRMConfig(t *testing.T, f *fakeWinRM, password string) map[string]string {
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	return map[string]string{
+		"transport":   "winrm",
+		"pssession":   host,
+		"winrmport":   port,
+		"winrmscheme": "http",
+		"psusername":  `CORP\admin`,
+		"pspassword":  password,
+	}
+}
+
+func TestWinRMShell(t *testing.T) {
+	f := &fakeWinRM{
+		password: "s3cret",
+		stdout:   []string{`[{"ZoneName": "example.com"},`, ` {"ZoneName": "example.net"}]`},
+		stderr:   `#< CLIXML` + "\r\n" + `<Objs Version="1.1.0.1" xmlns="http://schemas.microsoft.com/powershell/2004/04"><Obj S="progress" RefId="0"><TN RefId="0"><T>System.Management.Automation.PSCustomObject</T></TN></Obj><S S="Error">WARNING: zone is paused_x000D__x000A_</S></Objs>`,
+	}
+	psh, err := newPowerShell(newFakeWinRMConfig(t, f, "s3cret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = psh.GetDNSServerZoneAll("")
+	if err == nil || !strings.Contains(err.Error(), `"WARNING: zone is paused\r\n"`) {
+		t.Errorf("GetDNSServerZoneAll() error = %v, want the error of stderr", err)
+	}
+	f.stderr = ""
+	zones, err := psh.GetDNSServerZoneAll("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"example.com", "example.net"}; !reflect.DeepEqual(zones, want) {
+		t.Errorf("GetDNSServerZoneAll() = %v, want %v", zones, want)
+	}
+	if got := f.commands[1]; !strings.HasSuffix(got, " ; Get-DnsServerZone | ConvertTo-Json") {
+		t.Errorf("ran %q", got)
+	}
+
+	psh.Exit()
+	want := []string{"Create", "Command", "Receive", "Receive", "Receive", "Signal", "Command", "Receive", "Receive", "Receive", "Signal", "Delete"}
+	if !reflect.DeepEqual(f.actions, want) {
+		t.Errorf("got actions %v, want %v", f.actions, want)
+	}
+}
+
+func TestWinRMShellBadPassword(t *testing.T) {
+	f := &fakeWinRM{password: "s3cret"}
+	if _, err := newPowerShell(newFakeWinRMConfig(t, f, "wrong")); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("newPowerShell() error = %v, want an authentication error", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/registrarmeta"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	nc "github.com/billputer/go-namecheap"
 	"golang.org/x/net/publicsuffix"
@@ -21,9 +22,10 @@ var NamecheapDefaultNs = []string{"dns1.registrar-servers.com", "dns2.registrar-
 
 // namecheapProvider is the handle for this provider.
 type namecheapProvider struct {
-	APIKEY  string
-	APIUser string
-	client  *nc.Client
+	APIKEY          string
+	APIUser         string
+	WhoisguardEmail string // The address that WhoisGuard forwards mail to
+	client          *nc.Client
 }
 
 var features = providers.DocumentationNotes{
@@ -62,6 +64,7 @@ func newReg(conf map[string]string) (providers.Registrar, error) {
 func newProvider(m map[string]string, metadata json.RawMessage) (*namecheapProvider, error) {
 	api := &namecheapProvider{}
 	api.APIUser, api.APIKEY = m["apiuser"], m["apikey"]
+	api.WhoisguardEmail = m["whoisguard_email"]
 	if api.APIKEY == "" || api.APIUser == "" {
 		return nil, fmt.Errorf("missing Namecheap apikey and apiuser")
 	}
@@ -222,7 +225,8 @@ func (n *namecheapProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 		if len(desc) > 0 {
 			corrections = append(corrections,
 				&models.Correction{
-					Msg: msg,
+					Msg:  msg,
+					Kind: diff.Kind(create, delete, modify),
 					F: func() error {
 						return n.generateRecords(dc)
 					},
@@ -318,7 +322,8 @@ func (n *namecheapProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]
 		sld, tld := parts[0], parts[1]
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Change Nameservers from '%s' to '%s'", found, desired),
+				Msg:  fmt.Sprintf("Change Nameservers from '%s' to '%s'", found, desired),
+				Kind: models.CorrectionModify,
 				F: func() (err error) {
 					doWithRetry(func() error {
 						_, err = n.client.DomainDNSSetCustom(sld, tld, desired)
@@ -330,3 +335,53 @@ func (n *namecheapProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]
 	}
 	return nil, nil
 }
+
+// GetDomainMetaCorrections returns the corrections of the WHOIS privacy
+// (WhoisGuard) setting. The Namecheap API can't change auto-renew.
+func (n *namecheapProvider) GetDomainMetaCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	desired, err := registrarmeta.Desired(dc)
+	if err != nil || len(desired) == 0 {
+		return nil, err
+	}
+	var info *nc.DomainInfo
+	doWithRetry(func() error {
+		info, err = n.client.DomainGetInfo(dc.Name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]bool{registrarmeta.WhoisPrivacy: info.Whoisguard.Enabled}
+	return registrarmeta.Corrections(dc, current, func(key string, on bool) (err error) {
+		if !on {
+			doWithRetry(func() error {
+				err = n.client.WhoisguardDisable(info.Whoisguard.ID)
+				return err
+			})
+			return
+		}
+		if n.WhoisguardEmail == "" {
+			return fmt.Errorf("set whoisguard_email in creds.json to turn WHOIS privacy on")
+		}
+		doWithRetry(func() error {
+			err = n.client.WhoisguardEnable(info.Whoisguard.ID, n.WhoisguardEmail)
+			return err
+		})
+		return
+	})
+}
+
+// GetDomainExpiry returns the expiration date of the domain.
+func (n *namecheapProvider) GetDomainExpiry(domain string) (time.Time, error) {
+	var info *nc.DomainInfo
+	var err error
+	doWithRetry(func() error {
+		info, err = n.client.DomainGetInfo(domain)
+		return err
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	// The API writes the dates as MM/DD/YYYY.
+	return time.Parse("01/02/2006", info.Expires)
+}
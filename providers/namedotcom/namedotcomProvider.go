@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/StackExchange/dnscontrol/v3/pkg/aliastypes"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	"github.com/namedotcom/go/namecom"
 )
@@ -66,5 +67,5 @@ func init() {
 		Initializer:   newDsp,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("NAMEDOTCOM", fns, features)
+	providers.RegisterDomainServiceProviderType("NAMEDOTCOM", fns, features, aliastypes.ANAME)
 }
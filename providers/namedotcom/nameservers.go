@@ -62,8 +62,9 @@ func (n *namedotcomProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([
 	if foundNameservers != expectedNameservers {
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
-				F:   n.updateNameservers(expected, dc.Name),
+				Msg:  fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+				Kind: models.CorrectionModify,
+				F:    n.updateNameservers(expected, dc.Name),
 			},
 		}, nil
 	}
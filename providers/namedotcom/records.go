@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/aliastypes"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
 	"github.com/namedotcom/go/namecom"
@@ -43,11 +44,7 @@ func (n *namedotcomProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 		return nil, err
 	}
 
-	for _, rec := range dc.Records {
-		if rec.Type == "ALIAS" {
-			rec.Type = "ANAME"
-		}
-	}
+	aliastypes.ANAME.Apply(dc.Records)
 
 	checkNSModifications(dc)
 
@@ -69,18 +66,18 @@ func (n *namedotcomProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 
 	for _, d := range del {
 		rec := d.Existing.Original.(*namecom.Record)
-		c := &models.Correction{Msg: d.String(), F: func() error { return n.deleteRecord(rec.ID, dc.Name) }}
+		c := &models.Correction{Msg: d.String(), Kind: d.Kind(), F: func() error { return n.deleteRecord(rec.ID, dc.Name) }}
 		corrections = append(corrections, c)
 	}
 	for _, cre := range create {
 		rec := cre.Desired
-		c := &models.Correction{Msg: cre.String(), F: func() error { return n.createRecord(rec, dc.Name) }}
+		c := &models.Correction{Msg: cre.String(), Kind: cre.Kind(), F: func() error { return n.createRecord(rec, dc.Name) }}
 		corrections = append(corrections, c)
 	}
 	for _, chng := range mod {
 		old := chng.Existing.Original.(*namecom.Record)
 		new := chng.Desired
-		c := &models.Correction{Msg: chng.String(), F: func() error {
+		c := &models.Correction{Msg: chng.String(), Kind: chng.Kind(), F: func() error {
 			err := n.deleteRecord(old.ID, dc.Name)
 			if err != nil {
 				return err
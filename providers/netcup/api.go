@@ -17,11 +17,29 @@ type netcupProvider struct {
 	nameserversNames []string
 	credentials      struct {
 		apikey         string
+		password       string
 		customernumber string
 		sessionID      string
 	}
 }
 
+// Status codes returned by the netcup API.
+const (
+	statusSuccess        = 2000
+	statusSessionInvalid = 4001 // Session id is malformed or has expired.
+	statusNoRecords      = 5029 // infoDnsRecords on an empty zone.
+)
+
+// sessionParam is implemented by the request parameters that carry a
+// session ID, so that get() can refresh it after logging in again.
+type sessionParam interface {
+	setSessionID(id string)
+}
+
+func (p *paramGetRecords) setSessionID(id string)    { p.SessionID = id }
+func (p *paramUpdateRecords) setSessionID(id string) { p.SessionID = id }
+func (p *paramLogout) setSessionID(id string)        { p.SessionID = id }
+
 func (api *netcupProvider) createRecord(domain string, rec *record) error {
 	rec.Delete = false
 	data := paramUpdateRecords{
@@ -33,7 +51,7 @@ func (api *netcupProvider) createRecord(domain string, rec *record) error {
 			*rec,
 		}},
 	}
-	_, err := api.get("updateDnsRecords", data)
+	_, err := api.get("updateDnsRecords", &data)
 	if err != nil {
 		return fmt.Errorf("error while trying to create a record: %s", err)
 	}
@@ -51,7 +69,7 @@ func (api *netcupProvider) deleteRecord(domain string, rec *record) error {
 			*rec,
 		}},
 	}
-	_, err := api.get("updateDnsRecords", data)
+	_, err := api.get("updateDnsRecords", &data)
 	if err != nil {
 		return fmt.Errorf("error while trying to delete a record: %s", err)
 	}
@@ -69,7 +87,7 @@ func (api *netcupProvider) modifyRecord(domain string, rec *record) error {
 			*rec,
 		}},
 	}
-	_, err := api.get("updateDnsRecords", data)
+	_, err := api.get("updateDnsRecords", &data)
 	if err != nil {
 		return fmt.Errorf("error while trying to modify a record: %s", err)
 	}
@@ -83,13 +101,15 @@ func (api *netcupProvider) getRecords(domain string) ([]record, error) {
 		CustomerNumber: api.credentials.customernumber,
 		DomainName:     domain,
 	}
-	rawJSON, err := api.get("infoDnsRecords", data)
+	rawJSON, err := api.get("infoDnsRecords", &data)
 	if err != nil {
-		return nil, fmt.Errorf("failed while trying to login (netcup): %s", err)
+		return nil, fmt.Errorf("failed while trying to get records (netcup): %s", err)
 	}
 
 	resp := &records{}
-	json.Unmarshal(rawJSON, &resp)
+	if err := json.Unmarshal(rawJSON, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse records (netcup): %s", err)
+	}
 	return resp.Records, nil
 }
 
@@ -105,8 +125,11 @@ func (api *netcupProvider) login(apikey, password, customernumber string) error
 	}
 
 	resp := &responseLogin{}
-	json.Unmarshal(rawJSON, &resp)
+	if err := json.Unmarshal(rawJSON, &resp); err != nil {
+		return fmt.Errorf("failed to parse login response (netcup): %s", err)
+	}
 	api.credentials.apikey = apikey
+	api.credentials.password = password
 	api.credentials.customernumber = customernumber
 	api.credentials.sessionID = resp.SessionID
 	return nil
@@ -118,15 +141,49 @@ func (api *netcupProvider) logout() error {
 		SessionID:      api.credentials.sessionID,
 		CustomerNumber: api.credentials.customernumber,
 	}
-	_, err := api.get("logout", data)
+	_, err := api.get("logout", &data)
 	if err != nil {
 		return fmt.Errorf("failed to logout from netcup: %s", err)
 	}
-	api.credentials.apikey, api.credentials.sessionID, api.credentials.customernumber = "", "", ""
+	api.credentials.apikey, api.credentials.password, api.credentials.sessionID, api.credentials.customernumber = "", "", "", ""
 	return nil
 }
 
+// get calls action with params. If the session has expired (netcup
+// sessions time out after 15 minutes of inactivity) it logs in again
+// and retries once.
 func (api *netcupProvider) get(action string, params interface{}) (json.RawMessage, error) {
+	respData, err := api.call(action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if sp, ok := params.(sessionParam); ok && respData.StatusCode == statusSessionInvalid {
+		if err := api.login(api.credentials.apikey, api.credentials.password, api.credentials.customernumber); err != nil {
+			return nil, err
+		}
+		sp.setSessionID(api.credentials.sessionID)
+		respData, err = api.call(action, params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Yeah, netcup implemented an empty recordset as an error - don't ask.
+	if action == "infoDnsRecords" && respData.StatusCode == statusNoRecords {
+		emptyRecords, _ := json.Marshal(records{})
+		return emptyRecords, nil
+	}
+
+	// Check for any errors and log them
+	if respData.StatusCode != statusSuccess {
+		return nil, fmt.Errorf("netcup API error: %s: %s (%d)", action, respData.LongMessage, respData.StatusCode)
+	}
+
+	return respData.Data, nil
+}
+
+func (api *netcupProvider) call(action string, params interface{}) (*response, error) {
 	reqParam := request{
 		Action: action,
 		Param:  params,
@@ -139,6 +196,7 @@ func (api *netcupProvider) get(action string, params interface{}) (json.RawMessa
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
 	bodyString, _ := io.ReadAll(resp.Body)
 
@@ -148,16 +206,5 @@ func (api *netcupProvider) get(action string, params interface{}) (json.RawMessa
 		return nil, err
 	}
 
-	// Yeah, netcup implemented an empty recordset as an error - don't ask.
-	if action == "infoDnsRecords" && respData.StatusCode == 5029 {
-		emptyRecords, _ := json.Marshal(records{})
-		return emptyRecords, nil
-	}
-
-	// Check for any errors and log them
-	if respData.StatusCode != 2000 && (action == "") {
-		return nil, fmt.Errorf("netcup API error: %v\n%v", reqParam, respData)
-	}
-
-	return respData.Data, nil
+	return respData, nil
 }
@@ -114,7 +114,8 @@ func (api *netcupProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 		for _, m := range del {
 			req := m.Existing.Original.(*record)
 			corr := &models.Correction{
-				Msg: fmt.Sprintf("%s, Netcup ID: %s", m.String(), req.ID),
+				Msg:  fmt.Sprintf("%s, Netcup ID: %s", m.String(), req.ID),
+				Kind: m.Kind(),
 				F: func() error {
 					return api.deleteRecord(domain, req)
 				},
@@ -125,7 +126,8 @@ func (api *netcupProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 		for _, m := range create {
 			req := fromRecordConfig(m.Desired)
 			corr := &models.Correction{
-				Msg: m.String(),
+				Msg:  m.String(),
+				Kind: m.Kind(),
 				F: func() error {
 					return api.createRecord(domain, req)
 				},
@@ -137,7 +139,8 @@ func (api *netcupProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 			req := fromRecordConfig(m.Desired)
 			req.ID = id
 			corr := &models.Correction{
-				Msg: fmt.Sprintf("%s, Netcup ID: %s: ", m.String(), id),
+				Msg:  fmt.Sprintf("%s, Netcup ID: %s: ", m.String(), id),
+				Kind: m.Kind(),
 				F: func() error {
 					return api.modifyRecord(domain, req)
 				},
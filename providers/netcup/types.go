@@ -99,8 +99,20 @@ func toRecordConfig(domain string, r *record) *models.RecordConfig {
 	switch rtype := r.Type; rtype { // #rtype_variations
 	case "TXT":
 		_ = rc.SetTargetTXT(r.Destination)
-	case "NS", "ALIAS", "CNAME", "MX":
+	case "NS", "ALIAS", "CNAME":
 		_ = rc.SetTarget(dnsutil.AddOrigin(addTailingDot(r.Destination), domain))
+	case "MX":
+		// The priority normally comes in its own field, but records
+		// created through the CCP web interface sometimes have it
+		// combined into the destination ("10 mail.example.com").
+		destination := r.Destination
+		if parts := strings.Fields(destination); len(parts) == 2 {
+			if p, err := strconv.ParseUint(parts[0], 10, 16); err == nil {
+				rc.MxPreference = uint16(p)
+				destination = parts[1]
+			}
+		}
+		_ = rc.SetTarget(dnsutil.AddOrigin(addTailingDot(destination), domain))
 	case "SRV":
 		parts := strings.Split(r.Destination, " ")
 		priority, _ := strconv.ParseUint(parts[0], 10, 16)
@@ -116,6 +128,8 @@ func toRecordConfig(domain string, r *record) *models.RecordConfig {
 		rc.CaaFlag = uint8(caaFlag)
 		rc.CaaTag = parts[1]
 		_ = rc.SetTarget(strings.Trim(parts[2], "\""))
+	case "SSHFP", "TLSA":
+		_ = rc.PopulateFromString(rtype, r.Destination, domain)
 	default:
 		_ = rc.SetTarget(r.Destination)
 	}
@@ -146,11 +160,11 @@ func fromRecordConfig(in *models.RecordConfig) *record {
 	case "SRV":
 		rc.Destination = strconv.Itoa(int(in.SrvPriority)) + " " + strconv.Itoa(int(in.SrvWeight)) + " " + strconv.Itoa(int(in.SrvPort)) + " " + in.GetTargetField()
 	case "SSHFP":
-		rc.Destination = strconv.Itoa(int(in.SshfpAlgorithm)) + " " + strconv.Itoa(int(in.SshfpFingerprint))
+		rc.Destination = strconv.Itoa(int(in.SshfpAlgorithm)) + " " + strconv.Itoa(int(in.SshfpFingerprint)) + " " + in.GetTargetField()
 	case "TLSA":
-		rc.Destination = strconv.Itoa(int(in.TlsaUsage)) + " " + strconv.Itoa(int(in.TlsaSelector)) + " " + strconv.Itoa(int(in.TlsaMatchingType))
+		rc.Destination = strconv.Itoa(int(in.TlsaUsage)) + " " + strconv.Itoa(int(in.TlsaSelector)) + " " + strconv.Itoa(int(in.TlsaMatchingType)) + " " + in.GetTargetField()
 	default:
-		msg := fmt.Sprintf("ClouDNS.toReq rtype %v unimplemented", rc.Type)
+		msg := fmt.Sprintf("netcup.fromRecordConfig rtype %v unimplemented", rc.Type)
 		panic(msg)
 		// We panic so that we quickly find any switch statements
 	}
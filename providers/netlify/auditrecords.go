@@ -13,5 +13,11 @@ func AuditRecords(records []*models.RecordConfig) []error {
 
 	a.Add("MX", rejectif.MxNull) // Last verified 2022-11-20
 
+	a.Add("NETLIFY", rejectif.NetlifyTargetEmpty)
+	a.Add("NETLIFY", rejectif.NetlifyTargetHasDot)
+
+	a.Add("NETLIFYv6", rejectif.NetlifyTargetEmpty)
+	a.Add("NETLIFYv6", rejectif.NetlifyTargetHasDot)
+
 	return a.Audit(records)
 }
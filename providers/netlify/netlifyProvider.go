@@ -40,7 +40,7 @@ func init() {
 		Initializer:   newNetlify,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("NETLIFY", fns, features)
+	providers.RegisterDomainServiceProviderType("NETLIFY", fns, features, txtutil.SplitLong)
 	providers.RegisterCustomRecordType("NETLIFY", "NETLIFY", "")
 	providers.RegisterCustomRecordType("NETLIFYv6", "NETLIFY", "")
 }
@@ -116,8 +116,12 @@ func (n *netlifyProvider) GetZoneRecords(domain string) (models.Records, error)
 		}
 
 		switch rtype := r.Type; rtype {
-		case "NETLIFY", "NETLIFYv6": // transparently ignore
-			continue
+		case "NETLIFY", "NETLIFYv6":
+			// Netlify points these at the site's load balancers. They
+			// are only managed if the configuration includes NETLIFY()
+			// or NETLIFYv6() records; see ignoreNetlifyRecords.
+			rec.Type = rtype
+			err = rec.SetTarget(r.Value)
 		case "MX":
 			err = rec.SetTargetMX(uint16(r.Priority), r.Value)
 		case "SRV":
@@ -176,6 +180,33 @@ func removeOtherApexNS(dc *models.DomainConfig) {
 	dc.Records = newList
 }
 
+// isNetlifyRecord returns true if rc is one of the synthetic records
+// Netlify adds to zones that serve a Netlify site.
+func isNetlifyRecord(rc *models.RecordConfig) bool {
+	return rc.Type == "NETLIFY" || rc.Type == "NETLIFYv6"
+}
+
+// ignoreNetlifyRecords removes the NETLIFY and NETLIFYv6 records from
+// existing unless the desired configuration declares at least one of
+// them. This makes managing them opt-in: zones that don't mention them
+// keep whatever Netlify created.
+func ignoreNetlifyRecords(existing models.Records, dc *models.DomainConfig) models.Records {
+	for _, rec := range dc.Records {
+		if isNetlifyRecord(rec) {
+			return existing
+		}
+	}
+
+	newList := make(models.Records, 0, len(existing))
+	for _, rec := range existing {
+		if isNetlifyRecord(rec) {
+			continue
+		}
+		newList = append(newList, rec)
+	}
+	return newList
+}
+
 func (n *netlifyProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
 
 	err := dc.Punycode()
@@ -190,8 +221,9 @@ func (n *netlifyProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 
 	// Normalize
 	models.PostProcessRecords(records)
-	txtutil.SplitSingleLongTxt(dc.Records) // Auto split long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Auto split long TXT records
 	removeOtherApexNS(dc)
+	records = ignoreNetlifyRecords(records, dc)
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 || true { // Remove "|| true" when diff2 version arrives
@@ -211,7 +243,8 @@ func (n *netlifyProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		for _, m := range del {
 			id := m.Existing.Original.(*dnsRecord).ID
 			corr := &models.Correction{
-				Msg: m.String(),
+				Msg:  m.String(),
+				Kind: m.Kind(),
 				F: func() error {
 					return n.deleteDNSRecord(zone.ID, id)
 				},
@@ -222,7 +255,8 @@ func (n *netlifyProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		for _, m := range create {
 			req := toReq(m.Desired)
 			corr := &models.Correction{
-				Msg: m.String(),
+				Msg:  m.String(),
+				Kind: m.Kind(),
 				F: func() error {
 					_, err := n.createDNSRecord(zone.ID, req)
 					return err
@@ -235,7 +269,8 @@ func (n *netlifyProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 			id := m.Existing.Original.(*dnsRecord).ID
 			req := toReq(m.Desired)
 			corr := &models.Correction{
-				Msg: m.String(),
+				Msg:  m.String(),
+				Kind: m.Kind(),
 				F: func() error {
 					if err := n.deleteDNSRecord(zone.ID, id); err != nil {
 						return err
@@ -1,6 +1,8 @@
 package ns1
 
 import (
+	"fmt"
+
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/rejectif"
 )
@@ -13,5 +15,11 @@ func AuditRecords(records []*models.RecordConfig) []error {
 
 	a.Add("TXT", rejectif.TxtHasMultipleSegments)
 
-	return a.Audit(records)
+	errs := a.Audit(records)
+	for _, rc := range records {
+		if err := checkMetadata(rc); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", rc.Type, rc.GetLabelFQDN(), err))
+		}
+	}
+	return errs
 }
@@ -0,0 +1,157 @@
+package ns1
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/filter"
+)
+
+// NS1 steers traffic with a filter chain on each record, which selects
+// among its answers based on the metadata of the answers (up, weight,
+// georegion, ...). Both are given in dnsconfig.js as JSON, in the
+// format of the NS1 API:
+//
+//	A("www", "1.2.3.4", {
+//	    ns1_filters: '[{"filter": "up"}, {"filter": "select_first_n", "config": {"N": 1}}]',
+//	    ns1_meta: '{"up": true, "weight": 10}',
+//	})
+//
+// The filter chain is the same for all the answers of a record, so it
+// only needs to be given once per label and type.
+const (
+	metaFilters = "ns1_filters"
+	metaAnswer  = "ns1_meta"
+)
+
+// hasAnswerMetadata reports whether records of rtype may have a filter
+// chain and answer metadata.
+func hasAnswerMetadata(rtype string) bool {
+	return rtype == "A" || rtype == "AAAA" || rtype == "CNAME"
+}
+
+// canonicalFilters returns the filter chain s as the API returns it.
+func canonicalFilters(s string) (string, error) {
+	var filters []*filter.Filter
+	if err := json.Unmarshal([]byte(s), &filters); err != nil {
+		return "", fmt.Errorf("%s is not a JSON list of filters: %w", metaFilters, err)
+	}
+	if len(filters) == 0 {
+		return "", nil
+	}
+	for _, f := range filters {
+		if f == nil || f.Type == "" {
+			return "", fmt.Errorf("%s: each filter needs a \"filter\" name", metaFilters)
+		}
+		if f.Config == nil {
+			f.Config = filter.Config{}
+		}
+	}
+	b, err := json.Marshal(filters)
+	return string(b), err
+}
+
+// canonicalAnswerMeta returns the answer metadata s as the API returns it.
+func canonicalAnswerMeta(s string) (string, error) {
+	meta := &data.Meta{}
+	if err := json.Unmarshal([]byte(s), meta); err != nil {
+		return "", fmt.Errorf("%s is not a JSON object of answer metadata: %w", metaAnswer, err)
+	}
+	b, err := json.Marshal(meta)
+	if err != nil || string(b) == "{}" {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// checkMetadata returns an error if the NS1 metadata of rc is invalid.
+func checkMetadata(rc *models.RecordConfig) error {
+	for _, key := range []string{metaFilters, metaAnswer} {
+		if rc.Metadata[key] == "" {
+			continue
+		}
+		if !hasAnswerMetadata(rc.Type) {
+			return fmt.Errorf("%s is only supported on A, AAAA and CNAME records", key)
+		}
+	}
+	if _, err := canonicalFilters(orDefault(rc.Metadata[metaFilters], "[]")); err != nil {
+		return err
+	}
+	_, err := canonicalAnswerMeta(orDefault(rc.Metadata[metaAnswer], "{}"))
+	return err
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// canonicalizeMetadata rewrites the NS1 metadata of records as the API
+// returns it, so that only actual differences are reported. The filter
+// chain of a record set is copied to all of its records.
+func canonicalizeMetadata(records models.Records) error {
+	filters := map[models.RecordKey]string{}
+	for _, rc := range records {
+		if !hasAnswerMetadata(rc.Type) {
+			continue
+		}
+		if err := checkMetadata(rc); err != nil {
+			return fmt.Errorf("%s %s: %w", rc.Type, rc.GetLabelFQDN(), err)
+		}
+		f, _ := canonicalFilters(orDefault(rc.Metadata[metaFilters], "[]"))
+		if f != "" {
+			if other, ok := filters[rc.Key()]; ok && other != f {
+				return fmt.Errorf("%s %s: the records have different %s", rc.Type, rc.GetLabelFQDN(), metaFilters)
+			}
+			filters[rc.Key()] = f
+		}
+		if rc.Metadata[metaAnswer] != "" {
+			rc.Metadata[metaAnswer], _ = canonicalAnswerMeta(rc.Metadata[metaAnswer])
+		}
+	}
+	for _, rc := range records {
+		if f, ok := filters[rc.Key()]; ok {
+			if rc.Metadata == nil {
+				rc.Metadata = map[string]string{}
+			}
+			rc.Metadata[metaFilters] = f
+		}
+	}
+	return nil
+}
+
+// getNS1Metadata returns the NS1 metadata of r to compare it with
+// diff.New().
+func getNS1Metadata(r *models.RecordConfig) map[string]string {
+	if !hasAnswerMetadata(r.Type) {
+		return nil
+	}
+	m := map[string]string{}
+	for _, key := range []string{metaFilters, metaAnswer} {
+		if r.Metadata[key] != "" {
+			m[key] = r.Metadata[key]
+		}
+	}
+	return m
+}
+
+// ns1Comparable is getNS1Metadata for the diff2 Comparer of NS1.
+func ns1Comparable(r *models.RecordConfig) string {
+	m := getNS1Metadata(r)
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		parts = append(parts, k+"="+m[k])
+	}
+	return strings.Join(parts, " ")
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	"gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/data"
 	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
 	"gopkg.in/ns1/ns1-go.v2/rest/model/filter"
 )
@@ -37,6 +38,7 @@ func init() {
 	}
 	providers.RegisterDomainServiceProviderType("NS1", fns, providers.CanUseSRV, docNotes)
 	providers.RegisterCustomRecordType("NS1_URLFWD", "NS1", "URLFWD")
+	diff2.RegisterComparer("NS1", &diff2.Comparer{Comparable: ns1Comparable})
 }
 
 type nsone struct {
@@ -81,6 +83,26 @@ func (n *nsone) GetZoneRecords(domain string) (models.Records, error) {
 
 	found := models.Records{}
 	for _, r := range z.Records {
+		var tier int64
+		if r.Tier != "" {
+			if tier, err = r.Tier.Int64(); err != nil {
+				return nil, fmt.Errorf("record %s %s: invalid tier %q: %w", r.Domain, r.Type, r.Tier, err)
+			}
+		}
+		if tier > 1 && hasAnswerMetadata(r.Type) {
+			// The zone only lists the answers. Get the filter chain
+			// and the metadata of the answers too.
+			rec, _, err := n.Records.Get(domain, r.Domain, r.Type)
+			if err != nil {
+				return nil, err
+			}
+			zrs, err := convertRecord(rec, domain)
+			if err != nil {
+				return nil, err
+			}
+			found = append(found, zrs...)
+			continue
+		}
 		zrs, err := convert(r, domain)
 		if err != nil {
 			return nil, err
@@ -125,14 +147,16 @@ func (n *nsone) getDomainCorrectionsDNSSEC(domain, toggleDNSSEC string) *models.
 	if toggleDNSSEC == "on" && !status {
 		// disabled, but prefer it on, let's enable DNSSEC
 		return &models.Correction{
-			Msg: "ENABLE DNSSEC",
-			F:   func() error { return n.configureDNSSEC(domain, true) },
+			Msg:  "ENABLE DNSSEC",
+			Kind: models.CorrectionCreate,
+			F:    func() error { return n.configureDNSSEC(domain, true) },
 		}
 	} else if toggleDNSSEC == "off" && status {
 		// enabled, but prefer it off, let's disable DNSSEC
 		return &models.Correction{
-			Msg: "DISABLE DNSSEC",
-			F:   func() error { return n.configureDNSSEC(domain, false) },
+			Msg:  "DISABLE DNSSEC",
+			Kind: models.CorrectionDestroy,
+			F:    func() error { return n.configureDNSSEC(domain, false) },
 		}
 	}
 	return nil
@@ -153,6 +177,9 @@ func (n *nsone) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correct
 
 	//  Normalize
 	models.PostProcessRecords(existingRecords)
+	if err := canonicalizeMetadata(dc.Records); err != nil {
+		return nil, err
+	}
 
 	// add DNSSEC-related corrections
 	if dnssecCorrections := n.getDomainCorrectionsDNSSEC(domain, dc.AutoDNSSEC); dnssecCorrections != nil {
@@ -164,7 +191,7 @@ func (n *nsone) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correct
 		existingGrouped := existingRecords.GroupedByKey()
 		desiredGrouped := dc.Records.GroupedByKey()
 
-		differ := diff.New(dc)
+		differ := diff.New(dc, getNS1Metadata)
 		changedGroups, err := differ.ChangedGroups(existingRecords)
 		if err != nil {
 			return nil, err
@@ -182,27 +209,30 @@ func (n *nsone) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correct
 			if wanted && !current {
 				// pure addition
 				corrections = append(corrections, &models.Correction{
-					Msg: desc,
-					F:   func() error { return n.add(recs, dc.Name) },
+					Msg:  desc,
+					Kind: models.CorrectionCreate,
+					F:    func() error { return n.add(recs, dc.Name) },
 				})
 			} else if current && !wanted {
 				// pure deletion
 				corrections = append(corrections, &models.Correction{
-					Msg: desc,
-					F:   func() error { return n.remove(key, dc.Name) },
+					Msg:  desc,
+					Kind: models.CorrectionDestroy,
+					F:    func() error { return n.remove(key, dc.Name) },
 				})
 			} else {
 				// modification
 				corrections = append(corrections, &models.Correction{
-					Msg: desc,
-					F:   func() error { return n.modify(recs, dc.Name) },
+					Msg:  desc,
+					Kind: diff.ReplaceKind(existingGrouped[k], recs),
+					F:    func() error { return n.modify(recs, dc.Name) },
 				})
 			}
 		}
 		return corrections, nil
 	}
 
-	changes, err := diff2.ByRecordSet(existingRecords, dc, nil)
+	changes, err := diff2.GetComparer("NS1").ByRecordSet(existingRecords, dc)
 	if err != nil {
 		return nil, err
 	}
@@ -214,21 +244,24 @@ func (n *nsone) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correct
 
 		if change.Type == diff2.CREATE {
 			corrections = append(corrections, &models.Correction{
-				Msg: desc,
-				F:   func() error { return n.add(recs, dc.Name) },
+				Msg:  desc,
+				Kind: change.Kind(),
+				F:    func() error { return n.add(recs, dc.Name) },
 			})
 		}
 		if change.Type == diff2.CHANGE {
 			corrections = append(corrections, &models.Correction{
-				Msg: desc,
-				F:   func() error { return n.modify(recs, dc.Name) },
+				Msg:  desc,
+				Kind: change.Kind(),
+				F:    func() error { return n.modify(recs, dc.Name) },
 			})
 
 		}
 		if change.Type == diff2.DELETE {
 			corrections = append(corrections, &models.Correction{
-				Msg: desc,
-				F:   func() error { return n.remove(key, dc.Name) },
+				Msg:  desc,
+				Kind: change.Kind(),
+				F:    func() error { return n.remove(key, dc.Name) },
 			})
 		}
 	}
@@ -279,6 +312,16 @@ func buildRecord(recs models.Records, domain string, id string) *dns.Record {
 		Filters: []*filter.Filter{}, // Work through a bug in the NS1 API library that causes 400 Input validation failed (Value None for field '<obj>.filters' is not of type array)
 	}
 	for _, r := range recs {
+		if r.Metadata[metaFilters] != "" && len(rec.Filters) == 0 {
+			// Validated by AuditRecords.
+			_ = json.Unmarshal([]byte(r.Metadata[metaFilters]), &rec.Filters)
+		}
+		if r.Metadata[metaAnswer] != "" {
+			meta := &data.Meta{}
+			_ = json.Unmarshal([]byte(r.Metadata[metaAnswer]), meta)
+			rec.AddAnswer(&dns.Answer{Rdata: strings.Split(r.GetTargetField(), " "), Meta: meta})
+			continue
+		}
 		if r.Type == "MX" {
 			rec.AddAnswer(&dns.Answer{Rdata: strings.Split(fmt.Sprintf("%d %v", r.MxPreference, r.GetTargetField()), " ")})
 		} else if r.Type == "TXT" {
@@ -352,3 +395,44 @@ func convert(zr *dns.ZoneRecord, domain string) ([]*models.RecordConfig, error)
 	}
 	return found, nil
 }
+
+// convertRecord converts a record with its filter chain and the
+// metadata of its answers, which are stored in the ns1_filters and
+// ns1_meta metadata.
+func convertRecord(r *dns.Record, domain string) ([]*models.RecordConfig, error) {
+	var filters string
+	if len(r.Filters) != 0 {
+		b, err := json.Marshal(r.Filters)
+		if err != nil {
+			return nil, err
+		}
+		filters = string(b)
+	}
+
+	found := []*models.RecordConfig{}
+	for _, ans := range r.Answers {
+		rec := &models.RecordConfig{
+			TTL:      uint32(r.TTL),
+			Original: r,
+			Metadata: map[string]string{},
+		}
+		rec.SetLabelFromFQDN(r.Domain, domain)
+		if err := rec.PopulateFromString(r.Type, strings.Join(ans.Rdata, " "), domain); err != nil {
+			return nil, fmt.Errorf("unparsable record received from ns1: %w", err)
+		}
+		if filters != "" {
+			rec.Metadata[metaFilters] = filters
+		}
+		if ans.Meta != nil {
+			b, err := json.Marshal(ans.Meta)
+			if err != nil {
+				return nil, err
+			}
+			if string(b) != "{}" {
+				rec.Metadata[metaAnswer] = string(b)
+			}
+		}
+		found = append(found, rec)
+	}
+	return found, nil
+}
@@ -58,8 +58,9 @@ func (c *opensrsProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*m
 	if actual != expected {
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Update nameservers %s -> %s", actual, expected),
-				F:   c.updateNameserversFunc(expectedSet, dc.Name),
+				Msg:  fmt.Sprintf("Update nameservers %s -> %s", actual, expected),
+				Kind: models.CorrectionModify,
+				F:    c.updateNameserversFunc(expectedSet, dc.Name),
 			},
 		}, nil
 	}
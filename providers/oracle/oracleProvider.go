@@ -37,7 +37,7 @@ func init() {
 		Initializer:   New,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("ORACLE", fns, features)
+	providers.RegisterDomainServiceProviderType("ORACLE", fns, features, txtutil.SplitLong)
 }
 
 type oracleProvider struct {
@@ -220,7 +220,7 @@ func (o *oracleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*model
 
 	//  Normalize
 	models.PostProcessRecords(existingRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	// Ensure we don't emit changes for attempted modification of built-in apex NSs
 	for _, rec := range dc.Records {
@@ -291,7 +291,8 @@ func (o *oracleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*model
 
 	if len(createRecords) > 0 || len(deleteRecords) > 0 {
 		return []*models.Correction{{
-			Msg: desc,
+			Msg:  desc,
+			Kind: diff.Kind(create, dels, modify),
 			F: func() error {
 				return o.patch(createRecords, deleteRecords, domain)
 			},
@@ -147,6 +147,9 @@ func (c *ovhProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.C
 	if len(corrections) > 0 {
 		corrections = append(corrections, &models.Correction{
 			Msg: "REFRESH zone " + dc.Name,
+			// The refresh publishes every change waiting at OVH, not
+			// only the ones above.
+			Kind: models.CorrectionDestroy,
 			F: func() error {
 				return c.refreshZone(dc.Name)
 			},
@@ -168,16 +171,18 @@ func (c *ovhProvider) getDiff1DomainCorrections(dc *models.DomainConfig, actual
 	for _, del := range delete {
 		rec := del.Existing.Original.(*Record)
 		corrections = append(corrections, &models.Correction{
-			Msg: del.String(),
-			F:   c.deleteRecordFunc(rec.ID, dc.Name),
+			Msg:  del.String(),
+			Kind: del.Kind(),
+			F:    c.deleteRecordFunc(rec.ID, dc.Name),
 		})
 	}
 
 	for _, cre := range create {
 		rec := cre.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: cre.String(),
-			F:   c.createRecordFunc(rec, dc.Name),
+			Msg:  cre.String(),
+			Kind: cre.Kind(),
+			F:    c.createRecordFunc(rec, dc.Name),
 		})
 	}
 
@@ -185,8 +190,9 @@ func (c *ovhProvider) getDiff1DomainCorrections(dc *models.DomainConfig, actual
 		oldR := mod.Existing.Original.(*Record)
 		newR := mod.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: mod.String(),
-			F:   c.updateRecordFunc(oldR, newR, dc.Name),
+			Msg:  mod.String(),
+			Kind: mod.Kind(),
+			F:    c.updateRecordFunc(oldR, newR, dc.Name),
 		})
 	}
 	return corrections, nil
@@ -203,19 +209,22 @@ func (c *ovhProvider) getDiff2DomainCorrections(dc *models.DomainConfig, actual
 		switch inst.Type {
 		case diff2.CHANGE:
 			corrections = append(corrections, &models.Correction{
-				Msg: inst.Msgs[0],
-				F:   c.updateRecordFunc(inst.Old[0].Original.(*Record), inst.New[0], dc.Name),
+				Msg:  inst.Msgs[0],
+				Kind: inst.Kind(),
+				F:    c.updateRecordFunc(inst.Old[0].Original.(*Record), inst.New[0], dc.Name),
 			})
 		case diff2.CREATE:
 			corrections = append(corrections, &models.Correction{
-				Msg: inst.Msgs[0],
-				F:   c.createRecordFunc(inst.New[0], dc.Name),
+				Msg:  inst.Msgs[0],
+				Kind: inst.Kind(),
+				F:    c.createRecordFunc(inst.New[0], dc.Name),
 			})
 		case diff2.DELETE:
 			rec := inst.Old[0].Original.(*Record)
 			corrections = append(corrections, &models.Correction{
-				Msg: inst.Msgs[0],
-				F:   c.deleteRecordFunc(rec.ID, dc.Name),
+				Msg:  inst.Msgs[0],
+				Kind: inst.Kind(),
+				F:    c.deleteRecordFunc(rec.ID, dc.Name),
 			})
 		}
 	}
@@ -275,7 +284,8 @@ func (c *ovhProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*model
 	if actual != expected {
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Change Nameservers from '%s' to '%s'", actual, expected),
+				Msg:  fmt.Sprintf("Change Nameservers from '%s' to '%s'", actual, expected),
+				Kind: models.CorrectionModify,
 				F: func() error {
 					err := c.updateNS(dc.Name, expectedNs)
 					if err != nil {
@@ -139,7 +139,8 @@ func (api *packetframeProvider) GetDomainCorrections(dc *models.DomainConfig) ([
 				return nil, err
 			}
 			corr := &models.Correction{
-				Msg: m.String(),
+				Msg:  m.String(),
+				Kind: m.Kind(),
 				F: func() error {
 					_, err := api.createRecord(req)
 					return err
@@ -155,7 +156,8 @@ func (api *packetframeProvider) GetDomainCorrections(dc *models.DomainConfig) ([
 			}
 
 			corr := &models.Correction{
-				Msg: m.String(),
+				Msg:  m.String(),
+				Kind: m.Kind(),
 				F: func() error {
 					err := api.deleteRecord(zone.ID, original.ID)
 					return err
@@ -173,7 +175,8 @@ func (api *packetframeProvider) GetDomainCorrections(dc *models.DomainConfig) ([
 			req, _ := toReq(zone.ID, dc, m.Desired)
 			req.ID = original.ID
 			corr := &models.Correction{
-				Msg: m.String(),
+				Msg:  m.String(),
+				Kind: m.Kind(),
 				F: func() error {
 					err := api.modifyRecord(req)
 					return err
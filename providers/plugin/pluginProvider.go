@@ -0,0 +1,184 @@
+// Package plugin implements the PLUGIN provider, which delegates to an
+// external program. It lets in-house DNS systems be managed by
+// dnscontrol without adding a provider to it. See protocol.go for the
+// contract between dnscontrol and the program.
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
+	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
+	"github.com/StackExchange/dnscontrol/v3/providers"
+)
+
+var features = providers.DocumentationNotes{
+	providers.CanGetZones:            providers.Can(),
+	providers.CanUseCAA:              providers.Can(),
+	providers.CanUseDS:               providers.Can(),
+	providers.CanUseNAPTR:            providers.Can(),
+	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseSRV:              providers.Can(),
+	providers.CanUseSSHFP:            providers.Can(),
+	providers.CanUseTLSA:             providers.Can(),
+	providers.DocCreateDomains:       providers.Can("If the plugin implements create_zone"),
+	providers.DocDualHost:            providers.Can(),
+	providers.DocOfficiallySupported: providers.Cannot(),
+}
+
+func init() {
+	fns := providers.DspFuncs{
+		Initializer:   newProvider,
+		RecordAuditor: AuditRecords,
+	}
+	providers.RegisterDomainServiceProviderType("PLUGIN", fns, features, txtutil.SplitLong)
+}
+
+// defaultTimeout is how long a request may take, unless "timeout" in
+// creds.json says otherwise.
+const defaultTimeout = time.Minute
+
+// pluginProvider is the handle for this provider.
+type pluginProvider struct {
+	command []string          // The program and its arguments
+	config  map[string]string // The creds.json settings, passed to the program
+	timeout time.Duration     // How long to wait for the program
+}
+
+func newProvider(settings map[string]string, _ json.RawMessage) (providers.DNSServiceProvider, error) {
+	command := strings.Fields(settings["command"])
+	if len(command) == 0 {
+		return nil, fmt.Errorf("missing PLUGIN command")
+	}
+	timeout := defaultTimeout
+	if s := settings["timeout"]; s != "" {
+		var err error
+		if timeout, err = time.ParseDuration(s); err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid PLUGIN timeout %q: want a duration such as \"30s\"", s)
+		}
+	}
+	config := map[string]string{}
+	for k, v := range settings {
+		// The keys that start with "_" are for dnscontrol itself
+		// (e.g. "_concurrency").
+		if k != "TYPE" && k != "command" && k != "timeout" && !strings.HasPrefix(k, "_") {
+			config[k] = v
+		}
+	}
+	return &pluginProvider{command: command, config: config, timeout: timeout}, nil
+}
+
+// ListZones lists the zones on this account.
+func (p *pluginProvider) ListZones() ([]string, error) {
+	resp, err := p.call(request{Method: methodListZones})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Zones, nil
+}
+
+// EnsureDomainExists creates the domain if it does not exist.
+func (p *pluginProvider) EnsureDomainExists(domain string) error {
+	zones, err := p.ListZones()
+	if err != nil {
+		return err
+	}
+	for _, z := range zones {
+		if z == domain {
+			return nil
+		}
+	}
+	_, err = p.call(request{Method: methodCreateZone, Zone: domain})
+	return err
+}
+
+// GetNameservers returns the nameservers for a domain.
+func (p *pluginProvider) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	resp, err := p.call(request{Method: methodGetNameservers, Zone: domain})
+	if err != nil {
+		return nil, err
+	}
+	return models.ToNameservers(resp.Nameservers)
+}
+
+// GetZoneRecords gets the records of a zone and returns them in RecordConfig format.
+func (p *pluginProvider) GetZoneRecords(domain string) (models.Records, error) {
+	resp, err := p.call(request{Method: methodGetRecords, Zone: domain})
+	if err != nil {
+		return nil, err
+	}
+	records := make(models.Records, 0, len(resp.Records))
+	for _, r := range resp.Records {
+		rc, err := fromRecord(r, domain)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rc)
+	}
+	return records, nil
+}
+
+// GetDomainCorrections returns the corrections for a domain.
+func (p *pluginProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	dc, err := dc.Copy()
+	if err != nil {
+		return nil, err
+	}
+	if err := dc.Punycode(); err != nil {
+		return nil, err
+	}
+
+	existingRecords, err := p.GetZoneRecords(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize
+	models.PostProcessRecords(existingRecords)
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
+
+	var create, del, modify diff.Changeset
+	if !diff2.EnableDiff2 {
+		differ := diff.New(dc)
+		_, create, del, modify, err = differ.IncrementalDiff(existingRecords)
+	} else {
+		differ := diff.NewCompat(dc)
+		_, create, del, modify, err = differ.IncrementalDiff(existingRecords)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Each change is a correction of its own, so that they can be
+	// reviewed (and counted) one by one.
+	var corrections []*models.Correction
+	add := func(m diff.Correlation, req request) {
+		req.Method, req.Zone = methodApply, dc.Name
+		corrections = append(corrections, &models.Correction{
+			Msg:  m.String(),
+			Kind: m.Kind(),
+			F: func() error {
+				_, err := p.call(req)
+				return err
+			},
+		})
+	}
+	for _, m := range del {
+		add(m, request{Delete: []record{m.Existing.Original.(record)}})
+	}
+	for _, m := range create {
+		add(m, request{Create: []record{toRecord(m.Desired)}})
+	}
+	for _, m := range modify {
+		add(m, request{Modify: []modification{{
+			Old: m.Existing.Original.(record),
+			New: toRecord(m.Desired),
+		}}})
+	}
+	return corrections, nil
+}
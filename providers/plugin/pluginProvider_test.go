@@ -0,0 +1,167 @@
+package plugin
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// TestHelperPlugin isn't a real test: it is the plugin run by the
+// other tests. It keeps the zones in the JSON file set as "state".
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("DNSCONTROL_TEST_PLUGIN") != "1" {
+		return
+	}
+	var req request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		panic(err)
+	}
+	if d, err := time.ParseDuration(req.Config["sleep"]); err == nil {
+		time.Sleep(d)
+	}
+	zones := map[string][]record{}
+	if content, err := os.ReadFile(req.Config["state"]); err == nil {
+		json.Unmarshal(content, &zones)
+	}
+	resp := response{}
+	switch req.Method {
+	case methodListZones:
+		for z := range zones {
+			resp.Zones = append(resp.Zones, z)
+		}
+	case methodCreateZone:
+		zones[req.Zone] = []record{}
+	case methodGetNameservers:
+		resp.Nameservers = []string{"ns1.example.net"}
+	case methodGetRecords:
+		recs, ok := zones[req.Zone]
+		if !ok {
+			resp.Error = "no such zone"
+		}
+		resp.Records = recs
+	case methodApply:
+		var kept []record
+		for _, r := range zones[req.Zone] {
+			deleted := false
+			for _, d := range req.Delete {
+				deleted = deleted || d == r
+			}
+			for _, m := range req.Modify {
+				if m.Old == r {
+					r = m.New
+				}
+			}
+			if !deleted {
+				kept = append(kept, r)
+			}
+		}
+		zones[req.Zone] = append(kept, req.Create...)
+	default:
+		resp.Error = "unknown method " + req.Method
+	}
+	content, _ := json.Marshal(zones)
+	os.WriteFile(req.Config["state"], content, 0o600)
+	json.NewEncoder(os.Stdout).Encode(resp)
+	os.Exit(0)
+}
+
+func makeRec(label, rtype, content string) *models.RecordConfig {
+	rc := &models.RecordConfig{TTL: 300}
+	rc.SetLabel(label, "example.com")
+	if err := rc.PopulateFromString(rtype, content, "example.com"); err != nil {
+		panic(err)
+	}
+	return rc
+}
+
+func TestPlugin(t *testing.T) {
+	t.Setenv("DNSCONTROL_TEST_PLUGIN", "1")
+	state := filepath.Join(t.TempDir(), "state.json")
+	dsp, err := newProvider(map[string]string{
+		"TYPE":    "PLUGIN",
+		"command": os.Args[0] + " -test.run=TestHelperPlugin",
+		"state":   state,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := dsp.(*pluginProvider)
+
+	if _, err := p.GetZoneRecords("example.com"); err == nil || !strings.Contains(err.Error(), "no such zone") {
+		t.Fatalf("expected the plugin's error, got %v", err)
+	}
+	if err := p.EnsureDomainExists("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if zones, err := p.ListZones(); err != nil || len(zones) != 1 || zones[0] != "example.com" {
+		t.Fatalf("ListZones() = %v, %v", zones, err)
+	}
+
+	push := func(records ...*models.RecordConfig) int {
+		t.Helper()
+		dc := &models.DomainConfig{Name: "example.com", Records: records}
+		corrections, err := p.GetDomainCorrections(dc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range corrections {
+			if err := c.F(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return len(corrections)
+	}
+	if n := push(makeRec("@", "MX", "10 mx.example.com."), makeRec("www", "A", "1.2.3.4"), makeRec("www", "TXT", "hello world")); n != 3 {
+		t.Errorf("got %d corrections, want 3", n)
+	}
+	if n := push(makeRec("@", "MX", "20 mx.example.com."), makeRec("www", "A", "1.2.3.4")); n != 2 {
+		t.Errorf("got %d corrections, want 2", n)
+	}
+	if n := push(makeRec("@", "MX", "20 mx.example.com."), makeRec("www", "A", "1.2.3.4")); n != 0 {
+		t.Errorf("got %d corrections, want none", n)
+	}
+
+	records, err := p.GetZoneRecords("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, rc := range records {
+		got = append(got, rc.GetLabel()+" "+rc.Type+" "+rc.GetTargetCombined())
+	}
+	if strings.Join(got, ",") != "@ MX 20 mx.example.com.,www A 1.2.3.4" {
+		t.Errorf("got records %v", got)
+	}
+}
+
+func TestPluginConfig(t *testing.T) {
+	t.Setenv("DNSCONTROL_TEST_PLUGIN", "1")
+	dsp, err := newProvider(map[string]string{
+		"TYPE":         "PLUGIN",
+		"command":      os.Args[0] + " -test.run=TestHelperPlugin",
+		"timeout":      "100ms",
+		"_concurrency": "4",
+		"state":        filepath.Join(t.TempDir(), "state.json"),
+		"sleep":        "10s",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := dsp.(*pluginProvider)
+	if _, ok := p.config["_concurrency"]; ok {
+		t.Errorf("the plugin gets the settings of dnscontrol: %v", p.config)
+	}
+
+	if _, err := p.ListZones(); err == nil || !strings.Contains(err.Error(), "no response after 100ms") {
+		t.Errorf("ListZones() error = %v, want a timeout", err)
+	}
+
+	if _, err := newProvider(map[string]string{"command": "x", "timeout": "soon"}, nil); err == nil {
+		t.Error("expected an error for an invalid timeout, got none")
+	}
+}
@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// The plugin contract: for each request, dnscontrol runs the command
+// with a JSON request on stdin and reads a JSON response from stdout.
+// A response with a non-empty "error" fails the request. See
+// docs/_providers/plugin.md.
+
+// Request methods.
+const (
+	methodListZones      = "list_zones"
+	methodGetRecords     = "get_records"
+	methodGetNameservers = "get_nameservers"
+	methodCreateZone     = "create_zone"
+	methodApply          = "apply"
+)
+
+// record is a DNS record as exchanged with the plugin.
+type record struct {
+	Name  string `json:"name"`  // The label, relative to the zone; "@" for the apex
+	Type  string `json:"type"`  // The record type, e.g. "MX"
+	TTL   uint32 `json:"ttl"`   // The TTL, in seconds
+	Value string `json:"value"` // The rdata, as in a zone file, e.g. "10 mx.example.com."
+}
+
+// modification replaces a record.
+type modification struct {
+	Old record `json:"old"`
+	New record `json:"new"`
+}
+
+type request struct {
+	Method string            `json:"method"`
+	Config map[string]string `json:"config"`
+	Zone   string            `json:"zone,omitempty"`
+
+	// For "apply":
+	Create []record       `json:"create,omitempty"`
+	Delete []record       `json:"delete,omitempty"`
+	Modify []modification `json:"modify,omitempty"`
+}
+
+type response struct {
+	Error       string   `json:"error,omitempty"`
+	Zones       []string `json:"zones,omitempty"`
+	Records     []record `json:"records,omitempty"`
+	Nameservers []string `json:"nameservers,omitempty"`
+}
+
+// call runs the plugin with req and returns its response.
+func (p *pluginProvider) call(req request) (*response, error) {
+	req.Config = p.config
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	cmd.Stdin = bytes.NewReader(in)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("plugin %s %s: no response after %s", p.command[0], req.Method, p.timeout)
+		}
+		return nil, fmt.Errorf("plugin %s %s: %w: %s", p.command[0], req.Method, err, strings.TrimSpace(stderr.String()))
+	}
+	var resp response
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("plugin %s %s: invalid response: %w", p.command[0], req.Method, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s %s: %s", p.command[0], req.Method, resp.Error)
+	}
+	return &resp, nil
+}
+
+func toRecord(rc *models.RecordConfig) record {
+	return record{
+		Name:  rc.GetLabel(),
+		Type:  rc.Type,
+		TTL:   rc.TTL,
+		Value: rc.GetTargetCombined(),
+	}
+}
+
+func fromRecord(r record, origin string) (*models.RecordConfig, error) {
+	rc := &models.RecordConfig{TTL: r.TTL}
+	rc.SetLabel(r.Name, origin)
+	if err := rc.PopulateFromString(r.Type, r.Value, origin); err != nil {
+		return nil, fmt.Errorf("unparsable record received from plugin: %w", err)
+	}
+	rc.Original = r
+	return rc, nil
+}
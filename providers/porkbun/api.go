@@ -6,7 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"time"
+
+	"github.com/StackExchange/dnscontrol/v3/pkg/ratelimit"
 )
 
 const (
@@ -16,6 +17,7 @@ const (
 type porkbunProvider struct {
 	apiKey    string
 	secretKey string
+	limiter   *ratelimit.Limiter
 }
 
 type requestParams map[string]string
@@ -54,7 +56,7 @@ func (c *porkbunProvider) post(endpoint string, params requestParams) ([]byte, e
 
 	// If request sending too fast, the server will fail with the following error:
 	// porkbun API error: Create error: We were unable to create the DNS record.
-	time.Sleep(500 * time.Millisecond)
+	c.limiter.Wait()
 	resp, err := client.Do(req)
 	if err != nil {
 		return []byte{}, err
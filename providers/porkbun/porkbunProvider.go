@@ -10,12 +10,13 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ratelimit"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ttlutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 )
 
-const (
-	minimumTTL = 600
-)
+// The lowest TTL is 600.
+var ttlLimits = ttlutil.Limits{Min: 600}
 
 // https://kb.porkbun.com/article/63-how-to-switch-to-porkbuns-nameservers
 var defaultNS = []string{
@@ -27,7 +28,9 @@ var defaultNS = []string{
 
 // NewPorkbun creates the provider.
 func NewPorkbun(m map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
-	c := &porkbunProvider{}
+	c := &porkbunProvider{
+		limiter: ratelimit.New(2, 1), // One request every 500ms
+	}
 
 	c.apiKey, c.secretKey = m["api_key"], m["secret_key"]
 
@@ -66,7 +69,7 @@ func init() {
 		Initializer:   NewPorkbun,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("PORKBUN", fns, features)
+	providers.RegisterDomainServiceProviderType("PORKBUN", fns, features, ttlLimits)
 }
 
 // GetNameservers returns the nameservers for a domain.
@@ -94,10 +97,7 @@ func (c *porkbunProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 	// Normalize
 	models.PostProcessRecords(existingRecords)
 
-	// Make sure TTL larger than the minimum TTL
-	for _, record := range dc.Records {
-		record.TTL = fixTTL(record.TTL)
-	}
+	ttlLimits.Apply(dc.Records)
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 {
@@ -112,7 +112,8 @@ func (c *porkbunProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		for _, m := range del {
 			id := m.Existing.Original.(*domainRecord).ID
 			corr := &models.Correction{
-				Msg: fmt.Sprintf("%s, porkbun ID: %s", m.String(), id),
+				Msg:  fmt.Sprintf("%s, porkbun ID: %s", m.String(), id),
+				Kind: m.Kind(),
 				F: func() error {
 					return c.deleteRecord(dc.Name, id)
 				},
@@ -127,7 +128,8 @@ func (c *porkbunProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 			}
 
 			corr := &models.Correction{
-				Msg: m.String(),
+				Msg:  m.String(),
+				Kind: m.Kind(),
 				F: func() error {
 					return c.createRecord(dc.Name, req)
 				},
@@ -143,7 +145,8 @@ func (c *porkbunProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 			}
 
 			corr := &models.Correction{
-				Msg: fmt.Sprintf("%s, porkbun ID: %s", m.String(), id),
+				Msg:  fmt.Sprintf("%s, porkbun ID: %s", m.String(), id),
+				Kind: m.Kind(),
 				F: func() error {
 					return c.modifyRecord(dc.Name, id, req)
 				},
@@ -167,7 +170,8 @@ func (c *porkbunProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 				return nil, err
 			}
 			corr = &models.Correction{
-				Msg: change.Msgs[0],
+				Msg:  change.Msgs[0],
+				Kind: change.Kind(),
 				F: func() error {
 					return c.createRecord(dc.Name, req)
 				},
@@ -179,7 +183,8 @@ func (c *porkbunProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 				return nil, err
 			}
 			corr = &models.Correction{
-				Msg: fmt.Sprintf("%s, porkbun ID: %s", change.Msgs[0], id),
+				Msg:  fmt.Sprintf("%s, porkbun ID: %s", change.Msgs[0], id),
+				Kind: change.Kind(),
 				F: func() error {
 					return c.modifyRecord(dc.Name, id, req)
 				},
@@ -187,7 +192,8 @@ func (c *porkbunProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		case diff2.DELETE:
 			id := change.Old[0].Original.(*domainRecord).ID
 			corr = &models.Correction{
-				Msg: fmt.Sprintf("%s, porkbun ID: %s", change.Msgs[0], id),
+				Msg:  fmt.Sprintf("%s, porkbun ID: %s", change.Msgs[0], id),
+				Kind: change.Kind(),
 				F: func() error {
 					return c.deleteRecord(dc.Name, id)
 				},
@@ -320,9 +326,7 @@ func checkNSModifications(dc *models.DomainConfig) {
 	dc.Records = newList
 }
 
-func fixTTL(ttl uint32) uint32 {
-	if ttl > minimumTTL {
-		return ttl
-	}
-	return minimumTTL
+// RateLimit returns the limiter of the requests to the Porkbun API.
+func (c *porkbunProvider) RateLimit() *ratelimit.Limiter {
+	return c.limiter
 }
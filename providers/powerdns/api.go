@@ -0,0 +1,61 @@
+package powerdns
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// apiRequest sends a request to an endpoint of the API that the
+// PowerDNS client library doesn't cover. path is relative to the
+// server, e.g. "/zones/example.com.". It returns the HTTP status and,
+// if it isn't a success, the error that the API reported.
+func (dsp *powerdnsProvider) apiRequest(method, path string, body, result interface{}) (int, error) {
+	u := fmt.Sprintf("%s/api/v1/servers/%s%s", strings.TrimSuffix(dsp.APIUrl, "/"), url.PathEscape(dsp.ServerName), path)
+
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("X-API-Key", dsp.APIKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if resp.StatusCode/100 != 2 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
+			return resp.StatusCode, errors.New(apiErr.Error)
+		}
+		return resp.StatusCode, errors.New(resp.Status)
+	}
+	if result == nil || len(respBody) == 0 {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, json.Unmarshal(respBody, result)
+}
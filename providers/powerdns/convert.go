@@ -27,6 +27,8 @@ func toRecordConfig(domain string, r zones.Record, ttl int, name string, rtype s
 		// So we need to strip away " and split into multiple string
 		// We can't use SetTargetRFC1035Quoted, it would split the long strings into multiple parts
 		return rc, rc.SetTargetTXTs(parseTxt(r.Content))
+	case "LUA":
+		return rc, rc.SetTargetLUAString(r.Content)
 	default:
 		return rc, rc.PopulateFromString(rtype, r.Content, domain)
 	}
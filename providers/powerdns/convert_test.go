@@ -51,3 +51,16 @@ func TestParseText(t *testing.T) {
 	multipleLong := parseTxt(fmt.Sprintf("\"%s\" \"%s\"", strings.Repeat("A", 300), strings.Repeat("B", 300)))
 	assert.Equal(t, []string{strings.Repeat("A", 300), strings.Repeat("B", 300)}, multipleLong)
 }
+
+func TestToRecordConfigLUA(t *testing.T) {
+	record := zones.Record{
+		Content: `A "ifportup(443, {'192.0.2.1', '192.0.2.2'})"`,
+	}
+	recordConfig, err := toRecordConfig("example.com", record, 60, "www", "LUA")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "www.example.com", recordConfig.NameFQDN)
+	assert.Equal(t, "A", recordConfig.LuaRType)
+	assert.Equal(t, "ifportup(443, {'192.0.2.1', '192.0.2.2'})", recordConfig.GetTargetField())
+	assert.Equal(t, record.Content, recordConfig.GetTargetCombined())
+}
@@ -72,6 +72,7 @@ func (dsp *powerdnsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 	if err != nil {
 		return nil, err
 	}
+	existingRecords := curRecords.GroupedByKey()
 	desiredRecords := dc.Records.GroupedByKey()
 
 	var cuCorrections []*models.Correction
@@ -86,9 +87,14 @@ func (dsp *powerdnsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 		if _, ok := desiredRecords[label]; !ok {
 			// no record found so delete it
 			dCorrections = append(dCorrections, &models.Correction{
-				Msg: msgJoined,
+				Msg:  msgJoined,
+				Kind: models.CorrectionDestroy,
 				F: func() error {
-					return dsp.client.Zones().RemoveRecordSetFromZone(context.Background(), dsp.ServerName, dc.Name, labelName, labelType)
+					return dsp.changeRRSet(dc.Name, zones.ResourceRecordSet{
+						Name:       labelName,
+						Type:       labelType,
+						ChangeType: zones.ChangeTypeDelete,
+					})
 				},
 			})
 		} else {
@@ -101,9 +107,10 @@ func (dsp *powerdnsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 				})
 			}
 			cuCorrections = append(cuCorrections, &models.Correction{
-				Msg: msgJoined,
+				Msg:  msgJoined,
+				Kind: diff.ReplaceKind(existingRecords[label], desiredRecords[label]),
 				F: func() error {
-					return dsp.client.Zones().AddRecordSetToZone(context.Background(), dsp.ServerName, dc.Name, zones.ResourceRecordSet{
+					return dsp.changeRRSet(dc.Name, zones.ResourceRecordSet{
 						Name:       labelName,
 						Type:       labelType,
 						TTL:        int(ttl),
@@ -128,6 +135,13 @@ func (dsp *powerdnsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*m
 	}
 	corrections = append(corrections, dnssecCorrections...)
 
+	// Zone metadata corrections
+	metadataCorrections, err := dsp.getMetadataCorrections(dc)
+	if err != nil {
+		return nil, err
+	}
+	corrections = append(corrections, metadataCorrections...)
+
 	return corrections, nil
 }
 
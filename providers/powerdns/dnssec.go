@@ -32,8 +32,9 @@ func (dsp *powerdnsProvider) getDNSSECCorrections(dc *models.DomainConfig) ([]*m
 	if hasEnabledKey && dc.AutoDNSSEC == "off" {
 		return []*models.Correction{
 			{
-				Msg: "Disable DNSSEC",
-				F:   func() error { _, err := dsp.removeDnssec(dc.Name, keyID); return err },
+				Msg:  "Disable DNSSEC",
+				Kind: models.CorrectionDestroy,
+				F:    func() error { _, err := dsp.removeDnssec(dc.Name, keyID); return err },
 			},
 		}, nil
 	}
@@ -42,8 +43,9 @@ func (dsp *powerdnsProvider) getDNSSECCorrections(dc *models.DomainConfig) ([]*m
 	if !hasEnabledKey && dc.AutoDNSSEC == "on" {
 		return []*models.Correction{
 			{
-				Msg: "Enable DNSSEC",
-				F:   func() error { _, err := dsp.enableDnssec(dc.Name); return err },
+				Msg:  "Enable DNSSEC",
+				Kind: models.CorrectionCreate,
+				F:    func() error { _, err := dsp.enableDnssec(dc.Name); return err },
 			},
 		}, nil
 	}
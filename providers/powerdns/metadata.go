@@ -0,0 +1,129 @@
+package powerdns
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+// zoneMetadataKinds maps the domain metadata that DNSControl manages to
+// the PowerDNS zone metadata kinds. The values are lists separated by
+// commas. A kind is left alone unless its domain metadata is set; set
+// it to "" to remove the kind from the zone.
+var zoneMetadataKinds = []struct {
+	meta string
+	kind string
+}{
+	{"powerdns_also_notify", "ALSO-NOTIFY"},
+	{"powerdns_allow_axfr_from", "ALLOW-AXFR-FROM"},
+	{"powerdns_tsig_allow_axfr", "TSIG-ALLOW-AXFR"},
+}
+
+// zoneMetadata is a zone metadata kind and its values.
+type zoneMetadata struct {
+	Kind     string   `json:"kind"`
+	Metadata []string `json:"metadata"`
+}
+
+// parseMetadataList parses a list of values separated by commas.
+func parseMetadataList(s string) []string {
+	values := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// sameValues returns true if a and b have the same values, in any order.
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string{}, a...), append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// getMetadataCorrections returns the corrections that make the zone
+// metadata match the domain metadata.
+func (dsp *powerdnsProvider) getMetadataCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	var corrections []*models.Correction
+	for _, m := range zoneMetadataKinds {
+		value, ok := dc.Metadata[m.meta]
+		if !ok {
+			continue
+		}
+		want := parseMetadataList(value)
+		have, err := dsp.getZoneMetadata(dc.Name, m.kind)
+		if err != nil {
+			return nil, err
+		}
+		if sameValues(have, want) {
+			continue
+		}
+
+		kind := m.kind
+		was := ""
+		if len(have) != 0 {
+			was = fmt.Sprintf(" (was %s)", strings.Join(have, ", "))
+		}
+		if len(want) == 0 {
+			corrections = append(corrections, &models.Correction{
+				Msg:  fmt.Sprintf("Delete zone metadata %s%s", kind, was),
+				Kind: models.CorrectionDestroy,
+				F: func() error {
+					return dsp.metadataRequest(http.MethodDelete, dc.Name, kind, nil, nil)
+				},
+			})
+			continue
+		}
+		set := models.CorrectionModify
+		if len(have) == 0 {
+			set = models.CorrectionCreate
+		}
+		corrections = append(corrections, &models.Correction{
+			Msg:  fmt.Sprintf("Set zone metadata %s to %s%s", kind, strings.Join(want, ", "), was),
+			Kind: set,
+			F: func() error {
+				return dsp.metadataRequest(http.MethodPut, dc.Name, kind, &zoneMetadata{Kind: kind, Metadata: want}, nil)
+			},
+		})
+	}
+	return corrections, nil
+}
+
+// getZoneMetadata returns the values of a zone metadata kind.
+func (dsp *powerdnsProvider) getZoneMetadata(domain, kind string) ([]string, error) {
+	var result zoneMetadata
+	if err := dsp.metadataRequest(http.MethodGet, domain, kind, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Metadata, nil
+}
+
+// metadataRequest sends a request to the zone metadata endpoint of the
+// API, which the PowerDNS client library doesn't cover.
+func (dsp *powerdnsProvider) metadataRequest(method, domain, kind string, body, result interface{}) error {
+	path := fmt.Sprintf("/zones/%s/metadata/%s", url.PathEscape(domain+"."), url.PathEscape(kind))
+	status, err := dsp.apiRequest(method, path, body, result)
+	if status == http.StatusNotFound && method == http.MethodGet {
+		// Older versions of PowerDNS answer 404 for a kind that isn't set.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("PowerDNS API: %s %s metadata of %s: %w", method, kind, domain, err)
+	}
+	return nil
+}
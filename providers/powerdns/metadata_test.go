@@ -0,0 +1,102 @@
+package powerdns
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+)
+
+func TestParseMetadataList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{}},
+		{"192.0.2.1", []string{"192.0.2.1"}},
+		{" 192.0.2.1:5300 , 2001:db8::1,,", []string{"192.0.2.1:5300", "2001:db8::1"}},
+	}
+	for _, tt := range tests {
+		if got := parseMetadataList(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseMetadataList(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGetMetadataCorrections(t *testing.T) {
+	// The zone metadata kinds on the fake server.
+	server := map[string][]string{
+		"ALSO-NOTIFY":     {"192.0.2.1"},
+		"ALLOW-AXFR-FROM": {"198.51.100.0/24", "AUTO-NS"},
+		"TSIG-ALLOW-AXFR": {"old-key"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		prefix := "/api/v1/servers/localhost/zones/example.com./metadata/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		kind := strings.TrimPrefix(r.URL.Path, prefix)
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(zoneMetadata{Kind: kind, Metadata: server[kind]})
+		case http.MethodPut:
+			var m zoneMetadata
+			json.NewDecoder(r.Body).Decode(&m)
+			server[kind] = m.Metadata
+			json.NewEncoder(w).Encode(m)
+		case http.MethodDelete:
+			delete(server, kind)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	dsp := &powerdnsProvider{APIKey: "secret", APIUrl: srv.URL + "/", ServerName: "localhost"}
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Metadata: map[string]string{
+			"powerdns_also_notify":     "192.0.2.1, 192.0.2.2:5300",
+			"powerdns_allow_axfr_from": "AUTO-NS,198.51.100.0/24", // Unchanged.
+			"powerdns_tsig_allow_axfr": "",
+		},
+	}
+	corrections, err := dsp.getMetadataCorrections(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []string
+	for _, c := range corrections {
+		msgs = append(msgs, c.Msg)
+		if err := c.F(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantMsgs := []string{
+		"Set zone metadata ALSO-NOTIFY to 192.0.2.1, 192.0.2.2:5300 (was 192.0.2.1)",
+		"Delete zone metadata TSIG-ALLOW-AXFR (was old-key)",
+	}
+	if !reflect.DeepEqual(msgs, wantMsgs) {
+		t.Errorf("got corrections %q, want %q", msgs, wantMsgs)
+	}
+	wantServer := map[string][]string{
+		"ALSO-NOTIFY":     {"192.0.2.1", "192.0.2.2:5300"},
+		"ALLOW-AXFR-FROM": {"198.51.100.0/24", "AUTO-NS"},
+	}
+	if !reflect.DeepEqual(server, wantServer) {
+		t.Errorf("got metadata %q, want %q", server, wantServer)
+	}
+
+	// The zone now matches.
+	if corrections, err := dsp.getMetadataCorrections(dc); err != nil || len(corrections) != 0 {
+		t.Errorf("getMetadataCorrections() = %d corrections, %v; want none", len(corrections), err)
+	}
+}
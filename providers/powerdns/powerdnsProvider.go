@@ -5,8 +5,10 @@ import (
 	"fmt"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/metaschema"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	pdns "github.com/mittwald/go-powerdns"
+	"github.com/mittwald/go-powerdns/apis/zones"
 )
 
 var features = providers.DocumentationNotes{
@@ -20,6 +22,7 @@ var features = providers.DocumentationNotes{
 	providers.CanUseSRV:              providers.Can(),
 	providers.CanUseSSHFP:            providers.Can(),
 	providers.CanUseTLSA:             providers.Can(),
+	providers.CanUseURI:              providers.Can(),
 	providers.DocCreateDomains:       providers.Can(),
 	providers.DocDualHost:            providers.Can(),
 	providers.DocOfficiallySupported: providers.Cannot(),
@@ -30,7 +33,11 @@ func init() {
 		Initializer:   newDSP,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("POWERDNS", fns, features)
+	providers.RegisterCustomRecordType("LUA", "POWERDNS", "")
+	providers.RegisterDomainServiceProviderType("POWERDNS", fns, features, metaschema.Schema{
+		"default_ns":       metaschema.StringList,
+		"dnssec_on_create": metaschema.Bool,
+	})
 }
 
 // powerdnsProvider represents the powerdnsProvider DNSServiceProvider.
@@ -43,6 +50,7 @@ type powerdnsProvider struct {
 	DNSSecOnCreate bool     `json:"dnssec_on_create"`
 
 	nameservers []*models.Nameserver
+	batch       providers.ZoneBatch[zones.ResourceRecordSet] // The changes of the zone updates in progress.
 }
 
 // newDSP initializes a PowerDNS DNSServiceProvider.
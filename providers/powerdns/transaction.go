@@ -0,0 +1,50 @@
+package powerdns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/mittwald/go-powerdns/apis/zones"
+)
+
+// changeRRSet replaces or deletes an RRset of the zone, or queues the
+// change if an update of the zone is in progress.
+func (dsp *powerdnsProvider) changeRRSet(domain string, set zones.ResourceRecordSet) error {
+	if dsp.batch.Add(domain, set) {
+		return providers.ErrQueued
+	}
+	if set.ChangeType == zones.ChangeTypeDelete {
+		return dsp.client.Zones().RemoveRecordSetFromZone(context.Background(), dsp.ServerName, domain, set.Name, set.Type)
+	}
+	return dsp.client.Zones().AddRecordSetToZone(context.Background(), dsp.ServerName, domain, set)
+}
+
+// BeginZoneUpdate starts queuing the RRset changes of the zone.
+func (dsp *powerdnsProvider) BeginZoneUpdate(domain string) error {
+	return dsp.batch.Begin(domain)
+}
+
+// CommitZoneUpdate sends the queued RRset changes of the zone in a
+// single PATCH request, which PowerDNS applies atomically.
+func (dsp *powerdnsProvider) CommitZoneUpdate(domain string) error {
+	sets := dsp.batch.End(domain)
+	if len(sets) == 0 {
+		return nil
+	}
+	body := struct {
+		RRSets []zones.ResourceRecordSet `json:"rrsets"`
+	}{sets}
+	if _, err := dsp.apiRequest(http.MethodPatch, "/zones/"+url.PathEscape(domain+"."), body, nil); err != nil {
+		return fmt.Errorf("PowerDNS API: PATCH %s: %w", domain, err)
+	}
+	return nil
+}
+
+// RollbackZoneUpdate discards the queued RRset changes of the zone.
+func (dsp *powerdnsProvider) RollbackZoneUpdate(domain string) error {
+	dsp.batch.End(domain)
+	return nil
+}
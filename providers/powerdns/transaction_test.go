@@ -0,0 +1,58 @@
+package powerdns
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/providers"
+	"github.com/mittwald/go-powerdns/apis/zones"
+)
+
+func TestZoneUpdateTransaction(t *testing.T) {
+	var patches [][]zones.ResourceRecordSet
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/v1/servers/localhost/zones/example.com." {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body struct {
+			RRSets []zones.ResourceRecordSet `json:"rrsets"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		patches = append(patches, body.RRSets)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	dsp := &powerdnsProvider{APIKey: "secret", APIUrl: srv.URL, ServerName: "localhost"}
+	del := zones.ResourceRecordSet{Name: "old.example.com.", Type: "A", ChangeType: zones.ChangeTypeDelete}
+	add := zones.ResourceRecordSet{Name: "www.example.com.", Type: "A", TTL: 300, ChangeType: zones.ChangeTypeReplace, Records: []zones.Record{{Content: "192.0.2.1"}}}
+	queue := func() {
+		if err := dsp.BeginZoneUpdate("example.com"); err != nil {
+			t.Fatal(err)
+		}
+		for _, set := range []zones.ResourceRecordSet{del, add} {
+			if err := dsp.changeRRSet("example.com", set); err != providers.ErrQueued {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	queue()
+	if err := dsp.RollbackZoneUpdate("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(patches) != 0 {
+		t.Fatalf("got %d requests after the rollback, want none", len(patches))
+	}
+
+	queue()
+	if err := dsp.CommitZoneUpdate("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(patches) != 1 || len(patches[0]) != 2 || patches[0][0].ChangeType != zones.ChangeTypeDelete || patches[0][1].Name != add.Name {
+		t.Errorf("got requests %+v, want one with the 2 changes in order", patches)
+	}
+}
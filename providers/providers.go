@@ -2,10 +2,13 @@ package providers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ratelimit"
 )
 
 // Registrar is an interface for a domain registrar. It can return a list of needed corrections to be applied in the future. Implement this only if the provider is a "registrar" (i.e. can update the NS records of the parent to a domain).
@@ -31,6 +34,91 @@ type ZoneLister interface {
 	ListZones() ([]string, error)
 }
 
+// DSLister should be implemented by providers that sign zones
+// (AUTODNSSEC_ON) and can report the DS records that the parent zone
+// should publish. Registrars publish them automatically; see package
+// pkg/dnssec.
+type DSLister interface {
+	GetDSRecords(domain string) (models.Records, error)
+}
+
+// ZoneMetadataGetter should be implemented by providers that have
+// zone-level settings controlled by domain metadata. GetZoneMetadata
+// returns the current settings, as the metadata that would set them.
+// "dnscontrol get-zones" exports them.
+type ZoneMetadataGetter interface {
+	GetZoneMetadata(domain string) (map[string]string, error)
+}
+
+// DomainMetaCorrector should be implemented by registrars that can
+// manage the settings of a domain (auto-renew, WHOIS privacy) from its
+// metadata. See package pkg/registrarmeta.
+type DomainMetaCorrector interface {
+	GetDomainMetaCorrections(dc *models.DomainConfig) ([]*models.Correction, error)
+}
+
+// HostCorrector should be implemented by registrars that can manage
+// the host objects of a domain, i.e. the glue records of the
+// nameservers declared with NAMESERVER("ns1.example.com.", "192.0.2.1").
+// See package pkg/glue.
+type HostCorrector interface {
+	GetHostCorrections(dc *models.DomainConfig) ([]*models.Correction, error)
+}
+
+// ZoneTagCorrector should be implemented by DNS providers that can
+// manage the tags (labels) of their hosted zones from the zone_tags
+// metadata. See package pkg/zonetags.
+type ZoneTagCorrector interface {
+	GetZoneTagCorrections(dc *models.DomainConfig) ([]*models.Correction, error)
+}
+
+// ZoneTransactioner should be implemented by DNS providers whose API
+// can change many records of a zone atomically. "dnscontrol push" calls
+// BeginZoneUpdate before running the corrections of the zone; from then
+// on, the corrections queue their record changes rather than sending
+// them. CommitZoneUpdate sends the queued changes in one atomic request
+// once all the corrections have run. If any of them failed,
+// RollbackZoneUpdate discards the queued changes instead, so that the
+// zone isn't left half-updated. Corrections that the atomic request
+// can't make (DNSSEC settings, for example) still run at once. The F
+// of a correction that queued its changes returns ErrQueued, so that
+// push can report the outcome of the commit for it. See ZoneBatch.
+type ZoneTransactioner interface {
+	BeginZoneUpdate(domain string) error
+	CommitZoneUpdate(domain string) error
+	RollbackZoneUpdate(domain string) error
+}
+
+// ErrQueued is returned by the F of a correction whose changes were
+// queued between BeginZoneUpdate and CommitZoneUpdate rather than made.
+var ErrQueued = errors.New("queued for the commit of the zone update")
+
+// RateLimiter should be implemented by providers whose API limits the
+// rate of requests. The provider waits on the Limiter before each
+// request (see pkg/ratelimit). Implementing it also declares that the
+// provider is safe to use from several goroutines: with --concurrency,
+// preview and push run as many of its domains at once as they can,
+// unless "_concurrency" in creds.json says otherwise.
+type RateLimiter interface {
+	RateLimit() *ratelimit.Limiter
+}
+
+// ZoneVersioner should be implemented by providers that can cheaply
+// tell whether a zone has changed, e.g. from its SOA serial or an ETag.
+// GetZoneVersion returns a string that changes whenever the records of
+// the zone change. "preview --cache-dir" uses it to skip the zones that
+// haven't changed since the previous preview.
+type ZoneVersioner interface {
+	GetZoneVersion(domain string) (string, error)
+}
+
+// RegistrarExpiryGetter should be implemented by registrars that can
+// report when a domain expires. "dnscontrol check-expiry" lists the
+// expiration dates and warns about the domains to renew.
+type RegistrarExpiryGetter interface {
+	GetDomainExpiry(domain string) (time.Time, error)
+}
+
 // RegistrarInitializer is a function to create a registrar. Function will be passed the unprocessed json payload from the configuration file for the given provider.
 type RegistrarInitializer func(map[string]string) (Registrar, error)
 
@@ -99,6 +187,9 @@ func CreateDNSProvider(providerTypeName string, config map[string]string, meta j
 	if !ok {
 		return nil, fmt.Errorf("no such DNS service provider: %q", providerTypeName)
 	}
+	if err := ValidateMetadata(providerTypeName, meta); err != nil {
+		return nil, err
+	}
 	return p.Initializer(config, meta)
 }
 
@@ -151,6 +242,12 @@ func (n None) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models.Correc
 	return nil, nil
 }
 
+// GetHostCorrections returns corrections to update the host objects
+// of a domain.
+func (n None) GetHostCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	return nil, nil
+}
+
 // GetNameservers returns the current nameservers for a domain.
 func (n None) GetNameservers(string) ([]*models.Nameserver, error) {
 	return nil, nil
@@ -0,0 +1,67 @@
+package route53
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53 "github.com/aws/aws-sdk-go-v2/service/route53"
+)
+
+// maxRecordSetsPerPage is the most record sets that ListResourceRecordSets
+// returns at once.
+const maxRecordSetsPerPage = 300
+
+// listRecordSetsAPI is the part of the Route 53 client that
+// recordSetsPaginator uses.
+type listRecordSetsAPI interface {
+	ListResourceRecordSets(ctx context.Context, params *r53.ListResourceRecordSetsInput, optFns ...func(*r53.Options)) (*r53.ListResourceRecordSetsOutput, error)
+}
+
+// recordSetsPaginator pages through the record sets of a zone, like the
+// paginators of aws-sdk-go-v2 do for other APIs. The SDK has none for
+// ListResourceRecordSets, as the next page starts at a name, type and
+// set identifier rather than at one token.
+type recordSetsPaginator struct {
+	client listRecordSetsAPI
+	params r53.ListResourceRecordSetsInput
+	done   bool
+}
+
+func newRecordSetsPaginator(client listRecordSetsAPI, zoneID string) *recordSetsPaginator {
+	return &recordSetsPaginator{
+		client: client,
+		params: r53.ListResourceRecordSetsInput{
+			HostedZoneId: aws.String(zoneID),
+			MaxItems:     aws.Int32(maxRecordSetsPerPage),
+		},
+	}
+}
+
+// HasMorePages returns true if there are more pages to fetch.
+func (p *recordSetsPaginator) HasMorePages() bool {
+	return !p.done
+}
+
+// NextPage fetches the next page. If it fails, calling it again
+// fetches the same page.
+func (p *recordSetsPaginator) NextPage(ctx context.Context) (*r53.ListResourceRecordSetsOutput, error) {
+	if p.done {
+		return nil, errors.New("no more pages available")
+	}
+	params := p.params
+	out, err := p.client.ListResourceRecordSets(ctx, &params)
+	if err != nil {
+		return nil, err
+	}
+	if out.NextRecordName == nil {
+		p.done = true
+	} else {
+		// A set of records with routing policies may span pages, hence
+		// the identifier.
+		p.params.StartRecordName = out.NextRecordName
+		p.params.StartRecordType = out.NextRecordType
+		p.params.StartRecordIdentifier = out.NextRecordIdentifier
+	}
+	return out, nil
+}
@@ -0,0 +1,83 @@
+package route53
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53 "github.com/aws/aws-sdk-go-v2/service/route53"
+	r53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// fakeRecordSetsAPI returns pages of one record set each, and fails
+// once when failAt is requested.
+type fakeRecordSetsAPI struct {
+	pages  []r53.ListResourceRecordSetsOutput
+	failAt int
+	starts []string // The start of each request.
+}
+
+func (f *fakeRecordSetsAPI) ListResourceRecordSets(ctx context.Context, params *r53.ListResourceRecordSetsInput, optFns ...func(*r53.Options)) (*r53.ListResourceRecordSetsOutput, error) {
+	start := fmt.Sprintf("%s/%s/%s", aws.ToString(params.StartRecordName), params.StartRecordType, aws.ToString(params.StartRecordIdentifier))
+	f.starts = append(f.starts, start)
+	i := len(f.starts) - 1
+	if f.failAt >= 0 && i == f.failAt {
+		f.failAt = -1
+		return nil, errors.New("Rate exceeded")
+	}
+	for n, page := range f.pages {
+		if n == 0 && start == "//" || n > 0 && aws.ToString(page.ResourceRecordSets[0].Name) == aws.ToString(params.StartRecordName) && aws.ToString(page.ResourceRecordSets[0].SetIdentifier) == aws.ToString(params.StartRecordIdentifier) {
+			return &f.pages[n], nil
+		}
+	}
+	return nil, fmt.Errorf("no page starts at %s", start)
+}
+
+func TestRecordSetsPaginator(t *testing.T) {
+	set := func(name, id string) r53Types.ResourceRecordSet {
+		return r53Types.ResourceRecordSet{Name: aws.String(name), Type: r53Types.RRTypeA, SetIdentifier: aws.String(id)}
+	}
+	api := &fakeRecordSetsAPI{
+		pages: []r53.ListResourceRecordSetsOutput{
+			{ResourceRecordSets: []r53Types.ResourceRecordSet{set("a.", "")}, NextRecordName: aws.String("w."), NextRecordType: r53Types.RRTypeA, NextRecordIdentifier: aws.String("one")},
+			{ResourceRecordSets: []r53Types.ResourceRecordSet{set("w.", "one")}, NextRecordName: aws.String("w."), NextRecordType: r53Types.RRTypeA, NextRecordIdentifier: aws.String("two")},
+			{ResourceRecordSets: []r53Types.ResourceRecordSet{set("w.", "two")}},
+		},
+		failAt: 1,
+	}
+
+	var got []string
+	pages := newRecordSetsPaginator(api, "Z1")
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(context.Background())
+		if err != nil {
+			// The same page is requested again.
+			continue
+		}
+		for _, s := range page.ResourceRecordSets {
+			got = append(got, aws.ToString(s.Name)+aws.ToString(s.SetIdentifier))
+		}
+	}
+
+	if want := []string{"a.", "w.one", "w.two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got record sets %v, want %v", got, want)
+	}
+	if want := []string{"//", "w./A/one", "w./A/one", "w./A/two"}; !reflect.DeepEqual(api.starts, want) {
+		t.Errorf("got requests %v, want %v", api.starts, want)
+	}
+	if _, err := pages.NextPage(context.Background()); err == nil {
+		t.Errorf("NextPage after the last page: expected error")
+	}
+}
+
+func TestIsThrottled(t *testing.T) {
+	if !isThrottled(errors.New("operation error Route 53: ListResourceRecordSets, Rate exceeded")) {
+		t.Errorf("Rate exceeded: expected throttled")
+	}
+	if isThrottled(errors.New("NoSuchHostedZone: No hosted zone found with ID: Z1")) {
+		t.Errorf("NoSuchHostedZone: expected not throttled")
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"net/http"
 	"sort"
 	"strings"
 	"time"
@@ -14,10 +14,14 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
+	"github.com/StackExchange/dnscontrol/v3/pkg/httpclient"
 	"github.com/StackExchange/dnscontrol/v3/pkg/printer"
+	"github.com/StackExchange/dnscontrol/v3/pkg/ratelimit"
 	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	r53 "github.com/aws/aws-sdk-go-v2/service/route53"
@@ -27,12 +31,19 @@ import (
 )
 
 type route53Provider struct {
-	client          *r53.Client
-	registrar       *r53d.Client
-	delegationSet   *string
-	zonesByID       map[string]r53Types.HostedZone
-	zonesByDomain   map[string]r53Types.HostedZone
-	originalRecords []r53Types.ResourceRecordSet
+	client        *r53.Client
+	registrar     *r53d.Client
+	delegationSet *string
+	limiter       *ratelimit.Limiter
+	zones         *providers.Memo[*zoneIndex]
+	recordSets    *providers.MemoMap[string, []r53Types.ResourceRecordSet] // By zone ID.
+}
+
+// zoneIndex holds the hosted zones of the account.
+type zoneIndex struct {
+	byID     map[string]r53Types.HostedZone
+	byDomain map[string]r53Types.HostedZone // Public zones only.
+	private  map[string][]r53Types.HostedZone
 }
 
 func newRoute53Reg(conf map[string]string) (providers.Registrar, error) {
@@ -50,6 +61,14 @@ func newRoute53(m map[string]string, metadata json.RawMessage) (*route53Provider
 		// http://docs.aws.amazon.com/general/latest/gr/rande.html#r53_region
 		config.WithRegion("us-east-1"),
 	}
+	if httpclient.Installed() {
+		// The SDK doesn't use http.DefaultTransport: wrap its own.
+		sdk := awshttp.NewBuildableClient()
+		optFns = append(optFns, config.WithHTTPClient(&http.Client{
+			Transport: httpclient.Wrap(sdk.GetTransport()),
+			Timeout:   sdk.GetTimeout(),
+		}))
+	}
 
 	keyID, secretKey, tokenID := m["KeyId"], m["SecretKey"], m["Token"]
 	// Token is optional and left empty unless required
@@ -67,14 +86,25 @@ func newRoute53(m map[string]string, metadata json.RawMessage) (*route53Provider
 		printer.Printf("ROUTE53 DelegationSet %s configured\n", val)
 		dls = aws.String(val)
 	}
-	api := &route53Provider{client: r53.NewFromConfig(config), registrar: r53d.NewFromConfig(config), delegationSet: dls}
-	err = api.getZones()
-	if err != nil {
+	api := &route53Provider{
+		client:        r53.NewFromConfig(config),
+		registrar:     r53d.NewFromConfig(config),
+		delegationSet: dls,
+		limiter:       ratelimit.New(5, 5), // Route 53 allows five requests per second per account.
+	}
+	api.zones = providers.NewMemo(api.listZones)
+	api.recordSets = providers.NewMemoMap(api.fetchRecordSets)
+	if _, err := api.getZones(); err != nil {
 		return nil, err
 	}
 	return api, nil
 }
 
+// RateLimit returns the limiter of the requests to the Route 53 API.
+func (r *route53Provider) RateLimit() *ratelimit.Limiter {
+	return r.limiter
+}
+
 var features = providers.DocumentationNotes{
 	providers.CanGetZones:            providers.Can(),
 	providers.CanUseAlias:            providers.Cannot("R53 does not provide a generic ALIAS functionality. Use R53_ALIAS instead."),
@@ -92,79 +122,101 @@ func init() {
 		Initializer:   newRoute53Dsp,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("ROUTE53", fns, features)
+	providers.RegisterDomainServiceProviderType("ROUTE53", fns, features, txtutil.SplitLong)
 	providers.RegisterRegistrarType("ROUTE53", newRoute53Reg)
 	providers.RegisterCustomRecordType("R53_ALIAS", "ROUTE53", "")
 }
 
-func withRetry(f func() error) {
-	const maxRetries = 23
-	// TODO: exponential backoff
-	const sleepTime = 5 * time.Second
-	var currentRetry int
-	for {
+// maxBackoff is the longest wait between two retries of a throttled
+// request.
+const maxBackoff = time.Minute
+
+// withRetry calls f until it succeeds or fails with an error other
+// than throttling. Each call waits on the limiter first. When Route 53
+// throttles anyway (other clients share the account's limit), the
+// retries back off exponentially with jitter, so that the domains
+// processed concurrently don't retry in lockstep.
+func (r *route53Provider) withRetry(f func() error) {
+	const maxRetries = 10
+	backoff := retry.NewExponentialJitterBackoff(maxBackoff)
+	for attempt := 1; ; attempt++ {
+		r.limiter.Wait()
 		err := f()
-		if err == nil {
+		if err == nil || !isThrottled(err) || attempt > maxRetries {
 			return
 		}
-		if strings.Contains(err.Error(), "Rate exceeded") {
-			currentRetry++
-			if currentRetry >= maxRetries {
-				return
-			}
-			printer.Printf("============ Route53 rate limit exceeded. Waiting %s to retry.\n", sleepTime)
-			time.Sleep(sleepTime)
-		} else {
+		delay, berr := backoff.BackoffDelay(attempt, err)
+		if berr != nil {
 			return
 		}
+		printer.Printf("============ Route53 rate limit exceeded. Waiting %s to retry.\n", delay.Round(time.Millisecond))
+		time.Sleep(delay)
+	}
+}
+
+// isThrottled reports whether err means that the request was throttled.
+// The SDK already retried it a few times.
+func isThrottled(err error) bool {
+	if retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary {
+		return true
 	}
+	return strings.Contains(err.Error(), "Rate exceeded")
 }
 
 // ListZones lists the zones on this account.
 func (r *route53Provider) ListZones() ([]string, error) {
-	if err := r.getZones(); err != nil {
+	zi, err := r.getZones()
+	if err != nil {
 		return nil, err
 	}
 	var zones []string
-	for i := range r.zonesByDomain {
+	for i := range zi.byDomain {
 		zones = append(zones, i)
 	}
+	for i := range zi.private {
+		if _, ok := zi.byDomain[i]; !ok {
+			zones = append(zones, i)
+		}
+	}
 	return zones, nil
 }
 
-func (r *route53Provider) getZones() error {
-	if r.zonesByDomain != nil {
-		return nil
-	}
+// getZones returns the hosted zones of the account. They are listed
+// once per run.
+func (r *route53Provider) getZones() (*zoneIndex, error) {
+	return r.zones.Get()
+}
 
-	var nextMarker *string
-	r.zonesByDomain = make(map[string]r53Types.HostedZone)
-	r.zonesByID = make(map[string]r53Types.HostedZone)
-	for {
+func (r *route53Provider) listZones() (*zoneIndex, error) {
+	zi := &zoneIndex{
+		byID:     map[string]r53Types.HostedZone{},
+		byDomain: map[string]r53Types.HostedZone{},
+		private:  map[string][]r53Types.HostedZone{},
+	}
+	pages := r53.NewListHostedZonesPaginator(r.client, &r53.ListHostedZonesInput{})
+	for pages.HasMorePages() {
 		var out *r53.ListHostedZonesOutput
 		var err error
-		withRetry(func() error {
-			inp := &r53.ListHostedZonesInput{Marker: nextMarker}
-			out, err = r.client.ListHostedZones(context.Background(), inp)
+		r.withRetry(func() error {
+			out, err = pages.NextPage(context.Background())
 			return err
 		})
 		if err != nil && strings.Contains(err.Error(), "is not authorized") {
-			return errors.New("check your credentials, you're not authorized to perform actions on Route 53 AWS Service")
+			return nil, errors.New("check your credentials, you're not authorized to perform actions on Route 53 AWS Service")
 		} else if err != nil {
-			return err
+			return nil, err
 		}
 		for _, z := range out.HostedZones {
 			domain := strings.TrimSuffix(aws.ToString(z.Name), ".")
-			r.zonesByDomain[domain] = z
-			r.zonesByID[parseZoneID(aws.ToString(z.Id))] = z
-		}
-		if out.NextMarker != nil {
-			nextMarker = out.NextMarker
-		} else {
-			break
+			if isPrivateZone(z) {
+				zi.private[domain] = append(zi.private[domain], z)
+			} else {
+				zi.byDomain[domain] = z
+			}
+			zi.byID[parseZoneID(aws.ToString(z.Id))] = z
 		}
 	}
-	return nil
+	return zi, nil
 }
 
 type errDomainNoExist struct {
@@ -184,17 +236,21 @@ func (e errZoneNoExist) Error() string {
 }
 
 func (r *route53Provider) GetNameservers(domain string) ([]*models.Nameserver, error) {
-	if err := r.getZones(); err != nil {
+	zi, err := r.getZones()
+	if err != nil {
 		return nil, err
 	}
 
-	zone, ok := r.zonesByDomain[domain]
+	zone, ok := zi.byDomain[domain]
 	if !ok {
+		if _, ok := zi.private[domain]; ok {
+			// Private zones aren't delegated.
+			return nil, nil
+		}
 		return nil, errDomainNoExist{domain}
 	}
 	var z *r53.GetHostedZoneOutput
-	var err error
-	withRetry(func() error {
+	r.withRetry(func() error {
 		z, err = r.client.GetHostedZone(context.Background(), &r53.GetHostedZoneInput{Id: zone.Id})
 		return err
 	})
@@ -210,53 +266,74 @@ func (r *route53Provider) GetNameservers(domain string) ([]*models.Nameserver, e
 }
 
 func (r *route53Provider) GetZoneRecords(domain string) (models.Records, error) {
-	if err := r.getZones(); err != nil {
+	zi, err := r.getZones()
+	if err != nil {
 		return nil, err
 	}
 
-	if zone, ok := r.zonesByDomain[domain]; ok {
-		return r.getZoneRecords(zone)
+	zone, ok := zi.byDomain[domain]
+	if !ok {
+		zones := zi.private[domain]
+		if len(zones) != 1 {
+			return nil, errDomainNoExist{domain}
+		}
+		zone = zones[0]
 	}
-
-	return nil, errDomainNoExist{domain}
+	records, _, err := r.getZoneRecords(zone)
+	return records, err
 }
 
 func (r *route53Provider) getZone(dc *models.DomainConfig) (r53Types.HostedZone, error) {
-	if err := r.getZones(); err != nil {
+	zi, err := r.getZones()
+	if err != nil {
 		return r53Types.HostedZone{}, err
 	}
 
 	if zoneID, ok := dc.Metadata["zone_id"]; ok {
-		zone, ok := r.zonesByID[zoneID]
+		zone, ok := zi.byID[zoneID]
 		if !ok {
 			return r53Types.HostedZone{}, errZoneNoExist{zoneID}
 		}
 		return zone, nil
 	}
 
-	if zone, ok := r.zonesByDomain[dc.Name]; ok {
-		return zone, nil
+	// The private metadata is only needed to choose the private zone
+	// when there is a public one with the same name.
+	if !wantPrivateZone(dc) {
+		if zone, ok := zi.byDomain[dc.Name]; ok {
+			return zone, nil
+		}
+	}
+	switch zones := zi.private[dc.Name]; len(zones) {
+	case 0:
+		return r53Types.HostedZone{}, errDomainNoExist{dc.Name}
+	case 1:
+		return zones[0], nil
+	default:
+		return r53Types.HostedZone{}, fmt.Errorf("there are %d private zones named %s in your route 53 account; use R53_ZONE() to choose one", len(zones), dc.Name)
 	}
-
-	return r53Types.HostedZone{}, errDomainNoExist{dc.Name}
 }
 
-func (r *route53Provider) getZoneRecords(zone r53Types.HostedZone) (models.Records, error) {
-	records, err := r.fetchRecordSets(zone.Id)
+// getZoneRecords returns the records of the zone, and the record sets
+// they were read from.
+func (r *route53Provider) getZoneRecords(zone r53Types.HostedZone) (models.Records, []r53Types.ResourceRecordSet, error) {
+	if aws.ToString(zone.Id) == "" {
+		return nil, nil, nil
+	}
+	sets, err := r.recordSets.Get(aws.ToString(zone.Id))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	r.originalRecords = records
 
 	var existingRecords = []*models.RecordConfig{}
-	for _, set := range records {
+	for _, set := range sets {
 		rts, err := nativeToRecords(set, unescape(zone.Name))
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		existingRecords = append(existingRecords, rts...)
 	}
-	return existingRecords, nil
+	return existingRecords, sets, nil
 }
 
 func (r *route53Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
@@ -267,7 +344,12 @@ func (r *route53Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		return nil, err
 	}
 
-	existingRecords, err := r.getZoneRecords(zone)
+	vpcCorrections, err := r.getVPCCorrections(dc, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	existingRecords, originalRecords, err := r.getZoneRecords(zone)
 	if err != nil {
 		return nil, err
 	}
@@ -281,20 +363,20 @@ func (r *route53Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 
 	// Normalize
 	models.PostProcessRecords(existingRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtutil.SplitLong.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 || true { // Remove "|| true" when diff2 version arrives
 
 		// diff
-		differ := diff.New(dc, getAliasMap)
+		differ := diff.New(dc, getAliasMap, getRoutingMap)
 		namesToUpdate, err := differ.ChangedGroups(existingRecords)
 		if err != nil {
 			return nil, err
 		}
 
 		if len(namesToUpdate) == 0 {
-			return nil, nil
+			return vpcCorrections, nil
 		}
 
 		updates := map[models.RecordKey][]*models.RecordConfig{}
@@ -341,99 +423,88 @@ func (r *route53Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		// we collect all changes into one of two categories now:
 		// pure deletions where we delete an entire record set,
 		// or changes where we upsert an entire record set.
+		existing := existingRecords.GroupedByKey()
 		dels := []r53Types.Change{}
 		delDesc := []string{}
 		changes := []r53Types.Change{}
 		changeDesc := []string{}
+		changeKinds := []models.CorrectionKind{}
 
 		for _, k := range updateOrder {
 			recs := updates[k]
-			// If there are no records in our desired state for a key, this
-			// indicates we should delete all records at that key.
-			if len(recs) == 0 {
-				// To delete, we submit the original resource set we got from r53.
-				var (
-					rrset r53Types.ResourceRecordSet
-					found bool
-				)
-				// Find the original resource set:
-				for _, r := range r.originalRecords {
-					if unescape(r.Name) == k.NameFQDN && (string(r.Type) == k.Type || k.Type == "R53_ALIAS_"+string(r.Type)) {
-						rrset = r
-						found = true
-						break
-					}
-				}
-				if !found {
-					// This should not happen.
-					return nil, fmt.Errorf("no record set found to delete. Name: '%s'. Type: '%s'", k.NameFQDN, k.Type)
-				}
-				// Assemble the change and add it to the list:
-				chg := r53Types.Change{
-					Action:            r53Types.ChangeActionDelete,
-					ResourceRecordSet: &rrset,
-				}
-				dels = append(dels, chg)
-				delDesc = append(delDesc, strings.Join(namesToUpdate[k], "\n"))
-			} else {
-				// If it isn't a delete, it must be either a change or create. In
-				// either case, we build a new record set from the desired state and
-				// UPSERT it.
+			desc := strings.Join(namesToUpdate[k], "\n")
 
+			// Records with a routing policy are split into one record set
+			// per set identifier.
+			groups, err := groupByRouting(recs)
+			if err != nil {
+				return nil, err
+			}
+			wanted := map[string]r53Types.ResourceRecordSet{}
+			for _, g := range groups {
 				if strings.HasPrefix(k.Type, "R53_ALIAS_") {
 					// Each R53_ALIAS_* requires an individual change.
-					if len(recs) != 1 {
-						log.Fatal("Only one R53_ALIAS_ permitted on a label")
-					}
-					for _, r := range recs {
-						rrset := aliasToRRSet(zone, r)
-						rrset.Name = aws.String(k.NameFQDN)
-						// Assemble the change and add it to the list:
-						chg := r53Types.Change{
-							Action:            r53Types.ChangeActionUpsert,
-							ResourceRecordSet: rrset,
-						}
-						changes = append(changes, chg)
-						changeDesc = append(changeDesc, strings.Join(namesToUpdate[k], "\n"))
-					}
-				} else {
-					// All other keys combine their updates into one rrset:
-					rrset := &r53Types.ResourceRecordSet{
-						Name: aws.String(k.NameFQDN),
-						Type: r53Types.RRType(k.Type),
+					if len(g.records) != 1 {
+						return nil, fmt.Errorf("only one R53_ALIAS_ permitted on a label (per %s): %s", metaSetIdentifier, k.NameFQDN)
 					}
-					for _, r := range recs {
-						val := r.GetTargetCombined()
-						rr := r53Types.ResourceRecord{
-							Value: aws.String(val),
-						}
-						rrset.ResourceRecords = append(rrset.ResourceRecords, rr)
-						i := int64(r.TTL)
-						rrset.TTL = &i // TODO: make sure that ttls are consistent within a set
-					}
-					// Assemble the change and add it to the list:
-					chg := r53Types.Change{
-						Action:            r53Types.ChangeActionUpsert,
-						ResourceRecordSet: rrset,
-					}
-					changes = append(changes, chg)
-					changeDesc = append(changeDesc, strings.Join(namesToUpdate[k], "\n"))
 				}
+				wanted[g.setIdentifier] = recordsToRRSet(zone, k, g.records)
+			}
 
+			// Delete the original record sets that are no longer wanted,
+			// and the ones whose kind of routing policy changed (Route 53
+			// can't change that with an UPSERT).
+			deleted := 0
+			for _, orig := range originalRecords {
+				if unescape(orig.Name) != k.NameFQDN || !matchesKey(orig, k.Type) {
+					continue
+				}
+				if want, ok := wanted[aws.ToString(orig.SetIdentifier)]; ok && routingPolicyKind(want) == routingPolicyKind(orig) {
+					continue
+				}
+				orig := orig
+				dels = append(dels, r53Types.Change{
+					Action:            r53Types.ChangeActionDelete,
+					ResourceRecordSet: &orig,
+				})
+				delDesc = append(delDesc, desc)
+				desc = ""
+				deleted++
+			}
+
+			// If it isn't a delete, it must be either a change or create. In
+			// either case, we build a new record set from the desired state and
+			// UPSERT it.
+			for _, g := range groups {
+				rrset := wanted[g.setIdentifier]
+				changes = append(changes, r53Types.Change{
+					Action:            r53Types.ChangeActionUpsert,
+					ResourceRecordSet: &rrset,
+				})
+				changeDesc = append(changeDesc, desc)
+				changeKinds = append(changeKinds, diff.ReplaceKind(existing[k], recs))
+				desc = ""
+			}
+
+			if len(recs) == 0 && deleted == 0 {
+				// This should not happen.
+				return nil, fmt.Errorf("no record set found to delete. Name: '%s'. Type: '%s'", k.NameFQDN, k.Type)
 			}
 		}
 
-		addCorrection := func(msg string, req *r53.ChangeResourceRecordSetsInput) {
+		addCorrection := func(msg string, kind models.CorrectionKind, req *r53.ChangeResourceRecordSetsInput) {
 			corrections = append(corrections,
 				&models.Correction{
-					Msg: msg,
+					Msg:  msg,
+					Kind: kind,
 					F: func() error {
 						var err error
 						req.HostedZoneId = zone.Id
-						withRetry(func() error {
+						r.withRetry(func() error {
 							_, err = r.client.ChangeResourceRecordSets(context.Background(), req)
 							return err
 						})
+						r.recordSets.Invalidate(aws.ToString(zone.Id))
 						return err
 					},
 				})
@@ -443,11 +514,11 @@ func (r *route53Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		for batcher.Next() {
 			start, end := batcher.Batch()
 			batch := dels[start:end]
-			descBatchStr := "\n" + strings.Join(delDesc[start:end], "\n") + "\n"
+			descBatchStr := joinDescriptions(delDesc[start:end])
 			req := &r53.ChangeResourceRecordSetsInput{
 				ChangeBatch: &r53Types.ChangeBatch{Changes: batch},
 			}
-			addCorrection(descBatchStr, req)
+			addCorrection(descBatchStr, models.CorrectionDestroy, req)
 		}
 		if err := batcher.Err(); err != nil {
 			return nil, err
@@ -457,17 +528,21 @@ func (r *route53Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		for batcher.Next() {
 			start, end := batcher.Batch()
 			batch := changes[start:end]
-			descBatchStr := "\n" + strings.Join(changeDesc[start:end], "\n") + "\n"
+			descBatchStr := joinDescriptions(changeDesc[start:end])
+			var kind models.CorrectionKind
+			for _, k := range changeKinds[start:end] {
+				kind = kind.Max(k)
+			}
 			req := &r53.ChangeResourceRecordSetsInput{
 				ChangeBatch: &r53Types.ChangeBatch{Changes: batch},
 			}
-			addCorrection(descBatchStr, req)
+			addCorrection(descBatchStr, kind, req)
 		}
 		if err := batcher.Err(); err != nil {
 			return nil, err
 		}
 
-		return corrections, nil
+		return append(corrections, vpcCorrections...), nil
 
 	}
 
@@ -489,6 +564,7 @@ func nativeToRecords(set r53Types.ResourceRecordSet, origin string) ([]*models.R
 		}
 		rc.SetLabelFromFQDN(unescape(set.Name), origin)
 		rc.SetTarget(aws.ToString(set.AliasTarget.DNSName))
+		rc.Metadata = routingToMetadata(set)
 		results = append(results, rc)
 	} else if set.TrafficPolicyInstanceId != nil {
 		// skip traffic policy records
@@ -535,6 +611,7 @@ func nativeToRecords(set r53Types.ResourceRecordSet, origin string) ([]*models.R
 				if err := rc.PopulateFromString(string(rtype), val, origin); err != nil {
 					return nil, fmt.Errorf("unparsable record received from R53: %w", err)
 				}
+				rc.Metadata = routingToMetadata(set)
 				results = append(results, rc)
 			}
 		}
@@ -563,6 +640,59 @@ func aliasToRRSet(zone r53Types.HostedZone, r *models.RecordConfig) *r53Types.Re
 	return rrset
 }
 
+// recordsToRRSet returns the record set for the records of one key and
+// set identifier.
+func recordsToRRSet(zone r53Types.HostedZone, k models.RecordKey, recs []*models.RecordConfig) r53Types.ResourceRecordSet {
+	if strings.HasPrefix(k.Type, "R53_ALIAS_") {
+		rrset := aliasToRRSet(zone, recs[0])
+		rrset.Name = aws.String(k.NameFQDN)
+		applyRouting(rrset, recs[0])
+		return *rrset
+	}
+
+	// All other keys combine their records into one rrset:
+	rrset := &r53Types.ResourceRecordSet{
+		Name: aws.String(k.NameFQDN),
+		Type: r53Types.RRType(k.Type),
+	}
+	for _, r := range recs {
+		val := r.GetTargetCombined()
+		rr := r53Types.ResourceRecord{
+			Value: aws.String(val),
+		}
+		rrset.ResourceRecords = append(rrset.ResourceRecords, rr)
+		i := int64(r.TTL)
+		rrset.TTL = &i // TODO: make sure that ttls are consistent within a set
+	}
+	applyRouting(rrset, recs[0])
+	return *rrset
+}
+
+// matchesKey reports whether an original record set belongs to the
+// records with this key type.
+func matchesKey(set r53Types.ResourceRecordSet, keyType string) bool {
+	if set.TrafficPolicyInstanceId != nil {
+		// Traffic policy records aren't managed by dnscontrol.
+		return false
+	}
+	if set.AliasTarget != nil {
+		return keyType == "R53_ALIAS_"+string(set.Type)
+	}
+	return keyType == string(set.Type)
+}
+
+// joinDescriptions joins the descriptions of a batch of changes. Only
+// the first change of each key has a description.
+func joinDescriptions(descs []string) string {
+	var lines []string
+	for _, d := range descs {
+		if d != "" {
+			lines = append(lines, d)
+		}
+	}
+	return "\n" + strings.Join(lines, "\n") + "\n"
+}
+
 func getZoneID(zone r53Types.HostedZone, r *models.RecordConfig) string {
 	zoneID := r.R53Alias["zone_id"]
 	if zoneID == "" {
@@ -595,7 +725,8 @@ func (r *route53Provider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*m
 	if actual != expected {
 		return []*models.Correction{
 			{
-				Msg: fmt.Sprintf("Update nameservers %s -> %s", actual, expected),
+				Msg:  fmt.Sprintf("Update nameservers %s -> %s", actual, expected),
+				Kind: models.CorrectionModify,
 				F: func() error {
 					_, err := r.updateRegistrarNameservers(dc.Name, expectedSet)
 					return err
@@ -610,7 +741,7 @@ func (r *route53Provider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*m
 func (r *route53Provider) getRegistrarNameservers(domainName *string) ([]string, error) {
 	var domainDetail *r53d.GetDomainDetailOutput
 	var err error
-	withRetry(func() error {
+	r.withRetry(func() error {
 		domainDetail, err = r.registrar.GetDomainDetail(context.Background(), &r53d.GetDomainDetailInput{DomainName: domainName})
 		return err
 	})
@@ -633,7 +764,7 @@ func (r *route53Provider) updateRegistrarNameservers(domainName string, nameserv
 	}
 	var domainUpdate *r53d.UpdateDomainNameserversOutput
 	var err error
-	withRetry(func() error {
+	r.withRetry(func() error {
 		domainUpdate, err = r.registrar.UpdateDomainNameservers(context.Background(), &r53d.UpdateDomainNameserversInput{
 			DomainName:  aws.String(domainName),
 			Nameservers: servers,
@@ -647,37 +778,22 @@ func (r *route53Provider) updateRegistrarNameservers(domainName string, nameserv
 	return domainUpdate.OperationId, nil
 }
 
-func (r *route53Provider) fetchRecordSets(zoneID *string) ([]r53Types.ResourceRecordSet, error) {
-	if zoneID == nil || *zoneID == "" {
-		return nil, nil
-	}
-	var next *string
-	var nextType r53Types.RRType
+// fetchRecordSets lists the record sets of a zone. Use r.recordSets
+// to read them once per run.
+func (r *route53Provider) fetchRecordSets(zoneID string) ([]r53Types.ResourceRecordSet, error) {
 	var records []r53Types.ResourceRecordSet
-	for {
-		listInput := &r53.ListResourceRecordSetsInput{
-			HostedZoneId:    zoneID,
-			StartRecordName: next,
-			StartRecordType: nextType,
-			MaxItems:        aws.Int32(100),
-		}
-		var list *r53.ListResourceRecordSetsOutput
+	pages := newRecordSetsPaginator(r.client, zoneID)
+	for pages.HasMorePages() {
+		var page *r53.ListResourceRecordSetsOutput
 		var err error
-		withRetry(func() error {
-			list, err = r.client.ListResourceRecordSets(context.Background(), listInput)
+		r.withRetry(func() error {
+			page, err = pages.NextPage(context.Background())
 			return err
 		})
 		if err != nil {
 			return nil, err
 		}
-
-		records = append(records, list.ResourceRecordSets...)
-		if list.NextRecordName != nil {
-			next = list.NextRecordName
-			nextType = list.NextRecordType
-		} else {
-			break
-		}
+		records = append(records, page.ResourceRecordSets...)
 	}
 	return records, nil
 }
@@ -693,11 +809,17 @@ func unescape(s *string) string {
 }
 
 func (r *route53Provider) EnsureDomainExists(domain string) error {
-	if err := r.getZones(); err != nil {
+	zi, err := r.getZones()
+	if err != nil {
 		return err
 	}
 
-	if _, ok := r.zonesByDomain[domain]; ok {
+	if _, ok := zi.byDomain[domain]; ok {
+		return nil
+	}
+	if _, ok := zi.private[domain]; ok {
+		// Only public zones are created. Don't add a public zone next
+		// to a private one that is managed with dnscontrol.
 		return nil
 	}
 	if r.delegationSet != nil {
@@ -711,15 +833,11 @@ func (r *route53Provider) EnsureDomainExists(domain string) error {
 		CallerReference: aws.String(fmt.Sprint(time.Now().UnixNano())),
 	}
 
-	// reset zone cache
-	r.zonesByDomain = nil
-	r.zonesByID = nil
-
-	var err error
-	withRetry(func() error {
-		_, err := r.client.CreateHostedZone(context.Background(), in)
+	r.withRetry(func() error {
+		_, err = r.client.CreateHostedZone(context.Background(), in)
 		return err
 	})
+	r.zones.Invalidate() // List the new zone too.
 	return err
 }
 
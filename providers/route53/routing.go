@@ -0,0 +1,282 @@
+package route53
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// Route 53 routing policies (weighted, latency, failover and
+// geolocation) are set with record metadata. Each record set with a
+// routing policy needs a set identifier, which distinguishes it from
+// the other record sets with the same name and type.
+const (
+	metaSetIdentifier        = "r53_set_identifier"
+	metaWeight               = "r53_weight"
+	metaRegion               = "r53_region"
+	metaFailover             = "r53_failover"
+	metaGeoContinent         = "r53_geo_continent"
+	metaGeoCountry           = "r53_geo_country"
+	metaGeoSubdivision       = "r53_geo_subdivision"
+	metaHealthCheckID        = "r53_health_check_id"
+	metaEvaluateTargetHealth = "r53_evaluate_target_health"
+)
+
+// routingMetadata is the list of metadata keys that describe a routing
+// policy.
+var routingMetadata = []string{
+	metaSetIdentifier,
+	metaWeight,
+	metaRegion,
+	metaFailover,
+	metaGeoContinent,
+	metaGeoCountry,
+	metaGeoSubdivision,
+	metaHealthCheckID,
+	metaEvaluateTargetHealth,
+}
+
+// getRoutingMap returns the normalized routing policy of a record, so
+// that the differ notices when it changes.
+func getRoutingMap(rc *models.RecordConfig) map[string]string {
+	m := map[string]string{}
+	for _, k := range routingMetadata {
+		v, ok := rc.Metadata[k]
+		if !ok || v == "" {
+			continue
+		}
+		switch k {
+		case metaWeight:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				v = strconv.FormatInt(n, 10)
+			}
+		case metaFailover, metaGeoContinent, metaGeoCountry, metaGeoSubdivision:
+			v = strings.ToUpper(v)
+		case metaRegion:
+			v = strings.ToLower(v)
+		case metaEvaluateTargetHealth:
+			if b, err := strconv.ParseBool(v); err == nil {
+				if !b {
+					continue
+				}
+				v = "true"
+			}
+		}
+		m[k] = v
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// setIdentifier returns the set identifier of a record, or "" if it
+// doesn't have a routing policy.
+func setIdentifier(rc *models.RecordConfig) string {
+	return rc.Metadata[metaSetIdentifier]
+}
+
+// checkRoutingPolicy returns an error if the routing policy metadata of
+// a record is invalid.
+func checkRoutingPolicy(rc *models.RecordConfig) error {
+	if v, ok := rc.Metadata[metaEvaluateTargetHealth]; ok {
+		if rc.Type != "R53_ALIAS" {
+			return fmt.Errorf("%s %s: %s is only valid for R53_ALIAS records", rc.Type, rc.GetLabelFQDN(), metaEvaluateTargetHealth)
+		}
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("%s %s: %s must be true or false, not %q", rc.Type, rc.GetLabelFQDN(), metaEvaluateTargetHealth, v)
+		}
+	}
+
+	m := getRoutingMap(rc)
+	if m == nil {
+		return nil
+	}
+
+	var policies []string
+	if v, ok := m[metaWeight]; ok {
+		policies = append(policies, "weighted")
+		if n, err := strconv.ParseInt(v, 10, 64); err != nil || n < 0 || n > 255 {
+			return fmt.Errorf("%s %s: %s must be a number from 0 to 255, not %q", rc.Type, rc.GetLabelFQDN(), metaWeight, v)
+		}
+	}
+	if _, ok := m[metaRegion]; ok {
+		policies = append(policies, "latency")
+	}
+	if v, ok := m[metaFailover]; ok {
+		policies = append(policies, "failover")
+		if v != string(r53Types.ResourceRecordSetFailoverPrimary) && v != string(r53Types.ResourceRecordSetFailoverSecondary) {
+			return fmt.Errorf("%s %s: %s must be PRIMARY or SECONDARY, not %q", rc.Type, rc.GetLabelFQDN(), metaFailover, v)
+		}
+	}
+	_, continent := m[metaGeoContinent]
+	_, country := m[metaGeoCountry]
+	_, subdivision := m[metaGeoSubdivision]
+	if continent || country || subdivision {
+		policies = append(policies, "geolocation")
+		if continent && (country || subdivision) {
+			return fmt.Errorf("%s %s: %s can't be combined with %s or %s", rc.Type, rc.GetLabelFQDN(), metaGeoContinent, metaGeoCountry, metaGeoSubdivision)
+		}
+		if subdivision && !country {
+			return fmt.Errorf("%s %s: %s requires %s", rc.Type, rc.GetLabelFQDN(), metaGeoSubdivision, metaGeoCountry)
+		}
+	}
+
+	if len(policies) > 1 {
+		return fmt.Errorf("%s %s: only one routing policy is allowed, found %s", rc.Type, rc.GetLabelFQDN(), strings.Join(policies, " and "))
+	}
+	if _, ok := m[metaSetIdentifier]; ok && len(policies) == 0 {
+		return fmt.Errorf("%s %s: %s requires a routing policy (%s, %s, %s or geolocation)", rc.Type, rc.GetLabelFQDN(), metaSetIdentifier, metaWeight, metaRegion, metaFailover)
+	}
+	if _, ok := m[metaSetIdentifier]; !ok && len(policies) != 0 {
+		return fmt.Errorf("%s %s: a %s routing policy requires %s", rc.Type, rc.GetLabelFQDN(), policies[0], metaSetIdentifier)
+	}
+	if _, ok := m[metaHealthCheckID]; ok && len(policies) == 0 {
+		return fmt.Errorf("%s %s: %s requires a routing policy", rc.Type, rc.GetLabelFQDN(), metaHealthCheckID)
+	}
+	return nil
+}
+
+// routingGroup is the records of one record set: the records with the
+// same name, type and set identifier.
+type routingGroup struct {
+	setIdentifier string
+	records       []*models.RecordConfig
+}
+
+// groupByRouting splits the records with the same name and type into
+// record sets, and checks that the records of each set agree on the
+// routing policy.
+func groupByRouting(recs []*models.RecordConfig) ([]*routingGroup, error) {
+	var groups []*routingGroup
+	index := map[string]*routingGroup{}
+	for _, rc := range recs {
+		id := setIdentifier(rc)
+		g, ok := index[id]
+		if !ok {
+			g = &routingGroup{setIdentifier: id}
+			index[id] = g
+			groups = append(groups, g)
+		} else if !sameRouting(g.records[0], rc) {
+			return nil, fmt.Errorf("%s %s: the records with %s=%q have different routing policies", rc.Type, rc.GetLabelFQDN(), metaSetIdentifier, id)
+		}
+		g.records = append(g.records, rc)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].setIdentifier < groups[j].setIdentifier
+	})
+	return groups, nil
+}
+
+func sameRouting(a, b *models.RecordConfig) bool {
+	ma, mb := getRoutingMap(a), getRoutingMap(b)
+	if len(ma) != len(mb) {
+		return false
+	}
+	for k, v := range ma {
+		if mb[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// applyRouting sets the routing policy fields of rrset from the
+// metadata of rc.
+func applyRouting(rrset *r53Types.ResourceRecordSet, rc *models.RecordConfig) {
+	m := getRoutingMap(rc)
+	if v, ok := m[metaSetIdentifier]; ok {
+		rrset.SetIdentifier = aws.String(v)
+	}
+	if v, ok := m[metaWeight]; ok {
+		n, _ := strconv.ParseInt(v, 10, 64)
+		rrset.Weight = aws.Int64(n)
+	}
+	if v, ok := m[metaRegion]; ok {
+		rrset.Region = r53Types.ResourceRecordSetRegion(v)
+	}
+	if v, ok := m[metaFailover]; ok {
+		rrset.Failover = r53Types.ResourceRecordSetFailover(v)
+	}
+	_, continent := m[metaGeoContinent]
+	_, country := m[metaGeoCountry]
+	if continent || country {
+		rrset.GeoLocation = &r53Types.GeoLocation{}
+		if v, ok := m[metaGeoContinent]; ok {
+			rrset.GeoLocation.ContinentCode = aws.String(v)
+		}
+		if v, ok := m[metaGeoCountry]; ok {
+			rrset.GeoLocation.CountryCode = aws.String(v)
+		}
+		if v, ok := m[metaGeoSubdivision]; ok {
+			rrset.GeoLocation.SubdivisionCode = aws.String(v)
+		}
+	}
+	if v, ok := m[metaHealthCheckID]; ok {
+		rrset.HealthCheckId = aws.String(v)
+	}
+	if rrset.AliasTarget != nil {
+		rrset.AliasTarget.EvaluateTargetHealth = m[metaEvaluateTargetHealth] == "true"
+	}
+}
+
+// routingToMetadata returns the metadata that describes the routing
+// policy of a record set, or nil if it has none.
+func routingToMetadata(set r53Types.ResourceRecordSet) map[string]string {
+	m := map[string]string{}
+	if set.SetIdentifier != nil {
+		m[metaSetIdentifier] = aws.ToString(set.SetIdentifier)
+	}
+	if set.Weight != nil {
+		m[metaWeight] = strconv.FormatInt(aws.ToInt64(set.Weight), 10)
+	}
+	if set.Region != "" {
+		m[metaRegion] = string(set.Region)
+	}
+	if set.Failover != "" {
+		m[metaFailover] = string(set.Failover)
+	}
+	if geo := set.GeoLocation; geo != nil {
+		if geo.ContinentCode != nil {
+			m[metaGeoContinent] = aws.ToString(geo.ContinentCode)
+		}
+		if geo.CountryCode != nil {
+			m[metaGeoCountry] = aws.ToString(geo.CountryCode)
+		}
+		if geo.SubdivisionCode != nil {
+			m[metaGeoSubdivision] = aws.ToString(geo.SubdivisionCode)
+		}
+	}
+	if set.HealthCheckId != nil {
+		m[metaHealthCheckID] = aws.ToString(set.HealthCheckId)
+	}
+	if set.AliasTarget != nil && set.AliasTarget.EvaluateTargetHealth {
+		m[metaEvaluateTargetHealth] = "true"
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// routingPolicyKind returns the kind of routing policy of a record set,
+// or "" for simple routing. Route 53 doesn't allow the kind to change
+// in an UPSERT, so such record sets are deleted and recreated.
+func routingPolicyKind(set r53Types.ResourceRecordSet) string {
+	switch {
+	case set.Weight != nil:
+		return "weighted"
+	case set.Region != "":
+		return "latency"
+	case set.Failover != "":
+		return "failover"
+	case set.GeoLocation != nil:
+		return "geolocation"
+	}
+	return ""
+}
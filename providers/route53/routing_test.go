@@ -0,0 +1,98 @@
+package route53
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+func Test_checkRoutingPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		rtype   string
+		meta    map[string]string
+		wantErr bool
+	}{
+		{"simple", "A", nil, false},
+		{"weighted", "A", map[string]string{metaSetIdentifier: "a", metaWeight: "10"}, false},
+		{"latency", "A", map[string]string{metaSetIdentifier: "a", metaRegion: "us-east-1"}, false},
+		{"failover", "A", map[string]string{metaSetIdentifier: "a", metaFailover: "primary", metaHealthCheckID: "abc"}, false},
+		{"geo country", "A", map[string]string{metaSetIdentifier: "a", metaGeoCountry: "US", metaGeoSubdivision: "CA"}, false},
+		{"geo default", "A", map[string]string{metaSetIdentifier: "a", metaGeoCountry: "*"}, false},
+		{"alias health", "R53_ALIAS", map[string]string{metaSetIdentifier: "a", metaWeight: "1", metaEvaluateTargetHealth: "true"}, false},
+		{"no set identifier", "A", map[string]string{metaWeight: "10"}, true},
+		{"no policy", "A", map[string]string{metaSetIdentifier: "a"}, true},
+		{"two policies", "A", map[string]string{metaSetIdentifier: "a", metaWeight: "10", metaRegion: "us-east-1"}, true},
+		{"bad weight", "A", map[string]string{metaSetIdentifier: "a", metaWeight: "256"}, true},
+		{"bad failover", "A", map[string]string{metaSetIdentifier: "a", metaFailover: "TERTIARY"}, true},
+		{"continent and country", "A", map[string]string{metaSetIdentifier: "a", metaGeoContinent: "EU", metaGeoCountry: "FR"}, true},
+		{"subdivision only", "A", map[string]string{metaSetIdentifier: "a", metaGeoSubdivision: "CA"}, true},
+		{"health check without policy", "A", map[string]string{metaHealthCheckID: "abc"}, true},
+		{"evaluate on A", "A", map[string]string{metaEvaluateTargetHealth: "true"}, true},
+		{"bad evaluate", "R53_ALIAS", map[string]string{metaEvaluateTargetHealth: "maybe"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &models.RecordConfig{Type: tt.rtype, Metadata: tt.meta}
+			rc.SetLabel("www", "example.com")
+			if err := checkRoutingPolicy(rc); (err != nil) != tt.wantErr {
+				t.Errorf("checkRoutingPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_routingRoundTrip(t *testing.T) {
+	tests := []map[string]string{
+		{metaSetIdentifier: "a", metaWeight: "10"},
+		{metaSetIdentifier: "b", metaRegion: "eu-west-1", metaHealthCheckID: "abc"},
+		{metaSetIdentifier: "c", metaFailover: "SECONDARY"},
+		{metaSetIdentifier: "d", metaGeoCountry: "US", metaGeoSubdivision: "CA"},
+		{metaSetIdentifier: "e", metaGeoContinent: "EU", metaEvaluateTargetHealth: "true"},
+	}
+	for _, meta := range tests {
+		rc := &models.RecordConfig{Type: "R53_ALIAS", Metadata: meta}
+		rrset := &r53Types.ResourceRecordSet{AliasTarget: &r53Types.AliasTarget{}}
+		applyRouting(rrset, rc)
+		if got := routingToMetadata(*rrset); !reflect.DeepEqual(got, meta) {
+			t.Errorf("routingToMetadata(applyRouting(%v)) = %v", meta, got)
+		}
+	}
+}
+
+func Test_groupByRouting(t *testing.T) {
+	rec := func(target string, meta map[string]string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: "A", Metadata: meta}
+		rc.SetLabel("www", "example.com")
+		rc.SetTarget(target)
+		return rc
+	}
+	us := map[string]string{metaSetIdentifier: "us", metaWeight: "10"}
+	eu := map[string]string{metaSetIdentifier: "eu", metaWeight: "20"}
+
+	groups, err := groupByRouting([]*models.RecordConfig{
+		rec("1.1.1.1", us), rec("2.2.2.2", eu), rec("3.3.3.3", us),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 || groups[0].setIdentifier != "eu" || len(groups[0].records) != 1 || groups[1].setIdentifier != "us" || len(groups[1].records) != 2 {
+		t.Errorf("unexpected groups: %+v", groups)
+	}
+
+	k := models.RecordKey{NameFQDN: "www.example.com", Type: "A"}
+	rrset := recordsToRRSet(r53Types.HostedZone{}, k, groups[1].records)
+	if aws.ToString(rrset.SetIdentifier) != "us" || aws.ToInt64(rrset.Weight) != 10 || len(rrset.ResourceRecords) != 2 {
+		t.Errorf("unexpected record set: %+v", rrset)
+	}
+
+	_, err = groupByRouting([]*models.RecordConfig{
+		rec("1.1.1.1", us), rec("3.3.3.3", map[string]string{metaSetIdentifier: "us", metaWeight: "5"}),
+	})
+	if err == nil {
+		t.Errorf("expected an error for records with different weights in one set")
+	}
+}
@@ -0,0 +1,81 @@
+package route53
+
+import (
+	"context"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/pkg/zonetags"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53 "github.com/aws/aws-sdk-go-v2/service/route53"
+	r53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// maxTagChanges is the number of tags that one ChangeTagsForResource
+// request may add, and may remove.
+const maxTagChanges = 10
+
+// GetZoneTagCorrections returns the corrections of the tags of the
+// hosted zone (see package pkg/zonetags). The tags whose key starts
+// with "aws:" belong to AWS and are left alone.
+func (r *route53Provider) GetZoneTagCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	if _, ok := dc.Metadata[zonetags.Key]; !ok {
+		return nil, nil
+	}
+	zone, err := r.getZone(dc)
+	if err != nil {
+		return nil, err
+	}
+	id := parseZoneID(aws.ToString(zone.Id))
+
+	var out *r53.ListTagsForResourceOutput
+	r.withRetry(func() error {
+		out, err = r.client.ListTagsForResource(context.Background(), &r53.ListTagsForResourceInput{
+			ResourceId:   aws.String(id),
+			ResourceType: r53Types.TagResourceTypeHostedzone,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]string{}
+	if out.ResourceTagSet != nil {
+		for _, tag := range out.ResourceTagSet.Tags {
+			if key := aws.ToString(tag.Key); !strings.HasPrefix(key, "aws:") {
+				current[key] = aws.ToString(tag.Value)
+			}
+		}
+	}
+	return zonetags.Corrections(dc, current, func(add map[string]string, remove []string) error {
+		var tags []r53Types.Tag
+		for k, v := range add {
+			tags = append(tags, r53Types.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		for len(tags) > 0 || len(remove) > 0 {
+			in := &r53.ChangeTagsForResourceInput{
+				ResourceId:   aws.String(id),
+				ResourceType: r53Types.TagResourceTypeHostedzone,
+			}
+			n := len(tags)
+			if n > maxTagChanges {
+				n = maxTagChanges
+			}
+			in.AddTags, tags = tags[:n], tags[n:]
+			n = len(remove)
+			if n > maxTagChanges {
+				n = maxTagChanges
+			}
+			in.RemoveTagKeys, remove = remove[:n], remove[n:]
+			var err error
+			r.withRetry(func() error {
+				_, err = r.client.ChangeTagsForResource(context.Background(), in)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,154 @@
+package route53
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53 "github.com/aws/aws-sdk-go-v2/service/route53"
+	r53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// Domain metadata for private hosted zones.
+const (
+	// metaPrivate selects the private zone with the domain's name
+	// when there is also a public one.
+	metaPrivate = "private"
+	// metaVPCs is the list of VPCs that a private zone is associated
+	// with, as "region:vpc-id" separated by commas. If it is set, VPCs
+	// are associated and disassociated to match it.
+	metaVPCs = "vpcs"
+)
+
+func isPrivateZone(zone r53Types.HostedZone) bool {
+	return zone.Config != nil && zone.Config.PrivateZone
+}
+
+// wantPrivateZone reports whether the domain should use a private zone.
+func wantPrivateZone(dc *models.DomainConfig) bool {
+	b, _ := strconv.ParseBool(dc.Metadata[metaPrivate])
+	return b
+}
+
+// parseVPCs parses the value of the vpcs metadata.
+func parseVPCs(s string) ([]r53Types.VPC, error) {
+	var vpcs []r53Types.VPC
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid VPC %q: want region:vpc-id", item)
+		}
+		vpcs = append(vpcs, r53Types.VPC{
+			VPCRegion: r53Types.VPCRegion(parts[0]),
+			VPCId:     aws.String(parts[1]),
+		})
+	}
+	return vpcs, nil
+}
+
+func vpcString(vpc r53Types.VPC) string {
+	return string(vpc.VPCRegion) + ":" + aws.ToString(vpc.VPCId)
+}
+
+// diffVPCs returns the VPCs to associate with and to disassociate from
+// a zone.
+func diffVPCs(existing, desired []r53Types.VPC) (associate, disassociate []r53Types.VPC) {
+	have := map[string]bool{}
+	for _, vpc := range existing {
+		have[vpcString(vpc)] = true
+	}
+	want := map[string]bool{}
+	for _, vpc := range desired {
+		want[vpcString(vpc)] = true
+		if !have[vpcString(vpc)] {
+			associate = append(associate, vpc)
+		}
+	}
+	for _, vpc := range existing {
+		if !want[vpcString(vpc)] {
+			disassociate = append(disassociate, vpc)
+		}
+	}
+	sort.Slice(disassociate, func(i, j int) bool {
+		return vpcString(disassociate[i]) < vpcString(disassociate[j])
+	})
+	return associate, disassociate
+}
+
+// getVPCCorrections returns the corrections that make the VPC
+// associations of a private zone match the vpcs metadata. New VPCs are
+// associated before old ones are disassociated, since a private zone
+// must always be associated with at least one VPC.
+func (r *route53Provider) getVPCCorrections(dc *models.DomainConfig, zone r53Types.HostedZone) ([]*models.Correction, error) {
+	value, ok := dc.Metadata[metaVPCs]
+	if !ok {
+		return nil, nil
+	}
+	if !isPrivateZone(zone) {
+		return nil, fmt.Errorf("%s: the %s metadata is only valid for private zones", dc.Name, metaVPCs)
+	}
+	desired, err := parseVPCs(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dc.Name, err)
+	}
+	if len(desired) == 0 {
+		return nil, fmt.Errorf("%s: a private zone must be associated with at least one VPC", dc.Name)
+	}
+
+	var z *r53.GetHostedZoneOutput
+	r.withRetry(func() error {
+		z, err = r.client.GetHostedZone(context.Background(), &r53.GetHostedZoneInput{Id: zone.Id})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	associate, disassociate := diffVPCs(z.VPCs, desired)
+	var corrections []*models.Correction
+	for _, vpc := range associate {
+		vpc := vpc
+		corrections = append(corrections, &models.Correction{
+			Msg:  fmt.Sprintf("Associate VPC %s with private zone %s", vpcString(vpc), dc.Name),
+			Kind: models.CorrectionCreate,
+			F: func() error {
+				var err error
+				r.withRetry(func() error {
+					_, err = r.client.AssociateVPCWithHostedZone(context.Background(), &r53.AssociateVPCWithHostedZoneInput{
+						HostedZoneId: zone.Id,
+						VPC:          &vpc,
+					})
+					return err
+				})
+				return err
+			},
+		})
+	}
+	for _, vpc := range disassociate {
+		vpc := vpc
+		corrections = append(corrections, &models.Correction{
+			Msg:  fmt.Sprintf("Disassociate VPC %s from private zone %s", vpcString(vpc), dc.Name),
+			Kind: models.CorrectionDestroy,
+			F: func() error {
+				var err error
+				r.withRetry(func() error {
+					_, err = r.client.DisassociateVPCFromHostedZone(context.Background(), &r53.DisassociateVPCFromHostedZoneInput{
+						HostedZoneId: zone.Id,
+						VPC:          &vpc,
+					})
+					return err
+				})
+				return err
+			},
+		})
+	}
+	return corrections, nil
+}
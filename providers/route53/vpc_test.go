@@ -0,0 +1,47 @@
+package route53
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+func vpc(region, id string) r53Types.VPC {
+	return r53Types.VPC{VPCRegion: r53Types.VPCRegion(region), VPCId: aws.String(id)}
+}
+
+func Test_parseVPCs(t *testing.T) {
+	got, err := parseVPCs("us-east-1:vpc-1, eu-west-1:vpc-2,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []r53Types.VPC{vpc("us-east-1", "vpc-1"), vpc("eu-west-1", "vpc-2")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseVPCs() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"vpc-1", "us-east-1:", ":vpc-1", "a:b:c"} {
+		if _, err := parseVPCs(bad); err == nil {
+			t.Errorf("parseVPCs(%q): expected an error", bad)
+		}
+	}
+}
+
+func Test_diffVPCs(t *testing.T) {
+	existing := []r53Types.VPC{vpc("us-east-1", "vpc-1"), vpc("us-east-1", "vpc-2")}
+	desired := []r53Types.VPC{vpc("us-east-1", "vpc-2"), vpc("eu-west-1", "vpc-3")}
+	associate, disassociate := diffVPCs(existing, desired)
+	if want := []r53Types.VPC{vpc("eu-west-1", "vpc-3")}; !reflect.DeepEqual(associate, want) {
+		t.Errorf("associate = %v, want %v", associate, want)
+	}
+	if want := []r53Types.VPC{vpc("us-east-1", "vpc-1")}; !reflect.DeepEqual(disassociate, want) {
+		t.Errorf("disassociate = %v, want %v", disassociate, want)
+	}
+
+	associate, disassociate = diffVPCs(existing, existing)
+	if len(associate) != 0 || len(disassociate) != 0 {
+		t.Errorf("expected no changes, got %v and %v", associate, disassociate)
+	}
+}
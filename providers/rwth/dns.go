@@ -6,7 +6,6 @@ import (
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff2"
-	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 )
 
 // RWTHDefaultNs is the default DNS NS for this provider.
@@ -50,7 +49,7 @@ func (api *rwthProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*model
 	}
 	// Normalize
 	models.PostProcessRecords(existingRecords)
-	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
+	txtPolicy.Apply(dc.Records) // Autosplit long TXT records
 
 	var corrections []*models.Correction
 	if !diff2.EnableDiff2 || true { // Remove "|| true" when diff2 version arrives
@@ -64,23 +63,26 @@ func (api *rwthProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*model
 		for _, d := range create {
 			des := d.Desired
 			corrections = append(corrections, &models.Correction{
-				Msg: d.String(),
-				F:   func() error { return api.createRecord(dc.Name, des) },
+				Msg:  d.String(),
+				Kind: d.Kind(),
+				F:    func() error { return api.createRecord(dc.Name, des) },
 			})
 		}
 		for _, d := range del {
 			existingRecord := d.Existing.Original.(RecordReply)
 			corrections = append(corrections, &models.Correction{
-				Msg: d.String(),
-				F:   func() error { return api.destroyRecord(existingRecord) },
+				Msg:  d.String(),
+				Kind: d.Kind(),
+				F:    func() error { return api.destroyRecord(existingRecord) },
 			})
 		}
 		for _, d := range modify {
 			rec := d.Desired
 			existingID := d.Existing.Original.(RecordReply).ID
 			corrections = append(corrections, &models.Correction{
-				Msg: d.String(),
-				F:   func() error { return api.updateRecord(existingID, *rec) },
+				Msg:  d.String(),
+				Kind: d.Kind(),
+				F:    func() error { return api.updateRecord(existingID, *rec) },
 			})
 		}
 
@@ -88,7 +90,10 @@ func (api *rwthProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*model
 		if len(corrections) > 0 {
 			corrections = append(corrections, &models.Correction{
 				Msg: fmt.Sprintf("Deploy zone %s", domain),
-				F:   func() error { return api.deployZone(domain) },
+				// The deployment publishes every change waiting at
+				// RWTH, not only the ones above.
+				Kind: models.CorrectionDestroy,
+				F:    func() error { return api.deployZone(domain) },
 			})
 		}
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
 )
 
@@ -29,13 +30,17 @@ var features = providers.DocumentationNotes{
 	providers.DocOfficiallySupported: providers.Cannot(),
 }
 
+// txtPolicy: long TXT strings are split, but AuditRecords rejects the
+// TXT records that have several strings in dnsconfig.js.
+var txtPolicy = txtutil.Policy{MaxLength: 255, AutoSplit: true}
+
 // init registers the registrar and the domain service provider with dnscontrol.
 func init() {
 	fns := providers.DspFuncs{
 		Initializer:   New,
 		RecordAuditor: AuditRecords,
 	}
-	providers.RegisterDomainServiceProviderType("RWTH", fns, features)
+	providers.RegisterDomainServiceProviderType("RWTH", fns, features, txtPolicy)
 }
 
 // New allocates a DNS service provider.
@@ -95,23 +95,26 @@ func (s *softlayerProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 	for _, del := range deletes {
 		existing := del.Existing.Original.(datatypes.Dns_Domain_ResourceRecord)
 		corrections = append(corrections, &models.Correction{
-			Msg: del.String(),
-			F:   s.deleteRecordFunc(*existing.Id),
+			Msg:  del.String(),
+			Kind: del.Kind(),
+			F:    s.deleteRecordFunc(*existing.Id),
 		})
 	}
 
 	for _, cre := range create {
 		corrections = append(corrections, &models.Correction{
-			Msg: cre.String(),
-			F:   s.createRecordFunc(cre.Desired, domain),
+			Msg:  cre.String(),
+			Kind: cre.Kind(),
+			F:    s.createRecordFunc(cre.Desired, domain),
 		})
 	}
 
 	for _, mod := range modify {
 		existing := mod.Existing.Original.(datatypes.Dns_Domain_ResourceRecord)
 		corrections = append(corrections, &models.Correction{
-			Msg: mod.String(),
-			F:   s.updateRecordFunc(&existing, mod.Desired),
+			Msg:  mod.String(),
+			Kind: mod.Kind(),
+			F:    s.updateRecordFunc(&existing, mod.Desired),
 		})
 	}
 
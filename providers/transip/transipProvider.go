@@ -110,8 +110,9 @@ func (n *transipProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 			}
 
 			corrections = append(corrections, &models.Correction{
-				Msg: del.String(),
-				F:   func() error { return n.domains.RemoveDNSEntry(dc.Name, entry) },
+				Msg:  del.String(),
+				Kind: del.Kind(),
+				F:    func() error { return n.domains.RemoveDNSEntry(dc.Name, entry) },
 			})
 		}
 
@@ -122,8 +123,9 @@ func (n *transipProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 			}
 
 			corrections = append(corrections, &models.Correction{
-				Msg: cre.String(),
-				F:   func() error { return n.domains.AddDNSEntry(dc.Name, entry) },
+				Msg:  cre.String(),
+				Kind: cre.Kind(),
+				F:    func() error { return n.domains.AddDNSEntry(dc.Name, entry) },
 			})
 		}
 
@@ -142,12 +144,14 @@ func (n *transipProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 
 			corrections = append(corrections,
 				&models.Correction{
-					Msg: mod.String() + "[1/2]",
-					F:   func() error { return n.domains.RemoveDNSEntry(dc.Name, oldEntry) },
+					Msg:  mod.String() + "[1/2]",
+					Kind: mod.Kind(),
+					F:    func() error { return n.domains.RemoveDNSEntry(dc.Name, oldEntry) },
 				},
 				&models.Correction{
-					Msg: mod.String() + "[2/2]",
-					F:   func() error { return n.domains.AddDNSEntry(dc.Name, targetEntry) },
+					Msg:  mod.String() + "[2/2]",
+					Kind: mod.Kind(),
+					F:    func() error { return n.domains.AddDNSEntry(dc.Name, targetEntry) },
 				},
 			)
 
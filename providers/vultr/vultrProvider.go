@@ -130,7 +130,8 @@ func (api *vultrProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		for _, mod := range delete {
 			id := mod.Existing.Original.(govultr.DomainRecord).ID
 			corrections = append(corrections, &models.Correction{
-				Msg: fmt.Sprintf("%s; Vultr RecordID: %v", mod.String(), id),
+				Msg:  fmt.Sprintf("%s; Vultr RecordID: %v", mod.String(), id),
+				Kind: mod.Kind(),
 				F: func() error {
 					return api.client.DomainRecord.Delete(context.Background(), dc.Name, id)
 				},
@@ -140,7 +141,8 @@ func (api *vultrProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		for _, mod := range create {
 			r := toVultrRecord(dc, mod.Desired, "0")
 			corrections = append(corrections, &models.Correction{
-				Msg: mod.String(),
+				Msg:  mod.String(),
+				Kind: mod.Kind(),
 				F: func() error {
 					_, err := api.client.DomainRecord.Create(context.Background(), dc.Name, &govultr.DomainRecordReq{Name: r.Name, Type: r.Type, Data: r.Data, TTL: r.TTL, Priority: &r.Priority})
 					return err
@@ -151,7 +153,8 @@ func (api *vultrProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		for _, mod := range modify {
 			r := toVultrRecord(dc, mod.Desired, mod.Existing.Original.(govultr.DomainRecord).ID)
 			corrections = append(corrections, &models.Correction{
-				Msg: fmt.Sprintf("%s; Vultr RecordID: %v", mod.String(), r.ID),
+				Msg:  fmt.Sprintf("%s; Vultr RecordID: %v", mod.String(), r.ID),
+				Kind: mod.Kind(),
 				F: func() error {
 					return api.client.DomainRecord.Update(context.Background(), dc.Name, r.ID, &govultr.DomainRecordReq{Name: r.Name, Type: r.Type, Data: r.Data, TTL: r.TTL, Priority: &r.Priority})
 				},
@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ZoneBatch holds the changes that a provider implementing
+// ZoneTransactioner queues between BeginZoneUpdate() and
+// CommitZoneUpdate(), by zone. The zero value is ready to use. A
+// ZoneBatch is safe for concurrent use.
+//
+// Typical use:
+//
+//	type myProvider struct {
+//		batch providers.ZoneBatch[change]
+//	}
+//
+//	func (api *myProvider) BeginZoneUpdate(domain string) error {
+//		return api.batch.Begin(domain)
+//	}
+//
+//	// In the F of a correction:
+//	if api.batch.Add(domain, c) {
+//		return providers.ErrQueued // CommitZoneUpdate sends it.
+//	}
+//	return api.send(domain, []change{c})
+//
+//	func (api *myProvider) CommitZoneUpdate(domain string) error {
+//		return api.send(domain, api.batch.End(domain))
+//	}
+//
+//	func (api *myProvider) RollbackZoneUpdate(domain string) error {
+//		api.batch.End(domain)
+//		return nil
+//	}
+type ZoneBatch[T any] struct {
+	mu    sync.Mutex
+	zones map[string][]T
+}
+
+// Begin starts queuing the changes of domain.
+func (b *ZoneBatch[T]) Begin(domain string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.zones[domain]; ok {
+		return fmt.Errorf("an update of %s is already in progress", domain)
+	}
+	if b.zones == nil {
+		b.zones = map[string][]T{}
+	}
+	b.zones[domain] = []T{}
+	return nil
+}
+
+// Add queues change if an update of domain is in progress, and reports
+// whether it did. If not, the caller should make the change at once.
+func (b *ZoneBatch[T]) Add(domain string, change T) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	changes, ok := b.zones[domain]
+	if ok {
+		b.zones[domain] = append(changes, change)
+	}
+	return ok
+}
+
+// End ends the update of domain and returns the changes queued since
+// Begin, in the order they were added.
+func (b *ZoneBatch[T]) End(domain string) []T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	changes := b.zones[domain]
+	delete(b.zones, domain)
+	return changes
+}
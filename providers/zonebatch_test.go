@@ -0,0 +1,37 @@
+package providers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestZoneBatch(t *testing.T) {
+	var b ZoneBatch[string]
+	if b.Add("example.com", "a") {
+		t.Error("Add() queued a change without an update in progress")
+	}
+
+	if err := b.Begin("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Begin("example.com"); err == nil {
+		t.Error("expected an error for a second Begin(), got none")
+	}
+	if !b.Add("example.com", "a") || !b.Add("example.com", "b") {
+		t.Error("Add() didn't queue the changes of an update in progress")
+	}
+	if b.Add("example.net", "c") {
+		t.Error("Add() queued a change of another zone")
+	}
+	if got := b.End("example.com"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("End() = %q, want [a b]", got)
+	}
+
+	// The update is over.
+	if b.Add("example.com", "d") {
+		t.Error("Add() queued a change after End()")
+	}
+	if got := b.End("example.com"); len(got) != 0 {
+		t.Errorf("End() = %q, want nothing", got)
+	}
+}